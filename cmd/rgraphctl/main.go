@@ -0,0 +1,265 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// rgraphctl plans and applies resource graphs read from a file.
+//
+//	$ rgraphctl -project my-project -f graph.yaml plan
+//	$ rgraphctl -project my-project -f graph.yaml diff
+//	$ rgraphctl -project my-project -f graph.yaml -explain plan
+//	$ rgraphctl -project my-project -f graph.yaml apply
+//
+// The graph file is a YAML (or JSON) document in the format of the
+// rgraph/loader package:
+//
+//	project: my-project
+//	nodes:
+//	- kind: HealthCheck
+//	  name: hc
+//	  ownership: external
+//	- kind: BackendService
+//	  name: bs
+//	  spec:
+//	    loadBalancingScheme: INTERNAL_SELF_MANAGED
+//	    protocol: HTTP
+//	    compressionMode: DISABLED
+//	    sessionAffinity: NONE
+//	    timeoutSec: 30
+//	    healthChecks:
+//	    - https://www.googleapis.com/compute/v1/projects/my-project/global/healthChecks/hc
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/exec"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/loader"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/workflow/plan"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/google"
+	"google.golang.org/api/compute/v1"
+	"k8s.io/klog/v2"
+)
+
+var (
+	flags = struct {
+		project  string
+		file     string
+		executor string
+		dryRun   bool
+		explain  bool
+		mock     bool
+		force    bool
+		timeout  time.Duration
+
+		maxDestroy        int
+		maxDestroyPercent float64
+	}{
+		executor: "serial",
+		timeout:  10 * time.Minute,
+	}
+)
+
+func init() {
+	klog.InitFlags(flag.CommandLine)
+
+	flag.StringVar(&flags.project, "project", flags.project, "GCP project ID for the API calls. Defaults to the project of the resources in the graph file")
+	flag.StringVar(&flags.file, "f", flags.file, "Graph definition file (YAML or JSON)")
+	flag.StringVar(&flags.executor, "executor", flags.executor, "Executor to use for apply (serial, parallel)")
+	flag.BoolVar(&flags.dryRun, "dry-run", flags.dryRun, "Do not make any changes during apply")
+	flag.BoolVar(&flags.explain, "explain", flags.explain, "Print the reasons for the plan of each resource")
+	flag.IntVar(&flags.maxDestroy, "max-destroy", flags.maxDestroy, "Fail if the plan deletes or recreates more than this number of resources (0 is unlimited)")
+	flag.Float64Var(&flags.maxDestroyPercent, "max-destroy-percent", flags.maxDestroyPercent, "Fail if the plan deletes or recreates more than this percentage of the resources (0 is unlimited)")
+	flag.BoolVar(&flags.force, "force", flags.force, "Continue if the plan exceeds -max-destroy or -max-destroy-percent")
+	flag.BoolVar(&flags.mock, "mock", flags.mock, "Use an in-memory mock of the cloud instead of GCP (for debugging)")
+	flag.DurationVar(&flags.timeout, "timeout", flags.timeout, "Timeout for the command")
+
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [flags] plan|diff|apply\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+}
+
+func main() {
+	flag.Parse()
+
+	if err := run(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run() error {
+	if flag.NArg() != 1 {
+		flag.Usage()
+		return fmt.Errorf("expected a single command, got %v", flag.Args())
+	}
+	cmd := flag.Arg(0)
+	switch cmd {
+	case "plan", "diff", "apply":
+	default:
+		flag.Usage()
+		return fmt.Errorf("invalid command %q", cmd)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), flags.timeout)
+	defer cancel()
+
+	b, project, err := loadGraph(flags.file)
+	if err != nil {
+		return err
+	}
+	cl, err := newCloud(ctx, project)
+	if err != nil {
+		return err
+	}
+	want, err := b.Build()
+	if err != nil {
+		return err
+	}
+	var opts []plan.Option
+	if flags.explain {
+		opts = append(opts, plan.ExplainOption())
+	}
+	if flags.maxDestroy > 0 || flags.maxDestroyPercent > 0 {
+		opts = append(opts, plan.BlastRadiusOption(plan.BlastRadius{
+			MaxNodes:   flags.maxDestroy,
+			MaxPercent: flags.maxDestroyPercent,
+			Force:      flags.force,
+		}))
+	}
+	// plan.Do() will SyncFromCloud() all of the resources in the graph.
+	result, err := plan.Do(ctx, cl, want, opts...)
+	if err != nil {
+		return err
+	}
+
+	switch cmd {
+	case "plan":
+		printPlan(result, false)
+	case "diff":
+		printPlan(result, true)
+	case "apply":
+		printPlan(result, false)
+		return apply(ctx, cl, result.Actions)
+	}
+	return nil
+}
+
+// loadGraph loads the graph in file and returns the project to use for the
+// API calls. The project is -project if set, otherwise the project of the
+// resources in the graph.
+func loadGraph(file string) (*rgraph.Builder, string, error) {
+	if file == "" {
+		return nil, "", fmt.Errorf("-f must be set")
+	}
+	b, err := loader.LoadFile(file)
+	if err != nil {
+		return nil, "", err
+	}
+	if flags.project != "" {
+		return b, flags.project, nil
+	}
+	var project string
+	for _, nb := range b.All() {
+		switch p := nb.ID().ProjectID; {
+		case project == "":
+			project = p
+		case project != p:
+			return nil, "", fmt.Errorf("%s: resources are in more than one project (%s, %s); set -project", file, project, p)
+		}
+	}
+	if project == "" {
+		return nil, "", fmt.Errorf("%s: no resources in the graph", file)
+	}
+	return b, project, nil
+}
+
+func newCloud(ctx context.Context, project string) (cloud.Cloud, error) {
+	if flags.mock {
+		return cloud.NewMockGCE(&cloud.SingleProjectRouter{ID: project}), nil
+	}
+	credentials, err := google.FindDefaultCredentials(ctx, compute.ComputeScope)
+	if err != nil {
+		return nil, err
+	}
+	client := oauth2.NewClient(ctx, credentials.TokenSource)
+	svc, err := cloud.NewService(ctx, client, &cloud.SingleProjectRouter{ID: project}, &cloud.NopRateLimiter{})
+	if err != nil {
+		return nil, err
+	}
+	return cloud.NewGCE(svc), nil
+}
+
+func printPlan(result *plan.Result, showDiff bool) {
+	nodes := result.Want.All()
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].ID().String() < nodes[j].ID().String() })
+
+	counts := map[rnode.Operation]int{}
+	for _, n := range nodes {
+		counts[n.Plan().Op()]++
+		details := n.Plan().Details()
+		fmt.Printf("%-8s %v: %s\n", details.Operation, n.ID(), details.Why)
+		if showDiff && details.Diff != nil {
+			for _, item := range details.Diff.Items {
+				fmt.Printf("  [%s] %s: %v -> %v\n", item.State, item.Path, item.A, item.B)
+			}
+		}
+		for _, reason := range result.Explain(n.ID()) {
+			fmt.Printf("  because %s\n", reason)
+		}
+	}
+	fmt.Printf("\nPlan: %d to create, %d to recreate, %d to update, %d to delete, %d unchanged.\n",
+		counts[rnode.OpCreate], counts[rnode.OpRecreate], counts[rnode.OpUpdate], counts[rnode.OpDelete], counts[rnode.OpNothing])
+}
+
+func apply(ctx context.Context, cl cloud.Cloud, actions []exec.Action) error {
+	var (
+		ex  exec.Executor
+		err error
+	)
+	opts := []exec.Option{exec.DryRunOption(flags.dryRun)}
+	switch flags.executor {
+	case "serial":
+		ex, err = exec.NewSerialExecutor(cl, actions, opts...)
+	case "parallel":
+		ex, err = exec.NewParallelExecutor(cl, actions, opts...)
+	default:
+		return fmt.Errorf("invalid -executor %q", flags.executor)
+	}
+	if err != nil {
+		return err
+	}
+
+	result, runErr := ex.Run(ctx)
+	if result != nil {
+		fmt.Printf("\nApply: %d completed, %d errors, %d pending.\n", len(result.Completed), len(result.Errors), len(result.Pending))
+		for _, a := range result.Errors {
+			fmt.Printf("  ERROR %s: %v\n", a.Action, a.Err)
+		}
+		for _, a := range result.Pending {
+			fmt.Printf("  PENDING %s\n", a)
+		}
+	}
+	return runErr
+}
@@ -151,6 +151,10 @@ func TestBackendServiceUpdate(t *testing.T) {
 	}
 
 	t.Logf("tcpr = %s", pretty.Sprint(tcpr))
+	mID, err := meshID(meshURL)
+	if err != nil {
+		t.Fatalf("meshID(%s) = (_, %v), want (_, nil)", meshURL, err)
+	}
 
 	graph, err := graphBuilder.Build()
 	if err != nil {
@@ -215,6 +219,7 @@ func TestBackendServiceUpdate(t *testing.T) {
 		{Type: exec.ActionTypeMeta, Name: eventName(bs2ID)},
 		{Type: exec.ActionTypeMeta, Name: eventName(hc1ID)},
 		{Type: exec.ActionTypeMeta, Name: eventName(hc2ID)},
+		{Type: exec.ActionTypeMeta, Name: eventName(mID)},
 		{Type: exec.ActionTypeCreate, Name: actionName(exec.ActionTypeCreate, bs1ID)},
 		{Type: exec.ActionTypeCreate, Name: actionName(exec.ActionTypeCreate, tcpr)},
 		{Type: exec.ActionTypeDelete, Name: actionName(exec.ActionTypeDelete, bs1ID)},
@@ -63,12 +63,17 @@ func TestHealthcheckUpdate(t *testing.T) {
 		t.Fatalf("buildTCPRoute(_, hc-update-test, _, _, _) = (_, %v), want (_, nil)", err)
 	}
 	t.Logf("TCPRoute created: %v", tcprID)
+	mID, err := meshID(meshURL)
+	if err != nil {
+		t.Fatalf("meshID(%s) = (_, %v), want (_, nil)", meshURL, err)
+	}
 
 	expectedActions := []exec.ActionMetadata{
 		{Type: exec.ActionTypeCreate, Name: actionName(exec.ActionTypeCreate, tcprID)},
 		{Type: exec.ActionTypeCreate, Name: actionName(exec.ActionTypeCreate, bsID)},
 		{Type: exec.ActionTypeCreate, Name: actionName(exec.ActionTypeCreate, hcID)},
 		{Type: exec.ActionTypeCreate, Name: actionName(exec.ActionTypeCreate, negID)},
+		{Type: exec.ActionTypeMeta, Name: eventName(mID)},
 	}
 	processGraphAndExpectActions(t, graphBuilder, expectedActions)
 
@@ -92,6 +97,7 @@ func TestHealthcheckUpdate(t *testing.T) {
 		{Type: exec.ActionTypeUpdate, Name: actionName(exec.ActionTypeUpdate, tcprID)},
 		{Type: exec.ActionTypeMeta, Name: eventName(negID)},
 		{Type: exec.ActionTypeMeta, Name: eventName(bsID)},
+		{Type: exec.ActionTypeMeta, Name: eventName(mID)},
 	}
 	processGraphAndExpectActions(t, graphBuilder, expectedActions)
 	checkGCEHealthCheck(t, ctx, theCloud, hcID, 25)
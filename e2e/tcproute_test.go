@@ -21,12 +21,12 @@ import (
 	"testing"
 
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
-	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/cerrors"
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph"
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/exec"
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode"
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/backendservice"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/genericnode"
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/networkendpointgroup"
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/tcproute"
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/workflow/plan"
@@ -187,6 +187,10 @@ func buildTCPRoute(graphBuilder *rgraph.Builder, name, meshURL string, rules []*
 		return nil, err
 	}
 
+	if _, err := addMesh(graphBuilder, meshURL); err != nil {
+		return nil, err
+	}
+
 	tcpRouteBuilder := tcproute.NewBuilder(tcpID)
 	tcpRouteBuilder.SetOwnership(rnode.OwnershipManaged)
 	tcpRouteBuilder.SetState(rnode.NodeExists)
@@ -196,6 +200,27 @@ func buildTCPRoute(graphBuilder *rgraph.Builder, name, meshURL string, rules []*
 	return tcpID, nil
 }
 
+// meshID returns the ResourceID of the Mesh referenced by meshURL.
+func meshID(meshURL string) (*cloud.ResourceID, error) {
+	if id, err := cloud.ParseNetworkServicesName(meshURL); err == nil {
+		return id, nil
+	}
+	return cloud.ParseResourceURL(meshURL)
+}
+
+// addMesh adds the Mesh referenced by meshURL to the graph as an external
+// node. TcpRoutes reference the Mesh so it must be present in the graph.
+func addMesh(graphBuilder *rgraph.Builder, meshURL string) (*cloud.ResourceID, error) {
+	id, err := meshID(meshURL)
+	if err != nil {
+		return nil, err
+	}
+	if graphBuilder.Get(id) == nil {
+		graphBuilder.Add(genericnode.NewBuilder(id))
+	}
+	return id, nil
+}
+
 type routesServices struct {
 	bsID    *cloud.ResourceID
 	address string
@@ -236,6 +261,10 @@ func buildTCPRouteWithBackends(graphBuilder *rgraph.Builder, name, meshURL strin
 		return nil, err
 	}
 
+	if _, err := addMesh(graphBuilder, meshURL); err != nil {
+		return nil, err
+	}
+
 	tcpRouteBuilder := tcproute.NewBuilder(tcpID)
 	tcpRouteBuilder.SetOwnership(rnode.OwnershipManaged)
 	tcpRouteBuilder.SetState(rnode.NodeExists)
@@ -249,24 +278,22 @@ func buildTCPRouteWithBackends(graphBuilder *rgraph.Builder, name, meshURL strin
 // TODO: fix ensureMesh so it returns a mesh with hash suffix added to the mesh
 func ensureMesh(ctx context.Context, t *testing.T, meshName string) (string, *meta.Key) {
 	meshKey := meta.GlobalKey(resourceName(meshName))
-	mesh, err := theCloud.Meshes().Get(ctx, meshKey)
+	mesh, err := cloud.GetOrNil(theCloud.Meshes().Get(ctx, meshKey))
 	if err != nil {
-		if cerrors.IsGoogleAPINotFound(err) {
-			// Mesh not found create one
-			meshLocal := networkservices.Mesh{
-				Name: resourceName(meshName),
-			}
-			t.Logf("Insert mesh %v", meshLocal)
-			err = theCloud.Meshes().Insert(ctx, meshKey, &meshLocal)
-			if err != nil {
-				t.Fatalf("theCloud.Meshes().Insert(_, %v, %+v) = %v, want nil", meshKey, meshLocal, err)
-			}
-			mesh, err = theCloud.Meshes().Get(ctx, meshKey)
-			if err != nil {
-				t.Fatalf("theCloud.Meshes().Get(_, %v) = %v, want nil", meshKey, err)
-			}
-		} else {
-			t.Fatalf("theCloud.Meshes().Get(_, %s) = %v, want nil", meshKey, err)
+		t.Fatalf("theCloud.Meshes().Get(_, %s) = %v, want nil", meshKey, err)
+	}
+	if mesh == nil {
+		meshLocal := networkservices.Mesh{
+			Name: resourceName(meshName),
+		}
+		t.Logf("Insert mesh %v", meshLocal)
+		err = theCloud.Meshes().Insert(ctx, meshKey, &meshLocal)
+		if err != nil {
+			t.Fatalf("theCloud.Meshes().Insert(_, %v, %+v) = %v, want nil", meshKey, meshLocal, err)
+		}
+		mesh, err = theCloud.Meshes().Get(ctx, meshKey)
+		if err != nil {
+			t.Fatalf("theCloud.Meshes().Get(_, %v) = %v, want nil", meshKey, err)
 		}
 	}
 	return mesh.SelfLink, meshKey
@@ -331,6 +358,10 @@ func TestRgraphTCPRouteAddBackends(t *testing.T) {
 	if err != nil {
 		t.Fatalf("buildTCPRoute(_, tcproute-test, _, _, _) = (_, %v), want (_, nil)", err)
 	}
+	mID, err := meshID(meshURL)
+	if err != nil {
+		t.Fatalf("meshID(%s) = (_, %v), want (_, nil)", meshURL, err)
+	}
 	t.Logf("TCPRoute created: %v", tcprID)
 	t.Cleanup(func() {
 		err := theCloud.TcpRoutes().Delete(ctx, tcprID.Key)
@@ -342,6 +373,7 @@ func TestRgraphTCPRouteAddBackends(t *testing.T) {
 		{Type: exec.ActionTypeCreate, Name: actionName(exec.ActionTypeCreate, bsID)},
 		{Type: exec.ActionTypeCreate, Name: actionName(exec.ActionTypeCreate, hcID)},
 		{Type: exec.ActionTypeCreate, Name: actionName(exec.ActionTypeCreate, negID)},
+		{Type: exec.ActionTypeMeta, Name: eventName(mID)},
 	}
 	processGraphAndExpectActions(t, graphBuilder, expectedActions)
 
@@ -389,6 +421,7 @@ func TestRgraphTCPRouteAddBackends(t *testing.T) {
 		{Type: exec.ActionTypeMeta, Name: eventName(negID)},
 		{Type: exec.ActionTypeMeta, Name: eventName(bsID)},
 		{Type: exec.ActionTypeMeta, Name: eventName(hcID)},
+		{Type: exec.ActionTypeMeta, Name: eventName(mID)},
 	}
 	processGraphAndExpectActions(t, graphBuilder, expectedActions)
 	checkGCEBackendService(t, ctx, theCloud, hcID2, bsID2, 80)
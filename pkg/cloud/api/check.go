@@ -24,6 +24,7 @@ import (
 // checkPostAccess validates the fields for consistency. See the error messages
 // below for the properties being checked.
 func checkPostAccess(traits *FieldTraits, v reflect.Value) error {
+	ct := traits.compile(v.Type())
 	acc := newAcceptorFuncs()
 	acc.onStructF = func(p Path, v reflect.Value) (bool, error) {
 		if p.Equal(Path{}.Pointer().Field("ServerResponse")) {
@@ -34,12 +35,13 @@ func checkPostAccess(traits *FieldTraits, v reflect.Value) error {
 		if err != nil {
 			return false, fmt.Errorf("checkPostAccess %v: %w", p, err)
 		}
+		tc := ct.cursor(p)
 		for i := 0; i < v.NumField(); i++ {
 			ft := v.Type().Field(i)
 			if ft.Name == "NullFields" || ft.Name == "ForceSendFields" {
 				continue
 			}
-			fType := traits.fieldType(p.Field(ft.Name))
+			fType := tc.field(i).fieldType(p.Field(ft.Name))
 			fv := v.Field(i)
 			fp := p.Field(ft.Name)
 
@@ -59,7 +61,7 @@ func checkPostAccess(traits *FieldTraits, v reflect.Value) error {
 				case !fv.IsZero() && acc.inNull(ft.Name):
 					return false, fmt.Errorf("%s is non-nil and also in NullFields", fp)
 				}
-			case FieldTypeOrdinary, FieldTypeAllowZeroValue:
+			case FieldTypeOrdinary, FieldTypeAllowZeroValue, FieldTypeDescription:
 				continue
 			default:
 				return false, fmt.Errorf("invalid FieldType: %q", fType)
@@ -162,11 +164,13 @@ func checkSchema(t reflect.Type) error {
 		return fmt.Errorf("object is not a pointer (%s)", t)
 	}
 	st := t.Elem()
-	for _, fn := range []string{"Name", "SelfLink"} {
-		f, ok := st.FieldByName(fn)
-		if !ok || f.Type.Kind() != reflect.String {
-			return fmt.Errorf("object has missing or invalid type for the %s field", fn)
-		}
+	if f, ok := st.FieldByName("Name"); !ok || f.Type.Kind() != reflect.String {
+		return fmt.Errorf("object has missing or invalid type for the Name field")
+	}
+	// Some resources (e.g. networkservices ServiceBinding) do not have a
+	// SelfLink.
+	if f, ok := st.FieldByName("SelfLink"); ok && f.Type.Kind() != reflect.String {
+		return fmt.Errorf("object has invalid type for the SelfLink field")
 	}
 
 	return nil
@@ -257,6 +257,13 @@ func TestCheckSchema(t *testing.T) {
 		Name     int
 		SelfLink string
 	}
+	type noSelfLinkSt struct {
+		Name string
+	}
+	type badSelfLinkSt struct {
+		Name     string
+		SelfLink int
+	}
 
 	for _, tc := range []struct {
 		name    string
@@ -268,6 +275,8 @@ func TestCheckSchema(t *testing.T) {
 		{name: "fails cycle check", t: reflect.TypeOf(&rec2{}), wantErr: true},
 		{name: "fails type check", t: reflect.TypeOf(&badSt{}), wantErr: true},
 		{name: "fails type check bad fields", t: reflect.TypeOf(&badStFieldsBad{}), wantErr: true},
+		{name: "no SelfLink is ok", t: reflect.TypeOf(&noSelfLinkSt{})},
+		{name: "fails type check bad SelfLink", t: reflect.TypeOf(&badSelfLinkSt{}), wantErr: true},
 	} {
 		t.Run(tc.name, func(t *testing.T) {
 			err := checkSchema(tc.t)
@@ -0,0 +1,148 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+)
+
+// WithClearedFields returns a copy of want where the fields that are set in
+// got but are zero in want are added to the metafields of the enclosing
+// struct, so that an update with want clears them. Pointers are added to
+// NullFields, other fields are added to ForceSendFields. OutputOnly and
+// System fields are not changed.
+//
+// Example: got has .Iap = {Enabled: true} and want has .Iap = {}. The
+// returned resource has .Iap.ForceSendFields = ["Enabled"].
+//
+// Fields in map values are not handled.
+func WithClearedFields[GA any, Alpha any, Beta any](got, want Resource[GA, Alpha, Beta]) (Resource[GA, Alpha, Beta], error) {
+	d, err := got.Diff(want)
+	if err != nil {
+		return nil, fmt.Errorf("WithClearedFields: %w", err)
+	}
+	ret := want.Clone()
+	if !d.HasDiff() {
+		return ret, nil
+	}
+
+	var obj any
+	switch ret.Version() {
+	case meta.VersionGA:
+		obj, err = ret.ToGA()
+	case meta.VersionAlpha:
+		obj, err = ret.ToAlpha()
+	case meta.VersionBeta:
+		obj, err = ret.ToBeta()
+	default:
+		return nil, fmt.Errorf("WithClearedFields: invalid version %q", ret.Version())
+	}
+	if err != nil {
+		return nil, fmt.Errorf("WithClearedFields: %w", err)
+	}
+	v := reflect.ValueOf(obj)
+	for _, item := range d.Items {
+		markCleared(v, item.Path)
+	}
+	return ret, nil
+}
+
+// markCleared adds the field at p to the metafields of its parent struct in
+// v if the field is zero.
+func markCleared(v reflect.Value, p Path) {
+	if len(p) == 0 || p[len(p)-1][0] != pathField {
+		return
+	}
+	parent, ok := resolveValue(v, p[:len(p)-1])
+	if !ok || parent.Kind() != reflect.Struct {
+		return
+	}
+	name := p[len(p)-1][1:]
+	fv := parent.FieldByName(name)
+	if !fv.IsValid() || !isCleared(fv) {
+		return
+	}
+	acc, err := newMetafieldAccessor(parent)
+	if err != nil {
+		return
+	}
+	if fv.Kind() == reflect.Pointer {
+		addMetafield(acc.nullFields, name)
+	} else {
+		addMetafield(acc.forceSendFields, name)
+	}
+}
+
+// isCleared returns true if v is the zero value or an empty slice or map.
+func isCleared(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Slice, reflect.Map:
+		return v.Len() == 0
+	}
+	return v.IsZero()
+}
+
+// addMetafield adds name to the sorted metafield list mf if it is not
+// already present.
+func addMetafield(mf reflect.Value, name string) {
+	l := mf.Interface().([]string)
+	for _, x := range l {
+		if x == name {
+			return
+		}
+	}
+	l = append(append([]string{}, l...), name)
+	sort.Strings(l)
+	mf.Set(reflect.ValueOf(l))
+}
+
+// resolveValue returns the value at p in v. p may only contain field,
+// pointer and slice index references. Returns false if p cannot be resolved,
+// e.g. due to a nil pointer.
+func resolveValue(v reflect.Value, p Path) (reflect.Value, bool) {
+	for _, elem := range p {
+		switch {
+		case elem == string(pathPointer):
+			if v.Kind() != reflect.Pointer || v.IsNil() {
+				return reflect.Value{}, false
+			}
+			v = v.Elem()
+		case elem[0] == pathField:
+			if v.Kind() != reflect.Struct {
+				return reflect.Value{}, false
+			}
+			v = v.FieldByName(elem[1:])
+			if !v.IsValid() {
+				return reflect.Value{}, false
+			}
+		case isSliceIndex(elem):
+			i, _ := strconv.Atoi(elem[1:])
+			if v.Kind() != reflect.Slice || i >= v.Len() {
+				return reflect.Value{}, false
+			}
+			v = v.Index(i)
+		default:
+			return reflect.Value{}, false
+		}
+	}
+	return v, true
+}
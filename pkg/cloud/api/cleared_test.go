@@ -0,0 +1,89 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"github.com/google/go-cmp/cmp"
+	alpha "google.golang.org/api/compute/v0.alpha"
+	beta "google.golang.org/api/compute/v0.beta"
+	"google.golang.org/api/compute/v1"
+)
+
+func TestWithClearedFields(t *testing.T) {
+	t.Parallel()
+
+	id := &cloud.ResourceID{ProjectID: "proj", Resource: "backendServices", Key: meta.GlobalKey("bs")}
+	tt := &BaseTypeTrait[compute.BackendService, alpha.BackendService, beta.BackendService]{}
+	freeze := func(bs *compute.BackendService) Resource[compute.BackendService, alpha.BackendService, beta.BackendService] {
+		t.Helper()
+		m := NewResource(id, tt)
+		if err := m.Set(bs); err != nil {
+			t.Fatalf("Set() = %v", err)
+		}
+		r, err := m.Freeze()
+		if err != nil {
+			t.Fatalf("Freeze() = %v", err)
+		}
+		return r
+	}
+
+	got := freeze(&compute.BackendService{
+		Name:               "bs",
+		Description:        "d",
+		ConnectionDraining: &compute.ConnectionDraining{DrainingTimeoutSec: 10},
+		Iap:                &compute.BackendServiceIAP{Enabled: true, Oauth2ClientId: "id"},
+		Backends:           []*compute.Backend{{Group: "g", CapacityScaler: 1}},
+		CustomRequestHeaders: []string{
+			"h",
+		},
+	})
+	want := freeze(&compute.BackendService{
+		Name:                 "bs",
+		Iap:                  &compute.BackendServiceIAP{Oauth2ClientId: "id"},
+		Backends:             []*compute.Backend{{Group: "g"}},
+		CustomRequestHeaders: []string{},
+	})
+
+	r, err := WithClearedFields(got, want)
+	if err != nil {
+		t.Fatalf("WithClearedFields() = %v", err)
+	}
+	bs, _ := r.ToGA()
+	for _, tc := range []struct {
+		name      string
+		got, want []string
+	}{
+		{"ForceSendFields", bs.ForceSendFields, []string{"CustomRequestHeaders", "Description"}},
+		{"NullFields", bs.NullFields, []string{"ConnectionDraining"}},
+		{"Iap.ForceSendFields", bs.Iap.ForceSendFields, []string{"Enabled"}},
+		{"Backends[0].ForceSendFields", bs.Backends[0].ForceSendFields, []string{"CapacityScaler"}},
+	} {
+		if diff := cmp.Diff(tc.got, tc.want); diff != "" {
+			t.Errorf("%s: -got,+want: %s", tc.name, diff)
+		}
+	}
+
+	// want is not modified.
+	orig, _ := want.ToGA()
+	if len(orig.ForceSendFields) != 0 || len(orig.NullFields) != 0 || len(orig.Iap.ForceSendFields) != 0 {
+		t.Errorf("want was modified: %+v", orig)
+	}
+}
@@ -0,0 +1,186 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/gob"
+	"fmt"
+	"reflect"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+)
+
+// Compact returns a Resource with the same contents as r that is stored in
+// a compressed encoding. The resource is decoded each time it is accessed
+// (ToGA(), Diff() etc.) and the decoded form is not retained, trading CPU
+// for memory. This is useful for graphs with many large resources, e.g.
+// UrlMaps with thousands of host rules.
+//
+// The pointers returned by ToGA() etc. of a compact Resource are to a new
+// copy of the resource on every call.
+//
+// An error is returned if the resource does not survive the encoding
+// unchanged; use r as is in this case.
+func Compact[GA any, Alpha any, Beta any](r Resource[GA, Alpha, Beta]) (Resource[GA, Alpha, Beta], error) {
+	switch r := r.(type) {
+	case *compactResource[GA, Alpha, Beta]:
+		return r, nil
+	case *resource[GA, Alpha, Beta]:
+		ret := &compactResource[GA, Alpha, Beta]{
+			id:        r.ResourceID(),
+			ver:       r.Version(),
+			typeTrait: r.x.typeTrait,
+		}
+		obj, err := versionObj(r)
+		if err != nil {
+			return nil, fmt.Errorf("Compact: %w", err)
+		}
+		var buf bytes.Buffer
+		zw := gzip.NewWriter(&buf)
+		if err := gob.NewEncoder(zw).Encode(obj); err != nil {
+			return nil, fmt.Errorf("Compact: %w", err)
+		}
+		if err := zw.Close(); err != nil {
+			return nil, fmt.Errorf("Compact: %w", err)
+		}
+		ret.data = buf.Bytes()
+
+		// The encoding does not distinguish between some values (e.g. nil
+		// and empty slices). Check that the resource round-trips.
+		decoded, err := ret.decode()
+		if err != nil {
+			return nil, fmt.Errorf("Compact: %w", err)
+		}
+		decodedObj, err := versionObj(decoded)
+		if err != nil {
+			return nil, fmt.Errorf("Compact: %w", err)
+		}
+		if decoded.Version() != r.Version() || !reflect.DeepEqual(obj, decodedObj) {
+			return nil, fmt.Errorf("Compact: %v does not round-trip through the encoding", r.ResourceID())
+		}
+		return ret, nil
+	}
+	return nil, fmt.Errorf("Compact: unsupported Resource type %T", r)
+}
+
+// versionObj returns the object for the Version() of r.
+func versionObj[GA any, Alpha any, Beta any](r Resource[GA, Alpha, Beta]) (any, error) {
+	switch r.Version() {
+	case meta.VersionGA:
+		return r.ToGA()
+	case meta.VersionAlpha:
+		return r.ToAlpha()
+	case meta.VersionBeta:
+		return r.ToBeta()
+	}
+	return nil, fmt.Errorf("invalid version %q", r.Version())
+}
+
+// compactResource stores the object for the Version of the resource. The
+// other versions are derived from it when the resource is decoded.
+type compactResource[GA any, Alpha any, Beta any] struct {
+	id        *cloud.ResourceID
+	ver       meta.Version
+	typeTrait TypeTrait[GA, Alpha, Beta]
+	data      []byte
+}
+
+// decode the resource.
+func (obj *compactResource[GA, Alpha, Beta]) decode() (Resource[GA, Alpha, Beta], error) {
+	zr, err := gzip.NewReader(bytes.NewReader(obj.data))
+	if err != nil {
+		return nil, err
+	}
+	dec := gob.NewDecoder(zr)
+	u := NewResource(obj.id, obj.typeTrait)
+	switch obj.ver {
+	case meta.VersionGA:
+		var x GA
+		if err := dec.Decode(&x); err != nil {
+			return nil, err
+		}
+		err = u.Set(&x)
+	case meta.VersionAlpha:
+		var x Alpha
+		if err := dec.Decode(&x); err != nil {
+			return nil, err
+		}
+		err = u.SetAlpha(&x)
+	case meta.VersionBeta:
+		var x Beta
+		if err := dec.Decode(&x); err != nil {
+			return nil, err
+		}
+		err = u.SetBeta(&x)
+	default:
+		return nil, fmt.Errorf("invalid version %q", obj.ver)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return u.Freeze()
+}
+
+// Implements Resource.
+func (obj *compactResource[GA, Alpha, Beta]) Version() meta.Version         { return obj.ver }
+func (obj *compactResource[GA, Alpha, Beta]) ResourceID() *cloud.ResourceID { return obj.id }
+
+// ToGA implements Resource.
+func (obj *compactResource[GA, Alpha, Beta]) ToGA() (*GA, error) {
+	r, err := obj.decode()
+	if err != nil {
+		return nil, fmt.Errorf("compactResource: %w", err)
+	}
+	return r.ToGA()
+}
+
+// ToAlpha implements Resource.
+func (obj *compactResource[GA, Alpha, Beta]) ToAlpha() (*Alpha, error) {
+	r, err := obj.decode()
+	if err != nil {
+		return nil, fmt.Errorf("compactResource: %w", err)
+	}
+	return r.ToAlpha()
+}
+
+// ToBeta implements Resource.
+func (obj *compactResource[GA, Alpha, Beta]) ToBeta() (*Beta, error) {
+	r, err := obj.decode()
+	if err != nil {
+		return nil, fmt.Errorf("compactResource: %w", err)
+	}
+	return r.ToBeta()
+}
+
+// Diff implements Resource.
+func (obj *compactResource[GA, Alpha, Beta]) Diff(other Resource[GA, Alpha, Beta]) (*DiffResult, error) {
+	r, err := obj.decode()
+	if err != nil {
+		return nil, fmt.Errorf("compactResource: %w", err)
+	}
+	return r.Diff(other)
+}
+
+// Clone implements Resource. The encoded data is immutable and is shared
+// with the clone.
+func (obj *compactResource[GA, Alpha, Beta]) Clone() Resource[GA, Alpha, Beta] {
+	ret := *obj
+	return &ret
+}
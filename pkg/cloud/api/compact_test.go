@@ -0,0 +1,126 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"github.com/google/go-cmp/cmp"
+	alpha "google.golang.org/api/compute/v0.alpha"
+	beta "google.golang.org/api/compute/v0.beta"
+	ga "google.golang.org/api/compute/v1"
+)
+
+func TestCompact(t *testing.T) {
+	t.Parallel()
+
+	id := &cloud.ResourceID{ProjectID: "proj-1", Resource: "urlMaps", Key: meta.GlobalKey("um")}
+	newRes := func(t *testing.T, hosts ...string) Resource[ga.UrlMap, alpha.UrlMap, beta.UrlMap] {
+		t.Helper()
+		u := NewResource[ga.UrlMap, alpha.UrlMap, beta.UrlMap](id, nil)
+		um := &ga.UrlMap{
+			Name:           "um",
+			DefaultService: "bs",
+		}
+		for _, h := range hosts {
+			um.HostRules = append(um.HostRules, &ga.HostRule{Hosts: []string{h}, PathMatcher: "pm"})
+		}
+		if err := u.Set(um); err != nil {
+			t.Fatalf("Set() = %v", err)
+		}
+		r, err := u.Freeze()
+		if err != nil {
+			t.Fatalf("Freeze() = %v", err)
+		}
+		return r
+	}
+
+	r := newRes(t, "a.com", "b.com")
+	c, err := Compact(r)
+	if err != nil {
+		t.Fatalf("Compact() = %v, want nil", err)
+	}
+	if c.Version() != r.Version() || !c.ResourceID().Equal(r.ResourceID()) {
+		t.Errorf("Compact() = (%v, %v), want (%v, %v)", c.Version(), c.ResourceID(), r.Version(), r.ResourceID())
+	}
+	gotGA, err := c.ToGA()
+	if err != nil {
+		t.Fatalf("ToGA() = %v", err)
+	}
+	wantGA, _ := r.ToGA()
+	if diff := cmp.Diff(gotGA, wantGA); diff != "" {
+		t.Errorf("ToGA(): -got,+want: %s", diff)
+	}
+	gotBeta, err := c.ToBeta()
+	if err != nil {
+		t.Fatalf("ToBeta() = %v", err)
+	}
+	wantBeta, _ := r.ToBeta()
+	if diff := cmp.Diff(gotBeta, wantBeta); diff != "" {
+		t.Errorf("ToBeta(): -got,+want: %s", diff)
+	}
+
+	// Each access decodes a new copy.
+	gotGA.Name = "changed"
+	if again, _ := c.ToGA(); again.Name != "um" {
+		t.Errorf("ToGA().Name = %q after changing a previous copy, want \"um\"", again.Name)
+	}
+
+	for _, tc := range []struct {
+		name     string
+		a, b     Resource[ga.UrlMap, alpha.UrlMap, beta.UrlMap]
+		wantDiff bool
+	}{
+		{name: "compact, same", a: c, b: r},
+		{name: "same, compact", a: r, b: c},
+		{name: "compact, compact", a: c, b: c.Clone()},
+		{name: "compact, different", a: c, b: newRes(t, "a.com"), wantDiff: true},
+	} {
+		d, err := tc.a.Diff(tc.b)
+		if err != nil {
+			t.Fatalf("%s: Diff() = %v, want nil", tc.name, err)
+		}
+		if d.HasDiff() != tc.wantDiff {
+			t.Errorf("%s: Diff().HasDiff() = %t, want %t", tc.name, d.HasDiff(), tc.wantDiff)
+		}
+	}
+
+	if c2, err := Compact(c); err != nil || c2 != c {
+		t.Errorf("Compact(compact) = %p, %v; want %p, nil", c2, err, c)
+	}
+}
+
+func TestCompactRoundTripError(t *testing.T) {
+	t.Parallel()
+
+	u := NewResource[ga.UrlMap, alpha.UrlMap, beta.UrlMap](&cloud.ResourceID{
+		ProjectID: "proj-1", Resource: "urlMaps", Key: meta.GlobalKey("um"),
+	}, nil)
+	// The encoding does not preserve empty (non-nil) slices.
+	if err := u.Set(&ga.UrlMap{Name: "um", HostRules: []*ga.HostRule{{Hosts: []string{}}}}); err != nil {
+		t.Fatalf("Set() = %v", err)
+	}
+	r, err := u.Freeze()
+	if err != nil {
+		t.Fatalf("Freeze() = %v", err)
+	}
+	if _, err := Compact(r); err == nil {
+		t.Error("Compact() = nil, want error")
+	}
+}
@@ -0,0 +1,223 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// compiledTraitsCache caches the compiledTraits for each (type, traits)
+// combination. TypeTraits construct a new FieldTraits on every call to
+// FieldTraits() so the cache is keyed by the contents of the traits rather
+// than the pointer.
+var compiledTraitsCache sync.Map // map[compiledTraitsKey]*compiledTraits
+
+type compiledTraitsKey struct {
+	t   reflect.Type
+	sig string
+}
+
+// compiledTraits is a FieldTraits resolved against a specific type. Traits
+// are stored in a tree that mirrors the type, with struct fields referenced
+// by index. This allows the FieldType of a field to be found by walking the
+// tree as the value is traversed instead of matching the Path against every
+// trait.
+type compiledTraits struct {
+	// traits is used as a fallback when the traits could not be compiled
+	// (root == nil).
+	traits *FieldTraits
+	root   *traitNode
+}
+
+// traitNode is a node in the compiledTraits tree. Only the nodes that are
+// on the path to a trait exist.
+type traitNode struct {
+	// eff is the effective FieldType at this node, taking into account
+	// the traits of the parent nodes.
+	eff FieldType
+	// effIdx is the index in FieldTraits.fields of the trait for eff, -1
+	// if eff is the default.
+	effIdx int
+	// fields is indexed by the struct field index. byName maps the field
+	// name to the same nodes.
+	fields []*traitNode
+	byName map[string]*traitNode
+	// elem is the node for pointer, slice and map elements.
+	elem *traitNode
+}
+
+// compile returns the traits compiled for values of type t. The result is
+// cached.
+func (dt *FieldTraits) compile(t reflect.Type) *compiledTraits {
+	if dt == nil {
+		dt = &FieldTraits{}
+	}
+	key := compiledTraitsKey{t: t, sig: dt.signature()}
+	if ct, ok := compiledTraitsCache.Load(key); ok {
+		return ct.(*compiledTraits)
+	}
+	ct := &compiledTraits{traits: dt, root: dt.compileTree(t)}
+	actual, _ := compiledTraitsCache.LoadOrStore(key, ct)
+	return actual.(*compiledTraits)
+}
+
+// signature uniquely identifies the contents of the traits.
+func (dt *FieldTraits) signature() string {
+	var b strings.Builder
+	for _, f := range dt.fields {
+		for _, elem := range f.path {
+			b.WriteString(elem)
+		}
+		b.WriteByte('=')
+		b.WriteString(string(f.fType))
+		b.WriteByte(';')
+	}
+	return b.String()
+}
+
+// compileTree returns nil if the traits cannot be compiled, i.e. they
+// reference a specific slice index or map key. Traits that do not match the
+// schema of t (e.g. ServerResponse for non-API types) are skipped as they
+// cannot match any field.
+func (dt *FieldTraits) compileTree(t reflect.Type) *traitNode {
+	root := &traitNode{eff: FieldTypeOrdinary, effIdx: -1}
+traits:
+	for idx, f := range dt.fields {
+		n := root
+		nt := t
+		for _, elem := range f.path {
+			switch {
+			case elem[0] == pathField:
+				if nt.Kind() != reflect.Struct {
+					continue traits
+				}
+				sf, ok := nt.FieldByName(elem[1:])
+				if !ok || len(sf.Index) != 1 {
+					continue traits
+				}
+				if n.fields == nil {
+					n.fields = make([]*traitNode, nt.NumField())
+					n.byName = map[string]*traitNode{}
+				}
+				i := sf.Index[0]
+				if n.fields[i] == nil {
+					n.fields[i] = &traitNode{effIdx: -1}
+					n.byName[sf.Name] = n.fields[i]
+				}
+				n, nt = n.fields[i], sf.Type
+			case elem == string(pathPointer) && nt.Kind() == reflect.Pointer,
+				elem == anySliceIndex && nt.Kind() == reflect.Slice,
+				elem == anyMapIndex && nt.Kind() == reflect.Map:
+				if n.elem == nil {
+					n.elem = &traitNode{effIdx: -1}
+				}
+				n, nt = n.elem, nt.Elem()
+			case isSliceIndex(elem) || isMapIndex(elem) && elem != anyMapIndex:
+				return nil
+			default:
+				continue traits
+			}
+		}
+		// The first matching trait wins (see fieldTrait()).
+		if n.effIdx == -1 {
+			n.effIdx = idx
+			n.eff = f.fType
+		}
+	}
+	root.propagate(FieldTypeOrdinary, -1)
+	return root
+}
+
+// propagate the effective FieldType from the parent. A trait earlier in the
+// list takes precedence, matching fieldTrait().
+func (n *traitNode) propagate(eff FieldType, effIdx int) {
+	if effIdx != -1 && (n.effIdx == -1 || effIdx < n.effIdx) {
+		n.eff, n.effIdx = eff, effIdx
+	}
+	if n.effIdx == -1 {
+		n.eff = FieldTypeOrdinary
+	}
+	for _, c := range n.fields {
+		if c != nil {
+			c.propagate(n.eff, n.effIdx)
+		}
+	}
+	if n.elem != nil {
+		n.elem.propagate(n.eff, n.effIdx)
+	}
+}
+
+// cursor returns a cursor for the FieldType at p. p is walked from the root
+// of the tree; use the cursor methods when traversing values to avoid this.
+func (ct *compiledTraits) cursor(p Path) traitCursor {
+	if ct.root == nil {
+		return traitCursor{ct: ct}
+	}
+	c := traitCursor{ct: ct, n: ct.root, eff: ct.root.eff}
+	for _, elem := range p {
+		if c.n == nil {
+			break
+		}
+		if elem[0] == pathField {
+			c = c.child(c.n.byName[elem[1:]])
+		} else {
+			c = c.child(c.n.elem)
+		}
+	}
+	return c
+}
+
+// traitCursor is a position in the compiledTraits tree.
+type traitCursor struct {
+	ct  *compiledTraits
+	n   *traitNode
+	eff FieldType
+}
+
+func (c traitCursor) child(n *traitNode) traitCursor {
+	if n == nil {
+		return traitCursor{ct: c.ct, eff: c.eff}
+	}
+	return traitCursor{ct: c.ct, n: n, eff: n.eff}
+}
+
+// field returns the cursor for the i-th field of the struct.
+func (c traitCursor) field(i int) traitCursor {
+	if c.n == nil || c.n.fields == nil {
+		return c.child(nil)
+	}
+	return c.child(c.n.fields[i])
+}
+
+// elem returns the cursor for the pointer, slice or map element.
+func (c traitCursor) elem() traitCursor {
+	if c.n == nil {
+		return c.child(nil)
+	}
+	return c.child(c.n.elem)
+}
+
+// fieldType returns the FieldType at the cursor. p must be the Path of the
+// cursor; it is only used if the traits could not be compiled.
+func (c traitCursor) fieldType(p Path) FieldType {
+	if c.ct.root == nil {
+		return c.ct.traits.fieldType(p)
+	}
+	return c.eff
+}
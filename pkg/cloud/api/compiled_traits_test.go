@@ -0,0 +1,150 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCompiledTraits(t *testing.T) {
+	t.Parallel()
+
+	type sti struct {
+		I  int
+		LS []string
+	}
+	type st struct {
+		I   int
+		S   string
+		St  sti
+		PSt *sti
+		LSt []sti
+		M   map[string]sti
+	}
+
+	root := Path{}.Pointer()
+	paths := []Path{
+		{},
+		root,
+		root.Field("I"),
+		root.Field("S"),
+		root.Field("St"),
+		root.Field("St").Field("I"),
+		root.Field("St").Field("LS"),
+		root.Field("St").Field("LS").Index(0),
+		root.Field("PSt").Pointer().Field("I"),
+		root.Field("LSt").Index(3).Field("I"),
+		root.Field("LSt").Index(3).Field("LS"),
+		root.Field("M").MapIndex("k").Field("I"),
+	}
+
+	for _, tc := range []struct {
+		name         string
+		traits       func(dt *FieldTraits)
+		wantCompiled bool
+	}{
+		{
+			name:         "no traits",
+			traits:       func(*FieldTraits) {},
+			wantCompiled: true,
+		},
+		{
+			name: "fields",
+			traits: func(dt *FieldTraits) {
+				dt.OutputOnly(root.Field("I"))
+				dt.System(root.Field("St"))
+				dt.NonZeroValue(root.Field("PSt").Pointer().Field("I"))
+			},
+			wantCompiled: true,
+		},
+		{
+			name: "wildcards",
+			traits: func(dt *FieldTraits) {
+				dt.OutputOnly(root.Field("LSt").AnySliceIndex().Field("I"))
+				dt.NonZeroValue(root.Field("M").AnyMapIndex().Field("I"))
+			},
+			wantCompiled: true,
+		},
+		{
+			name: "earlier trait takes precedence",
+			traits: func(dt *FieldTraits) {
+				dt.NonZeroValue(root.Field("St").Field("I"))
+				dt.OutputOnly(root.Field("St"))
+				dt.System(root.Field("LSt"))
+				dt.NonZeroValue(root.Field("LSt").AnySliceIndex().Field("I"))
+			},
+			wantCompiled: true,
+		},
+		{
+			name: "specific index falls back",
+			traits: func(dt *FieldTraits) {
+				dt.OutputOnly(root.Field("LSt").Index(3).Field("I"))
+			},
+		},
+		{
+			name: "invalid paths are skipped",
+			traits: func(dt *FieldTraits) {
+				dt.OutputOnly(root.Field("DoesNotExist"))
+				dt.OutputOnly(root.Field("I").Field("X"))
+				dt.OutputOnly(root.Field("S"))
+			},
+			wantCompiled: true,
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			dt := NewFieldTraits()
+			tc.traits(dt)
+			ct := dt.compile(reflect.TypeOf(&st{}))
+			if gotCompiled := ct.root != nil; gotCompiled != tc.wantCompiled {
+				t.Errorf("compiled = %t, want %t", gotCompiled, tc.wantCompiled)
+			}
+			for _, p := range paths {
+				if got, want := ct.cursor(p).fieldType(p), dt.fieldType(p); got != want {
+					t.Errorf("cursor(%v).fieldType() = %q, want %q", p, got, want)
+				}
+			}
+			// Walking the cursor by field index gives the same result.
+			tc := ct.cursor(root).field(4).elem().field(0)
+			p := root.Field("LSt").Index(0).Field("I")
+			if got, want := tc.fieldType(p), dt.fieldType(p); got != want {
+				t.Errorf("cursor.field().elem().field().fieldType() = %q, want %q", got, want)
+			}
+		})
+	}
+}
+
+func TestCompiledTraitsCache(t *testing.T) {
+	t.Parallel()
+
+	type st struct{ I, J int }
+	newTraits := func(p string) *FieldTraits {
+		dt := NewFieldTraits()
+		dt.OutputOnly(Path{}.Pointer().Field(p))
+		return dt
+	}
+	typ := reflect.TypeOf(&st{})
+	if a, b := newTraits("I").compile(typ), newTraits("I").compile(typ); a != b {
+		t.Errorf("compile() returned different results for the same traits")
+	}
+	if a, b := newTraits("I").compile(typ), newTraits("J").compile(typ); a == b {
+		t.Errorf("compile() returned the same result for different traits")
+	}
+}
@@ -20,6 +20,7 @@ import (
 	"fmt"
 	"reflect"
 	"sort"
+	"sync"
 )
 
 type missingFieldOnCopy struct {
@@ -51,6 +52,8 @@ type copier struct {
 	missing []missingFieldOnCopy
 }
 
+// logS logs if logging is enabled. Callers in the hot path should check
+// c.logSFn != nil first to avoid the cost of building the kv arguments.
 func (c *copier) logS(msg string, kv ...any) {
 	if c.logSFn == nil {
 		return
@@ -59,7 +62,43 @@ func (c *copier) logS(msg string, kv ...any) {
 }
 
 func (c *copier) do(dest, src reflect.Value) error {
-	return c.doValues(Path{}, dest, src)
+	// The root Path has spare capacity so extending it does not allocate for
+	// every field. Paths that are retained (e.g. in c.missing) must be
+	// cloned.
+	return c.doValues(make(Path, 0, 16), dest, src)
+}
+
+// copyField maps a field in the src struct to the dest struct.
+type copyField struct {
+	name string
+	src  int
+	// dest is -1 if the field does not exist in dest.
+	dest int
+}
+
+// copyFieldsCache caches the copyFields for each (dest, src) type.
+var copyFieldsCache sync.Map // map[[2]reflect.Type][]copyField
+
+// copyFields returns the mapping of the fields from src to dest. The result is
+// cached as it is the same for every copy between the two types, e.g. GA to
+// Alpha.
+func copyFields(dest, src reflect.Type) []copyField {
+	key := [2]reflect.Type{dest, src}
+	if fields, ok := copyFieldsCache.Load(key); ok {
+		return fields.([]copyField)
+	}
+	var fields []copyField
+	for i := 0; i < src.NumField(); i++ {
+		cf := copyField{name: src.Field(i).Name, src: i, dest: -1}
+		if dest == src {
+			cf.dest = i
+		} else if sf, ok := dest.FieldByName(cf.name); ok && len(sf.Index) == 1 {
+			cf.dest = sf.Index[0]
+		}
+		fields = append(fields, cf)
+	}
+	actual, _ := copyFieldsCache.LoadOrStore(key, fields)
+	return actual.([]copyField)
 }
 
 func (c *copier) doValues(p Path, dest, src reflect.Value) error {
@@ -86,7 +125,9 @@ func (c *copier) doBasic(p Path, dest, src reflect.Value) error {
 		return fmt.Errorf("cannot set dest (%s)", p)
 	}
 	dest.Set(src)
-	c.logS("copyBasic", "path", p, "value", dest.Interface())
+	if c.logSFn != nil {
+		c.logS("copyBasic", "path", p, "value", dest.Interface())
+	}
 	return nil
 }
 
@@ -107,7 +148,9 @@ func (c *copier) doPointer(p Path, dest, src reflect.Value) error {
 			return fmt.Errorf("copyPointer: dest is nil and not addressable: src %T, dest %T", src.Interface(), dest.Interface())
 		}
 		dest.Set(reflect.New(dest.Type().Elem()))
-		c.logS("copyPointer", "path", p, "value", dest.Interface())
+		if c.logSFn != nil {
+			c.logS("copyPointer", "path", p, "value", dest.Interface())
+		}
 	}
 	return c.doValues(p.Pointer(), dest.Elem(), src.Elem())
 }
@@ -128,7 +171,9 @@ func (c *copier) doSlice(p Path, dest, src reflect.Value) error {
 			return err
 		}
 	}
-	c.logS("copySlice", "path", p, "value", newSlice)
+	if c.logSFn != nil {
+		c.logS("copySlice", "path", p, "value", newSlice)
+	}
 
 	if !dest.CanSet() {
 		return fmt.Errorf("cannot set dest (%s)", p)
@@ -142,22 +187,21 @@ func (c *copier) doStruct(p Path, dest, src reflect.Value) error {
 	if dest.Kind() != reflect.Struct || src.Kind() != reflect.Struct {
 		return fmt.Errorf("copyStruct: invalid type (dest: %T, src: %T)", dest.Interface(), src.Interface())
 	}
+	// ServerResponse should be skipped at the top level.
+	atRoot := len(p) == 0 || (len(p) == 1 && p[0] == string(pathPointer))
 	// Copy over fields that are present in both src and dest. Fields in dest
 	// that don't exist in src are left alone.
-	for i := 0; i < src.Type().NumField(); i++ {
-		srcFieldT := src.Type().Field(i)
-		fieldName := srcFieldT.Name
-		destField := dest.FieldByName(fieldName)
-		_, ok := dest.Type().FieldByName(fieldName)
+	for _, cf := range copyFields(dest.Type(), src.Type()) {
+		i, fieldName := cf.src, cf.name
 
-		if !ok {
+		if cf.dest == -1 {
 			// Only non-zero fields are counted towards
 			// the missing fields. Fields explicitly named
 			// in NullFields or ForceSendFields are
 			// handled by copyMetaFields() below.
 			if !src.Field(i).IsZero() {
 				c.missing = append(c.missing, missingFieldOnCopy{
-					Path:  p.Field(fieldName),
+					Path:  clonePath(p.Field(fieldName)),
 					Value: src.Field(i).Interface(),
 				})
 				c.logS("copyStruct missing field", "path", p, "fieldName", fieldName)
@@ -165,10 +209,10 @@ func (c *copier) doStruct(p Path, dest, src reflect.Value) error {
 			continue
 		}
 
-		// ServerResponse should be skipped.
-		if (p.Equal(Path{}) || p.Equal(Path{}.Pointer())) && fieldName == "ServerResponse" {
+		if atRoot && fieldName == "ServerResponse" {
 			continue
 		}
+		destField := dest.Field(cf.dest)
 
 		if fieldName == "NullFields" || fieldName == "ForceSendFields" {
 			err := c.doMetaFields(p.Field(fieldName), destField, src.Field(i), dest, src)
@@ -178,7 +222,9 @@ func (c *copier) doStruct(p Path, dest, src reflect.Value) error {
 			continue
 		}
 
-		c.logS("copyStruct", "path", p, "fieldName", fieldName)
+		if c.logSFn != nil {
+			c.logS("copyStruct", "path", p, "fieldName", fieldName)
+		}
 		if err := c.doValues(p.Field(fieldName), destField, src.Field(i)); err != nil {
 			return err
 		}
@@ -221,7 +267,9 @@ func (c *copier) doMap(p Path, dest, src reflect.Value) error {
 		sv := src.MapIndex(sk)
 		switch {
 		case basicT(dvt) && basicT(svt):
-			c.logS("copyMap basic", "path", p.MapIndex(sk.Interface()), "value", sv.Interface())
+			if c.logSFn != nil {
+				c.logS("copyMap basic", "path", p.MapIndex(sk.Interface()), "value", sv.Interface())
+			}
 			newMap.SetMapIndex(sk, sv)
 		case svt.Kind() == reflect.Struct:
 			pdv := reflect.New(dvt)
@@ -299,7 +347,7 @@ func (c *copier) doMetaFields(p Path, destField, srcField, destStruct, srcStruct
 			// field that didn't exist on the dest
 			// version.
 			c.missing = append(c.missing, missingFieldOnCopy{
-				Path:  p.Field(fn),
+				Path:  clonePath(p.Field(fn)),
 				Value: srcField.Interface(),
 			})
 			c.logS("copyMetaFields missing field", "path", p, "fieldName", fn)
@@ -316,3 +364,6 @@ func (c *copier) doMetaFields(p Path, destField, srcField, destStruct, srcStruct
 
 	return nil
 }
+
+// clonePath returns a copy of p that does not share the underlying array.
+func clonePath(p Path) Path { return append(Path{}, p...) }
@@ -21,6 +21,8 @@ import (
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
+	alpha "google.golang.org/api/compute/v0.alpha"
+	"google.golang.org/api/compute/v1"
 )
 
 func testCopier(t *testing.T) *copier {
@@ -554,3 +556,43 @@ func TestCopyMetaFields(t *testing.T) {
 		})
 	}
 }
+
+func TestCopyFields(t *testing.T) {
+	type a struct{ X, Y, Z int }
+	type b struct{ Z, X int }
+
+	got := copyFields(reflect.TypeOf(b{}), reflect.TypeOf(a{}))
+	want := []copyField{
+		{name: "X", src: 0, dest: 1},
+		{name: "Y", src: 1, dest: -1},
+		{name: "Z", src: 2, dest: 0},
+	}
+	if diff := cmp.Diff(got, want, cmp.AllowUnexported(copyField{})); diff != "" {
+		t.Errorf("copyFields(): -got,+want: %s", diff)
+	}
+	// Cached.
+	if again := copyFields(reflect.TypeOf(b{}), reflect.TypeOf(a{})); &again[0] != &got[0] {
+		t.Errorf("copyFields() was not cached")
+	}
+}
+
+func BenchmarkCopy(b *testing.B) {
+	src := reflect.ValueOf(benchBackendService())
+
+	for _, tc := range []struct {
+		name    string
+		newDest func() reflect.Value
+	}{
+		{name: "GA to GA", newDest: func() reflect.Value { return reflect.ValueOf(&compute.BackendService{}) }},
+		{name: "GA to Alpha", newDest: func() reflect.Value { return reflect.ValueOf(&alpha.BackendService{}) }},
+	} {
+		b.Run(tc.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if err := newCopier().do(tc.newDest(), src); err != nil {
+					b.Fatalf("copier.do() = %v", err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,174 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"reflect"
+	"strings"
+)
+
+// Unclassified returns the paths of the fields in type t that are not covered
+// by an explicit trait, i.e. neither the field nor any of its parents have a
+// trait. Structs (including the elements of slices and maps) are walked into;
+// the returned paths are the leaf fields. The NullFields and ForceSendFields
+// metafields are skipped.
+//
+// This is used to check that the traits for a resource are complete (see
+// Ordinary()).
+func (dt *FieldTraits) Unclassified(t reflect.Type) []Path {
+	var ret []Path
+	dt.unclassified(Path{}, t, map[reflect.Type]bool{}, &ret)
+	return ret
+}
+
+func (dt *FieldTraits) unclassified(p Path, t reflect.Type, seen map[reflect.Type]bool, ret *[]Path) {
+	for _, f := range dt.fields {
+		if p.HasPrefix(f.path) {
+			return
+		}
+	}
+	switch t.Kind() {
+	case reflect.Pointer:
+		dt.unclassified(p.Pointer(), t.Elem(), seen, ret)
+		return
+	case reflect.Slice:
+		if hasStruct(t.Elem()) {
+			dt.unclassified(p.AnySliceIndex(), t.Elem(), seen, ret)
+			return
+		}
+	case reflect.Map:
+		if hasStruct(t.Elem()) {
+			dt.unclassified(p.AnyMapIndex(), t.Elem(), seen, ret)
+			return
+		}
+	case reflect.Struct:
+		// Recursive types are reported at the point of recursion.
+		if seen[t] {
+			break
+		}
+		seen[t] = true
+		defer delete(seen, t)
+		for i := 0; i < t.NumField(); i++ {
+			sf := t.Field(i)
+			if !sf.IsExported() || sf.Name == nullFieldsName || sf.Name == forceSendFieldsName {
+				continue
+			}
+			dt.unclassified(p.Field(sf.Name), sf.Type, seen, ret)
+		}
+		return
+	}
+	// p shares its backing array with the siblings of the field.
+	*ret = append(*ret, append(Path{}, p...))
+}
+
+// hasStruct returns true if t is a struct or a pointer to a struct.
+func hasStruct(t reflect.Type) bool {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t.Kind() == reflect.Struct
+}
+
+// UnclassifiedSet returns the Unclassified() fields that have a non-zero
+// value in v, where v is a pointer to the resource. This is used to detect
+// fields returned by the API that are not known to the traits.
+func (dt *FieldTraits) UnclassifiedSet(v any) []Path {
+	rv := reflect.ValueOf(v)
+	var ret []Path
+	for _, p := range dt.Unclassified(rv.Type()) {
+		if anyNonZero(rv, p) {
+			ret = append(ret, p)
+		}
+	}
+	return ret
+}
+
+// anyNonZero returns true if any of the values matching p in v are
+// non-zero. p may contain wildcard slice and map indices.
+func anyNonZero(v reflect.Value, p Path) bool {
+	if len(p) == 0 {
+		return !v.IsZero()
+	}
+	switch elem := p[0]; {
+	case elem == string(pathPointer):
+		if v.Kind() != reflect.Pointer || v.IsNil() {
+			return false
+		}
+		return anyNonZero(v.Elem(), p[1:])
+	case elem[0] == pathField:
+		if v.Kind() != reflect.Struct {
+			return false
+		}
+		f := v.FieldByName(elem[1:])
+		return f.IsValid() && anyNonZero(f, p[1:])
+	case elem == anySliceIndex:
+		if v.Kind() != reflect.Slice {
+			return false
+		}
+		for i := 0; i < v.Len(); i++ {
+			if anyNonZero(v.Index(i), p[1:]) {
+				return true
+			}
+		}
+	case elem == anyMapIndex:
+		if v.Kind() != reflect.Map {
+			return false
+		}
+		for it := v.MapRange(); it.Next(); {
+			if anyNonZero(it.Value(), p[1:]) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ClassifiedFields returns the JSON names of the top-level fields of t that
+// have a trait on or below them, in the order of the fields in t. t is the
+// type of the resource (or a pointer to it). This is used to always request
+// the fields the library depends on (e.g. Fingerprint) in a partial response.
+func (dt *FieldTraits) ClassifiedFields(t reflect.Type) []string {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+	names := map[string]bool{}
+	for _, f := range dt.fields {
+		p := f.path
+		if len(p) > 0 && p[0] == string(pathPointer) {
+			p = p[1:]
+		}
+		if len(p) > 0 && p[0][0] == pathField {
+			names[p[0][1:]] = true
+		}
+	}
+	var ret []string
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if !names[sf.Name] {
+			continue
+		}
+		name, _, _ := strings.Cut(sf.Tag.Get("json"), ",")
+		if name == "" || name == "-" {
+			continue
+		}
+		ret = append(ret, name)
+	}
+	return ret
+}
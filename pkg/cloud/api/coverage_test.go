@@ -0,0 +1,193 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestUnclassified(t *testing.T) {
+	t.Parallel()
+
+	type sti struct {
+		I               int
+		S               string
+		NullFields      []string
+		ForceSendFields []string
+	}
+	type st struct {
+		I               int
+		St              sti
+		PSt             *sti
+		LSt             []*sti
+		MSt             map[string]sti
+		LS              []string
+		ServerResponse  struct{ Code int }
+		NullFields      []string
+		ForceSendFields []string
+		private         int
+	}
+	typ := reflect.TypeOf(&st{})
+
+	for _, tc := range []struct {
+		name   string
+		traits func(*FieldTraits)
+		want   []string
+	}{
+		{
+			name: "no traits",
+			want: []string{
+				"*.I", "*.St.I", "*.St.S", "*.PSt*.I", "*.PSt*.S",
+				"*.LSt!#*.I", "*.LSt!#*.S", "*.MSt:#.I", "*.MSt:#.S", "*.LS",
+			},
+		},
+		{
+			name: "all classified",
+			traits: func(dt *FieldTraits) {
+				dt.OutputOnly(Path{}.Pointer().Field("I"))
+				dt.NonZeroValue(Path{}.Pointer().Field("St").Field("I"))
+				dt.Ordinary(Path{}.Pointer().Field("St"))
+				dt.Ordinary(Path{}.Pointer().Field("PSt"))
+				dt.System(Path{}.Pointer().Field("LSt").AnySliceIndex().Pointer().Field("I"))
+				dt.Ordinary(Path{}.Pointer().Field("LSt").AnySliceIndex().Pointer().Field("S"))
+				dt.Ordinary(Path{}.Pointer().Field("MSt"))
+				dt.Ordinary(Path{}.Pointer().Field("LS"))
+			},
+		},
+		{
+			name: "partial",
+			traits: func(dt *FieldTraits) {
+				dt.OutputOnly(Path{}.Pointer().Field("I"))
+				dt.NonZeroValue(Path{}.Pointer().Field("St").Field("I"))
+				dt.Ordinary(Path{}.Pointer().Field("PSt"))
+				dt.Ordinary(Path{}.Pointer().Field("LSt"))
+				dt.Ordinary(Path{}.Pointer().Field("MSt"))
+			},
+			want: []string{"*.St.S", "*.LS"},
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			dt := NewFieldTraits()
+			if tc.traits != nil {
+				tc.traits(dt)
+			}
+			var got []string
+			for _, p := range dt.Unclassified(typ) {
+				got = append(got, p.String())
+			}
+			if diff := cmp.Diff(got, tc.want); diff != "" {
+				t.Errorf("Unclassified(): -got,+want: %s", diff)
+			}
+		})
+	}
+}
+
+func TestUnclassifiedRecursive(t *testing.T) {
+	t.Parallel()
+
+	type node struct {
+		Name     string
+		Children []*node
+	}
+	var got []string
+	for _, p := range NewFieldTraits().Unclassified(reflect.TypeOf(&node{})) {
+		got = append(got, p.String())
+	}
+	want := []string{"*.Name", "*.Children!#*"}
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Errorf("Unclassified(): -got,+want: %s", diff)
+	}
+}
+
+func TestUnclassifiedSet(t *testing.T) {
+	t.Parallel()
+
+	type sti struct {
+		I int
+		S string
+	}
+	type st struct {
+		I   int
+		PSt *sti
+		LSt []*sti
+		MSt map[string]sti
+	}
+	dt := NewFieldTraits()
+	dt.OutputOnly(Path{}.Pointer().Field("I"))
+
+	for _, tc := range []struct {
+		name string
+		v    *st
+		want []string
+	}{
+		{name: "zero", v: &st{}},
+		{name: "classified", v: &st{I: 1}},
+		{
+			name: "unclassified",
+			v: &st{
+				PSt: &sti{S: "a"},
+				LSt: []*sti{nil, {I: 1}},
+				MSt: map[string]sti{"a": {}, "b": {S: "b"}},
+			},
+			want: []string{"*.PSt*.S", "*.LSt!#*.I", "*.MSt:#.S"},
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			var got []string
+			for _, p := range dt.UnclassifiedSet(tc.v) {
+				got = append(got, p.String())
+			}
+			if diff := cmp.Diff(got, tc.want); diff != "" {
+				t.Errorf("UnclassifiedSet(): -got,+want: %s", diff)
+			}
+		})
+	}
+}
+
+func TestClassifiedFields(t *testing.T) {
+	t.Parallel()
+
+	type sti struct {
+		I int `json:"i,omitempty"`
+	}
+	type st struct {
+		Name        string `json:"name,omitempty"`
+		Fingerprint string `json:"fingerprint,omitempty"`
+		PSt         *sti   `json:"pSt,omitempty"`
+		Other       int    `json:"other,omitempty"`
+		NoTag       int
+	}
+
+	dt := &FieldTraits{}
+	dt.System(Path{}.Pointer().Field("Fingerprint"))
+	dt.OutputOnly(Path{}.Pointer().Field("PSt").Pointer().Field("I"))
+	dt.Ordinary(Path{}.Pointer().Field("Name"))
+	dt.Ordinary(Path{}.Pointer().Field("NoTag"))
+
+	got := dt.ClassifiedFields(reflect.TypeOf(&st{}))
+	want := []string{"name", "fingerprint", "pSt"}
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Errorf("ClassifiedFields() = %v, want %v; -got,+want: %s", got, want, diff)
+	}
+}
@@ -19,6 +19,8 @@ package api
 import (
 	"fmt"
 	"reflect"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
 )
 
 // TODO: how to diff force send fields? null fields? and zero values?
@@ -34,7 +36,8 @@ func diff[T any](a, b *T, trait *FieldTraits) (*DiffResult, error) {
 		traits: trait,
 		result: &DiffResult{},
 	}
-	err := d.do(Path{}, reflect.ValueOf(a), reflect.ValueOf(b))
+	av := reflect.ValueOf(a)
+	err := d.do(newDiffPath(), d.traits.compile(av.Type()).cursor(Path{}), av, reflect.ValueOf(b))
 	if err != nil {
 		return nil, err
 	}
@@ -46,7 +49,8 @@ func diffStructs[A any, B any](a *A, b *B) (*DiffResult, error) {
 		traits: &FieldTraits{},
 		result: &DiffResult{},
 	}
-	err := d.do(Path{}, reflect.ValueOf(a), reflect.ValueOf(b))
+	av := reflect.ValueOf(a)
+	err := d.do(newDiffPath(), d.traits.compile(av.Type()).cursor(Path{}), av, reflect.ValueOf(b))
 	if err != nil {
 		return nil, err
 	}
@@ -111,12 +115,19 @@ type DiffItem struct {
 	B     any
 }
 
+// newDiffPath returns the root Path for the differ. The Path has spare
+// capacity so extending it does not allocate for every field. This is safe as
+// the differ copies the Path when recording a DiffItem and does not retain it
+// otherwise.
+func newDiffPath() Path { return make(Path, 0, 16) }
+
 type differ[T any] struct {
 	traits *FieldTraits
 	result *DiffResult
 }
 
-func (d *differ[T]) do(p Path, av, bv reflect.Value) error {
+// do diffs av and bv. tc is the position of p in the compiled traits.
+func (d *differ[T]) do(p Path, tc traitCursor, av, bv reflect.Value) error {
 	// cmpZero applies to pointer, slice and map values. Returns true if no
 	// further diff'ing is required for the values.
 	cmpZero := func() bool {
@@ -135,6 +146,12 @@ func (d *differ[T]) do(p Path, av, bv reflect.Value) error {
 
 	switch {
 	case isBasicV(av):
+		if av.Kind() == reflect.String && tc.fieldType(p) == FieldTypeDescription {
+			if cloud.DescriptionText(av.String()) != cloud.DescriptionText(bv.String()) {
+				d.result.add(DiffItemDifferent, p, av, bv)
+			}
+			return nil
+		}
 		if !av.Equal(bv) {
 			d.result.add(DiffItemDifferent, p, av, bv)
 		}
@@ -144,9 +161,10 @@ func (d *differ[T]) do(p Path, av, bv reflect.Value) error {
 		if cmpZero() {
 			return nil
 		}
-		return d.do(p.Pointer(), av.Elem(), bv.Elem())
+		return d.do(p.Pointer(), tc.elem(), av.Elem(), bv.Elem())
 
 	case av.Type().Kind() == reflect.Struct:
+		sameType := av.Type() == bv.Type()
 		for i := 0; i < av.NumField(); i++ {
 			afv := av.Field(i)
 			aft := av.Type().Field(i)
@@ -156,17 +174,23 @@ func (d *differ[T]) do(p Path, av, bv reflect.Value) error {
 			}
 
 			fp := p.Field(aft.Name)
-			switch d.traits.fieldType(fp) {
+			ftc := tc.field(i)
+			switch ftc.fieldType(fp) {
 			case FieldTypeOutputOnly, FieldTypeSystem:
 				continue
 			}
 
-			bfv := bv.FieldByName(aft.Name)
+			var bfv reflect.Value
+			if sameType {
+				bfv = bv.Field(i)
+			} else {
+				bfv = bv.FieldByName(aft.Name)
+			}
 			if !bfv.IsValid() {
 				d.result.add(DiffItemOnlyInA, p, av, bv)
 				continue
 			}
-			if err := d.do(fp, afv, bfv); err != nil {
+			if err := d.do(fp, ftc, afv, bfv); err != nil {
 				return fmt.Errorf("differ struct %p: %w", fp, err)
 			}
 		}
@@ -187,7 +211,7 @@ func (d *differ[T]) do(p Path, av, bv reflect.Value) error {
 			asv := av.Index(i)
 			bsv := bv.Index(i)
 			sp := p.Index(i)
-			if err := d.do(sp, asv, bsv); err != nil {
+			if err := d.do(sp, tc.elem(), asv, bsv); err != nil {
 				return fmt.Errorf("differ slice %p: %w", sp, err)
 			}
 		}
@@ -213,7 +237,7 @@ func (d *differ[T]) do(p Path, av, bv reflect.Value) error {
 			if !bmv.IsValid() {
 				d.result.add(DiffItemDifferent, mp, amv, bmv)
 			}
-			if err := d.do(mp, amv, bmv); err != nil {
+			if err := d.do(mp, tc.elem(), amv, bmv); err != nil {
 				return fmt.Errorf("differ map %p: %w", mp, err)
 			}
 		}
@@ -17,9 +17,13 @@ limitations under the License.
 package api
 
 import (
+	"fmt"
 	"testing"
+	"time"
 
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
 	"github.com/kr/pretty"
+	"google.golang.org/api/compute/v1"
 )
 
 func TestDiff(t *testing.T) {
@@ -292,3 +296,112 @@ func TestDiffForStructWithUnexportedFields(t *testing.T) {
 		})
 	}
 }
+
+func TestDiffDescription(t *testing.T) {
+	t.Parallel()
+
+	type st struct {
+		Description string
+		Name        string
+	}
+	traits := &FieldTraits{}
+	traits.Description(Path{}.Pointer().Field("Description"))
+
+	owned := func(text, owner string) string {
+		d := cloud.Description{Text: text, Owner: owner, Created: time.Unix(1000, 0)}
+		return d.String()
+	}
+
+	for _, tc := range []struct {
+		name     string
+		a        st
+		b        st
+		wantDiff bool
+	}{
+		{
+			name: "same metadata",
+			a:    st{Description: owned("abc", "x")},
+			b:    st{Description: owned("abc", "x")},
+		},
+		{
+			name: "metadata differs",
+			a:    st{Description: owned("abc", "x")},
+			b:    st{Description: owned("abc", "y")},
+		},
+		{
+			name: "metadata only in one",
+			a:    st{Description: "abc"},
+			b:    st{Description: owned("abc", "y")},
+		},
+		{
+			name: "values differ",
+			a:    st{Description: (&cloud.Description{Text: "abc", Values: map[string]string{"v": "1"}}).String()},
+			b:    st{Description: (&cloud.Description{Text: "abc", Values: map[string]string{"v": "2"}}).String()},
+		},
+		{
+			name:     "text differs",
+			a:        st{Description: owned("abc", "x")},
+			b:        st{Description: owned("def", "x")},
+			wantDiff: true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			r, err := diff(&tc.a, &tc.b, traits)
+			if err != nil {
+				t.Fatalf("diff() = %v, want nil", err)
+			}
+			if r.HasDiff() != tc.wantDiff {
+				t.Errorf("HasDiff = %t, want %t. diff = %s", r.HasDiff(), tc.wantDiff, pretty.Sprint(r))
+			}
+		})
+	}
+
+	// Without the trait, the metadata is compared.
+	a, b := st{Description: owned("abc", "x")}, st{Description: owned("abc", "y")}
+	r, err := diff(&a, &b, nil)
+	if err != nil {
+		t.Fatalf("diff() = %v, want nil", err)
+	}
+	if !r.HasDiff() {
+		t.Errorf("HasDiff = false, want true")
+	}
+}
+
+// benchBackendService returns a BackendService with a realistic number of
+// fields set for benchmarks.
+func benchBackendService() *compute.BackendService {
+	bs := &compute.BackendService{
+		Name:                "bs",
+		Description:         "benchmark",
+		LoadBalancingScheme: "EXTERNAL_MANAGED",
+		Protocol:            "HTTP",
+		PortName:            "http",
+		TimeoutSec:          30,
+		HealthChecks:        []string{"https://www.googleapis.com/compute/v1/projects/proj/global/healthChecks/hc"},
+		CdnPolicy:           &compute.BackendServiceCdnPolicy{CacheMode: "CACHE_ALL_STATIC", DefaultTtl: 3600},
+		LogConfig:           &compute.BackendServiceLogConfig{Enable: true, SampleRate: 0.5},
+	}
+	for i := 0; i < 4; i++ {
+		bs.Backends = append(bs.Backends, &compute.Backend{
+			Group:              fmt.Sprintf("https://www.googleapis.com/compute/v1/projects/proj/zones/us-central1-a/networkEndpointGroups/neg-%d", i),
+			BalancingMode:      "RATE",
+			MaxRatePerEndpoint: 100,
+			CapacityScaler:     1,
+		})
+	}
+	return bs
+}
+
+func BenchmarkDiff(b *testing.B) {
+	x, y := benchBackendService(), benchBackendService()
+	y.Backends[3].CapacityScaler = 0.5
+	traits := NewFieldTraits()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := diff(x, y, traits); err != nil {
+			b.Fatalf("diff() = %v", err)
+		}
+	}
+}
@@ -0,0 +1,215 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+)
+
+// FieldAccessor is implemented by Resources. It is a type-erased interface
+// for reading and writing the top-level fields of a resource generically
+// (e.g. by the planner).
+type FieldAccessor interface {
+	// Field returns the value of the top-level field.
+	Field(name string) (any, error)
+	// WithField returns a copy of the resource with the top-level field set
+	// to v.
+	WithField(name string, v any) (any, error)
+	// SpecHash returns a hash of the fields in the resource that are sent to
+	// the server. OutputOnly and System fields and the top-level fields in
+	// exclude are not part of the hash. Resources of the same type and
+	// Version with no diff have the same hash.
+	SpecHash(exclude ...string) (string, error)
+	// SpecJSON returns the JSON of the resource without the OutputOnly and
+	// System fields. The keys are sorted.
+	SpecJSON() ([]byte, error)
+}
+
+var _ FieldAccessor = (*resource[struct{}, struct{}, struct{}])(nil)
+
+// typed returns the resource as the concrete type for its Version.
+func (obj *resource[GA, Alpha, Beta]) typed() (any, error) {
+	switch obj.Version() {
+	case meta.VersionGA:
+		return obj.ToGA()
+	case meta.VersionAlpha:
+		return obj.ToAlpha()
+	case meta.VersionBeta:
+		return obj.ToBeta()
+	}
+	return nil, fmt.Errorf("invalid version %q", obj.Version())
+}
+
+// Field implements FieldAccessor.
+func (obj *resource[GA, Alpha, Beta]) Field(name string) (any, error) {
+	x, err := obj.typed()
+	if err != nil {
+		return nil, fmt.Errorf("Field: %w", err)
+	}
+	f := reflect.ValueOf(x).Elem().FieldByName(name)
+	if !f.IsValid() {
+		return nil, fmt.Errorf("Field: no field %q in %T", name, x)
+	}
+	return f.Interface(), nil
+}
+
+// WithField implements FieldAccessor.
+func (obj *resource[GA, Alpha, Beta]) WithField(name string, v any) (any, error) {
+	m := NewResource[GA, Alpha, Beta](obj.ResourceID(), obj.x.typeTrait)
+	var err error
+	switch obj.Version() {
+	case meta.VersionGA:
+		x, _ := obj.ToGA()
+		err = setField(m.Set, x, name, v)
+	case meta.VersionAlpha:
+		x, _ := obj.ToAlpha()
+		err = setField(m.SetAlpha, x, name, v)
+	case meta.VersionBeta:
+		x, _ := obj.ToBeta()
+		err = setField(m.SetBeta, x, name, v)
+	default:
+		err = fmt.Errorf("invalid version %q", obj.Version())
+	}
+	if err != nil {
+		return nil, fmt.Errorf("WithField: %w", err)
+	}
+	return m.Freeze()
+}
+
+// setField calls set with a copy of x that has the field set to v.
+func setField[T any](set func(*T) error, x *T, name string, v any) error {
+	cp := *x
+	f := reflect.ValueOf(&cp).Elem().FieldByName(name)
+	if !f.IsValid() {
+		return fmt.Errorf("no field %q in %T", name, cp)
+	}
+	vv := reflect.ValueOf(v)
+	if !vv.IsValid() {
+		vv = reflect.Zero(f.Type())
+	}
+	if !vv.Type().AssignableTo(f.Type()) {
+		return fmt.Errorf("cannot assign %T to field %q in %T", v, name, cp)
+	}
+	f.Set(vv)
+	return set(&cp)
+}
+
+// SpecHash implements FieldAccessor.
+func (obj *resource[GA, Alpha, Beta]) SpecHash(exclude ...string) (string, error) {
+	data, err := obj.specJSON(exclude...)
+	if err != nil {
+		return "", fmt.Errorf("SpecHash: %w", err)
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:16]), nil
+}
+
+// SpecJSON implements FieldAccessor.
+func (obj *resource[GA, Alpha, Beta]) SpecJSON() ([]byte, error) {
+	data, err := obj.specJSON()
+	if err != nil {
+		return nil, fmt.Errorf("SpecJSON: %w", err)
+	}
+	return data, nil
+}
+
+// specJSON returns the JSON sent to the server without the OutputOnly and
+// System fields and the top-level fields in exclude.
+func (obj *resource[GA, Alpha, Beta]) specJSON(exclude ...string) ([]byte, error) {
+	x, err := obj.typed()
+	if err != nil {
+		return nil, err
+	}
+	// The JSON sent to the server omits the metafields and empty values.
+	data, err := json.Marshal(x)
+	if err != nil {
+		return nil, err
+	}
+	var m any
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, err
+	}
+
+	t := reflect.TypeOf(x)
+	traits := obj.x.typeTrait.FieldTraits(obj.Version())
+	for _, f := range traits.fields {
+		switch f.fType {
+		case FieldTypeOutputOnly, FieldTypeSystem:
+			deleteJSONPath(m, t, f.path)
+		}
+	}
+	for _, name := range exclude {
+		deleteJSONPath(m, t, Path{}.Pointer().Field(name))
+	}
+
+	// encoding/json sorts the keys of maps so the encoding is stable.
+	return json.Marshal(m)
+}
+
+// deleteJSONPath deletes the value at p from m, the JSON decoding of a value
+// of type t. Paths that do not exist in m are ignored.
+func deleteJSONPath(m any, t reflect.Type, p Path) {
+	if len(p) == 0 {
+		return
+	}
+	elem := p[0]
+	switch {
+	case elem[0] == pathPointer:
+		if t.Kind() == reflect.Pointer {
+			deleteJSONPath(m, t.Elem(), p[1:])
+		}
+	case elem[0] == pathField:
+		obj, ok := m.(map[string]any)
+		if !ok || t.Kind() != reflect.Struct {
+			return
+		}
+		sf, ok := t.FieldByName(elem[1:])
+		if !ok {
+			return
+		}
+		name, _, _ := strings.Cut(sf.Tag.Get("json"), ",")
+		if name == "" || name == "-" {
+			return
+		}
+		if len(p) == 1 {
+			delete(obj, name)
+			return
+		}
+		if v, ok := obj[name]; ok {
+			deleteJSONPath(v, sf.Type, p[1:])
+		}
+	case elem == anySliceIndex:
+		if l, ok := m.([]any); ok && t.Kind() == reflect.Slice {
+			for _, v := range l {
+				deleteJSONPath(v, t.Elem(), p[1:])
+			}
+		}
+	case elem == anyMapIndex:
+		if mm, ok := m.(map[string]any); ok && t.Kind() == reflect.Map {
+			for _, v := range mm {
+				deleteJSONPath(v, t.Elem(), p[1:])
+			}
+		}
+	}
+}
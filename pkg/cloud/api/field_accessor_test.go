@@ -0,0 +1,114 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+)
+
+func TestFieldAccessor(t *testing.T) {
+	type inner struct {
+		A               string   `json:"a,omitempty"`
+		Id              string   `json:"id,omitempty"`
+		NullFields      []string `json:"-"`
+		ForceSendFields []string `json:"-"`
+	}
+	type st struct {
+		Name            string            `json:"name,omitempty"`
+		Description     string            `json:"description,omitempty"`
+		Id              uint64            `json:"id,omitempty,string"`
+		Labels          map[string]string `json:"labels,omitempty"`
+		Items           []*inner          `json:"items,omitempty"`
+		NullFields      []string          `json:"-"`
+		ForceSendFields []string          `json:"-"`
+	}
+	trait := &TypeTraitFuncs[st, st, st]{
+		FieldTraitsF: func(meta.Version) *FieldTraits {
+			dt := &FieldTraits{}
+			dt.OutputOnly(Path{}.Pointer().Field("Id"))
+			dt.OutputOnly(Path{}.Pointer().Field("Items").AnySliceIndex().Pointer().Field("Id"))
+			return dt
+		},
+	}
+	newRes := func(x *st) Resource[st, st, st] {
+		m := newTestResource[st, st, st](trait)
+		if err := m.Set(x); err != nil {
+			t.Fatalf("Set() = %v", err)
+		}
+		r, err := m.Freeze()
+		if err != nil {
+			t.Fatalf("Freeze() = %v", err)
+		}
+		return r
+	}
+	hash := func(r Resource[st, st, st], exclude ...string) string {
+		h, err := r.(FieldAccessor).SpecHash(exclude...)
+		if err != nil {
+			t.Fatalf("SpecHash() = %v", err)
+		}
+		return h
+	}
+
+	base := newRes(&st{Name: "obj-1", Description: "d", Items: []*inner{{A: "a"}}})
+
+	// OutputOnly fields are not part of the hash.
+	server := newRes(&st{Name: "obj-1", Description: "d", Id: 123, Items: []*inner{{A: "a", Id: "x"}}})
+	if hash(base) != hash(server) {
+		t.Errorf("SpecHash() differs for OutputOnly fields")
+	}
+	changed := newRes(&st{Name: "obj-1", Description: "d", Items: []*inner{{A: "b"}}})
+	if hash(base) == hash(changed) {
+		t.Errorf("SpecHash() is the same for different resources")
+	}
+	desc := newRes(&st{Name: "obj-1", Description: "other", Items: []*inner{{A: "a"}}})
+	if hash(base) == hash(desc) || hash(base, "Description") != hash(desc, "Description") {
+		t.Errorf("SpecHash(Description) does not exclude the field")
+	}
+
+	data, err := server.(FieldAccessor).SpecJSON()
+	if err != nil {
+		t.Fatalf("SpecJSON() = %v", err)
+	}
+	if want := `{"description":"d","items":[{"a":"a"}],"name":"obj-1"}`; string(data) != want {
+		t.Errorf("SpecJSON() = %s, want %s", data, want)
+	}
+
+	v, err := base.(FieldAccessor).Field("Description")
+	if err != nil || v != "d" {
+		t.Errorf("Field(Description) = %v, %v; want d, nil", v, err)
+	}
+	if _, err := base.(FieldAccessor).Field("Invalid"); err == nil {
+		t.Error("Field(Invalid) = nil, want error")
+	}
+
+	res, err := base.(FieldAccessor).WithField("Labels", map[string]string{"k": "v"})
+	if err != nil {
+		t.Fatalf("WithField() = %v", err)
+	}
+	obj, _ := res.(Resource[st, st, st]).ToGA()
+	if obj.Labels["k"] != "v" || obj.Description != "d" {
+		t.Errorf("WithField() = %+v, want Labels set", obj)
+	}
+	if obj, _ := base.ToGA(); obj.Labels != nil {
+		t.Errorf("base.Labels = %v, want unchanged", obj.Labels)
+	}
+	if _, err := base.(FieldAccessor).WithField("Description", 1); err == nil {
+		t.Error("WithField(Description, 1) = nil, want error")
+	}
+}
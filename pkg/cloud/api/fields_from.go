@@ -0,0 +1,112 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+)
+
+// FieldCopier is implemented by Resources. It is a type-erased interface
+// for use by code that handles resources generically (e.g. the planner).
+type FieldCopier interface {
+	// WithFieldsFrom returns a copy of the resource with the top-level
+	// fields replaced by the values in src. src must be a Resource of
+	// the same type and Version.
+	WithFieldsFrom(src any, fields []string) (any, error)
+}
+
+var _ FieldCopier = (*resource[struct{}, struct{}, struct{}])(nil)
+
+// TopLevelField returns the name of the top-level struct field referenced by
+// the Path, e.g. "Backends" for "*.Backends!0.Group". Returns "" if the Path
+// does not reference a field.
+func (p Path) TopLevelField() string {
+	for _, x := range p {
+		switch x[0] {
+		case pathPointer:
+			continue
+		case pathField:
+			return x[1:]
+		}
+		return ""
+	}
+	return ""
+}
+
+// WithFieldsFrom implements FieldCopier.
+func (obj *resource[GA, Alpha, Beta]) WithFieldsFrom(src any, fields []string) (any, error) {
+	other, ok := src.(Resource[GA, Alpha, Beta])
+	if !ok {
+		return nil, fmt.Errorf("WithFieldsFrom: invalid type %T", src)
+	}
+	if obj.Version() != other.Version() {
+		return nil, fmt.Errorf("WithFieldsFrom: version mismatch (%s, %s)", obj.Version(), other.Version())
+	}
+
+	m := NewResource[GA, Alpha, Beta](obj.ResourceID(), obj.x.typeTrait)
+	var err error
+	switch obj.Version() {
+	case meta.VersionGA:
+		dst, _ := obj.ToGA()
+		s, _ := other.ToGA()
+		err = setFields(m.Set, dst, s, fields)
+	case meta.VersionAlpha:
+		dst, _ := obj.ToAlpha()
+		s, _ := other.ToAlpha()
+		err = setFields(m.SetAlpha, dst, s, fields)
+	case meta.VersionBeta:
+		dst, _ := obj.ToBeta()
+		s, _ := other.ToBeta()
+		err = setFields(m.SetBeta, dst, s, fields)
+	default:
+		err = fmt.Errorf("invalid version %q", obj.Version())
+	}
+	if err != nil {
+		return nil, fmt.Errorf("WithFieldsFrom: %w", err)
+	}
+	return m.Freeze()
+}
+
+// setFields calls set with a copy of dst that has the fields from src.
+func setFields[T any](set func(*T) error, dst, src *T, fields []string) error {
+	cp := *dst
+	dv := reflect.ValueOf(&cp).Elem()
+	sv := reflect.ValueOf(src).Elem()
+	copied := map[string]bool{}
+	for _, f := range fields {
+		df := dv.FieldByName(f)
+		if !df.IsValid() {
+			return fmt.Errorf("no field %q in %T", f, cp)
+		}
+		df.Set(sv.FieldByName(f))
+		copied[f] = true
+	}
+	// The copied fields are no longer explicitly nulled.
+	if nf := dv.FieldByName("NullFields"); nf.IsValid() {
+		var keep []string
+		for _, f := range nf.Interface().([]string) {
+			if !copied[f] {
+				keep = append(keep, f)
+			}
+		}
+		nf.Set(reflect.ValueOf(keep))
+	}
+	return set(&cp)
+}
@@ -0,0 +1,84 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestPathTopLevelField(t *testing.T) {
+	for _, tc := range []struct {
+		p    Path
+		want string
+	}{
+		{p: Path{}},
+		{p: Path{}.Pointer()},
+		{p: Path{}.Pointer().Field("A"), want: "A"},
+		{p: Path{}.Field("A").Index(0).Field("B"), want: "A"},
+		{p: Path{}.Index(0).Field("B")},
+	} {
+		if got := tc.p.TopLevelField(); got != tc.want {
+			t.Errorf("%v.TopLevelField() = %q, want %q", tc.p, got, tc.want)
+		}
+	}
+}
+
+func TestWithFieldsFrom(t *testing.T) {
+	type st struct {
+		Name            string
+		I               int
+		S               string
+		NullFields      []string
+		ForceSendFields []string
+	}
+	newRes := func(x *st) Resource[st, st, st] {
+		m := newTestResource[st, st, st](&testTrait[st, st, st]{})
+		if err := m.Set(x); err != nil {
+			t.Fatalf("Set() = %v", err)
+		}
+		r, err := m.Freeze()
+		if err != nil {
+			t.Fatalf("Freeze() = %v", err)
+		}
+		return r
+	}
+
+	want := newRes(&st{Name: "obj-1", I: 1, S: "want", NullFields: []string{"I"}})
+	got := newRes(&st{Name: "obj-1", I: 2, S: "got"})
+
+	res, err := want.(FieldCopier).WithFieldsFrom(got, []string{"I"})
+	if err != nil {
+		t.Fatalf("WithFieldsFrom() = %v, want nil", err)
+	}
+	obj, _ := res.(Resource[st, st, st]).ToGA()
+	if diff := cmp.Diff(obj, &st{Name: "obj-1", I: 2, S: "want"}); diff != "" {
+		t.Errorf("WithFieldsFrom(): -got,+want: %s", diff)
+	}
+	// want is unchanged.
+	if obj, _ := want.ToGA(); obj.I != 1 {
+		t.Errorf("want.I = %d, want 1", obj.I)
+	}
+
+	if _, err := want.(FieldCopier).WithFieldsFrom(got, []string{"Invalid"}); err == nil {
+		t.Error("WithFieldsFrom(Invalid) = nil, want error")
+	}
+	if _, err := want.(FieldCopier).WithFieldsFrom("x", []string{"I"}); err == nil {
+		t.Error("WithFieldsFrom(string) = nil, want error")
+	}
+}
@@ -158,6 +158,7 @@ func (f *filler) doMap(p Path, v reflect.Value) (bool, error) {
 }
 
 func fillNullAndForceSend(traits *FieldTraits, v reflect.Value) error {
+	ct := traits.compile(v.Type())
 	acc := newAcceptorFuncs()
 	acc.onStructF = func(p Path, v reflect.Value) (bool, error) {
 		if p.Equal(Path{}.Pointer().Field("ServerResponse")) {
@@ -167,6 +168,7 @@ func fillNullAndForceSend(traits *FieldTraits, v reflect.Value) error {
 		if err != nil {
 			return false, fmt.Errorf("fillNullAndForceSend: %w", err)
 		}
+		tc := ct.cursor(p)
 
 		nullFields := acc.null()
 		forceSendFields := acc.forceSend()
@@ -176,7 +178,7 @@ func fillNullAndForceSend(traits *FieldTraits, v reflect.Value) error {
 			if ft.Name == "NullFields" || ft.Name == "ForceSendFields" {
 				continue
 			}
-			fType := traits.fieldType(p.Field(ft.Name))
+			fType := tc.field(i).fieldType(p.Field(ft.Name))
 			fv := v.Field(i)
 
 			if fType == FieldTypeNonZeroValue {
@@ -198,8 +200,8 @@ func fillNullAndForceSend(traits *FieldTraits, v reflect.Value) error {
 			d.Set(reflect.ValueOf(sl))
 		}
 
-		set(nullFields, v.FieldByName(nullFieldsName))
-		set(forceSendFields, v.FieldByName(forceSendFieldsName))
+		set(nullFields, acc.nullFields)
+		set(forceSendFields, acc.forceSendFields)
 
 		return true, nil
 	}
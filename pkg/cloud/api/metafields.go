@@ -32,10 +32,10 @@ func newMetafieldAccessor(v reflect.Value) (*metafieldAccessor, error) {
 	}
 	ret := &metafieldAccessor{}
 	if t, ok := v.Type().FieldByName(nullFieldsName); ok && t.Type.Kind() == reflect.Slice && t.Type.Elem().Kind() == reflect.String {
-		ret.nullFields = v.FieldByName(nullFieldsName)
+		ret.nullFields = v.FieldByIndex(t.Index)
 	}
 	if t, ok := v.Type().FieldByName(forceSendFieldsName); ok && t.Type.Kind() == reflect.Slice && t.Type.Elem().Kind() == reflect.String {
-		ret.forceSendFields = v.FieldByName(forceSendFieldsName)
+		ret.forceSendFields = v.FieldByIndex(t.Index)
 	}
 	if !ret.nullFields.IsValid() || !ret.forceSendFields.IsValid() {
 		return nil, fmt.Errorf("struct does not have NullField or ForceSendFields")
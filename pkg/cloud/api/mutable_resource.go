@@ -467,6 +467,28 @@ func (u *mutableResource[GA, Alpha, Beta]) SetBeta(src *Beta) error {
 	return u.postAccess(meta.VersionBeta, postAccessSkipValidation)
 }
 
+// clone returns a deep copy of u.
+func (u *mutableResource[GA, Alpha, Beta]) clone() *mutableResource[GA, Alpha, Beta] {
+	ret := &mutableResource[GA, Alpha, Beta]{
+		copierOptions: u.copierOptions,
+		typeTrait:     u.typeTrait,
+		resourceID:    u.resourceID,
+		errors:        u.errors,
+	}
+	for _, x := range []struct{ dest, src any }{
+		{&ret.ga, &u.ga},
+		{&ret.alpha, &u.alpha},
+		{&ret.beta, &u.beta},
+	} {
+		// Copying between identical types can only fail for types
+		// that do not pass CheckSchema().
+		if err := newCopier().do(reflect.ValueOf(x.dest), reflect.ValueOf(x.src)); err != nil {
+			panic(fmt.Sprintf("clone %T: %v", u, err))
+		}
+	}
+	return ret
+}
+
 func (u *mutableResource[GA, Alpha, Beta]) Freeze() (Resource[GA, Alpha, Beta], error) {
 	ver, err := u.ImpliedVersion()
 	if err != nil {
@@ -21,6 +21,7 @@ import (
 	"reflect"
 	"strconv"
 	"strings"
+	"sync"
 )
 
 // Path specifies a field in nested object. The type of the reference
@@ -45,9 +46,18 @@ const (
 	anyMapIndex = string(pathMapIndex) + "#"
 )
 
+// fieldElems caches the Path elements for struct fields. Paths are built for
+// every field visited in a diff or copy, so this avoids allocating the same
+// strings over and over. The set of field names is bounded by the API types.
+var fieldElems sync.Map // map[string]string
+
 // Field returns the path extended with a struct field reference.
 func (p Path) Field(name string) Path {
-	return append(p, string(pathField)+name)
+	elem, ok := fieldElems.Load(name)
+	if !ok {
+		elem, _ = fieldElems.LoadOrStore(name, string(pathField)+name)
+	}
+	return append(p, elem.(string))
 }
 
 // AnySliceIndex returns a path extended to match any slice index.
@@ -60,8 +70,20 @@ func (p Path) AnyMapIndex() Path {
 	return append(p, anyMapIndex)
 }
 
+// sliceIndexElems are the preallocated Path elements for small slice indices.
+var sliceIndexElems = func() []string {
+	var ret []string
+	for i := 0; i < 64; i++ {
+		ret = append(ret, fmt.Sprintf("%c%d", pathSliceIndex, i))
+	}
+	return ret
+}()
+
 // Index returns the path extended with a slice dereference.
 func (p Path) Index(i int) Path {
+	if i >= 0 && i < len(sliceIndexElems) {
+		return append(p, sliceIndexElems[i])
+	}
 	return append(p, fmt.Sprintf("%c%d", pathSliceIndex, i))
 }
 
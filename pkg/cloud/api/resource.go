@@ -42,8 +42,10 @@ type Resource[GA any, Alpha any, Beta any] interface {
 	// currently supported.
 	Diff(other Resource[GA, Alpha, Beta]) (*DiffResult, error)
 
-	// Clone returns an exact structural copy of this resource.
-	// Clone() Resource[GA, Alpha, Beta] XXX
+	// Clone returns an exact structural copy of this resource. The copy
+	// shares no memory with the original, so changes made through the
+	// pointers returned by ToGA() etc. are not visible in the other.
+	Clone() Resource[GA, Alpha, Beta]
 }
 
 type resource[GA any, Alpha any, Beta any] struct {
@@ -119,11 +121,10 @@ func (obj *resource[GA, Alpha, Beta]) Diff(other Resource[GA, Alpha, Beta]) (*Di
 	return nil, fmt.Errorf("invalid versions (got a.Version=%s, b.Version=%s)", obj.Version(), other.Version())
 }
 
-/*
-func (obj *Resource[GA, Alpha, Beta]) Clone() Resource[GA, Alpha, Beta] {
-	return &Resource[GA, Alpha, Beta]{
-		x:   obj.Clone(),
+// Clone implements Resource.
+func (obj *resource[GA, Alpha, Beta]) Clone() Resource[GA, Alpha, Beta] {
+	return &resource[GA, Alpha, Beta]{
+		x:   obj.x.clone(),
 		ver: obj.ver,
 	}
 }
-*/
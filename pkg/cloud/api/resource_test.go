@@ -1102,3 +1102,59 @@ func TestResourceTypeTrait(t *testing.T) {
 		})
 	}
 }
+
+func TestResourceClone(t *testing.T) {
+	t.Parallel()
+
+	type inner struct {
+		I               int
+		NullFields      []string
+		ForceSendFields []string
+	}
+	type st struct {
+		Name            string
+		LS              []string
+		M               map[string]int
+		P               *inner
+		NullFields      []string
+		ForceSendFields []string
+	}
+
+	mr := NewResource[st, st, st](&cloud.ResourceID{
+		ProjectID: "proj-1",
+		Resource:  "st",
+		Key:       meta.GlobalKey("obj-1"),
+	}, nil)
+	if err := mr.Set(&st{Name: "obj-1", LS: []string{"a"}, M: map[string]int{"a": 1}, P: &inner{I: 1}}); err != nil {
+		t.Fatalf("Set() = %v, want nil", err)
+	}
+	r, err := mr.Freeze()
+	if err != nil {
+		t.Fatalf("Freeze() = %v, want nil", err)
+	}
+
+	c := r.Clone()
+	if c.Version() != r.Version() || !c.ResourceID().Equal(r.ResourceID()) {
+		t.Errorf("Clone() = (%v, %v), want (%v, %v)", c.Version(), c.ResourceID(), r.Version(), r.ResourceID())
+	}
+	want, _ := r.ToGA()
+	got, _ := c.ToGA()
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Errorf("Clone().ToGA(): -got,+want: %s", diff)
+	}
+
+	// Mutating the clone must not change the original.
+	got.LS[0] = "changed"
+	got.M["a"] = 2
+	got.P.I = 2
+	cb, _ := c.ToBeta()
+	cb.Name = "changed"
+
+	orig, _ := r.ToGA()
+	if diff := cmp.Diff(orig, &st{Name: "obj-1", LS: []string{"a"}, M: map[string]int{"a": 1}, P: &inner{I: 1}}); diff != "" {
+		t.Errorf("original changed after mutating clone: -got,+want: %s", diff)
+	}
+	if ob, _ := r.ToBeta(); ob.Name != "obj-1" {
+		t.Errorf("original ToBeta().Name = %q, want obj-1", ob.Name)
+	}
+}
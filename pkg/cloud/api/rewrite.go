@@ -0,0 +1,112 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+)
+
+// Rewriter is implemented by Resources. It is a type-erased interface for
+// tools that transform resources generically (e.g. renaming a resource and
+// the references to it).
+type Rewriter interface {
+	// Rewrite returns a copy of the resource with the ResourceID id and
+	// each string field (including strings in slices and
+	// map[string]string values) replaced by f(path, value). .Name is set
+	// from id.
+	Rewrite(id *cloud.ResourceID, f func(p Path, s string) string) (any, error)
+}
+
+var _ Rewriter = (*resource[struct{}, struct{}, struct{}])(nil)
+
+// Rewrite implements Rewriter.
+func (obj *resource[GA, Alpha, Beta]) Rewrite(id *cloud.ResourceID, f func(p Path, s string) string) (any, error) {
+	m := NewResource[GA, Alpha, Beta](id, obj.x.typeTrait)
+	var err error
+	switch obj.Version() {
+	case meta.VersionGA:
+		src, _ := obj.ToGA()
+		err = rewrite(m.Set, src, id, f)
+	case meta.VersionAlpha:
+		src, _ := obj.ToAlpha()
+		err = rewrite(m.SetAlpha, src, id, f)
+	case meta.VersionBeta:
+		src, _ := obj.ToBeta()
+		err = rewrite(m.SetBeta, src, id, f)
+	default:
+		err = fmt.Errorf("invalid version %q", obj.Version())
+	}
+	if err != nil {
+		return nil, fmt.Errorf("Rewrite: %w", err)
+	}
+	return m.Freeze()
+}
+
+// rewrite calls set with a rewritten copy of src.
+func rewrite[T any](set func(*T) error, src *T, id *cloud.ResourceID, f func(Path, string) string) error {
+	var cp T
+	if err := newCopier().do(reflect.ValueOf(&cp), reflect.ValueOf(src)); err != nil {
+		return err
+	}
+	v := reflect.ValueOf(&cp)
+	mapStrings(Path{}, v, f)
+	if name := v.Elem().FieldByName("Name"); name.IsValid() && name.Kind() == reflect.String {
+		name.SetString(id.Key.Name)
+	}
+	return set(&cp)
+}
+
+// mapStrings replaces the strings in v with f(path, value). v must be
+// settable or a pointer.
+func mapStrings(p Path, v reflect.Value, f func(Path, string) string) {
+	switch v.Kind() {
+	case reflect.String:
+		if v.CanSet() {
+			v.SetString(f(p, v.String()))
+		}
+	case reflect.Pointer:
+		if !v.IsNil() {
+			mapStrings(p.Pointer(), v.Elem(), f)
+		}
+	case reflect.Struct:
+		t := v.Type()
+		for i := 0; i < v.NumField(); i++ {
+			sf := t.Field(i)
+			if !sf.IsExported() || sf.Name == "NullFields" || sf.Name == "ForceSendFields" {
+				continue
+			}
+			mapStrings(p.Field(sf.Name), v.Field(i), f)
+		}
+	case reflect.Slice:
+		for i := 0; i < v.Len(); i++ {
+			mapStrings(p.Index(i), v.Index(i), f)
+		}
+	case reflect.Map:
+		if v.Type().Elem().Kind() != reflect.String {
+			// Map values are not addressable; only maps of strings are
+			// rewritten.
+			return
+		}
+		for _, k := range v.MapKeys() {
+			v.SetMapIndex(k, reflect.ValueOf(f(p.MapIndex(k.Interface()), v.MapIndex(k).String())).Convert(v.Type().Elem()))
+		}
+	}
+}
@@ -0,0 +1,94 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestRewrite(t *testing.T) {
+	type inner struct {
+		S               string
+		NullFields      []string
+		ForceSendFields []string
+	}
+	type st struct {
+		Name            string
+		S               string
+		LS              []string
+		P               *inner
+		M               map[string]string
+		I               int
+		NullFields      []string
+		ForceSendFields []string
+	}
+
+	m := newTestResource[st, st, st](&testTrait[st, st, st]{})
+	orig := &st{
+		Name: "obj-1",
+		S:    "old",
+		LS:   []string{"old", "x"},
+		P:    &inner{S: "old"},
+		M:    map[string]string{"k": "old"},
+		I:    1,
+	}
+	if err := m.Set(orig); err != nil {
+		t.Fatalf("Set() = %v", err)
+	}
+	r, err := m.Freeze()
+	if err != nil {
+		t.Fatalf("Freeze() = %v", err)
+	}
+
+	newID := &cloud.ResourceID{ProjectID: "proj-1", Resource: "st", Key: meta.GlobalKey("obj-2")}
+	var paths []string
+	res, err := r.(Rewriter).Rewrite(newID, func(p Path, s string) string {
+		paths = append(paths, p.String())
+		return strings.ReplaceAll(s, "old", "new")
+	})
+	if err != nil {
+		t.Fatalf("Rewrite() = %v, want nil", err)
+	}
+	got := res.(Resource[st, st, st])
+	if !got.ResourceID().Equal(newID) {
+		t.Errorf("ResourceID() = %v, want %v", got.ResourceID(), newID)
+	}
+	obj, _ := got.ToGA()
+	want := &st{
+		Name: "obj-2",
+		S:    "new",
+		LS:   []string{"new", "x"},
+		P:    &inner{S: "new"},
+		M:    map[string]string{"k": "new"},
+		I:    1,
+	}
+	if diff := cmp.Diff(obj, want); diff != "" {
+		t.Errorf("Rewrite(): -got,+want: %s", diff)
+	}
+	if !strings.Contains(strings.Join(paths, " "), "*.P*.S") {
+		t.Errorf("paths = %v, want *.P*.S", paths)
+	}
+	// The original is unchanged.
+	if obj, _ := r.ToGA(); obj.S != "old" || obj.P.S != "old" || obj.LS[0] != "old" {
+		t.Errorf("original modified: %+v", obj)
+	}
+}
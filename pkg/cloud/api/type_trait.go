@@ -155,6 +155,10 @@ const (
 	// FieldTypeNonZeroValue is a field that's value must be non-zero or
 	// specified in a meta-field. It will be compared by value in a diff.
 	FieldTypeNonZeroValue FieldType = "NonZeroValue"
+	// FieldTypeDescription is a string field holding a cloud.Description.
+	// Only the human-readable text is compared in a diff; the
+	// machine-managed ownership metadata is ignored.
+	FieldTypeDescription FieldType = "Description"
 )
 
 // CheckSchema validates that the traits are valid and match the schema of the
@@ -188,6 +192,16 @@ func (dt *FieldTraits) AllowZeroValue(p Path) { dt.add(p, FieldTypeAllowZeroValu
 // NonZeroValue specifies the type of the given path.
 func (dt *FieldTraits) NonZeroValue(p Path) { dt.add(p, FieldTypeNonZeroValue) }
 
+// Description specifies the type of the given path. The field must be a
+// string.
+func (dt *FieldTraits) Description(p Path) { dt.add(p, FieldTypeDescription) }
+
+// Ordinary specifies the type of the given path. Fields are Ordinary by
+// default; this marks the field as classified for Unclassified(). As the
+// first matching trait wins, Ordinary for a struct must be added after the
+// traits of its fields.
+func (dt *FieldTraits) Ordinary(p Path) { dt.add(p, FieldTypeOrdinary) }
+
 // Clone create an exact copy of the traits.
 func (dt *FieldTraits) Clone() *FieldTraits {
 	return &FieldTraits{
@@ -195,11 +209,15 @@ func (dt *FieldTraits) Clone() *FieldTraits {
 	}
 }
 
+// FieldType returns the type of the field at p. Fields without an explicit
+// trait are FieldTypeOrdinary.
+func (dt *FieldTraits) FieldType(p Path) FieldType { return dt.fieldType(p) }
+
 func (dt *FieldTraits) fieldType(p Path) FieldType { return dt.fieldTrait(p).fType }
 
 func (dt *FieldTraits) fieldTrait(p Path) fieldTrait {
-	// TODO(bowei): this can be made very efficient with a tree, early bailout
-	// etc.. We will go with a very inefficient implimentation for now.
+	// This is a linear scan over the traits. Traversals in the hot path
+	// (diff, fill) use compile() instead.
 	for _, f := range dt.fields {
 		if p.HasPrefix(f.path) {
 			return f
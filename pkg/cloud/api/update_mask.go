@@ -0,0 +1,106 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// UpdateMask returns the FieldMask (comma separated list of JSON field paths)
+// covering the changes in diff for an object of type T. This is used as the
+// updateMask for Patch in APIs that support it (e.g. Network Services).
+//
+// Nested messages are masked to the changed field, e.g.
+// "action.idleTimeout". Changes inside slices and maps mask the entire slice
+// or map as the FieldMask cannot address individual elements. Changes to
+// OutputOnly and System fields are ignored.
+//
+// An empty string is returned if there are no changes to send.
+func UpdateMask[T any](diff *DiffResult, traits *FieldTraits) (string, error) {
+	if traits == nil {
+		traits = &FieldTraits{}
+	}
+	t := reflect.TypeOf((*T)(nil))
+
+	paths := map[string]bool{}
+	for _, item := range diff.Items {
+		switch traits.fieldType(item.Path) {
+		case FieldTypeOutputOnly, FieldTypeSystem:
+			continue
+		}
+		p, err := maskPath(t, item.Path)
+		if err != nil {
+			return "", err
+		}
+		if p != "" {
+			paths[p] = true
+		}
+	}
+
+	var sorted []string
+	for p := range paths {
+		sorted = append(sorted, p)
+	}
+	sort.Strings(sorted)
+
+	// Remove paths that are covered by a parent path, e.g. "a.b" is covered by
+	// "a". After sorting, a parent always appears before its children.
+	var ret []string
+	for _, p := range sorted {
+		if len(ret) > 0 && strings.HasPrefix(p, ret[len(ret)-1]+".") {
+			continue
+		}
+		ret = append(ret, p)
+	}
+	return strings.Join(ret, ","), nil
+}
+
+// maskPath converts p to a FieldMask path for type t.
+func maskPath(t reflect.Type, p Path) (string, error) {
+	var names []string
+	for _, elem := range p {
+		switch elem[0] {
+		case pathPointer:
+			if t.Kind() != reflect.Pointer {
+				return "", fmt.Errorf("UpdateMask: invalid path %q: %v is not a pointer", p, t)
+			}
+			t = t.Elem()
+		case pathField:
+			if t.Kind() != reflect.Struct {
+				return "", fmt.Errorf("UpdateMask: invalid path %q: %v is not a struct", p, t)
+			}
+			sf, ok := t.FieldByName(elem[1:])
+			if !ok {
+				return "", fmt.Errorf("UpdateMask: invalid path %q: no field %q in %v", p, elem[1:], t)
+			}
+			name, _, _ := strings.Cut(sf.Tag.Get("json"), ",")
+			if name == "" || name == "-" {
+				// Not sent to the server, e.g. ForceSendFields.
+				return "", nil
+			}
+			names = append(names, name)
+			t = sf.Type
+		default:
+			// Slice and map elements cannot be addressed by the mask.
+			return strings.Join(names, "."), nil
+		}
+	}
+	return strings.Join(names, "."), nil
+}
@@ -0,0 +1,92 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"testing"
+)
+
+func TestUpdateMask(t *testing.T) {
+	t.Parallel()
+
+	type inner struct {
+		A string   `json:"a,omitempty"`
+		B []string `json:"b,omitempty"`
+	}
+	type st struct {
+		Name       string            `json:"name,omitempty"`
+		SelfLink   string            `json:"selfLink,omitempty"`
+		Inner      *inner            `json:"inner,omitempty"`
+		Rules      []*inner          `json:"rules,omitempty"`
+		Labels     map[string]string `json:"labels,omitempty"`
+		NullFields []string          `json:"-"`
+	}
+	base := func() *st {
+		return &st{
+			Name:     "n",
+			SelfLink: "link",
+			Inner:    &inner{A: "a", B: []string{"x"}},
+			Rules:    []*inner{{A: "r"}},
+			Labels:   map[string]string{"k": "v"},
+		}
+	}
+	traits := NewFieldTraits()
+	traits.OutputOnly(Path{}.Pointer().Field("SelfLink"))
+
+	for _, tc := range []struct {
+		name string
+		f    func(x *st)
+		want string
+	}{
+		{name: "no diff", f: func(*st) {}, want: ""},
+		{name: "top level field", f: func(x *st) { x.Name = "m" }, want: "name"},
+		{name: "output only field", f: func(x *st) { x.SelfLink = "other" }, want: ""},
+		{name: "nested field", f: func(x *st) { x.Inner.A = "b" }, want: "inner.a"},
+		{name: "nested slice", f: func(x *st) { x.Inner.B = []string{"y"} }, want: "inner.b"},
+		{
+			name: "nested fields are merged",
+			f:    func(x *st) { x.Inner.A = "b"; x.Inner.B = nil },
+			want: "inner.a,inner.b",
+		},
+		{name: "parent covers child", f: func(x *st) { x.Inner = nil }, want: "inner"},
+		{name: "slice element", f: func(x *st) { x.Rules[0].A = "s" }, want: "rules"},
+		{name: "map element", f: func(x *st) { x.Labels["k"] = "w" }, want: "labels"},
+		{
+			name: "multiple fields",
+			f:    func(x *st) { x.Name = "m"; x.Labels = nil; x.Rules = nil },
+			want: "labels,name,rules",
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			a, b := base(), base()
+			tc.f(b)
+			d, err := diff(a, b, traits)
+			if err != nil {
+				t.Fatalf("diff() = %v, want nil", err)
+			}
+			got, err := UpdateMask[st](d, traits)
+			if err != nil {
+				t.Fatalf("UpdateMask() = %v, want nil", err)
+			}
+			if got != tc.want {
+				t.Errorf("UpdateMask() = %q, want %q (diff = %+v)", got, tc.want, d.Items)
+			}
+		})
+	}
+}
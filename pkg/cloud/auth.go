@@ -0,0 +1,93 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"context"
+	"net/http"
+	"sync"
+
+	"golang.org/x/oauth2"
+)
+
+// NewServiceWithTokenSource returns a new Service that authenticates the
+// calls with ts. Each Service can use a different identity, e.g. a
+// multi-tenant controller with a Service per customer project. Use a
+// RotatingTokenSource to replace the credentials of the Service after it is
+// created.
+func NewServiceWithTokenSource(ctx context.Context, ts oauth2.TokenSource, pr ProjectRouter, rl RateLimiter) (*Service, error) {
+	// RotatingTokenSource caches the tokens itself; caching it again would
+	// hide the rotation.
+	if _, ok := ts.(*RotatingTokenSource); !ok {
+		ts = oauth2.ReuseTokenSource(nil, ts)
+	}
+	client := &http.Client{Transport: &oauth2.Transport{Source: ts}}
+	return NewService(ctx, client, pr, rl)
+}
+
+// RotatingTokenSource is an oauth2.TokenSource with credentials that can be
+// replaced while in use. Tokens are cached until they expire.
+type RotatingTokenSource struct {
+	// OnToken is called with each new token. This can be nil.
+	OnToken func(tok *oauth2.Token)
+	// OnError is called when the token cannot be fetched. If OnError
+	// returns a TokenSource, it replaces the current one and the token is
+	// fetched again. This can be nil.
+	OnError func(err error) oauth2.TokenSource
+
+	lock sync.Mutex
+	src  oauth2.TokenSource
+	last string
+}
+
+// NewRotatingTokenSource returns a RotatingTokenSource using ts.
+func NewRotatingTokenSource(ts oauth2.TokenSource) *RotatingTokenSource {
+	return &RotatingTokenSource{src: oauth2.ReuseTokenSource(nil, ts)}
+}
+
+// Rotate replaces the credentials with ts. The next call fetches a new token
+// from ts.
+func (r *RotatingTokenSource) Rotate(ts oauth2.TokenSource) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.src = oauth2.ReuseTokenSource(nil, ts)
+}
+
+// Token implements oauth2.TokenSource.
+func (r *RotatingTokenSource) Token() (*oauth2.Token, error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	tok, err := r.src.Token()
+	if err != nil && r.OnError != nil {
+		if ts := r.OnError(err); ts != nil {
+			r.src = oauth2.ReuseTokenSource(nil, ts)
+			tok, err = r.src.Token()
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	if tok.AccessToken != r.last {
+		r.last = tok.AccessToken
+		if r.OnToken != nil {
+			r.OnToken(tok)
+		}
+	}
+	return tok, nil
+}
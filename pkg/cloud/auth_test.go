@@ -0,0 +1,88 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"golang.org/x/oauth2"
+)
+
+type errTokenSource struct{ err error }
+
+func (s errTokenSource) Token() (*oauth2.Token, error) { return nil, s.err }
+
+func TestNewServiceWithTokenSource(t *testing.T) {
+	ctx := context.Background()
+
+	var gotAuth []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAuth = append(gotAuth, r.Header.Get("Authorization"))
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte("{}"))
+	}))
+	defer srv.Close()
+
+	expired := errors.New("expired")
+	ts := NewRotatingTokenSource(oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "a"}))
+	var onToken []string
+	ts.OnToken = func(tok *oauth2.Token) { onToken = append(onToken, tok.AccessToken) }
+	ts.OnError = func(err error) oauth2.TokenSource {
+		if err != expired {
+			t.Errorf("OnError(%v), want %v", err, expired)
+		}
+		return oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "c"})
+	}
+
+	svc, err := NewServiceWithTokenSource(ctx, ts, &SingleProjectRouter{ID: "proj"}, &NopRateLimiter{})
+	if err != nil {
+		t.Fatalf("NewServiceWithTokenSource() = %v", err)
+	}
+	svc.GA.BasePath = srv.URL + "/compute/v1/"
+	gce := NewGCE(svc)
+	get := func() {
+		t.Helper()
+		if _, err := gce.BackendServices().Get(ctx, meta.GlobalKey("bs")); err != nil {
+			t.Fatalf("Get() = %v", err)
+		}
+	}
+
+	get()
+	get()
+	ts.Rotate(oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "b"}))
+	get()
+	ts.Rotate(errTokenSource{expired})
+	get()
+
+	wantAuth := []string{"Bearer a", "Bearer a", "Bearer b", "Bearer c"}
+	if len(gotAuth) != len(wantAuth) {
+		t.Fatalf("Authorization = %v, want %v", gotAuth, wantAuth)
+	}
+	for i := range wantAuth {
+		if gotAuth[i] != wantAuth[i] {
+			t.Errorf("Authorization[%d] = %q, want %q", i, gotAuth[i], wantAuth[i])
+		}
+	}
+	if want := []string{"a", "b", "c"}; len(onToken) != len(want) || onToken[0] != "a" || onToken[1] != "b" || onToken[2] != "c" {
+		t.Errorf("OnToken calls = %v, want %v", onToken, want)
+	}
+}
@@ -0,0 +1,127 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/rand"
+	"time"
+)
+
+// Backoff is a time-bounded exponential backoff between attempts of an
+// operation, e.g. polling or retrying a call. The zero value does not wait
+// between attempts.
+type Backoff struct {
+	// Initial wait after the first attempt.
+	Initial time.Duration
+	// Multiplier applied to the wait after each attempt. Values <= 1 keep
+	// the wait constant.
+	Multiplier float64
+	// Cap is the maximum wait between attempts. 0 means no cap.
+	Cap time.Duration
+	// Jitter randomly increases each wait by up to this fraction of the
+	// wait, e.g. 0.1 for up to 10%. This spreads out the attempts of
+	// concurrent callers.
+	Jitter float64
+	// MaxElapsed bounds the total time spent in Retry(). Retry() will not
+	// start a wait that would end after MaxElapsed. 0 means no bound.
+	MaxElapsed time.Duration
+}
+
+// Duration returns the wait after the n-th attempt (starting from 1).
+func (b *Backoff) Duration(n int) time.Duration {
+	if n < 1 || b.Initial <= 0 {
+		return 0
+	}
+	d := float64(b.Initial)
+	if b.Multiplier > 1 {
+		d *= math.Pow(b.Multiplier, float64(n-1))
+	}
+	if b.Cap > 0 && d > float64(b.Cap) {
+		d = float64(b.Cap)
+	}
+	if b.Jitter > 0 {
+		d += d * b.Jitter * rand.Float64()
+	}
+	if d >= math.MaxInt64 {
+		return time.Duration(math.MaxInt64)
+	}
+	return time.Duration(d)
+}
+
+// Wait for Duration(n) or until the context is done. Returns ctx.Err() if
+// the context is done before the wait is over.
+func (b *Backoff) Wait(ctx context.Context, n int) error {
+	return SleepWithContext(ctx, b.Duration(n))
+}
+
+// BackoffTimeoutError is returned by Backoff.Retry() when the operation has
+// not succeeded within Backoff.MaxElapsed.
+type BackoffTimeoutError struct {
+	// Attempts made.
+	Attempts int
+	// Elapsed time since the first attempt.
+	Elapsed time.Duration
+}
+
+func (e *BackoffTimeoutError) Error() string {
+	return fmt.Sprintf("not done after %d attempts (%v elapsed)", e.Attempts, e.Elapsed)
+}
+
+// Retry calls f until it returns done or an error, waiting between the
+// attempts. Returns the error from f, ctx.Err() if the context is done or
+// *BackoffTimeoutError if MaxElapsed is exceeded.
+func (b *Backoff) Retry(ctx context.Context, f func(ctx context.Context, attempt int) (done bool, err error)) error {
+	start := time.Now()
+	for n := 1; ; n++ {
+		// ctx.Done() must be checked before returning ctx.Err().
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+		done, err := f(ctx, n)
+		if done || err != nil {
+			return err
+		}
+		d := b.Duration(n)
+		if b.MaxElapsed > 0 && time.Since(start)+d > b.MaxElapsed {
+			return &BackoffTimeoutError{Attempts: n, Elapsed: time.Since(start)}
+		}
+		if err := SleepWithContext(ctx, d); err != nil {
+			return err
+		}
+	}
+}
+
+// SleepWithContext waits for d or until the context is done. Returns
+// ctx.Err() if the context is done first.
+func SleepWithContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,136 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestBackoffDuration(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		b    Backoff
+		want []time.Duration
+	}{
+		{name: "zero", b: Backoff{}, want: []time.Duration{0, 0, 0}},
+		{name: "constant", b: Backoff{Initial: time.Second}, want: []time.Duration{time.Second, time.Second, time.Second}},
+		{
+			name: "exponential",
+			b:    Backoff{Initial: time.Second, Multiplier: 2},
+			want: []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second},
+		},
+		{
+			name: "cap",
+			b:    Backoff{Initial: time.Second, Multiplier: 3, Cap: 5 * time.Second},
+			want: []time.Duration{time.Second, 3 * time.Second, 5 * time.Second, 5 * time.Second},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			for i, want := range tc.want {
+				if got := tc.b.Duration(i + 1); got != want {
+					t.Errorf("Duration(%d) = %v, want %v", i+1, got, want)
+				}
+			}
+		})
+	}
+
+	b := Backoff{Initial: time.Second, Multiplier: 2, Jitter: 0.5}
+	for i := 0; i < 100; i++ {
+		if d := b.Duration(2); d < 2*time.Second || d > 3*time.Second {
+			t.Fatalf("Duration(2) = %v, want in [2s, 3s]", d)
+		}
+	}
+	// Large attempts do not overflow.
+	b = Backoff{Initial: time.Second, Multiplier: 10}
+	if d := b.Duration(100); d <= 0 {
+		t.Errorf("Duration(100) = %v, want > 0", d)
+	}
+}
+
+func TestBackoffRetry(t *testing.T) {
+	ctx := context.Background()
+	errTest := errors.New("test")
+
+	for _, tc := range []struct {
+		name         string
+		b            Backoff
+		doneAt       int
+		errAt        int
+		wantErr      error
+		wantTimeout  bool
+		wantAttempts int
+	}{
+		{name: "done", doneAt: 3, wantAttempts: 3},
+		{name: "error", errAt: 2, wantErr: errTest, wantAttempts: 2},
+		{
+			name:         "timeout",
+			b:            Backoff{Initial: time.Millisecond, MaxElapsed: 10 * time.Millisecond},
+			wantTimeout:  true,
+			wantAttempts: -1,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var attempts int
+			err := tc.b.Retry(ctx, func(_ context.Context, n int) (bool, error) {
+				attempts++
+				if n != attempts {
+					t.Errorf("attempt = %d, want %d", n, attempts)
+				}
+				if n == tc.errAt {
+					return false, errTest
+				}
+				return n == tc.doneAt, nil
+			})
+			var timeoutErr *BackoffTimeoutError
+			switch {
+			case tc.wantTimeout:
+				if !errors.As(err, &timeoutErr) || timeoutErr.Attempts != attempts {
+					t.Errorf("Retry() = %v, want BackoffTimeoutError after %d attempts", err, attempts)
+				}
+			case !errors.Is(err, tc.wantErr):
+				t.Errorf("Retry() = %v, want %v", err, tc.wantErr)
+			}
+			if tc.wantAttempts >= 0 && attempts != tc.wantAttempts {
+				t.Errorf("attempts = %d, want %d", attempts, tc.wantAttempts)
+			}
+		})
+	}
+
+	cctx, cancel := context.WithCancel(ctx)
+	b := Backoff{Initial: time.Hour}
+	err := b.Retry(cctx, func(context.Context, int) (bool, error) {
+		cancel()
+		return false, nil
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Retry() = %v, want %v", err, context.Canceled)
+	}
+}
+
+func TestSleepWithContext(t *testing.T) {
+	if err := SleepWithContext(context.Background(), time.Millisecond); err != nil {
+		t.Errorf("SleepWithContext() = %v, want nil", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := SleepWithContext(ctx, time.Hour); !errors.Is(err, context.Canceled) {
+		t.Errorf("SleepWithContext() = %v, want %v", err, context.Canceled)
+	}
+}
@@ -0,0 +1,86 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+)
+
+// Call is a call to the API made by an implementation of the Cloud
+// interfaces outside of this package (e.g. pkg/cloud/gapic). StartCall and
+// End apply the Options, the ProjectRouter, the RateLimiter, the
+// CallObservers and the CallMetrics in the same way as the generated
+// implementation.
+type Call struct {
+	// Key of the call. Key.ProjectID is the project to use for the call.
+	Key *CallContextKey
+	// Header to add to the request.
+	Header http.Header
+	// ValidateOnly is set by the ValidateOnly Option.
+	ValidateOnly bool
+	// UpdateMask is set by the UpdateMask Option.
+	UpdateMask string
+	// RequestSize and ResponseSize in bytes are reported to the
+	// CallMetrics by End, if set by the caller.
+	RequestSize  int
+	ResponseSize int
+
+	s     *Service
+	start time.Time
+}
+
+// StartCall starts the call of operation (e.g. "Get") on service (e.g.
+// "BackendServices"). This blocks on s.RateLimiter. End must be called if
+// StartCall does not return an error.
+func StartCall(ctx context.Context, s *Service, version meta.Version, service, operation string, options ...Option) (*Call, error) {
+	opts := mergeOptions(options)
+	c := &Call{
+		Key: &CallContextKey{
+			ProjectID: getProjectID(ctx, s.ProjectRouter, opts, version, service),
+			Operation: operation,
+			Version:   version,
+			Service:   service,
+		},
+		Header:       http.Header{},
+		ValidateOnly: opts.validateOnly,
+		UpdateMask:   opts.updateMask,
+		s:            s,
+	}
+	setCallHeaders(ctx, c.Header, opts)
+
+	callObserverStart(ctx, c.Key)
+	if err := s.RateLimiter.Accept(ctx, c.Key); err != nil {
+		callObserverEnd(ctx, c.Key, err)
+		return nil, err
+	}
+	c.start = time.Now()
+	return c, nil
+}
+
+// End the call with the result err.
+func (c *Call) End(ctx context.Context, err error) {
+	c.s.endCall(ctx, c.Key, CallStats{
+		Latency:      time.Since(c.start),
+		RequestSize:  c.RequestSize,
+		ResponseSize: c.ResponseSize,
+	}, err)
+	c.s.RateLimiter.Observe(ctx, err, c.Key)
+}
@@ -33,3 +33,11 @@ func isGoogleAPIErrorCode(err error, code int) bool {
 }
 
 func IsGoogleAPINotFound(err error) bool { return isGoogleAPIErrorCode(err, http.StatusNotFound) }
+
+func IsGoogleAPIAlreadyExists(err error) bool {
+	return isGoogleAPIErrorCode(err, http.StatusConflict)
+}
+
+func IsGoogleAPIPreconditionFailed(err error) bool {
+	return isGoogleAPIErrorCode(err, http.StatusPreconditionFailed)
+}
@@ -98,3 +98,63 @@ func TestIsGoogleAPINotFound(t *testing.T) {
 		})
 	}
 }
+
+func TestIsGoogleAPIAlreadyExists(t *testing.T) {
+	for _, tc := range []struct {
+		desc string
+		err  error
+		want bool
+	}{
+		{
+			desc: "Nil error",
+		},
+		{
+			desc: "Not a google API error",
+			err:  fmt.Errorf("some error"),
+		},
+		{
+			desc: "Google API NotFound error",
+			err:  &googleapi.Error{Code: http.StatusNotFound, Message: "some message"},
+		},
+		{
+			desc: "Google API Conflict error",
+			err:  &googleapi.Error{Code: http.StatusConflict, Message: "some message"},
+			want: true,
+		},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			got := IsGoogleAPIAlreadyExists(tc.err)
+			if got != tc.want {
+				t.Errorf("IsGoogleAPIAlreadyExists(%v) = %v, want %v", tc.err, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestIsGoogleAPIPreconditionFailed(t *testing.T) {
+	for _, tc := range []struct {
+		desc string
+		err  error
+		want bool
+	}{
+		{
+			desc: "Nil error",
+		},
+		{
+			desc: "Google API Conflict error",
+			err:  &googleapi.Error{Code: http.StatusConflict, Message: "some message"},
+		},
+		{
+			desc: "Google API PreconditionFailed error",
+			err:  &googleapi.Error{Code: http.StatusPreconditionFailed, Message: "some message"},
+			want: true,
+		},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			got := IsGoogleAPIPreconditionFailed(tc.err)
+			if got != tc.want {
+				t.Errorf("IsGoogleAPIPreconditionFailed(%v) = %v, want %v", tc.err, got, tc.want)
+			}
+		})
+	}
+}
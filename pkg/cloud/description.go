@@ -0,0 +1,155 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// DescriptionMetadataKey prefixes the line in the Description of a resource
+// that holds the metadata JSON: "<DescriptionMetadataKey>=<json>".
+const DescriptionMetadataKey = "rgraph-metadata"
+
+// Description of a resource with metadata. The metadata is machine-managed
+// and is encoded as a line of JSON after the human-readable Text:
+//
+//	Frontend for the shop.
+//	rgraph-metadata={"owner":"ingress","cluster":"c1","values":{"rgraph-orphaned-at":"1700000000"}}
+//
+// Use ParseDescription() and String() to convert from and to the Description
+// field. See also api.FieldTypeDescription, which ignores the metadata in a
+// diff.
+type Description struct {
+	// Text is the human-readable part of the Description.
+	Text string
+	// Owner of the resource, e.g. the controller that manages it.
+	Owner string
+	// Cluster the resource belongs to.
+	Cluster string
+	// Component of the Owner that manages the resource.
+	Component string
+	// Created is when the resource was created. Zero if not set.
+	Created time.Time
+	// Updated is when the resource was last updated. Zero if not set.
+	Updated time.Time
+	// Values are other machine-managed values by name, e.g. the time a
+	// resource was marked as an orphan by package gc.
+	Values map[string]string
+}
+
+// descriptionJSON is the JSON encoding of the metadata in a Description.
+type descriptionJSON struct {
+	Owner     string            `json:"owner,omitempty"`
+	Cluster   string            `json:"cluster,omitempty"`
+	Component string            `json:"component,omitempty"`
+	Created   *time.Time        `json:"created,omitempty"`
+	Updated   *time.Time        `json:"updated,omitempty"`
+	Values    map[string]string `json:"values,omitempty"`
+}
+
+// ParseDescription parses the Description field s of a resource. A
+// Description without metadata is returned as the Text. Returns an error if
+// the metadata JSON is invalid.
+func ParseDescription(s string) (*Description, error) {
+	text, line, ok := cutMetadataLine(s)
+	d := &Description{Text: text}
+	if !ok {
+		return d, nil
+	}
+	var dj descriptionJSON
+	if err := json.Unmarshal([]byte(line), &dj); err != nil {
+		return nil, fmt.Errorf("ParseDescription: invalid %s: %w", DescriptionMetadataKey, err)
+	}
+	d.Owner = dj.Owner
+	d.Cluster = dj.Cluster
+	d.Component = dj.Component
+	if dj.Created != nil {
+		d.Created = *dj.Created
+	}
+	if dj.Updated != nil {
+		d.Updated = *dj.Updated
+	}
+	d.Values = dj.Values
+	return d, nil
+}
+
+// SetValue sets the named value.
+func (d *Description) SetValue(name, value string) {
+	if d.Values == nil {
+		d.Values = map[string]string{}
+	}
+	d.Values[name] = value
+}
+
+// DeleteValue removes the named value.
+func (d *Description) DeleteValue(name string) {
+	delete(d.Values, name)
+	if len(d.Values) == 0 {
+		d.Values = nil
+	}
+}
+
+// HasOwnership is true if any of the ownership metadata is set.
+func (d *Description) HasOwnership() bool {
+	return d.Owner != "" || d.Cluster != "" || d.Component != "" || !d.Created.IsZero() || !d.Updated.IsZero()
+}
+
+// String returns the value for the Description field of the resource.
+func (d *Description) String() string {
+	if !d.HasOwnership() && len(d.Values) == 0 {
+		return d.Text
+	}
+	dj := descriptionJSON{Owner: d.Owner, Cluster: d.Cluster, Component: d.Component, Values: d.Values}
+	if !d.Created.IsZero() {
+		t := d.Created.UTC()
+		dj.Created = &t
+	}
+	if !d.Updated.IsZero() {
+		t := d.Updated.UTC()
+		dj.Updated = &t
+	}
+	// Marshal cannot fail for descriptionJSON.
+	b, _ := json.Marshal(&dj)
+	line := DescriptionMetadataKey + "=" + string(b)
+	if d.Text == "" {
+		return line
+	}
+	return d.Text + "\n" + line
+}
+
+// DescriptionText returns the Description field s without the metadata.
+// Invalid metadata is removed as well.
+func DescriptionText(s string) string {
+	text, _, _ := cutMetadataLine(s)
+	return text
+}
+
+// cutMetadataLine returns the Description without the metadata line and the
+// JSON from the line. ok is false if there is no metadata line.
+func cutMetadataLine(s string) (text, line string, ok bool) {
+	lines := strings.Split(s, "\n")
+	for i, l := range lines {
+		if v, found := strings.CutPrefix(l, DescriptionMetadataKey+"="); found {
+			rest := append(append([]string(nil), lines[:i]...), lines[i+1:]...)
+			return strings.Join(rest, "\n"), v, true
+		}
+	}
+	return s, "", false
+}
@@ -0,0 +1,129 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestDescription(t *testing.T) {
+	t.Parallel()
+
+	created := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	for _, tc := range []struct {
+		name string
+		d    Description
+		want string
+	}{
+		{
+			name: "empty",
+		},
+		{
+			name: "text only",
+			d:    Description{Text: "abc"},
+			want: "abc",
+		},
+		{
+			name: "ownership only",
+			d:    Description{Owner: "ingress", Cluster: "c1"},
+			want: `rgraph-metadata={"owner":"ingress","cluster":"c1"}`,
+		},
+		{
+			name: "values only",
+			d:    Description{Text: "abc", Values: map[string]string{"b": "2", "a": "1"}},
+			want: "abc\n" + `rgraph-metadata={"values":{"a":"1","b":"2"}}`,
+		},
+		{
+			name: "all",
+			d:    Description{Text: "abc\ndef", Owner: "ingress", Cluster: "c1", Component: "l7lb", Created: created, Updated: created, Values: map[string]string{"a": "1"}},
+			want: "abc\ndef\n" + `rgraph-metadata={"owner":"ingress","cluster":"c1","component":"l7lb","created":"2024-01-02T03:04:05Z","updated":"2024-01-02T03:04:05Z","values":{"a":"1"}}`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got := tc.d.String()
+			if got != tc.want {
+				t.Fatalf("String() = %q, want %q", got, tc.want)
+			}
+			d, err := ParseDescription(got)
+			if err != nil {
+				t.Fatalf("ParseDescription(%q) = %v, want nil", got, err)
+			}
+			if diff := cmp.Diff(&tc.d, d); diff != "" {
+				t.Errorf("ParseDescription(%q) diff -want +got: %s", got, diff)
+			}
+			if got := DescriptionText(got); got != tc.d.Text {
+				t.Errorf("DescriptionText() = %q, want %q", got, tc.d.Text)
+			}
+		})
+	}
+}
+
+func TestParseDescription(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name    string
+		s       string
+		want    *Description
+		wantErr bool
+	}{
+		{
+			name: "metadata before other lines",
+			s:    "abc\nrgraph-metadata={\"owner\":\"x\"}\ndef",
+			want: &Description{Text: "abc\ndef", Owner: "x"},
+		},
+		{
+			name:    "invalid json",
+			s:       "abc\nrgraph-metadata={",
+			wantErr: true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := ParseDescription(tc.s)
+			if gotErr := err != nil; gotErr != tc.wantErr {
+				t.Fatalf("ParseDescription(%q) = %v; gotErr = %t, want %t", tc.s, err, gotErr, tc.wantErr)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("ParseDescription(%q) diff -want +got: %s", tc.s, diff)
+			}
+		})
+	}
+}
+
+func TestDescriptionValues(t *testing.T) {
+	t.Parallel()
+
+	d := &Description{Text: "abc"}
+	d.SetValue("a", "1")
+	d.SetValue("b", "2")
+	if got, want := d.String(), "abc\n"+`rgraph-metadata={"values":{"a":"1","b":"2"}}`; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+	d.DeleteValue("a")
+	d.DeleteValue("b")
+	d.DeleteValue("c")
+	if d.Values != nil {
+		t.Errorf("Values = %v, want nil", d.Values)
+	}
+	if got := d.String(); got != "abc" {
+		t.Errorf("String() = %q, want %q", got, "abc")
+	}
+}
@@ -0,0 +1,111 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"net/http"
+	"strings"
+)
+
+// RegionalEndpoints is an http.RoundTripper that sends the compute API calls
+// for regional and zonal resources to the regional endpoint of the region,
+// e.g. https://us-central1-compute.googleapis.com. This reduces the latency
+// and the dependency on other regions. The calls for global resources use
+// the global endpoint.
+//
+// Wrap the transport of the client used with NewService:
+//
+//	client.Transport = &cloud.RegionalEndpoints{Transport: client.Transport}
+//	svc, err := cloud.NewService(ctx, client, pr, rl)
+//
+// The SelfLinks of the resources are not changed.
+type RegionalEndpoints struct {
+	// Transport makes the requests. If nil, http.DefaultTransport is used.
+	Transport http.RoundTripper
+	// Regions that use the regional endpoint. If empty, all regions use the
+	// regional endpoint.
+	Regions []string
+	// Domain of the regional endpoints. If empty, "googleapis.com" is used.
+	Domain string
+}
+
+// RoundTrip implements http.RoundTripper.
+func (e *RegionalEndpoints) RoundTrip(req *http.Request) (*http.Response, error) {
+	t := e.Transport
+	if t == nil {
+		t = http.DefaultTransport
+	}
+	host, ok := e.regionalHost(req)
+	if !ok {
+		return t.RoundTrip(req)
+	}
+	// RoundTrippers must not modify the request.
+	req = req.Clone(req.Context())
+	req.URL.Host = host
+	req.Host = host
+	return t.RoundTrip(req)
+}
+
+// regionalHost returns the host of the regional endpoint for the request.
+// Returns false if the request should not be changed.
+func (e *RegionalEndpoints) regionalHost(req *http.Request) (string, bool) {
+	switch req.URL.Host {
+	case "compute.googleapis.com", "www.googleapis.com":
+	default:
+		return "", false
+	}
+	if !strings.HasPrefix(req.URL.Path, "/compute/") {
+		return "", false
+	}
+	region := regionFromURLPath(req.URL.Path)
+	if region == "" {
+		return "", false
+	}
+	if len(e.Regions) > 0 {
+		var found bool
+		for _, r := range e.Regions {
+			found = found || r == region
+		}
+		if !found {
+			return "", false
+		}
+	}
+	domain := e.Domain
+	if domain == "" {
+		domain = "googleapis.com"
+	}
+	return region + "-compute." + domain, true
+}
+
+// regionFromURLPath returns the region of the regional or zonal resource in
+// the path. Returns "" if the path is not for a regional or zonal resource.
+func regionFromURLPath(path string) string {
+	parts := strings.Split(path, "/")
+	for i := 0; i+1 < len(parts); i++ {
+		switch parts[i] {
+		case "regions":
+			return parts[i+1]
+		case "zones":
+			// The region of the zone "us-central1-a" is "us-central1".
+			if j := strings.LastIndex(parts[i+1], "-"); j > 0 {
+				return parts[i+1][:j]
+			}
+			return ""
+		}
+	}
+	return ""
+}
@@ -0,0 +1,102 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"net/http"
+	"testing"
+)
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) { return f(req) }
+
+func TestRegionalEndpoints(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		regions []string
+		url     string
+		want    string
+	}{
+		{
+			name: "regional",
+			url:  "https://compute.googleapis.com/compute/v1/projects/p/regions/us-central1/backendServices/bs",
+			want: "https://us-central1-compute.googleapis.com/compute/v1/projects/p/regions/us-central1/backendServices/bs",
+		},
+		{
+			name: "zonal",
+			url:  "https://compute.googleapis.com/compute/v1/projects/p/zones/europe-west1-b/instances/i",
+			want: "https://europe-west1-compute.googleapis.com/compute/v1/projects/p/zones/europe-west1-b/instances/i",
+		},
+		{
+			name: "zone operation",
+			url:  "https://www.googleapis.com/compute/beta/projects/p/zones/us-east1-c/operations/op",
+			want: "https://us-east1-compute.googleapis.com/compute/beta/projects/p/zones/us-east1-c/operations/op",
+		},
+		{
+			name: "global",
+			url:  "https://compute.googleapis.com/compute/v1/projects/p/global/backendServices/bs",
+			want: "https://compute.googleapis.com/compute/v1/projects/p/global/backendServices/bs",
+		},
+		{
+			name: "aggregated",
+			url:  "https://compute.googleapis.com/compute/v1/projects/p/aggregated/backendServices",
+			want: "https://compute.googleapis.com/compute/v1/projects/p/aggregated/backendServices",
+		},
+		{
+			name: "other API",
+			url:  "https://networkservices.googleapis.com/v1/projects/p/locations/us-central1/meshes/m",
+			want: "https://networkservices.googleapis.com/v1/projects/p/locations/us-central1/meshes/m",
+		},
+		{
+			name:    "region not enabled",
+			regions: []string{"us-east1"},
+			url:     "https://compute.googleapis.com/compute/v1/projects/p/regions/us-central1/backendServices/bs",
+			want:    "https://compute.googleapis.com/compute/v1/projects/p/regions/us-central1/backendServices/bs",
+		},
+		{
+			name:    "region enabled",
+			regions: []string{"us-east1", "us-central1"},
+			url:     "https://compute.googleapis.com/compute/v1/projects/p/regions/us-central1/backendServices/bs",
+			want:    "https://us-central1-compute.googleapis.com/compute/v1/projects/p/regions/us-central1/backendServices/bs",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var got string
+			e := &RegionalEndpoints{
+				Regions: tc.regions,
+				Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+					got = req.URL.String()
+					return &http.Response{StatusCode: http.StatusOK}, nil
+				}),
+			}
+			req, err := http.NewRequest("GET", tc.url, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := e.RoundTrip(req); err != nil {
+				t.Fatalf("RoundTrip() = %v", err)
+			}
+			if got != tc.want {
+				t.Errorf("URL = %q, want %q", got, tc.want)
+			}
+			if req.URL.String() != tc.url {
+				t.Errorf("RoundTrip() modified the request URL to %q", req.URL)
+			}
+		})
+	}
+}
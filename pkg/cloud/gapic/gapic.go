@@ -0,0 +1,237 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package gapic implements cloud.Cloud with the cloud.google.com/go/compute
+// clients. The clients retry the calls using GAX. The Compute API is only
+// served over REST, so the REST clients are used.
+//
+// Only some services and methods use the compute clients (see Cloud); the
+// others use the google.golang.org/api implementation (cloud.GCE). This
+// allows moving the services one at a time without changing the code using
+// the cloud.Cloud interface, e.g. rgraph:
+//
+//	gc, err := gapic.New(ctx, svc, option.WithCredentialsFile(...))
+//	defer gc.Close()
+//	result, err := plan.Do(ctx, gc, graph)
+package gapic
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+
+	compute "cloud.google.com/go/compute/apiv1"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"github.com/googleapis/gax-go/v2/callctx"
+	"google.golang.org/api/option"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"k8s.io/klog/v2"
+)
+
+// Cloud implements cloud.Cloud. The following use the compute clients:
+//
+//   - BackendServices: Get, List, Insert, Delete, Update, Patch. Patch
+//     returns an error for objects with NullFields.
+//   - HealthChecks: Get, List, Insert, Delete, Update.
+//
+// Everything else uses the embedded cloud.Cloud.
+type Cloud struct {
+	cloud.Cloud
+
+	s *cloud.Service
+
+	backendServices *compute.BackendServicesClient
+	healthChecks    *compute.HealthChecksClient
+}
+
+// Cloud implements cloud.Cloud.
+var _ cloud.Cloud = (*Cloud)(nil)
+
+// New returns a Cloud. s provides the ProjectRouter, RateLimiter and the
+// google.golang.org/api services for the methods not implemented with the
+// compute clients. opts configure the compute clients (e.g. credentials,
+// endpoint). Close must be called to release the clients.
+func New(ctx context.Context, s *cloud.Service, opts ...option.ClientOption) (*Cloud, error) {
+	c := &Cloud{
+		Cloud: cloud.NewGCE(s),
+		s:     s,
+	}
+	var err error
+	if c.backendServices, err = compute.NewBackendServicesRESTClient(ctx, opts...); err != nil {
+		return nil, err
+	}
+	if c.healthChecks, err = compute.NewHealthChecksRESTClient(ctx, opts...); err != nil {
+		c.Close()
+		return nil, err
+	}
+	return c, nil
+}
+
+// Close the compute clients.
+func (c *Cloud) Close() error {
+	var errs []error
+	if c.backendServices != nil {
+		errs = append(errs, c.backendServices.Close())
+	}
+	if c.healthChecks != nil {
+		errs = append(errs, c.healthChecks.Close())
+	}
+	return errors.Join(errs...)
+}
+
+// BackendServices implements cloud.Cloud.
+func (c *Cloud) BackendServices() cloud.BackendServices {
+	return &backendServices{BackendServices: c.Cloud.BackendServices(), c: c}
+}
+
+// HealthChecks implements cloud.Cloud.
+func (c *Cloud) HealthChecks() cloud.HealthChecks {
+	return &healthChecks{HealthChecks: c.Cloud.HealthChecks(), c: c}
+}
+
+// do runs f as the operation of service. f is called with the project of
+// the call.
+func (c *Cloud) do(ctx context.Context, service, operation string, options []cloud.Option, f func(ctx context.Context, project string) error) error {
+	call, err := cloud.StartCall(ctx, c.s, meta.VersionGA, service, operation, options...)
+	if err != nil {
+		return err
+	}
+	if call.ValidateOnly {
+		err = fmt.Errorf("gapic: ValidateOnly is not supported for %s.%s", service, operation)
+		call.End(ctx, err)
+		return err
+	}
+	for k, vs := range call.Header {
+		for _, v := range vs {
+			ctx = callctx.SetHeaders(ctx, k, v)
+		}
+	}
+	err = f(ctx, call.Key.ProjectID)
+	call.End(ctx, err)
+	klog.V(4).Infof("gapic %s.%s(%s) = %v", service, operation, call.Key.ProjectID, err)
+	return err
+}
+
+// mutate runs f as the operation of service and waits for the returned
+// Operation. As with cloud.GCE, waiting is not part of the call and the
+// warnings of the completed Operation are reported to the
+// cloud.OperationWarningHandler in ctx.
+func (c *Cloud) mutate(ctx context.Context, service, operation string, options []cloud.Option, f func(ctx context.Context, project string) (*compute.Operation, error)) error {
+	var op *compute.Operation
+	err := c.do(ctx, service, operation, options, func(ctx context.Context, project string) error {
+		var err error
+		op, err = f(ctx, project)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	if err := op.Wait(ctx); err != nil {
+		return err
+	}
+	var warnings []cloud.OperationWarning
+	for _, w := range op.Proto().GetWarnings() {
+		warnings = append(warnings, cloud.OperationWarning{Code: w.GetCode(), Message: w.GetMessage()})
+	}
+	if len(warnings) > 0 {
+		klog.V(2).Infof("gapic %s.%s completed with warnings: %v", service, operation, warnings)
+		cloud.ReportOperationWarnings(ctx, warnings)
+	}
+	return nil
+}
+
+// checkGlobal returns an error if key is not a valid global key.
+func checkGlobal(key *meta.Key) error {
+	if !key.Valid() || key.Type() != meta.Global {
+		return fmt.Errorf("invalid GCE key (%+v)", key)
+	}
+	return nil
+}
+
+// fromProto converts the message to the google.golang.org/api type T. The
+// types have the same JSON representation.
+func fromProto[T any](m proto.Message) (*T, error) {
+	b, err := protojson.Marshal(m)
+	if err != nil {
+		return nil, err
+	}
+	ret := new(T)
+	if err := json.Unmarshal(b, ret); err != nil {
+		return nil, fmt.Errorf("gapic: convert %T to %T: %w", m, ret, err)
+	}
+	return ret, nil
+}
+
+// toProto converts the google.golang.org/api object to m.
+//
+// The fields in NullFields are encoded as JSON null, which protojson reads as
+// unset. This clears the field in an Update, which replaces the resource, but
+// not in a Patch; use toPatchProto for a Patch.
+func toProto[M proto.Message](obj any, m M) (M, error) {
+	b, err := json.Marshal(obj)
+	if err != nil {
+		return m, err
+	}
+	if err := (protojson.UnmarshalOptions{DiscardUnknown: true}).Unmarshal(b, m); err != nil {
+		return m, fmt.Errorf("gapic: convert %T to %T: %w", obj, m, err)
+	}
+	return m, nil
+}
+
+// toPatchProto converts the google.golang.org/api object to m for a Patch.
+// The compute Patch requests do not have a field mask, so clearing a field
+// with NullFields cannot be expressed and returns an error instead of
+// silently leaving the field unchanged.
+func toPatchProto[M proto.Message](obj any, m M) (M, error) {
+	b, err := json.Marshal(obj)
+	if err != nil {
+		return m, err
+	}
+	var v any
+	if err := json.Unmarshal(b, &v); err != nil {
+		return m, err
+	}
+	if paths := nullPaths("", v); len(paths) > 0 {
+		return m, fmt.Errorf("gapic: Patch of %T cannot clear fields %v (NullFields are not supported, use Update)", obj, paths)
+	}
+	return toProto(obj, m)
+}
+
+// nullPaths returns the paths of the JSON null values in v.
+func nullPaths(prefix string, v any) []string {
+	var ret []string
+	switch v := v.(type) {
+	case nil:
+		ret = append(ret, prefix)
+	case map[string]any:
+		for k, x := range v {
+			if prefix != "" {
+				k = prefix + "." + k
+			}
+			ret = append(ret, nullPaths(k, x)...)
+		}
+	case []any:
+		for i, x := range v {
+			ret = append(ret, nullPaths(fmt.Sprintf("%s[%d]", prefix, i), x)...)
+		}
+	}
+	sort.Strings(ret)
+	return ret
+}
@@ -0,0 +1,344 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gapic
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/filter"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	ga "google.golang.org/api/compute/v1"
+	"google.golang.org/api/option"
+)
+
+// fakeServer serves the global REST API collections (e.g. backendServices)
+// for the tests.
+type fakeServer struct {
+	lock sync.Mutex
+	// objs by "collection/name".
+	objs    map[string]json.RawMessage
+	headers []http.Header
+	// warnings are added to the Operations.
+	warnings []map[string]any
+}
+
+func (f *fakeServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	f.headers = append(f.headers, r.Header.Clone())
+	const prefix = "/compute/v1/projects/proj/global/"
+	path := strings.TrimPrefix(r.URL.Path, prefix)
+	collection, name, _ := strings.Cut(path, "/")
+	writeJSON := func(v any) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(v)
+	}
+	done := map[string]any{
+		"name":     "op",
+		"status":   "DONE",
+		"selfLink": "https://www.googleapis.com/compute/v1/projects/proj/global/operations/op",
+		"warnings": f.warnings,
+	}
+
+	switch {
+	case r.Method == "GET" && path == "operations/op", r.Method == "POST" && path == "operations/op/wait":
+		writeJSON(done)
+	case r.Method == "GET" && name == "":
+		var items []json.RawMessage
+		for k, o := range f.objs {
+			if strings.HasPrefix(k, collection+"/") {
+				items = append(items, o)
+			}
+		}
+		writeJSON(map[string]any{"items": items})
+	case r.Method == "GET":
+		o, ok := f.objs[path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			writeJSON(map[string]any{"error": map[string]any{"code": 404, "message": "not found"}})
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(o)
+	case r.Method == "POST" && name == "":
+		b, _ := io.ReadAll(r.Body)
+		var obj struct{ Name string }
+		json.Unmarshal(b, &obj)
+		f.objs[collection+"/"+obj.Name] = b
+		writeJSON(done)
+	case r.Method == "PUT" || r.Method == "PATCH":
+		old, ok := f.objs[path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			writeJSON(map[string]any{"error": map[string]any{"code": 404, "message": "not found"}})
+			return
+		}
+		b, _ := io.ReadAll(r.Body)
+		if r.Method == "PATCH" {
+			b = mergePatch(old, b)
+		}
+		f.objs[path] = b
+		writeJSON(done)
+	case r.Method == "DELETE":
+		delete(f.objs, path)
+		writeJSON(done)
+	default:
+		w.WriteHeader(http.StatusBadRequest)
+	}
+}
+
+// mergePatch applies the top-level fields of patch to obj. A null field is
+// removed.
+func mergePatch(obj, patch json.RawMessage) json.RawMessage {
+	var o, p map[string]json.RawMessage
+	json.Unmarshal(obj, &o)
+	json.Unmarshal(patch, &p)
+	for k, v := range p {
+		if string(v) == "null" {
+			delete(o, k)
+		} else {
+			o[k] = v
+		}
+	}
+	b, _ := json.Marshal(o)
+	return b
+}
+
+// newCloud returns a Cloud using a fakeServer. The embedded cloud.Cloud
+// (the google.golang.org/api implementation) uses the same fakeServer.
+func newCloud(t *testing.T) (*Cloud, *fakeServer) {
+	t.Helper()
+	fake := &fakeServer{objs: map[string]json.RawMessage{}}
+	srv := httptest.NewServer(fake)
+	t.Cleanup(srv.Close)
+
+	gaSvc, err := ga.NewService(context.Background(), option.WithEndpoint(srv.URL+"/compute/v1/"), option.WithoutAuthentication())
+	if err != nil {
+		t.Fatalf("ga.NewService() = %v", err)
+	}
+	svc := &cloud.Service{
+		GA:            gaSvc,
+		ProjectRouter: &cloud.SingleProjectRouter{ID: "proj"},
+		RateLimiter:   &cloud.NopRateLimiter{},
+	}
+	c, err := New(context.Background(), svc, option.WithEndpoint(srv.URL), option.WithoutAuthentication())
+	if err != nil {
+		t.Fatalf("New() = %v", err)
+	}
+	t.Cleanup(func() { c.Close() })
+	return c, fake
+}
+
+func TestCloud(t *testing.T) {
+	ctx := context.Background()
+	c, fake := newCloud(t)
+
+	key := meta.GlobalKey("bs")
+	if _, err := c.BackendServices().Get(ctx, key); !cloud.IsNotFound(err) {
+		t.Fatalf("Get() = %v, want NotFound", err)
+	}
+	bs := &ga.BackendService{
+		Description: "desc",
+		TimeoutSec:  30,
+		Backends:    []*ga.Backend{{Group: "ig", BalancingMode: "UTILIZATION"}},
+	}
+	if err := c.BackendServices().Insert(cloud.WithRequestReason(ctx, "test"), key, bs); err != nil {
+		t.Fatalf("Insert() = %v", err)
+	}
+	if got := fake.headers[len(fake.headers)-2].Get("X-Goog-Request-Reason"); got != "test" {
+		t.Errorf("Insert X-Goog-Request-Reason = %q, want %q", got, "test")
+	}
+
+	got, err := c.BackendServices().Get(ctx, key)
+	if err != nil {
+		t.Fatalf("Get() = %v", err)
+	}
+	if got.Name != "bs" || got.Description != "desc" || got.TimeoutSec != 30 || len(got.Backends) != 1 || got.Backends[0].BalancingMode != "UTILIZATION" {
+		t.Errorf("Get() = %+v, want %+v", got, bs)
+	}
+
+	objs, err := c.BackendServices().List(ctx, filter.None)
+	if err != nil || len(objs) != 1 {
+		t.Errorf("List() = %v, %v; want 1 object", objs, err)
+	}
+
+	got.Description = "updated"
+	if err := c.BackendServices().Update(ctx, key, got); err != nil {
+		t.Fatalf("Update() = %v", err)
+	}
+	if got, err := c.BackendServices().Get(ctx, key); err != nil || got.Description != "updated" {
+		t.Errorf("Get() after Update() = %+v, %v; want Description %q", got, err, "updated")
+	}
+	got.Description = "patched"
+	if err := c.BackendServices().Patch(ctx, key, got); err != nil {
+		t.Fatalf("Patch() = %v", err)
+	}
+	if got, err := c.BackendServices().Get(ctx, key); err != nil || got.Description != "patched" {
+		t.Errorf("Get() after Patch() = %+v, %v; want Description %q", got, err, "patched")
+	}
+
+	if err := c.BackendServices().Delete(ctx, key); err != nil {
+		t.Fatalf("Delete() = %v", err)
+	}
+	if _, err := c.BackendServices().Get(ctx, key); !cloud.IsNotFound(err) {
+		t.Errorf("Get() after Delete() = %v, want NotFound", err)
+	}
+
+	if err := c.BackendServices().Insert(ctx, key, bs, cloud.ValidateOnly()); err == nil {
+		t.Error("Insert(ValidateOnly) = nil, want error")
+	}
+	if err := c.BackendServices().Insert(ctx, meta.RegionalKey("bs", "us-central1"), bs); err == nil {
+		t.Error("Insert(regional key) = nil, want error")
+	}
+	if err := c.BackendServices().Update(ctx, meta.GlobalKey("missing"), bs); err == nil {
+		t.Error("Update(missing) = nil, want error")
+	}
+}
+
+func TestHealthChecks(t *testing.T) {
+	ctx := context.Background()
+	c, _ := newCloud(t)
+
+	key := meta.GlobalKey("hc")
+	if _, err := c.HealthChecks().Get(ctx, key); !cloud.IsNotFound(err) {
+		t.Fatalf("Get() = %v, want NotFound", err)
+	}
+	hc := &ga.HealthCheck{
+		Type:             "TCP",
+		CheckIntervalSec: 5,
+		TcpHealthCheck:   &ga.TCPHealthCheck{Port: 80},
+	}
+	if err := c.HealthChecks().Insert(ctx, key, hc); err != nil {
+		t.Fatalf("Insert() = %v", err)
+	}
+	got, err := c.HealthChecks().Get(ctx, key)
+	if err != nil {
+		t.Fatalf("Get() = %v", err)
+	}
+	if got.Name != "hc" || got.Type != "TCP" || got.CheckIntervalSec != 5 || got.TcpHealthCheck == nil || got.TcpHealthCheck.Port != 80 {
+		t.Errorf("Get() = %+v, want %+v", got, hc)
+	}
+
+	objs, err := c.HealthChecks().List(ctx, filter.Regexp("name", "hc"))
+	if err != nil || len(objs) != 1 {
+		t.Errorf("List() = %v, %v; want 1 object", objs, err)
+	}
+
+	got.CheckIntervalSec = 10
+	if err := c.HealthChecks().Update(ctx, key, got); err != nil {
+		t.Fatalf("Update() = %v", err)
+	}
+	if got, err := c.HealthChecks().Get(ctx, key); err != nil || got.CheckIntervalSec != 10 {
+		t.Errorf("Get() after Update() = %+v, %v; want CheckIntervalSec 10", got, err)
+	}
+
+	if err := c.HealthChecks().Delete(ctx, key); err != nil {
+		t.Fatalf("Delete() = %v", err)
+	}
+	if _, err := c.HealthChecks().Get(ctx, key); !cloud.IsNotFound(err) {
+		t.Errorf("Get() after Delete() = %v, want NotFound", err)
+	}
+
+	regional := meta.RegionalKey("hc", "us-central1")
+	if _, err := c.HealthChecks().Get(ctx, regional); err == nil {
+		t.Error("Get(regional key) = nil, want error")
+	}
+	if err := c.HealthChecks().Insert(ctx, regional, hc); err == nil {
+		t.Error("Insert(regional key) = nil, want error")
+	}
+	if err := c.HealthChecks().Update(ctx, regional, hc); err == nil {
+		t.Error("Update(regional key) = nil, want error")
+	}
+	if err := c.HealthChecks().Delete(ctx, regional); err == nil {
+		t.Error("Delete(regional key) = nil, want error")
+	}
+}
+
+func TestNullFields(t *testing.T) {
+	ctx := context.Background()
+	c, _ := newCloud(t)
+	key := meta.GlobalKey("bs")
+
+	for _, tc := range []struct {
+		name string
+		cl   cloud.Cloud
+		// patchErr is true if Patch cannot clear the field.
+		patchErr bool
+	}{
+		{name: "google.golang.org/api", cl: c.Cloud},
+		{name: "gapic", cl: c, patchErr: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			insert := func() {
+				t.Helper()
+				c.BackendServices().Delete(ctx, key)
+				if err := tc.cl.BackendServices().Insert(ctx, key, &ga.BackendService{Description: "desc", TimeoutSec: 30}); err != nil {
+					t.Fatalf("Insert() = %v", err)
+				}
+			}
+			clear := &ga.BackendService{Name: "bs", TimeoutSec: 30, NullFields: []string{"Description"}}
+
+			insert()
+			if err := tc.cl.BackendServices().Update(ctx, key, clear); err != nil {
+				t.Fatalf("Update() = %v", err)
+			}
+			if got, err := tc.cl.BackendServices().Get(ctx, key); err != nil || got.Description != "" || got.TimeoutSec != 30 {
+				t.Errorf("Get() after Update() = %+v, %v; want Description cleared", got, err)
+			}
+
+			insert()
+			err := tc.cl.BackendServices().Patch(ctx, key, clear)
+			if gotErr := err != nil; gotErr != tc.patchErr {
+				t.Fatalf("Patch() = %v; gotErr = %t, want %t", err, gotErr, tc.patchErr)
+			}
+			got, err := tc.cl.BackendServices().Get(ctx, key)
+			if err != nil {
+				t.Fatalf("Get() = %v", err)
+			}
+			// A refused Patch leaves the resource unchanged.
+			if want := map[bool]string{false: "", true: "desc"}[tc.patchErr]; got.Description != want {
+				t.Errorf("Get() after Patch() = %+v; want Description %q", got, want)
+			}
+		})
+	}
+}
+
+func TestOperationWarnings(t *testing.T) {
+	c, fake := newCloud(t)
+	fake.warnings = []map[string]any{{"code": "DEPRECATED_RESOURCE_USED", "message": "deprecated"}}
+
+	var got []cloud.OperationWarning
+	ctx := cloud.WithOperationWarningHandler(context.Background(), func(_ context.Context, w []cloud.OperationWarning) {
+		got = append(got, w...)
+	})
+	if err := c.HealthChecks().Insert(ctx, meta.GlobalKey("hc"), &ga.HealthCheck{Type: "TCP"}); err != nil {
+		t.Fatalf("Insert() = %v", err)
+	}
+	want := []cloud.OperationWarning{{Code: "DEPRECATED_RESOURCE_USED", Message: "deprecated"}}
+	if len(got) != 1 || got[0] != want[0] {
+		t.Errorf("warnings = %v, want %v", got, want)
+	}
+}
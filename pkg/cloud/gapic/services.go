@@ -0,0 +1,215 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gapic
+
+import (
+	"context"
+
+	compute "cloud.google.com/go/compute/apiv1"
+	"cloud.google.com/go/compute/apiv1/computepb"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/filter"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	ga "google.golang.org/api/compute/v1"
+	"google.golang.org/api/iterator"
+	"google.golang.org/protobuf/proto"
+)
+
+// listFilter returns the filter for a List request.
+func listFilter(fl *filter.F) *string {
+	if fl == filter.None {
+		return nil
+	}
+	return proto.String(fl.String())
+}
+
+type backendServices struct {
+	cloud.BackendServices
+	c *Cloud
+}
+
+func (g *backendServices) Get(ctx context.Context, key *meta.Key, options ...cloud.Option) (*ga.BackendService, error) {
+	if err := checkGlobal(key); err != nil {
+		return nil, err
+	}
+	var ret *ga.BackendService
+	err := g.c.do(ctx, "BackendServices", "Get", options, func(ctx context.Context, project string) error {
+		pb, err := g.c.backendServices.Get(ctx, &computepb.GetBackendServiceRequest{Project: project, BackendService: key.Name})
+		if err != nil {
+			return err
+		}
+		ret, err = fromProto[ga.BackendService](pb)
+		return err
+	})
+	return ret, err
+}
+
+func (g *backendServices) List(ctx context.Context, fl *filter.F, options ...cloud.Option) ([]*ga.BackendService, error) {
+	var ret []*ga.BackendService
+	err := g.c.do(ctx, "BackendServices", "List", options, func(ctx context.Context, project string) error {
+		it := g.c.backendServices.List(ctx, &computepb.ListBackendServicesRequest{Project: project, Filter: listFilter(fl)})
+		for {
+			pb, err := it.Next()
+			if err == iterator.Done {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			obj, err := fromProto[ga.BackendService](pb)
+			if err != nil {
+				return err
+			}
+			ret = append(ret, obj)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+func (g *backendServices) Insert(ctx context.Context, key *meta.Key, obj *ga.BackendService, options ...cloud.Option) error {
+	if err := checkGlobal(key); err != nil {
+		return err
+	}
+	obj.Name = key.Name
+	pb, err := toProto(obj, &computepb.BackendService{})
+	if err != nil {
+		return err
+	}
+	return g.c.mutate(ctx, "BackendServices", "Insert", options, func(ctx context.Context, project string) (*compute.Operation, error) {
+		return g.c.backendServices.Insert(ctx, &computepb.InsertBackendServiceRequest{Project: project, BackendServiceResource: pb})
+	})
+}
+
+func (g *backendServices) Delete(ctx context.Context, key *meta.Key, options ...cloud.Option) error {
+	if err := checkGlobal(key); err != nil {
+		return err
+	}
+	return g.c.mutate(ctx, "BackendServices", "Delete", options, func(ctx context.Context, project string) (*compute.Operation, error) {
+		return g.c.backendServices.Delete(ctx, &computepb.DeleteBackendServiceRequest{Project: project, BackendService: key.Name})
+	})
+}
+
+func (g *backendServices) Update(ctx context.Context, key *meta.Key, obj *ga.BackendService, options ...cloud.Option) error {
+	if err := checkGlobal(key); err != nil {
+		return err
+	}
+	pb, err := toProto(obj, &computepb.BackendService{})
+	if err != nil {
+		return err
+	}
+	return g.c.mutate(ctx, "BackendServices", "Update", options, func(ctx context.Context, project string) (*compute.Operation, error) {
+		return g.c.backendServices.Update(ctx, &computepb.UpdateBackendServiceRequest{Project: project, BackendService: key.Name, BackendServiceResource: pb})
+	})
+}
+
+func (g *backendServices) Patch(ctx context.Context, key *meta.Key, obj *ga.BackendService, options ...cloud.Option) error {
+	if err := checkGlobal(key); err != nil {
+		return err
+	}
+	pb, err := toPatchProto(obj, &computepb.BackendService{})
+	if err != nil {
+		return err
+	}
+	return g.c.mutate(ctx, "BackendServices", "Patch", options, func(ctx context.Context, project string) (*compute.Operation, error) {
+		return g.c.backendServices.Patch(ctx, &computepb.PatchBackendServiceRequest{Project: project, BackendService: key.Name, BackendServiceResource: pb})
+	})
+}
+
+type healthChecks struct {
+	cloud.HealthChecks
+	c *Cloud
+}
+
+func (g *healthChecks) Get(ctx context.Context, key *meta.Key, options ...cloud.Option) (*ga.HealthCheck, error) {
+	if err := checkGlobal(key); err != nil {
+		return nil, err
+	}
+	var ret *ga.HealthCheck
+	err := g.c.do(ctx, "HealthChecks", "Get", options, func(ctx context.Context, project string) error {
+		pb, err := g.c.healthChecks.Get(ctx, &computepb.GetHealthCheckRequest{Project: project, HealthCheck: key.Name})
+		if err != nil {
+			return err
+		}
+		ret, err = fromProto[ga.HealthCheck](pb)
+		return err
+	})
+	return ret, err
+}
+
+func (g *healthChecks) List(ctx context.Context, fl *filter.F, options ...cloud.Option) ([]*ga.HealthCheck, error) {
+	var ret []*ga.HealthCheck
+	err := g.c.do(ctx, "HealthChecks", "List", options, func(ctx context.Context, project string) error {
+		it := g.c.healthChecks.List(ctx, &computepb.ListHealthChecksRequest{Project: project, Filter: listFilter(fl)})
+		for {
+			pb, err := it.Next()
+			if err == iterator.Done {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			obj, err := fromProto[ga.HealthCheck](pb)
+			if err != nil {
+				return err
+			}
+			ret = append(ret, obj)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+func (g *healthChecks) Insert(ctx context.Context, key *meta.Key, obj *ga.HealthCheck, options ...cloud.Option) error {
+	if err := checkGlobal(key); err != nil {
+		return err
+	}
+	obj.Name = key.Name
+	pb, err := toProto(obj, &computepb.HealthCheck{})
+	if err != nil {
+		return err
+	}
+	return g.c.mutate(ctx, "HealthChecks", "Insert", options, func(ctx context.Context, project string) (*compute.Operation, error) {
+		return g.c.healthChecks.Insert(ctx, &computepb.InsertHealthCheckRequest{Project: project, HealthCheckResource: pb})
+	})
+}
+
+func (g *healthChecks) Delete(ctx context.Context, key *meta.Key, options ...cloud.Option) error {
+	if err := checkGlobal(key); err != nil {
+		return err
+	}
+	return g.c.mutate(ctx, "HealthChecks", "Delete", options, func(ctx context.Context, project string) (*compute.Operation, error) {
+		return g.c.healthChecks.Delete(ctx, &computepb.DeleteHealthCheckRequest{Project: project, HealthCheck: key.Name})
+	})
+}
+
+func (g *healthChecks) Update(ctx context.Context, key *meta.Key, obj *ga.HealthCheck, options ...cloud.Option) error {
+	if err := checkGlobal(key); err != nil {
+		return err
+	}
+	pb, err := toProto(obj, &computepb.HealthCheck{})
+	if err != nil {
+		return err
+	}
+	return g.c.mutate(ctx, "HealthChecks", "Update", options, func(ctx context.Context, project string) (*compute.Operation, error) {
+		return g.c.healthChecks.Update(ctx, &computepb.UpdateHealthCheckRequest{Project: project, HealthCheck: key.Name, HealthCheckResource: pb})
+	})
+}
@@ -108,6 +108,7 @@ import (
 	"fmt"
 	"net/http"
 	"sync"
+	"time"
 
 	"{{.GoogleAPIPackage}}"
 	"{{.KLogPackage}}"
@@ -187,6 +188,10 @@ type Cloud interface {
 {{- range .All}}
 	{{.WrapType}}() {{.WrapType}}
 {{- end}}
+	// ServiceDirectoryServices is implemented by hand in servicedirectory.go.
+	ServiceDirectoryServices() ServiceDirectoryServices
+	// ResourceURLs is implemented by hand in resourceurls.go.
+	ResourceURLs() ResourceURLs
 }
 
 // NewGCE returns a GCE.
@@ -195,6 +200,8 @@ func NewGCE(s *Service) *GCE {
 	{{- range .All}}
 		{{.Field}}: &{{.GCPWrapType}}{s},
 	{{- end}}
+		gceServiceDirectoryServices: &GCEServiceDirectoryServices{s},
+		gceResourceURLs:             &GCEResourceURLs{s},
 	}
 	return g
 }
@@ -207,6 +214,8 @@ type GCE struct {
 {{- range .All}}
 	{{.Field}} *{{.GCPWrapType}}
 {{- end}}
+	gceServiceDirectoryServices *GCEServiceDirectoryServices
+	gceResourceURLs             *GCEResourceURLs
 }
 
 {{range .All}}
@@ -226,6 +235,8 @@ func NewMockGCE(projectRouter ProjectRouter) *MockGCE {
 	{{- range .All}}
 		{{.MockField}}: New{{.MockWrapType}}(projectRouter, mock{{.Service}}Objs),
 	{{- end}}
+		MockServiceDirectoryServices: NewMockServiceDirectoryServices(projectRouter),
+		MockResourceURLs:             NewMockResourceURLs(),
 	}
 	return mock
 }
@@ -233,11 +244,22 @@ func NewMockGCE(projectRouter ProjectRouter) *MockGCE {
 // MockGCE implements Cloud.
 var _ Cloud = (*MockGCE)(nil)
 
+// SetListDelay sets the ListDelay of all of the mocks. See MockListDelay.
+func (mock *MockGCE) SetListDelay(d *MockListDelay) {
+{{- range .All}}
+{{- if .GenerateInsert}}
+	mock.{{.MockField}}.ListDelay = d
+{{- end}}
+{{- end}}
+}
+
 // MockGCE is the mock for the compute API.
 type MockGCE struct {
 {{- range .All}}
 	{{.MockField}} *{{.MockWrapType}}
 {{- end}}
+	MockServiceDirectoryServices *MockServiceDirectoryServices
+	MockResourceURLs             *MockResourceURLs
 }
 {{range .All}}
 // {{.WrapType}} returns the interface for the {{.Version}} {{.Service}}.
@@ -252,6 +274,14 @@ func (mock *MockGCE) {{.WrapType}}() {{.WrapType}} {
 // share the same "view" of the objects in the backend.
 type Mock{{.Service}}Obj struct {
 	Obj interface{}
+
+	listState mockListState
+}
+
+// Update replaces the object with obj. Fields that are not present in the
+// API version of obj are kept from the stored object.
+func (m *Mock{{.Service}}Obj) Update(obj interface{}) {
+	m.Obj = mockMergeVersions(m.Obj, obj)
 }
 {{- if .HasAlpha}}
 // ToAlpha retrieves the given version of the object.
@@ -264,6 +294,9 @@ func (m *Mock{{.Service}}Obj) ToAlpha() *{{.Alpha.FQObjectType}} {
 	if err := copyViaJSON(ret, m.Obj); err != nil {
 		klog.Errorf("Could not convert %T to *{{.Alpha.FQObjectType}} via JSON: %v", m.Obj, err)
 	}
+{{- if .Alpha.HasSelfLink}}
+	ret.SelfLink = mockSelfLink(ret.SelfLink, meta.VersionAlpha)
+{{- end}}
 	return ret
 }
 {{- end}}
@@ -278,6 +311,9 @@ func (m *Mock{{.Service}}Obj) ToBeta() *{{.Beta.FQObjectType}} {
 	if err := copyViaJSON(ret, m.Obj); err != nil {
 		klog.Errorf("Could not convert %T to *{{.Beta.FQObjectType}} via JSON: %v", m.Obj, err)
 	}
+{{- if .Beta.HasSelfLink}}
+	ret.SelfLink = mockSelfLink(ret.SelfLink, meta.VersionBeta)
+{{- end}}
 	return ret
 }
 {{- end}}
@@ -287,11 +323,14 @@ func (m *Mock{{.Service}}Obj) ToGA() *{{.GA.FQObjectType}} {
 	if ret, ok := m.Obj.(*{{.GA.FQObjectType}}); ok {
 		return ret
 	}
-		// Convert the object via JSON copying to the type that was requested.
+	// Convert the object via JSON copying to the type that was requested.
 	ret := &{{.GA.FQObjectType}}{}
 	if err := copyViaJSON(ret, m.Obj); err != nil {
 		klog.Errorf("Could not convert %T to *{{.GA.FQObjectType}} via JSON: %v", m.Obj, err)
 	}
+{{- if .GA.HasSelfLink}}
+	ret.SelfLink = mockSelfLink(ret.SelfLink, meta.VersionGA)
+{{- end}}
 	return ret
 }
 {{- end}}
@@ -410,6 +449,12 @@ type {{.MockWrapType}} struct {
 	{{- if .ListUsable}}
 	ListUsableError *error
 	{{- end}}
+	{{- if .GenerateInsert}}
+
+	// ListDelay delays the objects inserted by the mock from appearing in
+	// the lists. See MockListDelay.
+	ListDelay *MockListDelay
+	{{- end}}
 
 	// xxxHook allow you to intercept the standard processing of the mock in
 	// order to add your own logic. Return (true, _, _) to prevent the normal
@@ -555,6 +600,9 @@ func (m *{{.MockWrapType}}) List(ctx context.Context, zone string, fl *filter.F,
 		if !fl.Match(obj.To{{.VersionTitle}}()) {
 			continue
 		}
+		if !obj.listState.visible() {
+			continue
+		}
 		objs = append(objs, obj.To{{.VersionTitle}}())
 	}
 
@@ -584,6 +632,13 @@ func (m *{{.MockWrapType}}) Insert(ctx context.Context, key *meta.Key, obj *{{.F
 	if !key.Valid() {
 		return fmt.Errorf("invalid GCE key (%+v)", key)
 	}
+{{- if not .ValidateOnly}}
+	if opts.validateOnly {
+		err := fmt.Errorf("{{.MockWrapType}}.Insert: ValidateOnly is not supported")
+		klog.V(5).Infof("{{.MockWrapType}}.Insert(%v, %v, %+v) = %v", ctx, key, obj, err)
+		return err
+	}
+{{- end}}
 
 	m.Lock.Lock()
 	defer m.Lock.Unlock()
@@ -600,12 +655,20 @@ func (m *{{.MockWrapType}}) Insert(ctx context.Context, key *meta.Key, obj *{{.F
 		klog.V(5).Infof("{{.MockWrapType}}.Insert(%v, %v, %+v) = %v", ctx, key, obj, err)
 		return err
 	}
+{{- if .ValidateOnly}}
+	if opts.validateOnly {
+		klog.V(5).Infof("{{.MockWrapType}}.Insert(%v, %v, %+v) = nil (validateOnly)", ctx, key, obj)
+		return nil
+	}
+{{- end}}
 
 	obj.Name = key.Name
+{{- if .HasSelfLink}}
 	projectID := getProjectID(ctx, m.ProjectRouter, opts, "{{.Version}}", "{{.Resource}}")
 	obj.SelfLink = SelfLinkWithGroup("{{.APIGroup}}", meta.Version{{.VersionTitle}}, projectID, "{{.Resource}}", key)
+{{- end}}
 
-	m.Objects[*key] = &Mock{{.Service}}Obj{obj}
+	m.Objects[*key] = &Mock{{.Service}}Obj{Obj: obj, listState: newMockListState(m.ListDelay)}
 	klog.V(5).Infof("{{.MockWrapType}}.Insert(%v, %v, %+v) = nil", ctx, key, obj)
 	return nil
 }
@@ -675,6 +738,9 @@ func (m *{{.MockWrapType}}) AggregatedList(ctx context.Context, fl *filter.F, op
 		if !fl.Match(obj.To{{.VersionTitle}}()) {
 			continue
 		}
+		if !obj.listState.visible() {
+			continue
+		}
         location := aggregatedListKey(res.Key)
 		objs[location] = append(objs[location], obj.To{{.VersionTitle}}())
 	}
@@ -723,7 +789,7 @@ func (m *{{.MockWrapType}}) ListUsable(ctx context.Context, fl *filter.F, option
 
 // Obj wraps the object for use in the mock.
 func (m *{{.MockWrapType}}) Obj(o *{{.FQObjectType}}) *Mock{{.Service}}Obj {
-	return &Mock{{.Service}}Obj{o}
+	return &Mock{{.Service}}Obj{Obj: o}
 }
 
 {{with .Methods -}}
@@ -779,6 +845,7 @@ func (g *{{.GCPWrapType}}) Get(ctx context.Context, key *meta.Key, options... Op
 		klog.V(4).Infof("{{.GCPWrapType}}.Get(%v, %v): RateLimiter error: %v", ctx, key, err)
 		return nil, err
 	}
+	start := time.Now()
 {{- if .IsNetworkServices}}
     name := fmt.Sprintf("{{.NetworkServicesFmt}}", projectID, key.Name)
 	call := g.s.{{.GroupVersionTitle}}.{{.Service}}.Get(name)
@@ -793,11 +860,15 @@ func (g *{{.GCPWrapType}}) Get(ctx context.Context, key *meta.Key, options... Op
 		call := g.s.{{.GroupVersionTitle}}.{{.Service}}.Get(projectID, key.Zone, key.Name)
 	{{- end}}
 {{- end}}
+	setCallHeaders(ctx, call.Header(), opts)
+	if opts.fields != "" {
+		call.Fields(googleapi.Field(opts.fields))
+	}
 	call.Context(ctx)
 	v, err := call.Do()
 	klog.V(4).Infof("{{.GCPWrapType}}.Get(%v, %v) = %+v, %v", ctx, key, v, err)
 
-	callObserverEnd(ctx, ck, err)
+	g.s.endCall(ctx, ck, g.s.callStats(start, nil, v), err)
 	g.s.RateLimiter.Observe(ctx, err, ck)
 
 	return v, err
@@ -834,6 +905,7 @@ func (g *{{.GCPWrapType}}) List(ctx context.Context, zone string, fl *filter.F,
 	if err := g.s.RateLimiter.Accept(ctx, ck); err != nil {
 		return nil, err
 	}
+	start := time.Now()
 
 {{- if .KeyIsGlobal}}
 	klog.V(5).Infof("{{.GCPWrapType}}.List(%v, %v): projectID = %v, ck = %+v", ctx, fl, projectID, ck)
@@ -852,6 +924,10 @@ func (g *{{.GCPWrapType}}) List(ctx context.Context, zone string, fl *filter.F,
 		call.Filter(fl.String())
 	}
 {{- end}}
+	setCallHeaders(ctx, call.Header(), opts)
+	if opts.fields != "" {
+		call.Fields(listFields(opts.fields, "{{.ListItemName}}"))
+	}
 
 	var all []*{{.FQObjectType}}
 	f := func(l *{{.ObjectListType}}) error {
@@ -860,14 +936,14 @@ func (g *{{.GCPWrapType}}) List(ctx context.Context, zone string, fl *filter.F,
 		return nil
 	}
 	if err := call.Pages(ctx, f); err != nil {
-		callObserverEnd(ctx, ck, err)
+		g.s.endCall(ctx, ck, g.s.callStats(start, nil, nil), err)
 		g.s.RateLimiter.Observe(ctx, err, ck)
 
 		klog.V(4).Infof("{{.GCPWrapType}}.List(%v, ..., %v) = %v, %v", ctx, fl, nil, err)
 		return nil, err
 	}
 
-        callObserverEnd(ctx, ck, nil)
+        g.s.endCall(ctx, ck, g.s.callStats(start, nil, all), nil)
 	g.s.RateLimiter.Observe(ctx, nil, ck)
 
 	if kLogEnabled(4) {
@@ -893,6 +969,12 @@ func (g *{{.GCPWrapType}}) Insert(ctx context.Context, key *meta.Key, obj *{{.FQ
 		klog.V(2).Infof("{{.GCPWrapType}}.Insert(%v, %v, ...): key is invalid (%#v)", ctx, key, key)
 		return fmt.Errorf("invalid GCE key (%+v)", key)
 	}
+{{- if not .ValidateOnly}}
+	if opts.validateOnly {
+		klog.V(2).Infof("{{.GCPWrapType}}.Insert(%v, %v, ...): ValidateOnly is not supported", ctx, key)
+		return fmt.Errorf("{{.GCPWrapType}}.Insert: ValidateOnly is not supported")
+	}
+{{- end}}
 
     projectID := getProjectID(ctx, g.s.ProjectRouter, opts, "{{.Version}}", "{{.Service}}")
 
@@ -912,6 +994,7 @@ func (g *{{.GCPWrapType}}) Insert(ctx context.Context, key *meta.Key, obj *{{.FQ
 		klog.V(4).Infof("{{.GCPWrapType}}.Insert(%v, %v, ...): RateLimiter error: %v", ctx, key, err)
 		return err
 	}
+	start := time.Now()
 	obj.Name = key.Name
 
 {{- if .IsNetworkServices}}
@@ -931,17 +1014,28 @@ func (g *{{.GCPWrapType}}) Insert(ctx context.Context, key *meta.Key, obj *{{.FQ
 	call := g.s.{{.GroupVersionTitle}}.{{.Service}}.Insert(projectID, key.Zone, obj)
 	{{- end}}
 {{- end}}
+	setCallHeaders(ctx, call.Header(), opts)
 	call.Context(ctx)
 
+{{- if .ValidateOnly}}
+	op, err := call.Do(callOptions(opts)...)
+{{- else}}
 	op, err := call.Do()
+{{- end}}
 
-	callObserverEnd(ctx, ck, err)
+	g.s.endCall(ctx, ck, g.s.callStats(start, obj, op), err)
 	g.s.RateLimiter.Observe(ctx, err, ck)
 
 	if err != nil {
 		klog.V(4).Infof("{{.GCPWrapType}}.Insert(%v, %v, ...) = %+v", ctx, key, err)
 		return err
 	}
+{{- if .ValidateOnly}}
+	if opts.validateOnly {
+		klog.V(4).Infof("{{.GCPWrapType}}.Insert(%v, %v, ...) = nil (validateOnly)", ctx, key)
+		return nil
+	}
+{{- end}}
 
 	err = g.s.WaitForCompletion(ctx, op)
 	klog.V(4).Infof("{{.GCPWrapType}}.Insert(%v, %v, %+v) = %+v", ctx, key, obj, err)
@@ -972,6 +1066,7 @@ func (g *{{.GCPWrapType}}) Delete(ctx context.Context, key *meta.Key, options...
 		klog.V(4).Infof("{{.GCPWrapType}}.Delete(%v, %v): RateLimiter error: %v", ctx, key, err)
 		return err
 	}
+	start := time.Now()
 {{- if .IsNetworkServices}}
 	name := fmt.Sprintf("{{.NetworkServicesFmt}}", projectID, key.Name)
 	call := g.s.{{.GroupVersionTitle}}.{{.Service}}.Delete(name)
@@ -987,11 +1082,12 @@ func (g *{{.GCPWrapType}}) Delete(ctx context.Context, key *meta.Key, options...
 	{{- end}}
 {{- end}}
 
+	setCallHeaders(ctx, call.Header(), opts)
 	call.Context(ctx)
 
 	op, err := call.Do()
 
-	callObserverEnd(ctx, ck, err)
+	g.s.endCall(ctx, ck, g.s.callStats(start, nil, op), err)
 	g.s.RateLimiter.Observe(ctx, err, ck)
 
 	if err != nil {
@@ -1025,8 +1121,10 @@ func (g *{{.GCPWrapType}}) AggregatedList(ctx context.Context, fl *filter.F, opt
 		klog.V(5).Infof("{{.GCPWrapType}}.AggregatedList(%v, %v): RateLimiter error: %v", ctx, fl, err)
 		return nil, err
 	}
+	start := time.Now()
 
 	call := g.s.{{.GroupVersionTitle}}.{{.Service}}.AggregatedList(projectID)
+	setCallHeaders(ctx, call.Header(), opts)
 	call.Context(ctx)
 	if fl != filter.None {
 		call.Filter(fl.String())
@@ -1041,13 +1139,13 @@ func (g *{{.GCPWrapType}}) AggregatedList(ctx context.Context, fl *filter.F, opt
 		return nil
 	}
 	if err := call.Pages(ctx, f); err != nil {
-		callObserverEnd(ctx, ck, err)
+		g.s.endCall(ctx, ck, g.s.callStats(start, nil, nil), err)
 		g.s.RateLimiter.Observe(ctx, err, ck)
 
 		klog.V(4).Infof("{{.GCPWrapType}}.AggregatedList(%v, %v) = %v, %v", ctx, fl, nil, err)
 		return nil, err
 	}
-	callObserverEnd(ctx, ck, nil)
+	g.s.endCall(ctx, ck, g.s.callStats(start, nil, all), nil)
 	g.s.RateLimiter.Observe(ctx, nil, ck)
 
 	if kLogEnabled(4) {
@@ -1079,9 +1177,11 @@ func (g *{{.GCPWrapType}}) ListUsable(ctx context.Context, fl *filter.F, options
 	if err := g.s.RateLimiter.Accept(ctx, ck); err != nil {
 		return nil, err
 	}
+	start := time.Now()
 
 	klog.V(5).Infof("{{.GCPWrapType}}.ListUsable(%v, %v): projectID = %v, ck = %+v", ctx, fl, projectID, ck)
 	call := g.s.{{.GroupVersionTitle}}.{{.Service}}.ListUsable(projectID)
+	setCallHeaders(ctx, call.Header(), opts)
 	if fl != filter.None {
 		call.Filter(fl.String())
 	}
@@ -1092,13 +1192,13 @@ func (g *{{.GCPWrapType}}) ListUsable(ctx context.Context, fl *filter.F, options
 		return nil
 	}
 	if err := call.Pages(ctx, f); err != nil {
-		callObserverEnd(ctx, ck, err)
+		g.s.endCall(ctx, ck, g.s.callStats(start, nil, nil), err)
 		g.s.RateLimiter.Observe(ctx, err, ck)
 
 		klog.V(4).Infof("{{.GCPWrapType}}.ListUsable(%v, ..., %v) = %v, %v", ctx, fl, nil, err)
 		return nil, err
 	}
-	callObserverEnd(ctx, ck, nil)
+	g.s.endCall(ctx, ck, g.s.callStats(start, nil, all), nil)
 	g.s.RateLimiter.Observe(ctx, nil, ck)
 
 	if kLogEnabled(4) {
@@ -1149,10 +1249,16 @@ func (g *{{.GCPWrapType}}) {{.FcnArgs}} {
 		return nil, err
 	{{- end}}
 	}
+	start := time.Now()
 
 {{- if .IsNetworkServices}}
     name := fmt.Sprintf("{{.NetworkServicesFmt}}", projectID, key.Name)
 	call := g.s.{{.GroupVersionTitle}}.{{.Service}}.{{.Name}}(name {{.CallArgs}})
+	{{- if eq .Name "Patch"}}
+	if opts.updateMask != "" {
+		call.UpdateMask(opts.updateMask)
+	}
+	{{- end}}
 {{- else}}
 	{{- if .KeyIsGlobal}}
 	call := g.s.{{.GroupVersionTitle}}.{{.Service}}.{{.Name}}(projectID, key.Name {{.CallArgs}})
@@ -1165,11 +1271,14 @@ func (g *{{.GCPWrapType}}) {{.FcnArgs}} {
 	{{- end}}
 {{- end}}
 {{- if .IsOperation}}
+	setCallHeaders(ctx, call.Header(), opts)
 	call.Context(ctx)
 	op, err := call.Do()
+	// The stats do not include waiting for the operation.
+	stats := g.s.callStats(start, {{.RequestArg}}, op)
 
 	if err != nil {
-		callObserverEnd(ctx, ck, err)
+		g.s.endCall(ctx, ck, stats, err)
 		g.s.RateLimiter.Observe(ctx, err, ck)
 
 		klog.V(4).Infof("{{.GCPWrapType}}.{{.Name}}(%v, %v, ...) = %+v", ctx, key, err)
@@ -1177,21 +1286,23 @@ func (g *{{.GCPWrapType}}) {{.FcnArgs}} {
 	}
 
 	err = g.s.WaitForCompletion(ctx, op)
-        callObserverEnd(ctx, ck, err)
+        g.s.endCall(ctx, ck, stats, err)
 	g.s.RateLimiter.Observe(ctx, err, ck) // XXX
 
 	klog.V(4).Infof("{{.GCPWrapType}}.{{.Name}}(%v, %v, ...) = %+v", ctx, key, err)
 	return err
 {{- else if .IsGet}}
+	setCallHeaders(ctx, call.Header(), opts)
 	call.Context(ctx)
 	v, err := call.Do()
 
-        callObserverEnd(ctx, ck, err)
+        g.s.endCall(ctx, ck, g.s.callStats(start, {{.RequestArg}}, v), err)
 	g.s.RateLimiter.Observe(ctx, err, ck)
 
 	klog.V(4).Infof("{{.GCPWrapType}}.{{.Name}}(%v, %v, ...) = %+v, %v", ctx, key, v, err)
 	return v, err
 {{- else if .IsPaged}}
+	setCallHeaders(ctx, call.Header(), opts)
 	var all []*{{.APIGroup}}{{.Version}}.{{.ItemType}}
 	f := func(l *{{.APIGroup}}{{.Version}}.{{.ReturnType}}) error {
 		klog.V(5).Infof("{{.GCPWrapType}}.{{.Name}}(%v, %v, ...): page %+v", ctx, key, l)
@@ -1199,14 +1310,14 @@ func (g *{{.GCPWrapType}}) {{.FcnArgs}} {
 		return nil
 	}
 	if err := call.Pages(ctx, f); err != nil {
-		callObserverEnd(ctx, ck, err)
+		g.s.endCall(ctx, ck, g.s.callStats(start, {{.RequestArg}}, nil), err)
 		g.s.RateLimiter.Observe(ctx, err, ck)
 
 		klog.V(4).Infof("{{.GCPWrapType}}.{{.Name}}(%v, %v, ...) = %v, %v", ctx, key, nil, err)
 		return nil, err
 	}
 
-        callObserverEnd(ctx, ck, nil)
+        g.s.endCall(ctx, ck, g.s.callStats(start, {{.RequestArg}}, all), nil)
 	g.s.RateLimiter.Observe(ctx, nil, ck)
 
 	if kLogEnabled(4) {
@@ -370,6 +370,105 @@ func TestDisksGroup(t *testing.T) {
 	}
 }
 
+func TestEndpointPoliciesGroup(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	pr := &SingleProjectRouter{"mock-project"}
+	mock := NewMockGCE(pr)
+
+	var key *meta.Key
+	keyBeta := meta.GlobalKey("key-beta")
+	key = keyBeta
+	keyGA := meta.GlobalKey("key-ga")
+	key = keyGA
+	// Ignore unused variables.
+	_, _, _ = ctx, mock, key
+
+	// Get not found.
+	if _, err := mock.BetaEndpointPolicies().Get(ctx, key); err == nil {
+		t.Errorf("BetaEndpointPolicies().Get(%v, %v) = _, nil; want error", ctx, key)
+	}
+	if _, err := mock.EndpointPolicies().Get(ctx, key); err == nil {
+		t.Errorf("EndpointPolicies().Get(%v, %v) = _, nil; want error", ctx, key)
+	}
+
+	// Insert.
+	{
+		obj := &networkservicesbeta.EndpointPolicy{}
+		if err := mock.BetaEndpointPolicies().Insert(ctx, keyBeta, obj); err != nil {
+			t.Errorf("BetaEndpointPolicies().Insert(%v, %v, %v) = %v; want nil", ctx, keyBeta, obj, err)
+		}
+	}
+	{
+		obj := &networkservicesga.EndpointPolicy{}
+		if err := mock.EndpointPolicies().Insert(ctx, keyGA, obj); err != nil {
+			t.Errorf("EndpointPolicies().Insert(%v, %v, %v) = %v; want nil", ctx, keyGA, obj, err)
+		}
+	}
+
+	// Get across versions.
+	if obj, err := mock.BetaEndpointPolicies().Get(ctx, key); err != nil {
+		t.Errorf("BetaEndpointPolicies().Get(%v, %v) = %v, %v; want nil", ctx, key, obj, err)
+	}
+	if obj, err := mock.EndpointPolicies().Get(ctx, key); err != nil {
+		t.Errorf("EndpointPolicies().Get(%v, %v) = %v, %v; want nil", ctx, key, obj, err)
+	}
+
+	// List.
+	mock.MockBetaEndpointPolicies.Objects[*keyBeta] = mock.MockBetaEndpointPolicies.Obj(&networkservicesbeta.EndpointPolicy{Name: keyBeta.Name})
+	mock.MockEndpointPolicies.Objects[*keyGA] = mock.MockEndpointPolicies.Obj(&networkservicesga.EndpointPolicy{Name: keyGA.Name})
+	want := map[string]bool{
+		"key-beta": true,
+		"key-ga":   true,
+	}
+	_ = want // ignore unused variables.
+	{
+		objs, err := mock.BetaEndpointPolicies().List(ctx, filter.None)
+		if err != nil {
+			t.Errorf("BetaEndpointPolicies().List(%v, %v, %v) = %v, %v; want _, nil", ctx, location, filter.None, objs, err)
+		} else {
+			got := map[string]bool{}
+			for _, obj := range objs {
+				got[obj.Name] = true
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("BetaEndpointPolicies().List(); got %+v, want %+v", got, want)
+			}
+		}
+	}
+	{
+		objs, err := mock.EndpointPolicies().List(ctx, filter.None)
+		if err != nil {
+			t.Errorf("EndpointPolicies().List(%v, %v, %v) = %v, %v; want _, nil", ctx, location, filter.None, objs, err)
+		} else {
+			got := map[string]bool{}
+			for _, obj := range objs {
+				got[obj.Name] = true
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("EndpointPolicies().List(); got %+v, want %+v", got, want)
+			}
+		}
+	}
+
+	// Delete across versions.
+	if err := mock.BetaEndpointPolicies().Delete(ctx, keyBeta); err != nil {
+		t.Errorf("BetaEndpointPolicies().Delete(%v, %v) = %v; want nil", ctx, keyBeta, err)
+	}
+	if err := mock.EndpointPolicies().Delete(ctx, keyGA); err != nil {
+		t.Errorf("EndpointPolicies().Delete(%v, %v) = %v; want nil", ctx, keyGA, err)
+	}
+
+	// Delete not found.
+	if err := mock.BetaEndpointPolicies().Delete(ctx, keyBeta); err == nil {
+		t.Errorf("BetaEndpointPolicies().Delete(%v, %v) = nil; want error", ctx, keyBeta)
+	}
+	if err := mock.EndpointPolicies().Delete(ctx, keyGA); err == nil {
+		t.Errorf("EndpointPolicies().Delete(%v, %v) = nil; want error", ctx, keyGA)
+	}
+}
+
 func TestFirewallsGroup(t *testing.T) {
 	t.Parallel()
 
@@ -1765,6 +1864,96 @@ func TestInstancesGroup(t *testing.T) {
 	}
 }
 
+func TestInterconnectAttachmentsGroup(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	pr := &SingleProjectRouter{"mock-project"}
+	mock := NewMockGCE(pr)
+
+	var key *meta.Key
+	keyAlpha := meta.RegionalKey("key-alpha", "location")
+	key = keyAlpha
+	keyBeta := meta.RegionalKey("key-beta", "location")
+	key = keyBeta
+	keyGA := meta.RegionalKey("key-ga", "location")
+	key = keyGA
+	// Ignore unused variables.
+	_, _, _ = ctx, mock, key
+
+	// Get not found.
+	if _, err := mock.AlphaInterconnectAttachments().Get(ctx, key); err == nil {
+		t.Errorf("AlphaInterconnectAttachments().Get(%v, %v) = _, nil; want error", ctx, key)
+	}
+	if _, err := mock.BetaInterconnectAttachments().Get(ctx, key); err == nil {
+		t.Errorf("BetaInterconnectAttachments().Get(%v, %v) = _, nil; want error", ctx, key)
+	}
+	if _, err := mock.InterconnectAttachments().Get(ctx, key); err == nil {
+		t.Errorf("InterconnectAttachments().Get(%v, %v) = _, nil; want error", ctx, key)
+	}
+
+	// Insert.
+
+	// Get across versions.
+
+	// List.
+	mock.MockAlphaInterconnectAttachments.Objects[*keyAlpha] = mock.MockAlphaInterconnectAttachments.Obj(&computealpha.InterconnectAttachment{Name: keyAlpha.Name})
+	mock.MockBetaInterconnectAttachments.Objects[*keyBeta] = mock.MockBetaInterconnectAttachments.Obj(&computebeta.InterconnectAttachment{Name: keyBeta.Name})
+	mock.MockInterconnectAttachments.Objects[*keyGA] = mock.MockInterconnectAttachments.Obj(&computega.InterconnectAttachment{Name: keyGA.Name})
+	want := map[string]bool{
+		"key-alpha": true,
+		"key-beta":  true,
+		"key-ga":    true,
+	}
+	_ = want // ignore unused variables.
+	{
+		objs, err := mock.AlphaInterconnectAttachments().List(ctx, location, filter.None)
+		if err != nil {
+			t.Errorf("AlphaInterconnectAttachments().List(%v, %v, %v) = %v, %v; want _, nil", ctx, location, filter.None, objs, err)
+		} else {
+			got := map[string]bool{}
+			for _, obj := range objs {
+				got[obj.Name] = true
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("AlphaInterconnectAttachments().List(); got %+v, want %+v", got, want)
+			}
+		}
+	}
+	{
+		objs, err := mock.BetaInterconnectAttachments().List(ctx, location, filter.None)
+		if err != nil {
+			t.Errorf("BetaInterconnectAttachments().List(%v, %v, %v) = %v, %v; want _, nil", ctx, location, filter.None, objs, err)
+		} else {
+			got := map[string]bool{}
+			for _, obj := range objs {
+				got[obj.Name] = true
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("BetaInterconnectAttachments().List(); got %+v, want %+v", got, want)
+			}
+		}
+	}
+	{
+		objs, err := mock.InterconnectAttachments().List(ctx, location, filter.None)
+		if err != nil {
+			t.Errorf("InterconnectAttachments().List(%v, %v, %v) = %v, %v; want _, nil", ctx, location, filter.None, objs, err)
+		} else {
+			got := map[string]bool{}
+			for _, obj := range objs {
+				got[obj.Name] = true
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("InterconnectAttachments().List(); got %+v, want %+v", got, want)
+			}
+		}
+	}
+
+	// Delete across versions.
+
+	// Delete not found.
+}
+
 func TestMeshesGroup(t *testing.T) {
 	t.Parallel()
 
@@ -3792,6 +3981,105 @@ func TestServiceAttachmentsGroup(t *testing.T) {
 	}
 }
 
+func TestServiceBindingsGroup(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	pr := &SingleProjectRouter{"mock-project"}
+	mock := NewMockGCE(pr)
+
+	var key *meta.Key
+	keyBeta := meta.GlobalKey("key-beta")
+	key = keyBeta
+	keyGA := meta.GlobalKey("key-ga")
+	key = keyGA
+	// Ignore unused variables.
+	_, _, _ = ctx, mock, key
+
+	// Get not found.
+	if _, err := mock.BetaServiceBindings().Get(ctx, key); err == nil {
+		t.Errorf("BetaServiceBindings().Get(%v, %v) = _, nil; want error", ctx, key)
+	}
+	if _, err := mock.ServiceBindings().Get(ctx, key); err == nil {
+		t.Errorf("ServiceBindings().Get(%v, %v) = _, nil; want error", ctx, key)
+	}
+
+	// Insert.
+	{
+		obj := &networkservicesbeta.ServiceBinding{}
+		if err := mock.BetaServiceBindings().Insert(ctx, keyBeta, obj); err != nil {
+			t.Errorf("BetaServiceBindings().Insert(%v, %v, %v) = %v; want nil", ctx, keyBeta, obj, err)
+		}
+	}
+	{
+		obj := &networkservicesga.ServiceBinding{}
+		if err := mock.ServiceBindings().Insert(ctx, keyGA, obj); err != nil {
+			t.Errorf("ServiceBindings().Insert(%v, %v, %v) = %v; want nil", ctx, keyGA, obj, err)
+		}
+	}
+
+	// Get across versions.
+	if obj, err := mock.BetaServiceBindings().Get(ctx, key); err != nil {
+		t.Errorf("BetaServiceBindings().Get(%v, %v) = %v, %v; want nil", ctx, key, obj, err)
+	}
+	if obj, err := mock.ServiceBindings().Get(ctx, key); err != nil {
+		t.Errorf("ServiceBindings().Get(%v, %v) = %v, %v; want nil", ctx, key, obj, err)
+	}
+
+	// List.
+	mock.MockBetaServiceBindings.Objects[*keyBeta] = mock.MockBetaServiceBindings.Obj(&networkservicesbeta.ServiceBinding{Name: keyBeta.Name})
+	mock.MockServiceBindings.Objects[*keyGA] = mock.MockServiceBindings.Obj(&networkservicesga.ServiceBinding{Name: keyGA.Name})
+	want := map[string]bool{
+		"key-beta": true,
+		"key-ga":   true,
+	}
+	_ = want // ignore unused variables.
+	{
+		objs, err := mock.BetaServiceBindings().List(ctx, filter.None)
+		if err != nil {
+			t.Errorf("BetaServiceBindings().List(%v, %v, %v) = %v, %v; want _, nil", ctx, location, filter.None, objs, err)
+		} else {
+			got := map[string]bool{}
+			for _, obj := range objs {
+				got[obj.Name] = true
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("BetaServiceBindings().List(); got %+v, want %+v", got, want)
+			}
+		}
+	}
+	{
+		objs, err := mock.ServiceBindings().List(ctx, filter.None)
+		if err != nil {
+			t.Errorf("ServiceBindings().List(%v, %v, %v) = %v, %v; want _, nil", ctx, location, filter.None, objs, err)
+		} else {
+			got := map[string]bool{}
+			for _, obj := range objs {
+				got[obj.Name] = true
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("ServiceBindings().List(); got %+v, want %+v", got, want)
+			}
+		}
+	}
+
+	// Delete across versions.
+	if err := mock.BetaServiceBindings().Delete(ctx, keyBeta); err != nil {
+		t.Errorf("BetaServiceBindings().Delete(%v, %v) = %v; want nil", ctx, keyBeta, err)
+	}
+	if err := mock.ServiceBindings().Delete(ctx, keyGA); err != nil {
+		t.Errorf("ServiceBindings().Delete(%v, %v) = %v; want nil", ctx, keyGA, err)
+	}
+
+	// Delete not found.
+	if err := mock.BetaServiceBindings().Delete(ctx, keyBeta); err == nil {
+		t.Errorf("BetaServiceBindings().Delete(%v, %v) = nil; want error", ctx, keyBeta)
+	}
+	if err := mock.ServiceBindings().Delete(ctx, keyGA); err == nil {
+		t.Errorf("ServiceBindings().Delete(%v, %v) = nil; want error", ctx, keyGA)
+	}
+}
+
 func TestSslCertificatesGroup(t *testing.T) {
 	t.Parallel()
 
@@ -4111,6 +4399,141 @@ func TestSubnetworksGroup(t *testing.T) {
 	}
 }
 
+func TestTargetGrpcProxiesGroup(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	pr := &SingleProjectRouter{"mock-project"}
+	mock := NewMockGCE(pr)
+
+	var key *meta.Key
+	keyAlpha := meta.GlobalKey("key-alpha")
+	key = keyAlpha
+	keyBeta := meta.GlobalKey("key-beta")
+	key = keyBeta
+	keyGA := meta.GlobalKey("key-ga")
+	key = keyGA
+	// Ignore unused variables.
+	_, _, _ = ctx, mock, key
+
+	// Get not found.
+	if _, err := mock.AlphaTargetGrpcProxies().Get(ctx, key); err == nil {
+		t.Errorf("AlphaTargetGrpcProxies().Get(%v, %v) = _, nil; want error", ctx, key)
+	}
+	if _, err := mock.BetaTargetGrpcProxies().Get(ctx, key); err == nil {
+		t.Errorf("BetaTargetGrpcProxies().Get(%v, %v) = _, nil; want error", ctx, key)
+	}
+	if _, err := mock.TargetGrpcProxies().Get(ctx, key); err == nil {
+		t.Errorf("TargetGrpcProxies().Get(%v, %v) = _, nil; want error", ctx, key)
+	}
+
+	// Insert.
+	{
+		obj := &computealpha.TargetGrpcProxy{}
+		if err := mock.AlphaTargetGrpcProxies().Insert(ctx, keyAlpha, obj); err != nil {
+			t.Errorf("AlphaTargetGrpcProxies().Insert(%v, %v, %v) = %v; want nil", ctx, keyAlpha, obj, err)
+		}
+	}
+	{
+		obj := &computebeta.TargetGrpcProxy{}
+		if err := mock.BetaTargetGrpcProxies().Insert(ctx, keyBeta, obj); err != nil {
+			t.Errorf("BetaTargetGrpcProxies().Insert(%v, %v, %v) = %v; want nil", ctx, keyBeta, obj, err)
+		}
+	}
+	{
+		obj := &computega.TargetGrpcProxy{}
+		if err := mock.TargetGrpcProxies().Insert(ctx, keyGA, obj); err != nil {
+			t.Errorf("TargetGrpcProxies().Insert(%v, %v, %v) = %v; want nil", ctx, keyGA, obj, err)
+		}
+	}
+
+	// Get across versions.
+	if obj, err := mock.AlphaTargetGrpcProxies().Get(ctx, key); err != nil {
+		t.Errorf("AlphaTargetGrpcProxies().Get(%v, %v) = %v, %v; want nil", ctx, key, obj, err)
+	}
+	if obj, err := mock.BetaTargetGrpcProxies().Get(ctx, key); err != nil {
+		t.Errorf("BetaTargetGrpcProxies().Get(%v, %v) = %v, %v; want nil", ctx, key, obj, err)
+	}
+	if obj, err := mock.TargetGrpcProxies().Get(ctx, key); err != nil {
+		t.Errorf("TargetGrpcProxies().Get(%v, %v) = %v, %v; want nil", ctx, key, obj, err)
+	}
+
+	// List.
+	mock.MockAlphaTargetGrpcProxies.Objects[*keyAlpha] = mock.MockAlphaTargetGrpcProxies.Obj(&computealpha.TargetGrpcProxy{Name: keyAlpha.Name})
+	mock.MockBetaTargetGrpcProxies.Objects[*keyBeta] = mock.MockBetaTargetGrpcProxies.Obj(&computebeta.TargetGrpcProxy{Name: keyBeta.Name})
+	mock.MockTargetGrpcProxies.Objects[*keyGA] = mock.MockTargetGrpcProxies.Obj(&computega.TargetGrpcProxy{Name: keyGA.Name})
+	want := map[string]bool{
+		"key-alpha": true,
+		"key-beta":  true,
+		"key-ga":    true,
+	}
+	_ = want // ignore unused variables.
+	{
+		objs, err := mock.AlphaTargetGrpcProxies().List(ctx, filter.None)
+		if err != nil {
+			t.Errorf("AlphaTargetGrpcProxies().List(%v, %v, %v) = %v, %v; want _, nil", ctx, location, filter.None, objs, err)
+		} else {
+			got := map[string]bool{}
+			for _, obj := range objs {
+				got[obj.Name] = true
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("AlphaTargetGrpcProxies().List(); got %+v, want %+v", got, want)
+			}
+		}
+	}
+	{
+		objs, err := mock.BetaTargetGrpcProxies().List(ctx, filter.None)
+		if err != nil {
+			t.Errorf("BetaTargetGrpcProxies().List(%v, %v, %v) = %v, %v; want _, nil", ctx, location, filter.None, objs, err)
+		} else {
+			got := map[string]bool{}
+			for _, obj := range objs {
+				got[obj.Name] = true
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("BetaTargetGrpcProxies().List(); got %+v, want %+v", got, want)
+			}
+		}
+	}
+	{
+		objs, err := mock.TargetGrpcProxies().List(ctx, filter.None)
+		if err != nil {
+			t.Errorf("TargetGrpcProxies().List(%v, %v, %v) = %v, %v; want _, nil", ctx, location, filter.None, objs, err)
+		} else {
+			got := map[string]bool{}
+			for _, obj := range objs {
+				got[obj.Name] = true
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("TargetGrpcProxies().List(); got %+v, want %+v", got, want)
+			}
+		}
+	}
+
+	// Delete across versions.
+	if err := mock.AlphaTargetGrpcProxies().Delete(ctx, keyAlpha); err != nil {
+		t.Errorf("AlphaTargetGrpcProxies().Delete(%v, %v) = %v; want nil", ctx, keyAlpha, err)
+	}
+	if err := mock.BetaTargetGrpcProxies().Delete(ctx, keyBeta); err != nil {
+		t.Errorf("BetaTargetGrpcProxies().Delete(%v, %v) = %v; want nil", ctx, keyBeta, err)
+	}
+	if err := mock.TargetGrpcProxies().Delete(ctx, keyGA); err != nil {
+		t.Errorf("TargetGrpcProxies().Delete(%v, %v) = %v; want nil", ctx, keyGA, err)
+	}
+
+	// Delete not found.
+	if err := mock.AlphaTargetGrpcProxies().Delete(ctx, keyAlpha); err == nil {
+		t.Errorf("AlphaTargetGrpcProxies().Delete(%v, %v) = nil; want error", ctx, keyAlpha)
+	}
+	if err := mock.BetaTargetGrpcProxies().Delete(ctx, keyBeta); err == nil {
+		t.Errorf("BetaTargetGrpcProxies().Delete(%v, %v) = nil; want error", ctx, keyBeta)
+	}
+	if err := mock.TargetGrpcProxies().Delete(ctx, keyGA); err == nil {
+		t.Errorf("TargetGrpcProxies().Delete(%v, %v) = nil; want error", ctx, keyGA)
+	}
+}
+
 func TestTargetHttpProxiesGroup(t *testing.T) {
 	t.Parallel()
 
@@ -4868,6 +5291,7 @@ func TestResourceIDConversion(t *testing.T) {
 		NewAddressesResourceID("some-project", "us-central1", "my-addresses-resource"),
 		NewBackendServicesResourceID("some-project", "my-backendServices-resource"),
 		NewDisksResourceID("some-project", "us-east1-b", "my-disks-resource"),
+		NewEndpointPoliciesResourceID("some-project", "my-endpointPolicies-resource"),
 		NewFirewallsResourceID("some-project", "my-firewalls-resource"),
 		NewForwardingRulesResourceID("some-project", "us-central1", "my-forwardingRules-resource"),
 		NewGlobalAddressesResourceID("some-project", "my-addresses-resource"),
@@ -4881,6 +5305,7 @@ func TestResourceIDConversion(t *testing.T) {
 		NewInstanceGroupsResourceID("some-project", "us-east1-b", "my-instanceGroups-resource"),
 		NewInstanceTemplatesResourceID("some-project", "my-instanceTemplates-resource"),
 		NewInstancesResourceID("some-project", "us-east1-b", "my-instances-resource"),
+		NewInterconnectAttachmentsResourceID("some-project", "us-central1", "my-interconnectAttachments-resource"),
 		NewMeshesResourceID("some-project", "my-meshes-resource"),
 		NewNetworkEndpointGroupsResourceID("some-project", "us-east1-b", "my-networkEndpointGroups-resource"),
 		NewNetworkFirewallPoliciesResourceID("some-project", "my-networkFirewallPolicies-resource"),
@@ -4901,9 +5326,11 @@ func TestResourceIDConversion(t *testing.T) {
 		NewRoutesResourceID("some-project", "my-routes-resource"),
 		NewSecurityPoliciesResourceID("some-project", "my-securityPolicies-resource"),
 		NewServiceAttachmentsResourceID("some-project", "us-central1", "my-serviceAttachments-resource"),
+		NewServiceBindingsResourceID("some-project", "my-serviceBindings-resource"),
 		NewSslCertificatesResourceID("some-project", "my-sslCertificates-resource"),
 		NewSslPoliciesResourceID("some-project", "my-sslPolicies-resource"),
 		NewSubnetworksResourceID("some-project", "us-central1", "my-subnetworks-resource"),
+		NewTargetGrpcProxiesResourceID("some-project", "my-targetGrpcProxies-resource"),
 		NewTargetHttpProxiesResourceID("some-project", "my-targetHttpProxies-resource"),
 		NewTargetHttpsProxiesResourceID("some-project", "my-targetHttpsProxies-resource"),
 		NewTargetPoolsResourceID("some-project", "us-central1", "my-targetPools-resource"),
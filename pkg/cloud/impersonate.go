@@ -0,0 +1,147 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+
+	"golang.org/x/oauth2"
+	"google.golang.org/api/compute/v1"
+	"google.golang.org/api/impersonate"
+)
+
+// impersonatedTokenSource returns the TokenSource for the service account sa.
+// The credentials of the caller are the Application Default Credentials.
+// This is a variable for testing.
+var impersonatedTokenSource = func(ctx context.Context, sa string, scopes []string) (oauth2.TokenSource, error) {
+	return impersonate.CredentialsTokenSource(ctx, impersonate.CredentialsConfig{
+		TargetPrincipal: sa,
+		Scopes:          scopes,
+	})
+}
+
+func defaultScopes(scopes []string) []string {
+	if len(scopes) == 0 {
+		return []string{compute.CloudPlatformScope}
+	}
+	return scopes
+}
+
+// NewServiceForImpersonatedSA returns a new Service that makes the calls as
+// the service account targetSA (e.g.
+// "lb-controller@my-project.iam.gserviceaccount.com"). The Application
+// Default Credentials must have roles/iam.serviceAccountTokenCreator on
+// targetSA. If scopes is empty, the cloud-platform scope is used.
+//
+//	svc, err := cloud.NewServiceForImpersonatedSA(ctx, sa, pr, rl)
+//	gce := cloud.NewGCE(svc)
+func NewServiceForImpersonatedSA(ctx context.Context, targetSA string, pr ProjectRouter, rl RateLimiter, scopes ...string) (*Service, error) {
+	ts, err := impersonatedTokenSource(ctx, targetSA, defaultScopes(scopes))
+	if err != nil {
+		return nil, err
+	}
+	return NewServiceWithTokenSource(ctx, ts, pr, rl)
+}
+
+// NewServiceWithImpersonation returns a new Service that impersonates a
+// service account per project. serviceAccounts maps the project ID to the
+// service account for the calls to resources in the project. The calls for
+// the other projects use fallback; if fallback is nil, these calls fail.
+//
+// This allows executing a graph spanning multiple projects with a least
+// privilege identity for each project.
+func NewServiceWithImpersonation(ctx context.Context, serviceAccounts map[string]string, fallback oauth2.TokenSource, pr ProjectRouter, rl RateLimiter, scopes ...string) (*Service, error) {
+	t := &impersonationTransport{
+		ctx:             ctx,
+		serviceAccounts: map[string]string{},
+		scopes:          defaultScopes(scopes),
+		transports:      map[string]http.RoundTripper{},
+	}
+	for p, sa := range serviceAccounts {
+		t.serviceAccounts[p] = sa
+	}
+	if fallback != nil {
+		t.fallback = &oauth2.Transport{Source: oauth2.ReuseTokenSource(nil, fallback)}
+	}
+	return NewService(ctx, &http.Client{Transport: t}, pr, rl)
+}
+
+// impersonationTransport authenticates each request with the service
+// account for the project in the URL.
+type impersonationTransport struct {
+	ctx             context.Context
+	serviceAccounts map[string]string
+	scopes          []string
+	fallback        http.RoundTripper
+
+	lock       sync.Mutex
+	transports map[string]http.RoundTripper
+}
+
+func (t *impersonationTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	rt, err := t.transport(projectFromURLPath(req.URL.Path))
+	if err != nil {
+		if req.Body != nil {
+			req.Body.Close()
+		}
+		return nil, err
+	}
+	return rt.RoundTrip(req)
+}
+
+// transport returns the RoundTripper for project. The impersonated
+// TokenSources are created on first use.
+func (t *impersonationTransport) transport(project string) (http.RoundTripper, error) {
+	sa, ok := t.serviceAccounts[project]
+	if !ok {
+		if t.fallback == nil {
+			return nil, fmt.Errorf("no service account to impersonate for project %q", project)
+		}
+		return t.fallback, nil
+	}
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if rt, ok := t.transports[sa]; ok {
+		return rt, nil
+	}
+	ts, err := impersonatedTokenSource(t.ctx, sa, t.scopes)
+	if err != nil {
+		return nil, err
+	}
+	rt := &oauth2.Transport{Source: oauth2.ReuseTokenSource(nil, ts)}
+	t.transports[sa] = rt
+	return rt, nil
+}
+
+// projectFromURLPath returns the project in the path of an API URL, e.g.
+// "/compute/v1/projects/<project>/global/...". Returns "" if there is no
+// project.
+func projectFromURLPath(path string) string {
+	parts := strings.Split(path, "/")
+	for i := 0; i+1 < len(parts); i++ {
+		if parts[i] == "projects" {
+			return parts[i+1]
+		}
+	}
+	return ""
+}
@@ -0,0 +1,103 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"golang.org/x/oauth2"
+)
+
+func TestNewServiceWithImpersonation(t *testing.T) {
+	ctx := context.Background()
+
+	var created []string
+	oldTS := impersonatedTokenSource
+	impersonatedTokenSource = func(_ context.Context, sa string, scopes []string) (oauth2.TokenSource, error) {
+		created = append(created, sa)
+		return oauth2.StaticTokenSource(&oauth2.Token{AccessToken: sa}), nil
+	}
+	defer func() { impersonatedTokenSource = oldTS }()
+
+	var lock sync.Mutex
+	gotAuth := map[string]string{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lock.Lock()
+		defer lock.Unlock()
+		gotAuth[projectFromURLPath(r.URL.Path)] = r.Header.Get("Authorization")
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte("{}"))
+	}))
+	defer srv.Close()
+
+	svc, err := NewServiceWithImpersonation(ctx, map[string]string{
+		"p1": "sa1@p1.iam.gserviceaccount.com",
+		"p2": "sa2@p2.iam.gserviceaccount.com",
+	}, oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "fallback"}), &SingleProjectRouter{ID: "p1"}, &NopRateLimiter{})
+	if err != nil {
+		t.Fatalf("NewServiceWithImpersonation() = %v", err)
+	}
+	svc.GA.BasePath = srv.URL + "/compute/v1/"
+	gce := NewGCE(svc)
+
+	key := meta.GlobalKey("bs")
+	for _, p := range []string{"p1", "p2", "p1", "p3"} {
+		if _, err := gce.BackendServices().Get(ctx, key, ForceProjectID(p)); err != nil {
+			t.Fatalf("Get(%s) = %v", p, err)
+		}
+	}
+	for p, want := range map[string]string{
+		"p1": "Bearer sa1@p1.iam.gserviceaccount.com",
+		"p2": "Bearer sa2@p2.iam.gserviceaccount.com",
+		"p3": "Bearer fallback",
+	} {
+		if gotAuth[p] != want {
+			t.Errorf("Authorization for %s = %q, want %q", p, gotAuth[p], want)
+		}
+	}
+	if len(created) != 2 {
+		t.Errorf("impersonated TokenSources created = %v, want one per service account", created)
+	}
+
+	// Without a fallback, calls for other projects fail.
+	svc, err = NewServiceWithImpersonation(ctx, map[string]string{"p1": "sa1@p1.iam.gserviceaccount.com"}, nil, &SingleProjectRouter{ID: "p1"}, &NopRateLimiter{})
+	if err != nil {
+		t.Fatalf("NewServiceWithImpersonation() = %v", err)
+	}
+	svc.GA.BasePath = srv.URL + "/compute/v1/"
+	if _, err := NewGCE(svc).BackendServices().Get(ctx, key, ForceProjectID("p3")); err == nil {
+		t.Error("Get(p3) = nil, want error")
+	}
+}
+
+func TestProjectFromURLPath(t *testing.T) {
+	for _, tc := range []struct{ path, want string }{
+		{"/compute/v1/projects/p1/global/backendServices/bs", "p1"},
+		{"/v1/projects/p2/locations/global/meshes/m", "p2"},
+		{"/compute/v1/projects", ""},
+		{"/", ""},
+	} {
+		if got := projectFromURLPath(tc.path); got != tc.want {
+			t.Errorf("projectFromURLPath(%q) = %q, want %q", tc.path, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,96 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"context"
+	"sync"
+)
+
+// NewKeyedLock returns a new KeyedLock.
+func NewKeyedLock() *KeyedLock {
+	return &KeyedLock{locks: map[ResourceMapKey]*keyLock{}}
+}
+
+// KeyedLock is a mutex per resource. Callers that mutate the same resource
+// from different goroutines (e.g. separate reconcile loops sharing a
+// BackendService) can hold the lock for the resource to serialize the
+// mutations. Locks for different resources are independent.
+//
+// The zero value is not usable; use NewKeyedLock().
+type KeyedLock struct {
+	lock  sync.Mutex
+	locks map[ResourceMapKey]*keyLock
+}
+
+type keyLock struct {
+	// sem has capacity 1 and is held by the owner of the lock.
+	sem chan struct{}
+	// refs is the number of callers holding or waiting for sem.
+	refs int
+}
+
+// Lock the resource id, blocking until the lock is available or ctx is done.
+// Returns a func to release the lock. The lock is not reentrant.
+//
+//	unlock, err := kl.Lock(ctx, id)
+//	if err != nil {
+//		return err
+//	}
+//	defer unlock()
+func (l *KeyedLock) Lock(ctx context.Context, id *ResourceID) (func(), error) {
+	key := id.MapKey()
+
+	l.lock.Lock()
+	kl, ok := l.locks[key]
+	if !ok {
+		kl = &keyLock{sem: make(chan struct{}, 1)}
+		l.locks[key] = kl
+	}
+	kl.refs++
+	l.lock.Unlock()
+
+	done := func() {
+		l.lock.Lock()
+		defer l.lock.Unlock()
+		kl.refs--
+		if kl.refs == 0 {
+			delete(l.locks, key)
+		}
+	}
+
+	select {
+	case kl.sem <- struct{}{}:
+	case <-ctx.Done():
+		done()
+		return nil, ctx.Err()
+	}
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			<-kl.sem
+			done()
+		})
+	}, nil
+}
+
+// Len is the number of resources that are locked or being waited on.
+func (l *KeyedLock) Len() int {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	return len(l.locks)
+}
@@ -0,0 +1,82 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+)
+
+func TestKeyedLock(t *testing.T) {
+	idA := &ResourceID{Resource: "fake", Key: meta.GlobalKey("a")}
+	idB := &ResourceID{Resource: "fake", Key: meta.GlobalKey("b")}
+	l := NewKeyedLock()
+	ctx := context.Background()
+
+	unlockA, err := l.Lock(ctx, idA)
+	if err != nil {
+		t.Fatalf("Lock(a) = %v", err)
+	}
+	// Different keys do not block.
+	unlockB, err := l.Lock(ctx, idB)
+	if err != nil {
+		t.Fatalf("Lock(b) = %v", err)
+	}
+	unlockB()
+
+	// Same key blocks until unlocked.
+	locked := make(chan struct{})
+	go func() {
+		unlock, err := l.Lock(ctx, idA)
+		if err != nil {
+			t.Errorf("Lock(a) = %v", err)
+			close(locked)
+			return
+		}
+		close(locked)
+		unlock()
+	}()
+	select {
+	case <-locked:
+		t.Fatal("Lock(a) acquired while held")
+	case <-time.After(20 * time.Millisecond):
+	}
+	unlockA()
+	// Unlock is idempotent.
+	unlockA()
+	<-locked
+
+	// Cancelled wait.
+	unlockA, err = l.Lock(ctx, idA)
+	if err != nil {
+		t.Fatalf("Lock(a) = %v", err)
+	}
+	cctx, cancel := context.WithCancel(ctx)
+	cancel()
+	if _, err := l.Lock(cctx, idA); !errors.Is(err, context.Canceled) {
+		t.Errorf("Lock(cancelled) = %v, want context.Canceled", err)
+	}
+	unlockA()
+
+	if l.Len() != 0 {
+		t.Errorf("Len() = %d, want 0", l.Len())
+	}
+}
@@ -496,6 +496,33 @@ var ComputeServices = []*ServiceInfo{
 		keyType:     Global,
 		serviceType: reflect.TypeOf(&ga.InstanceTemplatesService{}),
 	},
+	{
+		Object:      "InterconnectAttachment",
+		Service:     "InterconnectAttachments",
+		Resource:    "interconnectAttachments",
+		keyType:     Regional,
+		version:     VersionAlpha,
+		options:     ReadOnly,
+		serviceType: reflect.TypeOf(&alpha.InterconnectAttachmentsService{}),
+	},
+	{
+		Object:      "InterconnectAttachment",
+		Service:     "InterconnectAttachments",
+		Resource:    "interconnectAttachments",
+		keyType:     Regional,
+		version:     VersionBeta,
+		options:     ReadOnly,
+		serviceType: reflect.TypeOf(&beta.InterconnectAttachmentsService{}),
+	},
+	{
+		Object:      "InterconnectAttachment",
+		Service:     "InterconnectAttachments",
+		Resource:    "interconnectAttachments",
+		keyType:     Regional,
+		version:     VersionGA,
+		options:     ReadOnly,
+		serviceType: reflect.TypeOf(&ga.InterconnectAttachmentsService{}),
+	},
 	{
 		Object:      "Image",
 		Service:     "Images",
@@ -761,6 +788,7 @@ var ComputeServices = []*ServiceInfo{
 		Resource:    "securityPolicies",
 		version:     VersionBeta,
 		keyType:     Global,
+		options:     ValidateOnly,
 		serviceType: reflect.TypeOf(&beta.SecurityPoliciesService{}),
 		additionalMethods: []string{
 			"AddRule",
@@ -902,6 +930,38 @@ var ComputeServices = []*ServiceInfo{
 			"Patch",
 		},
 	},
+	{
+		Object:      "TargetGrpcProxy",
+		Service:     "TargetGrpcProxies",
+		Resource:    "targetGrpcProxies",
+		version:     VersionAlpha,
+		keyType:     Global,
+		serviceType: reflect.TypeOf(&alpha.TargetGrpcProxiesService{}),
+		additionalMethods: []string{
+			"Patch",
+		},
+	},
+	{
+		Object:      "TargetGrpcProxy",
+		Service:     "TargetGrpcProxies",
+		Resource:    "targetGrpcProxies",
+		version:     VersionBeta,
+		keyType:     Global,
+		serviceType: reflect.TypeOf(&beta.TargetGrpcProxiesService{}),
+		additionalMethods: []string{
+			"Patch",
+		},
+	},
+	{
+		Object:      "TargetGrpcProxy",
+		Service:     "TargetGrpcProxies",
+		Resource:    "targetGrpcProxies",
+		keyType:     Global,
+		serviceType: reflect.TypeOf(&ga.TargetGrpcProxiesService{}),
+		additionalMethods: []string{
+			"Patch",
+		},
+	},
 	{
 		Object:      "TargetHttpProxy",
 		Service:     "TargetHttpProxies",
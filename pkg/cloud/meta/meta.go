@@ -33,6 +33,13 @@ const (
 	AggregatedList = 1 << iota
 	// ListUsable will generate a method for ListUsable().
 	ListUsable = 1 << iota
+	// NoSelfLink specifies that the object type does not have a SelfLink
+	// field. The mock will not set the SelfLink on Insert().
+	NoSelfLink = 1 << iota
+	// ValidateOnly specifies that Insert() accepts the validateOnly
+	// parameter (see cloud.ValidateOnly()). Insert() of the other
+	// services returns an error for cloud.ValidateOnly().
+	ValidateOnly = 1 << iota
 
 	// ReadOnly specifies that the given resource is read-only and should not
 	// have insert() or delete() methods generated for the wrapper.
@@ -60,6 +67,9 @@ const (
 
 	// APIGroupNetworkServices is the networkservices API group.
 	APIGroupNetworkServices APIGroup = "networkservices"
+
+	// APIGroupServiceDirectory is the servicedirectory API group.
+	APIGroupServiceDirectory APIGroup = "servicedirectory"
 )
 
 // AllVersions is a list of all versions of the GCP APIs.
@@ -268,6 +268,18 @@ func (m *Method) CallArgs() string {
 	return fmt.Sprintf(", %s", strings.Join(args, ", "))
 }
 
+// RequestArg is the "argN" of the request body of the method, or "nil" if
+// the method does not have a body. The body is the last argument and is a
+// pointer to the request object.
+func (m *Method) RequestArg() string {
+	t := m.m.Func.Type()
+	last := t.NumIn() - 1
+	if last < m.argsSkip() || t.In(last).Kind() != reflect.Pointer {
+		return "nil"
+	}
+	return fmt.Sprintf("arg%d", last-m.argsSkip())
+}
+
 // MockHookName is the name of the hook function in the mock.
 func (m *Method) MockHookName() string {
 	return m.m.Name + "Hook"
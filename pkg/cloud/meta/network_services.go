@@ -75,4 +75,46 @@ var NetworkServices = []*ServiceInfo{
 			"Patch",
 		},
 	},
+	{
+		Object:      "ServiceBinding",
+		Service:     "ServiceBindings",
+		Resource:    "serviceBindings",
+		version:     VersionGA,
+		keyType:     Global,
+		serviceType: reflect.TypeOf(&ga.ProjectsLocationsServiceBindingsService{}),
+		options:     NoSelfLink,
+	},
+	{
+		Object:      "ServiceBinding",
+		Service:     "ServiceBindings",
+		Resource:    "serviceBindings",
+		version:     VersionBeta,
+		keyType:     Global,
+		serviceType: reflect.TypeOf(&beta.ProjectsLocationsServiceBindingsService{}),
+		options:     NoSelfLink,
+	},
+	{
+		Object:      "EndpointPolicy",
+		Service:     "EndpointPolicies",
+		Resource:    "endpointPolicies",
+		version:     VersionGA,
+		keyType:     Global,
+		serviceType: reflect.TypeOf(&ga.ProjectsLocationsEndpointPoliciesService{}),
+		options:     NoSelfLink,
+		additionalMethods: []string{
+			"Patch",
+		},
+	},
+	{
+		Object:      "EndpointPolicy",
+		Service:     "EndpointPolicies",
+		Resource:    "endpointPolicies",
+		version:     VersionBeta,
+		keyType:     Global,
+		serviceType: reflect.TypeOf(&beta.ProjectsLocationsEndpointPoliciesService{}),
+		options:     NoSelfLink,
+		additionalMethods: []string{
+			"Patch",
+		},
+	},
 }
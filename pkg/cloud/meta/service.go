@@ -283,6 +283,16 @@ func (i *ServiceInfo) ListUsable() bool {
 	return i.options&ListUsable != 0
 }
 
+// HasSelfLink is true if the object type has a SelfLink field.
+func (i *ServiceInfo) HasSelfLink() bool {
+	return i.options&NoSelfLink == 0
+}
+
+// ValidateOnly is true if Insert() accepts the validateOnly parameter.
+func (i *ServiceInfo) ValidateOnly() bool {
+	return i.options&ValidateOnly != 0
+}
+
 // ServiceGroup is a grouping of the same service but at different API versions.
 type ServiceGroup struct {
 	Alpha *ServiceInfo
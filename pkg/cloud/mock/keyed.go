@@ -0,0 +1,210 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mock
+
+import (
+	"context"
+	"sync"
+
+	cloud "github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+)
+
+// The Keyed* types register the behavior of a mock method for specific keys,
+// instead of writing a single hook with a switch on the key. The behaviors
+// for a key are used in the order they were registered, one per call; the
+// last behavior is repeated for the remaining calls. Calls for keys without
+// behaviors are handled as if there was no hook.
+//
+//	// Get of "bs-test" returns fingerprint "X" then "Y".
+//	get := &mock.KeyedGet[cloud.MockBackendServices, ga.BackendService]{}
+//	get.Return(key, &ga.BackendService{Fingerprint: "X"}, nil).
+//		Return(key, &ga.BackendService{Fingerprint: "Y"}, nil)
+//	mockGCE.MockBackendServices.GetHook = get.Hook
+
+// keyed holds the sequence of behaviors F for each key.
+type keyed[F any] struct {
+	lock  sync.Mutex
+	steps map[meta.Key][]F
+	calls map[meta.Key]int
+}
+
+func (k *keyed[F]) add(key *meta.Key, f F) {
+	k.lock.Lock()
+	defer k.lock.Unlock()
+
+	if k.steps == nil {
+		k.steps = map[meta.Key][]F{}
+	}
+	k.steps[*key] = append(k.steps[*key], f)
+}
+
+// next returns the behavior for the call with key. Returns false if there
+// are no behaviors for the key.
+func (k *keyed[F]) next(key *meta.Key) (F, bool) {
+	k.lock.Lock()
+	defer k.lock.Unlock()
+
+	if k.calls == nil {
+		k.calls = map[meta.Key]int{}
+	}
+	k.calls[*key]++
+
+	var f F
+	steps := k.steps[*key]
+	if len(steps) == 0 {
+		return f, false
+	}
+	i := k.calls[*key] - 1
+	if i >= len(steps) {
+		i = len(steps) - 1
+	}
+	return steps[i], true
+}
+
+// Calls returns the number of calls made for key, including calls that were
+// handled by the mock.
+func (k *keyed[F]) Calls(key *meta.Key) int {
+	k.lock.Lock()
+	defer k.lock.Unlock()
+
+	return k.calls[*key]
+}
+
+// KeyedGet registers the behavior of Get for M, a mock with objects of type
+// T (e.g. KeyedGet[cloud.MockBackendServices, ga.BackendService]). Set Hook
+// as the GetHook of the mock.
+type KeyedGet[M, T any] struct {
+	keyed[func(context.Context, *meta.Key, *M, ...cloud.Option) (bool, *T, error)]
+}
+
+// Return obj, err for the next call of Get with key.
+func (k *KeyedGet[M, T]) Return(key *meta.Key, obj *T, err error) *KeyedGet[M, T] {
+	return k.Func(key, func(context.Context, *meta.Key, *M, ...cloud.Option) (bool, *T, error) {
+		return true, obj, err
+	})
+}
+
+// Func calls f for the next call of Get with key. f has the same semantics
+// as the GetHook.
+func (k *KeyedGet[M, T]) Func(key *meta.Key, f func(context.Context, *meta.Key, *M, ...cloud.Option) (bool, *T, error)) *KeyedGet[M, T] {
+	k.add(key, f)
+	return k
+}
+
+// Hook implements the GetHook.
+func (k *KeyedGet[M, T]) Hook(ctx context.Context, key *meta.Key, m *M, options ...cloud.Option) (bool, *T, error) {
+	if f, ok := k.next(key); ok {
+		return f(ctx, key, m, options...)
+	}
+	return false, nil, nil
+}
+
+// KeyedInsert registers the behavior of Insert for M, a mock with objects of
+// type T. Set Hook as the InsertHook of the mock.
+type KeyedInsert[M, T any] struct {
+	keyed[func(context.Context, *meta.Key, *T, *M, ...cloud.Option) (bool, error)]
+}
+
+// Return err for the next call of Insert with key. The object is not
+// inserted.
+func (k *KeyedInsert[M, T]) Return(key *meta.Key, err error) *KeyedInsert[M, T] {
+	return k.Func(key, func(context.Context, *meta.Key, *T, *M, ...cloud.Option) (bool, error) {
+		return true, err
+	})
+}
+
+// Func calls f for the next call of Insert with key. f has the same
+// semantics as the InsertHook.
+func (k *KeyedInsert[M, T]) Func(key *meta.Key, f func(context.Context, *meta.Key, *T, *M, ...cloud.Option) (bool, error)) *KeyedInsert[M, T] {
+	k.add(key, f)
+	return k
+}
+
+// Hook implements the InsertHook.
+func (k *KeyedInsert[M, T]) Hook(ctx context.Context, key *meta.Key, obj *T, m *M, options ...cloud.Option) (bool, error) {
+	if f, ok := k.next(key); ok {
+		return f(ctx, key, obj, m, options...)
+	}
+	return false, nil
+}
+
+// KeyedDelete registers the behavior of Delete for M. Set Hook as the
+// DeleteHook of the mock.
+type KeyedDelete[M any] struct {
+	keyed[func(context.Context, *meta.Key, *M, ...cloud.Option) (bool, error)]
+}
+
+// Return err for the next call of Delete with key. The object is not
+// deleted.
+func (k *KeyedDelete[M]) Return(key *meta.Key, err error) *KeyedDelete[M] {
+	return k.Func(key, func(context.Context, *meta.Key, *M, ...cloud.Option) (bool, error) {
+		return true, err
+	})
+}
+
+// Func calls f for the next call of Delete with key. f has the same
+// semantics as the DeleteHook.
+func (k *KeyedDelete[M]) Func(key *meta.Key, f func(context.Context, *meta.Key, *M, ...cloud.Option) (bool, error)) *KeyedDelete[M] {
+	k.add(key, f)
+	return k
+}
+
+// Hook implements the DeleteHook.
+func (k *KeyedDelete[M]) Hook(ctx context.Context, key *meta.Key, m *M, options ...cloud.Option) (bool, error) {
+	if f, ok := k.next(key); ok {
+		return f(ctx, key, m, options...)
+	}
+	return false, nil
+}
+
+// KeyedMethod registers the behavior of a custom method (e.g. Update, Patch,
+// SetSecurityPolicy) taking an argument of type A for M. Set Hook as the
+// hook of the method (e.g. UpdateHook). The hooks of custom methods always
+// intercept the call, so calls for keys without behaviors are passed to
+// Default. If Default is nil, these calls return nil.
+type KeyedMethod[M, A any] struct {
+	keyed[func(context.Context, *meta.Key, A, *M, ...cloud.Option) error]
+
+	// Default handles the calls for keys without behaviors, e.g.
+	// UpdateBackendServiceHook.
+	Default func(context.Context, *meta.Key, A, *M, ...cloud.Option) error
+}
+
+// Return err for the next call of the method with key.
+func (k *KeyedMethod[M, A]) Return(key *meta.Key, err error) *KeyedMethod[M, A] {
+	return k.Func(key, func(context.Context, *meta.Key, A, *M, ...cloud.Option) error {
+		return err
+	})
+}
+
+// Func calls f for the next call of the method with key.
+func (k *KeyedMethod[M, A]) Func(key *meta.Key, f func(context.Context, *meta.Key, A, *M, ...cloud.Option) error) *KeyedMethod[M, A] {
+	k.add(key, f)
+	return k
+}
+
+// Hook implements the hook of the method.
+func (k *KeyedMethod[M, A]) Hook(ctx context.Context, key *meta.Key, arg A, m *M, options ...cloud.Option) error {
+	if f, ok := k.next(key); ok {
+		return f(ctx, key, arg, m, options...)
+	}
+	if k.Default != nil {
+		return k.Default(ctx, key, arg, m, options...)
+	}
+	return nil
+}
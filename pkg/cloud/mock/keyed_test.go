@@ -0,0 +1,85 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mock
+
+import (
+	"context"
+	"testing"
+
+	cloud "github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	ga "google.golang.org/api/compute/v1"
+)
+
+func TestKeyed(t *testing.T) {
+	ctx := context.Background()
+	mockGCE := cloud.NewMockGCE(&cloud.SingleProjectRouter{ID: "proj"})
+	key := meta.GlobalKey("bs-test")
+	other := meta.GlobalKey("bs-other")
+
+	for _, k := range []*meta.Key{key, other} {
+		if err := mockGCE.BackendServices().Insert(ctx, k, &ga.BackendService{Fingerprint: "mock"}); err != nil {
+			t.Fatalf("Insert(%v) = %v", k, err)
+		}
+	}
+
+	get := &KeyedGet[cloud.MockBackendServices, ga.BackendService]{}
+	get.Return(key, &ga.BackendService{Fingerprint: "X"}, nil).
+		Return(key, &ga.BackendService{Fingerprint: "Y"}, nil)
+	mockGCE.MockBackendServices.GetHook = get.Hook
+
+	for _, want := range []string{"X", "Y", "Y"} {
+		bs, err := mockGCE.BackendServices().Get(ctx, key)
+		if err != nil || bs.Fingerprint != want {
+			t.Errorf("Get(%v) = %+v, %v; want Fingerprint %q", key, bs, err, want)
+		}
+	}
+	if bs, err := mockGCE.BackendServices().Get(ctx, other); err != nil || bs.Fingerprint != "mock" {
+		t.Errorf("Get(%v) = %+v, %v; want Fingerprint %q", other, bs, err, "mock")
+	}
+	if got := get.Calls(key); got != 3 {
+		t.Errorf("Calls(%v) = %d, want 3", key, got)
+	}
+
+	del := &KeyedDelete[cloud.MockBackendServices]{}
+	del.Return(key, InUseError)
+	mockGCE.MockBackendServices.DeleteHook = del.Hook
+	if err := mockGCE.BackendServices().Delete(ctx, key); err != InUseError {
+		t.Errorf("Delete(%v) = %v, want %v", key, err, InUseError)
+	}
+	if err := mockGCE.BackendServices().Delete(ctx, other); err != nil {
+		t.Errorf("Delete(%v) = %v, want nil", other, err)
+	}
+
+	update := &KeyedMethod[cloud.MockBackendServices, *ga.BackendService]{Default: UpdateBackendServiceHook}
+	update.Return(key, InternalServerError)
+	mockGCE.MockBackendServices.UpdateHook = update.Hook
+	mockGCE.MockBackendServices.GetHook = nil
+	if err := mockGCE.BackendServices().Update(ctx, key, &ga.BackendService{}); err != InternalServerError {
+		t.Errorf("Update(%v) = %v, want %v", key, err, InternalServerError)
+	}
+	if err := mockGCE.BackendServices().Update(ctx, other, &ga.BackendService{}); err == nil {
+		t.Errorf("Update(%v) = nil, want error (deleted)", other)
+	}
+
+	insert := &KeyedInsert[cloud.MockBackendServices, ga.BackendService]{}
+	insert.Return(other, InternalServerError)
+	mockGCE.MockBackendServices.InsertHook = insert.Hook
+	if err := mockGCE.BackendServices().Insert(ctx, other, &ga.BackendService{}); err != InternalServerError {
+		t.Errorf("Insert(%v) = %v, want %v", other, err, InternalServerError)
+	}
+}
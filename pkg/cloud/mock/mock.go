@@ -26,9 +26,12 @@ package mock
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"sort"
 	"sync"
 
 	cloud "github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
@@ -482,7 +485,7 @@ func UpdateFirewallHook(ctx context.Context, key *meta.Key, obj *ga.Firewall, m
 	projectID := m.ProjectRouter.ProjectID(ctx, "ga", "firewalls")
 	obj.SelfLink = cloud.SelfLinkWithGroup(meta.APIGroupCompute, meta.VersionGA, projectID, "firewalls", key)
 
-	m.Objects[*key] = &cloud.MockFirewallsObj{Obj: obj}
+	m.Objects[*key].Update(obj)
 	return nil
 }
 
@@ -503,7 +506,7 @@ func UpdateAlphaFirewallHook(ctx context.Context, key *meta.Key, obj *alpha.Fire
 	projectID := m.ProjectRouter.ProjectID(ctx, "alpha", "firewalls")
 	obj.SelfLink = cloud.SelfLinkWithGroup(meta.APIGroupCompute, meta.VersionAlpha, projectID, "firewalls", key)
 
-	m.Objects[*key] = &cloud.MockFirewallsObj{Obj: obj}
+	m.Objects[*key].Update(obj)
 	return nil
 }
 
@@ -524,7 +527,7 @@ func UpdateBetaFirewallHook(ctx context.Context, key *meta.Key, obj *beta.Firewa
 	projectID := m.ProjectRouter.ProjectID(ctx, "beta", "firewalls")
 	obj.SelfLink = cloud.SelfLinkWithGroup(meta.APIGroupCompute, meta.VersionBeta, projectID, "firewalls", key)
 
-	m.Objects[*key] = &cloud.MockFirewallsObj{Obj: obj}
+	m.Objects[*key].Update(obj)
 	return nil
 }
 
@@ -545,7 +548,7 @@ func UpdateHealthCheckHook(ctx context.Context, key *meta.Key, obj *ga.HealthChe
 	projectID := m.ProjectRouter.ProjectID(ctx, "ga", "healthChecks")
 	obj.SelfLink = cloud.SelfLinkWithGroup(meta.APIGroupCompute, meta.VersionGA, projectID, "healthChecks", key)
 
-	m.Objects[*key] = &cloud.MockHealthChecksObj{Obj: obj}
+	m.Objects[*key].Update(obj)
 	return nil
 }
 
@@ -566,7 +569,7 @@ func UpdateAlphaHealthCheckHook(ctx context.Context, key *meta.Key, obj *alpha.H
 	projectID := m.ProjectRouter.ProjectID(ctx, "alpha", "healthChecks")
 	obj.SelfLink = cloud.SelfLinkWithGroup(meta.APIGroupCompute, meta.VersionAlpha, projectID, "healthChecks", key)
 
-	m.Objects[*key] = &cloud.MockHealthChecksObj{Obj: obj}
+	m.Objects[*key].Update(obj)
 	return nil
 }
 
@@ -586,7 +589,7 @@ func UpdateAlphaRegionHealthCheckHook(ctx context.Context, key *meta.Key, obj *a
 	projectID := m.ProjectRouter.ProjectID(ctx, "alpha", "healthChecks")
 	obj.SelfLink = cloud.SelfLinkWithGroup(meta.APIGroupCompute, meta.VersionAlpha, projectID, "healthChecks", key)
 
-	m.Objects[*key] = &cloud.MockRegionHealthChecksObj{Obj: obj}
+	m.Objects[*key].Update(obj)
 	return nil
 }
 
@@ -606,7 +609,7 @@ func UpdateBetaHealthCheckHook(ctx context.Context, key *meta.Key, obj *beta.Hea
 	projectID := m.ProjectRouter.ProjectID(ctx, "beta", "healthChecks")
 	obj.SelfLink = cloud.SelfLinkWithGroup(meta.APIGroupCompute, meta.VersionBeta, projectID, "healthChecks", key)
 
-	m.Objects[*key] = &cloud.MockHealthChecksObj{Obj: obj}
+	m.Objects[*key].Update(obj)
 	return nil
 }
 
@@ -626,7 +629,7 @@ func UpdateBetaRegionHealthCheckHook(ctx context.Context, key *meta.Key, obj *be
 	projectID := m.ProjectRouter.ProjectID(ctx, "beta", "healthChecks")
 	obj.SelfLink = cloud.SelfLinkWithGroup(meta.APIGroupCompute, meta.VersionBeta, projectID, "healthChecks", key)
 
-	m.Objects[*key] = &cloud.MockRegionHealthChecksObj{Obj: obj}
+	m.Objects[*key].Update(obj)
 	return nil
 }
 
@@ -646,7 +649,7 @@ func UpdateRegionHealthCheckHook(ctx context.Context, key *meta.Key, obj *ga.Hea
 	projectID := m.ProjectRouter.ProjectID(ctx, "ga", "healthChecks")
 	obj.SelfLink = cloud.SelfLinkWithGroup(meta.APIGroupCompute, meta.VersionGA, projectID, "healthChecks", key)
 
-	m.Objects[*key] = &cloud.MockRegionHealthChecksObj{Obj: obj}
+	m.Objects[*key].Update(obj)
 	return nil
 }
 
@@ -668,7 +671,7 @@ func UpdateRegionBackendServiceHook(ctx context.Context, key *meta.Key, obj *ga.
 	projectID := m.ProjectRouter.ProjectID(ctx, "ga", "backendServices")
 	obj.SelfLink = cloud.SelfLinkWithGroup(meta.APIGroupCompute, meta.VersionGA, projectID, "backendServices", key)
 
-	m.Objects[*key] = &cloud.MockRegionBackendServicesObj{Obj: obj}
+	m.Objects[*key].Update(obj)
 	return nil
 }
 
@@ -690,7 +693,7 @@ func UpdateAlphaRegionBackendServiceHook(ctx context.Context, key *meta.Key, obj
 	projectID := m.ProjectRouter.ProjectID(ctx, "alpha", "backendServices")
 	obj.SelfLink = cloud.SelfLinkWithGroup(meta.APIGroupCompute, meta.VersionAlpha, projectID, "backendServices", key)
 
-	m.Objects[*key] = &cloud.MockRegionBackendServicesObj{Obj: obj}
+	m.Objects[*key].Update(obj)
 	return nil
 }
 
@@ -712,7 +715,7 @@ func UpdateBetaRegionBackendServiceHook(ctx context.Context, key *meta.Key, obj
 	projectID := m.ProjectRouter.ProjectID(ctx, "beta", "backendServices")
 	obj.SelfLink = cloud.SelfLinkWithGroup(meta.APIGroupCompute, meta.VersionAlpha, projectID, "backendServices", key)
 
-	m.Objects[*key] = &cloud.MockRegionBackendServicesObj{Obj: obj}
+	m.Objects[*key].Update(obj)
 	return nil
 }
 
@@ -733,7 +736,7 @@ func UpdateBackendServiceHook(ctx context.Context, key *meta.Key, obj *ga.Backen
 	projectID := m.ProjectRouter.ProjectID(ctx, "ga", "backendServices")
 	obj.SelfLink = cloud.SelfLinkWithGroup(meta.APIGroupCompute, meta.VersionGA, projectID, "backendServices", key)
 
-	m.Objects[*key] = &cloud.MockBackendServicesObj{Obj: obj}
+	m.Objects[*key].Update(obj)
 	return nil
 }
 
@@ -754,7 +757,7 @@ func UpdateAlphaBackendServiceHook(ctx context.Context, key *meta.Key, obj *alph
 	projectID := m.ProjectRouter.ProjectID(ctx, "alpha", "backendServices")
 	obj.SelfLink = cloud.SelfLinkWithGroup(meta.APIGroupCompute, meta.VersionAlpha, projectID, "backendServices", key)
 
-	m.Objects[*key] = &cloud.MockBackendServicesObj{Obj: obj}
+	m.Objects[*key].Update(obj)
 	return nil
 }
 
@@ -775,7 +778,7 @@ func UpdateBetaBackendServiceHook(ctx context.Context, key *meta.Key, obj *beta.
 	projectID := m.ProjectRouter.ProjectID(ctx, "beta", "backendServices")
 	obj.SelfLink = cloud.SelfLinkWithGroup(meta.APIGroupCompute, meta.VersionBeta, projectID, "backendServices", key)
 
-	m.Objects[*key] = &cloud.MockBackendServicesObj{Obj: obj}
+	m.Objects[*key].Update(obj)
 	return nil
 }
 
@@ -796,7 +799,7 @@ func UpdateURLMapHook(ctx context.Context, key *meta.Key, obj *ga.UrlMap, m *clo
 	projectID := m.ProjectRouter.ProjectID(ctx, "ga", "urlMaps")
 	obj.SelfLink = cloud.SelfLinkWithGroup(meta.APIGroupCompute, meta.VersionGA, projectID, "urlMaps", key)
 
-	m.Objects[*key] = &cloud.MockUrlMapsObj{Obj: obj}
+	m.Objects[*key].Update(obj)
 	return nil
 }
 
@@ -817,7 +820,7 @@ func UpdateAlphaURLMapHook(ctx context.Context, key *meta.Key, obj *alpha.UrlMap
 	projectID := m.ProjectRouter.ProjectID(ctx, "alpha", "urlMaps")
 	obj.SelfLink = cloud.SelfLinkWithGroup(meta.APIGroupCompute, meta.VersionAlpha, projectID, "urlMaps", key)
 
-	m.Objects[*key] = &cloud.MockUrlMapsObj{Obj: obj}
+	m.Objects[*key].Update(obj)
 	return nil
 }
 
@@ -838,7 +841,7 @@ func UpdateBetaURLMapHook(ctx context.Context, key *meta.Key, obj *beta.UrlMap,
 	projectID := m.ProjectRouter.ProjectID(ctx, "beta", "urlMaps")
 	obj.SelfLink = cloud.SelfLinkWithGroup(meta.APIGroupCompute, meta.VersionBeta, projectID, "urlMaps", key)
 
-	m.Objects[*key] = &cloud.MockUrlMapsObj{Obj: obj}
+	m.Objects[*key].Update(obj)
 	return nil
 }
 
@@ -859,7 +862,7 @@ func UpdateAlphaRegionURLMapHook(ctx context.Context, key *meta.Key, obj *alpha.
 	projectID := m.ProjectRouter.ProjectID(ctx, "alpha", "urlMaps")
 	obj.SelfLink = cloud.SelfLinkWithGroup(meta.APIGroupCompute, meta.VersionAlpha, projectID, "urlMaps", key)
 
-	m.Objects[*key] = &cloud.MockRegionUrlMapsObj{Obj: obj}
+	m.Objects[*key].Update(obj)
 	return nil
 }
 
@@ -880,7 +883,7 @@ func UpdateBetaRegionURLMapHook(ctx context.Context, key *meta.Key, obj *beta.Ur
 	projectID := m.ProjectRouter.ProjectID(ctx, "beta", "urlMaps")
 	obj.SelfLink = cloud.SelfLinkWithGroup(meta.APIGroupCompute, meta.VersionBeta, projectID, "urlMaps", key)
 
-	m.Objects[*key] = &cloud.MockRegionUrlMapsObj{Obj: obj}
+	m.Objects[*key].Update(obj)
 	return nil
 }
 
@@ -901,7 +904,7 @@ func UpdateRegionURLMapHook(ctx context.Context, key *meta.Key, obj *ga.UrlMap,
 	projectID := m.ProjectRouter.ProjectID(ctx, "ga", "urlMaps")
 	obj.SelfLink = cloud.SelfLinkWithGroup(meta.APIGroupCompute, meta.VersionGA, projectID, "urlMaps", key)
 
-	m.Objects[*key] = &cloud.MockRegionUrlMapsObj{Obj: obj}
+	m.Objects[*key].Update(obj)
 	return nil
 }
 
@@ -1006,6 +1009,64 @@ var _ = cloud.MockBetaGlobalForwardingRules{
 	SetTargetHook: SetTargetBetaGlobalForwardingRuleHook,
 }
 
+// SetLabelsGlobalForwardingRuleHook defines the hook for setting the labels of
+// a GlobalForwardingRule. The request fails with StatusPreconditionFailed if
+// the LabelFingerprint does not match.
+func SetLabelsGlobalForwardingRuleHook(ctx context.Context, key *meta.Key, req *ga.GlobalSetLabelsRequest, m *cloud.MockGlobalForwardingRules, options ...cloud.Option) error {
+	fw, err := m.Get(ctx, key)
+	if err != nil {
+		return err
+	}
+	return setFwdRuleLabels(fw, req.Labels, req.LabelFingerprint)
+}
+
+// Verify SetLabelsGlobalForwardingRuleHook implements MockGlobalForwardingRules.SetLabelsHook.
+var _ = cloud.MockGlobalForwardingRules{
+	SetLabelsHook: SetLabelsGlobalForwardingRuleHook,
+}
+
+// SetLabelsForwardingRuleHook defines the hook for setting the labels of a
+// ForwardingRule. The request fails with StatusPreconditionFailed if the
+// LabelFingerprint does not match.
+func SetLabelsForwardingRuleHook(ctx context.Context, key *meta.Key, req *ga.RegionSetLabelsRequest, m *cloud.MockForwardingRules, options ...cloud.Option) error {
+	fw, err := m.Get(ctx, key)
+	if err != nil {
+		return err
+	}
+	return setFwdRuleLabels(fw, req.Labels, req.LabelFingerprint)
+}
+
+// Verify SetLabelsForwardingRuleHook implements MockForwardingRules.SetLabelsHook.
+var _ = cloud.MockForwardingRules{
+	SetLabelsHook: SetLabelsForwardingRuleHook,
+}
+
+func setFwdRuleLabels(fw *ga.ForwardingRule, labels map[string]string, fingerprint string) error {
+	if fw.LabelFingerprint != fingerprint {
+		return &googleapi.Error{
+			Code:    http.StatusPreconditionFailed,
+			Message: fmt.Sprintf("LabelFingerprint mismatch (got %q, want %q)", fingerprint, fw.LabelFingerprint),
+		}
+	}
+	fw.Labels = labels
+	fw.LabelFingerprint = labelFingerprint(labels)
+	return nil
+}
+
+// labelFingerprint returns a fingerprint that changes with the labels.
+func labelFingerprint(labels map[string]string) string {
+	var keys []string
+	for k := range labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	h := sha256.New()
+	for _, k := range keys {
+		fmt.Fprintf(h, "%s=%s;", k, labels[k])
+	}
+	return hex.EncodeToString(h.Sum(nil))[:16]
+}
+
 // SetURLMapTargetHTTPProxyHook defines the hook for setting the url map for a TargetHttpProxy.
 func SetURLMapTargetHTTPProxyHook(ctx context.Context, key *meta.Key, ref *ga.UrlMapReference, m *cloud.MockTargetHttpProxies, options ...cloud.Option) error {
 	tp, err := m.Get(ctx, key)
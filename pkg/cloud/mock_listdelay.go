@@ -0,0 +1,76 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import "time"
+
+// MockListDelay simulates the eventual consistency of List and
+// AggregatedList in the mocks: objects created by Insert are not returned by
+// the lists until both Calls and Duration have passed. Get is not affected.
+//
+// The delay applies to the objects inserted by a mock with the ListDelay
+// field set. Use MockGCE.SetListDelay to set it for all of the mocks.
+type MockListDelay struct {
+	// Calls is the number of List/AggregatedList calls, in any API version,
+	// that do not return a new object.
+	Calls int
+	// Duration after the Insert during which the lists do not return the
+	// object.
+	Duration time.Duration
+	// Now returns the current time. If nil, time.Now is used.
+	Now func() time.Time
+}
+
+func (d *MockListDelay) now() time.Time {
+	if d == nil || d.Now == nil {
+		return time.Now()
+	}
+	return d.Now()
+}
+
+// mockListState tracks the visibility of an object for MockListDelay.
+type mockListState struct {
+	// delay is the MockListDelay when the object was inserted.
+	delay *MockListDelay
+	// inserted is the time of the Insert.
+	inserted time.Time
+	// hiddenCalls is the number of list calls that did not return the
+	// object.
+	hiddenCalls int
+}
+
+func newMockListState(d *MockListDelay) mockListState {
+	if d == nil {
+		return mockListState{}
+	}
+	return mockListState{delay: d, inserted: d.now()}
+}
+
+// visible returns true if the object should be returned by a list call. Each
+// call that returns false counts towards MockListDelay.Calls.
+func (s *mockListState) visible() bool {
+	d := s.delay
+	if d == nil {
+		return true
+	}
+	if s.hiddenCalls < d.Calls || d.now().Sub(s.inserted) < d.Duration {
+		s.hiddenCalls++
+		return false
+	}
+	s.delay = nil
+	return true
+}
@@ -20,6 +20,7 @@ import (
 	"context"
 	"reflect"
 	"testing"
+	"time"
 
 	alpha "google.golang.org/api/compute/v0.alpha"
 	beta "google.golang.org/api/compute/v0.beta"
@@ -149,3 +150,91 @@ func TestMocks(t *testing.T) {
 		t.Errorf("Addresses().Delete(%v, %v) = nil; want error", ctx, key)
 	}
 }
+
+func TestMockVersionViews(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	mock := NewMockGCE(&SingleProjectRouter{"mock-project"})
+	key := meta.GlobalKey("bs")
+
+	if err := mock.AlphaBackendServices().Insert(ctx, key, &alpha.BackendService{
+		Description:     "d1",
+		VpcNetworkScope: "REGIONAL_VPC_NETWORK",
+	}); err != nil {
+		t.Fatalf("AlphaBackendServices().Insert() = %v", err)
+	}
+
+	gaObj, err := mock.BackendServices().Get(ctx, key)
+	if err != nil {
+		t.Fatalf("BackendServices().Get() = %v", err)
+	}
+	if want := SelfLink(meta.VersionGA, "mock-project", "backendServices", key); gaObj.SelfLink != want {
+		t.Errorf("GA SelfLink = %q, want %q", gaObj.SelfLink, want)
+	}
+	betaObj, err := mock.BetaBackendServices().Get(ctx, key)
+	if err != nil {
+		t.Fatalf("BetaBackendServices().Get() = %v", err)
+	}
+	if want := SelfLink(meta.VersionBeta, "mock-project", "backendServices", key); betaObj.SelfLink != want {
+		t.Errorf("beta SelfLink = %q, want %q", betaObj.SelfLink, want)
+	}
+
+	// Replace the object using the GA version; the alpha only field is
+	// kept.
+	gaObj.Description = "d2"
+	mock.MockBackendServices.Objects[*key].Update(gaObj)
+
+	alphaObj, err := mock.AlphaBackendServices().Get(ctx, key)
+	if err != nil {
+		t.Fatalf("AlphaBackendServices().Get() = %v", err)
+	}
+	if alphaObj.Description != "d2" || alphaObj.VpcNetworkScope != "REGIONAL_VPC_NETWORK" {
+		t.Errorf("alpha object = {Description: %q, VpcNetworkScope: %q}, want {d2, REGIONAL_VPC_NETWORK}", alphaObj.Description, alphaObj.VpcNetworkScope)
+	}
+	if want := SelfLink(meta.VersionAlpha, "mock-project", "backendServices", key); alphaObj.SelfLink != want {
+		t.Errorf("alpha SelfLink = %q, want %q", alphaObj.SelfLink, want)
+	}
+}
+
+func TestMockListDelay(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	mock := NewMockGCE(&SingleProjectRouter{"mock-project"})
+	now := time.Unix(1000, 0)
+	mock.SetListDelay(&MockListDelay{Calls: 2, Duration: time.Minute, Now: func() time.Time { return now }})
+
+	key := meta.GlobalKey("bs")
+	if err := mock.BackendServices().Insert(ctx, key, &ga.BackendService{}); err != nil {
+		t.Fatalf("Insert() = %v", err)
+	}
+	if _, err := mock.BackendServices().Get(ctx, key); err != nil {
+		t.Errorf("Get() = %v, want nil", err)
+	}
+
+	list := func() (int, int) {
+		t.Helper()
+		objs, err := mock.BetaBackendServices().List(ctx, filter.None)
+		if err != nil {
+			t.Fatalf("List() = %v", err)
+		}
+		agg, err := mock.BackendServices().AggregatedList(ctx, filter.None)
+		if err != nil {
+			t.Fatalf("AggregatedList() = %v", err)
+		}
+		return len(objs), len(agg["global"])
+	}
+	// Both calls are hidden, which exhausts Calls.
+	if l, a := list(); l != 0 || a != 0 {
+		t.Errorf("list() = %d, %d; want 0, 0", l, a)
+	}
+	// Duration has not passed.
+	if l, a := list(); l != 0 || a != 0 {
+		t.Errorf("list() = %d, %d; want 0, 0", l, a)
+	}
+	now = now.Add(time.Minute)
+	if l, a := list(); l != 1 || a != 1 {
+		t.Errorf("list() = %d, %d; want 1, 1", l, a)
+	}
+}
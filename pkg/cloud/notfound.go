@@ -0,0 +1,61 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/cerrors"
+)
+
+// IsNotFound returns true if err is a NotFound error from the API. This
+// includes the errors returned by the Mock* services.
+func IsNotFound(err error) bool {
+	return cerrors.IsGoogleAPINotFound(err)
+}
+
+// GetOrNil wraps the result of a Get() call so that a NotFound error is
+// returned as (nil, nil). Other errors are returned unchanged. This works with
+// any service:
+//
+//	bs, err := cloud.GetOrNil(c.BackendServices().Get(ctx, key))
+//	if err != nil {
+//		return err // Real error.
+//	}
+//	if bs == nil {
+//		// Does not exist.
+//	}
+func GetOrNil[T any](obj *T, err error) (*T, error) {
+	if IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return obj, nil
+}
+
+// Exists wraps the result of a Get() call and returns true if the resource
+// exists, false if the Get() returned NotFound and an error for any other
+// error.
+//
+//	ok, err := cloud.Exists(c.Meshes().Get(ctx, key))
+func Exists[T any](obj *T, err error) (bool, error) {
+	obj, err = GetOrNil(obj, err)
+	if err != nil {
+		return false, err
+	}
+	return obj != nil, nil
+}
@@ -0,0 +1,88 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"google.golang.org/api/compute/v1"
+	"google.golang.org/api/googleapi"
+)
+
+func TestIsNotFound(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		err  error
+		want bool
+	}{
+		{err: nil},
+		{err: errors.New("x")},
+		{err: &googleapi.Error{Code: http.StatusForbidden}},
+		{err: &googleapi.Error{Code: http.StatusNotFound}, want: true},
+		{err: fmt.Errorf("wrapped: %w", &googleapi.Error{Code: http.StatusNotFound}), want: true},
+	} {
+		if got := IsNotFound(tc.err); got != tc.want {
+			t.Errorf("IsNotFound(%v) = %t, want %t", tc.err, got, tc.want)
+		}
+	}
+}
+
+func TestGetOrNilExists(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	mock := NewMockGCE(&SingleProjectRouter{ID: "proj"})
+	key := meta.GlobalKey("hc")
+
+	hc, err := GetOrNil(mock.HealthChecks().Get(ctx, key))
+	if hc != nil || err != nil {
+		t.Errorf("GetOrNil(Get(%v)) = %v, %v; want nil, nil", key, hc, err)
+	}
+	ok, err := Exists(mock.HealthChecks().Get(ctx, key))
+	if ok || err != nil {
+		t.Errorf("Exists(Get(%v)) = %t, %v; want false, nil", key, ok, err)
+	}
+
+	if err := mock.HealthChecks().Insert(ctx, key, &compute.HealthCheck{Name: "hc"}); err != nil {
+		t.Fatalf("Insert(%v) = %v", key, err)
+	}
+	hc, err = GetOrNil(mock.HealthChecks().Get(ctx, key))
+	if hc == nil || err != nil {
+		t.Errorf("GetOrNil(Get(%v)) = %v, %v; want non-nil, nil", key, hc, err)
+	}
+	ok, err = Exists(mock.HealthChecks().Get(ctx, key))
+	if !ok || err != nil {
+		t.Errorf("Exists(Get(%v)) = %t, %v; want true, nil", key, ok, err)
+	}
+
+	injected := &googleapi.Error{Code: http.StatusInternalServerError}
+	mock.MockHealthChecks.GetError[*key] = injected
+	hc, err = GetOrNil(mock.HealthChecks().Get(ctx, key))
+	if hc != nil || !errors.Is(err, injected) {
+		t.Errorf("GetOrNil(Get(%v)) = %v, %v; want nil, %v", key, hc, err, injected)
+	}
+	ok, err = Exists(mock.HealthChecks().Get(ctx, key))
+	if ok || !errors.Is(err, injected) {
+		t.Errorf("Exists(Get(%v)) = %t, %v; want false, %v", key, ok, err, injected)
+	}
+}
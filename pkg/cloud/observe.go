@@ -18,7 +18,9 @@ package cloud
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"time"
 )
 
 // CallObserver is called between the start and end of the operation.
@@ -72,3 +74,57 @@ func callObserverEnd(ctx context.Context, key *CallContextKey, err error) {
 	}
 	co.End(ctx, key, err)
 }
+
+// CallMetrics receives the latency, the sizes and the result of each call
+// to the API made with a Service, e.g. to record them in a histogram per
+// Service and Operation. Set Service.Metrics to use it. Implementations must
+// be safe for concurrent use.
+type CallMetrics interface {
+	// ObserveCall is called at the end of the call. err is the result of
+	// the call.
+	ObserveCall(key *CallContextKey, stats CallStats, err error)
+}
+
+// CallStats of a call to the API.
+type CallStats struct {
+	// Latency of the call. This does not include the wait in the
+	// RateLimiter. The latency of a mutation does not include waiting
+	// for the operation; each poll of the operation is observed
+	// separately.
+	Latency time.Duration
+	// RequestSize is the size in bytes of the JSON encoding of the
+	// request body. This is 0 for calls without a body.
+	RequestSize int
+	// ResponseSize is the size in bytes of the JSON encoding of the
+	// response. For List calls, this is the size of all of the items
+	// returned. This is 0 if there is no response, e.g. on error.
+	ResponseSize int
+}
+
+// callStats of the call started (after the RateLimiter) at start. The sizes
+// are only computed if s.Metrics is set.
+func (s *Service) callStats(start time.Time, req, resp any) CallStats {
+	stats := CallStats{Latency: time.Since(start)}
+	if s.Metrics != nil {
+		stats.RequestSize = jsonSize(req)
+		stats.ResponseSize = jsonSize(resp)
+	}
+	return stats
+}
+
+// jsonSize returns the size of the JSON encoding of v, 0 if v is nil.
+func jsonSize(v any) int {
+	b, err := json.Marshal(v)
+	if err != nil || string(b) == "null" {
+		return 0
+	}
+	return len(b)
+}
+
+// endCall ends the call with the result err.
+func (s *Service) endCall(ctx context.Context, key *CallContextKey, stats CallStats, err error) {
+	callObserverEnd(ctx, key, err)
+	if s.Metrics != nil {
+		s.Metrics.ObserveCall(key, stats, err)
+	}
+}
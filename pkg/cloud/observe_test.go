@@ -19,7 +19,14 @@ package cloud
 import (
 	"context"
 	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
 	"testing"
+	"time"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	ga "google.golang.org/api/compute/v1"
 )
 
 type fakeCO struct {
@@ -62,3 +69,104 @@ func TestCallObserver(t *testing.T) {
 		})
 	}
 }
+
+type fakeMetrics struct {
+	lock  sync.Mutex
+	calls []string
+	stats []CallStats
+	errs  []error
+}
+
+func (m *fakeMetrics) ObserveCall(key *CallContextKey, stats CallStats, err error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.calls = append(m.calls, key.Service+"."+key.Operation)
+	m.stats = append(m.stats, stats)
+	m.errs = append(m.errs, err)
+}
+
+// sleepRateLimiter waits d in Accept.
+type sleepRateLimiter struct {
+	NopRateLimiter
+	d time.Duration
+}
+
+func (rl *sleepRateLimiter) Accept(ctx context.Context, key *RateLimitKey) error {
+	time.Sleep(rl.d)
+	return nil
+}
+
+func TestCallMetrics(t *testing.T) {
+	ctx := context.Background()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/compute/v1/projects/proj/global/healthChecks/missing" {
+			http.Error(w, "not found", http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte("{}"))
+	}))
+	defer srv.Close()
+
+	svc, err := NewService(ctx, srv.Client(), &SingleProjectRouter{ID: "proj"}, &NopRateLimiter{})
+	if err != nil {
+		t.Fatalf("NewService() = %v", err)
+	}
+	svc.GA.BasePath = srv.URL + "/compute/v1/"
+	metrics := &fakeMetrics{}
+	svc.Metrics = metrics
+	gce := NewGCE(svc)
+
+	if _, err := gce.BackendServices().Get(ctx, meta.GlobalKey("bs")); err != nil {
+		t.Fatalf("Get() = %v", err)
+	}
+	if _, err := gce.HealthChecks().Get(ctx, meta.GlobalKey("missing")); err == nil {
+		t.Fatal("Get() = nil, want error")
+	}
+
+	wantCalls := []string{"BackendServices.Get", "HealthChecks.Get"}
+	if len(metrics.calls) != len(wantCalls) || metrics.calls[0] != wantCalls[0] || metrics.calls[1] != wantCalls[1] {
+		t.Fatalf("ObserveCall() calls = %v, want %v", metrics.calls, wantCalls)
+	}
+	if metrics.errs[0] != nil || !IsNotFound(metrics.errs[1]) {
+		t.Errorf("ObserveCall() errs = %v, want [nil, NotFound]", metrics.errs)
+	}
+}
+
+func TestCallMetricsStats(t *testing.T) {
+	ctx := context.Background()
+	const opJSON = `{"name":"op","selfLink":"https://www.googleapis.com/compute/v1/projects/proj/global/operations/op","status":"DONE"}`
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(opJSON))
+	}))
+	defer srv.Close()
+
+	const wait = 200 * time.Millisecond
+	svc, err := NewService(ctx, srv.Client(), &SingleProjectRouter{ID: "proj"}, &sleepRateLimiter{d: wait})
+	if err != nil {
+		t.Fatalf("NewService() = %v", err)
+	}
+	svc.GA.BasePath = srv.URL + "/compute/v1/"
+	metrics := &fakeMetrics{}
+	svc.Metrics = metrics
+	gce := NewGCE(svc)
+
+	hc := &ga.HealthCheck{Name: "hc", Description: "abc"}
+	if err := gce.HealthChecks().Insert(ctx, meta.GlobalKey("hc"), hc); err != nil {
+		t.Fatalf("Insert() = %v", err)
+	}
+	if len(metrics.stats) == 0 || metrics.calls[0] != "HealthChecks.Insert" {
+		t.Fatalf("ObserveCall() calls = %v, want HealthChecks.Insert first", metrics.calls)
+	}
+	stats := metrics.stats[0]
+	if stats.Latency >= wait {
+		t.Errorf("stats.Latency = %v, want < %v (the RateLimiter wait is not included)", stats.Latency, wait)
+	}
+	if want := jsonSize(hc); stats.RequestSize != want || want == 0 {
+		t.Errorf("stats.RequestSize = %d, want %d", stats.RequestSize, want)
+	}
+	if stats.ResponseSize != len(opJSON) {
+		t.Errorf("stats.ResponseSize = %d, want %d", stats.ResponseSize, len(opJSON))
+	}
+}
@@ -48,6 +48,8 @@ type operation interface {
 	// error returns the resulting error of the operation. This may be nil if the operations
 	// was successful.
 	error() error
+	// warnings returns the warnings of the completed operation.
+	warnings() []OperationWarning
 	// rateLimitKey returns the rate limit key to use for the given operation.
 	// This rate limit will govern how fast the server will be polled for
 	// operation completion status.
@@ -59,6 +61,7 @@ type gaOperation struct {
 	projectID string
 	key       *meta.Key
 	err       error
+	warns     []OperationWarning
 }
 
 func (o *gaOperation) String() string {
@@ -112,6 +115,11 @@ func (o *gaOperation) isDone(ctx context.Context) (bool, error) {
 		e := op.Error.Errors[0]
 		o.err = &googleapi.Error{Code: int(op.HttpErrorStatusCode), Message: fmt.Sprintf("%v - %v", e.Code, e.Message)}
 	}
+	for _, w := range op.Warnings {
+		if w != nil {
+			o.warns = append(o.warns, OperationWarning{Code: w.Code, Message: w.Message})
+		}
+	}
 	return true, nil
 }
 
@@ -128,11 +136,16 @@ func (o *gaOperation) error() error {
 	return o.err
 }
 
+func (o *gaOperation) warnings() []OperationWarning {
+	return o.warns
+}
+
 type alphaOperation struct {
 	s         *Service
 	projectID string
 	key       *meta.Key
 	err       error
+	warns     []OperationWarning
 }
 
 func (o *alphaOperation) String() string {
@@ -186,6 +199,11 @@ func (o *alphaOperation) isDone(ctx context.Context) (bool, error) {
 		e := op.Error.Errors[0]
 		o.err = &googleapi.Error{Code: int(op.HttpErrorStatusCode), Message: fmt.Sprintf("%v - %v", e.Code, e.Message)}
 	}
+	for _, w := range op.Warnings {
+		if w != nil {
+			o.warns = append(o.warns, OperationWarning{Code: w.Code, Message: w.Message})
+		}
+	}
 	return true, nil
 }
 
@@ -202,11 +220,16 @@ func (o *alphaOperation) error() error {
 	return o.err
 }
 
+func (o *alphaOperation) warnings() []OperationWarning {
+	return o.warns
+}
+
 type betaOperation struct {
 	s         *Service
 	projectID string
 	key       *meta.Key
 	err       error
+	warns     []OperationWarning
 }
 
 func (o *betaOperation) String() string {
@@ -259,6 +282,11 @@ func (o *betaOperation) isDone(ctx context.Context) (bool, error) {
 		e := op.Error.Errors[0]
 		o.err = &googleapi.Error{Code: int(op.HttpErrorStatusCode), Message: fmt.Sprintf("%v - %v", e.Code, e.Message)}
 	}
+	for _, w := range op.Warnings {
+		if w != nil {
+			o.warns = append(o.warns, OperationWarning{Code: w.Code, Message: w.Message})
+		}
+	}
 	return true, nil
 }
 
@@ -274,3 +302,7 @@ func (o *betaOperation) rateLimitKey() *RateLimitKey {
 func (o *betaOperation) error() error {
 	return o.err
 }
+
+func (o *betaOperation) warnings() []OperationWarning {
+	return o.warns
+}
@@ -87,6 +87,11 @@ func (o *networkServicesOperation) error() error {
 	return o.err
 }
 
+// warnings is not supported by the Network Services API.
+func (o *networkServicesOperation) warnings() []OperationWarning {
+	return nil
+}
+
 type networkServiceOpURLParseResult struct {
 	projectID string
 	key       *meta.Key
@@ -0,0 +1,79 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"context"
+	"fmt"
+)
+
+// OperationWarning is a warning attached to a completed operation (e.g. the
+// use of a deprecated field). Warnings do not cause the operation to fail.
+type OperationWarning struct {
+	// Code is the warning type identifier (e.g. "DEPRECATED_RESOURCE_USED").
+	Code string
+	// Message is a human readable description of the warning.
+	Message string
+}
+
+func (w OperationWarning) String() string {
+	return fmt.Sprintf("%s: %s", w.Code, w.Message)
+}
+
+// OperationWarningHandler is called with the warnings of a completed
+// operation. The handler may be called concurrently if ctx is shared between
+// goroutines.
+type OperationWarningHandler func(ctx context.Context, warnings []OperationWarning)
+
+var operationWarningHandlerContextKey = contextKey("operation warning handler")
+
+// WithOperationWarningHandler adds a handler that will be called with the
+// warnings of operations completed with the context. Handlers already in ctx
+// are still called after h.
+//
+//	ctx := WithOperationWarningHandler(ctx, func(_ context.Context, w []OperationWarning) {
+//		klog.Warningf("Operation warnings: %v", w)
+//	})
+//	g.Addresses.Insert(ctx, ...)
+func WithOperationWarningHandler(ctx context.Context, h OperationWarningHandler) context.Context {
+	if parent, ok := ctx.Value(operationWarningHandlerContextKey).(OperationWarningHandler); ok {
+		h0 := h
+		h = func(ctx context.Context, warnings []OperationWarning) {
+			h0(ctx, warnings)
+			parent(ctx, warnings)
+		}
+	}
+	return context.WithValue(ctx, operationWarningHandlerContextKey, h)
+}
+
+// ReportOperationWarnings calls the handler in ctx (if any) with the
+// warnings. This is called when an operation completes and may be used by
+// mocks to simulate warnings.
+func ReportOperationWarnings(ctx context.Context, warnings []OperationWarning) {
+	if len(warnings) == 0 {
+		return
+	}
+	obj := ctx.Value(operationWarningHandlerContextKey)
+	if obj == nil {
+		return
+	}
+	h, ok := obj.(OperationWarningHandler)
+	if !ok {
+		panic(fmt.Sprintf("expected OperationWarningHandler, got %T", obj))
+	}
+	h(ctx, warnings)
+}
@@ -1,5 +1,13 @@
 package cloud
 
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"google.golang.org/api/googleapi"
+)
+
 // Option are optional parameters to the generated methods.
 type Option interface {
 	mergeInto(all *allOptions)
@@ -7,17 +15,70 @@ type Option interface {
 
 // allOptions that can be configured for the generated methods.
 type allOptions struct {
-	projectID string
+	projectID     string
+	updateMask    string
+	requestReason string
+	quotaUser     string
+	validateOnly  bool
+	fields        string
 }
 
 // ForceProjectID forces the projectID to be used in the call to be the one
 // specified. This ignores the default routing done by the ProjectRouter.
 func ForceProjectID(projectID string) Option { return projectIDOption(projectID) }
 
+// UpdateMask limits a Patch to the given fields. The fields are the JSON
+// names of the fields in the resource, e.g. "rules" or "meshes". This is only
+// supported by the Network Services APIs.
+func UpdateMask(fields ...string) Option { return updateMaskOption(strings.Join(fields, ",")) }
+
+// RequestReason sets the x-goog-request-reason header of the call. The reason
+// is recorded in the Cloud Audit Logs of the mutation. Use WithRequestReason to
+// set the reason for all calls made with a context.
+func RequestReason(reason string) Option { return requestReasonOption(reason) }
+
+// QuotaUser sets the quota user of the call (x-goog-quota-user). Use
+// WithQuotaUser to set the quota user for all calls made with a context.
+func QuotaUser(user string) Option { return quotaUserOption(user) }
+
+// ValidateOnly makes an Insert only validate the request without creating
+// the resource. The mocks also do not create the resource. This is only
+// supported by the services that accept the validateOnly parameter (see
+// meta.ValidateOnly, e.g. SecurityPolicies); Insert of the other services
+// returns an error without calling the API.
+func ValidateOnly() Option { return validateOnlyOption(true) }
+
+// Fields requests a partial response with only the given fields of the
+// resource (the "fields" system parameter). The fields are the JSON names of
+// the fields, e.g. "name" or "hostRules(hosts,pathMatcher)". This applies to
+// Get and List; for List, the fields are of the items in the list. The mocks
+// ignore the option and return the complete resource.
+func Fields(fields ...string) Option { return fieldsOption(strings.Join(fields, ",")) }
+
 type projectIDOption string
 
 func (opt projectIDOption) mergeInto(all *allOptions) { all.projectID = string(opt) }
 
+type updateMaskOption string
+
+func (opt updateMaskOption) mergeInto(all *allOptions) { all.updateMask = string(opt) }
+
+type requestReasonOption string
+
+func (opt requestReasonOption) mergeInto(all *allOptions) { all.requestReason = string(opt) }
+
+type quotaUserOption string
+
+func (opt quotaUserOption) mergeInto(all *allOptions) { all.quotaUser = string(opt) }
+
+type validateOnlyOption bool
+
+func (opt validateOnlyOption) mergeInto(all *allOptions) { all.validateOnly = bool(opt) }
+
+type fieldsOption string
+
+func (opt fieldsOption) mergeInto(all *allOptions) { all.fields = string(opt) }
+
 func mergeOptions(options []Option) allOptions {
 	var ret allOptions
 	for _, opt := range options {
@@ -25,3 +86,59 @@ func mergeOptions(options []Option) allOptions {
 	}
 	return ret
 }
+
+var (
+	requestReasonContextKey = contextKey("request reason")
+	quotaUserContextKey     = contextKey("quota user")
+)
+
+// WithRequestReason sets the request reason for all calls made with the
+// context. A RequestReason Option on the call takes precedence.
+//
+//	ctx = WithRequestReason(ctx, "ingress-controller: sync default/my-ingress")
+//	g.BackendServices().Update(ctx, ...)
+func WithRequestReason(ctx context.Context, reason string) context.Context {
+	return context.WithValue(ctx, requestReasonContextKey, reason)
+}
+
+// WithQuotaUser sets the quota user for all calls made with the context. A
+// QuotaUser Option on the call takes precedence.
+func WithQuotaUser(ctx context.Context, user string) context.Context {
+	return context.WithValue(ctx, quotaUserContextKey, user)
+}
+
+// setCallHeaders sets the HTTP headers of the call from the options and the
+// context. The User-Agent is per Service and cannot be set per call.
+func setCallHeaders(ctx context.Context, h http.Header, opts allOptions) {
+	reason := opts.requestReason
+	if reason == "" {
+		reason, _ = ctx.Value(requestReasonContextKey).(string)
+	}
+	if reason != "" {
+		h.Set("X-Goog-Request-Reason", reason)
+	}
+	quotaUser := opts.quotaUser
+	if quotaUser == "" {
+		quotaUser, _ = ctx.Value(quotaUserContextKey).(string)
+	}
+	if quotaUser != "" {
+		h.Set("X-Goog-Quota-User", quotaUser)
+	}
+}
+
+// callOptions returns the googleapi.CallOptions for the options.
+func callOptions(opts allOptions) []googleapi.CallOption {
+	var ret []googleapi.CallOption
+	if opts.validateOnly {
+		ret = append(ret, googleapi.QueryParameter("validateOnly", "true"))
+	}
+	return ret
+}
+
+// listFields returns the partial response fields of a List call for the
+// fields of the items. items is the name of the field with the items in the
+// list response (e.g. "Items" or "TcpRoutes").
+func listFields(fields, items string) googleapi.Field {
+	items = strings.ToLower(items[:1]) + items[1:]
+	return googleapi.Field("nextPageToken," + items + "(" + fields + ")")
+}
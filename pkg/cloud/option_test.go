@@ -0,0 +1,238 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/filter"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	beta "google.golang.org/api/compute/v0.beta"
+	ga "google.golang.org/api/compute/v1"
+	"google.golang.org/api/option"
+)
+
+func TestCallHeaders(t *testing.T) {
+	var (
+		lock   sync.Mutex
+		header http.Header
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lock.Lock()
+		header = r.Header.Clone()
+		lock.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte("{}"))
+	}))
+	defer srv.Close()
+
+	ctx := context.Background()
+	gaSvc, err := ga.NewService(ctx, option.WithEndpoint(srv.URL), option.WithHTTPClient(srv.Client()))
+	if err != nil {
+		t.Fatalf("ga.NewService() = %v", err)
+	}
+	g := NewGCE(&Service{
+		GA:            gaSvc,
+		ProjectRouter: &SingleProjectRouter{ID: "proj"},
+		RateLimiter:   &NopRateLimiter{},
+	})
+
+	for _, tc := range []struct {
+		name       string
+		ctx        func(context.Context) context.Context
+		opts       []Option
+		wantReason string
+		wantQuota  string
+	}{
+		{
+			name: "no options",
+		},
+		{
+			name:       "options",
+			opts:       []Option{RequestReason("reason"), QuotaUser("user")},
+			wantReason: "reason",
+			wantQuota:  "user",
+		},
+		{
+			name: "context",
+			ctx: func(ctx context.Context) context.Context {
+				return WithQuotaUser(WithRequestReason(ctx, "ctx-reason"), "ctx-user")
+			},
+			wantReason: "ctx-reason",
+			wantQuota:  "ctx-user",
+		},
+		{
+			name: "option overrides context",
+			ctx: func(ctx context.Context) context.Context {
+				return WithQuotaUser(WithRequestReason(ctx, "ctx-reason"), "ctx-user")
+			},
+			opts:       []Option{RequestReason("reason")},
+			wantReason: "reason",
+			wantQuota:  "ctx-user",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tc.ctx != nil {
+				ctx = tc.ctx(ctx)
+			}
+			check := func(call string) {
+				t.Helper()
+				lock.Lock()
+				defer lock.Unlock()
+				if got := header.Get("X-Goog-Request-Reason"); got != tc.wantReason {
+					t.Errorf("%s: X-Goog-Request-Reason = %q, want %q", call, got, tc.wantReason)
+				}
+				if got := header.Get("X-Goog-Quota-User"); got != tc.wantQuota {
+					t.Errorf("%s: X-Goog-Quota-User = %q, want %q", call, got, tc.wantQuota)
+				}
+			}
+
+			if _, err := g.GlobalAddresses().Get(ctx, meta.GlobalKey("addr"), tc.opts...); err != nil {
+				t.Fatalf("Get() = %v", err)
+			}
+			check("Get")
+			if _, err := g.GlobalAddresses().List(ctx, filter.None, tc.opts...); err != nil {
+				t.Fatalf("List() = %v", err)
+			}
+			check("List")
+		})
+	}
+}
+
+func TestValidateOnly(t *testing.T) {
+	var (
+		lock  sync.Mutex
+		query string
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lock.Lock()
+		query = r.URL.RawQuery
+		lock.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte("{}"))
+	}))
+	defer srv.Close()
+
+	ctx := context.Background()
+	betaSvc, err := beta.NewService(ctx, option.WithEndpoint(srv.URL), option.WithHTTPClient(srv.Client()))
+	if err != nil {
+		t.Fatalf("beta.NewService() = %v", err)
+	}
+	g := NewGCE(&Service{
+		Beta:          betaSvc,
+		ProjectRouter: &SingleProjectRouter{ID: "proj"},
+		RateLimiter:   &NopRateLimiter{},
+	})
+	key := meta.GlobalKey("sp")
+	if err := g.BetaSecurityPolicies().Insert(ctx, key, &beta.SecurityPolicy{}, ValidateOnly()); err != nil {
+		t.Fatalf("Insert() = %v", err)
+	}
+	lock.Lock()
+	if !strings.Contains(query, "validateOnly=true") {
+		t.Errorf("query = %q, want validateOnly=true", query)
+	}
+	lock.Unlock()
+
+	mock := NewMockGCE(&SingleProjectRouter{ID: "proj"})
+	if err := mock.BetaSecurityPolicies().Insert(ctx, key, &beta.SecurityPolicy{}, ValidateOnly()); err != nil {
+		t.Fatalf("mock Insert() = %v", err)
+	}
+	if _, err := mock.BetaSecurityPolicies().Get(ctx, key); err == nil {
+		t.Error("mock Get() = nil, want NotFound after a validate-only Insert")
+	}
+
+	// BackendServices do not accept validateOnly.
+	gaSvc, err := ga.NewService(ctx, option.WithEndpoint(srv.URL), option.WithHTTPClient(srv.Client()))
+	if err != nil {
+		t.Fatalf("ga.NewService() = %v", err)
+	}
+	g = NewGCE(&Service{
+		GA:            gaSvc,
+		ProjectRouter: &SingleProjectRouter{ID: "proj"},
+		RateLimiter:   &NopRateLimiter{},
+	})
+	lock.Lock()
+	query = ""
+	lock.Unlock()
+	if err := g.BackendServices().Insert(ctx, meta.GlobalKey("bs"), &ga.BackendService{}, ValidateOnly()); err == nil {
+		t.Error("BackendServices().Insert(ValidateOnly) = nil, want error")
+	}
+	lock.Lock()
+	if query != "" {
+		t.Errorf("query = %q, want no call", query)
+	}
+	lock.Unlock()
+	if err := mock.BackendServices().Insert(ctx, meta.GlobalKey("bs"), &ga.BackendService{}, ValidateOnly()); err == nil {
+		t.Error("mock BackendServices().Insert(ValidateOnly) = nil, want error")
+	}
+	if _, err := mock.BackendServices().Get(ctx, meta.GlobalKey("bs")); err == nil {
+		t.Error("mock Get() = nil, want NotFound after a rejected validate-only Insert")
+	}
+}
+
+func TestFields(t *testing.T) {
+	var (
+		lock   sync.Mutex
+		fields string
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lock.Lock()
+		fields = r.URL.Query().Get("fields")
+		lock.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte("{}"))
+	}))
+	defer srv.Close()
+
+	ctx := context.Background()
+	gaSvc, err := ga.NewService(ctx, option.WithEndpoint(srv.URL), option.WithHTTPClient(srv.Client()))
+	if err != nil {
+		t.Fatalf("ga.NewService() = %v", err)
+	}
+	g := NewGCE(&Service{
+		GA:            gaSvc,
+		ProjectRouter: &SingleProjectRouter{ID: "proj"},
+		RateLimiter:   &NopRateLimiter{},
+	})
+	check := func(call, want string) {
+		t.Helper()
+		lock.Lock()
+		defer lock.Unlock()
+		if fields != want {
+			t.Errorf("%s: fields = %q, want %q", call, fields, want)
+		}
+	}
+
+	if _, err := g.UrlMaps().Get(ctx, meta.GlobalKey("um")); err != nil {
+		t.Fatalf("Get() = %v", err)
+	}
+	check("Get()", "")
+	if _, err := g.UrlMaps().Get(ctx, meta.GlobalKey("um"), Fields("name", "fingerprint")); err != nil {
+		t.Fatalf("Get(Fields) = %v", err)
+	}
+	check("Get(Fields)", "name,fingerprint")
+	if _, err := g.UrlMaps().List(ctx, filter.None, Fields("name", "fingerprint")); err != nil {
+		t.Fatalf("List(Fields) = %v", err)
+	}
+	check("List(Fields)", "nextPageToken,items(name,fingerprint)")
+}
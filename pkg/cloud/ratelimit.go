@@ -18,6 +18,8 @@ package cloud
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"time"
 )
 
@@ -115,6 +117,87 @@ func (m *MinimumRateLimiter) Observe(ctx context.Context, err error, key *RateLi
 	m.RateLimiter.Observe(ctx, err, key)
 }
 
+// DeadlineTooShortError is returned by DeadlineRateLimiter when the deadline
+// of the context is too close to make the call. The call was not made, so it
+// is safe to retry it with a new context, e.g. by requeueing the work.
+type DeadlineTooShortError struct {
+	// Key of the call.
+	Key *RateLimitKey
+	// Remaining time until the deadline of the context.
+	Remaining time.Duration
+	// Minimum is DeadlineRateLimiter.Minimum.
+	Minimum time.Duration
+}
+
+func (e *DeadlineTooShortError) Error() string {
+	return fmt.Sprintf("deadline too short for %s.%s: %v remaining, minimum is %v", e.Key.Service, e.Key.Operation, e.Remaining, e.Minimum)
+}
+
+// IsDeadlineTooShort returns true if err is or wraps a DeadlineTooShortError.
+func IsDeadlineTooShort(err error) bool {
+	var dErr *DeadlineTooShortError
+	return errors.As(err, &dErr)
+}
+
+// DeadlineRateLimiter wraps a RateLimiter and rejects the calls with a
+// DeadlineTooShortError when the context has less than Minimum remaining
+// until its deadline. This avoids starting mutations whose operation cannot
+// be waited for before the deadline.
+type DeadlineRateLimiter struct {
+	// RateLimiter is the underlying ratelimiter. If nil, the calls that
+	// are not rejected are accepted immediately.
+	RateLimiter RateLimiter
+	// Minimum remaining time until the deadline of the context.
+	Minimum time.Duration
+	// Operations that are checked, e.g. "Insert". If empty, all of the
+	// operations are checked except for "Get", "List", "AggregatedList"
+	// and the polls of the operations.
+	Operations []string
+}
+
+// Accept rejects the call if the deadline is too short, otherwise it calls
+// the underlying ratelimiter.
+func (d *DeadlineRateLimiter) Accept(ctx context.Context, key *RateLimitKey) error {
+	if deadline, ok := ctx.Deadline(); ok && d.checked(key) {
+		if remaining := time.Until(deadline); remaining < d.Minimum {
+			return &DeadlineTooShortError{Key: key, Remaining: remaining, Minimum: d.Minimum}
+		}
+	}
+	if d.RateLimiter == nil {
+		return nil
+	}
+	return d.RateLimiter.Accept(ctx, key)
+}
+
+// Observe just passes error to the underlying ratelimiter.
+func (d *DeadlineRateLimiter) Observe(ctx context.Context, err error, key *RateLimitKey) {
+	if d.RateLimiter != nil {
+		d.RateLimiter.Observe(ctx, err, key)
+	}
+}
+
+func (d *DeadlineRateLimiter) checked(key *RateLimitKey) bool {
+	if key == nil {
+		return false
+	}
+	if len(d.Operations) > 0 {
+		for _, op := range d.Operations {
+			if op == key.Operation {
+				return true
+			}
+		}
+		return false
+	}
+	if key.Service == "Operations" {
+		return false
+	}
+	switch key.Operation {
+	case "Get", "List", "AggregatedList":
+		return false
+	}
+	return true
+}
+
 // TickerRateLimiter uses time.Ticker to spread Accepts over time.
 //
 // Concurrent calls to Accept will block on the same channel. It is not
@@ -218,3 +218,51 @@ func TestCompositeRateLimiter_Table(t *testing.T) {
 		t.Errorf("getNetRL served %d calls, want = 3", *getNetRL)
 	}
 }
+
+func TestDeadlineRateLimiter(t *testing.T) {
+	t.Parallel()
+
+	insert := &CallContextKey{Service: "BackendServices", Operation: "Insert"}
+	get := &CallContextKey{Service: "BackendServices", Operation: "Get"}
+	poll := &CallContextKey{Service: "Operations", Operation: "Get"}
+
+	shortCtx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	longCtx, cancel2 := context.WithTimeout(context.Background(), time.Hour)
+	defer cancel2()
+
+	for _, tc := range []struct {
+		name    string
+		rl      *DeadlineRateLimiter
+		ctx     context.Context
+		key     *CallContextKey
+		wantErr bool
+	}{
+		{name: "short deadline", rl: &DeadlineRateLimiter{Minimum: time.Minute}, ctx: shortCtx, key: insert, wantErr: true},
+		{name: "long deadline", rl: &DeadlineRateLimiter{Minimum: time.Minute}, ctx: longCtx, key: insert},
+		{name: "no deadline", rl: &DeadlineRateLimiter{Minimum: time.Minute}, ctx: context.Background(), key: insert},
+		{name: "get is not checked", rl: &DeadlineRateLimiter{Minimum: time.Minute}, ctx: shortCtx, key: get},
+		{name: "poll is not checked", rl: &DeadlineRateLimiter{Minimum: time.Minute}, ctx: shortCtx, key: poll},
+		{name: "operations", rl: &DeadlineRateLimiter{Minimum: time.Minute, Operations: []string{"Get"}}, ctx: shortCtx, key: get, wantErr: true},
+		{name: "not in operations", rl: &DeadlineRateLimiter{Minimum: time.Minute, Operations: []string{"Get"}}, ctx: shortCtx, key: insert},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			counter := new(CountingRateLimiter)
+			tc.rl.RateLimiter = counter
+			err := tc.rl.Accept(tc.ctx, tc.key)
+			if gotErr := err != nil; gotErr != tc.wantErr {
+				t.Fatalf("Accept() = %v, want error = %t", err, tc.wantErr)
+			}
+			if tc.wantErr {
+				if !IsDeadlineTooShort(err) {
+					t.Errorf("IsDeadlineTooShort(%v) = false, want true", err)
+				}
+				if *counter != 0 {
+					t.Errorf("underlying Accept() called %d times, want 0", *counter)
+				}
+			} else if *counter != 1 {
+				t.Errorf("underlying Accept() called %d times, want 1", *counter)
+			}
+		})
+	}
+}
@@ -0,0 +1,159 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"google.golang.org/api/googleapi"
+	"k8s.io/klog/v2"
+)
+
+// ResourceURLs is an interface for checking the existence of resources by
+// URL (e.g. a SelfLink). This is used for resources that do not have a typed
+// interface in Cloud.
+type ResourceURLs interface {
+	// Get returns nil if the resource at url exists. A resource that does
+	// not exist returns an error for which IsNotFound() is true.
+	Get(ctx context.Context, url string, options ...Option) error
+}
+
+// ResourceURLs returns the interface for getting resources by URL.
+func (gce *GCE) ResourceURLs() ResourceURLs {
+	return gce.gceResourceURLs
+}
+
+// ResourceURLs returns the interface for getting resources by URL.
+func (mock *MockGCE) ResourceURLs() ResourceURLs {
+	return mock.MockResourceURLs
+}
+
+// GCEResourceURLs is a simplifying adapter for getting resources by URL.
+type GCEResourceURLs struct {
+	s *Service
+}
+
+// Get the resource at url. The project of the call is the project in the
+// url.
+func (g *GCEResourceURLs) Get(ctx context.Context, url string, options ...Option) error {
+	opts := mergeOptions(options)
+	klog.V(5).Infof("GCEResourceURLs.Get(%v, %v, %v): called", ctx, url, opts)
+
+	if g.s.client == nil {
+		return fmt.Errorf("GCEResourceURLs.Get(%v): Service does not have an HTTP client", url)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	setCallHeaders(ctx, req.Header, opts)
+
+	ck := &CallContextKey{
+		ProjectID: projectFromURLPath(req.URL.Path),
+		Operation: "Get",
+		Version:   meta.Version("ga"),
+		Service:   "ResourceURLs",
+	}
+	callObserverStart(ctx, ck)
+	if err := g.s.RateLimiter.Accept(ctx, ck); err != nil {
+		klog.V(4).Infof("GCEResourceURLs.Get(%v, %v): RateLimiter error: %v", ctx, url, err)
+		return err
+	}
+	start := time.Now()
+	resp, err := g.s.client.Do(req)
+	var size int64
+	if err == nil {
+		err = googleapi.CheckResponse(resp)
+		size, _ = io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+	}
+	klog.V(4).Infof("GCEResourceURLs.Get(%v, %v) = %v", ctx, url, err)
+
+	stats := g.s.callStats(start, nil, nil)
+	stats.ResponseSize = int(size)
+	g.s.endCall(ctx, ck, stats, err)
+	g.s.RateLimiter.Observe(ctx, err, ck)
+
+	return err
+}
+
+// NewMockResourceURLs returns a new mock for getting resources by URL.
+func NewMockResourceURLs() *MockResourceURLs {
+	return &MockResourceURLs{
+		Objects:  map[string]bool{},
+		GetError: map[string]error{},
+	}
+}
+
+// MockResourceURLs is the mock for getting resources by URL.
+type MockResourceURLs struct {
+	Lock sync.Mutex
+
+	// Objects are the URLs of the resources that exist.
+	Objects map[string]bool
+
+	// If an entry exists for the given URL, then the error will be returned
+	// instead of the operation.
+	GetError map[string]error
+
+	// GetHook intercepts the Get call. If GetHook returns true, the mock
+	// returns the error from the hook.
+	GetHook func(ctx context.Context, url string, m *MockResourceURLs, options ...Option) (bool, error)
+}
+
+// Get is a mock for Get.
+func (m *MockResourceURLs) Get(ctx context.Context, url string, options ...Option) error {
+	if m.GetHook != nil {
+		if intercept, err := m.GetHook(ctx, url, m, options...); intercept {
+			klog.V(5).Infof("MockResourceURLs.Get(%v, %s) = %v", ctx, url, err)
+			return err
+		}
+	}
+
+	m.Lock.Lock()
+	defer m.Lock.Unlock()
+
+	if err, ok := m.GetError[url]; ok {
+		klog.V(5).Infof("MockResourceURLs.Get(%v, %s) = %v", ctx, url, err)
+		return err
+	}
+	if m.Objects[url] {
+		klog.V(5).Infof("MockResourceURLs.Get(%v, %s) = nil", ctx, url)
+		return nil
+	}
+
+	err := &googleapi.Error{
+		Code:    http.StatusNotFound,
+		Message: fmt.Sprintf("MockResourceURLs %v not found", url),
+	}
+	klog.V(5).Infof("MockResourceURLs.Get(%v, %s) = %v", ctx, url, err)
+	return err
+}
+
+// Insert adds the resource at url to the mock.
+func (m *MockResourceURLs) Insert(url string) {
+	m.Lock.Lock()
+	defer m.Lock.Unlock()
+
+	m.Objects[url] = true
+}
@@ -0,0 +1,76 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestResourceURLs(t *testing.T) {
+	ctx := context.Background()
+
+	var gotKeys []CallContextKey
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/compute/v1/projects/p1/global/sslCertificates/cert" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte("{}"))
+	}))
+	defer srv.Close()
+
+	rl := &recordingRateLimiter{keys: &gotKeys}
+	svc, err := NewService(ctx, srv.Client(), &SingleProjectRouter{ID: "p0"}, rl)
+	if err != nil {
+		t.Fatalf("NewService() = %v", err)
+	}
+	gce := NewGCE(svc)
+
+	if err := gce.ResourceURLs().Get(ctx, srv.URL+"/compute/v1/projects/p1/global/sslCertificates/cert"); err != nil {
+		t.Errorf("Get(cert) = %v, want nil", err)
+	}
+	if err := gce.ResourceURLs().Get(ctx, srv.URL+"/compute/v1/projects/p1/global/sslCertificates/other"); !IsNotFound(err) {
+		t.Errorf("Get(other) = %v, want NotFound", err)
+	}
+	if len(gotKeys) != 2 || gotKeys[0].ProjectID != "p1" || gotKeys[0].Service != "ResourceURLs" {
+		t.Errorf("RateLimiter keys = %+v, want 2 ResourceURLs calls for p1", gotKeys)
+	}
+
+	mock := NewMockGCE(&SingleProjectRouter{ID: "p0"})
+	url := "https://compute.googleapis.com/compute/v1/projects/p1/global/sslCertificates/cert"
+	if err := mock.ResourceURLs().Get(ctx, url); !IsNotFound(err) {
+		t.Errorf("mock Get() = %v, want NotFound", err)
+	}
+	mock.MockResourceURLs.Insert(url)
+	if err := mock.ResourceURLs().Get(ctx, url); err != nil {
+		t.Errorf("mock Get() = %v, want nil", err)
+	}
+}
+
+type recordingRateLimiter struct {
+	NopRateLimiter
+	keys *[]CallContextKey
+}
+
+func (rl *recordingRateLimiter) Accept(ctx context.Context, key *CallContextKey) error {
+	*rl.keys = append(*rl.keys, *key)
+	return nil
+}
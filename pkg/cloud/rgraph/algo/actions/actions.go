@@ -21,13 +21,20 @@ import (
 
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph"
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/exec"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode"
 )
 
 // Do accumulates all of the Actions for executing a plan to transform
-// got to want.
+// got to want. The Actions are annotated with the Annotations of the want
+// Node.
 func Do(got, want *rgraph.Graph) ([]exec.Action, error) {
+	return DoNodes(got, want.All())
+}
+
+// DoNodes is like Do() for the subset of the want Nodes in nodes.
+func DoNodes(got *rgraph.Graph, nodes []rnode.Node) ([]exec.Action, error) {
 	var actions []exec.Action
-	for _, n := range want.All() {
+	for _, n := range nodes {
 		gotNode := got.Get(n.ID())
 		if gotNode == nil {
 			return nil, fmt.Errorf("actions: `got` is missing node %s that is in `want`", n.ID())
@@ -36,7 +43,9 @@ func Do(got, want *rgraph.Graph) ([]exec.Action, error) {
 		if err != nil {
 			return nil, err
 		}
-		actions = append(actions, act...)
+		for _, a := range act {
+			actions = append(actions, exec.WithAnnotations(a, n.Annotations()))
+		}
 	}
 	return actions, nil
 }
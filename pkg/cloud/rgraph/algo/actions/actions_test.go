@@ -109,3 +109,30 @@ func TestActions(t *testing.T) {
 		})
 	}
 }
+
+func TestActionsAnnotations(t *testing.T) {
+	id := fake.ID("project-1", meta.GlobalKey("fake-1"))
+	gotb := rgraph.NewBuilder()
+	wantb := rgraph.NewBuilder()
+	for _, b := range []*rgraph.Builder{gotb, wantb} {
+		nb := fake.NewBuilder(id)
+		nb.SetOwnership(rnode.OwnershipManaged)
+		b.Add(nb)
+	}
+	wantb.Get(id).SetAnnotations(map[string]string{"service": "ns/name"})
+
+	got := gotb.MustBuild()
+	want := wantb.MustBuild()
+	want.Get(id).Plan().Set(rnode.PlanDetails{Operation: rnode.OpNothing, Why: "test"})
+
+	actions, err := Do(got, want)
+	if err != nil {
+		t.Fatalf("Do() = %v, want nil", err)
+	}
+	if len(actions) != 1 {
+		t.Fatalf("len(actions) = %d, want 1", len(actions))
+	}
+	if v := actions[0].Metadata().Annotations["service"]; v != "ns/name" {
+		t.Errorf("Annotations[service] = %q, want %q", v, "ns/name")
+	}
+}
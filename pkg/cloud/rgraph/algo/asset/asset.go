@@ -0,0 +1,151 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package asset exports a resource graph as Cloud Asset Inventory-style
+// resource descriptors. See https://cloud.google.com/asset-inventory/docs/resource-name-format
+// for the naming conventions used.
+package asset
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode"
+)
+
+// Asset describes a single resource in the graph.
+type Asset struct {
+	// Name is the full resource name, e.g.
+	// "//compute.googleapis.com/projects/p/global/backendServices/bs".
+	Name string `json:"name"`
+	// AssetType is the type of the resource, e.g.
+	// "compute.googleapis.com/BackendService".
+	AssetType string `json:"assetType"`
+	// Parent is the full resource name of the project containing the
+	// resource.
+	Parent string `json:"parent"`
+	// Relationships to other resources in the graph. These are derived
+	// from the OutRefs of the node.
+	Relationships []Relationship `json:"relationships,omitempty"`
+}
+
+// Relationship is a reference from one Asset to another.
+type Relationship struct {
+	// Type of the relationship, e.g. "BACKENDSERVICE_TO_HEALTHCHECK".
+	Type string `json:"relationshipType"`
+	// Field in the source resource that holds the reference.
+	Field string `json:"field"`
+	// Asset is the full resource name of the target.
+	Asset string `json:"asset"`
+	// AssetType of the target.
+	AssetType string `json:"assetType"`
+}
+
+// Do returns the Assets for the nodes in the graph, sorted by Name. Nodes
+// that are planned to not exist (e.g. tombstones) are skipped.
+func Do(g *rgraph.Graph) []Asset {
+	var ret []Asset
+
+	for _, node := range g.All() {
+		if node.State() == rnode.NodeDoesNotExist {
+			continue
+		}
+		a := Asset{
+			Name:      Name(node.ID()),
+			AssetType: Type(node.ID()),
+			Parent:    parent(node.ID()),
+		}
+		fromKind := strings.ToUpper(kind(node.ID()))
+		for _, ref := range node.OutRefs() {
+			a.Relationships = append(a.Relationships, Relationship{
+				Type:      fmt.Sprintf("%s_TO_%s", fromKind, strings.ToUpper(kind(ref.To))),
+				Field:     ref.Path.String(),
+				Asset:     Name(ref.To),
+				AssetType: Type(ref.To),
+			})
+		}
+		sort.Slice(a.Relationships, func(i, j int) bool {
+			ri, rj := a.Relationships[i], a.Relationships[j]
+			if ri.Field != rj.Field {
+				return ri.Field < rj.Field
+			}
+			return ri.Asset < rj.Asset
+		})
+		ret = append(ret, a)
+	}
+	sort.Slice(ret, func(i, j int) bool { return ret[i].Name < ret[j].Name })
+
+	return ret
+}
+
+// Name returns the full resource name for the id.
+func Name(id *cloud.ResourceID) string {
+	group := apiGroup(id)
+	if group == meta.APIGroupNetworkServices {
+		// Network services resources use the "locations" form instead of the
+		// compute scoping.
+		location := "global"
+		switch id.Key.Type() {
+		case meta.Regional:
+			location = id.Key.Region
+		case meta.Zonal:
+			location = id.Key.Zone
+		}
+		return fmt.Sprintf("//%s.googleapis.com/projects/%s/locations/%s/%s/%s", group, id.ProjectID, location, id.Resource, id.Key.Name)
+	}
+	return fmt.Sprintf("//%s.googleapis.com/%s", group, cloud.RelativeResourceName(id.ProjectID, id.Resource, id.Key))
+}
+
+// Type returns the asset type for the id.
+func Type(id *cloud.ResourceID) string {
+	return fmt.Sprintf("%s.googleapis.com/%s", apiGroup(id), kind(id))
+}
+
+func parent(id *cloud.ResourceID) string {
+	return fmt.Sprintf("//cloudresourcemanager.googleapis.com/projects/%s", id.ProjectID)
+}
+
+func apiGroup(id *cloud.ResourceID) meta.APIGroup {
+	if id.APIGroup == "" {
+		return meta.APIGroupCompute
+	}
+	return id.APIGroup
+}
+
+// kind converts the plural resource name in the URL to the singular Kind,
+// e.g. "targetHttpProxies" => "TargetHttpProxy".
+func kind(id *cloud.ResourceID) string {
+	s := id.Resource
+	switch {
+	case strings.HasSuffix(s, "ies"):
+		s = strings.TrimSuffix(s, "ies") + "y"
+	case strings.HasSuffix(s, "sses"):
+		s = strings.TrimSuffix(s, "es")
+	case strings.HasSuffix(s, "s"):
+		s = strings.TrimSuffix(s, "s")
+	}
+	if s == "" {
+		return s
+	}
+	r := []rune(s)
+	r[0] = unicode.ToUpper(r[0])
+	return string(r)
+}
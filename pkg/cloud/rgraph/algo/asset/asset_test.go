@@ -0,0 +1,121 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package asset
+
+import (
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/testing/ez"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestDo(t *testing.T) {
+	g := ez.Graph{
+		Project: "proj",
+		Nodes: []ez.Node{
+			{Name: "thp", Refs: []ez.Ref{{Field: "UrlMap", To: "um"}}},
+			{Name: "um", Refs: []ez.Ref{{Field: "DefaultService", To: "bs"}}},
+			{Name: "bs", Refs: []ez.Ref{{Field: "Healthchecks", To: "hc"}}},
+			{Name: "hc"},
+			{Name: "addr", Region: "us-central1"},
+		},
+	}
+	graph := g.Builder().MustBuild()
+
+	got := Do(graph)
+	want := []Asset{
+		{
+			Name:      "//compute.googleapis.com/projects/proj/global/backendServices/bs",
+			AssetType: "compute.googleapis.com/BackendService",
+			Parent:    "//cloudresourcemanager.googleapis.com/projects/proj",
+			Relationships: []Relationship{{
+				Type:      "BACKENDSERVICE_TO_HEALTHCHECK",
+				Field:     ".HealthChecks!0",
+				Asset:     "//compute.googleapis.com/projects/proj/global/healthChecks/hc",
+				AssetType: "compute.googleapis.com/HealthCheck",
+			}},
+		},
+		{
+			Name:      "//compute.googleapis.com/projects/proj/global/healthChecks/hc",
+			AssetType: "compute.googleapis.com/HealthCheck",
+			Parent:    "//cloudresourcemanager.googleapis.com/projects/proj",
+		},
+		{
+			Name:      "//compute.googleapis.com/projects/proj/global/targetHttpProxies/thp",
+			AssetType: "compute.googleapis.com/TargetHttpProxy",
+			Parent:    "//cloudresourcemanager.googleapis.com/projects/proj",
+			Relationships: []Relationship{{
+				Type:      "TARGETHTTPPROXY_TO_URLMAP",
+				Field:     ".UrlMap",
+				Asset:     "//compute.googleapis.com/projects/proj/global/urlMaps/um",
+				AssetType: "compute.googleapis.com/UrlMap",
+			}},
+		},
+		{
+			Name:      "//compute.googleapis.com/projects/proj/global/urlMaps/um",
+			AssetType: "compute.googleapis.com/UrlMap",
+			Parent:    "//cloudresourcemanager.googleapis.com/projects/proj",
+			Relationships: []Relationship{{
+				Type:      "URLMAP_TO_BACKENDSERVICE",
+				Field:     ".DefaultService",
+				Asset:     "//compute.googleapis.com/projects/proj/global/backendServices/bs",
+				AssetType: "compute.googleapis.com/BackendService",
+			}},
+		},
+		{
+			Name:      "//compute.googleapis.com/projects/proj/regions/us-central1/addresses/addr",
+			AssetType: "compute.googleapis.com/Address",
+			Parent:    "//cloudresourcemanager.googleapis.com/projects/proj",
+		},
+	}
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Errorf("Do() diff = -got,+want: %s", diff)
+	}
+}
+
+func TestName(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		id       *cloud.ResourceID
+		wantName string
+		wantType string
+	}{
+		{
+			name:     "zonal compute",
+			id:       &cloud.ResourceID{ProjectID: "p", Resource: "networkEndpointGroups", Key: meta.ZonalKey("neg", "us-central1-a")},
+			wantName: "//compute.googleapis.com/projects/p/zones/us-central1-a/networkEndpointGroups/neg",
+			wantType: "compute.googleapis.com/NetworkEndpointGroup",
+		},
+		{
+			name:     "network services",
+			id:       &cloud.ResourceID{ProjectID: "p", APIGroup: meta.APIGroupNetworkServices, Resource: "tcpRoutes", Key: meta.GlobalKey("r")},
+			wantName: "//networkservices.googleapis.com/projects/p/locations/global/tcpRoutes/r",
+			wantType: "networkservices.googleapis.com/TcpRoute",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := Name(tc.id); got != tc.wantName {
+				t.Errorf("Name(%v) = %q, want %q", tc.id, got, tc.wantName)
+			}
+			if got := Type(tc.id); got != tc.wantType {
+				t.Errorf("Type(%v) = %q, want %q", tc.id, got, tc.wantType)
+			}
+		})
+	}
+}
@@ -32,6 +32,26 @@ func PlanWantGraph(got, want *rgraph.Graph) error {
 	return p.do()
 }
 
+// PlanWantNodes is PlanWantGraph for a subset of the Nodes in the "want"
+// Graph. Nodes not in the subset are not modified. This is used to replan
+// only the Nodes that have changed.
+func PlanWantNodes(got, want *rgraph.Graph, nodes []rnode.Node) error {
+	p := planner{got: got, want: want}
+	if err := p.preconditions(); err != nil {
+		return err
+	}
+	for _, wantNode := range nodes {
+		gotNode := got.Get(wantNode.ID())
+		if gotNode == nil {
+			return fmt.Errorf("localPlanner: node %s not in got", wantNode.ID())
+		}
+		if err := p.planWantGraph(gotNode, wantNode); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 type planner struct {
 	got  *rgraph.Graph
 	want *rgraph.Graph
@@ -68,46 +88,36 @@ func (p *planner) preconditions() error {
 }
 
 func (p *planner) planWantGraph(gotNode, wantNode rnode.Node) error {
-	if wantNode.Ownership() != rnode.OwnershipManaged {
-		wantNode.Plan().Set(rnode.PlanDetails{
-			Operation: rnode.OpNothing,
-			Why:       "Node is not managed",
-		})
-		return nil
+	in := rnode.VerdictInputs{
+		Ownership: wantNode.Ownership(),
+		GotState:  gotNode.State(),
+		WantState: wantNode.State(),
 	}
 
-	type s struct{ got, want rnode.NodeState }
-
-	statePair := s{gotNode.State(), wantNode.State()}
-	switch statePair {
-	case s{rnode.NodeExists, rnode.NodeExists}:
-		action, err := wantNode.Diff(gotNode)
+	var details *rnode.PlanDetails
+	if rnode.NeedsDiff(in) {
+		var err error
+		details, err = wantNode.Diff(gotNode)
 		if err != nil {
 			return fmt.Errorf("localPlanner: %w", err)
 		}
-		wantNode.Plan().Set(*action)
-
-	case s{rnode.NodeExists, rnode.NodeDoesNotExist}:
-		wantNode.Plan().Set(rnode.PlanDetails{
-			Operation: rnode.OpDelete,
-			Why:       "Node doesn't exist in want, but exists in got",
-		})
-
-	case s{rnode.NodeDoesNotExist, rnode.NodeExists}:
-		wantNode.Plan().Set(rnode.PlanDetails{
-			Operation: rnode.OpCreate,
-			Why:       "Node doesn't exist in got, but exists in want",
-		})
-
-	case s{rnode.NodeDoesNotExist, rnode.NodeDoesNotExist}:
-		wantNode.Plan().Set(rnode.PlanDetails{
-			Operation: rnode.OpNothing,
-			Why:       "Node does not exist",
-		})
-
-	default:
-		return fmt.Errorf("nodes are in an invalid state for planning: %+v", statePair)
+		in.HasDiff = details.Diff != nil && details.Diff.HasDiff()
+	}
+
+	var diffOp rnode.Operation
+	if details != nil {
+		diffOp = details.Operation
+	}
+	t, ok := rnode.LookupTransition(in, diffOp)
+	if !ok {
+		return fmt.Errorf("nodes are in an invalid state for planning: %+v (diff %s)", in, diffOp)
+	}
+	if details == nil {
+		details = &rnode.PlanDetails{Operation: t.Operation, Why: t.Why}
 	}
+	details.Verdict = t.Verdict
+	details.Inputs = in
+	wantNode.Plan().Set(*details)
 
 	return nil
 }
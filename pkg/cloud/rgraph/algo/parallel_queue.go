@@ -28,6 +28,7 @@ import (
 type config struct {
 	workerCount int
 	tracer      Tracer
+	scheduler   Scheduler
 }
 
 type QueueOption func(*config)
@@ -35,6 +36,19 @@ type QueueOption func(*config)
 func WorkerCount(n int) QueueOption  { return func(c *config) { c.workerCount = n } }
 func UseTracer(t Tracer) QueueOption { return func(c *config) { c.tracer = t } }
 
+// UseScheduler selects the order in which pending items are launched. By
+// default, items are launched in the order they were added.
+func UseScheduler(s Scheduler) QueueOption { return func(c *config) { c.scheduler = s } }
+
+// Scheduler selects the next item to launch from the pending items in the
+// ParallelQueue. Items are identified by their String().
+type Scheduler interface {
+	// Next returns the index in ids of the item to launch. If no item can
+	// be launched now, Next returns -1 and the time to wait before
+	// calling Next again.
+	Next(now time.Time, ids []string) (int, time.Duration)
+}
+
 type Tracer func(RunInfo)
 
 // RunInfo records the details of a task.
@@ -87,6 +101,9 @@ type ParallelQueue[T fmt.Stringer] struct {
 	// active is the count of outstanding operations that have
 	// running goroutines.
 	active int
+	// wakeAt is the time the Scheduler asked to be called again. It is
+	// zero if there is no wake up pending.
+	wakeAt time.Time
 }
 
 type queueElement[T fmt.Stringer] struct {
@@ -158,10 +175,11 @@ func (q *ParallelQueue[T]) Run(ctx context.Context, op func(context.Context, T)
 	q.state = stateRunning
 	q.lock.Unlock()
 
+	logger := klog.FromContext(ctx)
 	for {
 		q.lock.Lock()
 
-		klog.V(4).Infof("Run loop: pending: %d active: %d", len(q.pending), q.active)
+		logger.V(4).Info("Run loop", "pending", len(q.pending), "active", q.active)
 		if len(q.pending) == 0 && q.active == 0 {
 			q.state = stateDone
 			q.lock.Unlock()
@@ -175,7 +193,7 @@ func (q *ParallelQueue[T]) Run(ctx context.Context, op func(context.Context, T)
 		case <-ctx.Done():
 			q.lock.Lock()
 			q.state = stateDone
-			klog.V(2).Infof("Context is Done, exiting early (pending: %d active: %d): %v", len(q.pending), q.active, ctx.Err())
+			logger.V(2).Info("Context is Done, exiting early", "pending", len(q.pending), "active", q.active, "err", ctx.Err())
 			q.lock.Unlock()
 			return ctx.Err()
 		case ri := <-q.done:
@@ -185,14 +203,14 @@ func (q *ParallelQueue[T]) Run(ctx context.Context, op func(context.Context, T)
 
 			if ri.Err != nil {
 				q.state = stateDone
-				klog.V(2).Infof("Task error, exiting early (pending: %d active: %d): %v", len(q.pending), q.active, ri.Err)
+				logger.V(2).Info("Task error, exiting early", "pending", len(q.pending), "active", q.active, "err", ri.Err)
 				q.lock.Unlock()
 				return ri.Err
 			}
 
 			q.lock.Unlock()
 		case <-q.in:
-			klog.V(4).Info("<-q.in")
+			logger.V(4).Info("<-q.in")
 			// Wake up from sleep to (maybe) launch new items.
 		}
 	}
@@ -202,30 +220,82 @@ func (q *ParallelQueue[T]) Run(ctx context.Context, op func(context.Context, T)
 //
 // Precondition: q.lock must be locked.
 func (q *ParallelQueue[T]) launch(ctx context.Context, op func(context.Context, T) error) {
-	pop := func() queueElement[T] {
-		t := q.pending[0]
-		q.pending = q.pending[1:]
-		return t
-	}
-
-	klog.V(4).Infof("Launch: active: %d/%d pending: %d", q.active, q.c.workerCount, len(q.pending))
+	logger := klog.FromContext(ctx)
+	logger.V(4).Info("Launch", "active", q.active, "workers", q.c.workerCount, "pending", len(q.pending))
 
 	for q.active < q.c.workerCount && len(q.pending) > 0 {
-		elt := pop()
+		elt, ok := q.pop()
+		if !ok {
+			logger.V(4).Info("Scheduler is waiting", "wakeAt", q.wakeAt)
+			break
+		}
 		ri := elt.ri
 		q.active++
-		klog.V(4).Infof("Launch: %q active: %d/%d pending: %d", elt.item, q.active, q.c.workerCount, len(q.pending))
+		logger.V(4).Info("Launch task", "task", elt.item, "active", q.active, "workers", q.c.workerCount, "pending", len(q.pending))
 		go func() {
-			klog.V(4).Infof("Task %q start", elt.item)
+			logger.V(4).Info("Task start", "task", elt.item)
 			ri.Start = time.Now()
 			ri.Err = op(ctx, elt.item)
 			ri.End = time.Now()
-			klog.V(4).Infof("Task %q end", elt.item)
+			logger.V(4).Info("Task end", "task", elt.item, "err", ri.Err)
 			q.done <- ri
 		}()
 	}
 }
 
+// pop the next element to launch. Returns false if the Scheduler does not
+// allow any elements to be launched now; a wake up for Run() is scheduled in
+// this case.
+//
+// Precondition: q.lock must be locked.
+func (q *ParallelQueue[T]) pop() (queueElement[T], bool) {
+	i := 0
+	if q.c.scheduler != nil {
+		ids := make([]string, len(q.pending))
+		for j, elt := range q.pending {
+			ids[j] = elt.ri.ID
+		}
+		var wait time.Duration
+		now := time.Now()
+		if i, wait = q.c.scheduler.Next(now, ids); i < 0 {
+			q.wakeAfter(now, wait)
+			return queueElement[T]{}, false
+		}
+	}
+	elt := q.pending[i]
+	if i == 0 {
+		q.pending = q.pending[1:]
+	} else {
+		q.pending = append(q.pending[:i], q.pending[i+1:]...)
+	}
+	return elt, true
+}
+
+// wakeAfter wakes up the Run() loop after d.
+//
+// Precondition: q.lock must be locked.
+func (q *ParallelQueue[T]) wakeAfter(now time.Time, d time.Duration) {
+	at := now.Add(d)
+	if !q.wakeAt.IsZero() && !q.wakeAt.After(at) {
+		// An earlier wake up is already pending.
+		return
+	}
+	q.wakeAt = at
+	time.AfterFunc(d, func() {
+		q.lock.Lock()
+		if q.wakeAt.Equal(at) {
+			q.wakeAt = time.Time{}
+		}
+		q.lock.Unlock()
+		// Don't block if the channel is full; Run() will wake up
+		// anyway.
+		select {
+		case q.in <- struct{}{}:
+		default:
+		}
+	})
+}
+
 // WaitForOrphans will block until remaining op() goroutines
 // finish. Call this if Run() returns an error and you need to know
 // that all remaining threads of execution are done.
@@ -240,12 +310,13 @@ func (q *ParallelQueue[T]) WaitForOrphans(ctx context.Context) error {
 	}
 	q.lock.Unlock()
 
+	logger := klog.FromContext(ctx)
 	for {
 		q.lock.Lock()
-		klog.V(4).Infof("WaitForOrphans: active: %d", q.active)
+		logger.V(4).Info("WaitForOrphans", "active", q.active)
 		if q.active == 0 {
 			q.lock.Unlock()
-			klog.V(4).Info("WaitForOrphans: done")
+			logger.V(4).Info("WaitForOrphans: done")
 			return nil
 		}
 		q.lock.Unlock()
@@ -254,7 +325,7 @@ func (q *ParallelQueue[T]) WaitForOrphans(ctx context.Context) error {
 		// enqueue to the channel.
 		select {
 		case <-ctx.Done():
-			klog.V(4).Infof("WaitForOrphans: early exit, context Done: %v", ctx.Err())
+			logger.V(4).Info("WaitForOrphans: early exit, context Done", "err", ctx.Err())
 			return ctx.Err()
 		case <-q.done:
 			q.lock.Lock()
@@ -374,3 +374,46 @@ func TestParallelQueueWaitForOrphans(t *testing.T) {
 		t.Fatalf("q.Add(_) = %v, want false", ok)
 	}
 }
+
+// lifoScheduler launches the last pending item. The first call to Next
+// waits.
+type lifoScheduler struct {
+	waited bool
+}
+
+func (s *lifoScheduler) Next(_ time.Time, ids []string) (int, time.Duration) {
+	if !s.waited {
+		s.waited = true
+		return -1, 10 * time.Millisecond
+	}
+	return len(ids) - 1, 0
+}
+
+func TestParallelQueueScheduler(t *testing.T) {
+	t.Parallel()
+
+	tracer := &taskTracer{}
+	q := NewParallelQueue[*task](
+		WorkerCount(1),
+		UseTracer(tracer.do),
+		UseScheduler(&lifoScheduler{}),
+	)
+	taskc := newTaskControl(q)
+	for _, id := range []string{"a", "b", "c"} {
+		q.Add(taskc.newTask(id, nil))
+	}
+	start := time.Now()
+	if err := q.Run(context.Background(), taskc.queueOp); err != nil {
+		t.Fatalf("q.Run() = %v; want nil", err)
+	}
+	if elapsed := time.Since(start); elapsed < 10*time.Millisecond {
+		t.Errorf("q.Run() took %v, want >= 10ms (scheduler wait)", elapsed)
+	}
+	var got []string
+	for _, ri := range tracer.got {
+		got = append(got, ri.ID)
+	}
+	if diff := cmp.Diff(got, []string{"c", "b", "a"}); diff != "" {
+		t.Errorf("launch order: -got,+want: %s", diff)
+	}
+}
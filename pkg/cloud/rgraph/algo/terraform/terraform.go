@@ -0,0 +1,189 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package terraform generates Terraform import statements for the managed
+// resources in a graph. This is intended to help migrate ownership of
+// resources between this library and Terraform.
+package terraform
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode"
+)
+
+// Import is a single Terraform import for a resource.
+type Import struct {
+	// ResourceType is the Terraform resource type, e.g.
+	// "google_compute_backend_service".
+	ResourceType string
+	// Name is the local name of the resource in the Terraform configuration.
+	Name string
+	// ID is the import ID of the resource, e.g.
+	// "projects/p/global/backendServices/bs".
+	ID string
+}
+
+// Address of the resource in Terraform (e.g. "google_compute_url_map.um").
+func (i *Import) Address() string {
+	return fmt.Sprintf("%s.%s", i.ResourceType, i.Name)
+}
+
+// Command returns the "terraform import" command line for the resource.
+func (i *Import) Command() string {
+	return fmt.Sprintf("terraform import %s %s", i.Address(), i.ID)
+}
+
+// Block returns the import block (Terraform >= 1.5) for the resource.
+func (i *Import) Block() string {
+	return fmt.Sprintf("import {\n  to = %s\n  id = %q\n}\n", i.Address(), i.ID)
+}
+
+// Do returns the Imports for all of the managed nodes in the graph, sorted
+// by Address. Nodes that are not managed or are planned to not exist are
+// skipped. An error is returned if the graph contains a resource type that
+// has no Terraform equivalent.
+func Do(g *rgraph.Graph) ([]Import, error) {
+	var ret []Import
+
+	for _, node := range g.All() {
+		if node.Ownership() != rnode.OwnershipManaged || node.State() == rnode.NodeDoesNotExist {
+			continue
+		}
+		imp, err := importFor(node.ID())
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, *imp)
+	}
+	sort.Slice(ret, func(i, j int) bool { return ret[i].Address() < ret[j].Address() })
+
+	return ret, nil
+}
+
+// Blocks returns the import blocks for the imports as a single document
+// that can be saved to a .tf file.
+func Blocks(imports []Import) string {
+	var buf bytes.Buffer
+	for i, imp := range imports {
+		if i > 0 {
+			buf.WriteString("\n")
+		}
+		buf.WriteString(imp.Block())
+	}
+	return buf.String()
+}
+
+// Script returns a shell script with the "terraform import" commands for the
+// imports.
+func Script(imports []Import) string {
+	var buf bytes.Buffer
+	for _, imp := range imports {
+		buf.WriteString(imp.Command())
+		buf.WriteString("\n")
+	}
+	return buf.String()
+}
+
+// tfType maps the resource to the Terraform resource type by scope.
+type tfType struct {
+	global   string
+	regional string
+	zonal    string
+}
+
+var tfTypes = map[string]tfType{
+	"addresses": {
+		global:   "google_compute_global_address",
+		regional: "google_compute_address",
+	},
+	"backendServices": {
+		global:   "google_compute_backend_service",
+		regional: "google_compute_region_backend_service",
+	},
+	"forwardingRules": {
+		global:   "google_compute_global_forwarding_rule",
+		regional: "google_compute_forwarding_rule",
+	},
+	"healthChecks": {
+		global:   "google_compute_health_check",
+		regional: "google_compute_region_health_check",
+	},
+	"networkEndpointGroups": {
+		global:   "google_compute_global_network_endpoint_group",
+		regional: "google_compute_region_network_endpoint_group",
+		zonal:    "google_compute_network_endpoint_group",
+	},
+	"targetHttpProxies": {
+		global:   "google_compute_target_http_proxy",
+		regional: "google_compute_region_target_http_proxy",
+	},
+	"urlMaps": {
+		global:   "google_compute_url_map",
+		regional: "google_compute_region_url_map",
+	},
+	"tcpRoutes": {
+		global: "google_network_services_tcp_route",
+	},
+	"serviceBindings": {
+		global: "google_network_services_service_binding",
+	},
+	"endpointPolicies": {
+		global: "google_network_services_endpoint_policy",
+	},
+}
+
+func importFor(id *cloud.ResourceID) (*Import, error) {
+	t, ok := tfTypes[id.Resource]
+	if !ok {
+		return nil, fmt.Errorf("terraform: unsupported resource %q (%v)", id.Resource, id)
+	}
+
+	var resType, name, location string
+	switch id.Key.Type() {
+	case meta.Global:
+		resType = t.global
+		name = id.Key.Name
+		location = "global"
+	case meta.Regional:
+		resType = t.regional
+		// Local names must be unique for the same type so we include the
+		// location for non-global resources.
+		name = id.Key.Name + "_" + id.Key.Region
+		location = id.Key.Region
+	case meta.Zonal:
+		resType = t.zonal
+		name = id.Key.Name + "_" + id.Key.Zone
+		location = id.Key.Zone
+	}
+	if resType == "" {
+		return nil, fmt.Errorf("terraform: unsupported scope for %v", id)
+	}
+
+	imp := &Import{ResourceType: resType, Name: name}
+	if id.APIGroup == meta.APIGroupNetworkServices {
+		imp.ID = fmt.Sprintf("projects/%s/locations/%s/%s/%s", id.ProjectID, location, id.Resource, id.Key.Name)
+	} else {
+		imp.ID = cloud.RelativeResourceName(id.ProjectID, id.Resource, id.Key)
+	}
+
+	return imp, nil
+}
@@ -0,0 +1,79 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package terraform
+
+import (
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/fake"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/testing/ez"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestDo(t *testing.T) {
+	g := ez.Graph{
+		Project: "proj",
+		Nodes: []ez.Node{
+			{Name: "fr", Refs: []ez.Ref{{Field: "Target", To: "thp"}}},
+			{Name: "thp", Refs: []ez.Ref{{Field: "UrlMap", To: "um"}}},
+			{Name: "um", Refs: []ez.Ref{{Field: "DefaultService", To: "bs"}}},
+			{Name: "bs", Refs: []ez.Ref{{Field: "Backends.Group", To: "us-central1-a/neg"}}},
+			{Name: "neg", Zone: "us-central1-a"},
+			{Name: "hc", Options: ez.External},
+			{Name: "addr", Region: "us-central1", Options: ez.DoesNotExist},
+			{Name: "tcp-route"},
+		},
+	}
+	imports, err := Do(g.Builder().MustBuild())
+	if err != nil {
+		t.Fatalf("Do() = %v, want nil", err)
+	}
+
+	const wantScript = `terraform import google_compute_backend_service.bs projects/proj/global/backendServices/bs
+terraform import google_compute_global_forwarding_rule.fr projects/proj/global/forwardingRules/fr
+terraform import google_compute_network_endpoint_group.neg_us-central1-a projects/proj/zones/us-central1-a/networkEndpointGroups/neg
+terraform import google_compute_target_http_proxy.thp projects/proj/global/targetHttpProxies/thp
+terraform import google_compute_url_map.um projects/proj/global/urlMaps/um
+terraform import google_network_services_tcp_route.tcp-route projects/proj/locations/global/tcpRoutes/tcp-route
+`
+	if diff := cmp.Diff(Script(imports), wantScript); diff != "" {
+		t.Errorf("Script() diff = -got,+want: %s", diff)
+	}
+
+	const wantBlocks = `import {
+  to = google_compute_backend_service.bs
+  id = "projects/proj/global/backendServices/bs"
+}
+`
+	if diff := cmp.Diff(Blocks(imports[:1]), wantBlocks); diff != "" {
+		t.Errorf("Blocks() diff = -got,+want: %s", diff)
+	}
+}
+
+func TestDoUnsupported(t *testing.T) {
+	b := rgraph.NewBuilder()
+	nb := fake.NewBuilder(fake.ID("proj", meta.GlobalKey("fake")))
+	nb.SetOwnership(rnode.OwnershipManaged)
+	nb.SetState(rnode.NodeExists)
+	b.Add(nb)
+	if _, err := Do(b.MustBuild()); err == nil {
+		t.Errorf("Do() = nil, want error")
+	}
+}
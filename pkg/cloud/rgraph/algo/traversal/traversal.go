@@ -110,3 +110,56 @@ func TransitiveInRefs(g *rgraph.Graph, n rnode.Node) ([]rnode.Node, error) {
 
 	return ret, nil
 }
+
+// DependencyLevels groups the Nodes of g by the length of the longest path of
+// OutRefs from the Node. Level 0 are the Nodes that do not reference other
+// Nodes in g, level 1 are the Nodes that only reference Nodes in level 0 and
+// so on. For example, for graph A => B => C; D => C, this will return [[C],
+// [B, D], [A]]. References to Nodes that are not in g are ignored. Returns an
+// error if the references have a cycle.
+func DependencyLevels(g *rgraph.Graph) ([][]rnode.Node, error) {
+	level := map[cloud.ResourceMapKey]int{}
+	visiting := map[cloud.ResourceMapKey]bool{}
+
+	var visit func(n rnode.Node) (int, error)
+	visit = func(n rnode.Node) (int, error) {
+		key := n.ID().MapKey()
+		if l, ok := level[key]; ok {
+			return l, nil
+		}
+		if visiting[key] {
+			return 0, fmt.Errorf("invalid graph: cycle at node %v", n.ID())
+		}
+		visiting[key] = true
+
+		l := 0
+		for _, ref := range n.OutRefs() {
+			to := g.Get(ref.To)
+			if to == nil {
+				continue
+			}
+			toLevel, err := visit(to)
+			if err != nil {
+				return 0, err
+			}
+			if toLevel+1 > l {
+				l = toLevel + 1
+			}
+		}
+		level[key] = l
+		return l, nil
+	}
+
+	var ret [][]rnode.Node
+	for _, n := range g.All() {
+		l, err := visit(n)
+		if err != nil {
+			return nil, err
+		}
+		for len(ret) <= l {
+			ret = append(ret, nil)
+		}
+		ret[l] = append(ret[l], n)
+	}
+	return ret, nil
+}
@@ -17,6 +17,7 @@ limitations under the License.
 package traversal
 
 import (
+	"sort"
 	"strings"
 	"testing"
 
@@ -373,3 +374,54 @@ func TestTransitiveRefs(t *testing.T) {
 		})
 	}
 }
+
+func TestDependencyLevels(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		graph   string
+		want    [][]string
+		wantErr bool
+	}{
+		{name: "empty graph"},
+		{
+			name:  "one node",
+			graph: "a",
+			want:  [][]string{{"a"}},
+		},
+		{
+			name:  "chain",
+			graph: "a->b->c",
+			want:  [][]string{{"c"}, {"b"}, {"a"}},
+		},
+		{
+			name:  "longest path",
+			graph: "a->b->c; a->c; d->c; e",
+			want:  [][]string{{"c", "e"}, {"b", "d"}, {"a"}},
+		},
+		{
+			name:    "cycle",
+			graph:   "a->b; b->c; c->a",
+			wantErr: true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			g := parseGraph(t, tc.graph)
+			levels, err := DependencyLevels(g)
+			if gotErr := err != nil; gotErr != tc.wantErr {
+				t.Fatalf("DependencyLevels() = %v; gotErr = %t, want %t", err, gotErr, tc.wantErr)
+			}
+			var got [][]string
+			for _, l := range levels {
+				var names []string
+				for _, n := range l {
+					names = append(names, n.ID().Key.Name)
+				}
+				sort.Strings(names)
+				got = append(got, names)
+			}
+			if diff := cmp.Diff(got, tc.want); diff != "" {
+				t.Errorf("DependencyLevels(): -got,+want: %s", diff)
+			}
+		})
+	}
+}
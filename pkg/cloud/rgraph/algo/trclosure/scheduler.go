@@ -0,0 +1,175 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trclosure
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/algo"
+)
+
+// serviceOf returns the service that is used to read the resource, e.g.
+// "compute" or "networkservices".
+func serviceOf(id *cloud.ResourceID) string {
+	if id.APIGroup == "" {
+		return string(meta.APIGroupCompute)
+	}
+	return string(id.APIGroup)
+}
+
+// groupOf returns the group of reads that are interleaved by the scheduler:
+// the service, resource type and scope.
+func groupOf(id *cloud.ResourceID) string {
+	g := serviceOf(id) + "/" + id.Resource
+	switch id.Key.Type() {
+	case meta.Regional:
+		g += "/" + id.Key.Region
+	case meta.Zonal:
+		g += "/" + id.Key.Zone
+	}
+	return g
+}
+
+// scheduler interleaves the reads across the groups of resources (see
+// groupOf) and limits the rate of reads for each service.
+type scheduler struct {
+	lock sync.Mutex
+	// ids maps work.String() to the ResourceID.
+	ids map[string]*cloud.ResourceID
+	// limits by service.
+	limits map[string]*tokenBucket
+	// last is the group of the last item launched.
+	last string
+}
+
+var _ algo.Scheduler = (*scheduler)(nil)
+
+func newScheduler(qps map[string]QPS) *scheduler {
+	s := &scheduler{
+		ids:    map[string]*cloud.ResourceID{},
+		limits: map[string]*tokenBucket{},
+	}
+	for svc, q := range qps {
+		s.limits[svc] = &tokenBucket{qps: q.QPS, burst: float64(q.Burst), tokens: float64(q.Burst)}
+	}
+	return s
+}
+
+// add must be called for each ID before the work is added to the queue.
+func (s *scheduler) add(id *cloud.ResourceID) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.ids[id.String()] = id
+}
+
+// Next implements algo.Scheduler. The first pending item of each group is a
+// candidate if the rate limit of the service allows. The candidate from the
+// group after the last launched group (in sorted order) is selected, so that
+// reads round-robin across the groups.
+func (s *scheduler) Next(now time.Time, ids []string) (int, time.Duration) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	first := map[string]int{}
+	var (
+		groups  []string
+		minWait time.Duration
+		waiting = map[string]bool{}
+	)
+	for i, idStr := range ids {
+		id, ok := s.ids[idStr]
+		if !ok {
+			// Not registered, launch immediately.
+			return i, 0
+		}
+		svc := serviceOf(id)
+		if waiting[svc] {
+			continue
+		}
+		g := groupOf(id)
+		if _, ok := first[g]; ok {
+			continue
+		}
+		if tb := s.limits[svc]; tb != nil {
+			if wait := tb.wait(now); wait > 0 {
+				waiting[svc] = true
+				if minWait == 0 || wait < minWait {
+					minWait = wait
+				}
+				continue
+			}
+		}
+		first[g] = i
+		groups = append(groups, g)
+	}
+	if len(groups) == 0 {
+		return -1, minWait
+	}
+	sort.Strings(groups)
+	pick := groups[0]
+	for _, g := range groups {
+		if g > s.last {
+			pick = g
+			break
+		}
+	}
+	s.last = pick
+	i := first[pick]
+	if tb := s.limits[serviceOf(s.ids[ids[i]])]; tb != nil {
+		tb.take(now)
+	}
+	return i, 0
+}
+
+// tokenBucket rate limiter. This is not thread safe.
+type tokenBucket struct {
+	qps    float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func (tb *tokenBucket) refill(now time.Time) {
+	if !tb.last.IsZero() {
+		tb.tokens += now.Sub(tb.last).Seconds() * tb.qps
+		if tb.tokens > tb.burst {
+			tb.tokens = tb.burst
+		}
+	}
+	tb.last = now
+}
+
+// wait returns how long until a token is available.
+func (tb *tokenBucket) wait(now time.Time) time.Duration {
+	tb.refill(now)
+	if tb.tokens >= 1 {
+		return 0
+	}
+	if d := time.Duration((1 - tb.tokens) / tb.qps * float64(time.Second)); d > 0 {
+		return d
+	}
+	return time.Nanosecond
+}
+
+func (tb *tokenBucket) take(now time.Time) {
+	tb.refill(now)
+	tb.tokens--
+}
@@ -0,0 +1,90 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trclosure
+
+import (
+	"testing"
+	"time"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestSchedulerInterleave(t *testing.T) {
+	t.Parallel()
+
+	s := newScheduler(nil)
+	ids := []*cloud.ResourceID{
+		{Resource: "backendServices", ProjectID: "p", Key: meta.GlobalKey("bs1")},
+		{Resource: "backendServices", ProjectID: "p", Key: meta.GlobalKey("bs2")},
+		{Resource: "healthChecks", ProjectID: "p", Key: meta.GlobalKey("hc1")},
+		{Resource: "healthChecks", ProjectID: "p", Key: meta.RegionalKey("hc2", "us-central1")},
+		{Resource: "tcpRoutes", APIGroup: meta.APIGroupNetworkServices, ProjectID: "p", Key: meta.GlobalKey("r1")},
+	}
+	var pending []string
+	for _, id := range ids {
+		s.add(id)
+		pending = append(pending, id.String())
+	}
+
+	var got []string
+	for len(pending) > 0 {
+		i, wait := s.Next(time.Now(), pending)
+		if i < 0 {
+			t.Fatalf("Next() = %d, %v; want an item", i, wait)
+		}
+		got = append(got, pending[i])
+		pending = append(pending[:i], pending[i+1:]...)
+	}
+	// Round-robin across the groups.
+	want := []string{ids[0].String(), ids[2].String(), ids[3].String(), ids[4].String(), ids[1].String()}
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Errorf("order: -got,+want: %s", diff)
+	}
+}
+
+func TestSchedulerQPS(t *testing.T) {
+	t.Parallel()
+
+	s := newScheduler(map[string]QPS{"compute": {QPS: 10, Burst: 2}})
+	computeID := &cloud.ResourceID{Resource: "healthChecks", ProjectID: "p", Key: meta.GlobalKey("hc")}
+	nsID := &cloud.ResourceID{Resource: "tcpRoutes", APIGroup: meta.APIGroupNetworkServices, ProjectID: "p", Key: meta.GlobalKey("r")}
+	s.add(computeID)
+	s.add(nsID)
+
+	now := time.Now()
+	compute := []string{computeID.String()}
+	// Burst.
+	for i := 0; i < 2; i++ {
+		if i, _ := s.Next(now, compute); i != 0 {
+			t.Fatalf("Next() = %d, want 0 (burst %d)", i, i)
+		}
+	}
+	i, wait := s.Next(now, compute)
+	if i != -1 || wait != 100*time.Millisecond {
+		t.Errorf("Next() = %d, %v; want -1, 100ms", i, wait)
+	}
+	// Other services are not limited.
+	if i, _ := s.Next(now, []string{computeID.String(), nsID.String()}); i != 1 {
+		t.Errorf("Next() = %d, want 1", i)
+	}
+	// Refill.
+	if i, _ := s.Next(now.Add(100*time.Millisecond), compute); i != 0 {
+		t.Errorf("Next() after refill = %d, want 0", i)
+	}
+}
@@ -40,19 +40,112 @@ func OnGetFunc(f func(n rnode.Builder) error) Option {
 	return func(c *Config) { c.onGet = f }
 }
 
+// SyncFunc replaces rnode.Builder.SyncFromCloud() for fetching the state of
+// each Node. This can be used to substitute the state of some or all of the
+// resources, e.g. to plan against a hypothetical state of the Cloud. f is
+// called concurrently for different Nodes.
+func SyncFunc(f func(ctx context.Context, cl cloud.Cloud, n rnode.Builder) error) Option {
+	return func(c *Config) { c.sync = f }
+}
+
+// SyncErrorFunc is called when fetching the Node from the Cloud fails. If
+// f returns nil, the traversal continues without the references of the
+// Node; f should leave the Node in rnode.NodeStateError. Otherwise the
+// traversal fails with the error returned by f. The default fails the
+// traversal. f is called concurrently for different Nodes.
+func SyncErrorFunc(f func(n rnode.Builder, err error) error) Option {
+	return func(c *Config) { c.syncError = f }
+}
+
+// WorkersOption sets the number of resources that are synced from the Cloud
+// in parallel. The default is 2.
+func WorkersOption(n int) Option {
+	return func(c *Config) { c.workers = n }
+}
+
+// QPS is a rate limit.
+type QPS struct {
+	// QPS is the sustained rate of reads.
+	QPS float64
+	// Burst is the maximum number of reads above the QPS.
+	Burst int
+}
+
+// QPSOption limits the rate of reads from service (e.g. "compute",
+// "networkservices"), i.e. the cloud.ResourceID.APIGroup, with "compute" for
+// resources without an APIGroup. Setting a limit for any service enables
+// scheduling: reads are interleaved across the services, resource types and
+// scopes so that workers are not blocked behind a single rate-limited service.
+func QPSOption(service string, qps QPS) Option {
+	return func(c *Config) {
+		if c.qps == nil {
+			c.qps = map[string]QPS{}
+		}
+		c.qps[service] = qps
+	}
+}
+
+// Progress of the traversal.
+type Progress struct {
+	// ID of the resource that was synced.
+	ID *cloud.ResourceID
+	// Err syncing the resource.
+	Err error
+	// Done is the number of resources synced so far.
+	Done int
+	// Total is the number of resources found so far. This grows as
+	// references are traversed.
+	Total int
+}
+
+// ProgressOption calls f after each resource is synced. Calls to f are
+// serialized.
+func ProgressOption(f func(Progress)) Option {
+	return func(c *Config) { c.progress = f }
+}
+
+// SyncedFunc is called on the Nodes in the initial Builder. Nodes for which f
+// returns true already hold the current state from the Cloud and are not
+// fetched again. Their OutRefs are not traversed, so the caller must include
+// any of their references in the Builder.
+func SyncedFunc(f func(n rnode.Builder) bool) Option {
+	return func(c *Config) { c.synced = f }
+}
+
 // Config for the algorithm.
 type Config struct {
-	onGet func(n rnode.Builder) error
+	onGet     func(n rnode.Builder) error
+	sync      func(ctx context.Context, cl cloud.Cloud, n rnode.Builder) error
+	synced    func(n rnode.Builder) bool
+	syncError func(n rnode.Builder, err error) error
+	workers   int
+	qps       map[string]QPS
+	progress  func(Progress)
 }
 
-func makeConfig(opts ...Option) Config {
+func makeConfig(opts ...Option) (Config, error) {
 	config := Config{
 		onGet: func(rnode.Builder) error { return nil },
+		sync: func(ctx context.Context, cl cloud.Cloud, n rnode.Builder) error {
+			return n.SyncFromCloud(ctx, cl)
+		},
+		synced:    func(rnode.Builder) bool { return false },
+		syncError: func(_ rnode.Builder, err error) error { return err },
+		workers:   2,
+		progress:  func(Progress) {},
 	}
 	for _, o := range opts {
 		o(&config)
 	}
-	return config
+	if config.workers < 1 {
+		return config, makeErr("invalid workers %d", config.workers)
+	}
+	for svc, q := range config.qps {
+		if q.QPS <= 0 || q.Burst < 1 {
+			return config, makeErr("invalid QPS for %q: %+v", svc, q)
+		}
+	}
+	return config, nil
 }
 
 // work is a unit of work for the parallel queue.
@@ -65,15 +158,29 @@ func makeErr(s string, args ...any) error { return fmt.Errorf("TransitiveClosure
 // Do traverses and fetches the graph, adding all the dependencies into
 // the graph, pulling the resource from Cloud as needed.
 func Do(ctx context.Context, cl cloud.Cloud, gr *rgraph.Builder, opts ...Option) error {
+	config, err := makeConfig(opts...)
+	if err != nil {
+		return err
+	}
+
+	logger := klog.FromContext(ctx).WithName("TransitiveClosure")
+	ctx = klog.NewContext(ctx, logger)
 	subctx, cancel := context.WithCancel(ctx)
-	pq := algo.NewParallelQueue[work]()
 
-	err := doInternal(subctx, cl, gr, pq, opts...)
+	queueOpts := []algo.QueueOption{algo.WorkerCount(config.workers)}
+	var sched *scheduler
+	if len(config.qps) > 0 {
+		sched = newScheduler(config.qps)
+		queueOpts = append(queueOpts, algo.UseScheduler(sched))
+	}
+	pq := algo.NewParallelQueue[work](queueOpts...)
+
+	err = doInternal(subctx, cl, gr, pq, config, sched)
 	cancel()
 
 	// Cancel pending traverse operations if we get an error.
 	if err != nil {
-		klog.Errorf("doInternal() = %v", err)
+		logger.Error(err, "Traversal failed")
 		waitErr := pq.WaitForOrphans(ctx)
 		if waitErr != nil {
 			return fmt.Errorf("TransitiveClosure: WaitForOrphans: %w: inner error: %w", waitErr, err)
@@ -81,7 +188,7 @@ func Do(ctx context.Context, cl cloud.Cloud, gr *rgraph.Builder, opts ...Option)
 		return err
 	}
 
-	klog.V(2).Info("Do() = nil")
+	logger.V(2).Info("Traversal done")
 
 	return nil
 }
@@ -91,16 +198,46 @@ func doInternal(
 	cl cloud.Cloud,
 	gr *rgraph.Builder,
 	pq *algo.ParallelQueue[work],
-	opts ...Option,
+	config Config,
+	sched *scheduler,
 ) error {
-	config := makeConfig(opts...)
+	// add work to the queue.
+	add := func(b rnode.Builder) error {
+		if sched != nil {
+			sched.add(b.ID())
+		}
+		if ok := pq.Add(work{b: b}); !ok {
+			return fmt.Errorf("parallel queue is done")
+		}
+		return nil
+	}
 
+	var initial []rnode.Builder
 	for _, nb := range gr.All() {
-		if ok := pq.Add(work{b: nb}); !ok {
-			return fmt.Errorf("parallel queue is done")
+		if !config.synced(nb) {
+			initial = append(initial, nb)
+		}
+	}
+	for _, nb := range initial {
+		if err := add(nb); err != nil {
+			return err
 		}
 	}
 
+	// progressLock serializes the calls to config.progress and guards
+	// progress.
+	var progressLock sync.Mutex
+	progress := Progress{Total: len(initial)}
+	reportProgress := func(id *cloud.ResourceID, added int, err error) {
+		progressLock.Lock()
+		defer progressLock.Unlock()
+		progress.ID = id
+		progress.Err = err
+		progress.Done++
+		progress.Total += added
+		config.progress(progress)
+	}
+
 	// graphLock is held when updating gr (rgraph.Builder).
 	//
 	// Invariant: We traverse and add each Node exactly once. We maintain this
@@ -111,32 +248,42 @@ func doInternal(
 	fn := func(ctx context.Context, w work) error {
 		outRefs, err := syncNode(ctx, cl, config, w.b)
 		if err != nil {
+			reportProgress(w.b.ID(), 0, err)
 			return err
 		}
 
+		var added []rnode.Builder
 		for _, ref := range outRefs {
 			graphLock.Lock()
 
 			if gr.Get(ref.To) != nil {
 				// We have already fetched the Node, don't need to add to the
 				// graph and the work queue.
-				klog.V(2).Infof("ref.To %+v is already in the graph, ignoring", ref)
+				klog.FromContext(ctx).V(2).Info("Reference already in the graph, ignoring", "from", ref.From, "path", ref.Path, "to", ref.To)
 				graphLock.Unlock()
 				continue
 			}
-			toNode, err := all.NewBuilderByID(ref.To)
+			toNode, err := all.NewBuilderForRef(ref.To)
 			if err != nil {
 				graphLock.Unlock()
 				return makeErr("%w", err)
 			}
+			toNode.SetOutRefsMode(w.b.OutRefsMode())
 
 			// Add the untraversed node to the graph.
-			klog.V(2).Infof("ref.To %+v has not been traversed, adding to graph", ref)
+			klog.FromContext(ctx).V(2).Info("Reference has not been traversed, adding to graph", "from", ref.From, "path", ref.Path, "to", ref.To)
 			gr.Add(toNode)
 			graphLock.Unlock()
 
-			if ok := pq.Add(work{b: toNode}); !ok {
-				return fmt.Errorf("parallel queue is done")
+			added = append(added, toNode)
+		}
+
+		// Report progress before adding to the queue so that Done never
+		// exceeds Total.
+		reportProgress(w.b.ID(), len(added), nil)
+		for _, b := range added {
+			if err := add(b); err != nil {
+				return err
 			}
 		}
 
@@ -149,12 +296,20 @@ func doInternal(
 // syncNode loads the resource from the Cloud. This func MUST be threadsafe with
 // respect to the Node it is syncing.
 func syncNode(ctx context.Context, cl cloud.Cloud, config Config, b rnode.Builder) ([]rnode.ResourceRef, error) {
+	logger := klog.FromContext(ctx).WithValues("resourceID", b.ID())
 	// TODO: SyncFromCloud needs to be threadsafe.
-	err := b.SyncFromCloud(ctx, cl)
-	klog.V(2).Infof("node.SyncFromCloud(%s) = %v (%s)", b.ID(), err, pretty.Sprint(b))
+	err := config.sync(klog.NewContext(ctx, logger), cl, b)
+	logger.V(2).Info("SyncFromCloud", "err", err)
+	if loggerV := logger.V(5); loggerV.Enabled() {
+		loggerV.Info("SyncFromCloud node", "node", pretty.Sprint(b))
+	}
 
 	if err != nil {
-		return nil, makeErr("%w", err)
+		if err := config.syncError(b, err); err != nil {
+			return nil, makeErr("%w", err)
+		}
+		logger.V(2).Info("Sync error ignored, no outRefs", "state", b.State())
+		return nil, nil
 	}
 	err = config.onGet(b)
 	if err != nil {
@@ -162,13 +317,13 @@ func syncNode(ctx context.Context, cl cloud.Cloud, config Config, b rnode.Builde
 	}
 
 	if b.State() != rnode.NodeExists {
-		klog.V(2).Infof("Node %s resource state = %s, no outRefs", b.ID(), b.State())
+		logger.V(2).Info("Node does not exist, no outRefs", "state", b.State())
 		return nil, nil
 	}
 
 	if b.Ownership() == rnode.OwnershipExternal {
 		// Nodes that are ExternallyOwned are not traversed for their references.
-		klog.V(2).Infof("Node %s externally owned, no outRefs", b.ID())
+		logger.V(2).Info("Node externally owned, no outRefs")
 		return nil, nil
 	}
 
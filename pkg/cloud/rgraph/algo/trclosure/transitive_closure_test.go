@@ -2,7 +2,10 @@ package trclosure
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sort"
+	"sync"
 	"testing"
 
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
@@ -302,3 +305,177 @@ func TestTransitiveClosure(t *testing.T) {
 		})
 	}
 }
+
+func TestTransitiveClosureOptions(t *testing.T) {
+	// No t.Parallel() due to use of fake.Mocks.Add().
+	const project = "proj-options"
+	mockCloud := cloud.NewMockGCE(&cloud.SingleProjectRouter{ID: project})
+
+	// [a] -> b -> c; [d]
+	g := rgraph.NewBuilder()
+	for _, n := range []struct {
+		name  string
+		to    []string
+		start bool
+	}{
+		{name: "a", to: []string{"b"}, start: true},
+		{name: "b", to: []string{"c"}},
+		{name: "c"},
+		{name: "d", start: true},
+	} {
+		b := fake.NewBuilder(fake.ID(project, meta.GlobalKey(n.name)))
+		b.SetOwnership(rnode.OwnershipManaged)
+		b.SetState(rnode.NodeExists)
+		for _, to := range n.to {
+			b.FakeOutRefs = append(b.FakeOutRefs, rnode.ResourceRef{From: b.ID(), To: fake.ID(project, meta.GlobalKey(to))})
+		}
+		fake.Mocks.Add(b)
+		if n.start {
+			g.Add(b)
+		}
+	}
+
+	var got []Progress
+	err := Do(context.Background(), mockCloud, g,
+		WorkersOption(4),
+		QPSOption("compute", QPS{QPS: 1000, Burst: 1}),
+		ProgressOption(func(p Progress) { got = append(got, p) }),
+	)
+	if err != nil {
+		t.Fatalf("Do() = %v, want nil", err)
+	}
+	if len(got) != 4 {
+		t.Fatalf("len(progress) = %d, want 4", len(got))
+	}
+	if last := got[len(got)-1]; last.Done != 4 || last.Total != 4 {
+		t.Errorf("last progress = %+v, want Done=4, Total=4", last)
+	}
+	for _, p := range got {
+		if p.Done > p.Total {
+			t.Errorf("progress %+v: Done > Total", p)
+		}
+	}
+
+	// Synced nodes are not fetched or traversed.
+	g = rgraph.NewBuilder()
+	for _, name := range []string{"a", "d"} {
+		g.Add(fake.NewBuilder(fake.ID(project, meta.GlobalKey(name))))
+	}
+	got = nil
+	err = Do(context.Background(), mockCloud, g,
+		SyncedFunc(func(n rnode.Builder) bool { return n.ID().Key.Name == "a" }),
+		ProgressOption(func(p Progress) { got = append(got, p) }),
+	)
+	if err != nil {
+		t.Fatalf("Do() = %v, want nil", err)
+	}
+	if len(got) != 1 || got[0].ID.Key.Name != "d" {
+		t.Errorf("progress = %+v, want only d", got)
+	}
+
+	// SyncFunc replaces SyncFromCloud.
+	g = rgraph.NewBuilder()
+	g.Add(fake.NewBuilder(fake.ID(project, meta.GlobalKey("a"))))
+	var lock sync.Mutex
+	var synced []string
+	err = Do(context.Background(), mockCloud, g,
+		SyncFunc(func(ctx context.Context, cl cloud.Cloud, n rnode.Builder) error {
+			lock.Lock()
+			synced = append(synced, n.ID().Key.Name)
+			lock.Unlock()
+			return n.SyncFromCloud(ctx, cl)
+		}),
+	)
+	if err != nil {
+		t.Fatalf("Do() = %v, want nil", err)
+	}
+	sort.Strings(synced)
+	if diff := cmp.Diff(synced, []string{"a", "b", "c"}); diff != "" {
+		t.Errorf("synced: -got,+want: %s", diff)
+	}
+	syncErr := errors.New("injected")
+	err = Do(context.Background(), mockCloud, rgraph.NewBuilder(),
+		SyncFunc(func(context.Context, cloud.Cloud, rnode.Builder) error { return syncErr }))
+	if err != nil {
+		t.Errorf("Do(empty graph) = %v, want nil", err)
+	}
+	g = rgraph.NewBuilder()
+	g.Add(fake.NewBuilder(fake.ID(project, meta.GlobalKey("d"))))
+	err = Do(context.Background(), mockCloud, g,
+		SyncFunc(func(context.Context, cloud.Cloud, rnode.Builder) error { return syncErr }))
+	if !errors.Is(err, syncErr) {
+		t.Errorf("Do() = %v, want %v", err, syncErr)
+	}
+
+	// SyncErrorFunc continues the traversal without the failed Node's
+	// references.
+	g = rgraph.NewBuilder()
+	g.Add(fake.NewBuilder(fake.ID(project, meta.GlobalKey("a"))))
+	g.Add(fake.NewBuilder(fake.ID(project, meta.GlobalKey("d"))))
+	var failed []string
+	err = Do(context.Background(), mockCloud, g,
+		SyncFunc(func(ctx context.Context, cl cloud.Cloud, n rnode.Builder) error {
+			if n.ID().Key.Name == "a" {
+				n.SetState(rnode.NodeStateError)
+				return syncErr
+			}
+			return n.SyncFromCloud(ctx, cl)
+		}),
+		SyncErrorFunc(func(n rnode.Builder, err error) error {
+			lock.Lock()
+			failed = append(failed, n.ID().Key.Name)
+			lock.Unlock()
+			return nil
+		}),
+	)
+	if err != nil {
+		t.Fatalf("Do() = %v, want nil", err)
+	}
+	if diff := cmp.Diff(failed, []string{"a"}); diff != "" {
+		t.Errorf("failed: -got,+want: %s", diff)
+	}
+	if n := len(g.All()); n != 2 {
+		t.Errorf("len(g.All()) = %d, want 2 (references of a are not traversed)", n)
+	}
+	if s := g.Get(fake.ID(project, meta.GlobalKey("a"))).State(); s != rnode.NodeStateError {
+		t.Errorf("a.State() = %s, want %s", s, rnode.NodeStateError)
+	}
+
+	for _, opt := range []Option{
+		WorkersOption(0),
+		QPSOption("compute", QPS{QPS: 0, Burst: 1}),
+		QPSOption("compute", QPS{QPS: 1, Burst: 0}),
+	} {
+		if err := Do(context.Background(), mockCloud, rgraph.NewBuilder(), opt); err == nil {
+			t.Errorf("Do() = nil, want error")
+		}
+	}
+}
+
+func TestTransitiveClosureUnmodeledRef(t *testing.T) {
+	// No t.Parallel() due to use of fake.Mocks.Add().
+	const project = "proj-unmodeled"
+	mockCloud := cloud.NewMockGCE(&cloud.SingleProjectRouter{ID: project})
+	certID := &cloud.ResourceID{Resource: "sslCertificates", ProjectID: project, Key: meta.GlobalKey("cert")}
+	mockCloud.MockResourceURLs.Insert(certID.SelfLink(meta.VersionGA))
+
+	fake.Mocks.Clear()
+	b := fake.NewBuilder(fake.ID(project, meta.GlobalKey("a")))
+	b.SetOwnership(rnode.OwnershipManaged)
+	b.SetState(rnode.NodeExists)
+	b.FakeOutRefs = []rnode.ResourceRef{{From: b.ID(), To: certID}}
+	fake.Mocks.Add(b)
+
+	g := rgraph.NewBuilder()
+	g.Add(fake.NewBuilder(b.ID()))
+	if err := Do(context.Background(), mockCloud, g); err != nil {
+		t.Fatalf("Do() = %v, want nil", err)
+	}
+	cert := g.Get(certID)
+	if cert == nil {
+		t.Fatalf("g.Get(%v) = nil, want generic node", certID)
+	}
+	if cert.State() != rnode.NodeExists || cert.Ownership() != rnode.OwnershipExternal {
+		t.Errorf("cert state = %v, ownership = %v; want %v, %v", cert.State(), cert.Ownership(), rnode.NodeExists, rnode.OwnershipExternal)
+	}
+}
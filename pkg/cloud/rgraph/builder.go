@@ -50,10 +50,24 @@ func (g *Builder) All() []rnode.Builder {
 // Add a node to the resource graph.
 func (g *Builder) Add(node rnode.Builder) { g.nodes[node.ID().MapKey()] = node }
 
+// Remove the node named by id from the graph. This is a no-op if the node
+// does not exist.
+func (g *Builder) Remove(id *cloud.ResourceID) { delete(g.nodes, id.MapKey()) }
+
 // Get the node named by id from the graph. Returns nil if the node does not
 // exist.
 func (g *Builder) Get(id *cloud.ResourceID) rnode.Builder { return g.nodes[id.MapKey()] }
 
+// Clone returns a deep copy of the Builder. The clone can be modified (e.g. for
+// what-if planning) without affecting g.
+func (g *Builder) Clone() *Builder {
+	ret := NewBuilder()
+	for k, nb := range g.nodes {
+		ret.nodes[k] = nb.Clone()
+	}
+	return ret
+}
+
 // Build a Graph for planning from the nodes.
 func (g *Builder) Build() (*Graph, error) {
 	if err := g.computeInRefs(); err != nil {
@@ -88,6 +102,9 @@ func (g *Builder) MustBuild() *Graph {
 // computeInRefs calculates the inbound references to a resource from all of the
 // nodes in the graph.
 func (g *Builder) computeInRefs() error {
+	for _, n := range g.nodes {
+		n.ClearInRefs()
+	}
 	for _, fromNode := range g.nodes {
 		refs, err := fromNode.OutRefs()
 		if err != nil {
@@ -0,0 +1,339 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package composite has builders for load balancers that expand into the
+// constituent resource nodes with the references between them filled in.
+// This lets the caller declare one logical load balancer instead of
+// assembling the ForwardingRule, proxy, UrlMap, BackendService and
+// HealthCheck by hand.
+//
+//	lb := &composite.GlobalHTTPLB{
+//		Project:  "proj",
+//		Name:     "my-lb",
+//		Backends: neg.IDs(),
+//	}
+//	err := lb.Expand(builder)
+//
+// The backend groups (e.g. NEGs from zonalneg) must be added to the Builder
+// separately.
+package composite
+
+import (
+	"fmt"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/api"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/address"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/backendservice"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/forwardingrule"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/healthcheck"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/targethttpproxy"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/urlmap"
+	"google.golang.org/api/compute/v1"
+)
+
+const errPrefix = "Composite"
+
+// GlobalHTTPLB is a global external HTTP load balancer:
+//
+//	[Address] <- ForwardingRule -> TargetHttpProxy -> UrlMap -> BackendService -> HealthCheck
+//
+// All of the resources are named Name.
+type GlobalHTTPLB struct {
+	// Project of the resources.
+	Project string
+	// Name of the resources.
+	Name string
+	// Port to serve on. Defaults to 80.
+	Port int64
+	// StaticIP, if true, reserves a global Address for the ForwardingRule.
+	// Otherwise the ForwardingRule uses an ephemeral IP.
+	StaticIP bool
+	// Backends are the groups (e.g. NEGs) to send traffic to. The groups
+	// must be in the Builder.
+	Backends []*cloud.ResourceID
+	// HealthCheck is called to customize the HealthCheck after the defaults
+	// are filled in. Optional.
+	HealthCheck func(x *compute.HealthCheck)
+	// BackendService is called to customize the BackendService after the
+	// defaults are filled in. Optional.
+	BackendService func(x *compute.BackendService)
+}
+
+// AddressID is the ID of the Address. This is only used if StaticIP is set.
+func (lb *GlobalHTTPLB) AddressID() *cloud.ResourceID {
+	return address.ID(lb.Project, meta.GlobalKey(lb.Name))
+}
+
+// ForwardingRuleID is the ID of the ForwardingRule.
+func (lb *GlobalHTTPLB) ForwardingRuleID() *cloud.ResourceID {
+	return forwardingrule.ID(lb.Project, meta.GlobalKey(lb.Name))
+}
+
+// TargetHttpProxyID is the ID of the TargetHttpProxy.
+func (lb *GlobalHTTPLB) TargetHttpProxyID() *cloud.ResourceID {
+	return targethttpproxy.ID(lb.Project, meta.GlobalKey(lb.Name))
+}
+
+// UrlMapID is the ID of the UrlMap.
+func (lb *GlobalHTTPLB) UrlMapID() *cloud.ResourceID {
+	return urlmap.ID(lb.Project, meta.GlobalKey(lb.Name))
+}
+
+// BackendServiceID is the ID of the BackendService.
+func (lb *GlobalHTTPLB) BackendServiceID() *cloud.ResourceID {
+	return backendservice.ID(lb.Project, meta.GlobalKey(lb.Name))
+}
+
+// HealthCheckID is the ID of the HealthCheck.
+func (lb *GlobalHTTPLB) HealthCheckID() *cloud.ResourceID {
+	return healthcheck.ID(lb.Project, meta.GlobalKey(lb.Name))
+}
+
+// IDs of all of the resources that are part of the load balancer.
+func (lb *GlobalHTTPLB) IDs() []*cloud.ResourceID {
+	ret := []*cloud.ResourceID{
+		lb.ForwardingRuleID(),
+		lb.TargetHttpProxyID(),
+		lb.UrlMapID(),
+		lb.BackendServiceID(),
+		lb.HealthCheckID(),
+	}
+	if lb.StaticIP {
+		ret = append(ret, lb.AddressID())
+	}
+	return ret
+}
+
+// Expand adds the resources for the load balancer to the Builder as
+// OwnershipManaged, NodeExists. Existing nodes with the same IDs are
+// replaced.
+func (lb *GlobalHTTPLB) Expand(b *rgraph.Builder) error {
+	if lb.Project == "" || lb.Name == "" {
+		return fmt.Errorf("%s: GlobalHTTPLB: Project and Name must be set", errPrefix)
+	}
+	port := lb.Port
+	if port == 0 {
+		port = 80
+	}
+
+	hc := &compute.HealthCheck{
+		Name: lb.Name,
+		Type: "HTTP",
+		HttpHealthCheck: &compute.HTTPHealthCheck{
+			Port:        port,
+			RequestPath: "/",
+		},
+	}
+	if lb.HealthCheck != nil {
+		lb.HealthCheck(hc)
+	}
+	if err := add(b, healthcheck.NewMutableHealthCheck(lb.Project, meta.GlobalKey(lb.Name)), hc, healthcheck.NewBuilderWithResource); err != nil {
+		return err
+	}
+
+	bs := &compute.BackendService{
+		Name:                lb.Name,
+		Protocol:            "HTTP",
+		LoadBalancingScheme: "EXTERNAL_MANAGED",
+		HealthChecks:        []string{lb.HealthCheckID().SelfLink(meta.VersionGA)},
+		Backends:            backends(lb.Backends, "RATE"),
+	}
+	if lb.BackendService != nil {
+		lb.BackendService(bs)
+	}
+	if err := add(b, backendservice.NewMutableBackendService(lb.Project, meta.GlobalKey(lb.Name)), bs, backendservice.NewBuilderWithResource); err != nil {
+		return err
+	}
+
+	um := &compute.UrlMap{
+		Name:           lb.Name,
+		DefaultService: lb.BackendServiceID().SelfLink(meta.VersionGA),
+	}
+	if err := add(b, urlmap.NewMutableUrlMap(lb.Project, meta.GlobalKey(lb.Name)), um, urlmap.NewBuilderWithResource); err != nil {
+		return err
+	}
+
+	thp := &compute.TargetHttpProxy{
+		Name:   lb.Name,
+		UrlMap: lb.UrlMapID().SelfLink(meta.VersionGA),
+	}
+	if err := add(b, targethttpproxy.NewMutableTargetHttpProxy(lb.Project, meta.GlobalKey(lb.Name)), thp, targethttpproxy.NewBuilderWithResource); err != nil {
+		return err
+	}
+
+	fr := &compute.ForwardingRule{
+		Name:                lb.Name,
+		IPProtocol:          "TCP",
+		PortRange:           fmt.Sprint(port),
+		LoadBalancingScheme: "EXTERNAL_MANAGED",
+		Target:              lb.TargetHttpProxyID().SelfLink(meta.VersionGA),
+	}
+	if lb.StaticIP {
+		addr := &compute.Address{Name: lb.Name}
+		if err := add(b, address.NewMutableAddress(lb.Project, meta.GlobalKey(lb.Name)), addr, address.NewBuilderWithResource); err != nil {
+			return err
+		}
+		fr.IPAddress = lb.AddressID().SelfLink(meta.VersionGA)
+	}
+	return add(b, forwardingrule.NewMutableForwardingRule(lb.Project, meta.GlobalKey(lb.Name)), fr, forwardingrule.NewBuilderWithResource)
+}
+
+// InternalTCPLB is a regional internal passthrough TCP load balancer:
+//
+//	ForwardingRule -> BackendService -> HealthCheck
+//
+// All of the resources are regional and named Name.
+type InternalTCPLB struct {
+	// Project of the resources.
+	Project string
+	// Region of the resources.
+	Region string
+	// Name of the resources.
+	Name string
+	// Network and Subnetwork (resource URLs) of the ForwardingRule.
+	Network    string
+	Subnetwork string
+	// Ports to serve on. If empty, the ForwardingRule serves all ports.
+	Ports []string
+	// HealthCheckPort is the TCP port to health check. Defaults to 80.
+	HealthCheckPort int64
+	// Backends are the groups (e.g. NEGs) to send traffic to. The groups
+	// must be in the Builder.
+	Backends []*cloud.ResourceID
+	// HealthCheck is called to customize the HealthCheck after the defaults
+	// are filled in. Optional.
+	HealthCheck func(x *compute.HealthCheck)
+	// BackendService is called to customize the BackendService after the
+	// defaults are filled in. Optional.
+	BackendService func(x *compute.BackendService)
+}
+
+func (lb *InternalTCPLB) key() *meta.Key { return meta.RegionalKey(lb.Name, lb.Region) }
+
+// ForwardingRuleID is the ID of the ForwardingRule.
+func (lb *InternalTCPLB) ForwardingRuleID() *cloud.ResourceID {
+	return forwardingrule.ID(lb.Project, lb.key())
+}
+
+// BackendServiceID is the ID of the BackendService.
+func (lb *InternalTCPLB) BackendServiceID() *cloud.ResourceID {
+	return backendservice.ID(lb.Project, lb.key())
+}
+
+// HealthCheckID is the ID of the HealthCheck.
+func (lb *InternalTCPLB) HealthCheckID() *cloud.ResourceID {
+	return healthcheck.ID(lb.Project, lb.key())
+}
+
+// IDs of all of the resources that are part of the load balancer.
+func (lb *InternalTCPLB) IDs() []*cloud.ResourceID {
+	return []*cloud.ResourceID{
+		lb.ForwardingRuleID(),
+		lb.BackendServiceID(),
+		lb.HealthCheckID(),
+	}
+}
+
+// Expand adds the resources for the load balancer to the Builder as
+// OwnershipManaged, NodeExists. Existing nodes with the same IDs are
+// replaced.
+func (lb *InternalTCPLB) Expand(b *rgraph.Builder) error {
+	if lb.Project == "" || lb.Region == "" || lb.Name == "" {
+		return fmt.Errorf("%s: InternalTCPLB: Project, Region and Name must be set", errPrefix)
+	}
+	hcPort := lb.HealthCheckPort
+	if hcPort == 0 {
+		hcPort = 80
+	}
+
+	hc := &compute.HealthCheck{
+		Name:           lb.Name,
+		Type:           "TCP",
+		TcpHealthCheck: &compute.TCPHealthCheck{Port: hcPort},
+	}
+	if lb.HealthCheck != nil {
+		lb.HealthCheck(hc)
+	}
+	if err := add(b, healthcheck.NewMutableHealthCheck(lb.Project, lb.key()), hc, healthcheck.NewBuilderWithResource); err != nil {
+		return err
+	}
+
+	bs := &compute.BackendService{
+		Name:                lb.Name,
+		Protocol:            "TCP",
+		LoadBalancingScheme: "INTERNAL",
+		HealthChecks:        []string{lb.HealthCheckID().SelfLink(meta.VersionGA)},
+		Backends:            backends(lb.Backends, "CONNECTION"),
+	}
+	if lb.BackendService != nil {
+		lb.BackendService(bs)
+	}
+	if err := add(b, backendservice.NewMutableBackendService(lb.Project, lb.key()), bs, backendservice.NewBuilderWithResource); err != nil {
+		return err
+	}
+
+	fr := &compute.ForwardingRule{
+		Name:                lb.Name,
+		IPProtocol:          "TCP",
+		LoadBalancingScheme: "INTERNAL",
+		Network:             lb.Network,
+		Subnetwork:          lb.Subnetwork,
+		BackendService:      lb.BackendServiceID().SelfLink(meta.VersionGA),
+	}
+	if len(lb.Ports) == 0 {
+		fr.AllPorts = true
+	} else {
+		fr.Ports = lb.Ports
+	}
+	return add(b, forwardingrule.NewMutableForwardingRule(lb.Project, lb.key()), fr, forwardingrule.NewBuilderWithResource)
+}
+
+// backends returns a Backend for each of the groups.
+func backends(groups []*cloud.ResourceID, balancingMode string) []*compute.Backend {
+	var ret []*compute.Backend
+	for _, g := range groups {
+		ret = append(ret, &compute.Backend{
+			Group:         g.SelfLink(meta.VersionGA),
+			BalancingMode: balancingMode,
+		})
+	}
+	return ret
+}
+
+// add the obj to the Builder as a managed node.
+func add[GA any, Alpha any, Beta any](
+	b *rgraph.Builder,
+	m api.MutableResource[GA, Alpha, Beta],
+	obj *GA,
+	newBuilder func(api.Resource[GA, Alpha, Beta]) rnode.Builder,
+) error {
+	if err := m.Set(obj); err != nil {
+		return fmt.Errorf("%s: %s: %w", errPrefix, m.ResourceID(), err)
+	}
+	r, err := m.Freeze()
+	if err != nil {
+		return fmt.Errorf("%s: %s: %w", errPrefix, m.ResourceID(), err)
+	}
+	nb := newBuilder(r)
+	nb.SetState(rnode.NodeExists)
+	nb.SetOwnership(rnode.OwnershipManaged)
+	b.Add(nb)
+	return nil
+}
@@ -0,0 +1,193 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package composite
+
+import (
+	"context"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/workflow/ensure"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/zonalneg"
+	"google.golang.org/api/compute/v1"
+)
+
+const proj = "proj"
+
+// negBuilder returns a Builder with zonal NEGs in two zones.
+func negBuilder(t *testing.T, region string) (*rgraph.Builder, *zonalneg.NEG) {
+	t.Helper()
+	b := rgraph.NewBuilder()
+	neg := &zonalneg.NEG{
+		Project: proj,
+		Name:    "neg",
+		Zones:   []string{region + "-a", region + "-b"},
+	}
+	if err := neg.Expand(b); err != nil {
+		t.Fatalf("Expand() = %v, want nil", err)
+	}
+	return b, neg
+}
+
+// checkRef checks that the node from has an outRef to the node to in the
+// Graph.
+func checkRef(t *testing.T, g *rgraph.Graph, from, to *cloud.ResourceID) {
+	t.Helper()
+	n := g.Get(to)
+	if n == nil {
+		t.Errorf("node %s not in graph", to)
+		return
+	}
+	for _, ref := range n.InRefs() {
+		if ref.From.Equal(from) {
+			return
+		}
+	}
+	t.Errorf("node %s InRefs() = %v, want ref from %s", to, n.InRefs(), from)
+}
+
+func TestGlobalHTTPLBExpand(t *testing.T) {
+	for _, staticIP := range []bool{false, true} {
+		b, neg := negBuilder(t, "us-central1")
+		lb := &GlobalHTTPLB{
+			Project:  proj,
+			Name:     "lb",
+			StaticIP: staticIP,
+			Backends: neg.IDs(),
+			HealthCheck: func(x *compute.HealthCheck) {
+				x.HttpHealthCheck.RequestPath = "/healthz"
+			},
+		}
+		if err := lb.Expand(b); err != nil {
+			t.Fatalf("Expand() = %v, want nil", err)
+		}
+		for _, id := range lb.IDs() {
+			nb := b.Get(id)
+			if nb == nil {
+				t.Fatalf("node %s not in graph", id)
+			}
+			if nb.State() != rnode.NodeExists || nb.Ownership() != rnode.OwnershipManaged {
+				t.Errorf("node %s = (%v, %v), want (%v, %v)", id, nb.State(), nb.Ownership(), rnode.NodeExists, rnode.OwnershipManaged)
+			}
+		}
+		if got := b.Get(lb.AddressID()) != nil; got != staticIP {
+			t.Errorf("Address in graph = %t, want %t", got, staticIP)
+		}
+
+		g, err := b.Build()
+		if err != nil {
+			t.Fatalf("Build() = %v, want nil", err)
+		}
+		checkRef(t, g, lb.ForwardingRuleID(), lb.TargetHttpProxyID())
+		checkRef(t, g, lb.TargetHttpProxyID(), lb.UrlMapID())
+		checkRef(t, g, lb.UrlMapID(), lb.BackendServiceID())
+		checkRef(t, g, lb.BackendServiceID(), lb.HealthCheckID())
+		for _, id := range neg.IDs() {
+			checkRef(t, g, lb.BackendServiceID(), id)
+		}
+		if staticIP {
+			checkRef(t, g, lb.ForwardingRuleID(), lb.AddressID())
+		}
+
+		hc, _ := b.Get(lb.HealthCheckID()).Resource().(interface {
+			ToGA() (*compute.HealthCheck, error)
+		}).ToGA()
+		if hc.HttpHealthCheck.RequestPath != "/healthz" || hc.HttpHealthCheck.Port != 80 {
+			t.Errorf("HttpHealthCheck = %+v, want RequestPath=/healthz, Port=80", hc.HttpHealthCheck)
+		}
+	}
+}
+
+func TestInternalTCPLBExpand(t *testing.T) {
+	b, neg := negBuilder(t, "us-central1")
+	lb := &InternalTCPLB{
+		Project:  proj,
+		Region:   "us-central1",
+		Name:     "lb",
+		Ports:    []string{"80", "443"},
+		Backends: neg.IDs(),
+	}
+	if err := lb.Expand(b); err != nil {
+		t.Fatalf("Expand() = %v, want nil", err)
+	}
+	for _, id := range lb.IDs() {
+		if id.Key.Type() != meta.Regional || id.Key.Region != "us-central1" {
+			t.Errorf("ID %s, want regional key in us-central1", id)
+		}
+		if b.Get(id) == nil {
+			t.Fatalf("node %s not in graph", id)
+		}
+	}
+	g, err := b.Build()
+	if err != nil {
+		t.Fatalf("Build() = %v, want nil", err)
+	}
+	checkRef(t, g, lb.ForwardingRuleID(), lb.BackendServiceID())
+	checkRef(t, g, lb.BackendServiceID(), lb.HealthCheckID())
+	for _, id := range neg.IDs() {
+		checkRef(t, g, lb.BackendServiceID(), id)
+	}
+}
+
+func TestExpandMissingBackend(t *testing.T) {
+	b := rgraph.NewBuilder()
+	lb := &GlobalHTTPLB{
+		Project:  proj,
+		Name:     "lb",
+		Backends: []*cloud.ResourceID{{Resource: "networkEndpointGroups", ProjectID: proj, Key: meta.ZonalKey("neg", "us-central1-a")}},
+	}
+	if err := lb.Expand(b); err != nil {
+		t.Fatalf("Expand() = %v, want nil", err)
+	}
+	if _, err := b.Build(); err == nil {
+		t.Errorf("Build() = nil, want error (backend not in graph)")
+	}
+}
+
+func TestExpandInvalid(t *testing.T) {
+	b := rgraph.NewBuilder()
+	if err := (&GlobalHTTPLB{Project: proj}).Expand(b); err == nil {
+		t.Errorf("GlobalHTTPLB.Expand() = nil, want error")
+	}
+	if err := (&InternalTCPLB{Project: proj, Name: "lb"}).Expand(b); err == nil {
+		t.Errorf("InternalTCPLB.Expand() = nil, want error")
+	}
+}
+
+func TestGlobalHTTPLBEnsure(t *testing.T) {
+	ctx := context.Background()
+	m := cloud.NewMockGCE(&cloud.SingleProjectRouter{ID: proj})
+
+	b, neg := negBuilder(t, "us-central1")
+	lb := &GlobalHTTPLB{Project: proj, Name: "lb", StaticIP: true, Backends: neg.IDs()}
+	if err := lb.Expand(b); err != nil {
+		t.Fatalf("Expand() = %v, want nil", err)
+	}
+	if _, err := ensure.Do(ctx, m, b); err != nil {
+		t.Fatalf("ensure.Do() = %v, want nil", err)
+	}
+	fr, err := m.GlobalForwardingRules().Get(ctx, meta.GlobalKey("lb"))
+	if err != nil {
+		t.Fatalf("GlobalForwardingRules().Get() = %v, want nil", err)
+	}
+	if want := lb.TargetHttpProxyID().SelfLink(meta.VersionGA); fr.Target != want {
+		t.Errorf("fr.Target = %q, want %q", fr.Target, want)
+	}
+}
@@ -19,4 +19,12 @@ limitations under the License.
 //
 // This package is highly experimental and exported symbols may change at any
 // time. Do not use in production.
+//
+// # Logging
+//
+// The library uses contextual structured logging: the logger is taken from
+// the context with klog.FromContext(). Use klog.NewContext() to control the
+// sink, verbosity and additional keys (e.g. the K8s object being
+// reconciled). Log entries for Actions carry the "action" key and the
+// Annotations of the Node; entries for Nodes carry the "resourceID" key.
 package rgraph
@@ -0,0 +1,99 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package customnode
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/api"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode"
+)
+
+// NewBuilder returns a Builder for the Widget id stored in store.
+func NewBuilder(id *cloud.ResourceID, store *Store) rnode.Builder {
+	b := &builder{store: store}
+	b.Defaults(id)
+	return b
+}
+
+// NewBuilderWithResource returns a Builder for the Widget r.
+func NewBuilderWithResource(r Widget, store *Store) rnode.Builder {
+	b := &builder{store: store, resource: r}
+	b.Init(r.ResourceID(), rnode.NodeExists, rnode.OwnershipUnknown, r)
+	return b
+}
+
+type builder struct {
+	rnode.BuilderBase
+	store    *Store
+	resource Widget
+}
+
+// builder implements rnode.Builder.
+var _ rnode.Builder = (*builder)(nil)
+
+func (b *builder) Resource() rnode.UntypedResource { return b.resource }
+
+func (b *builder) SetResource(u rnode.UntypedResource) error {
+	r, ok := u.(Widget)
+	if !ok {
+		return fmt.Errorf("Widget: invalid type for SetResource: %T", u)
+	}
+	b.resource = r
+	return nil
+}
+
+func (b *builder) SyncFromCloud(ctx context.Context, gcp cloud.Cloud) error {
+	return rnode.GenericGet[WidgetResource, WidgetResource, WidgetResource](
+		ctx, gcp, "Widget", &ops{store: b.store}, &typeTrait{}, b)
+}
+
+func (b *builder) OutRefs() ([]rnode.ResourceRef, error) {
+	if b.resource == nil {
+		return nil, nil
+	}
+	obj, _ := b.resource.ToGA()
+	if obj.Parent == "" {
+		return nil, nil
+	}
+	return []rnode.ResourceRef{{
+		From: b.ID(),
+		Path: api.Path{}.Pointer().Field("Parent"),
+		To:   ID(b.ID().ProjectID, meta.GlobalKey(obj.Parent)),
+	}}, nil
+}
+
+func (b *builder) Clone() rnode.Builder {
+	return &builder{
+		BuilderBase: b.CopyBase(),
+		store:       b.store,
+		resource:    rnode.CloneResource(b.resource),
+	}
+}
+
+func (b *builder) Build() (rnode.Node, error) {
+	if b.State() == rnode.NodeExists && b.resource == nil {
+		return nil, fmt.Errorf("Widget %s resource is nil with state %s", b.ID(), b.State())
+	}
+	ret := &widgetNode{store: b.store, resource: rnode.CloneResource(b.resource)}
+	if err := ret.InitFromBuilder(b); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
@@ -0,0 +1,61 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// Package customnode is an example of a resource type implemented outside of
+// the rnode packages using only the exported interfaces described in the
+// rnode package documentation. Widget is a made-up resource stored in an
+// in-memory Store that stands in for the client of the resource's API.
+package customnode
+
+import (
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/api"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+)
+
+// WidgetResource is the API object. The same Go type is used for all
+// versions as the API only has a GA version.
+type WidgetResource struct {
+	Name        string
+	Description string
+	Size        int64
+	// Parent is the name of another Widget in the same project. This is an
+	// OutRef of the Widget.
+	Parent string
+	// SelfLink is set by the Store.
+	SelfLink        string
+	NullFields      []string
+	ForceSendFields []string
+}
+
+// ID of the Widget.
+func ID(project string, key *meta.Key) *cloud.ResourceID {
+	return &cloud.ResourceID{
+		Resource:  "widgets",
+		ProjectID: project,
+		Key:       key,
+	}
+}
+
+// MutableWidget is the mutable form of the Widget.
+type MutableWidget = api.MutableResource[WidgetResource, WidgetResource, WidgetResource]
+
+// NewMutableWidget returns a new, empty Widget.
+func NewMutableWidget(project string, key *meta.Key) MutableWidget {
+	return api.NewResource[WidgetResource, WidgetResource, WidgetResource](ID(project, key), &typeTrait{})
+}
+
+// Widget is the frozen form of the resource.
+type Widget = api.Resource[WidgetResource, WidgetResource, WidgetResource]
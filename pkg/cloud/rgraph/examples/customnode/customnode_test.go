@@ -0,0 +1,149 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package customnode
+
+import (
+	"context"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/exec"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/all"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/workflow/plan"
+)
+
+const project = "proj-1"
+
+// testStore is the Store for the Builders created by all.NewBuilderByID.
+var testStore = NewStore()
+
+func init() {
+	all.Register("widgets", func(id *cloud.ResourceID) rnode.Builder { return NewBuilder(id, testStore) })
+}
+
+func TestWidgetSchema(t *testing.T) {
+	if err := NewMutableWidget(project, meta.GlobalKey("w")).CheckSchema(); err != nil {
+		t.Fatalf("CheckSchema() = %v, want nil", err)
+	}
+}
+
+func TestRegister(t *testing.T) {
+	id := ID(project, meta.GlobalKey("w"))
+	b, err := all.NewBuilderByID(id)
+	if err != nil {
+		t.Fatalf("NewBuilderByID(%v) = %v, want nil", id, err)
+	}
+	if _, ok := b.(*builder); !ok {
+		t.Errorf("NewBuilderByID(%v) = %T, want *builder", id, b)
+	}
+
+	for _, resource := range []string{"widgets", "addresses"} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("Register(%q) did not panic", resource)
+				}
+			}()
+			all.Register(resource, func(id *cloud.ResourceID) rnode.Builder { return nil })
+		}()
+	}
+}
+
+func widgetBuilder(t *testing.T, store *Store, name string, f func(*WidgetResource)) rnode.Builder {
+	t.Helper()
+	m := NewMutableWidget(project, meta.GlobalKey(name))
+	m.Access(func(x *WidgetResource) {
+		x.Name = name
+		f(x)
+	})
+	r, err := m.Freeze()
+	if err != nil {
+		t.Fatalf("Freeze() = %v", err)
+	}
+	b := NewBuilderWithResource(r, store)
+	b.SetOwnership(rnode.OwnershipManaged)
+	return b
+}
+
+// planAndExec plans the graph and executes the Actions. It returns the Op planned
+// for each Widget.
+func planAndExec(t *testing.T, want *rgraph.Builder) map[string]rnode.Operation {
+	t.Helper()
+	ctx := context.Background()
+	mock := cloud.NewMockGCE(&cloud.SingleProjectRouter{ID: project})
+
+	res, err := plan.Do(ctx, mock, want.MustBuild())
+	if err != nil {
+		t.Fatalf("plan.Do() = %v, want nil", err)
+	}
+	ops := map[string]rnode.Operation{}
+	for _, n := range res.Want.All() {
+		ops[n.ID().Key.Name] = n.Plan().Op()
+	}
+	ex, err := exec.NewSerialExecutor(mock, res.Actions)
+	if err != nil {
+		t.Fatalf("NewSerialExecutor() = %v, want nil", err)
+	}
+	if _, err := ex.Run(ctx); err != nil {
+		t.Fatalf("Run() = %v, want nil", err)
+	}
+	return ops
+}
+
+func TestPlanAndExec(t *testing.T) {
+	store := NewStore()
+	want := rgraph.NewBuilder()
+	want.Add(widgetBuilder(t, store, "parent", func(x *WidgetResource) { x.Size = 1 }))
+	want.Add(widgetBuilder(t, store, "child", func(x *WidgetResource) { x.Parent = "parent" }))
+
+	checkOps := func(got, want map[string]rnode.Operation) {
+		t.Helper()
+		for name, op := range want {
+			if got[name] != op {
+				t.Errorf("Op(%s) = %s, want %s", name, got[name], op)
+			}
+		}
+	}
+
+	checkOps(planAndExec(t, want), map[string]rnode.Operation{"parent": rnode.OpCreate, "child": rnode.OpCreate})
+	child, err := store.Get(context.Background(), meta.GlobalKey("child"))
+	if err != nil {
+		t.Fatalf("store.Get(child) = %v, want nil", err)
+	}
+	if child.Parent != "parent" || child.SelfLink != "widgets/child" {
+		t.Errorf("store.Get(child) = %+v, want Parent=parent and SelfLink set", child)
+	}
+
+	checkOps(planAndExec(t, want), map[string]rnode.Operation{"parent": rnode.OpNothing, "child": rnode.OpNothing})
+
+	want.Add(widgetBuilder(t, store, "parent", func(x *WidgetResource) { x.Size = 2 }))
+	checkOps(planAndExec(t, want), map[string]rnode.Operation{"parent": rnode.OpUpdate, "child": rnode.OpNothing})
+	if parent, _ := store.Get(context.Background(), meta.GlobalKey("parent")); parent.Size != 2 {
+		t.Errorf("parent.Size = %d, want 2", parent.Size)
+	}
+
+	del := NewBuilder(ID(project, meta.GlobalKey("child")), store)
+	del.SetOwnership(rnode.OwnershipManaged)
+	del.SetState(rnode.NodeDoesNotExist)
+	want.Add(del)
+	checkOps(planAndExec(t, want), map[string]rnode.Operation{"child": rnode.OpDelete})
+	if _, err := store.Get(context.Background(), meta.GlobalKey("child")); !cloud.IsNotFound(err) {
+		t.Errorf("store.Get(child) = %v, want NotFound", err)
+	}
+}
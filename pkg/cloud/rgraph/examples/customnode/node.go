@@ -0,0 +1,81 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package customnode
+
+import (
+	"fmt"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/exec"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode"
+)
+
+type widgetNode struct {
+	rnode.NodeBase
+	store    *Store
+	resource Widget
+}
+
+// widgetNode implements rnode.Node.
+var _ rnode.Node = (*widgetNode)(nil)
+
+func (n *widgetNode) Resource() rnode.UntypedResource { return n.resource }
+
+func (n *widgetNode) Diff(gotNode rnode.Node) (*rnode.PlanDetails, error) {
+	gotRes, ok := gotNode.Resource().(Widget)
+	if !ok {
+		return nil, fmt.Errorf("WidgetNode: invalid type to Diff: %T", gotNode.Resource())
+	}
+	diff, err := gotRes.Diff(n.resource)
+	if err != nil {
+		return nil, fmt.Errorf("WidgetNode: Diff %w", err)
+	}
+	if !diff.HasDiff() {
+		return &rnode.PlanDetails{
+			Operation: rnode.OpNothing,
+			Why:       "No diff between got and want",
+		}, nil
+	}
+	return &rnode.PlanDetails{
+		Operation: rnode.OpUpdate,
+		Why:       "Widget needs to be updated",
+		Diff:      diff,
+	}, nil
+}
+
+func (n *widgetNode) Actions(got rnode.Node) ([]exec.Action, error) {
+	ops := &ops{store: n.store}
+
+	switch op := n.Plan().Op(); op {
+	case rnode.OpCreate:
+		return rnode.CreateActions[WidgetResource, WidgetResource, WidgetResource](ops, n, n.resource)
+	case rnode.OpDelete:
+		return rnode.DeleteActions[WidgetResource, WidgetResource, WidgetResource](ops, got, n)
+	case rnode.OpNothing:
+		return []exec.Action{exec.NewExistsAction(n.ID())}, nil
+	case rnode.OpRecreate:
+		return rnode.RecreateActions[WidgetResource, WidgetResource, WidgetResource](ops, got, n, n.resource)
+	case rnode.OpUpdate:
+		return rnode.UpdateActions[WidgetResource, WidgetResource, WidgetResource](ops, got, n, n.resource, "")
+	default:
+		return nil, fmt.Errorf("WidgetNode: invalid plan op %s", op)
+	}
+}
+
+func (n *widgetNode) Builder() rnode.Builder {
+	b := &builder{store: n.store}
+	b.InitFromNode(n)
+	return b
+}
@@ -0,0 +1,56 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package customnode
+
+import (
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode"
+)
+
+// ops dispatches to the Store. The cloud.Cloud is not used as Widgets are not
+// a Google Cloud API.
+type ops struct {
+	store *Store
+}
+
+// ops implements rnode.GenericOps.
+var _ rnode.GenericOps[WidgetResource, WidgetResource, WidgetResource] = (*ops)(nil)
+
+func (o *ops) GetFuncs(cloud.Cloud) *rnode.GetFuncs[WidgetResource, WidgetResource, WidgetResource] {
+	return &rnode.GetFuncs[WidgetResource, WidgetResource, WidgetResource]{
+		GA: rnode.GetFuncsByScope[WidgetResource]{Global: o.store.Get},
+	}
+}
+
+func (o *ops) CreateFuncs(cloud.Cloud) *rnode.CreateFuncs[WidgetResource, WidgetResource, WidgetResource] {
+	return &rnode.CreateFuncs[WidgetResource, WidgetResource, WidgetResource]{
+		GA: rnode.CreateFuncsByScope[WidgetResource]{Global: o.store.Insert},
+	}
+}
+
+func (o *ops) UpdateFuncs(cloud.Cloud) *rnode.UpdateFuncs[WidgetResource, WidgetResource, WidgetResource] {
+	return &rnode.UpdateFuncs[WidgetResource, WidgetResource, WidgetResource]{
+		GA: rnode.UpdateFuncsByScope[WidgetResource]{Global: o.store.Update},
+		// Widgets do not have a Fingerprint.
+		Options: rnode.UpdateFuncsNoFingerprint,
+	}
+}
+
+func (o *ops) DeleteFuncs(cloud.Cloud) *rnode.DeleteFuncs[WidgetResource, WidgetResource, WidgetResource] {
+	return &rnode.DeleteFuncs[WidgetResource, WidgetResource, WidgetResource]{
+		GA: rnode.DeleteFuncsByScope[WidgetResource]{Global: o.store.Delete},
+	}
+}
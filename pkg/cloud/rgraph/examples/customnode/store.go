@@ -0,0 +1,97 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package customnode
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"google.golang.org/api/googleapi"
+)
+
+// Store is an in-memory backend for Widgets. The methods have the signatures
+// expected by the rnode.GenericOps funcs.
+type Store struct {
+	lock    sync.Mutex
+	objects map[meta.Key]*WidgetResource
+}
+
+// NewStore returns an empty Store.
+func NewStore() *Store {
+	return &Store{objects: map[meta.Key]*WidgetResource{}}
+}
+
+func notFound(key *meta.Key) error {
+	return &googleapi.Error{Code: http.StatusNotFound, Message: fmt.Sprintf("widget %s not found", key)}
+}
+
+// Get the Widget.
+func (s *Store) Get(ctx context.Context, key *meta.Key, options ...cloud.Option) (*WidgetResource, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	obj, ok := s.objects[*key]
+	if !ok {
+		return nil, notFound(key)
+	}
+	ret := *obj
+	return &ret, nil
+}
+
+// Insert the Widget.
+func (s *Store) Insert(ctx context.Context, key *meta.Key, obj *WidgetResource, options ...cloud.Option) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if _, ok := s.objects[*key]; ok {
+		return &googleapi.Error{Code: http.StatusConflict, Message: fmt.Sprintf("widget %s exists", key)}
+	}
+	x := *obj
+	x.SelfLink = "widgets/" + key.Name
+	s.objects[*key] = &x
+	return nil
+}
+
+// Update the Widget.
+func (s *Store) Update(ctx context.Context, key *meta.Key, obj *WidgetResource, options ...cloud.Option) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	old, ok := s.objects[*key]
+	if !ok {
+		return notFound(key)
+	}
+	x := *obj
+	x.SelfLink = old.SelfLink
+	s.objects[*key] = &x
+	return nil
+}
+
+// Delete the Widget.
+func (s *Store) Delete(ctx context.Context, key *meta.Key, options ...cloud.Option) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if _, ok := s.objects[*key]; !ok {
+		return notFound(key)
+	}
+	delete(s.objects, *key)
+	return nil
+}
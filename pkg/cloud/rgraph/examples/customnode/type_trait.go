@@ -0,0 +1,38 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package customnode
+
+import (
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/api"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+)
+
+type typeTrait struct {
+	api.BaseTypeTrait[WidgetResource, WidgetResource, WidgetResource]
+}
+
+// typeTrait implements api.TypeTrait.
+var _ api.TypeTrait[WidgetResource, WidgetResource, WidgetResource] = (*typeTrait)(nil)
+
+func (*typeTrait) FieldTraits(meta.Version) *api.FieldTraits {
+	dt := api.NewFieldTraits()
+	dt.OutputOnly(api.Path{}.Pointer().Field("SelfLink"))
+	dt.AllowZeroValue(api.Path{}.Pointer().Field("Description"))
+	dt.AllowZeroValue(api.Path{}.Pointer().Field("Size"))
+	dt.AllowZeroValue(api.Path{}.Pointer().Field("Parent"))
+	dt.Ordinary(api.Path{}.Pointer().Field("Name"))
+	return dt
+}
@@ -65,6 +65,19 @@ type ActionMetadata struct {
 	Type ActionType
 	// Summary is a human readable description of this action.
 	Summary string
+	// ResourceID modified by this action. This is nil for actions that do
+	// not modify a resource (e.g. ActionTypeMeta).
+	ResourceID *cloud.ResourceID
+	// Annotations from the Node that generated this action. See
+	// WithAnnotations().
+	Annotations map[string]string
+	// Phase of the action. See WithPhase().
+	Phase Phase
+	// ValidateOnly is true if Run() supports a context with
+	// WithValidateOnly(), i.e. it validates the change without applying it
+	// or returns DryRun() if the API cannot validate the change. See
+	// ValidateOnlyOption.
+	ValidateOnly bool
 }
 
 // ActionBase is a helper that implements some standard behaviors of common
@@ -95,7 +108,7 @@ func (b *ActionBase) Signal(ev Event) bool {
 // It has no other side effects.
 func NewExistsAction(id *cloud.ResourceID) Action {
 	return &eventAction{
-		events: EventList{&existsEvent{id: id}},
+		events: EventList{&ExistsEvent{id: id}},
 	}
 }
 
@@ -18,6 +18,7 @@ package exec
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"testing"
 
@@ -162,9 +163,84 @@ func TestEventAction(t *testing.T) {
 		Signal:    false,
 		S:         "EventAction([Exists(res1:proj1/x)])",
 		Pending:   nil,
-		RunEvents: EventList{&existsEvent{id: resID}},
+		RunEvents: EventList{&ExistsEvent{id: resID}},
 	})
 	if diff != "" {
 		t.Errorf("diff: -got/+want: %s", diff)
 	}
 }
+
+func TestWithAnnotations(t *testing.T) {
+	a := &testAction{name: "A"}
+	if got := WithAnnotations(a, nil); got != Action(a) {
+		t.Errorf("WithAnnotations(a, nil) = %v, want a", got)
+	}
+
+	wa := WithAnnotations(a, map[string]string{"service": "ns/name"})
+	m := wa.Metadata()
+	if m.Name != a.Metadata().Name {
+		t.Errorf("Metadata().Name = %q, want %q", m.Name, a.Metadata().Name)
+	}
+	if diff := cmp.Diff(m.Annotations, map[string]string{"service": "ns/name"}); diff != "" {
+		t.Errorf("Metadata().Annotations -got,+want: %s", diff)
+	}
+	if wa.String() != a.String() {
+		t.Errorf("String() = %q, want %q", wa.String(), a.String())
+	}
+}
+
+func TestWithWant(t *testing.T) {
+	a := &testAction{name: "A", ActionBase: ActionBase{Want: EventList{StringEvent("x")}}}
+	if got := WithWant(a); got != Action(a) {
+		t.Errorf("WithWant(a) = %v, want a", got)
+	}
+
+	wa := WithWant(a, StringEvent("x"), StringEvent("y"))
+	if wa.CanRun() {
+		t.Fatalf("CanRun() = true, want false")
+	}
+	if !wa.Signal(StringEvent("x")) {
+		t.Errorf("Signal(x) = false, want true")
+	}
+	if diff := cmp.Diff(wa.PendingEvents(), EventList{StringEvent("y")}); diff != "" {
+		t.Errorf("PendingEvents() -got,+want: %s", diff)
+	}
+	if wa.CanRun() {
+		t.Errorf("CanRun() = true after Signal(x), want false")
+	}
+	if !wa.Signal(StringEvent("y")) {
+		t.Errorf("Signal(y) = false, want true")
+	}
+	if !wa.CanRun() {
+		t.Errorf("CanRun() = false after all signals, want true")
+	}
+	if wa.Signal(StringEvent("z")) {
+		t.Errorf("Signal(z) = true, want false")
+	}
+}
+
+func TestWithSignal(t *testing.T) {
+	a := &testAction{name: "A", events: EventList{StringEvent("a")}}
+	if got := WithSignal(a); got != Action(a) {
+		t.Errorf("WithSignal(a) = %v, want a", got)
+	}
+
+	sa := WithSignal(a, StringEvent("x"))
+	want := EventList{StringEvent("a"), StringEvent("x")}
+	if diff := cmp.Diff(sa.DryRun(), want); diff != "" {
+		t.Errorf("DryRun() -got,+want: %s", diff)
+	}
+	got, err := sa.Run(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("Run() = %v, want nil", err)
+	}
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Errorf("Run() -got,+want: %s", diff)
+	}
+
+	a.err = errors.New("injected")
+	got, _ = sa.Run(context.Background(), nil)
+	if diff := cmp.Diff(got, EventList{StringEvent("a")}); diff != "" {
+		t.Errorf("Run() with error -got,+want: %s", diff)
+	}
+}
@@ -0,0 +1,46 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package exec
+
+// annotatedAction adds Annotations to the Metadata of an Action.
+type annotatedAction struct {
+	Action
+	annotations map[string]string
+}
+
+// WithAnnotations returns an Action that adds the annotations to the
+// ActionMetadata of a. Annotations are used to correlate Actions with the
+// objects that created them (e.g. in Tracer output and logs). Returns a
+// unchanged if there are no annotations.
+func WithAnnotations(a Action, annotations map[string]string) Action {
+	if len(annotations) == 0 {
+		return a
+	}
+	return &annotatedAction{Action: a, annotations: annotations}
+}
+
+func (a *annotatedAction) Metadata() *ActionMetadata {
+	m := *a.Action.Metadata()
+	m.Annotations = map[string]string{}
+	for k, v := range a.Action.Metadata().Annotations {
+		m.Annotations[k] = v
+	}
+	for k, v := range a.annotations {
+		m.Annotations[k] = v
+	}
+	return &m
+}
@@ -0,0 +1,234 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package exec
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"k8s.io/klog/v2"
+)
+
+// RequiresApprovalAnnotationKey marks an Action that must be approved
+// before it runs. See RequireApproval() and ApprovalGate.
+const RequiresApprovalAnnotationKey = "rgraph-requires-approval"
+
+// RequireApproval returns a with RequiresApprovalAnnotationKey set. The
+// Action is held by the ApprovalGate until it is approved.
+func RequireApproval(a Action) Action {
+	return WithAnnotations(a, map[string]string{RequiresApprovalAnnotationKey: "true"})
+}
+
+// RequiresApproval returns true if a has RequiresApprovalAnnotationKey.
+func RequiresApproval(a Action) bool {
+	return a.Metadata().Annotations[RequiresApprovalAnnotationKey] == "true"
+}
+
+// NewApprovalEvent returns the Event signalled when the Action named name
+// (ActionMetadata.Name) is approved. String() is "Approved(<name>)".
+func NewApprovalEvent(name string) Event {
+	return StringEvent(fmt.Sprintf("Approved(%s)", name))
+}
+
+// NewApprovalGate returns a new ApprovalGate.
+func NewApprovalGate() *ApprovalGate {
+	return &ApprovalGate{
+		gated:    map[string]bool{},
+		approved: map[string]chan struct{}{},
+		held:     map[string]bool{},
+	}
+}
+
+// ApprovalGate holds the Actions that require approval (see
+// RequireApproval()) until Approve() is called for them, e.g. by an operator
+// reviewing the deletion of a ForwardingRule.
+//
+// Call Wrap() on the Actions before creating the Executor. Each gated Action
+// gets an approval Action (ActionTypeMeta) that waits for the same Events as
+// the gated Action, then blocks until the Action is approved or the context
+// is done. The other Actions are not held, so use the parallel Executor to
+// make progress on them while waiting.
+type ApprovalGate struct {
+	lock sync.Mutex
+	// gated are the names of the Actions that require approval.
+	gated map[string]bool
+	// approved is closed when the named Action is approved.
+	approved map[string]chan struct{}
+	// held are the names of the Actions that are ready to run and are
+	// waiting for approval.
+	held map[string]bool
+}
+
+// Wrap the Actions that require approval. Actions that do not require
+// approval are returned unchanged.
+func (g *ApprovalGate) Wrap(actions []Action) []Action {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+
+	var ret []Action
+	for _, a := range actions {
+		if !RequiresApproval(a) {
+			ret = append(ret, a)
+			continue
+		}
+		name := a.Metadata().Name
+		g.gated[name] = true
+		if _, ok := g.approved[name]; !ok {
+			g.approved[name] = make(chan struct{})
+		}
+		approval := &approvalAction{
+			ActionBase: ActionBase{Want: append(EventList(nil), a.PendingEvents()...)},
+			g:          g,
+			name:       name,
+		}
+		ret = append(ret, WithWant(a, NewApprovalEvent(name)), approval)
+	}
+	return ret
+}
+
+// Approve the Action with the given name (ActionMetadata.Name). The Action
+// may be approved before it is held. Returns an error if the Action was not
+// gated by Wrap().
+func (g *ApprovalGate) Approve(name string) error {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+
+	if !g.gated[name] {
+		return fmt.Errorf("ApprovalGate: no Action %q requires approval", name)
+	}
+	select {
+	case <-g.approved[name]:
+		// Already approved.
+	default:
+		close(g.approved[name])
+	}
+	return nil
+}
+
+// Approved returns true if the Action with the given name was approved.
+func (g *ApprovalGate) Approved(name string) bool {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+
+	ch, ok := g.approved[name]
+	if !ok {
+		return false
+	}
+	select {
+	case <-ch:
+		return true
+	default:
+		return false
+	}
+}
+
+// Approvals returns the names of the approved Actions, sorted.
+func (g *ApprovalGate) Approvals() []string {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+
+	var ret []string
+	for name, ch := range g.approved {
+		select {
+		case <-ch:
+			ret = append(ret, name)
+		default:
+		}
+	}
+	sort.Strings(ret)
+	return ret
+}
+
+// Restore the approvals from a stopped run (see ExecutorState). Call this
+// after Wrap(). Approvals for Actions that were not gated by Wrap() are
+// ignored, e.g. for Actions that completed in the stopped run.
+func (g *ApprovalGate) Restore(st *ExecutorState) {
+	for _, name := range st.Approved {
+		// Ignore the error as the Action may have completed.
+		g.Approve(name)
+	}
+}
+
+// Held returns the names of the Actions that are waiting for approval,
+// sorted.
+func (g *ApprovalGate) Held() []string {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+
+	var ret []string
+	for name := range g.held {
+		ret = append(ret, name)
+	}
+	sort.Strings(ret)
+	return ret
+}
+
+// wait blocks until the Action is approved or the context is done.
+func (g *ApprovalGate) wait(ctx context.Context, name string) error {
+	g.lock.Lock()
+	ch := g.approved[name]
+	g.held[name] = true
+	g.lock.Unlock()
+
+	defer func() {
+		g.lock.Lock()
+		delete(g.held, name)
+		g.lock.Unlock()
+	}()
+
+	select {
+	case <-ch:
+		return nil
+	default:
+	}
+	klog.FromContext(ctx).V(2).Info("Waiting for approval", "action", name)
+	select {
+	case <-ch:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("ApprovalGate: %s was not approved: %w", name, ctx.Err())
+	}
+}
+
+// approvalAction signals NewApprovalEvent(name) once the Action is approved.
+type approvalAction struct {
+	ActionBase
+	g    *ApprovalGate
+	name string
+}
+
+func (a *approvalAction) Run(ctx context.Context, _ cloud.Cloud) (EventList, error) {
+	if err := a.g.wait(ctx, a.name); err != nil {
+		return nil, err
+	}
+	return EventList{NewApprovalEvent(a.name)}, nil
+}
+
+func (a *approvalAction) DryRun() EventList { return EventList{NewApprovalEvent(a.name)} }
+
+func (a *approvalAction) String() string { return fmt.Sprintf("ApprovalAction(%s)", a.name) }
+
+func (a *approvalAction) Metadata() *ActionMetadata {
+	return &ActionMetadata{
+		Name:    a.String(),
+		Type:    ActionTypeMeta,
+		Summary: fmt.Sprintf("Wait for approval of %s", a.name),
+	}
+}
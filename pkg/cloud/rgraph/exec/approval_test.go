@@ -0,0 +1,147 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package exec
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestApprovalGate(t *testing.T) {
+	var ran atomic.Int32
+	hook := func(context.Context) error {
+		ran.Add(1)
+		return nil
+	}
+	newActions := func() (a, b, c *testAction) {
+		a = &testAction{name: "A", events: EventList{StringEvent("A")}, runHook: hook}
+		b = &testAction{name: "B", events: EventList{StringEvent("B")}, runHook: hook}
+		b.Want = EventList{StringEvent("A")}
+		c = &testAction{name: "C", events: EventList{StringEvent("C")}, runHook: hook}
+		return a, b, c
+	}
+
+	t.Run("held until approved", func(t *testing.T) {
+		ran.Store(0)
+		a, b, c := newActions()
+		gate := NewApprovalGate()
+		acts := gate.Wrap([]Action{a, RequireApproval(b), c})
+		if len(acts) != 4 {
+			t.Fatalf("len(Wrap()) = %d, want 4", len(acts))
+		}
+		ex, err := NewParallelExecutor(nil, acts)
+		if err != nil {
+			t.Fatalf("NewParallelExecutor() = %v", err)
+		}
+		type runResult struct {
+			result *Result
+			err    error
+		}
+		done := make(chan runResult)
+		go func() {
+			result, err := ex.Run(context.Background())
+			done <- runResult{result, err}
+		}()
+
+		name := b.Metadata().Name
+		for len(gate.Held()) == 0 {
+			time.Sleep(time.Millisecond)
+		}
+		if got := gate.Held(); len(got) != 1 || got[0] != name {
+			t.Fatalf("Held() = %v, want [%s]", got, name)
+		}
+		// A must have completed for B to be held.
+		if got := ran.Load(); got < 1 || got > 2 {
+			t.Errorf("ran = %d before approval, want A and maybe C", got)
+		}
+		if err := gate.Approve(name); err != nil {
+			t.Fatalf("Approve(%q) = %v", name, err)
+		}
+		r := <-done
+		if r.err != nil {
+			t.Fatalf("Run() = %v, want nil", r.err)
+		}
+		if got := ran.Load(); got != 3 {
+			t.Errorf("ran = %d, want 3", got)
+		}
+		if len(r.result.Completed) != 4 {
+			t.Errorf("len(Completed) = %d, want 4", len(r.result.Completed))
+		}
+		if got := gate.Held(); len(got) != 0 {
+			t.Errorf("Held() = %v, want []", got)
+		}
+	})
+
+	t.Run("approved before run", func(t *testing.T) {
+		a, b, c := newActions()
+		gate := NewApprovalGate()
+		acts := gate.Wrap([]Action{a, RequireApproval(b), c})
+		if err := gate.Approve(b.Metadata().Name); err != nil {
+			t.Fatalf("Approve() = %v", err)
+		}
+		if !gate.Approved(b.Metadata().Name) {
+			t.Errorf("Approved() = false, want true")
+		}
+		ex, err := NewSerialExecutor(nil, acts)
+		if err != nil {
+			t.Fatalf("NewSerialExecutor() = %v", err)
+		}
+		if _, err := ex.Run(context.Background()); err != nil {
+			t.Errorf("Run() = %v, want nil", err)
+		}
+	})
+
+	t.Run("not approved", func(t *testing.T) {
+		a, b, c := newActions()
+		gate := NewApprovalGate()
+		acts := gate.Wrap([]Action{a, RequireApproval(b), c})
+		ex, err := NewParallelExecutor(nil, acts, TimeoutOption(20*time.Millisecond))
+		if err != nil {
+			t.Fatalf("NewParallelExecutor() = %v", err)
+		}
+		result, err := ex.Run(context.Background())
+		if err == nil {
+			t.Fatalf("Run() = nil, want error")
+		}
+		if len(result.Pending) != 1 || result.Pending[0].Metadata().Name != b.Metadata().Name {
+			t.Errorf("Pending = %v, want [%v]", result.Pending, b)
+		}
+		if len(result.Errors) != 1 || !errors.Is(result.Errors[0].Err, context.DeadlineExceeded) {
+			t.Errorf("Errors = %v, want the approval Action with %v", result.Errors, context.DeadlineExceeded)
+		}
+	})
+
+	t.Run("dry run", func(t *testing.T) {
+		a, b, c := newActions()
+		gate := NewApprovalGate()
+		ex, err := NewSerialExecutor(nil, gate.Wrap([]Action{a, RequireApproval(b), c}), DryRunOption(true))
+		if err != nil {
+			t.Fatalf("NewSerialExecutor() = %v", err)
+		}
+		if _, err := ex.Run(context.Background()); err != nil {
+			t.Errorf("Run() = %v, want nil", err)
+		}
+	})
+
+	gate := NewApprovalGate()
+	if err := gate.Approve("unknown"); err == nil {
+		t.Errorf("Approve(unknown) = nil, want error")
+	}
+}
@@ -0,0 +1,99 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package exec
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+)
+
+// ChangeRecord is an entry in the ChangeLog for a change applied to a
+// resource.
+type ChangeRecord struct {
+	// Time the change was applied.
+	Time time.Time `json:"time"`
+	// Type of the Action that applied the change.
+	Type ActionType `json:"type"`
+	// Action is the name of the Action.
+	Action string `json:"action"`
+	// ID of the resource.
+	ID *cloud.ResourceID `json:"-"`
+	// Resource is the string form of ID.
+	Resource string `json:"resource"`
+	// Before is the JSON of the resource before the change, without the
+	// OutputOnly fields. This is nil if the prior state is not known.
+	Before json.RawMessage `json:"before,omitempty"`
+	// After is the JSON of the resource after the change, without the
+	// OutputOnly fields. This is nil for deletes.
+	After json.RawMessage `json:"after,omitempty"`
+}
+
+// ChangeLog is a sink for the changes applied by the Actions, e.g. for post
+// incident review of what a controller changed. Record() may be called
+// concurrently by the parallel executors.
+type ChangeLog interface {
+	Record(ctx context.Context, r *ChangeRecord) error
+}
+
+// ChangeLogOption records the changes applied by the update and delete
+// Actions to l. Record() is called after the change was applied; an error
+// from Record() fails the Action.
+func ChangeLogOption(l ChangeLog) Option {
+	return func(c *ExecutorConfig) { c.ChangeLog = l }
+}
+
+var changeLogContextKey = contextKey("change log")
+
+// WithChangeLog returns a context that records the changes applied by
+// Actions run with it. See ChangeLogOption.
+func WithChangeLog(ctx context.Context, l ChangeLog) context.Context {
+	return context.WithValue(ctx, changeLogContextKey, l)
+}
+
+// ChangeLogFrom returns the ChangeLog that Actions should record their
+// changes to. Returns nil if changes are not recorded.
+func ChangeLogFrom(ctx context.Context) ChangeLog {
+	l, _ := ctx.Value(changeLogContextKey).(ChangeLog)
+	return l
+}
+
+// NewJSONChangeLog returns a ChangeLog that writes each ChangeRecord to w as
+// a line of JSON.
+func NewJSONChangeLog(w io.Writer) ChangeLog {
+	return &jsonChangeLog{enc: json.NewEncoder(w)}
+}
+
+type jsonChangeLog struct {
+	lock sync.Mutex
+	enc  *json.Encoder
+}
+
+func (l *jsonChangeLog) Record(_ context.Context, r *ChangeRecord) error {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	rr := *r
+	if rr.Resource == "" && rr.ID != nil {
+		rr.Resource = rr.ID.String()
+	}
+	return l.enc.Encode(&rr)
+}
@@ -0,0 +1,66 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package exec
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+)
+
+// ConflictError is returned by an update Action when the resource was
+// modified after the plan was made, i.e. the fingerprint observed at plan
+// time no longer matches the live resource.
+type ConflictError struct {
+	// ID of the resource.
+	ID *cloud.ResourceID
+	// Want is the fingerprint observed at plan time.
+	Want string
+	// Got is the live fingerprint. This is empty if the conflict was
+	// reported by the server (HTTP 412) rather than detected by the
+	// Action.
+	Got string
+	// Err is the underlying error, if any.
+	Err error
+}
+
+func (e *ConflictError) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("conflict updating %v (fingerprint %q): %v", e.ID, e.Want, e.Err)
+	}
+	return fmt.Sprintf("conflict updating %v: fingerprint changed from %q to %q", e.ID, e.Want, e.Got)
+}
+
+func (e *ConflictError) Unwrap() error { return e.Err }
+
+type contextKey string
+
+var strictFingerprintContextKey = contextKey("strict fingerprint")
+
+// WithStrictFingerprint returns a context that enables strict fingerprint
+// checking for Actions run with it. See StrictFingerprintOption.
+func WithStrictFingerprint(ctx context.Context) context.Context {
+	return context.WithValue(ctx, strictFingerprintContextKey, true)
+}
+
+// StrictFingerprint returns true if the Action should enforce that the live
+// fingerprint of the resource matches the one observed at plan time.
+func StrictFingerprint(ctx context.Context) bool {
+	v, _ := ctx.Value(strictFingerprintContextKey).(bool)
+	return v
+}
@@ -0,0 +1,75 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package exec
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"k8s.io/klog/v2"
+)
+
+// NewCoordinator returns a new Coordinator.
+func NewCoordinator() *Coordinator {
+	return &Coordinator{locks: cloud.NewKeyedLock()}
+}
+
+// Coordinator serializes Actions that modify the same resource across
+// Executors in the same process. This is used when separate plans touch the
+// same resource, e.g. two Services that share a BackendService are
+// reconciled concurrently.
+//
+// Use a single Coordinator for all of the Executors and call Wrap() on the
+// Actions before creating the Executor. See also KeyedLockOption.
+type Coordinator struct {
+	locks *cloud.KeyedLock
+}
+
+// Wrap the Actions so that only one Action for a given resource
+// (ActionMetadata.ResourceID) runs at a time. Actions without a ResourceID
+// are returned unchanged.
+func (c *Coordinator) Wrap(actions []Action) []Action {
+	var ret []Action
+	for _, a := range actions {
+		id := a.Metadata().ResourceID
+		if id == nil {
+			ret = append(ret, a)
+			continue
+		}
+		ret = append(ret, &coordinatedAction{Action: a, c: c, id: id})
+	}
+	return ret
+}
+
+// coordinatedAction holds the Coordinator lock for the resource while
+// running.
+type coordinatedAction struct {
+	Action
+	c  *Coordinator
+	id *cloud.ResourceID
+}
+
+func (a *coordinatedAction) Run(ctx context.Context, cl cloud.Cloud) (EventList, error) {
+	klog.FromContext(ctx).V(4).Info("Waiting for resource lock", "resourceID", a.id)
+	release, err := a.c.locks.Lock(ctx, a.id)
+	if err != nil {
+		return nil, fmt.Errorf("Coordinator: waiting for %v: %w", a.id, err)
+	}
+	defer release()
+	return a.Action.Run(ctx, cl)
+}
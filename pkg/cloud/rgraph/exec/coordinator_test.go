@@ -0,0 +1,183 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package exec
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+)
+
+// resourceAction is a testAction that modifies a resource.
+type resourceAction struct {
+	testAction
+	id *cloud.ResourceID
+}
+
+func (a *resourceAction) Metadata() *ActionMetadata {
+	m := a.testAction.Metadata()
+	m.ResourceID = a.id
+	return m
+}
+
+func TestCoordinator(t *testing.T) {
+	id := &cloud.ResourceID{Resource: "fake", Key: meta.GlobalKey("shared")}
+
+	var (
+		lock      sync.Mutex
+		active    int
+		maxActive int
+	)
+	runHook := func(context.Context) error {
+		lock.Lock()
+		active++
+		if active > maxActive {
+			maxActive = active
+		}
+		lock.Unlock()
+
+		time.Sleep(10 * time.Millisecond)
+
+		lock.Lock()
+		active--
+		lock.Unlock()
+		return nil
+	}
+
+	c := NewCoordinator()
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			a := &resourceAction{testAction: testAction{name: "A", runHook: runHook}, id: id}
+			ex, err := NewParallelExecutor(nil, c.Wrap([]Action{a}))
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if _, err := ex.Run(context.Background()); err != nil {
+				t.Errorf("Run() = %v, want nil", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if maxActive != 1 {
+		t.Errorf("maxActive = %d, want 1", maxActive)
+	}
+	if c.locks.Len() != 0 {
+		t.Errorf("c.locks.Len() = %d, want 0 (locks were not released)", c.locks.Len())
+	}
+}
+
+func TestCoordinatorWrap(t *testing.T) {
+	c := NewCoordinator()
+	noID := &testAction{name: "A"}
+	withID := &resourceAction{testAction: testAction{name: "B"}, id: &cloud.ResourceID{Resource: "fake", Key: meta.GlobalKey("x")}}
+
+	got := c.Wrap([]Action{noID, withID})
+	if got[0] != Action(noID) {
+		t.Errorf("Wrap()[0] = %v, want unchanged", got[0])
+	}
+	if _, ok := got[1].(*coordinatedAction); !ok {
+		t.Errorf("Wrap()[1] = %T, want *coordinatedAction", got[1])
+	}
+}
+
+func TestCoordinatorCancel(t *testing.T) {
+	id := &cloud.ResourceID{Resource: "fake", Key: meta.GlobalKey("shared")}
+	c := NewCoordinator()
+
+	release, err := c.locks.Lock(context.Background(), id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer release()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	a := c.Wrap([]Action{&resourceAction{testAction: testAction{name: "A"}, id: id}})[0]
+	if _, err := a.Run(ctx, nil); !errors.Is(err, context.Canceled) {
+		t.Errorf("Run() = %v, want context.Canceled", err)
+	}
+}
+
+func TestKeyedLockOption(t *testing.T) {
+	id := &cloud.ResourceID{Resource: "fake", Key: meta.GlobalKey("shared")}
+
+	var (
+		lock      sync.Mutex
+		active    int
+		maxActive int
+	)
+	runHook := func(context.Context) error {
+		lock.Lock()
+		active++
+		if active > maxActive {
+			maxActive = active
+		}
+		lock.Unlock()
+
+		time.Sleep(10 * time.Millisecond)
+
+		lock.Lock()
+		active--
+		lock.Unlock()
+		return nil
+	}
+
+	kl := cloud.NewKeyedLock()
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			a := &resourceAction{testAction: testAction{name: "A", runHook: runHook}, id: id}
+			var (
+				ex  Executor
+				err error
+			)
+			// Mix Executor types sharing the same lock.
+			if i%2 == 0 {
+				ex, err = NewParallelExecutor(nil, []Action{a}, KeyedLockOption(kl))
+			} else {
+				ex, err = NewSerialExecutor(nil, []Action{a}, KeyedLockOption(kl))
+			}
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if _, err := ex.Run(context.Background()); err != nil {
+				t.Errorf("Run() = %v, want nil", err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	if maxActive != 1 {
+		t.Errorf("maxActive = %d, want 1", maxActive)
+	}
+	if kl.Len() != 0 {
+		t.Errorf("kl.Len() = %d, want 0 (locks were not released)", kl.Len())
+	}
+}
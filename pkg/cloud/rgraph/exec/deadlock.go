@@ -0,0 +1,89 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package exec
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DeadlockError is returned by the parallel Executor when there are pending
+// Actions but no Action is running or can run and no Action failed, e.g. the
+// Actions have a dependency cycle or wait for an Event that no Action
+// signals.
+type DeadlockError struct {
+	// Blocked are the pending Actions with the Events they are still
+	// waiting for.
+	Blocked []BlockedAction
+}
+
+// BlockedAction is a pending Action in a DeadlockError.
+type BlockedAction struct {
+	Action Action
+	// Want are the Events the Action is waiting for.
+	Want EventList
+	// Missing are the Events in Want that are not signalled by any of the
+	// pending Actions. These will never be signalled.
+	Missing EventList
+}
+
+func (e *DeadlockError) Error() string {
+	var parts []string
+	for _, b := range e.Blocked {
+		s := fmt.Sprintf("%v waits for %v", b.Action, b.Want)
+		if len(b.Missing) > 0 {
+			s += fmt.Sprintf(" (no Action signals %v)", b.Missing)
+		}
+		parts = append(parts, s)
+	}
+	return fmt.Sprintf("deadlock: %d Actions blocked: %s", len(e.Blocked), strings.Join(parts, "; "))
+}
+
+// Unwrap returns ErrPendingActions so that callers checking for pending
+// Actions will also handle a DeadlockError.
+func (e *DeadlockError) Unwrap() error { return ErrPendingActions }
+
+// newDeadlockError analyzes the pending Actions to find the Events each is
+// waiting for.
+func newDeadlockError(pending []Action) *DeadlockError {
+	var signalled EventList
+	for _, a := range pending {
+		signalled = append(signalled, a.DryRun()...)
+	}
+	ret := &DeadlockError{}
+	for _, a := range pending {
+		b := BlockedAction{Action: a, Want: a.PendingEvents()}
+		for _, ev := range b.Want {
+			if !containsEvent(signalled, ev) {
+				b.Missing = append(b.Missing, ev)
+			}
+		}
+		ret.Blocked = append(ret.Blocked, b)
+	}
+	return ret
+}
+
+// containsEvent is true if ev, as an Event in a Want list, is satisfied by
+// one of the events in el.
+func containsEvent(el EventList, ev Event) bool {
+	for _, x := range el {
+		if ev.Equal(x) {
+			return true
+		}
+	}
+	return false
+}
@@ -18,6 +18,8 @@ limitations under the License.
 //
 // - Actions produce Events when they complete.
 // - Events fulfill dependencies of Actions so they can be executed.
+// - AnyOf() and Not() combine Events into richer dependencies.
+// - Spawn() adds Actions that are only known at run time.
 //
 // # Example
 //
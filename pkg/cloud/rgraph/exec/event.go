@@ -18,12 +18,19 @@ package exec
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
 )
 
 // Event occurs when an Action completes. Events can signal dependent Actions to
 // be available for execution.
+//
+// The Events for resources (NewExistsEvent, NewNotExistsEvent and
+// NewDropRefEvent) are signalled and waited for by the built-in Actions.
+// Custom Actions should use the same constructors to interoperate with them:
+// two Events constructed with the same arguments are Equal, regardless of
+// where they were created.
 type Event interface {
 	// Equal returns true if this event is == to other.
 	Equal(other Event) bool
@@ -54,66 +61,90 @@ func (el EventList) Equal(other EventList) bool {
 }
 
 // NewExistsEvent returns and event that signals that the resource ID exists.
+// This is signalled by Actions that create or update the resource and waited
+// for by Actions for resources that refer to it.
 func NewExistsEvent(id *cloud.ResourceID) Event {
-	return &existsEvent{id: id}
+	return &ExistsEvent{id: id}
 }
 
-type existsEvent struct{ id *cloud.ResourceID }
+// ExistsEvent is the Event returned by NewExistsEvent. It is Equal to
+// another ExistsEvent with an Equal resource ID. String() is
+// "Exists(<id>)".
+type ExistsEvent struct{ id *cloud.ResourceID }
 
-func (e *existsEvent) Equal(other Event) bool {
+// ID of the resource that exists.
+func (e *ExistsEvent) ID() *cloud.ResourceID { return e.id }
+
+func (e *ExistsEvent) Equal(other Event) bool {
 	switch other := other.(type) {
-	case *existsEvent:
+	case *ExistsEvent:
 		return e.id.Equal(other.id)
 	}
 	return false
 }
 
-func (e *existsEvent) String() string {
+func (e *ExistsEvent) String() string {
 	return fmt.Sprintf("Exists(%v)", e.id)
 }
 
 // NewNotExistsEvent returns and event that signals that the resource ID no
-// longer exists.
+// longer exists. This is signalled by Actions that delete the resource.
 func NewNotExistsEvent(id *cloud.ResourceID) Event {
-	return &notExistsEvent{id: id}
+	return &NotExistsEvent{id: id}
 }
 
-type notExistsEvent struct{ id *cloud.ResourceID }
+// NotExistsEvent is the Event returned by NewNotExistsEvent. It is Equal to
+// another NotExistsEvent with an Equal resource ID. String() is
+// "NotExists(<id>)".
+type NotExistsEvent struct{ id *cloud.ResourceID }
+
+// ID of the resource that no longer exists.
+func (e *NotExistsEvent) ID() *cloud.ResourceID { return e.id }
 
-func (e *notExistsEvent) Equal(other Event) bool {
+func (e *NotExistsEvent) Equal(other Event) bool {
 	switch other := other.(type) {
-	case *notExistsEvent:
+	case *NotExistsEvent:
 		return e.id.Equal(other.id)
 	}
 	return false
 }
 
-func (e *notExistsEvent) String() string {
+func (e *NotExistsEvent) String() string {
 	return fmt.Sprintf("NotExists(%v)", e.id)
 }
 
 // NewDropRefEvent returns an event that signals that a resource reference has
-// changed (From no longer refers to To).
+// changed (From no longer refers to To). This is signalled by Actions that
+// update or delete From and waited for by the Action that deletes To.
 func NewDropRefEvent(from, to *cloud.ResourceID) Event {
-	return &dropRefEvent{
+	return &DropRefEvent{
 		from: from,
 		to:   to,
 	}
 }
 
-type dropRefEvent struct {
+// DropRefEvent is the Event returned by NewDropRefEvent. It is Equal to
+// another DropRefEvent with Equal From and To IDs. String() is
+// "DropRef(<from> => <to>)".
+type DropRefEvent struct {
 	from, to *cloud.ResourceID
 }
 
-func (e *dropRefEvent) Equal(other Event) bool {
+// From is the resource that no longer refers to To.
+func (e *DropRefEvent) From() *cloud.ResourceID { return e.from }
+
+// To is the resource that is no longer referred to by From.
+func (e *DropRefEvent) To() *cloud.ResourceID { return e.to }
+
+func (e *DropRefEvent) Equal(other Event) bool {
 	switch other := other.(type) {
-	case *dropRefEvent:
+	case *DropRefEvent:
 		return e.from.Equal(other.from) && e.to.Equal(other.to)
 	}
 	return false
 }
 
-func (e *dropRefEvent) String() string {
+func (e *DropRefEvent) String() string {
 	return fmt.Sprintf("DropRef(%v => %v)", e.from, e.to)
 }
 
@@ -130,3 +161,107 @@ func (e StringEvent) Equal(other Event) bool {
 }
 
 func (e StringEvent) String() string { return string(e) }
+
+// AnyOf returns an Event for use in an Action's Want list that is satisfied
+// when any one of events is signalled. For example, an Action can wait for
+// either of two resources to be deleted:
+//
+//	AnyOf(NewNotExistsEvent(a), NewNotExistsEvent(b))
+//
+// AnyOf is a condition rather than an Event that is signalled by an Action:
+// Equal(other) is true if other is equal to any of the events.
+func AnyOf(events ...Event) Event {
+	return &anyOfEvent{events: append(EventList(nil), events...)}
+}
+
+type anyOfEvent struct{ events EventList }
+
+func (e *anyOfEvent) Equal(other Event) bool {
+	if other, ok := other.(*anyOfEvent); ok {
+		return e.String() == other.String()
+	}
+	for _, ev := range e.events {
+		if ev.Equal(other) {
+			return true
+		}
+	}
+	return false
+}
+
+func (e *anyOfEvent) String() string {
+	var parts []string
+	for _, ev := range e.events {
+		parts = append(parts, ev.String())
+	}
+	return fmt.Sprintf("AnyOf(%s)", strings.Join(parts, ", "))
+}
+
+// Not returns an Event for use in an Action's Want list that is satisfied if
+// none of the Actions given to the Executor will signal ev (according to
+// their DryRun()). This expresses conditions that are known at the start of
+// execution, e.g. "resource X never existed":
+//
+//	AnyOf(NewNotExistsEvent(x), Not(NewExistsEvent(x)))
+//
+// will be satisfied if X is deleted by an Action or if there is no Action
+// that signals that X exists.
+//
+// Not is resolved by the serial and parallel Executors when they start. The
+// streaming Executor cannot know what Actions will be added and never
+// resolves Not.
+func Not(ev Event) Event { return &notEvent{ev: ev} }
+
+type notEvent struct{ ev Event }
+
+func (e *notEvent) Equal(other Event) bool {
+	switch other := other.(type) {
+	case *notEvent:
+		return e.ev.Equal(other.ev)
+	}
+	return false
+}
+
+func (e *notEvent) String() string { return fmt.Sprintf("Not(%v)", e.ev) }
+
+// resolveNotEvents signals the Not() events in the Want lists of the pending
+// Actions that are satisfied, i.e. the negated Event is not signalled by any
+// of the pending Actions.
+func resolveNotEvents(pending []Action) []TraceSignal {
+	var nots []*notEvent
+	var collect func(ev Event)
+	collect = func(ev Event) {
+		switch ev := ev.(type) {
+		case *notEvent:
+			nots = append(nots, ev)
+		case *anyOfEvent:
+			for _, x := range ev.events {
+				collect(x)
+			}
+		}
+	}
+	for _, a := range pending {
+		for _, ev := range a.PendingEvents() {
+			collect(ev)
+		}
+	}
+	if len(nots) == 0 {
+		return nil
+	}
+
+	var signalled EventList
+	for _, a := range pending {
+		signalled = append(signalled, a.DryRun()...)
+	}
+	var ret []TraceSignal
+	for _, n := range nots {
+		if containsEvent(signalled, n.ev) {
+			continue
+		}
+		for _, a := range pending {
+			if a.Signal(n) {
+				ret = append(ret, TraceSignal{Event: n, SignaledAction: a})
+			}
+		}
+	}
+	return ret
+}
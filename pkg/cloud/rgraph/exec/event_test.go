@@ -17,6 +17,7 @@ limitations under the License.
 package exec
 
 import (
+	"context"
 	"testing"
 
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
@@ -139,3 +140,137 @@ func TestEventEqual(t *testing.T) {
 		}
 	}
 }
+
+func TestAnyOfAndNotEvents(t *testing.T) {
+	a, b, c := StringEvent("a"), StringEvent("b"), StringEvent("c")
+	for _, tc := range []struct {
+		name string
+		want Event
+		ev   Event
+		eq   bool
+	}{
+		{name: "any of first", want: AnyOf(a, b), ev: a, eq: true},
+		{name: "any of second", want: AnyOf(a, b), ev: b, eq: true},
+		{name: "any of none", want: AnyOf(a, b), ev: c},
+		{name: "any of same", want: AnyOf(a, b), ev: AnyOf(a, b), eq: true},
+		{name: "any of different", want: AnyOf(a, b), ev: AnyOf(a, c)},
+		{name: "not", want: Not(a), ev: Not(a), eq: true},
+		{name: "not does not match event", want: Not(a), ev: a},
+		{name: "not different", want: Not(a), ev: Not(b)},
+		{name: "any of with not", want: AnyOf(a, Not(b)), ev: Not(b), eq: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.want.Equal(tc.ev); got != tc.eq {
+				t.Errorf("%v.Equal(%v) = %t, want %t", tc.want, tc.ev, got, tc.eq)
+			}
+		})
+	}
+}
+
+func TestExecutorConditionEvents(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		// want for action "T". The other actions are from graph.
+		want      Event
+		graph     string
+		completed []string
+	}{
+		{
+			name:      "any of, first signalled",
+			want:      AnyOf(StringEvent("A"), StringEvent("missing")),
+			graph:     "A",
+			completed: []string{"A", "T"},
+		},
+		{
+			name:      "any of, none signalled",
+			want:      AnyOf(StringEvent("missing1"), StringEvent("missing2")),
+			graph:     "A",
+			completed: []string{"A"},
+		},
+		{
+			name:      "not, no action signals the event",
+			want:      Not(StringEvent("missing")),
+			graph:     "A",
+			completed: []string{"A", "T"},
+		},
+		{
+			name:      "not, an action signals the event",
+			want:      Not(StringEvent("A")),
+			graph:     "A",
+			completed: []string{"A"},
+		},
+		{
+			name:      "deleted or never existed, never existed",
+			want:      AnyOf(StringEvent("deleted"), Not(StringEvent("exists"))),
+			graph:     "A",
+			completed: []string{"A", "T"},
+		},
+		{
+			name:      "deleted or never existed, deleted",
+			want:      AnyOf(StringEvent("deleted"), Not(StringEvent("exists"))),
+			graph:     "exists -> deleted",
+			completed: []string{"T", "deleted", "exists"},
+		},
+	} {
+		for _, executor := range []string{"serial", "parallel"} {
+			t.Run(tc.name+" "+executor, func(t *testing.T) {
+				acts := actionsFromGraphStr(tc.graph)
+				acts = append(acts, &testAction{name: "T", ActionBase: ActionBase{Want: EventList{tc.want}}})
+
+				var result *Result
+				switch executor {
+				case "serial":
+					ex, err := NewSerialExecutor(nil, acts)
+					if err != nil {
+						t.Fatalf("NewSerialExecutor() = %v", err)
+					}
+					result, _ = ex.Run(context.Background())
+				case "parallel":
+					ex, err := NewParallelExecutor(nil, acts)
+					if err != nil {
+						t.Fatalf("NewParallelExecutor() = %v", err)
+					}
+					result, _ = ex.Run(context.Background())
+				}
+				got := sortedStrings(result.Completed, func(a Action) string { return a.(*testAction).name })
+				if diff := cmp.Diff(got, tc.completed); diff != "" {
+					t.Errorf("Completed: diff -got,+want: %s", diff)
+				}
+			})
+		}
+	}
+}
+
+func TestResourceEvents(t *testing.T) {
+	newID := func(name string) *cloud.ResourceID {
+		return &cloud.ResourceID{ProjectID: "proj1", Resource: "res1", Key: meta.GlobalKey(name)}
+	}
+	// Events constructed separately from equal IDs must be Equal so that
+	// custom Actions interoperate with the built-in Actions.
+	for _, tc := range []struct {
+		a, b    Event
+		wantStr string
+	}{
+		{a: NewExistsEvent(newID("x")), b: NewExistsEvent(newID("x")), wantStr: "Exists(res1:proj1/x)"},
+		{a: NewNotExistsEvent(newID("x")), b: NewNotExistsEvent(newID("x")), wantStr: "NotExists(res1:proj1/x)"},
+		{a: NewDropRefEvent(newID("x"), newID("y")), b: NewDropRefEvent(newID("x"), newID("y")), wantStr: "DropRef(res1:proj1/x => res1:proj1/y)"},
+	} {
+		if !tc.a.Equal(tc.b) {
+			t.Errorf("%v.Equal(%v) = false, want true", tc.a, tc.b)
+		}
+		if got := tc.a.String(); got != tc.wantStr {
+			t.Errorf("String() = %q, want %q", got, tc.wantStr)
+		}
+	}
+
+	if ev, ok := NewExistsEvent(newID("x")).(*ExistsEvent); !ok || !ev.ID().Equal(newID("x")) {
+		t.Errorf("NewExistsEvent() = %v, want *ExistsEvent with ID x", ev)
+	}
+	if ev, ok := NewNotExistsEvent(newID("x")).(*NotExistsEvent); !ok || !ev.ID().Equal(newID("x")) {
+		t.Errorf("NewNotExistsEvent() = %v, want *NotExistsEvent with ID x", ev)
+	}
+	ev, ok := NewDropRefEvent(newID("x"), newID("y")).(*DropRefEvent)
+	if !ok || !ev.From().Equal(newID("x")) || !ev.To().Equal(newID("y")) {
+		t.Errorf("NewDropRefEvent() = %v, want *DropRefEvent from x to y", ev)
+	}
+}
@@ -19,7 +19,11 @@ package exec
 import (
 	"context"
 	"fmt"
+	"sync"
 	"time"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"k8s.io/klog/v2"
 )
 
 type Result struct {
@@ -30,17 +34,27 @@ type Result struct {
 	// Pending are Actions that could not be executed due to missing
 	// preconditions.
 	Pending []Action
+	// Warnings are the operation warnings reported by the Actions. Actions
+	// with warnings may have completed or failed.
+	Warnings []ActionWithWarnings
+	// VerificationErrors are Actions that failed with a VerificationError,
+	// i.e. the changes were applied but did not have the expected result.
+	VerificationErrors []ActionWithErr
 }
 
 func (r *Result) DeepCopy() *Result {
 	resultCopy := Result{
-		Completed: make([]Action, len(r.Completed)),
-		Pending:   make([]Action, len(r.Pending)),
-		Errors:    make([]ActionWithErr, len(r.Errors)),
+		Completed:          make([]Action, len(r.Completed)),
+		Pending:            make([]Action, len(r.Pending)),
+		Errors:             make([]ActionWithErr, len(r.Errors)),
+		Warnings:           make([]ActionWithWarnings, len(r.Warnings)),
+		VerificationErrors: make([]ActionWithErr, len(r.VerificationErrors)),
 	}
 	copy(resultCopy.Completed, r.Completed)
 	copy(resultCopy.Errors, r.Errors)
 	copy(resultCopy.Pending, r.Pending)
+	copy(resultCopy.Warnings, r.Warnings)
+	copy(resultCopy.VerificationErrors, r.VerificationErrors)
 	return &resultCopy
 }
 
@@ -49,6 +63,109 @@ type ActionWithErr struct {
 	Err    error
 }
 
+// ActionWithWarnings are the operation warnings reported while running the
+// Action.
+type ActionWithWarnings struct {
+	Action   Action
+	Warnings []cloud.OperationWarning
+}
+
+// warningCollector accumulates the operation warnings of a single Action.
+type warningCollector struct {
+	lock     sync.Mutex
+	warnings []cloud.OperationWarning
+}
+
+// withWarningCollector returns a context that records operation warnings
+// into a new warningCollector.
+func withWarningCollector(ctx context.Context) (context.Context, *warningCollector) {
+	wc := &warningCollector{}
+	ctx = cloud.WithOperationWarningHandler(ctx, func(_ context.Context, w []cloud.OperationWarning) {
+		wc.lock.Lock()
+		defer wc.lock.Unlock()
+		wc.warnings = append(wc.warnings, w...)
+	})
+	return ctx, wc
+}
+
+// runContext returns ctx with the settings of the config for the Actions,
+// e.g. WithStrictFingerprint() for StrictFingerprint.
+func (c *ExecutorConfig) runContext(ctx context.Context) context.Context {
+	if c.StrictFingerprint {
+		ctx = WithStrictFingerprint(ctx)
+	}
+	if c.SkipUnchanged {
+		ctx = WithSkipUnchanged(ctx)
+	}
+	if c.DeleteRefCheck != nil {
+		ctx = WithDeleteRefCheck(ctx, c.DeleteRefCheck)
+	}
+	if c.ReadYourWrites != nil {
+		ctx = WithReadYourWrites(ctx, *c.ReadYourWrites)
+	}
+	if c.ChangeLog != nil {
+		ctx = WithChangeLog(ctx, c.ChangeLog)
+	}
+	return ctx
+}
+
+// run the Action or dry run it with DryRun.
+func (c *ExecutorConfig) run(ctx context.Context, cl cloud.Cloud, a Action) (EventList, error) {
+	if c.DryRun {
+		return c.dryRun(ctx, cl, a)
+	}
+	return a.Run(ctx, cl)
+}
+
+// dryRun returns the result of a dry run of the Action. With ValidateOnly,
+// the Actions that support it are validated against the Cloud.
+func (c *ExecutorConfig) dryRun(ctx context.Context, cl cloud.Cloud, a Action) (EventList, error) {
+	if c.ValidateOnly && a.Metadata().ValidateOnly {
+		return a.Run(WithValidateOnly(ctx), cl)
+	}
+	return a.DryRun(), nil
+}
+
+var validateOnlyContextKey = contextKey("validate only")
+
+// WithValidateOnly returns a context for Run() that validates the change
+// without applying it. Only Actions with ActionMetadata.ValidateOnly may be
+// Run() with this context.
+func WithValidateOnly(ctx context.Context) context.Context {
+	return context.WithValue(ctx, validateOnlyContextKey, true)
+}
+
+// ValidateOnly returns true if the Action should only validate the change.
+func ValidateOnly(ctx context.Context) bool {
+	v, _ := ctx.Value(validateOnlyContextKey).(bool)
+	return v
+}
+
+// runLocked calls f while holding the KeyedLock (if any) for the resource of
+// the Action.
+func (c *ExecutorConfig) runLocked(ctx context.Context, a Action, f func() (EventList, error)) (EventList, error) {
+	if c.KeyedLock == nil || c.DryRun {
+		return f()
+	}
+	id := a.Metadata().ResourceID
+	if id == nil {
+		return f()
+	}
+	klog.FromContext(ctx).V(4).Info("Waiting for resource lock", "resourceID", id)
+	unlock, err := c.KeyedLock.Lock(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("waiting for lock on %v: %w", id, err)
+	}
+	defer unlock()
+	return f()
+}
+
+func (wc *warningCollector) get() []cloud.OperationWarning {
+	wc.lock.Lock()
+	defer wc.lock.Unlock()
+	return wc.warnings
+}
+
 // Executor performs the operations given by a list of Actions.
 type Executor interface {
 	// Run the actions. Returns non-nil if there was an error in execution of
@@ -100,6 +217,43 @@ func ErrorStrategyOption(s ErrorStrategy) Option {
 	return func(c *ExecutorConfig) { c.ErrorStrategy = s }
 }
 
+// StrictFingerprintOption enables optimistic concurrency for updates. Each
+// update Action checks that the live fingerprint of the resource matches the
+// fingerprint observed at plan time and fails with a ConflictError instead of
+// overwriting a concurrent change. Resources that do not have a fingerprint
+// are updated as usual.
+func StrictFingerprintOption(strict bool) Option {
+	return func(c *ExecutorConfig) { c.StrictFingerprint = strict }
+}
+
+// DeleteRefCheckOption enables a safety check before deletes. Each delete
+// Action calls f to find resources in the Cloud that still reference the
+// resource and fails with a ReferencedError instead of deleting it. See the
+// refcheck package for an implementation using live reads.
+func DeleteRefCheckOption(f ReferrersFunc) Option {
+	return func(c *ExecutorConfig) { c.DeleteRefCheck = f }
+}
+
+// KeyedLockOption holds the lock for the resource (ActionMetadata.ResourceID)
+// while each Action runs. Use the same KeyedLock for all Executors in the
+// process to guarantee that Actions on the same resource never run
+// concurrently, even across plans.
+func KeyedLockOption(l *cloud.KeyedLock) Option {
+	return func(c *ExecutorConfig) { c.KeyedLock = l }
+}
+
+// ValidateOnlyOption makes a DryRun validate the changes with the API
+// instead of skipping them. Actions that support it (see
+// ActionMetadata.ValidateOnly) are Run() with a context from
+// WithValidateOnly(). This catches field-level rejections before the real
+// apply. Only the create Actions of the resources with an Insert that
+// accepts validateOnly (see rnode.CreateFuncsValidateOnly) are validated.
+// Updates are not validated; the update APIs do not accept validateOnly.
+// Other Actions are dry run as usual. Requires DryRunOption.
+func ValidateOnlyOption(validate bool) Option {
+	return func(c *ExecutorConfig) { c.ValidateOnly = validate }
+}
+
 func defaultExecutorConfig() *ExecutorConfig {
 	return &ExecutorConfig{
 		DryRun:        false,
@@ -114,6 +268,13 @@ type ExecutorConfig struct {
 	ErrorStrategy         ErrorStrategy
 	Timeout               time.Duration
 	WaitForOrphansTimeout time.Duration
+	StrictFingerprint     bool
+	SkipUnchanged         bool
+	DeleteRefCheck        ReferrersFunc
+	KeyedLock             *cloud.KeyedLock
+	ReadYourWrites        *ReadYourWrites
+	ValidateOnly          bool
+	ChangeLog             ChangeLog
 }
 
 func (c *ExecutorConfig) validate() error {
@@ -122,5 +283,11 @@ func (c *ExecutorConfig) validate() error {
 	default:
 		return fmt.Errorf("invalid ErrorStrategy: %q", c.ErrorStrategy)
 	}
+	if c.ValidateOnly && !c.DryRun {
+		return fmt.Errorf("ValidateOnly requires DryRun")
+	}
+	if r := c.ReadYourWrites; r != nil && (r.Attempts <= 0 || r.Interval < 0) {
+		return fmt.Errorf("invalid ReadYourWrites: %+v", *r)
+	}
 	return nil
 }
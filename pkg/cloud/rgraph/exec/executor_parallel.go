@@ -44,7 +44,7 @@ func NewParallelExecutor(c cloud.Cloud, pending []Action, opts ...Option) (*para
 	ret := &parallelExecutor{
 		config: defaultParallelExecutorConfig(),
 		cloud:  c,
-		result: &Result{Pending: pending},
+		result: &Result{Pending: addPhaseBarriers(pending)},
 		pq:     algo.NewParallelQueue[Action](),
 	}
 	for _, opt := range opts {
@@ -61,12 +61,18 @@ type parallelExecutor struct {
 	config *ExecutorConfig
 	cloud  cloud.Cloud
 
-	// lock guards results
+	// lock guards results, queued and running
 	lock   sync.Mutex
 	result *Result
+	// queued are the Actions that have been added to pq but have not
+	// started running.
+	queued []Action
+	// running are the Actions that have started and have not finished.
+	running []Action
 
-	pq   *algo.ParallelQueue[Action]
-	done chan *TraceEntry
+	pq     *algo.ParallelQueue[Action]
+	done   chan *TraceEntry
+	logger klog.Logger
 }
 
 // parallelExecutor implements Executor.
@@ -79,75 +85,113 @@ var _ Executor = (*parallelExecutor)(nil)
 // routines that are currently executing.
 //
 // To handle timeout properly use TimeoutOption for canceling running actions
-// and WaitForOrphansTimeoutOption for canceling post error cleanup.
+// and WaitForOrphansTimeoutOption for canceling post error cleanup. Actions
+// that are still running when the wait for orphans is cancelled are reported
+// in Result.Errors.
+//
+// If Actions remain pending when none are running or runnable and no Action
+// failed, Run returns a *DeadlockError describing the Events the pending
+// Actions are waiting for.
 func (ex *parallelExecutor) Run(ctx context.Context) (*Result, error) {
+	ex.logger = klog.FromContext(ctx).WithName("ParallelExecutor")
+	ctx = klog.NewContext(ctx, ex.logger)
+	ctx = ex.config.runContext(ctx)
+	if signals := resolveNotEvents(ex.result.Pending); len(signals) > 0 {
+		ex.logger.V(4).Info("Resolved Not events", "signals", signals)
+	}
 	ex.queueRunnableActions()
 
 	queueErr := ex.runActionQueue(ctx)
 	if queueErr != nil {
 		waitErr := ex.waitForQueueOrphans(ctx)
+		ex.requeueNotStarted()
 		if waitErr != nil {
 			// Actions might still run and modify the results. Because result is
 			// returned as a pointer we need to deep copy it.
 			ex.lock.Lock()
 			defer ex.lock.Unlock()
+			// The Actions that are still running are neither Completed nor
+			// Pending; report them as Errors.
+			for _, a := range ex.running {
+				ex.result.addError(a, fmt.Errorf("ParallelExecutor: Action %s still running: %w", a, waitErr))
+			}
 			result := ex.result.DeepCopy()
 			return result, fmt.Errorf("ParallelExecutor: WaitForOrphans: %w", waitErr)
 		}
 	}
+	if queueErr == nil && len(ex.result.Errors) == 0 && len(ex.result.Pending) != 0 {
+		// Nothing is running or can run and no Action failed, so the
+		// remaining Actions are deadlocked.
+		return ex.result, newDeadlockError(ex.result.Pending)
+	}
 	if len(ex.result.Errors) > 0 || len(ex.result.Pending) != 0 {
 		return ex.result, ErrPendingActions
 	}
+	if len(ex.result.VerificationErrors) > 0 {
+		return ex.result, ErrVerificationFailed
+	}
 	return ex.result, nil
 
 }
 
 func (ex *parallelExecutor) runActionQueue(ctx context.Context) error {
-	msg := "Run runAction"
 	if ex.config.Timeout > 0 {
 		var cancel context.CancelFunc
 		ctx, cancel = context.WithTimeout(ctx, ex.config.Timeout)
 		defer cancel()
-		msg = fmt.Sprintf("%s with timeout %v.", msg, ex.config.Timeout)
 	}
-	klog.Infof(msg)
+	ex.logger.V(4).Info("Run action queue", "timeout", ex.config.Timeout)
 	return ex.pq.Run(ctx, ex.runAction)
 }
 
 func (ex *parallelExecutor) waitForQueueOrphans(ctx context.Context) error {
-	msg := "Run WaitForOrphans"
 	if ex.config.WaitForOrphansTimeout > 0 {
 		var cancel context.CancelFunc
 		ctx, cancel = context.WithTimeout(ctx, ex.config.WaitForOrphansTimeout)
 		defer cancel()
-		msg = fmt.Sprintf("%s with timeout %v.", msg, ex.config.WaitForOrphansTimeout)
 	}
-	klog.V(4).Infof(msg)
+	ex.logger.V(4).Info("Run WaitForOrphans", "timeout", ex.config.WaitForOrphansTimeout)
 	return ex.pq.WaitForOrphans(ctx)
 }
 
 func (ex *parallelExecutor) runAction(ctx context.Context, a Action) error {
+	ex.dequeue(a)
 	te := &TraceEntry{
 		Action: a,
 		Start:  time.Now(),
 	}
-	klog.V(4).Infof("Run action %s", a)
-	events, runErr := a.Run(ctx, ex.cloud)
+	logger := loggerWithAction(ex.logger, a)
+	logger.V(4).Info("Run action")
+	actCtx, wc := withWarningCollector(klog.NewContext(ctx, logger))
+	actCtx, sc := withSpawnCollector(actCtx)
+	events, runErr := ex.config.runLocked(actCtx, a, func() (EventList, error) {
+		return ex.config.run(actCtx, ex.cloud, a)
+	})
 	te.End = time.Now()
-	klog.V(4).Infof("Finish action %s, err: %v", a, runErr)
+	te.Warnings = wc.get()
+	logger.V(4).Info("Finish action", "err", runErr)
+	if len(te.Warnings) > 0 {
+		logger.V(2).Info("Action warnings", "warnings", te.Warnings)
+	}
 
-	ex.addActionResult(a, runErr)
+	ex.addActionResult(a, runErr, te.Warnings)
 
 	if runErr != nil {
-		klog.V(2).Infof("Got error  %v, from action %s error_strategy: %s", runErr, a, ex.config.ErrorStrategy)
+		logger.V(2).Info("Action error", "err", runErr, "errorStrategy", ex.config.ErrorStrategy)
 		// check error strategy and decide if new actions should be executed.
-		if ex.config.ErrorStrategy == StopOnError {
+		if ex.config.ErrorStrategy == StopOnError && !isVerificationError(runErr) {
 			if ex.config.Tracer != nil {
 				ex.config.Tracer.Record(te, runErr)
 			}
 			return fmt.Errorf("parallelExecutor: StopOnError due to Action %s: %w", a, runErr)
 		}
 	} else {
+		var spawned []Action
+		events, spawned = joinSpawned(a, events, sc.get())
+		if len(spawned) > 0 {
+			logger.V(4).Info("Spawned actions", "count", len(spawned)-1)
+			ex.addPending(spawned)
+		}
 		// notify parents only when action finished with success
 		te.Signaled = ex.signal(events)
 	}
@@ -165,29 +209,64 @@ func (ex *parallelExecutor) queueRunnableActions() {
 	ex.lock.Lock()
 	defer ex.lock.Unlock()
 
-	klog.V(4).Infof("queueRunnableActions: %d actions pending", len(ex.result.Pending))
+	ex.logger.V(4).Info("queueRunnableActions", "pending", len(ex.result.Pending))
 
 	taskWasRun := false
 	var notRunnable []Action
-	for _, a := range ex.result.Pending {
+	for i, a := range ex.result.Pending {
 		if a.CanRun() {
-			klog.V(4).Infof("Run task: %s", a)
+			ex.logger.V(4).Info("Queue action", "action", a.Metadata().Name)
 			if ok := ex.pq.Add(a); !ok {
-				klog.Errorf("error scheduling task %s: parallel queue is done", a)
+				ex.logger.Error(nil, "Error scheduling action: parallel queue is done", "action", a.Metadata().Name)
+				// The remaining Actions will not be run and stay
+				// pending.
+				notRunnable = append(notRunnable, ex.result.Pending[i:]...)
 				break
 			}
+			ex.queued = append(ex.queued, a)
 			taskWasRun = true
 		} else {
 			notRunnable = append(notRunnable, a)
 		}
 	}
-	klog.V(4).Infof("queueRunnableActions: remaining %d pending actions", len(notRunnable))
+	ex.logger.V(4).Info("queueRunnableActions done", "remaining", len(notRunnable))
 	// update Pending array only if actions were run
 	if taskWasRun {
 		ex.result.Pending = notRunnable
 	}
 }
 
+// dequeue moves a from the queued to the running Actions as it has started
+// running.
+func (ex *parallelExecutor) dequeue(a Action) {
+	ex.lock.Lock()
+	defer ex.lock.Unlock()
+	ex.running = append(ex.running, a)
+	for i, q := range ex.queued {
+		if q == a {
+			ex.queued = append(ex.queued[:i], ex.queued[i+1:]...)
+			return
+		}
+	}
+}
+
+// requeueNotStarted returns the Actions that were queued but were never
+// started to Pending. This happens when the queue is stopped early (e.g.
+// StopOnError).
+func (ex *parallelExecutor) requeueNotStarted() {
+	ex.lock.Lock()
+	defer ex.lock.Unlock()
+	ex.result.Pending = append(ex.result.Pending, ex.queued...)
+	ex.queued = nil
+}
+
+// addPending adds the Actions to Pending.
+func (ex *parallelExecutor) addPending(acts []Action) {
+	ex.lock.Lock()
+	defer ex.lock.Unlock()
+	ex.result.Pending = append(ex.result.Pending, acts...)
+}
+
 // signal notifies parents that action finished
 func (ex *parallelExecutor) signal(evs []Event) []TraceSignal {
 	ex.lock.Lock()
@@ -203,12 +282,21 @@ func (ex *parallelExecutor) signal(evs []Event) []TraceSignal {
 	return ret
 }
 
-func (ex *parallelExecutor) addActionResult(a Action, runErr error) {
+func (ex *parallelExecutor) addActionResult(a Action, runErr error, warnings []cloud.OperationWarning) {
 	ex.lock.Lock()
 	defer ex.lock.Unlock()
+	for i, r := range ex.running {
+		if r == a {
+			ex.running = append(ex.running[:i], ex.running[i+1:]...)
+			break
+		}
+	}
+	if len(warnings) > 0 {
+		ex.result.Warnings = append(ex.result.Warnings, ActionWithWarnings{Action: a, Warnings: warnings})
+	}
 	if runErr == nil {
 		ex.result.Completed = append(ex.result.Completed, a)
 	} else {
-		ex.result.Errors = append(ex.result.Errors, ActionWithErr{Action: a, Err: runErr})
+		ex.result.addError(a, runErr)
 	}
 }
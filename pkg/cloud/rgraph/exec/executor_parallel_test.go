@@ -18,7 +18,10 @@ package exec
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"slices"
+	"sync"
 	"testing"
 	"time"
 
@@ -111,6 +114,10 @@ func TestParallelExecutorErrorStrategy(t *testing.T) {
 		// pending should be sorted alphabetically for comparison.
 		pending []string
 		errs    []string
+		// mayPend are Actions that do not depend on the error. With
+		// StopOnError, they are pending if they were not started before
+		// the error.
+		mayPend []string
 	}{
 		{
 			name:    "linear graph",
@@ -123,6 +130,7 @@ func TestParallelExecutorErrorStrategy(t *testing.T) {
 			graph:   "A -> !B -> C; A -> D; A -> E; A -> F",
 			pending: []string{"C"},
 			errs:    []string{"B"},
+			mayPend: []string{"D", "E", "F"},
 		},
 	} {
 		mockCloud := cloud.NewMockGCE(&cloud.SingleProjectRouter{ID: "proj1"})
@@ -147,10 +155,19 @@ func TestParallelExecutorErrorStrategy(t *testing.T) {
 				if diff := cmp.Diff(gotErrs, tc.errs); diff != "" {
 					t.Errorf("errors: diff -got,+want: %s", diff)
 				}
-				got := sortedStrings(result.Pending, func(a Action) string { return a.(*testAction).name })
+				var got []string
+				for _, name := range sortedStrings(result.Pending, func(a Action) string { return a.(*testAction).name }) {
+					if strategy == StopOnError && slices.Contains(tc.mayPend, name) {
+						continue
+					}
+					got = append(got, name)
+				}
 				if diff := cmp.Diff(got, tc.pending); diff != "" {
 					t.Errorf("pending: diff -got,+want: %s", diff)
 				}
+				if n := len(result.Completed) + len(result.Errors) + len(result.Pending); n != len(actions) {
+					t.Errorf("completed + errors + pending = %d, want %d", n, len(actions))
+				}
 			})
 		}
 	}
@@ -188,6 +205,7 @@ func TestParallelExecutorTimeoutOptions(t *testing.T) {
 			timeout:               1 * time.Second,
 			waitForOrphansTimeout: 2 * time.Second,
 			completed:             []string{"A", "B"},
+			errors:                []string{"C"},
 			pending:               []string{"D"},
 			wantErr:               true,
 		},
@@ -224,7 +242,7 @@ func TestParallelExecutorTimeoutOptions(t *testing.T) {
 			waitForOrphansTimeout: 1 * time.Second,
 			injectError:           true,
 			completed:             []string{"A"},
-			errors:                []string{"B"},
+			errors:                []string{"B", "C"},
 			pending:               []string{"D"},
 			wantErr:               true,
 		},
@@ -242,9 +260,9 @@ func TestParallelExecutorTimeoutOptions(t *testing.T) {
 				name:   "C",
 				events: EventList{StringEvent("C")},
 				runHook: func(ctx context.Context) error {
-					t.Log("Action c run hook, wait 5sec")
+					// C may still be running after the test has
+					// completed, so it must not use t.
 					time.Sleep(5 * time.Second)
-					t.Log("Action c run hook, finish wait")
 					return nil
 				},
 			}
@@ -290,3 +308,131 @@ func TestParallelExecutorTimeoutOptions(t *testing.T) {
 		})
 	}
 }
+
+func TestParallelExecutorDeadlock(t *testing.T) {
+	for _, tc := range []struct {
+		name  string
+		graph string
+		// extraWant is an Event that no Action signals, added to the
+		// Want of Action "A".
+		extraWant   Event
+		wantBlocked []string
+		wantMissing []string
+		wantErr     bool
+	}{
+		{
+			name:  "no deadlock",
+			graph: "A -> B -> C",
+		},
+		{
+			name:        "cycle",
+			graph:       "A -> B -> C -> B; X",
+			wantBlocked: []string{"B", "C"},
+		},
+		{
+			name:        "missing event",
+			graph:       "A -> B; X",
+			extraWant:   StringEvent("nobody"),
+			wantBlocked: []string{"A", "B"},
+			wantMissing: []string{"A"},
+		},
+		{
+			name:    "blocked by error is not a deadlock",
+			graph:   "A -> !B -> C",
+			wantErr: true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			actions := actionsFromGraphStr(tc.graph)
+			if tc.extraWant != nil {
+				for _, a := range actions {
+					if ta := a.(*testAction); ta.name == "A" {
+						ta.Want = append(ta.Want, tc.extraWant)
+					}
+				}
+			}
+			ex, err := NewParallelExecutor(nil, actions)
+			if err != nil {
+				t.Fatalf("NewParallelExecutor() = %v, want nil", err)
+			}
+			_, err = ex.Run(context.Background())
+
+			var dErr *DeadlockError
+			if !errors.As(err, &dErr) {
+				if len(tc.wantBlocked) > 0 {
+					t.Fatalf("Run() = %v, want DeadlockError", err)
+				}
+				if gotErr := err != nil; gotErr != tc.wantErr {
+					t.Fatalf("Run() = %v; gotErr = %t, want %t", err, gotErr, tc.wantErr)
+				}
+				return
+			}
+			if !errors.Is(err, ErrPendingActions) {
+				t.Errorf("errors.Is(%v, ErrPendingActions) = false, want true", err)
+			}
+			blocked := sortedStrings(dErr.Blocked, func(b BlockedAction) string { return b.Action.(*testAction).name })
+			if diff := cmp.Diff(blocked, tc.wantBlocked); diff != "" {
+				t.Errorf("Blocked: diff -got,+want: %s", diff)
+			}
+			var missing []string
+			for _, b := range dErr.Blocked {
+				if len(b.Missing) > 0 {
+					missing = append(missing, b.Action.(*testAction).name)
+				}
+			}
+			if diff := cmp.Diff(missing, tc.wantMissing); diff != "" {
+				t.Errorf("Missing: diff -got,+want: %s", diff)
+			}
+		})
+	}
+}
+
+func TestParallelExecutorDryRun(t *testing.T) {
+	for _, tc := range []struct {
+		name          string
+		opts          []Option
+		wantValidated []string
+	}{
+		{name: "dry run", opts: []Option{DryRunOption(true)}},
+		{name: "validate only", opts: []Option{DryRunOption(true), ValidateOnlyOption(true)}, wantValidated: []string{"A"}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var (
+				lock           sync.Mutex
+				validated, ran []string
+			)
+			hook := func(name string) func(context.Context) error {
+				return func(ctx context.Context) error {
+					lock.Lock()
+					defer lock.Unlock()
+					if ValidateOnly(ctx) {
+						validated = append(validated, name)
+					} else {
+						ran = append(ran, name)
+					}
+					return nil
+				}
+			}
+			a := &validatingAction{testAction{name: "A", events: EventList{StringEvent("A")}, runHook: hook("A")}}
+			b := &testAction{name: "B", ActionBase: ActionBase{Want: EventList{StringEvent("A")}}, runHook: hook("B")}
+
+			ex, err := NewParallelExecutor(nil, []Action{a, b}, tc.opts...)
+			if err != nil {
+				t.Fatalf("NewParallelExecutor() = %v", err)
+			}
+			result, err := ex.Run(context.Background())
+			if err != nil {
+				t.Fatalf("Run() = %v", err)
+			}
+			if len(result.Completed) != 2 {
+				t.Errorf("len(result.Completed) = %d, want 2", len(result.Completed))
+			}
+			if diff := cmp.Diff(validated, tc.wantValidated); diff != "" {
+				t.Errorf("validated: diff -got,+want: %s", diff)
+			}
+			if len(ran) != 0 {
+				t.Errorf("ran = %v, want none", ran)
+			}
+		})
+	}
+}
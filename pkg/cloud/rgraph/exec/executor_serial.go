@@ -30,7 +30,7 @@ func NewSerialExecutor(c cloud.Cloud, pending []Action, opts ...Option) (*serial
 	ret := &serialExecutor{
 		cloud:  c,
 		config: defaultExecutorConfig(),
-		result: &Result{Pending: pending},
+		result: &Result{Pending: addPhaseBarriers(pending)},
 	}
 	for _, opt := range opts {
 		opt(ret.config)
@@ -40,25 +40,14 @@ func NewSerialExecutor(c cloud.Cloud, pending []Action, opts ...Option) (*serial
 		return nil, err
 	}
 
-	if ret.config.DryRun {
-		ret.runFunc = func(ctx context.Context, c cloud.Cloud, a Action) (EventList, error) {
-			return a.DryRun(), nil
-		}
-	} else {
-		ret.runFunc = func(ctx context.Context, c cloud.Cloud, a Action) (EventList, error) {
-			return a.Run(ctx, c)
-		}
-	}
-
 	return ret, nil
 }
 
 type serialExecutor struct {
 	config *ExecutorConfig
 
-	cloud   cloud.Cloud
-	runFunc func(context.Context, cloud.Cloud, Action) (EventList, error)
-	result  *Result
+	cloud  cloud.Cloud
+	result *Result
 }
 
 var _ Executor = (*serialExecutor)(nil)
@@ -69,9 +58,12 @@ var _ Executor = (*serialExecutor)(nil)
 // Note that when timeout occurs the executor will block until active action
 // has returned.
 func (ex *serialExecutor) Run(ctx context.Context) (*Result, error) {
+	logger := klog.FromContext(ctx).WithName("SerialExecutor")
+	ctx = klog.NewContext(ctx, logger)
+	ctx = ex.config.runContext(ctx)
 	if ex.config.Timeout != 0 {
 		var cancel context.CancelFunc
-		klog.V(4).Infof("Run serialExecutor with timeout %v", ex.config.Timeout)
+		logger.V(4).Info("Run with timeout", "timeout", ex.config.Timeout)
 		ctx, cancel = context.WithTimeout(ctx, ex.config.Timeout)
 		defer cancel()
 	}
@@ -79,6 +71,9 @@ func (ex *serialExecutor) Run(ctx context.Context) (*Result, error) {
 }
 
 func (ex *serialExecutor) runInternal(ctx context.Context) (*Result, error) {
+	if signals := resolveNotEvents(ex.result.Pending); len(signals) > 0 {
+		klog.FromContext(ctx).V(4).Info("Resolved Not events", "signals", signals)
+	}
 	for a := ex.next(); a != nil; a = ex.next() {
 		err := ex.runAction(ctx, a)
 		if err != nil {
@@ -91,25 +86,50 @@ func (ex *serialExecutor) runInternal(ctx context.Context) (*Result, error) {
 	if len(ex.result.Errors) > 0 {
 		return ex.result, fmt.Errorf("serialExecutor: errors in execution %v", ex.result.Errors)
 	}
+	if len(ex.result.VerificationErrors) > 0 {
+		return ex.result, fmt.Errorf("serialExecutor: %w: %v", ErrVerificationFailed, ex.result.VerificationErrors)
+	}
 
 	return ex.result, nil
 }
 
 func (ex *serialExecutor) runAction(ctx context.Context, a Action) error {
-	klog.V(4).Infof("runAction %s", a)
+	logger := loggerWithAction(klog.FromContext(ctx), a)
+	logger.V(4).Info("Run action")
 
 	te := &TraceEntry{
 		Action: a,
 		Start:  time.Now(),
 	}
-	events, runErr := ex.runFunc(ctx, ex.cloud, a)
+	actCtx, wc := withWarningCollector(klog.NewContext(ctx, logger))
+	actCtx, sc := withSpawnCollector(actCtx)
+	events, runErr := ex.config.runLocked(actCtx, a, func() (EventList, error) {
+		return ex.config.run(actCtx, ex.cloud, a)
+	})
 	te.End = time.Now()
+	te.Warnings = wc.get()
+	logger.V(4).Info("Finish action", "err", runErr)
+
+	if len(te.Warnings) > 0 {
+		logger.V(2).Info("Action warnings", "warnings", te.Warnings)
+		ex.result.Warnings = append(ex.result.Warnings, ActionWithWarnings{Action: a, Warnings: te.Warnings})
+	}
 
 	if runErr == nil {
 		ex.result.Completed = append(ex.result.Completed, a)
+		var spawned []Action
+		events, spawned = joinSpawned(a, events, sc.get())
+		if len(spawned) > 0 {
+			logger.V(4).Info("Spawned actions", "count", len(spawned)-1)
+			ex.result.Pending = append(ex.result.Pending, spawned...)
+		}
 	} else {
-		ex.result.Errors = append(ex.result.Errors, ActionWithErr{Action: a, Err: runErr})
-		switch ex.config.ErrorStrategy {
+		ex.result.addError(a, runErr)
+		strategy := ex.config.ErrorStrategy
+		if isVerificationError(runErr) {
+			strategy = ContinueOnError
+		}
+		switch strategy {
 		case ContinueOnError:
 		case StopOnError:
 			return fmt.Errorf("serialExecutor: stopping execution for Action %s (got %v)", a, runErr)
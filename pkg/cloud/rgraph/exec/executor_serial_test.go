@@ -274,3 +274,52 @@ func TestSerialExecutorTimeoutOptions(t *testing.T) {
 		})
 	}
 }
+
+// validatingAction is a testAction that supports ValidateOnly.
+type validatingAction struct {
+	testAction
+}
+
+func (a *validatingAction) Metadata() *ActionMetadata {
+	m := a.testAction.Metadata()
+	m.ValidateOnly = true
+	return m
+}
+
+func TestSerialExecutorValidateOnly(t *testing.T) {
+	var validated, ran []string
+	hook := func(name string) func(context.Context) error {
+		return func(ctx context.Context) error {
+			if ValidateOnly(ctx) {
+				validated = append(validated, name)
+			} else {
+				ran = append(ran, name)
+			}
+			return nil
+		}
+	}
+	a := &validatingAction{testAction{name: "A", events: EventList{StringEvent("A")}, runHook: hook("A")}}
+	b := &testAction{name: "B", ActionBase: ActionBase{Want: EventList{StringEvent("A")}}, runHook: hook("B")}
+
+	ex, err := NewSerialExecutor(nil, []Action{a, b}, DryRunOption(true), ValidateOnlyOption(true))
+	if err != nil {
+		t.Fatalf("NewSerialExecutor() = %v", err)
+	}
+	result, err := ex.Run(context.Background())
+	if err != nil {
+		t.Fatalf("Run() = %v", err)
+	}
+	if len(result.Completed) != 2 {
+		t.Errorf("len(result.Completed) = %d, want 2", len(result.Completed))
+	}
+	if diff := cmp.Diff(validated, []string{"A"}); diff != "" {
+		t.Errorf("validated: diff -got,+want: %s", diff)
+	}
+	if len(ran) != 0 {
+		t.Errorf("ran = %v, want none", ran)
+	}
+
+	if _, err := NewSerialExecutor(nil, nil, ValidateOnlyOption(true)); err == nil {
+		t.Error("NewSerialExecutor(ValidateOnly without DryRun) = nil, want error")
+	}
+}
@@ -0,0 +1,267 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package exec
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"math/rand"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+)
+
+var (
+	soakIterations = flag.Int("soak-iterations", 20, "number of random graphs to run in TestExecutorSoak")
+	soakSeed       = flag.Int64("soak-seed", 0, "seed for TestExecutorSoak; 0 uses the current time")
+)
+
+// soakConfig configures the random graphs generated by randomSoakGraph.
+type soakConfig struct {
+	// actions is the number of Actions in the graph.
+	actions int
+	// maxWants is the maximum number of dependencies of an Action.
+	maxWants int
+	// errorRate is the probability that an Action returns an error.
+	errorRate float64
+	// maxLatency is the maximum time an Action takes to Run.
+	maxLatency time.Duration
+}
+
+// soakRecorder tracks the Actions run by an Executor and records violations
+// of the ordering invariants.
+type soakRecorder struct {
+	lock       sync.Mutex
+	done       map[string]bool
+	runs       map[string]int
+	violations []string
+}
+
+func newSoakRecorder() *soakRecorder {
+	return &soakRecorder{done: map[string]bool{}, runs: map[string]int{}}
+}
+
+func (r *soakRecorder) start(a *soakAction) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.runs[a.name]++
+	if r.runs[a.name] > 1 {
+		r.violations = append(r.violations, fmt.Sprintf("%s ran %d times", a.name, r.runs[a.name]))
+	}
+	for _, dep := range a.deps {
+		if !r.done[dep] {
+			r.violations = append(r.violations, fmt.Sprintf("%s ran before its dependency %s completed", a.name, dep))
+		}
+	}
+}
+
+func (r *soakRecorder) finish(a *soakAction) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.done[a.name] = true
+}
+
+// soakAction is a testAction with injected latency that reports to a
+// soakRecorder.
+type soakAction struct {
+	ActionBase
+	name    string
+	deps    []string
+	err     error
+	latency time.Duration
+	rec     *soakRecorder
+}
+
+func (a *soakAction) Run(ctx context.Context, _ cloud.Cloud) (EventList, error) {
+	a.rec.start(a)
+	select {
+	case <-time.After(a.latency):
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	if a.err != nil {
+		return nil, a.err
+	}
+	a.rec.finish(a)
+	return EventList{StringEvent(a.name)}, nil
+}
+
+func (a *soakAction) DryRun() EventList { return EventList{StringEvent(a.name)} }
+
+func (a *soakAction) String() string { return a.name }
+
+func (a *soakAction) Metadata() *ActionMetadata {
+	return &ActionMetadata{
+		Name:    a.name,
+		Type:    ActionTypeCustom,
+		Summary: "Action used for soak testing",
+	}
+}
+
+// randomSoakGraph returns a random DAG of Actions. Actions only depend on
+// Actions generated before them so the graph has no cycles. The graph is a
+// deterministic function of seed.
+func randomSoakGraph(seed int64, cfg soakConfig, rec *soakRecorder) []*soakAction {
+	rng := rand.New(rand.NewSource(seed))
+
+	var ret []*soakAction
+	for i := 0; i < cfg.actions; i++ {
+		a := &soakAction{name: fmt.Sprintf("A%03d", i), rec: rec}
+		if i > 0 {
+			for _, j := range rng.Perm(i)[:rng.Intn(min(i, cfg.maxWants)+1)] {
+				a.deps = append(a.deps, ret[j].name)
+				a.Want = append(a.Want, StringEvent(ret[j].name))
+			}
+		}
+		if cfg.maxLatency > 0 {
+			a.latency = time.Duration(rng.Int63n(int64(cfg.maxLatency)))
+		}
+		if rng.Float64() < cfg.errorRate {
+			a.err = errors.New("injected")
+		}
+		ret = append(ret, a)
+	}
+	rng.Shuffle(len(ret), func(i, j int) { ret[i], ret[j] = ret[j], ret[i] })
+
+	return ret
+}
+
+// checkSoakInvariants returns the invariants of the Result that are violated.
+func checkSoakInvariants(acts []*soakAction, rec *soakRecorder, result *Result, strategy ErrorStrategy) []string {
+	violations := append([]string(nil), rec.violations...)
+
+	if n := len(result.Completed) + len(result.Errors) + len(result.Pending); n != len(acts) {
+		violations = append(violations, fmt.Sprintf("completed(%d) + errors(%d) + pending(%d) = %d, want %d",
+			len(result.Completed), len(result.Errors), len(result.Pending), n, len(acts)))
+	}
+
+	seen := map[string]string{}
+	add := func(a Action, where string) {
+		name := a.(*soakAction).name
+		if prev, ok := seen[name]; ok {
+			violations = append(violations, fmt.Sprintf("%s is in both %s and %s", name, prev, where))
+		}
+		seen[name] = where
+	}
+	for _, a := range result.Completed {
+		add(a, "Completed")
+		if a.(*soakAction).err != nil {
+			violations = append(violations, fmt.Sprintf("%s is Completed but returned an error", a))
+		}
+	}
+	for _, a := range result.Errors {
+		add(a.Action, "Errors")
+		if a.Action.(*soakAction).err == nil {
+			violations = append(violations, fmt.Sprintf("%s is in Errors but did not return an error", a.Action))
+		}
+	}
+	for _, a := range result.Pending {
+		add(a, "Pending")
+		if rec.runs[a.(*soakAction).name] > 0 {
+			violations = append(violations, fmt.Sprintf("%s is Pending but was run", a))
+		}
+	}
+
+	// With ContinueOnError, an Action can only be left pending if one of its
+	// dependencies did not complete.
+	if strategy == ContinueOnError {
+		for _, a := range result.Pending {
+			sa := a.(*soakAction)
+			blocked := false
+			for _, dep := range sa.deps {
+				if !rec.done[dep] {
+					blocked = true
+				}
+			}
+			if !blocked {
+				violations = append(violations, fmt.Sprintf("%s is Pending but all of its dependencies completed", sa.name))
+			}
+		}
+	}
+
+	return violations
+}
+
+func TestExecutorSoak(t *testing.T) {
+	seed := *soakSeed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	iterations := *soakIterations
+	if testing.Short() {
+		iterations = 2
+	}
+	t.Logf("seed = %d (use -soak-seed to reproduce)", seed)
+
+	cfg := soakConfig{
+		actions:    40,
+		maxWants:   3,
+		errorRate:  0.05,
+		maxLatency: 2 * time.Millisecond,
+	}
+
+	for _, tc := range []struct {
+		name        string
+		newExecutor func(cloud.Cloud, []Action, ...Option) (Executor, error)
+	}{
+		{
+			name: "serial",
+			newExecutor: func(c cloud.Cloud, acts []Action, opts ...Option) (Executor, error) {
+				return NewSerialExecutor(c, acts, opts...)
+			},
+		},
+		{
+			name: "parallel",
+			newExecutor: func(c cloud.Cloud, acts []Action, opts ...Option) (Executor, error) {
+				return NewParallelExecutor(c, acts, opts...)
+			},
+		},
+	} {
+		for _, strategy := range []ErrorStrategy{ContinueOnError, StopOnError} {
+			t.Run(tc.name+" "+string(strategy), func(t *testing.T) {
+				for i := 0; i < iterations; i++ {
+					graphSeed := seed + int64(i)
+					rec := newSoakRecorder()
+					acts := randomSoakGraph(graphSeed, cfg, rec)
+					var pending []Action
+					for _, a := range acts {
+						pending = append(pending, a)
+					}
+					ex, err := tc.newExecutor(nil, pending, ErrorStrategyOption(strategy))
+					if err != nil {
+						t.Fatalf("newExecutor() = %v, want nil", err)
+					}
+					result, err := ex.Run(context.Background())
+					if result == nil {
+						t.Fatalf("graph seed %d: Run() = nil, %v; want non-nil Result", graphSeed, err)
+					}
+					if err == nil && (len(result.Errors) > 0 || len(result.Pending) > 0) {
+						t.Errorf("graph seed %d: Run() = nil, want error (Errors = %v, Pending = %v)", graphSeed, result.Errors, result.Pending)
+					}
+					for _, v := range checkSoakInvariants(acts, rec, result, strategy) {
+						t.Errorf("graph seed %d: %s", graphSeed, v)
+					}
+				}
+			})
+		}
+	}
+}
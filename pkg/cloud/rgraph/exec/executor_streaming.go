@@ -0,0 +1,254 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package exec
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"k8s.io/klog/v2"
+)
+
+// ErrExecutorClosed is returned by StreamingExecutor.Add() after Close().
+var ErrExecutorClosed = errors.New("executor is closed")
+
+// StreamingExecutor is an Executor that accepts Actions while it is running.
+// This allows execution to start before all of the Actions are known, e.g.
+// while the rest of a large graph is still being planned.
+type StreamingExecutor interface {
+	Executor
+	// Add Actions to be executed. Events signaled by Actions that have
+	// already completed are delivered to the new Actions, so the order of
+	// Add() calls does not matter. Add() is safe to call concurrently with
+	// Run().
+	Add(actions ...Action) error
+	// Close signals that no more Actions will be added. Run() returns
+	// after Close() once there are no more Actions that can run.
+	Close()
+}
+
+// NewStreamingExecutor returns a new StreamingExecutor that runs Actions in
+// parallel as they become runnable. The default ErrorStrategy is
+// ContinueOnError.
+func NewStreamingExecutor(c cloud.Cloud, opts ...Option) (*streamingExecutor, error) {
+	ret := &streamingExecutor{
+		config: defaultParallelExecutorConfig(),
+		cloud:  c,
+		result: &Result{},
+		wake:   make(chan struct{}, 1),
+	}
+	for _, opt := range opts {
+		opt(ret.config)
+	}
+	if err := ret.config.validate(); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+type streamingExecutor struct {
+	config *ExecutorConfig
+	cloud  cloud.Cloud
+
+	// lock guards the fields below.
+	lock   sync.Mutex
+	result *Result
+	// signaled are all of the Events from completed Actions. These are
+	// replayed to Actions that are added later.
+	signaled EventList
+	// active is the number of running Actions.
+	active int
+	closed bool
+	// stop is set when the execution should not start new Actions.
+	stop error
+
+	// wake is signaled when the state changes.
+	wake chan struct{}
+}
+
+var _ StreamingExecutor = (*streamingExecutor)(nil)
+
+func (ex *streamingExecutor) Add(actions ...Action) error {
+	ex.lock.Lock()
+	defer ex.lock.Unlock()
+
+	if ex.closed {
+		return ErrExecutorClosed
+	}
+	for _, a := range actions {
+		for _, ev := range ex.signaled {
+			a.Signal(ev)
+		}
+		ex.result.Pending = append(ex.result.Pending, a)
+	}
+	ex.notify()
+	return nil
+}
+
+func (ex *streamingExecutor) Close() {
+	ex.lock.Lock()
+	defer ex.lock.Unlock()
+
+	ex.closed = true
+	ex.notify()
+}
+
+func (ex *streamingExecutor) notify() {
+	select {
+	case ex.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Run executes the Actions as they are added until Close() is called and
+// there are no more runnable Actions.
+//
+// If the context is cancelled, Run() will stop launching new Actions and
+// wait for the running Actions to return.
+func (ex *streamingExecutor) Run(ctx context.Context) (*Result, error) {
+	logger := klog.FromContext(ctx).WithName("StreamingExecutor")
+	ctx = klog.NewContext(ctx, logger)
+	ctx = ex.config.runContext(ctx)
+	if ex.config.Timeout != 0 {
+		var cancel context.CancelFunc
+		logger.V(4).Info("Run with timeout", "timeout", ex.config.Timeout)
+		ctx, cancel = context.WithTimeout(ctx, ex.config.Timeout)
+		defer cancel()
+	}
+
+	for {
+		ex.lock.Lock()
+		if ex.stop == nil {
+			ex.launch(ctx)
+		}
+		finished := ex.active == 0 && (ex.closed || ex.stop != nil)
+		ex.lock.Unlock()
+
+		if finished {
+			break
+		}
+
+		select {
+		case <-ex.wake:
+		case <-ctx.Done():
+			ex.lock.Lock()
+			if ex.stop == nil {
+				logger.V(2).Info("Context is Done, stopping", "err", ctx.Err())
+				ex.stop = ctx.Err()
+			}
+			ex.lock.Unlock()
+			// Wait for the active Actions to return.
+			ex.waitForActive()
+		}
+	}
+
+	ex.lock.Lock()
+	defer ex.lock.Unlock()
+
+	if ex.config.Tracer != nil {
+		ex.config.Tracer.Finish(ex.result.Pending)
+	}
+	if ex.stop != nil {
+		return ex.result, fmt.Errorf("StreamingExecutor: %w", ex.stop)
+	}
+	if len(ex.result.Errors) > 0 || len(ex.result.Pending) != 0 {
+		return ex.result, ErrPendingActions
+	}
+	if len(ex.result.VerificationErrors) > 0 {
+		return ex.result, ErrVerificationFailed
+	}
+	return ex.result, nil
+}
+
+// launch all of the runnable Actions. ex.lock must be held.
+func (ex *streamingExecutor) launch(ctx context.Context) {
+	var notRunnable []Action
+	for _, a := range ex.result.Pending {
+		if !a.CanRun() {
+			notRunnable = append(notRunnable, a)
+			continue
+		}
+		ex.active++
+		go ex.runAction(ctx, a)
+	}
+	ex.result.Pending = notRunnable
+}
+
+func (ex *streamingExecutor) waitForActive() {
+	for {
+		ex.lock.Lock()
+		active := ex.active
+		ex.lock.Unlock()
+		if active == 0 {
+			return
+		}
+		<-ex.wake
+	}
+}
+
+func (ex *streamingExecutor) runAction(ctx context.Context, a Action) {
+	te := &TraceEntry{
+		Action: a,
+		Start:  time.Now(),
+	}
+	logger := loggerWithAction(klog.FromContext(ctx), a)
+	logger.V(4).Info("Run action")
+
+	actCtx, wc := withWarningCollector(klog.NewContext(ctx, logger))
+	actCtx, sc := withSpawnCollector(actCtx)
+	events, runErr := ex.config.runLocked(actCtx, a, func() (EventList, error) {
+		return ex.config.run(actCtx, ex.cloud, a)
+	})
+	te.End = time.Now()
+	te.Warnings = wc.get()
+	logger.V(4).Info("Finish action", "err", runErr)
+
+	ex.lock.Lock()
+	defer ex.lock.Unlock()
+
+	if len(te.Warnings) > 0 {
+		ex.result.Warnings = append(ex.result.Warnings, ActionWithWarnings{Action: a, Warnings: te.Warnings})
+	}
+	if runErr == nil {
+		ex.result.Completed = append(ex.result.Completed, a)
+		var spawned []Action
+		events, spawned = joinSpawned(a, events, sc.get())
+		ex.result.Pending = append(ex.result.Pending, spawned...)
+		for _, ev := range events {
+			for _, p := range ex.result.Pending {
+				if p.Signal(ev) {
+					te.Signaled = append(te.Signaled, TraceSignal{Event: ev, SignaledAction: p})
+				}
+			}
+		}
+		ex.signaled = append(ex.signaled, events...)
+	} else {
+		ex.result.addError(a, runErr)
+		if ex.config.ErrorStrategy == StopOnError && ex.stop == nil && !isVerificationError(runErr) {
+			ex.stop = fmt.Errorf("stopping execution for Action %s: %w", a, runErr)
+		}
+	}
+	if ex.config.Tracer != nil {
+		ex.config.Tracer.Record(te, runErr)
+	}
+	ex.active--
+	ex.notify()
+}
@@ -0,0 +1,168 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package exec
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestStreamingExecutor(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		// batches of actions added in order. Dependencies may be in
+		// different batches.
+		batches []string
+		opts    []Option
+		// pending should be sorted alphabetically for comparison.
+		pending []string
+		wantErr bool
+	}{
+		{
+			name: "empty",
+		},
+		{
+			name:    "one batch",
+			batches: []string{"A -> B -> C"},
+		},
+		{
+			name:    "dependency in a later batch",
+			batches: []string{"A", "B", "A -> C"},
+		},
+		{
+			name:    "dependency completed before added",
+			batches: []string{"A -> B", "B -> C; A -> C"},
+		},
+		{
+			name:    "error in action",
+			batches: []string{"A -> !B", "B -> C", "D"},
+			pending: []string{"C"},
+			wantErr: true,
+		},
+		{
+			name:    "dry run",
+			batches: []string{"A -> !B", "B -> C"},
+			opts:    []Option{DryRunOption(true)},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			ex, err := NewStreamingExecutor(nil, tc.opts...)
+			if err != nil {
+				t.Fatalf("NewStreamingExecutor() = %v", err)
+			}
+			type runResult struct {
+				result *Result
+				err    error
+			}
+			done := make(chan runResult)
+			go func() {
+				r, err := ex.Run(context.Background())
+				done <- runResult{r, err}
+			}()
+			// Actions for each event name are shared between batches.
+			all := map[string]*testAction{}
+			for _, batch := range tc.batches {
+				var acts []Action
+				for _, a := range actionsFromGraphStr(batch) {
+					ta := a.(*testAction)
+					if _, ok := all[ta.name]; ok {
+						// Dependency only; the Action was already added.
+						continue
+					}
+					all[ta.name] = ta
+					acts = append(acts, ta)
+				}
+				if err := ex.Add(acts...); err != nil {
+					t.Fatalf("Add() = %v", err)
+				}
+				time.Sleep(5 * time.Millisecond)
+			}
+			ex.Close()
+			r := <-done
+
+			if gotErr := r.err != nil; gotErr != tc.wantErr {
+				t.Errorf("Run() = %v; gotErr = %t, want %t", r.err, gotErr, tc.wantErr)
+			}
+			var pending []string
+			for _, a := range r.result.Pending {
+				pending = append(pending, a.(*testAction).name)
+			}
+			sort.Strings(pending)
+			if diff := cmp.Diff(pending, tc.pending); diff != "" {
+				t.Errorf("pending: -got,+want: %s", diff)
+			}
+			if err := ex.Add(&testAction{name: "Z"}); !errors.Is(err, ErrExecutorClosed) {
+				t.Errorf("Add() after Close() = %v, want ErrExecutorClosed", err)
+			}
+		})
+	}
+}
+
+func TestStreamingExecutorMissingDependency(t *testing.T) {
+	ex, err := NewStreamingExecutor(nil)
+	if err != nil {
+		t.Fatalf("NewStreamingExecutor() = %v", err)
+	}
+	b := &testAction{name: "B", ActionBase: ActionBase{Want: EventList{StringEvent("X")}}}
+	ex.Add(&testAction{name: "A", events: EventList{StringEvent("A")}}, b)
+	ex.Close()
+
+	r, err := ex.Run(context.Background())
+	if !errors.Is(err, ErrPendingActions) {
+		t.Errorf("Run() = %v, want ErrPendingActions", err)
+	}
+	if len(r.Pending) != 1 || r.Pending[0] != Action(b) {
+		t.Errorf("Pending = %v, want [B]", r.Pending)
+	}
+}
+
+func TestStreamingExecutorStopOnError(t *testing.T) {
+	ex, err := NewStreamingExecutor(nil, ErrorStrategyOption(StopOnError))
+	if err != nil {
+		t.Fatalf("NewStreamingExecutor() = %v", err)
+	}
+	ex.Add(actionsFromGraphStr("!A -> B")...)
+	// Run() returns without Close() as execution is stopped.
+	r, err := ex.Run(context.Background())
+	if err == nil {
+		t.Fatalf("Run() = nil, want error")
+	}
+	if len(r.Errors) != 1 || len(r.Pending) != 1 {
+		t.Errorf("Errors = %v, Pending = %v; want 1 error and 1 pending", r.Errors, r.Pending)
+	}
+}
+
+func TestStreamingExecutorCancel(t *testing.T) {
+	ex, err := NewStreamingExecutor(nil)
+	if err != nil {
+		t.Fatalf("NewStreamingExecutor() = %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	ex.Add(&testAction{name: "A", runHook: func(context.Context) error {
+		cancel()
+		return nil
+	}})
+	// Run() returns without Close() as the context is cancelled.
+	if _, err := ex.Run(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("Run() = %v, want context.Canceled", err)
+	}
+}
@@ -17,8 +17,14 @@ limitations under the License.
 package exec
 
 import (
+	"context"
 	"errors"
 	"strings"
+	"sync"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/google/go-cmp/cmp"
 )
 
 // actionsFromGraphStr parses a graph in the form of "A -> B -> C; B -> D" to a
@@ -67,3 +73,181 @@ func actionsFromGraphStr(graphStr string) []Action {
 
 	return actions
 }
+
+type recordingTracer struct {
+	lock    sync.Mutex
+	entries []*TraceEntry
+}
+
+func (tr *recordingTracer) Record(entry *TraceEntry, _ error) {
+	tr.lock.Lock()
+	defer tr.lock.Unlock()
+	tr.entries = append(tr.entries, entry)
+}
+
+func (tr *recordingTracer) Finish([]Action) {}
+
+func TestExecutorWarnings(t *testing.T) {
+	warns := []cloud.OperationWarning{{Code: "DEPRECATED_RESOURCE_USED", Message: "deprecated"}}
+
+	for _, tc := range []struct {
+		name        string
+		newExecutor func(cloud.Cloud, []Action, ...Option) (Executor, error)
+	}{
+		{
+			name: "serial",
+			newExecutor: func(c cloud.Cloud, acts []Action, opts ...Option) (Executor, error) {
+				return NewSerialExecutor(c, acts, opts...)
+			},
+		},
+		{
+			name: "parallel",
+			newExecutor: func(c cloud.Cloud, acts []Action, opts ...Option) (Executor, error) {
+				return NewParallelExecutor(c, acts, opts...)
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			a := &testAction{
+				name:   "A",
+				events: EventList{StringEvent("A")},
+				runHook: func(ctx context.Context) error {
+					cloud.ReportOperationWarnings(ctx, warns)
+					return nil
+				},
+			}
+			b := &testAction{
+				name:       "B",
+				ActionBase: ActionBase{Want: EventList{StringEvent("A")}},
+			}
+			tr := &recordingTracer{}
+			ex, err := tc.newExecutor(nil, []Action{a, b}, TracerOption(tr))
+			if err != nil {
+				t.Fatalf("newExecutor() = %v", err)
+			}
+			result, err := ex.Run(context.Background())
+			if err != nil {
+				t.Fatalf("Run() = %v", err)
+			}
+
+			if len(result.Warnings) != 1 || result.Warnings[0].Action != a {
+				t.Fatalf("result.Warnings = %v, want warnings for A", result.Warnings)
+			}
+			if diff := cmp.Diff(result.Warnings[0].Warnings, warns); diff != "" {
+				t.Errorf("result.Warnings: diff -got,+want: %s", diff)
+			}
+			for _, te := range tr.entries {
+				var want []cloud.OperationWarning
+				if te.Action == a {
+					want = warns
+				}
+				if diff := cmp.Diff(te.Warnings, want); diff != "" {
+					t.Errorf("TraceEntry(%s).Warnings: diff -got,+want: %s", te.Action, diff)
+				}
+			}
+		})
+	}
+}
+
+func TestExecutorRunContext(t *testing.T) {
+	for _, tc := range []struct {
+		name        string
+		newExecutor func(cloud.Cloud, []Action, ...Option) (Executor, error)
+	}{
+		{
+			name: "serial",
+			newExecutor: func(c cloud.Cloud, acts []Action, opts ...Option) (Executor, error) {
+				return NewSerialExecutor(c, acts, opts...)
+			},
+		},
+		{
+			name: "parallel",
+			newExecutor: func(c cloud.Cloud, acts []Action, opts ...Option) (Executor, error) {
+				return NewParallelExecutor(c, acts, opts...)
+			},
+		},
+		{
+			name: "streaming",
+			newExecutor: func(c cloud.Cloud, acts []Action, opts ...Option) (Executor, error) {
+				ex, err := NewStreamingExecutor(c, opts...)
+				if err != nil {
+					return nil, err
+				}
+				if err := ex.Add(acts...); err != nil {
+					return nil, err
+				}
+				ex.Close()
+				return ex, nil
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var strict, skip, validate bool
+			a := &validatingAction{testAction{
+				name:   "A",
+				events: EventList{StringEvent("A")},
+				runHook: func(ctx context.Context) error {
+					strict, skip, validate = StrictFingerprint(ctx), SkipUnchanged(ctx), ValidateOnly(ctx)
+					return nil
+				},
+			}}
+			ex, err := tc.newExecutor(nil, []Action{a},
+				StrictFingerprintOption(true), SkipUnchangedOption(true), DryRunOption(true), ValidateOnlyOption(true))
+			if err != nil {
+				t.Fatalf("newExecutor() = %v", err)
+			}
+			if _, err := ex.Run(context.Background()); err != nil {
+				t.Fatalf("Run() = %v", err)
+			}
+			if !strict || !skip || !validate {
+				t.Errorf("StrictFingerprint, SkipUnchanged, ValidateOnly = %t, %t, %t; want all true", strict, skip, validate)
+			}
+		})
+	}
+}
+
+func TestExecutorVerificationErrors(t *testing.T) {
+	for _, tc := range []struct {
+		name        string
+		newExecutor func(cloud.Cloud, []Action, ...Option) (Executor, error)
+	}{
+		{
+			name: "serial",
+			newExecutor: func(c cloud.Cloud, acts []Action, opts ...Option) (Executor, error) {
+				return NewSerialExecutor(c, acts, opts...)
+			},
+		},
+		{
+			name: "parallel",
+			newExecutor: func(c cloud.Cloud, acts []Action, opts ...Option) (Executor, error) {
+				return NewParallelExecutor(c, acts, opts...)
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			a := &testAction{name: "A", events: EventList{StringEvent("A")}}
+			v := &testAction{
+				name:       "V",
+				ActionBase: ActionBase{Want: EventList{StringEvent("A")}},
+				err:        &VerificationError{Err: errors.New("injected")},
+			}
+			ex, err := tc.newExecutor(nil, []Action{a, v})
+			if err != nil {
+				t.Fatalf("newExecutor() = %v", err)
+			}
+			result, err := ex.Run(context.Background())
+			if !errors.Is(err, ErrVerificationFailed) {
+				t.Fatalf("Run() = %v, want ErrVerificationFailed", err)
+			}
+			if len(result.Errors) != 0 {
+				t.Errorf("result.Errors = %v, want none", result.Errors)
+			}
+			if len(result.VerificationErrors) != 1 || result.VerificationErrors[0].Action != v {
+				t.Errorf("result.VerificationErrors = %v, want error for V", result.VerificationErrors)
+			}
+			if len(result.Completed) != 1 || result.Completed[0] != a {
+				t.Errorf("result.Completed = %v, want [A]", result.Completed)
+			}
+		})
+	}
+}
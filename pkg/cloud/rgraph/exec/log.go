@@ -0,0 +1,40 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package exec
+
+import (
+	"sort"
+
+	"k8s.io/klog/v2"
+)
+
+// loggerWithAction returns a logger with the keys identifying the Action:
+// "action" and the Annotations of the Action (see WithAnnotations()).
+func loggerWithAction(logger klog.Logger, a Action) klog.Logger {
+	m := a.Metadata()
+	kv := []any{"action", m.Name}
+
+	var keys []string
+	for k := range m.Annotations {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		kv = append(kv, k, m.Annotations[k])
+	}
+	return logger.WithValues(kv...)
+}
@@ -0,0 +1,64 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package exec
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"k8s.io/klog/v2"
+	"k8s.io/klog/v2/ktesting"
+)
+
+func TestExecutorContextualLogging(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		new  func(pending []Action) (Executor, error)
+	}{
+		{
+			name: "serial",
+			new:  func(p []Action) (Executor, error) { return NewSerialExecutor(nil, p) },
+		},
+		{
+			name: "parallel",
+			new:  func(p []Action) (Executor, error) { return NewParallelExecutor(nil, p) },
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tl := &ktesting.BufferTL{}
+			logger := ktesting.NewLogger(tl, ktesting.NewConfig(ktesting.Verbosity(4)))
+			ctx := klog.NewContext(context.Background(), logger)
+
+			a := WithAnnotations(&testAction{name: "A"}, map[string]string{"service": "ns/svc"})
+			ex, err := tc.new([]Action{a})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := ex.Run(ctx); err != nil {
+				t.Fatalf("Run() = %v, want nil", err)
+			}
+
+			out := tl.String()
+			// The Action log lines carry the action name and annotations.
+			const want = `Run action action="A([])" service="ns/svc"`
+			if !strings.Contains(out, want) {
+				t.Errorf("log output does not contain %s:\n%s", want, out)
+			}
+		})
+	}
+}
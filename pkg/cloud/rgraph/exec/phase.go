@@ -0,0 +1,148 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package exec
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+)
+
+// Phase groups Actions for coarse-grained sequencing: all of the Actions in
+// a Phase must complete before any Action in a later Phase is started (e.g.
+// all creates before any routing changes). Phases are ordered by value.
+// Actions with PhaseNone are not sequenced.
+//
+// Phases are enforced by the serial and parallel Executors by adding a
+// barrier Action between each Phase. Note: the Phases must be consistent
+// with the dependencies between the Actions, otherwise the Actions will be
+// deadlocked.
+type Phase int
+
+const (
+	// PhaseNone is the default; the Action is not sequenced.
+	PhaseNone Phase = 0
+	// PhaseInfra is for Actions that create or update the resources that
+	// serve traffic (e.g. BackendServices, HealthChecks).
+	PhaseInfra Phase = 10
+	// PhaseRouting is for Actions that change how traffic is routed (e.g.
+	// ForwardingRules, UrlMaps).
+	PhaseRouting Phase = 20
+	// PhaseCleanup is for Actions that delete resources that are no
+	// longer used.
+	PhaseCleanup Phase = 30
+)
+
+func (p Phase) String() string {
+	switch p {
+	case PhaseNone:
+		return "None"
+	case PhaseInfra:
+		return "Infra"
+	case PhaseRouting:
+		return "Routing"
+	case PhaseCleanup:
+		return "Cleanup"
+	}
+	return fmt.Sprintf("Phase(%d)", int(p))
+}
+
+// phasedAction sets the Phase in the Metadata of an Action.
+type phasedAction struct {
+	Action
+	phase Phase
+}
+
+// WithPhase returns an Action that is in Phase p. Returns a unchanged if p
+// is PhaseNone.
+func WithPhase(a Action, p Phase) Action {
+	if p == PhaseNone {
+		return a
+	}
+	return &phasedAction{Action: a, phase: p}
+}
+
+func (a *phasedAction) Metadata() *ActionMetadata {
+	m := *a.Action.Metadata()
+	m.Phase = a.phase
+	return &m
+}
+
+// addPhaseBarriers returns the Actions with barriers between the Phases.
+// Each Action in a Phase signals an Event that is waited for by the barrier
+// Action for the Phase. The Actions in the next Phase wait for the barrier.
+// Returns acts unchanged if no Action has a Phase.
+func addPhaseBarriers(acts []Action) []Action {
+	byPhase := map[Phase][]int{}
+	for i, a := range acts {
+		if p := a.Metadata().Phase; p != PhaseNone {
+			byPhase[p] = append(byPhase[p], i)
+		}
+	}
+	if len(byPhase) < 2 {
+		return acts
+	}
+	var phases []Phase
+	for p := range byPhase {
+		phases = append(phases, p)
+	}
+	sort.Slice(phases, func(i, j int) bool { return phases[i] < phases[j] })
+
+	ret := append([]Action(nil), acts...)
+	for i, p := range phases[:len(phases)-1] {
+		barrier := &phaseBarrierAction{phase: p}
+		for _, j := range byPhase[p] {
+			ev := StringEvent(fmt.Sprintf("PhaseActionDone(%s)", ret[j].Metadata().Name))
+			barrier.Want = append(barrier.Want, ev)
+			ret[j] = WithSignal(ret[j], ev)
+		}
+		for _, j := range byPhase[phases[i+1]] {
+			ret[j] = WithWant(ret[j], barrier.event())
+		}
+		ret = append(ret, barrier)
+	}
+	return ret
+}
+
+// phaseBarrierAction signals that all of the Actions in a Phase have
+// completed.
+type phaseBarrierAction struct {
+	ActionBase
+	phase Phase
+}
+
+func (a *phaseBarrierAction) event() Event {
+	return StringEvent(fmt.Sprintf("PhaseDone(%v)", a.phase))
+}
+
+func (a *phaseBarrierAction) Run(context.Context, cloud.Cloud) (EventList, error) {
+	return EventList{a.event()}, nil
+}
+
+func (a *phaseBarrierAction) DryRun() EventList { return EventList{a.event()} }
+
+func (a *phaseBarrierAction) String() string { return fmt.Sprintf("PhaseBarrierAction(%v)", a.phase) }
+
+func (a *phaseBarrierAction) Metadata() *ActionMetadata {
+	return &ActionMetadata{
+		Name:    a.String(),
+		Type:    ActionTypeMeta,
+		Summary: fmt.Sprintf("All Actions in phase %v are done", a.phase),
+	}
+}
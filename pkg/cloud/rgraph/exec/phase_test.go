@@ -0,0 +1,146 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package exec
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestPhases(t *testing.T) {
+	type phased struct {
+		name  string
+		phase Phase
+		fail  bool
+	}
+	for _, tc := range []struct {
+		name    string
+		actions []phased
+		// before[x] are the Actions that must finish before x starts.
+		before map[string][]string
+		// completed should be sorted alphabetically for comparison.
+		completed []string
+	}{
+		{
+			name:      "no phases",
+			actions:   []phased{{name: "A"}, {name: "B"}},
+			completed: []string{"A", "B"},
+		},
+		{
+			name: "three phases",
+			actions: []phased{
+				{name: "C1", phase: PhaseCleanup},
+				{name: "I1", phase: PhaseInfra},
+				{name: "I2", phase: PhaseInfra},
+				{name: "N"},
+				{name: "R1", phase: PhaseRouting},
+			},
+			before: map[string][]string{
+				"R1": {"I1", "I2"},
+				"C1": {"I1", "I2", "R1"},
+			},
+			completed: []string{"C1", "I1", "I2", "N", "R1"},
+		},
+		{
+			name: "error blocks later phases",
+			actions: []phased{
+				{name: "I1", phase: PhaseInfra, fail: true},
+				{name: "I2", phase: PhaseInfra},
+				{name: "N"},
+				{name: "R1", phase: PhaseRouting},
+			},
+			completed: []string{"I2", "N"},
+		},
+	} {
+		for _, executor := range []string{"serial", "parallel"} {
+			t.Run(tc.name+" "+executor, func(t *testing.T) {
+				var (
+					lock      sync.Mutex
+					finished  = map[string]bool{}
+					completed []string
+				)
+				var acts []Action
+				for _, p := range tc.actions {
+					p := p
+					a := &testAction{
+						name:   p.name,
+						events: EventList{StringEvent(p.name)},
+						runHook: func(context.Context) error {
+							lock.Lock()
+							for _, b := range tc.before[p.name] {
+								if !finished[b] {
+									t.Errorf("%s started before %s finished", p.name, b)
+								}
+							}
+							lock.Unlock()
+							// Give the other Actions a chance to run.
+							time.Sleep(time.Millisecond)
+							lock.Lock()
+							defer lock.Unlock()
+							finished[p.name] = true
+							if p.fail {
+								return errors.New("injected")
+							}
+							completed = append(completed, p.name)
+							return nil
+						},
+					}
+					acts = append(acts, WithPhase(a, p.phase))
+				}
+
+				var (
+					ex  Executor
+					err error
+				)
+				switch executor {
+				case "serial":
+					ex, err = NewSerialExecutor(nil, acts, ErrorStrategyOption(ContinueOnError))
+				case "parallel":
+					ex, err = NewParallelExecutor(nil, acts)
+				}
+				if err != nil {
+					t.Fatalf("new executor = %v", err)
+				}
+				ex.Run(context.Background())
+
+				sort.Strings(completed)
+				if diff := cmp.Diff(completed, tc.completed); diff != "" {
+					t.Errorf("completed: diff -got,+want: %s", diff)
+				}
+			})
+		}
+	}
+}
+
+func TestWithPhase(t *testing.T) {
+	a := &testAction{name: "A"}
+	if got := WithPhase(a, PhaseNone); got != Action(a) {
+		t.Errorf("WithPhase(a, PhaseNone) = %v, want a", got)
+	}
+	if got := WithPhase(a, PhaseRouting).Metadata().Phase; got != PhaseRouting {
+		t.Errorf("Metadata().Phase = %v, want %v", got, PhaseRouting)
+	}
+	if got := a.Metadata().Phase; got != PhaseNone {
+		t.Errorf("a.Metadata().Phase = %v, want %v", got, PhaseNone)
+	}
+}
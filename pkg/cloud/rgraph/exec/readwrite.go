@@ -0,0 +1,58 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package exec
+
+import (
+	"context"
+	"time"
+)
+
+// ReadYourWrites bounds the polling done by Actions that need to observe
+// the result of their own write. An immediate Get after an Insert or Patch
+// may return stale data.
+type ReadYourWrites struct {
+	// Attempts is the maximum number of Gets.
+	Attempts int
+	// Interval between the Gets.
+	Interval time.Duration
+}
+
+// ReadYourWritesOption makes update Actions poll the resource after the
+// write until the change is observed (e.g. the fingerprint changed). The
+// Action fails with an error if the change is not observed within the bounds
+// of r.
+func ReadYourWritesOption(r ReadYourWrites) Option {
+	return func(c *ExecutorConfig) { c.ReadYourWrites = &r }
+}
+
+var readYourWritesContextKey = contextKey("read your writes")
+
+// WithReadYourWrites returns a context that enables read-your-writes for
+// Actions run with it. See ReadYourWritesOption.
+func WithReadYourWrites(ctx context.Context, r ReadYourWrites) context.Context {
+	return context.WithValue(ctx, readYourWritesContextKey, r)
+}
+
+// ReadYourWritesConfig returns the bounds for read-your-writes polling.
+// Returns nil if read-your-writes is not enabled.
+func ReadYourWritesConfig(ctx context.Context) *ReadYourWrites {
+	r, ok := ctx.Value(readYourWritesContextKey).(ReadYourWrites)
+	if !ok {
+		return nil
+	}
+	return &r
+}
@@ -0,0 +1,63 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package exec
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+)
+
+// ReferrersFunc returns the resources in the Cloud that reference id.
+type ReferrersFunc func(ctx context.Context, cl cloud.Cloud, id *cloud.ResourceID) ([]*cloud.ResourceID, error)
+
+// ReferencedError is returned by a delete Action when resources in the Cloud
+// still reference the resource to be deleted. This is usually caused by
+// resources that are not in the graph, e.g. a manually created
+// ForwardingRule pointing at a managed TargetHttpProxy.
+type ReferencedError struct {
+	// ID of the resource to be deleted.
+	ID *cloud.ResourceID
+	// Referrers that still reference the resource.
+	Referrers []*cloud.ResourceID
+}
+
+func (e *ReferencedError) Error() string {
+	var refs []string
+	for _, r := range e.Referrers {
+		refs = append(refs, r.String())
+	}
+	return fmt.Sprintf("cannot delete %v: still referenced by [%s]", e.ID, strings.Join(refs, ", "))
+}
+
+var deleteRefCheckContextKey = contextKey("delete ref check")
+
+// WithDeleteRefCheck returns a context that enables the referrer check for
+// delete Actions run with it. See DeleteRefCheckOption.
+func WithDeleteRefCheck(ctx context.Context, f ReferrersFunc) context.Context {
+	return context.WithValue(ctx, deleteRefCheckContextKey, f)
+}
+
+// DeleteRefCheck returns the ReferrersFunc that a delete Action should use to
+// check for live referrers before deleting. Returns nil if the check is not
+// enabled.
+func DeleteRefCheck(ctx context.Context) ReferrersFunc {
+	f, _ := ctx.Value(deleteRefCheckContextKey).(ReferrersFunc)
+	return f
+}
@@ -47,15 +47,10 @@ func (ra *retriableAction) Run(ctx context.Context, c cloud.Cloud) (EventList, e
 			return events, nil
 		}
 		if canRetry, backOffTime := ra.canRetry(err); canRetry {
-			timer := time.NewTimer(backOffTime)
-			select {
-			case <-timer.C:
-				timer.Stop()
-				continue
-			case <-ctx.Done():
-				timer.Stop()
+			if err := cloud.SleepWithContext(ctx, backOffTime); err != nil {
 				return nil, fmt.Errorf("context canceled")
 			}
+			continue
 		}
 		return events, err
 	}
@@ -0,0 +1,52 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package exec
+
+import (
+	"context"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+)
+
+// signalAction signals additional Events when an Action succeeds.
+type signalAction struct {
+	Action
+	events EventList
+}
+
+// WithSignal returns an Action that signals events in addition to the events
+// of a when a completes without error. This is used to add dependencies on
+// Actions that were created without them (e.g. postcondition checks).
+// Returns a unchanged if there are no events.
+func WithSignal(a Action, events ...Event) Action {
+	if len(events) == 0 {
+		return a
+	}
+	return &signalAction{Action: a, events: append(EventList(nil), events...)}
+}
+
+func (a *signalAction) Run(ctx context.Context, cl cloud.Cloud) (EventList, error) {
+	events, err := a.Action.Run(ctx, cl)
+	if err != nil {
+		return events, err
+	}
+	return append(events, a.events...), nil
+}
+
+func (a *signalAction) DryRun() EventList {
+	return append(a.Action.DryRun(), a.events...)
+}
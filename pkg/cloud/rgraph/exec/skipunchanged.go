@@ -0,0 +1,43 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package exec
+
+import "context"
+
+// SkipUnchangedOption makes update Actions re-fetch the resource before the
+// write and skip the write if the live resource already matches the wanted
+// state. The Action signals its Events as if the update succeeded. This
+// avoids redundant writes when multiple reconcilers converge on the same
+// resources.
+func SkipUnchangedOption(skip bool) Option {
+	return func(c *ExecutorConfig) { c.SkipUnchanged = skip }
+}
+
+var skipUnchangedContextKey = contextKey("skip unchanged")
+
+// WithSkipUnchanged returns a context that enables skipping unchanged
+// updates for Actions run with it. See SkipUnchangedOption.
+func WithSkipUnchanged(ctx context.Context) context.Context {
+	return context.WithValue(ctx, skipUnchangedContextKey, true)
+}
+
+// SkipUnchanged returns true if the Action should skip the update when the
+// live resource already matches the wanted state.
+func SkipUnchanged(ctx context.Context) bool {
+	v, _ := ctx.Value(skipUnchangedContextKey).(bool)
+	return v
+}
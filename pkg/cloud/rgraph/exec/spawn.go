@@ -0,0 +1,113 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package exec
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+)
+
+var spawnContextKey = contextKey("spawn")
+
+// spawnCollector accumulates the Actions spawned by a single Action.
+type spawnCollector struct {
+	lock sync.Mutex
+	acts []Action
+}
+
+// withSpawnCollector returns a context that records the Actions passed to
+// Spawn() into a new spawnCollector.
+func withSpawnCollector(ctx context.Context) (context.Context, *spawnCollector) {
+	sc := &spawnCollector{}
+	return context.WithValue(ctx, spawnContextKey, sc), sc
+}
+
+func (sc *spawnCollector) get() []Action {
+	sc.lock.Lock()
+	defer sc.lock.Unlock()
+	return sc.acts
+}
+
+// Spawn adds new Actions to the Executor running the current Action. This
+// is used for operations that are only known at run time (e.g. detaching
+// the endpoints found by listing a NEG). ctx must be the context passed to
+// Action.Run().
+//
+// The spawned Actions are added to the pending Actions only if the Run() of
+// the current Action succeeds. The Events of the current Action are
+// signaled after all of the spawned Actions have completed, so Actions
+// waiting on the current Action also wait for the spawned Actions.
+//
+// Spawned Actions do not take part in Phases; they run as soon as they are
+// runnable. The Want list of a spawned Action should only contain Events
+// from other spawned Actions as Events signaled before the Action was
+// spawned are not replayed. Actions cannot be spawned in DryRun().
+func Spawn(ctx context.Context, acts ...Action) error {
+	sc, ok := ctx.Value(spawnContextKey).(*spawnCollector)
+	if !ok {
+		return fmt.Errorf("Spawn: context is not from an Executor")
+	}
+	sc.lock.Lock()
+	defer sc.lock.Unlock()
+	sc.acts = append(sc.acts, acts...)
+	return nil
+}
+
+// joinSpawned returns the Events to signal and the Actions to add to Pending
+// for Action a that returned events and spawned the given Actions. If there
+// are spawned Actions, the events are deferred to a join Action that waits
+// for all of the spawned Actions.
+func joinSpawned(a Action, events EventList, spawned []Action) (EventList, []Action) {
+	if len(spawned) == 0 {
+		return events, nil
+	}
+	join := &spawnJoinAction{parent: a.Metadata().Name, events: events}
+	var ret []Action
+	for i, s := range spawned {
+		ev := StringEvent(fmt.Sprintf("SpawnedActionDone(%s, %d, %s)", join.parent, i, s.Metadata().Name))
+		join.Want = append(join.Want, ev)
+		ret = append(ret, WithSignal(s, ev))
+	}
+	return nil, append(ret, join)
+}
+
+// spawnJoinAction signals the Events of the parent Action once all of the
+// Actions spawned by the parent have completed.
+type spawnJoinAction struct {
+	ActionBase
+	parent string
+	events EventList
+}
+
+func (a *spawnJoinAction) Run(context.Context, cloud.Cloud) (EventList, error) {
+	return a.events, nil
+}
+
+func (a *spawnJoinAction) DryRun() EventList { return a.events }
+
+func (a *spawnJoinAction) String() string { return fmt.Sprintf("SpawnJoinAction(%s)", a.parent) }
+
+func (a *spawnJoinAction) Metadata() *ActionMetadata {
+	return &ActionMetadata{
+		Name:    a.String(),
+		Type:    ActionTypeMeta,
+		Summary: fmt.Sprintf("All Actions spawned by %s are done", a.parent),
+	}
+}
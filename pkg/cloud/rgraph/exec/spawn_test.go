@@ -0,0 +1,126 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package exec
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestSpawn(t *testing.T) {
+	for _, tc := range []struct {
+		name        string
+		newExecutor func(cloud.Cloud, []Action, ...Option) (Executor, error)
+	}{
+		{
+			name: "serial",
+			newExecutor: func(c cloud.Cloud, acts []Action, opts ...Option) (Executor, error) {
+				return NewSerialExecutor(c, acts, opts...)
+			},
+		},
+		{
+			name: "parallel",
+			newExecutor: func(c cloud.Cloud, acts []Action, opts ...Option) (Executor, error) {
+				return NewParallelExecutor(c, acts, opts...)
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var (
+				lock  sync.Mutex
+				order []string
+			)
+			record := func(name string) func(context.Context) error {
+				return func(context.Context) error {
+					lock.Lock()
+					defer lock.Unlock()
+					order = append(order, name)
+					return nil
+				}
+			}
+			s1 := &testAction{name: "S1", events: EventList{StringEvent("S1")}, runHook: record("S1")}
+			s2 := &testAction{
+				name:       "S2",
+				ActionBase: ActionBase{Want: EventList{StringEvent("S1")}},
+				runHook:    record("S2"),
+			}
+			a := &testAction{
+				name:   "A",
+				events: EventList{StringEvent("A")},
+				runHook: func(ctx context.Context) error {
+					record("A")(ctx)
+					return Spawn(ctx, s1, s2)
+				},
+			}
+			b := &testAction{
+				name:       "B",
+				ActionBase: ActionBase{Want: EventList{StringEvent("A")}},
+				runHook:    record("B"),
+			}
+			ex, err := tc.newExecutor(nil, []Action{a, b})
+			if err != nil {
+				t.Fatalf("newExecutor() = %v", err)
+			}
+			result, err := ex.Run(context.Background())
+			if err != nil {
+				t.Fatalf("Run() = %v", err)
+			}
+			if len(result.Pending) != 0 {
+				t.Errorf("result.Pending = %v, want none", result.Pending)
+			}
+			// A, S1, S2, B and the join Action.
+			if len(result.Completed) != 5 {
+				t.Errorf("len(result.Completed) = %d, want 5", len(result.Completed))
+			}
+			if diff := cmp.Diff(order, []string{"A", "S1", "S2", "B"}); diff != "" {
+				t.Errorf("order: diff -got,+want: %s", diff)
+			}
+		})
+	}
+}
+
+func TestSpawnParentError(t *testing.T) {
+	s := &testAction{name: "S"}
+	a := &testAction{
+		name: "A",
+		runHook: func(ctx context.Context) error {
+			if err := Spawn(ctx, s); err != nil {
+				return err
+			}
+			return errors.New("injected")
+		},
+	}
+	ex, err := NewSerialExecutor(nil, []Action{a}, ErrorStrategyOption(ContinueOnError))
+	if err != nil {
+		t.Fatalf("NewSerialExecutor() = %v", err)
+	}
+	result, _ := ex.Run(context.Background())
+	if len(result.Errors) != 1 || len(result.Completed) != 0 || len(result.Pending) != 0 {
+		t.Errorf("result = %+v, want 1 error and no spawned Actions", result)
+	}
+}
+
+func TestSpawnWithoutExecutor(t *testing.T) {
+	if err := Spawn(context.Background(), &testAction{name: "S"}); err == nil {
+		t.Error("Spawn() = nil, want error")
+	}
+}
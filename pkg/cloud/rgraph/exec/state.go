@@ -0,0 +1,125 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package exec
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+)
+
+// ExecutorState is the serializable state of a run of Actions that stopped
+// before all of the Actions were done, e.g. because Actions were waiting
+// for approval (see ApprovalGate) when the context expired. The run is
+// continued with Resume() using the same Actions, without replanning or
+// running the completed Actions again.
+//
+// Actions are identified by ActionMetadata.Name. Stop the parallel Executor
+// with TimeoutOption rather than by cancelling the context given to Run(), so
+// that the held Actions have stopped when Run() returns.
+type ExecutorState struct {
+	// Completed are the Actions that completed without error.
+	Completed []string `json:"completed,omitempty"`
+	// Pending are the Actions that did not complete.
+	Pending []string `json:"pending,omitempty"`
+	// Approved are the Actions that were approved.
+	Approved []string `json:"approved,omitempty"`
+}
+
+// NewExecutorState returns the state after the run that returned result.
+// gate is the ApprovalGate used for the run and may be nil.
+func NewExecutorState(result *Result, gate *ApprovalGate) *ExecutorState {
+	st := &ExecutorState{}
+	for _, a := range result.Completed {
+		st.Completed = append(st.Completed, a.Metadata().Name)
+	}
+	for _, a := range result.Pending {
+		st.Pending = append(st.Pending, a.Metadata().Name)
+	}
+	for _, a := range result.Errors {
+		st.Pending = append(st.Pending, a.Action.Metadata().Name)
+	}
+	if gate != nil {
+		st.Approved = gate.Approvals()
+	}
+	sort.Strings(st.Completed)
+	sort.Strings(st.Pending)
+	return st
+}
+
+// Approve the Action with the given name. This can be done while the run is
+// stopped; the approval is applied by ApprovalGate.Restore().
+func (st *ExecutorState) Approve(name string) {
+	for _, n := range st.Approved {
+		if n == name {
+			return
+		}
+	}
+	st.Approved = append(st.Approved, name)
+	sort.Strings(st.Approved)
+}
+
+// Write the state to w as JSON.
+func (st *ExecutorState) Write(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(st)
+}
+
+// ReadExecutorState reads the JSON state written by ExecutorState.Write().
+func ReadExecutorState(r io.Reader) (*ExecutorState, error) {
+	var st ExecutorState
+	if err := json.NewDecoder(r).Decode(&st); err != nil {
+		return nil, fmt.Errorf("ReadExecutorState: %w", err)
+	}
+	return &st, nil
+}
+
+// Resume returns the Actions that remain to be run from actions, the same
+// list of Actions that was given to the Executor for the run that produced
+// st. The completed Actions are removed and the remaining Actions are
+// signalled with the Events of the completed Actions (from DryRun()). Use
+// ApprovalGate.Restore() to apply the approvals in st before running the
+// returned Actions.
+//
+// Completed Actions that are not in actions are ignored, e.g. the Actions
+// added by the Executor and ApprovalGate.
+func Resume(actions []Action, st *ExecutorState) []Action {
+	completed := map[string]bool{}
+	for _, name := range st.Completed {
+		completed[name] = true
+	}
+
+	var (
+		ret    []Action
+		events EventList
+	)
+	for _, a := range actions {
+		if completed[a.Metadata().Name] {
+			events = append(events, a.DryRun()...)
+			continue
+		}
+		ret = append(ret, a)
+	}
+	for _, a := range ret {
+		for _, ev := range events {
+			a.Signal(ev)
+		}
+	}
+	return ret
+}
@@ -0,0 +1,105 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package exec
+
+import (
+	"bytes"
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestResumeWithApproval(t *testing.T) {
+	var (
+		lock sync.Mutex
+		runs = map[string]int{}
+	)
+	newAction := func(name string, want ...string) *testAction {
+		a := &testAction{name: name, events: EventList{StringEvent(name)}}
+		a.runHook = func(context.Context) error {
+			lock.Lock()
+			defer lock.Unlock()
+			runs[name]++
+			return nil
+		}
+		for _, w := range want {
+			a.Want = append(a.Want, StringEvent(w))
+		}
+		return a
+	}
+	// A -> B -> C; D. B requires approval.
+	actions := []Action{
+		newAction("A"),
+		RequireApproval(newAction("B", "A")),
+		newAction("C", "B"),
+		newAction("D"),
+	}
+	nameB := actions[1].Metadata().Name
+
+	// The first run stops while B is waiting for approval.
+	gate := NewApprovalGate()
+	// TimeoutOption waits for the approval Action to stop before returning.
+	ex, err := NewParallelExecutor(nil, gate.Wrap(actions), TimeoutOption(20*time.Millisecond))
+	if err != nil {
+		t.Fatalf("NewParallelExecutor() = %v", err)
+	}
+	result, err := ex.Run(context.Background())
+	if err == nil {
+		t.Fatalf("Run() = nil, want error")
+	}
+	st := NewExecutorState(result, gate)
+	if diff := cmp.Diff([]string{"A([A])", "D([D])"}, st.Completed); diff != "" {
+		t.Errorf("Completed: diff -want,+got: %s", diff)
+	}
+
+	// The operator approves B later on from the saved state.
+	var buf bytes.Buffer
+	if err := st.Write(&buf); err != nil {
+		t.Fatalf("Write() = %v", err)
+	}
+	st, err = ReadExecutorState(&buf)
+	if err != nil {
+		t.Fatalf("ReadExecutorState() = %v", err)
+	}
+	st.Approve(nameB)
+	st.Approve(nameB)
+	if diff := cmp.Diff([]string{nameB}, st.Approved); diff != "" {
+		t.Errorf("Approved: diff -want,+got: %s", diff)
+	}
+
+	// Resume the run.
+	remaining := Resume(actions, st)
+	if len(remaining) != 2 {
+		t.Fatalf("len(Resume()) = %d, want 2 (B and C)", len(remaining))
+	}
+	gate = NewApprovalGate()
+	remaining = gate.Wrap(remaining)
+	gate.Restore(st)
+	serial, err := NewSerialExecutor(nil, remaining)
+	if err != nil {
+		t.Fatalf("NewSerialExecutor() = %v", err)
+	}
+	if _, err := serial.Run(context.Background()); err != nil {
+		t.Fatalf("Run() = %v, want nil", err)
+	}
+	if diff := cmp.Diff(map[string]int{"A": 1, "B": 1, "C": 1, "D": 1}, runs); diff != "" {
+		t.Errorf("runs: diff -want,+got: %s", diff)
+	}
+}
@@ -16,7 +16,11 @@ limitations under the License.
 
 package exec
 
-import "time"
+import (
+	"time"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+)
 
 // Tracer is a sink for tracing an execution.
 type Tracer interface {
@@ -29,6 +33,8 @@ type TraceEntry struct {
 	Action   Action
 	Err      error
 	Signaled []TraceSignal
+	// Warnings reported by the operations performed by the Action.
+	Warnings []cloud.OperationWarning
 
 	Start time.Time
 	End   time.Time
@@ -19,6 +19,8 @@ package exec
 import (
 	"bytes"
 	"fmt"
+	"html"
+	"sort"
 	"sync"
 	"time"
 )
@@ -74,6 +76,14 @@ func (tr *GraphvizTracer) Record(entry *TraceEntry, err error) {
 	tr.outf("      <tr><td colspan=\"2\">%s</td></tr>", metadata.Summary)
 	tr.outf("      <tr><td>Start (delta)</td><td>%v</td></tr>", entry.Start.Sub(tr.start))
 	tr.outf("      <tr><td>Duration</td><td>%v</td></tr>", entry.End.Sub(entry.Start))
+	var keys []string
+	for k := range metadata.Annotations {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		tr.outf("      <tr><td>%s</td><td>%s</td></tr>", html.EscapeString(k), html.EscapeString(metadata.Annotations[k]))
+	}
 	if err != nil {
 		tr.outf("      <tr><td><b>Error</b></td><td><b>%v</b></td></tr>", err)
 	}
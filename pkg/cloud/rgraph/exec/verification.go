@@ -0,0 +1,52 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package exec
+
+import "errors"
+
+// ErrVerificationFailed is returned by the Executors when all of the Actions
+// were applied but one or more verification Actions failed. See
+// VerificationError.
+var ErrVerificationFailed = errors.New("Executor verification failed")
+
+// VerificationError is returned by an Action that verifies the result of
+// other Actions (e.g. the postconditions of a Node) when the verification
+// fails. The Executors report these in Result.VerificationErrors instead of
+// Result.Errors to distinguish them from errors applying the changes.
+type VerificationError struct {
+	Err error
+}
+
+func (e *VerificationError) Error() string { return "verification failed: " + e.Err.Error() }
+
+func (e *VerificationError) Unwrap() error { return e.Err }
+
+// isVerificationError is true if err is a VerificationError. Verification
+// errors do not stop execution as the changes have already been applied.
+func isVerificationError(err error) bool {
+	var vErr *VerificationError
+	return errors.As(err, &vErr)
+}
+
+// addError records the error for the Action in the appropriate list.
+func (r *Result) addError(a Action, err error) {
+	if isVerificationError(err) {
+		r.VerificationErrors = append(r.VerificationErrors, ActionWithErr{Action: a, Err: err})
+		return
+	}
+	r.Errors = append(r.Errors, ActionWithErr{Action: a, Err: err})
+}
@@ -0,0 +1,51 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package exec
+
+// wantAction adds additional Events that an Action must wait for.
+type wantAction struct {
+	Action
+	ActionBase
+}
+
+// WithWant returns an Action that cannot run until a can run and all of the
+// events have been signaled. This is used to add dependencies to Actions
+// that were created without them (e.g. precondition checks). Returns a
+// unchanged if there are no events.
+func WithWant(a Action, events ...Event) Action {
+	if len(events) == 0 {
+		return a
+	}
+	return &wantAction{
+		Action:     a,
+		ActionBase: ActionBase{Want: append(EventList(nil), events...)},
+	}
+}
+
+func (a *wantAction) CanRun() bool {
+	return a.ActionBase.CanRun() && a.Action.CanRun()
+}
+
+func (a *wantAction) Signal(ev Event) bool {
+	// Both must be signaled as the event may be wanted by both.
+	signaled := a.ActionBase.Signal(ev)
+	return a.Action.Signal(ev) || signaled
+}
+
+func (a *wantAction) PendingEvents() EventList {
+	return append(append(EventList(nil), a.ActionBase.PendingEvents()...), a.Action.PendingEvents()...)
+}
@@ -19,6 +19,7 @@ package rgraph
 import (
 	"bytes"
 	"fmt"
+	"sort"
 
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode"
@@ -26,7 +27,10 @@ import (
 
 func newGraph() *Graph {
 	return &Graph{
-		nodes: map[cloud.ResourceMapKey]rnode.Node{},
+		nodes:     map[cloud.ResourceMapKey]rnode.Node{},
+		inRefs:    map[cloud.ResourceMapKey][]rnode.ResourceRef{},
+		byName:    map[string][]cloud.ResourceMapKey{},
+		byService: map[string][]cloud.ResourceMapKey{},
 	}
 }
 
@@ -34,6 +38,14 @@ func newGraph() *Graph {
 // the Builder to manipulate the set of resource nodes.
 type Graph struct {
 	nodes map[cloud.ResourceMapKey]rnode.Node
+	// inRefs is a reverse index of the OutRefs() of the nodes, keyed by
+	// ResourceRef.To.
+	inRefs map[cloud.ResourceMapKey][]rnode.ResourceRef
+	// byName indexes the nodes by ResourceID.Key.Name.
+	byName map[string][]cloud.ResourceMapKey
+	// byService indexes the nodes by the rnode.AnnotationService
+	// annotation.
+	byService map[string][]cloud.ResourceMapKey
 }
 
 // All of the nodes in the Graph.
@@ -51,6 +63,75 @@ func (g *Graph) Get(id *cloud.ResourceID) rnode.Node {
 	return g.nodes[id.MapKey()]
 }
 
+// OutRefs returns the references from the resource named by id to other
+// resources. Returns nil if the resource is not in the Graph.
+func (g *Graph) OutRefs(id *cloud.ResourceID) []rnode.ResourceRef {
+	n := g.Get(id)
+	if n == nil {
+		return nil
+	}
+	return n.OutRefs()
+}
+
+// InRefs returns the references from other resources in the Graph to the
+// resource named by id, sorted by ResourceRef.From. This can be used to find
+// e.g. all of the UrlMaps that reference a BackendService. The resource named
+// by id does not need to be in the Graph.
+func (g *Graph) InRefs(id *cloud.ResourceID) []rnode.ResourceRef {
+	refs := g.inRefs[id.MapKey()]
+	if len(refs) == 0 {
+		return nil
+	}
+	ret := append([]rnode.ResourceRef{}, refs...)
+	sort.Slice(ret, func(i, j int) bool {
+		if a, b := ret[i].From.String(), ret[j].From.String(); a != b {
+			return a < b
+		}
+		return ret[i].Path.String() < ret[j].Path.String()
+	})
+	return ret
+}
+
+// ByName returns the nodes with the given resource name (ResourceID.Key.Name),
+// across all resource types and locations, sorted by ResourceID.
+func (g *Graph) ByName(name string) []rnode.Node {
+	return g.lookup(g.byName[name])
+}
+
+// ByService returns the nodes annotated with the given K8s Service
+// ("namespace/name", see rnode.AnnotationService), sorted by ResourceID.
+func (g *Graph) ByService(service string) []rnode.Node {
+	return g.lookup(g.byService[service])
+}
+
+// Find returns the nodes for which pred returns true, sorted by ResourceID.
+func (g *Graph) Find(pred func(rnode.Node) bool) []rnode.Node {
+	var ret []rnode.Node
+	for _, n := range g.nodes {
+		if pred(n) {
+			ret = append(ret, n)
+		}
+	}
+	sortNodes(ret)
+	return ret
+}
+
+func (g *Graph) lookup(keys []cloud.ResourceMapKey) []rnode.Node {
+	if len(keys) == 0 {
+		return nil
+	}
+	ret := make([]rnode.Node, 0, len(keys))
+	for _, k := range keys {
+		ret = append(ret, g.nodes[k])
+	}
+	sortNodes(ret)
+	return ret
+}
+
+func sortNodes(nodes []rnode.Node) {
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].ID().String() < nodes[j].ID().String() })
+}
+
 // NewBuilderWithEmptyNodes creates a graph Builder with the same set of nodes
 // but with no resource values. This is used to create a Builder that can be
 // sync'ed with the cloud.
@@ -67,7 +148,18 @@ func (g *Graph) AddTombstone(n rnode.Node) error {
 	if n.State() != rnode.NodeDoesNotExist {
 		return fmt.Errorf("graph: invalid tombstone (want state %s, but got %s)", rnode.NodeDoesNotExist, n.State())
 	}
-	g.nodes[n.ID().MapKey()] = n
+	g.add(n)
+	return nil
+}
+
+// Replace the node with the same ID in the Graph with n. This is used by the
+// planner to substitute a modified "want" node. The node must exist in the
+// Graph.
+func (g *Graph) Replace(n rnode.Node) error {
+	if g.Get(n.ID()) == nil {
+		return fmt.Errorf("graph: Replace: node %v not in graph", n.ID())
+	}
+	g.add(n)
 	return nil
 }
 
@@ -75,7 +167,54 @@ func (g *Graph) AddTombstone(n rnode.Node) error {
 // should not be used outside of internal implementation of the graph
 // package.
 func (g *Graph) add(n rnode.Node) {
-	g.nodes[n.ID().MapKey()] = n
+	key := n.ID().MapKey()
+	if old, ok := g.nodes[key]; ok {
+		g.removeInRefs(old)
+		removeIndex(g.byService, old.Annotations()[rnode.AnnotationService], key)
+	} else {
+		g.byName[n.ID().Key.Name] = append(g.byName[n.ID().Key.Name], key)
+	}
+	g.nodes[key] = n
+	for _, ref := range n.OutRefs() {
+		to := ref.To.MapKey()
+		g.inRefs[to] = append(g.inRefs[to], ref)
+	}
+	if svc := n.Annotations()[rnode.AnnotationService]; svc != "" {
+		g.byService[svc] = append(g.byService[svc], key)
+	}
+}
+
+// removeIndex removes key from index[v].
+func removeIndex(index map[string][]cloud.ResourceMapKey, v string, key cloud.ResourceMapKey) {
+	var keys []cloud.ResourceMapKey
+	for _, k := range index[v] {
+		if k != key {
+			keys = append(keys, k)
+		}
+	}
+	if len(keys) == 0 {
+		delete(index, v)
+	} else {
+		index[v] = keys
+	}
+}
+
+// removeInRefs removes the OutRefs of n from the inRefs index.
+func (g *Graph) removeInRefs(n rnode.Node) {
+	for _, ref := range n.OutRefs() {
+		key := ref.To.MapKey()
+		var refs []rnode.ResourceRef
+		for _, r := range g.inRefs[key] {
+			if !r.From.Equal(n.ID()) {
+				refs = append(refs, r)
+			}
+		}
+		if len(refs) == 0 {
+			delete(g.inRefs, key)
+		} else {
+			g.inRefs[key] = refs
+		}
+	}
 }
 
 // ExplainPlan returns a human-readable string describing the plan attached to
@@ -94,3 +233,15 @@ func (g *Graph) ExplainPlan() string {
 	}
 	return buf.String()
 }
+
+// Subgraph returns a Graph with the nodes of g for which keep returns true.
+// The returned Graph shares the nodes with g.
+func (g *Graph) Subgraph(keep func(n rnode.Node) bool) *Graph {
+	ret := newGraph()
+	for _, n := range g.nodes {
+		if keep(n) {
+			ret.add(n)
+		}
+	}
+	return ret
+}
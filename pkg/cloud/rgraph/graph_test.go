@@ -19,14 +19,17 @@ package rgraph
 import (
 	"errors"
 	"fmt"
+	"sort"
 	"strings"
 	"testing"
 
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/backendservice"
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/fake"
 	"github.com/google/go-cmp/cmp"
+	"google.golang.org/api/compute/v1"
 )
 
 type topology struct {
@@ -338,3 +341,256 @@ func TestGraphAddTombstone(t *testing.T) {
 		t.Fatalf("g.AddTombstone() = nil, want error")
 	}
 }
+
+func TestGraphInRefs(t *testing.T) {
+	ids := make([]*cloud.ResourceID, 4)
+	for i := 0; i < len(ids); i++ {
+		ids[i] = &cloud.ResourceID{Resource: "fake", Key: meta.GlobalKey(fmt.Sprintf("r%d", i))}
+	}
+
+	// r2 -> r0; r1 -> r0; r1 -> r2
+	b := NewBuilder()
+	b1 := fake.NewBuilder(ids[1])
+	b1.FakeOutRefs = []rnode.ResourceRef{{From: ids[1], To: ids[0]}, {From: ids[1], To: ids[2]}}
+	b2 := fake.NewBuilder(ids[2])
+	b2.FakeOutRefs = []rnode.ResourceRef{{From: ids[2], To: ids[0]}}
+	b.Add(fake.NewBuilder(ids[0]))
+	b.Add(b1)
+	b.Add(b2)
+	for _, id := range ids[:3] {
+		b.Get(id).SetOwnership(rnode.OwnershipManaged)
+	}
+	g := b.MustBuild()
+
+	from := func(refs []rnode.ResourceRef) []string {
+		var ret []string
+		for _, ref := range refs {
+			ret = append(ret, ref.From.Key.Name)
+		}
+		return ret
+	}
+	for _, tc := range []struct {
+		id   *cloud.ResourceID
+		want []string
+	}{
+		{id: ids[0], want: []string{"r1", "r2"}},
+		{id: ids[1]},
+		{id: ids[2], want: []string{"r1"}},
+		{id: ids[3]},
+	} {
+		if diff := cmp.Diff(from(g.InRefs(tc.id)), tc.want); diff != "" {
+			t.Errorf("InRefs(%v): -got,+want: %s", tc.id, diff)
+		}
+	}
+	if got := len(g.OutRefs(ids[1])); got != 2 {
+		t.Errorf("len(OutRefs(r1)) = %d, want 2", got)
+	}
+	if got := g.OutRefs(ids[3]); got != nil {
+		t.Errorf("OutRefs(r3) = %v, want nil", got)
+	}
+}
+
+func TestGraphSubgraph(t *testing.T) {
+	id := func(name string) *cloud.ResourceID {
+		return &cloud.ResourceID{Resource: "fake", Key: meta.GlobalKey(name)}
+	}
+	for _, tc := range []struct {
+		name      string
+		graph     string
+		keep      []string
+		want      []string
+		wantInRef map[string][]string
+	}{
+		{name: "empty"},
+		{name: "none", graph: "A -> B"},
+		{
+			name:      "all",
+			graph:     "A -> B; C -> B",
+			keep:      []string{"A", "B", "C"},
+			want:      []string{"A", "B", "C"},
+			wantInRef: map[string][]string{"B": {"A", "C"}},
+		},
+		{
+			name:      "refs from removed nodes",
+			graph:     "A -> B; C -> B",
+			keep:      []string{"A", "B"},
+			want:      []string{"A", "B"},
+			wantInRef: map[string][]string{"B": {"A"}},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			top := parseTopology(tc.graph)
+			b := NewBuilder()
+			for n := range top.nodes {
+				nb := fake.NewBuilder(id(n))
+				for to := range top.edges[n] {
+					nb.FakeOutRefs = append(nb.FakeOutRefs, rnode.ResourceRef{From: id(n), To: id(to)})
+				}
+				nb.SetOwnership(rnode.OwnershipManaged)
+				b.Add(nb)
+			}
+			g := b.MustBuild()
+
+			keep := map[string]bool{}
+			for _, n := range tc.keep {
+				keep[n] = true
+			}
+			sub := g.Subgraph(func(n rnode.Node) bool { return keep[n.ID().Key.Name] })
+
+			var got []string
+			gotInRefs := map[string][]string{}
+			for _, n := range sub.All() {
+				if n != g.Get(n.ID()) {
+					t.Errorf("Subgraph() node %v is not shared with the Graph", n.ID())
+				}
+				got = append(got, n.ID().Key.Name)
+				for _, ref := range sub.InRefs(n.ID()) {
+					gotInRefs[n.ID().Key.Name] = append(gotInRefs[n.ID().Key.Name], ref.From.Key.Name)
+				}
+			}
+			sort.Strings(got)
+			for _, refs := range gotInRefs {
+				sort.Strings(refs)
+			}
+			if diff := cmp.Diff(got, tc.want); diff != "" {
+				t.Errorf("Subgraph(): -got,+want: %s", diff)
+			}
+			if len(tc.wantInRef) == 0 {
+				tc.wantInRef = map[string][]string{}
+			}
+			if diff := cmp.Diff(gotInRefs, tc.wantInRef); diff != "" {
+				t.Errorf("Subgraph().InRefs(): -got,+want: %s", diff)
+			}
+		})
+	}
+}
+
+func TestGraphIndexes(t *testing.T) {
+	global := func(name string) *cloud.ResourceID {
+		return &cloud.ResourceID{Resource: "fake", Key: meta.GlobalKey(name)}
+	}
+	regional := &cloud.ResourceID{Resource: "fake", Key: meta.RegionalKey("a", "us-central1")}
+
+	b := NewBuilder()
+	for _, tc := range []struct {
+		id  *cloud.ResourceID
+		svc string
+	}{
+		{id: global("a"), svc: "ns/x"},
+		{id: regional, svc: "ns/y"},
+		{id: global("b"), svc: "ns/x"},
+		{id: global("c")},
+	} {
+		nb := fake.NewBuilder(tc.id)
+		nb.SetOwnership(rnode.OwnershipManaged)
+		if tc.svc != "" {
+			nb.SetAnnotations(map[string]string{rnode.AnnotationService: tc.svc})
+		}
+		b.Add(nb)
+	}
+	g := b.MustBuild()
+
+	ids := func(nodes []rnode.Node) []string {
+		var ret []string
+		for _, n := range nodes {
+			ret = append(ret, n.ID().String())
+		}
+		return ret
+	}
+	checkService := func(svc string, want ...*cloud.ResourceID) {
+		t.Helper()
+		if diff := cmp.Diff(ids(g.ByService(svc)), ids(nodesFor(g, want))); diff != "" {
+			t.Errorf("ByService(%q): -got,+want: %s", svc, diff)
+		}
+	}
+
+	if diff := cmp.Diff(ids(g.ByName("a")), ids(nodesFor(g, []*cloud.ResourceID{global("a"), regional}))); diff != "" {
+		t.Errorf("ByName(a): -got,+want: %s", diff)
+	}
+	if got := g.ByName("z"); got != nil {
+		t.Errorf("ByName(z) = %v, want nil", got)
+	}
+	checkService("ns/x", global("a"), global("b"))
+	checkService("ns/y", regional)
+	checkService("ns/z")
+
+	got := g.Find(func(n rnode.Node) bool { return n.Annotations()[rnode.AnnotationService] == "" })
+	if diff := cmp.Diff(ids(got), []string{global("c").String()}); diff != "" {
+		t.Errorf("Find(): -got,+want: %s", diff)
+	}
+
+	// Replacing a node updates the indexes.
+	nb := fake.NewBuilder(global("b"))
+	nb.SetOwnership(rnode.OwnershipManaged)
+	nb.SetAnnotations(map[string]string{rnode.AnnotationService: "ns/y"})
+	n, err := nb.Build()
+	if err != nil {
+		t.Fatalf("Build() = %v, want nil", err)
+	}
+	if err := g.Replace(n); err != nil {
+		t.Fatalf("Replace() = %v, want nil", err)
+	}
+	checkService("ns/x", global("a"))
+	checkService("ns/y", global("b"), regional)
+	if got := len(g.ByName("b")); got != 1 {
+		t.Errorf("len(ByName(b)) = %d, want 1", got)
+	}
+}
+
+// nodesFor returns the nodes for ids in sorted order.
+func nodesFor(g *Graph, ids []*cloud.ResourceID) []rnode.Node {
+	var ret []rnode.Node
+	for _, id := range ids {
+		ret = append(ret, g.Get(id))
+	}
+	sortNodes(ret)
+	return ret
+}
+
+func TestBuilderClone(t *testing.T) {
+	mr := backendservice.NewMutableBackendService("proj", meta.GlobalKey("bs"))
+	mr.Access(func(x *compute.BackendService) { x.Description = "orig" })
+	r, err := mr.Freeze()
+	if err != nil {
+		t.Fatalf("Freeze() = %v, want nil", err)
+	}
+	nb := backendservice.NewBuilderWithResource(r)
+	nb.SetOwnership(rnode.OwnershipManaged)
+	nb.SetState(rnode.NodeExists)
+	nb.SetAnnotations(map[string]string{"a": "orig"})
+	b := NewBuilder()
+	b.Add(nb)
+
+	description := func(res rnode.UntypedResource) string {
+		obj, _ := res.(backendservice.BackendService).ToGA()
+		return obj.Description
+	}
+
+	c := b.Clone()
+	cnb := c.Get(nb.ID())
+	if cnb == nil || cnb == nb {
+		t.Fatalf("Clone().Get() = %v, want a copy of %v", cnb, nb)
+	}
+	obj, _ := cnb.Resource().(backendservice.BackendService).ToGA()
+	obj.Description = "changed"
+	cnb.SetAnnotations(map[string]string{"a": "changed"})
+	c.Remove(nb.ID())
+
+	if b.Get(nb.ID()) == nil {
+		t.Errorf("b.Get() = nil after removing from clone")
+	}
+	if got := description(nb.Resource()); got != "orig" {
+		t.Errorf("Description = %q after changing clone, want orig", got)
+	}
+	if got := nb.Annotations()["a"]; got != "orig" {
+		t.Errorf("Annotations()[a] = %q after changing clone, want orig", got)
+	}
+
+	// The Graph node is a snapshot of the resource.
+	g := b.MustBuild()
+	obj, _ = nb.Resource().(backendservice.BackendService).ToGA()
+	obj.Description = "changed"
+	if got := description(g.Get(nb.ID()).Resource()); got != "orig" {
+		t.Errorf("node Description = %q after changing builder, want orig", got)
+	}
+}
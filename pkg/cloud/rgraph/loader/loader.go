@@ -0,0 +1,294 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package loader constructs a rgraph.Builder from a declarative YAML or JSON
+// document:
+//
+//	project: my-project
+//	nodes:
+//	- kind: HealthCheck
+//	  name: hc
+//	  spec:
+//	    type: TCP
+//	    checkIntervalSec: 5
+//	    timeoutSec: 5
+//	    healthyThreshold: 2
+//	    unhealthyThreshold: 2
+//	    tcpHealthCheck: {port: 80}
+//	- kind: BackendService
+//	  name: bs
+//	  region: us-central1
+//	  spec:
+//	    loadBalancingScheme: INTERNAL
+//	    protocol: TCP
+//	    compressionMode: DISABLED
+//	    sessionAffinity: NONE
+//	    timeoutSec: 30
+//	    healthChecks:
+//	    - https://www.googleapis.com/compute/v1/projects/my-project/global/healthChecks/hc
+//
+// The spec contains the fields of the resource in the API JSON format for the
+// given version (default "ga"). Additional kinds can be added with Register().
+//
+// A document may declare parameters that are referenced as ${name} and
+// substituted when the graph is built. See LoadTemplate().
+package loader
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode"
+	"gopkg.in/yaml.v2"
+)
+
+const errPrefix = "Loader"
+
+// Document is the declarative definition of a graph.
+type Document struct {
+	// Parameters that can be referenced in the Document as ${name}. See
+	// LoadTemplate().
+	Parameters []Parameter `yaml:"parameters,omitempty"`
+	// Project is the default project for the Nodes.
+	Project string `yaml:"project"`
+	// Nodes in the graph.
+	Nodes []Node `yaml:"nodes"`
+}
+
+// Node in the Document.
+type Node struct {
+	// Kind of the resource (e.g. "BackendService"). See Register().
+	Kind string `yaml:"kind"`
+	// Name of the resource.
+	Name string `yaml:"name"`
+	// Project overrides the Document Project if set.
+	Project string `yaml:"project,omitempty"`
+	// Region is set for regional resources.
+	Region string `yaml:"region,omitempty"`
+	// Zone is set for zonal resources.
+	Zone string `yaml:"zone,omitempty"`
+	// Ownership is one of "managed" (default) or "external".
+	Ownership string `yaml:"ownership,omitempty"`
+	// State is one of "exists" (default) or "doesNotExist".
+	State string `yaml:"state,omitempty"`
+	// DeletionProtected prevents the planner from deleting the resource.
+	DeletionProtected bool `yaml:"deletionProtected,omitempty"`
+	// Annotations are attached to the Node and its Actions.
+	Annotations map[string]string `yaml:"annotations,omitempty"`
+	// Version of the API used for Spec. One of "ga" (default), "alpha",
+	// "beta".
+	Version string `yaml:"version,omitempty"`
+	// Spec are the fields of the resource in API JSON format.
+	Spec map[string]any `yaml:"spec,omitempty"`
+}
+
+// Parse a YAML or JSON document. JSON is a subset of YAML so both formats are
+// handled.
+func Parse(data []byte) (*Document, error) {
+	var doc Document
+	if err := yaml.UnmarshalStrict(data, &doc); err != nil {
+		return nil, fmt.Errorf("%s: %w", errPrefix, err)
+	}
+	return &doc, nil
+}
+
+// Load the document in data and return the Builder for the graph.
+func Load(data []byte) (*rgraph.Builder, error) {
+	doc, err := Parse(data)
+	if err != nil {
+		return nil, err
+	}
+	return doc.Builder()
+}
+
+// LoadFile loads the document in the given file.
+func LoadFile(path string) (*rgraph.Builder, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", errPrefix, err)
+	}
+	b, err := Load(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return b, nil
+}
+
+// Builder returns a rgraph.Builder with the Nodes in the Document. If the
+// Document declares Parameters, the default values are substituted.
+func (d *Document) Builder() (*rgraph.Builder, error) {
+	if len(d.Parameters) > 0 {
+		inst, err := d.Instantiate(nil)
+		if err != nil {
+			return nil, err
+		}
+		d = inst
+	}
+	b := rgraph.NewBuilder()
+	for i := range d.Nodes {
+		nb, err := d.Nodes[i].builder(d)
+		if err != nil {
+			return nil, fmt.Errorf("%s: node %d (%s %q): %w", errPrefix, i, d.Nodes[i].Kind, d.Nodes[i].Name, err)
+		}
+		if b.Get(nb.ID()) != nil {
+			return nil, fmt.Errorf("%s: duplicate node %v", errPrefix, nb.ID())
+		}
+		b.Add(nb)
+	}
+	return b, nil
+}
+
+func (n *Node) id(d *Document) (*cloud.ResourceID, *Kind, error) {
+	k := getKind(n.Kind)
+	if k == nil {
+		return nil, nil, fmt.Errorf("unknown kind %q", n.Kind)
+	}
+	if n.Name == "" {
+		return nil, nil, fmt.Errorf("name must be set")
+	}
+	project := n.Project
+	if project == "" {
+		project = d.Project
+	}
+	if project == "" {
+		return nil, nil, fmt.Errorf("project must be set")
+	}
+
+	var key *meta.Key
+	switch {
+	case n.Region != "" && n.Zone != "":
+		return nil, nil, fmt.Errorf("only one of region and zone can be set")
+	case n.Region != "":
+		key = meta.RegionalKey(n.Name, n.Region)
+	case n.Zone != "":
+		key = meta.ZonalKey(n.Name, n.Zone)
+	default:
+		key = meta.GlobalKey(n.Name)
+	}
+
+	return k.ID(project, key), k, nil
+}
+
+func (n *Node) builder(d *Document) (rnode.Builder, error) {
+	id, k, err := n.id(d)
+	if err != nil {
+		return nil, err
+	}
+	b, err := k.NewBuilder(id)
+	if err != nil {
+		return nil, err
+	}
+
+	switch n.Ownership {
+	case "", "managed":
+		b.SetOwnership(rnode.OwnershipManaged)
+	case "external":
+		b.SetOwnership(rnode.OwnershipExternal)
+	default:
+		return nil, fmt.Errorf("invalid ownership %q", n.Ownership)
+	}
+	b.SetDeletionProtected(n.DeletionProtected)
+	b.SetAnnotations(n.Annotations)
+
+	switch n.State {
+	case "", "exists":
+		b.SetState(rnode.NodeExists)
+	case "doesNotExist":
+		b.SetState(rnode.NodeDoesNotExist)
+		if n.Spec != nil {
+			return nil, fmt.Errorf("spec cannot be set when state is %q", n.State)
+		}
+		return b, nil
+	default:
+		return nil, fmt.Errorf("invalid state %q", n.State)
+	}
+
+	ver := meta.VersionGA
+	if n.Version != "" {
+		ver = meta.Version(n.Version)
+	}
+	spec, err := specJSON(n.Spec)
+	if err != nil {
+		return nil, err
+	}
+	r, err := k.Resource(id, ver, spec)
+	if err != nil {
+		return nil, err
+	}
+	if err := b.SetResource(r); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// specJSON converts the spec to JSON. YAML decodes maps as
+// map[any]any which needs to be converted to map[string]any for the JSON
+// encoder.
+func specJSON(spec map[string]any) ([]byte, error) {
+	if spec == nil {
+		return nil, nil
+	}
+	v, err := jsonCompatible(spec)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(v)
+}
+
+func jsonCompatible(v any) (any, error) {
+	switch v := v.(type) {
+	case map[any]any:
+		ret := map[string]any{}
+		for k, val := range v {
+			ks, ok := k.(string)
+			if !ok {
+				return nil, fmt.Errorf("invalid key %v (%T) in spec, must be a string", k, k)
+			}
+			cv, err := jsonCompatible(val)
+			if err != nil {
+				return nil, err
+			}
+			ret[ks] = cv
+		}
+		return ret, nil
+	case map[string]any:
+		ret := map[string]any{}
+		for k, val := range v {
+			cv, err := jsonCompatible(val)
+			if err != nil {
+				return nil, err
+			}
+			ret[k] = cv
+		}
+		return ret, nil
+	case []any:
+		ret := make([]any, len(v))
+		for i, val := range v {
+			cv, err := jsonCompatible(val)
+			if err != nil {
+				return nil, err
+			}
+			ret[i] = cv
+		}
+		return ret, nil
+	}
+	return v, nil
+}
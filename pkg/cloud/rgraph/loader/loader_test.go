@@ -0,0 +1,226 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package loader
+
+import (
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/backendservice"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/healthcheck"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/tcproute"
+)
+
+const yamlDoc = `
+project: proj
+nodes:
+- kind: HealthCheck
+  name: hc
+  ownership: external
+  deletionProtected: true
+  spec:
+    type: TCP
+    checkIntervalSec: 5
+    timeoutSec: 5
+    healthyThreshold: 2
+    unhealthyThreshold: 2
+    tcpHealthCheck: {port: 80}
+- kind: BackendService
+  name: bs
+  region: us-central1
+  annotations: {service: ns/svc}
+  spec:
+    loadBalancingScheme: INTERNAL
+    protocol: TCP
+    compressionMode: DISABLED
+    sessionAffinity: NONE
+    timeoutSec: 30
+    healthChecks:
+    - https://www.googleapis.com/compute/v1/projects/proj/global/healthChecks/hc
+- kind: TcpRoute
+  name: route
+  state: doesNotExist
+`
+
+const jsonDoc = `{
+  "project": "proj",
+  "nodes": [
+    {
+      "kind": "HealthCheck",
+      "name": "hc",
+      "ownership": "external",
+      "deletionProtected": true,
+      "spec": {
+        "type": "TCP",
+        "checkIntervalSec": 5,
+        "timeoutSec": 5,
+        "healthyThreshold": 2,
+        "unhealthyThreshold": 2,
+        "tcpHealthCheck": {"port": 80}
+      }
+    },
+    {
+      "kind": "BackendService",
+      "name": "bs",
+      "region": "us-central1",
+      "annotations": {"service": "ns/svc"},
+      "spec": {
+        "loadBalancingScheme": "INTERNAL",
+        "protocol": "TCP",
+        "compressionMode": "DISABLED",
+        "sessionAffinity": "NONE",
+        "timeoutSec": 30,
+        "healthChecks": ["https://www.googleapis.com/compute/v1/projects/proj/global/healthChecks/hc"]
+      }
+    },
+    {"kind": "TcpRoute", "name": "route", "state": "doesNotExist"}
+  ]
+}`
+
+func TestLoad(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		data string
+	}{
+		{name: "yaml", data: yamlDoc},
+		{name: "json", data: jsonDoc},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			b, err := Load([]byte(tc.data))
+			if err != nil {
+				t.Fatalf("Load() = %v, want nil", err)
+			}
+			g, err := b.Build()
+			if err != nil {
+				t.Fatalf("Build() = %v, want nil", err)
+			}
+
+			hcID := healthcheck.ID("proj", meta.GlobalKey("hc"))
+			bsID := backendservice.ID("proj", meta.RegionalKey("bs", "us-central1"))
+			routeID := tcproute.ID("proj", meta.GlobalKey("route"))
+
+			hc := g.Get(hcID)
+			if hc == nil {
+				t.Fatalf("g.Get(%v) = nil", hcID)
+			}
+			if hc.Ownership() != rnode.OwnershipExternal {
+				t.Errorf("hc.Ownership() = %v, want %v", hc.Ownership(), rnode.OwnershipExternal)
+			}
+			if !hc.DeletionProtected() {
+				t.Errorf("hc.DeletionProtected() = false, want true")
+			}
+
+			bs := g.Get(bsID)
+			if bs == nil {
+				t.Fatalf("g.Get(%v) = nil", bsID)
+			}
+			if bs.Ownership() != rnode.OwnershipManaged || bs.State() != rnode.NodeExists {
+				t.Errorf("bs = %v, %v; want %v, %v", bs.Ownership(), bs.State(), rnode.OwnershipManaged, rnode.NodeExists)
+			}
+			if v := bs.Annotations()["service"]; v != "ns/svc" {
+				t.Errorf("bs.Annotations()[service] = %q, want %q", v, "ns/svc")
+			}
+			bsRes, err := bs.Resource().(backendservice.BackendService).ToGA()
+			if err != nil {
+				t.Fatalf("ToGA() = %v, want nil", err)
+			}
+			if bsRes.Name != "bs" || bsRes.LoadBalancingScheme != "INTERNAL" {
+				t.Errorf("bs resource = %+v, want Name=bs, LoadBalancingScheme=INTERNAL", bsRes)
+			}
+			if len(bs.OutRefs()) != 1 || !bs.OutRefs()[0].To.Equal(hcID) {
+				t.Errorf("bs.OutRefs() = %v, want ref to %v", bs.OutRefs(), hcID)
+			}
+
+			route := g.Get(routeID)
+			if route == nil {
+				t.Fatalf("g.Get(%v) = nil", routeID)
+			}
+			if route.State() != rnode.NodeDoesNotExist {
+				t.Errorf("route.State() = %v, want %v", route.State(), rnode.NodeDoesNotExist)
+			}
+		})
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		data string
+	}{
+		{
+			name: "unknown kind",
+			data: "project: p\nnodes:\n- {kind: Foo, name: x}",
+		},
+		{
+			name: "no project",
+			data: "nodes:\n- {kind: HealthCheck, name: x}",
+		},
+		{
+			name: "no name",
+			data: "project: p\nnodes:\n- {kind: HealthCheck}",
+		},
+		{
+			name: "region and zone",
+			data: "project: p\nnodes:\n- {kind: HealthCheck, name: x, region: r, zone: z}",
+		},
+		{
+			name: "invalid ownership",
+			data: "project: p\nnodes:\n- {kind: HealthCheck, name: x, ownership: foo}",
+		},
+		{
+			name: "invalid state",
+			data: "project: p\nnodes:\n- {kind: HealthCheck, name: x, state: foo}",
+		},
+		{
+			name: "invalid version",
+			data: "project: p\nnodes:\n- {kind: HealthCheck, name: x, version: v2, spec: {type: TCP}}",
+		},
+		{
+			name: "unknown spec field",
+			data: "project: p\nnodes:\n- {kind: HealthCheck, name: x, spec: {notAField: 1}}",
+		},
+		{
+			name: "spec fails validation",
+			data: "project: p\nnodes:\n- {kind: BackendService, name: x, spec: {description: d}}",
+		},
+		{
+			name: "unknown document field",
+			data: "project: p\nfoo: bar\nnodes: []",
+		},
+		{
+			name: "spec with doesNotExist",
+			data: "project: p\nnodes:\n- {kind: HealthCheck, name: x, state: doesNotExist, spec: {type: TCP}}",
+		},
+		{
+			name: "duplicate node",
+			data: "project: p\nnodes:\n- {kind: HealthCheck, name: x}\n- {kind: HealthCheck, name: x}",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := Load([]byte(tc.data)); err == nil {
+				t.Errorf("Load() = nil, want error")
+			}
+		})
+	}
+}
+
+func TestRegisterDuplicate(t *testing.T) {
+	if err := Register("HealthCheck", &Kind{}); err == nil {
+		t.Errorf("Register(HealthCheck) = nil, want error")
+	}
+}
@@ -0,0 +1,188 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package loader
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/api"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/address"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/all"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/backendservice"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/forwardingrule"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/healthcheck"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/networkendpointgroup"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/targetgrpcproxy"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/targethttpproxy"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/tcproute"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/urlmap"
+)
+
+// Kind describes how to construct a node of a given kind from a
+// document.
+type Kind struct {
+	// ID returns the ResourceID for the resource.
+	ID func(project string, key *meta.Key) *cloud.ResourceID
+	// NewBuilder returns an empty Builder for the id.
+	NewBuilder func(id *cloud.ResourceID) (rnode.Builder, error)
+	// Resource returns the resource with the given JSON spec fields for
+	// the version.
+	Resource func(id *cloud.ResourceID, ver meta.Version, spec []byte) (rnode.UntypedResource, error)
+	// Spec returns the JSON spec for the resource. This is the inverse of
+	// Resource. If nil, Nodes of this kind cannot be saved with FromGraph().
+	Spec func(r rnode.UntypedResource) ([]byte, error)
+}
+
+var (
+	registryLock sync.Mutex
+	registry     = map[string]*Kind{}
+)
+
+// Register a kind with the loader. The name is the value used in the "kind"
+// field of the document (e.g. "BackendService"). It is an error to register
+// the same kind twice.
+func Register(name string, k *Kind) error {
+	registryLock.Lock()
+	defer registryLock.Unlock()
+
+	if _, ok := registry[name]; ok {
+		return fmt.Errorf("loader: kind %q already registered", name)
+	}
+	registry[name] = k
+	return nil
+}
+
+// kindByID returns the name and Kind for the resource id. Returns nil if
+// no Kind matches.
+func kindByID(id *cloud.ResourceID) (string, *Kind) {
+	registryLock.Lock()
+	defer registryLock.Unlock()
+
+	var names []string
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		k := registry[name]
+		if k.ID(id.ProjectID, id.Key).Equal(id) {
+			return name, k
+		}
+	}
+	return "", nil
+}
+
+func getKind(name string) *Kind {
+	registryLock.Lock()
+	defer registryLock.Unlock()
+
+	return registry[name]
+}
+
+// NewKind returns a Kind for a resource type that uses
+// api.MutableResource. The Builder is created by all.NewBuilderByID().
+func NewKind[GA any, Alpha any, Beta any](
+	id func(project string, key *meta.Key) *cloud.ResourceID,
+	newMutable func(project string, key *meta.Key) api.MutableResource[GA, Alpha, Beta],
+) *Kind {
+	return &Kind{
+		ID:         id,
+		NewBuilder: all.NewBuilderByID,
+		Resource: func(id *cloud.ResourceID, ver meta.Version, spec []byte) (rnode.UntypedResource, error) {
+			mr := newMutable(id.ProjectID, id.Key)
+			if len(spec) > 0 {
+				// decodeErr is the error from decoding the spec, err is
+				// from accessing the resource.
+				var decodeErr, err error
+				switch ver {
+				case meta.VersionGA:
+					err = mr.Access(func(x *GA) { decodeErr = decodeSpec(spec, x) })
+				case meta.VersionAlpha:
+					err = mr.AccessAlpha(func(x *Alpha) { decodeErr = decodeSpec(spec, x) })
+				case meta.VersionBeta:
+					err = mr.AccessBeta(func(x *Beta) { decodeErr = decodeSpec(spec, x) })
+				default:
+					return nil, fmt.Errorf("invalid version %q", ver)
+				}
+				if err != nil {
+					return nil, err
+				}
+				if decodeErr != nil {
+					return nil, decodeErr
+				}
+			}
+			return mr.Freeze()
+		},
+		Spec: func(u rnode.UntypedResource) ([]byte, error) {
+			r, ok := u.(api.Resource[GA, Alpha, Beta])
+			if !ok {
+				return nil, fmt.Errorf("invalid resource type %T", u)
+			}
+			var (
+				x   any
+				err error
+			)
+			switch r.Version() {
+			case meta.VersionGA:
+				x, err = r.ToGA()
+			case meta.VersionAlpha:
+				x, err = r.ToAlpha()
+			case meta.VersionBeta:
+				x, err = r.ToBeta()
+			default:
+				return nil, fmt.Errorf("invalid version %q", r.Version())
+			}
+			if err != nil {
+				return nil, err
+			}
+			return json.Marshal(x)
+		},
+	}
+}
+
+// decodeSpec unmarshals the spec into x, rejecting fields that do not exist
+// in the type.
+func decodeSpec(spec []byte, x any) error {
+	d := json.NewDecoder(bytes.NewReader(spec))
+	d.DisallowUnknownFields()
+	return d.Decode(x)
+}
+
+func init() {
+	for name, k := range map[string]*Kind{
+		"Address":              NewKind(address.ID, address.NewMutableAddress),
+		"BackendService":       NewKind(backendservice.ID, backendservice.NewMutableBackendService),
+		"ForwardingRule":       NewKind(forwardingrule.ID, forwardingrule.NewMutableForwardingRule),
+		"HealthCheck":          NewKind(healthcheck.ID, healthcheck.NewMutableHealthCheck),
+		"NetworkEndpointGroup": NewKind(networkendpointgroup.ID, networkendpointgroup.NewMutableNetworkEndpointGroup),
+		"TargetGrpcProxy":      NewKind(targetgrpcproxy.ID, targetgrpcproxy.NewMutableTargetGrpcProxy),
+		"TargetHttpProxy":      NewKind(targethttpproxy.ID, targethttpproxy.NewMutableTargetHttpProxy),
+		"TcpRoute":             NewKind(tcproute.ID, tcproute.NewMutableTcpRoute),
+		"UrlMap":               NewKind(urlmap.ID, urlmap.NewMutableUrlMap),
+	} {
+		if err := Register(name, k); err != nil {
+			panic(err)
+		}
+	}
+}
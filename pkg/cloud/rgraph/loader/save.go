@@ -0,0 +1,118 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package loader
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode"
+	"gopkg.in/yaml.v2"
+)
+
+// FromGraph returns the Document for the Nodes in g. This is the inverse of
+// Document.Builder(); it can be used to save a graph, e.g. the last applied
+// graph. The Nodes are sorted by ID. The Document Project is the project of
+// the first Node.
+func FromGraph(g *rgraph.Graph) (*Document, error) {
+	nodes := g.All()
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].ID().String() < nodes[j].ID().String() })
+
+	doc := &Document{}
+	for _, n := range nodes {
+		if doc.Project == "" {
+			doc.Project = n.ID().ProjectID
+		}
+		dn, err := fromNode(doc, n)
+		if err != nil {
+			return nil, fmt.Errorf("%s: node %v: %w", errPrefix, n.ID(), err)
+		}
+		doc.Nodes = append(doc.Nodes, *dn)
+	}
+	return doc, nil
+}
+
+func fromNode(doc *Document, n rnode.Node) (*Node, error) {
+	kind, k := kindByID(n.ID())
+	if k == nil {
+		return nil, fmt.Errorf("no kind registered")
+	}
+	id := n.ID()
+	ret := &Node{
+		Kind:              kind,
+		Name:              id.Key.Name,
+		DeletionProtected: n.DeletionProtected(),
+		Annotations:       n.Annotations(),
+	}
+	if id.ProjectID != doc.Project {
+		ret.Project = id.ProjectID
+	}
+	switch id.Key.Type() {
+	case meta.Regional:
+		ret.Region = id.Key.Region
+	case meta.Zonal:
+		ret.Zone = id.Key.Zone
+	}
+
+	switch n.Ownership() {
+	case rnode.OwnershipManaged:
+	case rnode.OwnershipExternal:
+		ret.Ownership = "external"
+	default:
+		return nil, fmt.Errorf("invalid ownership %s", n.Ownership())
+	}
+
+	switch n.State() {
+	case rnode.NodeExists:
+	case rnode.NodeDoesNotExist:
+		ret.State = "doesNotExist"
+		return ret, nil
+	default:
+		return nil, fmt.Errorf("invalid state %s", n.State())
+	}
+
+	r := n.Resource()
+	if r == nil {
+		return ret, nil
+	}
+	if k.Spec == nil {
+		return nil, fmt.Errorf("kind %q does not support Spec", kind)
+	}
+	if r.Version() != meta.VersionGA {
+		ret.Version = string(r.Version())
+	}
+	spec, err := k.Spec(r)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(spec, &ret.Spec); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// Marshal the Document to YAML. The result can be loaded with Load().
+func (d *Document) Marshal() ([]byte, error) {
+	data, err := yaml.Marshal(d)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", errPrefix, err)
+	}
+	return data, nil
+}
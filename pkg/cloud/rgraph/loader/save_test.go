@@ -0,0 +1,72 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package loader
+
+import (
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/fake"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestFromGraph(t *testing.T) {
+	b, err := Load([]byte(yamlDoc))
+	if err != nil {
+		t.Fatalf("Load() = %v", err)
+	}
+	g := b.MustBuild()
+
+	doc, err := FromGraph(g)
+	if err != nil {
+		t.Fatalf("FromGraph() = %v", err)
+	}
+	if doc.Project != "proj" || len(doc.Nodes) != 3 {
+		t.Fatalf("FromGraph() = %+v, want project proj with 3 nodes", doc)
+	}
+	data, err := doc.Marshal()
+	if err != nil {
+		t.Fatalf("Marshal() = %v", err)
+	}
+
+	// Round trip.
+	b2, err := Load(data)
+	if err != nil {
+		t.Fatalf("Load(%s) = %v", data, err)
+	}
+	doc2, err := FromGraph(b2.MustBuild())
+	if err != nil {
+		t.Fatalf("FromGraph() = %v", err)
+	}
+	if diff := cmp.Diff(doc, doc2); diff != "" {
+		t.Errorf("round trip: -want,+got: %s", diff)
+	}
+}
+
+func TestFromGraphInvalid(t *testing.T) {
+	// The fake kind is not registered.
+	nb := fake.NewBuilder(fake.ID("proj", meta.GlobalKey("x")))
+	nb.SetOwnership(rnode.OwnershipManaged)
+	nb.SetState(rnode.NodeDoesNotExist)
+	b := rgraph.NewBuilder()
+	b.Add(nb)
+	if _, err := FromGraph(b.MustBuild()); err == nil {
+		t.Errorf("FromGraph() = nil, want error")
+	}
+}
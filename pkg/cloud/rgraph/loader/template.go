@@ -0,0 +1,189 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package loader
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph"
+)
+
+// Parameter declared by a templated Document:
+//
+//	parameters:
+//	- name: region
+//	  default: us-central1
+//	- name: cluster
+//	project: my-project
+//	nodes:
+//	- kind: BackendService
+//	  name: bs-${cluster}
+//	  region: ${region}
+//
+// References are substituted in all string values of the Document, including
+// annotations and spec. "$${" is an escape for a literal "${".
+type Parameter struct {
+	// Name of the parameter.
+	Name string `yaml:"name"`
+	// Default value. A Parameter without a Default must be given a value
+	// when the Document is instantiated.
+	Default *string `yaml:"default,omitempty"`
+}
+
+var (
+	paramNameRE = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_-]*$`)
+	paramRefRE  = regexp.MustCompile(`\$\$\{|\$\{([^}]*)\}`)
+)
+
+// LoadTemplate loads the templated document in data, substituting the
+// parameter values in params. Every parameter in params must be declared by
+// the document.
+func LoadTemplate(data []byte, params map[string]string) (*rgraph.Builder, error) {
+	doc, err := Parse(data)
+	if err != nil {
+		return nil, err
+	}
+	inst, err := doc.Instantiate(params)
+	if err != nil {
+		return nil, err
+	}
+	return inst.Builder()
+}
+
+// Instantiate returns a copy of the Document with parameter references
+// replaced by the values in params, or the parameter defaults. The returned
+// Document has no Parameters. The receiver is not modified.
+func (d *Document) Instantiate(params map[string]string) (*Document, error) {
+	values, err := d.paramValues(params)
+	if err != nil {
+		return nil, err
+	}
+	s := substituter{values: values}
+
+	ret := &Document{Project: s.str(d.Project)}
+	for i := range d.Nodes {
+		n := d.Nodes[i]
+		n.Kind = s.str(n.Kind)
+		n.Name = s.str(n.Name)
+		n.Project = s.str(n.Project)
+		n.Region = s.str(n.Region)
+		n.Zone = s.str(n.Zone)
+		n.Ownership = s.str(n.Ownership)
+		n.State = s.str(n.State)
+		n.Version = s.str(n.Version)
+		if n.Annotations != nil {
+			a := make(map[string]string, len(n.Annotations))
+			for k, v := range n.Annotations {
+				a[s.str(k)] = s.str(v)
+			}
+			n.Annotations = a
+		}
+		if n.Spec != nil {
+			n.Spec = s.value(n.Spec).(map[string]any)
+		}
+		ret.Nodes = append(ret.Nodes, n)
+	}
+	if len(s.undefined) > 0 {
+		var names []string
+		for name := range s.undefined {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return nil, fmt.Errorf("%s: undefined parameters %v", errPrefix, names)
+	}
+	return ret, nil
+}
+
+// paramValues resolves the value of each declared Parameter.
+func (d *Document) paramValues(params map[string]string) (map[string]string, error) {
+	values := map[string]string{}
+	for _, p := range d.Parameters {
+		if !paramNameRE.MatchString(p.Name) {
+			return nil, fmt.Errorf("%s: invalid parameter name %q", errPrefix, p.Name)
+		}
+		if _, ok := values[p.Name]; ok {
+			return nil, fmt.Errorf("%s: duplicate parameter %q", errPrefix, p.Name)
+		}
+		switch v, ok := params[p.Name]; {
+		case ok:
+			values[p.Name] = v
+		case p.Default != nil:
+			values[p.Name] = *p.Default
+		default:
+			return nil, fmt.Errorf("%s: missing value for parameter %q", errPrefix, p.Name)
+		}
+	}
+	for name := range params {
+		if _, ok := values[name]; !ok {
+			return nil, fmt.Errorf("%s: parameter %q is not declared by the document", errPrefix, name)
+		}
+	}
+	return values, nil
+}
+
+type substituter struct {
+	values map[string]string
+	// undefined references found during substitution.
+	undefined map[string]bool
+}
+
+func (s *substituter) str(in string) string {
+	return paramRefRE.ReplaceAllStringFunc(in, func(ref string) string {
+		if ref == "$${" {
+			return "${"
+		}
+		name := ref[2 : len(ref)-1]
+		v, ok := s.values[name]
+		if !ok {
+			if s.undefined == nil {
+				s.undefined = map[string]bool{}
+			}
+			s.undefined[name] = true
+		}
+		return v
+	})
+}
+
+// value substitutes the strings in a value decoded from the document,
+// returning a copy.
+func (s *substituter) value(v any) any {
+	switch v := v.(type) {
+	case string:
+		return s.str(v)
+	case map[string]any:
+		ret := make(map[string]any, len(v))
+		for key, val := range v {
+			ret[s.str(key)] = s.value(val)
+		}
+		return ret
+	case map[any]any:
+		ret := make(map[any]any, len(v))
+		for key, val := range v {
+			ret[s.value(key)] = s.value(val)
+		}
+		return ret
+	case []any:
+		ret := make([]any, len(v))
+		for i, val := range v {
+			ret[i] = s.value(val)
+		}
+		return ret
+	}
+	return v
+}
@@ -0,0 +1,174 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package loader
+
+import (
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/backendservice"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/healthcheck"
+)
+
+const templateDoc = `
+parameters:
+- name: project
+- name: region
+  default: us-central1
+- name: cluster
+project: ${project}
+nodes:
+- kind: HealthCheck
+  name: hc-${cluster}
+  spec:
+    type: TCP
+    description: "cost $${literal}"
+    checkIntervalSec: 5
+    timeoutSec: 5
+    healthyThreshold: 2
+    unhealthyThreshold: 2
+    tcpHealthCheck: {port: 80}
+- kind: BackendService
+  name: bs-${cluster}
+  region: ${region}
+  annotations: {cluster: "${cluster}"}
+  spec:
+    loadBalancingScheme: INTERNAL
+    protocol: TCP
+    compressionMode: DISABLED
+    sessionAffinity: NONE
+    timeoutSec: 30
+    healthChecks:
+    - https://www.googleapis.com/compute/v1/projects/${project}/global/healthChecks/hc-${cluster}
+`
+
+func TestLoadTemplate(t *testing.T) {
+	for _, tc := range []struct {
+		name       string
+		params     map[string]string
+		wantRegion string
+	}{
+		{
+			name:       "default region",
+			params:     map[string]string{"project": "proj", "cluster": "abc123"},
+			wantRegion: "us-central1",
+		},
+		{
+			name:       "override region",
+			params:     map[string]string{"project": "proj", "cluster": "abc123", "region": "europe-west1"},
+			wantRegion: "europe-west1",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			b, err := LoadTemplate([]byte(templateDoc), tc.params)
+			if err != nil {
+				t.Fatalf("LoadTemplate() = %v, want nil", err)
+			}
+			g, err := b.Build()
+			if err != nil {
+				t.Fatalf("Build() = %v, want nil", err)
+			}
+
+			hcID := healthcheck.ID("proj", meta.GlobalKey("hc-abc123"))
+			hc := g.Get(hcID)
+			if hc == nil {
+				t.Fatalf("g.Get(%v) = nil", hcID)
+			}
+			hcRes, err := hc.Resource().(healthcheck.HealthCheck).ToGA()
+			if err != nil {
+				t.Fatalf("ToGA() = %v, want nil", err)
+			}
+			if hcRes.Description != "cost ${literal}" {
+				t.Errorf("hc Description = %q, want %q", hcRes.Description, "cost ${literal}")
+			}
+
+			bsID := backendservice.ID("proj", meta.RegionalKey("bs-abc123", tc.wantRegion))
+			bs := g.Get(bsID)
+			if bs == nil {
+				t.Fatalf("g.Get(%v) = nil", bsID)
+			}
+			if v := bs.Annotations()["cluster"]; v != "abc123" {
+				t.Errorf("bs.Annotations()[cluster] = %q, want %q", v, "abc123")
+			}
+			if len(bs.OutRefs()) != 1 || !bs.OutRefs()[0].To.Equal(hcID) {
+				t.Errorf("bs.OutRefs() = %v, want ref to %v", bs.OutRefs(), hcID)
+			}
+		})
+	}
+}
+
+func TestInstantiateDoesNotModifyTemplate(t *testing.T) {
+	doc, err := Parse([]byte(templateDoc))
+	if err != nil {
+		t.Fatalf("Parse() = %v, want nil", err)
+	}
+	for _, cluster := range []string{"a", "b"} {
+		inst, err := doc.Instantiate(map[string]string{"project": "proj", "cluster": cluster})
+		if err != nil {
+			t.Fatalf("Instantiate() = %v, want nil", err)
+		}
+		if got, want := inst.Nodes[1].Name, "bs-"+cluster; got != want {
+			t.Errorf("inst.Nodes[1].Name = %q, want %q", got, want)
+		}
+		if len(inst.Parameters) != 0 {
+			t.Errorf("inst.Parameters = %v, want none", inst.Parameters)
+		}
+	}
+	if doc.Nodes[1].Name != "bs-${cluster}" || doc.Nodes[1].Annotations["cluster"] != "${cluster}" {
+		t.Errorf("template was modified: %+v", doc.Nodes[1])
+	}
+}
+
+func TestLoadTemplateErrors(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		data   string
+		params map[string]string
+	}{
+		{
+			name:   "missing value",
+			data:   templateDoc,
+			params: map[string]string{"project": "proj"},
+		},
+		{
+			name:   "undeclared parameter",
+			data:   templateDoc,
+			params: map[string]string{"project": "proj", "cluster": "c", "zone": "z"},
+		},
+		{
+			name:   "undefined reference",
+			data:   "parameters:\n- {name: p}\nproject: ${p}\nnodes:\n- {kind: HealthCheck, name: ${q}}",
+			params: map[string]string{"p": "proj"},
+		},
+		{
+			name:   "duplicate parameter",
+			data:   "parameters:\n- {name: p}\n- {name: p}\nproject: ${p}\nnodes: []",
+			params: map[string]string{"p": "proj"},
+		},
+		{
+			name:   "invalid parameter name",
+			data:   "parameters:\n- {name: 'a b'}\nproject: p\nnodes: []",
+			params: map[string]string{"a b": "x"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := LoadTemplate([]byte(tc.data), tc.params); err == nil {
+				t.Errorf("LoadTemplate() = nil, want error")
+			}
+		})
+	}
+}
@@ -0,0 +1,203 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package naming
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/api"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/all"
+)
+
+// Rename of a resource from Old to New.
+type Rename struct {
+	Old *cloud.ResourceID
+	New *cloud.ResourceID
+}
+
+// Validate returns the IDs of the managed nodes in the Builder with names
+// that are not accepted by valid, e.g. names that don't follow the current
+// naming scheme. The IDs are sorted.
+func Validate(b *rgraph.Builder, valid func(name string) bool) []*cloud.ResourceID {
+	var ret []*cloud.ResourceID
+	for _, nb := range b.All() {
+		if nb.Ownership() == rnode.OwnershipManaged && !valid(nb.ID().Key.Name) {
+			ret = append(ret, nb.ID())
+		}
+	}
+	sort.Slice(ret, func(i, j int) bool { return ret[i].String() < ret[j].String() })
+	return ret
+}
+
+// Detect returns the Renames for the managed, existing nodes in the Builder
+// that are named under an old naming scheme. newName returns the name under
+// the new scheme and true if the resource is named under the old scheme. The
+// Renames are sorted by Old.
+func Detect(b *rgraph.Builder, newName func(id *cloud.ResourceID) (string, bool)) []Rename {
+	var ret []Rename
+	for _, nb := range b.All() {
+		if nb.Ownership() != rnode.OwnershipManaged || nb.State() != rnode.NodeExists {
+			continue
+		}
+		name, ok := newName(nb.ID())
+		if !ok || name == nb.ID().Key.Name {
+			continue
+		}
+		newID := *nb.ID()
+		key := *nb.ID().Key
+		key.Name = name
+		newID.Key = &key
+		ret = append(ret, Rename{Old: nb.ID(), New: &newID})
+	}
+	sort.Slice(ret, func(i, j int) bool { return ret[i].Old.String() < ret[j].Old.String() })
+	return ret
+}
+
+// Migrate changes the Builder to rename the resources:
+//
+//   - A node with the New ID is added with a copy of the Old resource.
+//   - References to Old from other nodes in the Builder are changed to
+//     New.
+//   - The Old node is marked as NodeDoesNotExist.
+//
+// Planning the resulting graph creates the new resources, updates the
+// referrers and then deletes the old resources, in that order. Only
+// references from nodes in the Builder are moved; use refcheck to find
+// referrers outside of the graph.
+func Migrate(b *rgraph.Builder, renames []Rename) error {
+	renamed := map[cloud.ResourceMapKey]*cloud.ResourceID{}
+	for _, r := range renames {
+		nb := b.Get(r.Old)
+		switch {
+		case nb == nil:
+			return fmt.Errorf("%s: Migrate: %v is not in the graph", errPrefix, r.Old)
+		case nb.State() != rnode.NodeExists || nb.Resource() == nil:
+			return fmt.Errorf("%s: Migrate: %v does not have a resource (state %s)", errPrefix, r.Old, nb.State())
+		case b.Get(r.New) != nil:
+			return fmt.Errorf("%s: Migrate: %v is already in the graph", errPrefix, r.New)
+		case r.Old.Resource != r.New.Resource:
+			return fmt.Errorf("%s: Migrate: cannot rename %v to a different resource type (%v)", errPrefix, r.Old, r.New)
+		}
+		renamed[r.Old.MapKey()] = r.New
+	}
+	rewriteRef := func(_ api.Path, s string) string {
+		id, err := cloud.ParseResourceURL(s)
+		if err != nil {
+			return s
+		}
+		newID, ok := renamed[id.MapKey()]
+		if !ok {
+			return s
+		}
+		if sameScope(id, newID) && strings.HasSuffix(s, "/"+id.Key.Name) {
+			// Keep the version and format of the original link.
+			return strings.TrimSuffix(s, id.Key.Name) + newID.Key.Name
+		}
+		return newID.SelfLink(meta.VersionGA)
+	}
+
+	// Move the references from the other nodes.
+	for _, nb := range b.All() {
+		if renamed[nb.ID().MapKey()] != nil || nb.Resource() == nil {
+			continue
+		}
+		refs, err := nb.OutRefs()
+		if err != nil {
+			return fmt.Errorf("%s: Migrate: %w", errPrefix, err)
+		}
+		var refersToRenamed bool
+		for _, ref := range refs {
+			if renamed[ref.To.MapKey()] != nil {
+				refersToRenamed = true
+			}
+		}
+		if !refersToRenamed {
+			continue
+		}
+		r, err := rewriteResource(nb.Resource(), nb.ID(), rewriteRef)
+		if err != nil {
+			return err
+		}
+		if err := nb.SetResource(r); err != nil {
+			return fmt.Errorf("%s: Migrate: %v: %w", errPrefix, nb.ID(), err)
+		}
+	}
+
+	for _, r := range renames {
+		old := b.Get(r.Old)
+		res, err := rewriteResource(old.Resource(), r.New, rewriteRef)
+		if err != nil {
+			return err
+		}
+		nb, err := all.NewBuilderByID(r.New)
+		if err != nil {
+			return fmt.Errorf("%s: Migrate: %w", errPrefix, err)
+		}
+		if err := nb.SetResource(res); err != nil {
+			return fmt.Errorf("%s: Migrate: %v: %w", errPrefix, r.New, err)
+		}
+		nb.SetState(rnode.NodeExists)
+		nb.SetOwnership(rnode.OwnershipManaged)
+		nb.SetDeletionProtected(old.DeletionProtected())
+		nb.SetConflictStrategy(old.ConflictStrategy())
+		nb.SetAnnotations(old.Annotations())
+		nb.SetPreconditions(old.Preconditions())
+		nb.SetPostconditions(old.Postconditions())
+		nb.SetOutRefsMode(old.OutRefsMode())
+		b.Add(nb)
+
+		tombstone, err := all.NewBuilderByID(r.Old)
+		if err != nil {
+			return fmt.Errorf("%s: Migrate: %w", errPrefix, err)
+		}
+		tombstone.SetState(rnode.NodeDoesNotExist)
+		tombstone.SetOwnership(rnode.OwnershipManaged)
+		b.Add(tombstone)
+	}
+	return nil
+}
+
+// sameScope returns true if a and b only differ by name.
+func sameScope(a, b *cloud.ResourceID) bool {
+	return a.ProjectID == b.ProjectID &&
+		a.Resource == b.Resource &&
+		a.Key.Type() == b.Key.Type() &&
+		a.Key.Region == b.Key.Region &&
+		a.Key.Zone == b.Key.Zone
+}
+
+func rewriteResource(r rnode.UntypedResource, id *cloud.ResourceID, f func(api.Path, string) string) (rnode.UntypedResource, error) {
+	rw, ok := r.(api.Rewriter)
+	if !ok {
+		return nil, fmt.Errorf("%s: Migrate: %v: resource %T does not support Rewrite", errPrefix, id, r)
+	}
+	res, err := rw.Rewrite(id, f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: Migrate: %v: %w", errPrefix, id, err)
+	}
+	ret, ok := res.(rnode.UntypedResource)
+	if !ok {
+		return nil, fmt.Errorf("%s: Migrate: %v: invalid resource %T", errPrefix, id, res)
+	}
+	return ret, nil
+}
@@ -0,0 +1,128 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package naming
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/mock"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/exec"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/healthcheck"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/testing/ez"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/workflow/ensure"
+	"github.com/google/go-cmp/cmp"
+)
+
+func oldGraph() *ez.Graph {
+	return &ez.Graph{
+		Project: "proj",
+		Nodes: []ez.Node{
+			{Name: "hc-old"},
+			{Name: "bs", Refs: []ez.Ref{{Field: "Healthchecks", To: "hc-old"}}},
+		},
+	}
+}
+
+// newName migrates names with the "-old" suffix to "-new".
+func newName(id *cloud.ResourceID) (string, bool) {
+	if !strings.HasSuffix(id.Key.Name, "-old") {
+		return "", false
+	}
+	return strings.TrimSuffix(id.Key.Name, "-old") + "-new", true
+}
+
+func TestValidate(t *testing.T) {
+	got := Validate(oldGraph().Builder(), func(name string) bool { return !strings.HasSuffix(name, "-old") })
+	if len(got) != 1 || got[0].Key.Name != "hc-old" {
+		t.Errorf("Validate() = %v, want [hc-old]", got)
+	}
+}
+
+func TestMigrate(t *testing.T) {
+	ctx := context.Background()
+	m := cloud.NewMockGCE(&cloud.SingleProjectRouter{ID: "proj"})
+	m.MockBackendServices.UpdateHook = mock.UpdateBackendServiceHook
+
+	if _, err := ensure.Do(ctx, m, oldGraph().Builder()); err != nil {
+		t.Fatalf("ensure.Do() = %v, want nil", err)
+	}
+
+	b := oldGraph().Builder()
+	renames := Detect(b, newName)
+	if len(renames) != 1 || renames[0].New.Key.Name != "hc-new" {
+		t.Fatalf("Detect() = %+v, want hc-old => hc-new", renames)
+	}
+	if err := Migrate(b, renames); err != nil {
+		t.Fatalf("Migrate() = %v, want nil", err)
+	}
+	r, err := ensure.Do(ctx, m, b)
+	if err != nil {
+		t.Fatalf("ensure.Do() = %v, want nil", err)
+	}
+
+	var order []string
+	for _, a := range r.Exec.Completed {
+		switch a.Metadata().Type {
+		case exec.ActionTypeCreate, exec.ActionTypeUpdate, exec.ActionTypeDelete:
+			order = append(order, string(a.Metadata().Type)+" "+a.Metadata().ResourceID.Key.Name)
+		}
+	}
+	wantOrder := []string{"Create hc-new", "Update bs", "Delete hc-old"}
+	if diff := cmp.Diff(order, wantOrder); diff != "" {
+		t.Errorf("action order: -got,+want: %s", diff)
+	}
+
+	bs, err := m.BackendServices().Get(ctx, meta.GlobalKey("bs"))
+	if err != nil {
+		t.Fatalf("BackendServices().Get() = %v, want nil", err)
+	}
+	if want := healthcheck.ID("proj", meta.GlobalKey("hc-new")); len(bs.HealthChecks) != 1 || !strings.HasSuffix(bs.HealthChecks[0], "/"+want.Key.Name) {
+		t.Errorf("bs.HealthChecks = %v, want [%v]", bs.HealthChecks, want)
+	}
+	if _, err := m.HealthChecks().Get(ctx, meta.GlobalKey("hc-old")); err == nil {
+		t.Error("HealthChecks().Get(hc-old) = nil, want not found")
+	}
+}
+
+func TestMigrateInvalid(t *testing.T) {
+	hcOld := healthcheck.ID("proj", meta.GlobalKey("hc-old"))
+	for _, tc := range []struct {
+		name    string
+		renames []Rename
+	}{
+		{
+			name:    "not in graph",
+			renames: []Rename{{Old: healthcheck.ID("proj", meta.GlobalKey("hc-x")), New: healthcheck.ID("proj", meta.GlobalKey("hc-y"))}},
+		},
+		{
+			name:    "new already exists",
+			renames: []Rename{{Old: hcOld, New: hcOld}},
+		},
+		{
+			name:    "different resource type",
+			renames: []Rename{{Old: hcOld, New: &cloud.ResourceID{ProjectID: "proj", Resource: "backendServices", Key: meta.GlobalKey("x")}}},
+		},
+	} {
+		if err := Migrate(oldGraph().Builder(), tc.renames); err == nil {
+			t.Errorf("%s: Migrate() = nil, want error", tc.name)
+		}
+	}
+}
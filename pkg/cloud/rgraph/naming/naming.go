@@ -0,0 +1,254 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package naming generates names for resources created by the library.
+//
+//	s := &naming.Hashed{Prefix: "k8s1-0a1b2c3d"}
+//	name := s.Name("default", "my-service", "80")
+//	// k8s1-0a1b2c3d-default-my-service-80-1f2e3d4c
+//
+// Generated names are deterministic, so the same logical object always maps
+// to the same resource. Use CheckCollision or Resolve to detect a resource
+// with the same name that belongs to someone else.
+package naming
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/all"
+)
+
+const (
+	errPrefix = "Naming"
+
+	// MaxNameLength is the maximum length of a GCE resource name.
+	MaxNameLength = 63
+	// defaultHashLength is the number of hex digits of the hash.
+	defaultHashLength = 8
+)
+
+var validName = regexp.MustCompile(`^[a-z]([-a-z0-9]*[a-z0-9])?$`)
+
+// ValidName returns true if name is a valid GCE resource name.
+func ValidName(name string) bool {
+	return len(name) <= MaxNameLength && validName.MatchString(name)
+}
+
+// Strategy generates the names of resources.
+type Strategy interface {
+	// Name of the resource for the given components (e.g. the namespace,
+	// name and port of a K8s Service). The same components always return
+	// the same name.
+	Name(components ...string) string
+}
+
+// Hashed generates names of the form "<Prefix>-<components>-<hash>". The hash
+// is computed over all of the components so names remain unique when the
+// components have to be truncated to fit in MaxLength.
+type Hashed struct {
+	// Prefix of the names. This must start with a lowercase letter.
+	Prefix string
+	// MaxLength of the names. Defaults to MaxNameLength.
+	MaxLength int
+	// HashLength is the number of hex digits of the hash. Defaults to 8.
+	HashLength int
+}
+
+var _ Strategy = (*Hashed)(nil)
+
+// Name implements Strategy.
+func (s *Hashed) Name(components ...string) string {
+	maxLen := s.MaxLength
+	if maxLen <= 0 || maxLen > MaxNameLength {
+		maxLen = MaxNameLength
+	}
+	hashLen := s.HashLength
+	if hashLen <= 0 {
+		hashLen = defaultHashLength
+	}
+
+	sum := sha256.Sum256([]byte(strings.Join(components, "/")))
+	hash := hex.EncodeToString(sum[:])
+	if hashLen < len(hash) {
+		hash = hash[:hashLen]
+	}
+
+	var parts []string
+	for _, c := range components {
+		if c = sanitize(c); c != "" {
+			parts = append(parts, c)
+		}
+	}
+
+	// Space left for the components and their "-" separators after the
+	// prefix and hash.
+	budget := maxLen - len(hash)
+	if s.Prefix != "" {
+		budget -= len(s.Prefix) + 1
+	}
+	parts = truncate(parts, budget)
+
+	var elems []string
+	if s.Prefix != "" {
+		elems = append(elems, s.Prefix)
+	}
+	elems = append(elems, parts...)
+	elems = append(elems, hash)
+	return strings.Join(elems, "-")
+}
+
+// sanitize c to only contain the characters allowed in a name.
+func sanitize(c string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(c) {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+			b.WriteRune(r)
+		default:
+			b.WriteRune('-')
+		}
+	}
+	return strings.Trim(b.String(), "-")
+}
+
+// truncate the parts so that they fit in budget when joined with "-" and a
+// trailing "-". The space is shared evenly; space not used by short parts
+// is given to the longer ones.
+func truncate(parts []string, budget int) []string {
+	for len(parts) > 0 && budget < 2*len(parts) {
+		// Not enough space for even one character per part.
+		parts = parts[:len(parts)-1]
+	}
+	if len(parts) == 0 {
+		return nil
+	}
+	// Each part needs a trailing "-".
+	avail := budget - len(parts)
+	lens := make([]int, len(parts))
+	remaining := len(parts)
+	for remaining > 0 {
+		share := avail / remaining
+		progress := false
+		for i, p := range parts {
+			if lens[i] != 0 {
+				continue
+			}
+			if len(p) <= share {
+				lens[i] = len(p)
+				avail -= len(p)
+				remaining--
+				progress = true
+			}
+		}
+		if !progress {
+			// All remaining parts are longer than the share.
+			extra := avail % remaining
+			for i := range parts {
+				if lens[i] == 0 {
+					lens[i] = share
+					if extra > 0 {
+						lens[i]++
+						extra--
+					}
+				}
+			}
+			break
+		}
+	}
+	var ret []string
+	for i, p := range parts {
+		if t := strings.TrimRight(p[:lens[i]], "-"); t != "" {
+			ret = append(ret, t)
+		}
+	}
+	return ret
+}
+
+// OwnerFunc returns true if the resource in the Cloud belongs to the caller,
+// e.g. by checking a marker in the Description.
+type OwnerFunc func(r rnode.UntypedResource) bool
+
+// CollisionError is returned when a resource with the generated name exists
+// in the Cloud but is not owned by the caller.
+type CollisionError struct {
+	ID *cloud.ResourceID
+}
+
+func (e *CollisionError) Error() string {
+	return fmt.Sprintf("%s: %v already exists and is not owned by the caller", errPrefix, e.ID)
+}
+
+// CheckCollision returns a CollisionError if the resource named by id exists
+// in the Cloud and isOwner returns false for it. A resource that does not
+// exist is not a collision.
+func CheckCollision(ctx context.Context, cl cloud.Cloud, id *cloud.ResourceID, isOwner OwnerFunc) error {
+	nb, err := all.NewBuilderByID(id)
+	if err != nil {
+		return fmt.Errorf("%s: %w", errPrefix, err)
+	}
+	if err := nb.SyncFromCloud(ctx, cl); err != nil {
+		return fmt.Errorf("%s: %w", errPrefix, err)
+	}
+	if nb.State() != rnode.NodeExists || isOwner(nb.Resource()) {
+		return nil
+	}
+	return &CollisionError{ID: id}
+}
+
+// Resolve returns the ID for the components that does not collide with a
+// resource owned by someone else. The name from s is tried first; on a
+// collision, an attempt number is appended to the components and the name is
+// regenerated, up to maxAttempts times. newID creates the ResourceID from a
+// name (e.g. to set the project and scope).
+func Resolve(
+	ctx context.Context,
+	cl cloud.Cloud,
+	s Strategy,
+	newID func(name string) *cloud.ResourceID,
+	isOwner OwnerFunc,
+	maxAttempts int,
+	components ...string,
+) (*cloud.ResourceID, error) {
+	var lastErr error
+	for i := 0; i < maxAttempts; i++ {
+		c := components
+		if i > 0 {
+			c = append(append([]string{}, components...), fmt.Sprint(i))
+		}
+		id := newID(s.Name(c...))
+		err := CheckCollision(ctx, cl, id, isOwner)
+		if err == nil {
+			return id, nil
+		}
+		var collision *CollisionError
+		if !errors.As(err, &collision) {
+			return nil, err
+		}
+		lastErr = err
+	}
+	if lastErr == nil {
+		return nil, fmt.Errorf("%s: maxAttempts must be > 0", errPrefix)
+	}
+	return nil, fmt.Errorf("%s: no free name after %d attempts: %w", errPrefix, maxAttempts, lastErr)
+}
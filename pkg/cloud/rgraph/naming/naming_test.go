@@ -0,0 +1,151 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package naming
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/healthcheck"
+	"google.golang.org/api/compute/v1"
+)
+
+func TestHashed(t *testing.T) {
+	long := strings.Repeat("x", 100)
+
+	for _, tc := range []struct {
+		name       string
+		s          Hashed
+		components []string
+		wantPrefix string
+		wantLen    int
+	}{
+		{
+			name:       "short",
+			s:          Hashed{Prefix: "k8s1-0a1b2c3d"},
+			components: []string{"default", "my-service", "80"},
+			wantPrefix: "k8s1-0a1b2c3d-default-my-service-80-",
+			wantLen:    len("k8s1-0a1b2c3d-default-my-service-80-") + 8,
+		},
+		{
+			name:       "sanitized",
+			s:          Hashed{Prefix: "p"},
+			components: []string{"My_Namespace", "--svc.name--"},
+			wantPrefix: "p-my-namespace-svc-name-",
+		},
+		{
+			name:       "truncated",
+			s:          Hashed{Prefix: "k8s1-0a1b2c3d"},
+			components: []string{"ns", long, long},
+			wantPrefix: "k8s1-0a1b2c3d-ns-xxxxx",
+			wantLen:    MaxNameLength,
+		},
+		{
+			name:       "no prefix",
+			components: []string{"a"},
+			wantPrefix: "a-",
+		},
+		{
+			name:       "max length and hash length",
+			s:          Hashed{Prefix: "p", MaxLength: 20, HashLength: 4},
+			components: []string{long},
+			wantLen:    20,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got := tc.s.Name(tc.components...)
+			if !ValidName(got) {
+				t.Errorf("Name() = %q, not a valid name", got)
+			}
+			if !strings.HasPrefix(got, tc.wantPrefix) {
+				t.Errorf("Name() = %q, want prefix %q", got, tc.wantPrefix)
+			}
+			if tc.wantLen != 0 && len(got) != tc.wantLen {
+				t.Errorf("len(Name()) = %d, want %d (%q)", len(got), tc.wantLen, got)
+			}
+			if again := tc.s.Name(tc.components...); again != got {
+				t.Errorf("Name() = %q, then %q; want deterministic", got, again)
+			}
+		})
+	}
+
+	// Components that are identical after truncation must not collide.
+	s := &Hashed{Prefix: "p"}
+	if a, b := s.Name(long+"a"), s.Name(long+"b"); a == b {
+		t.Errorf("Name() = %q for different components", a)
+	}
+}
+
+func TestCheckCollision(t *testing.T) {
+	ctx := context.Background()
+	mock := cloud.NewMockGCE(&cloud.SingleProjectRouter{ID: "proj"})
+	mock.HealthChecks().Insert(ctx, meta.GlobalKey("mine"), &compute.HealthCheck{Description: "owner=me"})
+	mock.HealthChecks().Insert(ctx, meta.GlobalKey("theirs"), &compute.HealthCheck{Description: "owner=them"})
+
+	isOwner := func(r rnode.UntypedResource) bool {
+		hc, _ := r.(healthcheck.HealthCheck).ToGA()
+		return hc.Description == "owner=me"
+	}
+
+	for _, tc := range []struct {
+		name          string
+		wantCollision bool
+	}{
+		{name: "mine"},
+		{name: "does-not-exist"},
+		{name: "theirs", wantCollision: true},
+	} {
+		err := CheckCollision(ctx, mock, healthcheck.ID("proj", meta.GlobalKey(tc.name)), isOwner)
+		var collision *CollisionError
+		if got := errors.As(err, &collision); got != tc.wantCollision {
+			t.Errorf("CheckCollision(%s) = %v, want collision=%t", tc.name, err, tc.wantCollision)
+		}
+	}
+
+	if err := CheckCollision(ctx, mock, &cloud.ResourceID{Resource: "unknown", Key: meta.GlobalKey("x")}, isOwner); err == nil {
+		t.Error("CheckCollision(unknown) = nil, want error")
+	}
+}
+
+func TestResolve(t *testing.T) {
+	ctx := context.Background()
+	mock := cloud.NewMockGCE(&cloud.SingleProjectRouter{ID: "proj"})
+	s := &Hashed{Prefix: "p"}
+	newID := func(name string) *cloud.ResourceID { return healthcheck.ID("proj", meta.GlobalKey(name)) }
+	notMine := func(rnode.UntypedResource) bool { return false }
+
+	// The first name is taken by someone else.
+	taken := s.Name("svc")
+	mock.HealthChecks().Insert(ctx, meta.GlobalKey(taken), &compute.HealthCheck{})
+
+	id, err := Resolve(ctx, mock, s, newID, notMine, 3, "svc")
+	if err != nil {
+		t.Fatalf("Resolve() = %v, want nil", err)
+	}
+	if want := s.Name("svc", "1"); id.Key.Name != want {
+		t.Errorf("Resolve() = %q, want %q", id.Key.Name, want)
+	}
+
+	if _, err := Resolve(ctx, mock, s, newID, notMine, 1, "svc"); err == nil {
+		t.Error("Resolve(maxAttempts=1) = nil, want error")
+	}
+}
@@ -0,0 +1,51 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rgraph
+
+import (
+	"sort"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode"
+)
+
+// Prune adds a node with NodeDoesNotExist to the Builder for each of the
+// managed nodes in prev (e.g. the previously applied graph) that is missing
+// from the Builder. Planning the Builder will delete the corresponding
+// resources without the caller having to track which resources were removed.
+// Returns the IDs of the nodes that were added, sorted.
+//
+// Nodes in prev that are External or that do not exist are ignored. The
+// added nodes keep the DeletionProtected and CascadeDelete settings from
+// prev.
+func (g *Builder) Prune(prev *Graph) []*cloud.ResourceID {
+	var ret []*cloud.ResourceID
+	for _, n := range prev.All() {
+		if n.Ownership() != rnode.OwnershipManaged || n.State() != rnode.NodeExists {
+			continue
+		}
+		if g.Get(n.ID()) != nil {
+			continue
+		}
+		b := n.Builder()
+		b.SetState(rnode.NodeDoesNotExist)
+		g.Add(b)
+		ret = append(ret, n.ID())
+	}
+	sort.Slice(ret, func(i, j int) bool { return ret[i].String() < ret[j].String() })
+	return ret
+}
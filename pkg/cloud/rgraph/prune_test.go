@@ -0,0 +1,74 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rgraph
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/fake"
+)
+
+func TestBuilderPrune(t *testing.T) {
+	ids := make([]*cloud.ResourceID, 5)
+	for i := range ids {
+		ids[i] = &cloud.ResourceID{Resource: "fake", Key: meta.GlobalKey(fmt.Sprintf("r%d", i))}
+	}
+	add := func(b *Builder, id *cloud.ResourceID, os rnode.OwnershipStatus, state rnode.NodeState) {
+		nb := fake.NewBuilder(id)
+		nb.SetOwnership(os)
+		nb.SetState(state)
+		b.Add(nb)
+	}
+
+	prevBuilder := NewBuilder()
+	add(prevBuilder, ids[0], rnode.OwnershipManaged, rnode.NodeExists)
+	add(prevBuilder, ids[1], rnode.OwnershipManaged, rnode.NodeExists)
+	add(prevBuilder, ids[2], rnode.OwnershipExternal, rnode.NodeExists)
+	add(prevBuilder, ids[3], rnode.OwnershipManaged, rnode.NodeDoesNotExist)
+	add(prevBuilder, ids[4], rnode.OwnershipManaged, rnode.NodeExists)
+	prevBuilder.Get(ids[4]).SetDeletionProtected(true)
+	prev := prevBuilder.MustBuild()
+
+	b := NewBuilder()
+	add(b, ids[0], rnode.OwnershipManaged, rnode.NodeExists)
+
+	pruned := b.Prune(prev)
+	if len(pruned) != 2 || !pruned[0].Equal(ids[1]) || !pruned[1].Equal(ids[4]) {
+		t.Fatalf("Prune() = %v, want [%v %v]", pruned, ids[1], ids[4])
+	}
+	for _, id := range pruned {
+		if got := b.Get(id).State(); got != rnode.NodeDoesNotExist {
+			t.Errorf("%v State() = %s, want %s", id, got, rnode.NodeDoesNotExist)
+		}
+	}
+	if !b.Get(ids[4]).DeletionProtected() {
+		t.Errorf("%v DeletionProtected() = false, want true", ids[4])
+	}
+	if got := b.Get(ids[0]).State(); got != rnode.NodeExists {
+		t.Errorf("%v State() = %s, want %s", ids[0], got, rnode.NodeExists)
+	}
+	if b.Get(ids[2]) != nil || b.Get(ids[3]) != nil {
+		t.Errorf("Prune() added external or non-existent nodes")
+	}
+	if _, err := b.Build(); err != nil {
+		t.Errorf("Build() = %v", err)
+	}
+}
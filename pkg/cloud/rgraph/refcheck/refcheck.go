@@ -0,0 +1,307 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package refcheck finds the resources in the Cloud that reference a
+// resource. Use Live with exec.DeleteRefCheckOption to prevent deleting a
+// resource that is still in use by resources outside of the graph:
+//
+//	ex, err := exec.NewParallelExecutor(cl, actions, exec.DeleteRefCheckOption(refcheck.Live))
+package refcheck
+
+import (
+	"context"
+	"fmt"
+	"net"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/cerrors"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/filter"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/exec"
+	"google.golang.org/api/compute/v1"
+)
+
+const errPrefix = "RefCheck"
+
+var _ exec.ReferrersFunc = Live
+
+// Live lists the resources that can reference id and returns the ones that
+// do. The following resource types are supported:
+//
+//   - addresses: ForwardingRules (IPAddress).
+//   - targetHttpProxies, targetGrpcProxies: ForwardingRules (Target).
+//   - urlMaps: TargetHttpProxies, TargetHttpsProxies, TargetGrpcProxies
+//     (UrlMap).
+//   - backendServices: UrlMaps (default and path rule services),
+//     ForwardingRules (BackendService).
+//   - healthChecks: BackendServices (HealthChecks).
+//   - networkEndpointGroups: BackendServices (Backends.Group).
+//
+// Other resource types always return no referrers.
+func Live(ctx context.Context, cl cloud.Cloud, id *cloud.ResourceID) ([]*cloud.ResourceID, error) {
+	var (
+		ret []*cloud.ResourceID
+		err error
+	)
+	switch id.Resource {
+	case "addresses":
+		ret, err = addressReferrers(ctx, cl, id)
+	case "targetHttpProxies", "targetGrpcProxies":
+		ret, err = forwardingRuleReferrers(ctx, cl, id, func(fr *compute.ForwardingRule) bool {
+			return refersTo(id, fr.Target)
+		})
+	case "urlMaps":
+		ret, err = urlMapReferrers(ctx, cl, id)
+	case "backendServices":
+		ret, err = backendServiceReferrers(ctx, cl, id)
+	case "healthChecks":
+		ret, err = backendServicesReferencing(ctx, cl, id, func(bs *compute.BackendService) []string {
+			return bs.HealthChecks
+		})
+	case "networkEndpointGroups":
+		ret, err = backendServicesReferencing(ctx, cl, id, func(bs *compute.BackendService) []string {
+			var groups []string
+			for _, b := range bs.Backends {
+				groups = append(groups, b.Group)
+			}
+			return groups
+		})
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v: %w", errPrefix, id, err)
+	}
+	return ret, nil
+}
+
+// refersTo returns true if any of the urls is a link to id.
+func refersTo(id *cloud.ResourceID, urls ...string) bool {
+	for _, u := range urls {
+		if u == "" {
+			continue
+		}
+		ref, err := cloud.ParseResourceURL(u)
+		if err != nil {
+			continue
+		}
+		if ref.Equal(id) {
+			return true
+		}
+	}
+	return false
+}
+
+// parse the selfLink of a referrer.
+func parse(selfLink string) (*cloud.ResourceID, error) {
+	id, err := cloud.ParseResourceURL(selfLink)
+	if err != nil {
+		return nil, fmt.Errorf("referrer: %w", err)
+	}
+	return id, nil
+}
+
+// forwardingRules in the same scope as id. Global resources are referenced
+// by GlobalForwardingRules, regional resources by ForwardingRules in the
+// region.
+func forwardingRules(ctx context.Context, cl cloud.Cloud, id *cloud.ResourceID) ([]*compute.ForwardingRule, error) {
+	opt := cloud.ForceProjectID(id.ProjectID)
+	if id.Key.Type() == meta.Regional {
+		return cl.ForwardingRules().List(ctx, id.Key.Region, filter.None, opt)
+	}
+	return cl.GlobalForwardingRules().List(ctx, filter.None, opt)
+}
+
+// forwardingRuleReferrers returns the ForwardingRules in the same scope as id
+// that match.
+func forwardingRuleReferrers(ctx context.Context, cl cloud.Cloud, id *cloud.ResourceID, match func(*compute.ForwardingRule) bool) ([]*cloud.ResourceID, error) {
+	frs, err := forwardingRules(ctx, cl, id)
+	if err != nil {
+		return nil, err
+	}
+	var ret []*cloud.ResourceID
+	for _, fr := range frs {
+		if !match(fr) {
+			continue
+		}
+		frID, err := parse(fr.SelfLink)
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, frID)
+	}
+	return ret, nil
+}
+
+func addressReferrers(ctx context.Context, cl cloud.Cloud, id *cloud.ResourceID) ([]*cloud.ResourceID, error) {
+	var (
+		addr *compute.Address
+		err  error
+	)
+	opt := cloud.ForceProjectID(id.ProjectID)
+	if id.Key.Type() == meta.Regional {
+		addr, err = cl.Addresses().Get(ctx, id.Key, opt)
+	} else {
+		addr, err = cl.GlobalAddresses().Get(ctx, id.Key, opt)
+	}
+	if cerrors.IsGoogleAPINotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// ForwardingRules can reference the Address by URL or by the numeric IP.
+	return forwardingRuleReferrers(ctx, cl, id, func(fr *compute.ForwardingRule) bool {
+		if net.ParseIP(fr.IPAddress) != nil {
+			return addr.Address != "" && fr.IPAddress == addr.Address
+		}
+		return refersTo(id, fr.IPAddress)
+	})
+}
+
+func urlMapReferrers(ctx context.Context, cl cloud.Cloud, id *cloud.ResourceID) ([]*cloud.ResourceID, error) {
+	opt := cloud.ForceProjectID(id.ProjectID)
+	var selfLinks []string
+	if id.Key.Type() == meta.Regional {
+		tps, err := cl.RegionTargetHttpProxies().List(ctx, id.Key.Region, filter.None, opt)
+		if err != nil {
+			return nil, err
+		}
+		for _, tp := range tps {
+			if refersTo(id, tp.UrlMap) {
+				selfLinks = append(selfLinks, tp.SelfLink)
+			}
+		}
+		tps2, err := cl.RegionTargetHttpsProxies().List(ctx, id.Key.Region, filter.None, opt)
+		if err != nil {
+			return nil, err
+		}
+		for _, tp := range tps2 {
+			if refersTo(id, tp.UrlMap) {
+				selfLinks = append(selfLinks, tp.SelfLink)
+			}
+		}
+	} else {
+		tps, err := cl.TargetHttpProxies().List(ctx, filter.None, opt)
+		if err != nil {
+			return nil, err
+		}
+		for _, tp := range tps {
+			if refersTo(id, tp.UrlMap) {
+				selfLinks = append(selfLinks, tp.SelfLink)
+			}
+		}
+		tps2, err := cl.TargetHttpsProxies().List(ctx, filter.None, opt)
+		if err != nil {
+			return nil, err
+		}
+		for _, tp := range tps2 {
+			if refersTo(id, tp.UrlMap) {
+				selfLinks = append(selfLinks, tp.SelfLink)
+			}
+		}
+		gps, err := cl.TargetGrpcProxies().List(ctx, filter.None, opt)
+		if err != nil {
+			return nil, err
+		}
+		for _, gp := range gps {
+			if refersTo(id, gp.UrlMap) {
+				selfLinks = append(selfLinks, gp.SelfLink)
+			}
+		}
+	}
+	return parseAll(selfLinks)
+}
+
+func backendServiceReferrers(ctx context.Context, cl cloud.Cloud, id *cloud.ResourceID) ([]*cloud.ResourceID, error) {
+	opt := cloud.ForceProjectID(id.ProjectID)
+	var (
+		ums []*compute.UrlMap
+		err error
+	)
+	if id.Key.Type() == meta.Regional {
+		ums, err = cl.RegionUrlMaps().List(ctx, id.Key.Region, filter.None, opt)
+	} else {
+		ums, err = cl.UrlMaps().List(ctx, filter.None, opt)
+	}
+	if err != nil {
+		return nil, err
+	}
+	var selfLinks []string
+	for _, um := range ums {
+		if refersTo(id, urlMapServices(um)...) {
+			selfLinks = append(selfLinks, um.SelfLink)
+		}
+	}
+	ret, err := parseAll(selfLinks)
+	if err != nil {
+		return nil, err
+	}
+
+	// Internal passthrough load balancers reference the BackendService from
+	// the ForwardingRule directly.
+	frRefs, err := forwardingRuleReferrers(ctx, cl, id, func(fr *compute.ForwardingRule) bool {
+		return refersTo(id, fr.BackendService)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return append(ret, frRefs...), nil
+}
+
+// urlMapServices returns the services referenced by the UrlMap.
+func urlMapServices(um *compute.UrlMap) []string {
+	ret := []string{um.DefaultService}
+	for _, pm := range um.PathMatchers {
+		ret = append(ret, pm.DefaultService)
+		for _, pr := range pm.PathRules {
+			ret = append(ret, pr.Service)
+		}
+		for _, rr := range pm.RouteRules {
+			ret = append(ret, rr.Service)
+		}
+	}
+	return ret
+}
+
+// backendServicesReferencing returns the BackendServices (global and
+// regional) that reference id.
+func backendServicesReferencing(ctx context.Context, cl cloud.Cloud, id *cloud.ResourceID, refs func(*compute.BackendService) []string) ([]*cloud.ResourceID, error) {
+	all, err := cl.BackendServices().AggregatedList(ctx, filter.None, cloud.ForceProjectID(id.ProjectID))
+	if err != nil {
+		return nil, err
+	}
+	var selfLinks []string
+	for _, bss := range all {
+		for _, bs := range bss {
+			if refersTo(id, refs(bs)...) {
+				selfLinks = append(selfLinks, bs.SelfLink)
+			}
+		}
+	}
+	return parseAll(selfLinks)
+}
+
+func parseAll(selfLinks []string) ([]*cloud.ResourceID, error) {
+	var ret []*cloud.ResourceID
+	for _, sl := range selfLinks {
+		id, err := parse(sl)
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, id)
+	}
+	return ret, nil
+}
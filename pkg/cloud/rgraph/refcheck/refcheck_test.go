@@ -0,0 +1,197 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package refcheck
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/exec"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/address"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/backendservice"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/healthcheck"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/networkendpointgroup"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/targethttpproxy"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/urlmap"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/testing/ez"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/workflow/ensure"
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/api/compute/v1"
+)
+
+const proj = "proj"
+
+func link(id *cloud.ResourceID) string { return id.SelfLink(meta.VersionGA) }
+
+func names(ids []*cloud.ResourceID) []string {
+	var ret []string
+	for _, id := range ids {
+		ret = append(ret, id.Resource+"/"+id.Key.Name)
+	}
+	// The mock List() does not return items in a stable order.
+	sort.Strings(ret)
+	return ret
+}
+
+func TestLive(t *testing.T) {
+	ctx := context.Background()
+	m := cloud.NewMockGCE(&cloud.SingleProjectRouter{ID: proj})
+
+	var (
+		addrID  = address.ID(proj, meta.GlobalKey("addr"))
+		ipID    = address.ID(proj, meta.GlobalKey("ip"))
+		tpID    = targethttpproxy.ID(proj, meta.GlobalKey("tp"))
+		umID    = urlmap.ID(proj, meta.GlobalKey("um"))
+		rumID   = urlmap.ID(proj, meta.RegionalKey("rum", "us-central1"))
+		bsID    = backendservice.ID(proj, meta.GlobalKey("bs"))
+		rbsID   = backendservice.ID(proj, meta.RegionalKey("rbs", "us-central1"))
+		hcID    = healthcheck.ID(proj, meta.GlobalKey("hc"))
+		negID   = networkendpointgroup.ID(proj, meta.ZonalKey("neg", "us-central1-a"))
+		unusedH = healthcheck.ID(proj, meta.GlobalKey("unused"))
+	)
+
+	m.GlobalAddresses().Insert(ctx, addrID.Key, &compute.Address{})
+	m.GlobalAddresses().Insert(ctx, ipID.Key, &compute.Address{Address: "1.2.3.4"})
+	m.GlobalForwardingRules().Insert(ctx, meta.GlobalKey("fr"), &compute.ForwardingRule{
+		IPAddress: link(addrID),
+		Target:    link(tpID),
+	})
+	m.GlobalForwardingRules().Insert(ctx, meta.GlobalKey("fr-ip"), &compute.ForwardingRule{
+		IPAddress: "1.2.3.4",
+	})
+	m.TargetHttpProxies().Insert(ctx, tpID.Key, &compute.TargetHttpProxy{UrlMap: link(umID)})
+	m.TargetHttpsProxies().Insert(ctx, meta.GlobalKey("tps"), &compute.TargetHttpsProxy{UrlMap: link(umID)})
+	m.TargetGrpcProxies().Insert(ctx, meta.GlobalKey("gp"), &compute.TargetGrpcProxy{UrlMap: link(umID)})
+	m.RegionTargetHttpProxies().Insert(ctx, meta.RegionalKey("rtp", "us-central1"), &compute.TargetHttpProxy{UrlMap: link(rumID)})
+	m.RegionTargetHttpsProxies().Insert(ctx, meta.RegionalKey("rtps", "us-central1"), &compute.TargetHttpsProxy{UrlMap: link(rumID)})
+	// Refers to a different UrlMap.
+	m.RegionTargetHttpProxies().Insert(ctx, meta.RegionalKey("other", "us-central1"), &compute.TargetHttpProxy{UrlMap: link(umID)})
+	m.UrlMaps().Insert(ctx, umID.Key, &compute.UrlMap{
+		DefaultService: link(bsID),
+	})
+	m.UrlMaps().Insert(ctx, meta.GlobalKey("um-path"), &compute.UrlMap{
+		PathMatchers: []*compute.PathMatcher{{PathRules: []*compute.PathRule{{Service: link(bsID)}}}},
+	})
+	m.BackendServices().Insert(ctx, bsID.Key, &compute.BackendService{
+		HealthChecks: []string{link(hcID)},
+		Backends:     []*compute.Backend{{Group: link(negID)}},
+	})
+	m.ForwardingRules().Insert(ctx, meta.RegionalKey("ilb", "us-central1"), &compute.ForwardingRule{
+		BackendService: link(rbsID),
+	})
+
+	for _, tc := range []struct {
+		id   *cloud.ResourceID
+		want []string
+	}{
+		{id: addrID, want: []string{"forwardingRules/fr"}},
+		{id: ipID, want: []string{"forwardingRules/fr-ip"}},
+		{id: tpID, want: []string{"forwardingRules/fr"}},
+		{id: umID, want: []string{"targetGrpcProxies/gp", "targetHttpProxies/tp", "targetHttpsProxies/tps"}},
+		{id: rumID, want: []string{"targetHttpProxies/rtp", "targetHttpsProxies/rtps"}},
+		{id: bsID, want: []string{"urlMaps/um", "urlMaps/um-path"}},
+		{id: rbsID, want: []string{"forwardingRules/ilb"}},
+		{id: hcID, want: []string{"backendServices/bs"}},
+		{id: negID, want: []string{"backendServices/bs"}},
+		{id: unusedH},
+		{id: address.ID(proj, meta.GlobalKey("does-not-exist"))},
+	} {
+		got, err := Live(ctx, m, tc.id)
+		if err != nil {
+			t.Errorf("Live(%v) = %v, want nil", tc.id, err)
+			continue
+		}
+		if diff := cmp.Diff(names(got), tc.want); diff != "" {
+			t.Errorf("Live(%v): -got,+want: %s", tc.id, diff)
+		}
+	}
+}
+
+func TestLiveListError(t *testing.T) {
+	ctx := context.Background()
+	listErr := errors.New("injected error")
+
+	for _, tc := range []struct {
+		name  string
+		id    *cloud.ResourceID
+		setup func(m *cloud.MockGCE)
+	}{
+		{
+			name:  "global UrlMap",
+			id:    urlmap.ID(proj, meta.GlobalKey("um")),
+			setup: func(m *cloud.MockGCE) { m.MockTargetGrpcProxies.ListError = &listErr },
+		},
+		{
+			name:  "regional UrlMap",
+			id:    urlmap.ID(proj, meta.RegionalKey("um", "us-central1")),
+			setup: func(m *cloud.MockGCE) { m.MockRegionTargetHttpsProxies.ListError = &listErr },
+		},
+		{
+			name:  "BackendService",
+			id:    backendservice.ID(proj, meta.GlobalKey("bs")),
+			setup: func(m *cloud.MockGCE) { m.MockUrlMaps.ListError = &listErr },
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			m := cloud.NewMockGCE(&cloud.SingleProjectRouter{ID: proj})
+			tc.setup(m)
+			if _, err := Live(ctx, m, tc.id); !errors.Is(err, listErr) {
+				t.Errorf("Live(%v) = %v, want %v", tc.id, err, listErr)
+			}
+		})
+	}
+}
+
+func TestDeleteRefCheck(t *testing.T) {
+	ctx := context.Background()
+	m := cloud.NewMockGCE(&cloud.SingleProjectRouter{ID: proj})
+	hcID := healthcheck.ID(proj, meta.GlobalKey("hc"))
+	m.HealthChecks().Insert(ctx, hcID.Key, &compute.HealthCheck{})
+	// BackendService that is not in the graph.
+	m.BackendServices().Insert(ctx, meta.GlobalKey("unmanaged"), &compute.BackendService{
+		HealthChecks: []string{link(hcID)},
+	})
+
+	g := &ez.Graph{Project: proj, Nodes: []ez.Node{{Name: "hc", Options: ez.DoesNotExist}}}
+
+	r, err := ensure.Do(ctx, m, g.Builder(), ensure.ExecutorOptions(exec.DeleteRefCheckOption(Live)))
+	if err == nil {
+		t.Fatal("ensure.Do() = nil, want error")
+	}
+	if len(r.Exec.Errors) != 1 {
+		t.Fatalf("r.Exec.Errors = %v, want 1 error", r.Exec.Errors)
+	}
+	var refErr *exec.ReferencedError
+	if !errors.As(r.Exec.Errors[0].Err, &refErr) {
+		t.Fatalf("r.Exec.Errors[0].Err = %v, want ReferencedError", r.Exec.Errors[0].Err)
+	}
+	if diff := cmp.Diff(names(refErr.Referrers), []string{"backendServices/unmanaged"}); diff != "" {
+		t.Errorf("Referrers: -got,+want: %s", diff)
+	}
+	if _, err := m.HealthChecks().Get(ctx, hcID.Key); err != nil {
+		t.Errorf("HealthChecks().Get() = %v, want nil (not deleted)", err)
+	}
+
+	// Without the check, the delete goes ahead.
+	if _, err := ensure.Do(ctx, m, g.Builder()); err != nil {
+		t.Fatalf("ensure.Do() = %v, want nil", err)
+	}
+}
@@ -31,6 +31,9 @@ func CreatePreconditions(want Node) (exec.EventList, error) {
 	var events exec.EventList
 	// Condition: references must exist before creation.
 	for _, ref := range outRefs {
+		if !ref.RequiredBeforeCreate() {
+			continue
+		}
 		events = append(events, exec.NewExistsEvent(ref.To))
 	}
 	return events, nil
@@ -77,8 +80,19 @@ func (a *genericCreateAction[GA, Alpha, Beta]) Run(
 	ctx context.Context,
 	c cloud.Cloud,
 ) (exec.EventList, error) {
+	cf := a.ops.CreateFuncs(c)
+	if exec.ValidateOnly(ctx) {
+		if cf.Options&CreateFuncsValidateOnly == 0 {
+			return a.DryRun(), nil
+		}
+		a.start = time.Now()
+		err := cf.Do(ctx, a.id, a.resource, cloud.ValidateOnly())
+		a.end = time.Now()
+		return exec.EventList{exec.NewExistsEvent(a.id)}, err
+	}
+
 	a.start = time.Now()
-	err := a.ops.CreateFuncs(c).Do(ctx, a.id, a.resource)
+	err := cf.Do(ctx, a.id, a.resource)
 	a.end = time.Now()
 
 	return exec.EventList{exec.NewExistsEvent(a.id)}, err
@@ -96,8 +110,12 @@ func (a *genericCreateAction[GA, Alpha, Beta]) String() string {
 
 func (a *genericCreateAction[GA, Alpha, Beta]) Metadata() *exec.ActionMetadata {
 	return &exec.ActionMetadata{
-		Name:    fmt.Sprintf("GenericCreateAction(%s)", a.id),
-		Type:    exec.ActionTypeCreate,
-		Summary: fmt.Sprintf("Create %s", a.id),
+		Name:       fmt.Sprintf("GenericCreateAction(%s)", a.id),
+		Type:       exec.ActionTypeCreate,
+		Summary:    fmt.Sprintf("Create %s", a.id),
+		ResourceID: a.id,
+		// Run() issues a validate-only Insert if the create funcs have
+		// CreateFuncsValidateOnly and falls back to DryRun() otherwise.
+		ValidateOnly: true,
 	}
 }
@@ -0,0 +1,116 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rnode
+
+import (
+	"context"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/api"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/exec"
+	alpha "google.golang.org/api/compute/v0.alpha"
+	beta "google.golang.org/api/compute/v0.beta"
+	"google.golang.org/api/compute/v1"
+)
+
+// securityPolicyOps creates beta SecurityPolicies, which accept
+// cloud.ValidateOnly().
+type securityPolicyOps struct {
+	options int
+}
+
+func (*securityPolicyOps) GetFuncs(gcp cloud.Cloud) *GetFuncs[compute.SecurityPolicy, alpha.SecurityPolicy, beta.SecurityPolicy] {
+	return &GetFuncs[compute.SecurityPolicy, alpha.SecurityPolicy, beta.SecurityPolicy]{
+		Beta: GetFuncsByScope[beta.SecurityPolicy]{Global: gcp.BetaSecurityPolicies().Get},
+	}
+}
+
+func (o *securityPolicyOps) CreateFuncs(gcp cloud.Cloud) *CreateFuncs[compute.SecurityPolicy, alpha.SecurityPolicy, beta.SecurityPolicy] {
+	return &CreateFuncs[compute.SecurityPolicy, alpha.SecurityPolicy, beta.SecurityPolicy]{
+		Beta:    CreateFuncsByScope[beta.SecurityPolicy]{Global: gcp.BetaSecurityPolicies().Insert},
+		Options: o.options,
+	}
+}
+
+func (*securityPolicyOps) UpdateFuncs(gcp cloud.Cloud) *UpdateFuncs[compute.SecurityPolicy, alpha.SecurityPolicy, beta.SecurityPolicy] {
+	return nil
+}
+
+func (*securityPolicyOps) DeleteFuncs(gcp cloud.Cloud) *DeleteFuncs[compute.SecurityPolicy, alpha.SecurityPolicy, beta.SecurityPolicy] {
+	return nil
+}
+
+func TestCreateActionValidateOnly(t *testing.T) {
+	ctx := context.Background()
+	id := &cloud.ResourceID{Resource: "securityPolicies", ProjectID: project, Key: meta.GlobalKey("sp")}
+	mr := api.NewResource[compute.SecurityPolicy, alpha.SecurityPolicy, beta.SecurityPolicy](
+		id, &api.BaseTypeTrait[compute.SecurityPolicy, alpha.SecurityPolicy, beta.SecurityPolicy]{})
+	// DisplayName is only in beta, so the resource is created with beta.
+	if err := mr.AccessBeta(func(x *beta.SecurityPolicy) { x.DisplayName = "sp" }); err != nil {
+		t.Fatalf("AccessBeta() = %v, want nil", err)
+	}
+	r, err := mr.Freeze()
+	if err != nil {
+		t.Fatalf("Freeze() = %v, want nil", err)
+	}
+
+	for _, tc := range []struct {
+		name        string
+		options     int
+		exists      bool
+		wantInserts int
+		wantErr     bool
+	}{
+		{name: "validate only", options: CreateFuncsValidateOnly, wantInserts: 1},
+		{name: "validate only rejected", options: CreateFuncsValidateOnly, exists: true, wantInserts: 1, wantErr: true},
+		{name: "not supported", wantInserts: 0},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			mock := cloud.NewMockGCE(&cloud.SingleProjectRouter{ID: project})
+			if tc.exists {
+				mock.MockBetaSecurityPolicies.Objects[*id.Key] = &cloud.MockSecurityPoliciesObj{Obj: &beta.SecurityPolicy{Name: "sp"}}
+			}
+			inserts := 0
+			mock.MockBetaSecurityPolicies.InsertHook = func(context.Context, *meta.Key, *beta.SecurityPolicy, *cloud.MockBetaSecurityPolicies, ...cloud.Option) (bool, error) {
+				inserts++
+				return false, nil
+			}
+			a := newGenericCreateAction[compute.SecurityPolicy, alpha.SecurityPolicy, beta.SecurityPolicy](
+				nil, &securityPolicyOps{options: tc.options}, id, r)
+
+			ex, err := exec.NewSerialExecutor(mock, []exec.Action{a}, exec.DryRunOption(true), exec.ValidateOnlyOption(true))
+			if err != nil {
+				t.Fatalf("NewSerialExecutor() = %v, want nil", err)
+			}
+			result, err := ex.Run(ctx)
+			if gotErr := err != nil; gotErr != tc.wantErr {
+				t.Fatalf("Run() = %+v, %v; gotErr = %t, want %t", result, err, gotErr, tc.wantErr)
+			}
+			if inserts != tc.wantInserts {
+				t.Errorf("Insert() calls = %d, want %d", inserts, tc.wantInserts)
+			}
+			if tc.exists {
+				return
+			}
+			if _, err := mock.BetaSecurityPolicies().Get(ctx, id.Key); err == nil {
+				t.Error("Get() = nil, want NotFound; the validate-only Insert created the resource")
+			}
+		})
+	}
+}
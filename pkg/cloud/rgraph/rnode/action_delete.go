@@ -35,6 +35,7 @@ func NewGenericDeleteAction[GA any, Alpha any, Beta any](
 		ops:        ops,
 		id:         got.ID(),
 		outRefs:    got.OutRefs(),
+		before:     got.Resource(),
 	}
 }
 
@@ -42,6 +43,9 @@ func DeletePreconditions(got, want Node) exec.EventList {
 	var ret exec.EventList
 	// Condition: no inRefs to the resource still exist.
 	for _, ref := range got.InRefs() {
+		if !ref.RequiredBeforeDelete() {
+			continue
+		}
 		ret = append(ret, exec.NewDropRefEvent(ref.From, ref.To))
 	}
 	return ret
@@ -61,6 +65,8 @@ type genericDeleteAction[GA any, Alpha any, Beta any] struct {
 	ops     GenericOps[GA, Alpha, Beta]
 	id      *cloud.ResourceID
 	outRefs []ResourceRef
+	// before is the resource at plan time for the ChangeLog.
+	before any
 
 	start, end time.Time
 }
@@ -70,7 +76,19 @@ func (a *genericDeleteAction[GA, Alpha, Beta]) Run(
 	c cloud.Cloud,
 ) (exec.EventList, error) {
 	a.start = time.Now()
+	if f := exec.DeleteRefCheck(ctx); f != nil {
+		refs, err := f(ctx, c, a.id)
+		if err != nil {
+			return nil, fmt.Errorf("GenericDeleteAction: checking referrers of %v: %w", a.id, err)
+		}
+		if len(refs) > 0 {
+			return nil, &exec.ReferencedError{ID: a.id, Referrers: refs}
+		}
+	}
 	err := a.ops.DeleteFuncs(c).Do(ctx, a.id)
+	if err == nil {
+		err = recordChange(ctx, a.Metadata(), a.id, a.before, nil)
+	}
 
 	var events exec.EventList
 	// Event: Node no longer exists.
@@ -96,8 +114,9 @@ func (a *genericDeleteAction[GA, Alpha, Beta]) String() string {
 
 func (a *genericDeleteAction[GA, Alpha, Beta]) Metadata() *exec.ActionMetadata {
 	return &exec.ActionMetadata{
-		Name:    fmt.Sprintf("GenericDeleteAction(%s)", a.id),
-		Type:    exec.ActionTypeDelete,
-		Summary: fmt.Sprintf("Delete %s", a.id),
+		Name:       fmt.Sprintf("GenericDeleteAction(%s)", a.id),
+		Type:       exec.ActionTypeDelete,
+		Summary:    fmt.Sprintf("Delete %s", a.id),
+		ResourceID: a.id,
 	}
 }
@@ -18,13 +18,17 @@ package rnode
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net/http"
 	"time"
 
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/api"
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/exec"
+	"google.golang.org/api/googleapi"
+	"k8s.io/klog/v2"
 )
 
 func UpdateActions[GA any, Alpha any, Beta any](
@@ -33,14 +37,14 @@ func UpdateActions[GA any, Alpha any, Beta any](
 	resource api.Resource[GA, Alpha, Beta],
 	fingerprint string,
 ) ([]exec.Action, error) {
-	preEvents, err := updatePreconditions(got, want)
+	preEvents, err := UpdatePreconditions(got, want)
 	if err != nil {
 		return nil, err
 	}
-	postEvents := postUpdateActionEvents(got, want)
-	return []exec.Action{
-		newGenericUpdateAction(preEvents, ops, want.ID(), resource, postEvents, fingerprint),
-	}, nil
+	postEvents := PostUpdateActionEvents(got, want)
+	act := newGenericUpdateAction(preEvents, ops, want.ID(), resource, postEvents, fingerprint)
+	act.before = got.Resource()
+	return []exec.Action{act}, nil
 }
 
 func newGenericUpdateAction[GA any, Alpha any, Beta any](
@@ -68,6 +72,8 @@ type genericUpdateAction[GA any, Alpha any, Beta any] struct {
 	resource    api.Resource[GA, Alpha, Beta]
 	postEvents  exec.EventList
 	fingerprint string
+	// before is the resource at plan time for the ChangeLog.
+	before any
 
 	start, end time.Time
 }
@@ -77,13 +83,81 @@ func (a *genericUpdateAction[GA, Alpha, Beta]) Run(
 	c cloud.Cloud,
 ) (exec.EventList, error) {
 	a.start = time.Now()
-	err := a.ops.UpdateFuncs(c).Do(ctx, a.fingerprint, a.id, a.resource)
-	a.end = time.Now()
+	defer func() { a.end = time.Now() }()
+
+	if exec.SkipUnchanged(ctx) {
+		unchanged, err := a.unchanged(ctx, c)
+		if err != nil {
+			return nil, err
+		}
+		if unchanged {
+			klog.FromContext(ctx).V(2).Info("Skipping update, resource unchanged", "id", a.id)
+			return a.postEvents, nil
+		}
+	}
+
+	uf := a.ops.UpdateFuncs(c)
+	strict := exec.StrictFingerprint(ctx) && uf.Options&UpdateFuncsNoFingerprint == 0
+	if strict {
+		if err := a.checkFingerprint(ctx, c); err != nil {
+			return nil, err
+		}
+	}
+	err := uf.Do(ctx, a.fingerprint, a.id, a.resource)
+	if strict && isPreconditionFailed(err) {
+		return nil, &exec.ConflictError{ID: a.id, Want: a.fingerprint, Err: err}
+	}
+	if r := exec.ReadYourWritesConfig(ctx); err == nil && r != nil && a.fingerprint != "" && uf.Options&UpdateFuncsNoFingerprint == 0 {
+		// Wait for the update to be visible so that the events signaled
+		// reflect the post-write state.
+		err = WaitForWrite(ctx, c, a.ops, a.id, a.resource.Version(), *r, func(fp string) bool { return fp != a.fingerprint })
+	}
+	if err == nil {
+		err = recordChange(ctx, a.Metadata(), a.id, a.before, a.resource)
+	}
 
 	// Emit DropReference events for removed references.
 	return a.postEvents, err
 }
 
+// checkFingerprint returns a ConflictError if the live fingerprint of the
+// resource differs from the fingerprint observed at plan time.
+func (a *genericUpdateAction[GA, Alpha, Beta]) checkFingerprint(ctx context.Context, c cloud.Cloud) error {
+	if a.fingerprint == "" {
+		return fmt.Errorf("genericUpdateAction: update of %v does not carry a fingerprint", a.id)
+	}
+	got, err := getFingerprint(ctx, c, a.ops, a.id, a.resource.Version())
+	if err != nil {
+		return err
+	}
+	if got != a.fingerprint {
+		return &exec.ConflictError{ID: a.id, Want: a.fingerprint, Got: got}
+	}
+	return nil
+}
+
+// unchanged returns true if the live resource already matches the resource
+// to write, e.g. another reconciler made the same change after the plan.
+func (a *genericUpdateAction[GA, Alpha, Beta]) unchanged(ctx context.Context, c cloud.Cloud) (bool, error) {
+	gf := a.ops.GetFuncs(c)
+	// The TypeTrait of a.resource is used for the Diff so the live resource
+	// does not need one.
+	live, err := gf.Do(ctx, a.resource.Version(), a.id, nil)
+	if err != nil {
+		return false, err
+	}
+	diff, err := a.resource.Diff(live)
+	if err != nil {
+		return false, err
+	}
+	return !diff.HasDiff(), nil
+}
+
+func isPreconditionFailed(err error) bool {
+	var gerr *googleapi.Error
+	return errors.As(err, &gerr) && gerr.Code == http.StatusPreconditionFailed
+}
+
 func (a *genericUpdateAction[GA, Alpha, Beta]) DryRun() exec.EventList {
 	// Emit DropReference events for removed references.
 	return a.postEvents
@@ -95,13 +169,16 @@ func (a *genericUpdateAction[GA, Alpha, Beta]) String() string {
 
 func (a *genericUpdateAction[GA, Alpha, Beta]) Metadata() *exec.ActionMetadata {
 	return &exec.ActionMetadata{
-		Name:    fmt.Sprintf("GenericUpdateAction(%s)", a.id),
-		Type:    exec.ActionTypeUpdate,
-		Summary: fmt.Sprintf("Update %s", a.id),
+		Name:       fmt.Sprintf("GenericUpdateAction(%s)", a.id),
+		Type:       exec.ActionTypeUpdate,
+		Summary:    fmt.Sprintf("Update %s", a.id),
+		ResourceID: a.id,
 	}
 }
 
-func updatePreconditions(got, want Node) (exec.EventList, error) {
+// UpdatePreconditions returns the events that must be signalled before the
+// resource can be updated.
+func UpdatePreconditions(got, want Node) (exec.EventList, error) {
 	// Update can only occur if the resource Exists TODO: is there a case where
 	// the ambient signal for existance from Update op collides with a
 	// reference to it?
@@ -113,12 +190,17 @@ func updatePreconditions(got, want Node) (exec.EventList, error) {
 	var events exec.EventList
 	// Condition: references must exist before update.
 	for _, ref := range outRefs {
+		if !ref.RequiredBeforeCreate() {
+			continue
+		}
 		events = append(events, exec.NewExistsEvent(ref.To))
 	}
 	return events, nil
 }
 
-func postUpdateActionEvents(got, want Node) exec.EventList {
+// PostUpdateActionEvents returns the events signalled after the update: the
+// references dropped by the update and the existence of the resource.
+func PostUpdateActionEvents(got, want Node) exec.EventList {
 	wantOutRefs := want.OutRefs()
 	gotOutRefs := got.OutRefs()
 
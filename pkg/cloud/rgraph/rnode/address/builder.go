@@ -70,12 +70,19 @@ func (b *builder) OutRefs() ([]rnode.ResourceRef, error) {
 	return nil, nil
 }
 
+func (b *builder) Clone() rnode.Builder {
+	return &builder{
+		BuilderBase: b.CopyBase(),
+		resource:    rnode.CloneResource(b.resource),
+	}
+}
+
 func (b *builder) Build() (rnode.Node, error) {
 	if b.State() == rnode.NodeExists && b.resource == nil {
 		return nil, fmt.Errorf("Address %s resource is nil with state %s", b.ID(), b.State())
 	}
 
-	ret := &addressNode{resource: b.resource}
+	ret := &addressNode{resource: rnode.CloneResource(b.resource)}
 	if err := ret.InitFromBuilder(b); err != nil {
 		return nil, err
 	}
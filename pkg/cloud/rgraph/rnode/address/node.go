@@ -88,6 +88,6 @@ func (n *addressNode) Actions(got rnode.Node) ([]exec.Action, error) {
 
 func (n *addressNode) Builder() rnode.Builder {
 	b := &builder{}
-	b.Init(n.ID(), n.State(), n.Ownership(), n.resource)
+	b.InitFromNode(n)
 	return b
 }
@@ -18,40 +18,122 @@ package all
 
 import (
 	"fmt"
+	"sync"
 
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode"
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/address"
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/backendservice"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/endpointpolicy"
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/fake"
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/forwardingrule"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/genericnode"
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/healthcheck"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/instance"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/interconnectattachment"
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/networkendpointgroup"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/router"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/servicebinding"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/servicedirectoryservice"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/targetgrpcproxy"
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/targethttpproxy"
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/tcproute"
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/urlmap"
 )
 
+var (
+	registryLock sync.RWMutex
+	registry     = map[string]func(*cloud.ResourceID) rnode.Builder{}
+)
+
+// Register a Builder constructor for a custom resource type, i.e. a type
+// implemented outside of this library (see the rnode package
+// documentation). resource is the ResourceID.Resource of the type. Once
+// registered, NewBuilderByID (and the graph algorithms that use it, e.g.
+// trclosure) can create Builders for the type.
+//
+// Register panics if resource is already registered or is a type built into
+// this library. It is usually called from an init() function.
+func Register(resource string, newBuilder func(*cloud.ResourceID) rnode.Builder) {
+	if builtinBuilder(&cloud.ResourceID{Resource: resource}) != nil {
+		panic(fmt.Sprintf("all.Register: %q is a builtin type", resource))
+	}
+	registryLock.Lock()
+	defer registryLock.Unlock()
+	if _, ok := registry[resource]; ok {
+		panic(fmt.Sprintf("all.Register: %q is already registered", resource))
+	}
+	registry[resource] = newBuilder
+}
+
+// NewBuilderByID returns a Builder for the type of resource of id. Custom
+// types must be added with Register.
 func NewBuilderByID(id *cloud.ResourceID) (rnode.Builder, error) {
+	if b := builtinBuilder(id); b != nil {
+		return b, nil
+	}
+	registryLock.RLock()
+	newBuilder, ok := registry[id.Resource]
+	registryLock.RUnlock()
+	if ok {
+		return newBuilder(id), nil
+	}
+	return nil, fmt.Errorf("NewBuilderByID: invalid Resource %q", id.Resource)
+}
+
+// builtinBuilder returns a Builder for the types in this library or nil.
+func builtinBuilder(id *cloud.ResourceID) rnode.Builder {
 	switch id.Resource {
 	case "addresses":
-		return address.NewBuilder(id), nil
+		return address.NewBuilder(id)
 	case "backendServices":
-		return backendservice.NewBuilder(id), nil
+		return backendservice.NewBuilder(id)
 	case "fakes":
-		return fake.NewBuilder(id), nil
+		return fake.NewBuilder(id)
 	case "forwardingRules":
-		return forwardingrule.NewBuilder(id), nil
+		return forwardingrule.NewBuilder(id)
 	case "healthChecks":
-		return healthcheck.NewBuilder(id), nil
+		return healthcheck.NewBuilder(id)
+	case "instances":
+		return instance.NewBuilder(id)
+	case "interconnectAttachments":
+		return interconnectattachment.NewBuilder(id)
 	case "networkEndpointGroups":
-		return networkendpointgroup.NewBuilder(id), nil
+		return networkendpointgroup.NewBuilder(id)
+	case "routers":
+		return router.NewBuilder(id)
+	case "targetGrpcProxies":
+		return targetgrpcproxy.NewBuilder(id)
 	case "targetHttpProxies":
-		return targethttpproxy.NewBuilder(id), nil
+		return targethttpproxy.NewBuilder(id)
 	case "urlMaps":
-		return urlmap.NewBuilder(id), nil
-	case "tcpRoute":
-		return tcproute.NewBuilder(id), nil
+		return urlmap.NewBuilder(id)
+	case "tcpRoutes":
+		return tcproute.NewBuilder(id)
+	case "serviceBindings":
+		return servicebinding.NewBuilder(id)
+	case "services":
+		return servicedirectoryservice.NewBuilder(id)
+	case "endpointPolicies":
+		return endpointpolicy.NewBuilder(id)
 	}
-	return nil, fmt.Errorf("NewBuilderByID: invalid Resource %q", id.Resource)
+	return nil
+}
+
+// NewBuilderForRef returns a Builder for the target of a reference. This is
+// the same as NewBuilderByID except that a genericnode Builder is returned
+// for the types that do not have a Node and for rnode.UnresolvedIDs, so that
+// references to unmodeled resources only have their existence checked.
+func NewBuilderForRef(id *cloud.ResourceID) (rnode.Builder, error) {
+	if rnode.IsUnresolved(id) {
+		return genericnode.NewBuilder(id), nil
+	}
+	b, err := NewBuilderByID(id)
+	if err == nil {
+		return b, nil
+	}
+	if id.Resource == "" || id.Key == nil {
+		return nil, err
+	}
+	return genericnode.NewBuilder(id), nil
 }
@@ -26,7 +26,11 @@ import (
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/backendservice"
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/forwardingrule"
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/healthcheck"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/instance"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/interconnectattachment"
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/networkendpointgroup"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/router"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/targetgrpcproxy"
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/targethttpproxy"
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/tcproute"
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/urlmap"
@@ -85,9 +89,17 @@ func (b *ResourceBuilder) Address() *AddressBuilder               { return &Addr
 func (b *ResourceBuilder) BackendService() *BackendServiceBuilder { return &BackendServiceBuilder{*b} }
 func (b *ResourceBuilder) ForwardingRule() *ForwardingRuleBuilder { return &ForwardingRuleBuilder{*b} }
 func (b *ResourceBuilder) HealthCheck() *HealthCheckBuilder       { return &HealthCheckBuilder{*b} }
+func (b *ResourceBuilder) Instance() *InstanceBuilder             { return &InstanceBuilder{*b} }
+func (b *ResourceBuilder) InterconnectAttachment() *InterconnectAttachmentBuilder {
+	return &InterconnectAttachmentBuilder{*b}
+}
 func (b *ResourceBuilder) NetworkEndpointGroup() *NetworkEndpointGroupBuilder {
 	return &NetworkEndpointGroupBuilder{*b}
 }
+func (b *ResourceBuilder) Router() *RouterBuilder { return &RouterBuilder{*b} }
+func (b *ResourceBuilder) TargetGrpcProxy() *TargetGrpcProxyBuilder {
+	return &TargetGrpcProxyBuilder{*b}
+}
 func (b *ResourceBuilder) TargetHttpProxy() *TargetHttpProxyBuilder {
 	return &TargetHttpProxyBuilder{*b}
 }
@@ -177,6 +189,50 @@ func (b *HealthCheckBuilder) Build(f func(*compute.HealthCheck)) rnode.Builder {
 	return nb
 }
 
+type InstanceBuilder struct{ ResourceBuilder }
+
+func (b *InstanceBuilder) ID() *cloud.ResourceID { return instance.ID(b.Project, b.Key()) }
+func (b *InstanceBuilder) SelfLink() string      { return b.ID().SelfLink(meta.VersionGA) }
+func (b *InstanceBuilder) Resource() instance.MutableInstance {
+	return instance.NewMutableInstance(b.Project, b.Key())
+}
+
+// Build returns an Instance node builder. Instances are always
+// OwnershipExternal.
+func (b *InstanceBuilder) Build(f func(*compute.Instance)) rnode.Builder {
+	m := b.Resource()
+	if f != nil {
+		m.Access(f)
+	}
+	r, _ := m.Freeze()
+	nb := instance.NewBuilderWithResource(r)
+	nb.SetState(rnode.NodeExists)
+	return nb
+}
+
+type InterconnectAttachmentBuilder struct{ ResourceBuilder }
+
+func (b *InterconnectAttachmentBuilder) ID() *cloud.ResourceID {
+	return interconnectattachment.ID(b.Project, b.Key())
+}
+func (b *InterconnectAttachmentBuilder) SelfLink() string { return b.ID().SelfLink(meta.VersionGA) }
+func (b *InterconnectAttachmentBuilder) Resource() interconnectattachment.MutableInterconnectAttachment {
+	return interconnectattachment.NewMutableInterconnectAttachment(b.Project, b.Key())
+}
+
+// Build returns an InterconnectAttachment node builder. InterconnectAttachments
+// are always OwnershipExternal.
+func (b *InterconnectAttachmentBuilder) Build(f func(*compute.InterconnectAttachment)) rnode.Builder {
+	m := b.Resource()
+	if f != nil {
+		m.Access(f)
+	}
+	r, _ := m.Freeze()
+	nb := interconnectattachment.NewBuilderWithResource(r)
+	nb.SetState(rnode.NodeExists)
+	return nb
+}
+
 type NetworkEndpointGroupBuilder struct{ ResourceBuilder }
 
 func (b *NetworkEndpointGroupBuilder) ID() *cloud.ResourceID {
@@ -199,6 +255,50 @@ func (b *NetworkEndpointGroupBuilder) Build(f func(*compute.NetworkEndpointGroup
 	return nb
 }
 
+type RouterBuilder struct{ ResourceBuilder }
+
+func (b *RouterBuilder) ID() *cloud.ResourceID {
+	return router.ID(b.Project, b.Key())
+}
+func (b *RouterBuilder) SelfLink() string { return b.ID().SelfLink(meta.VersionGA) }
+func (b *RouterBuilder) Resource() router.MutableRouter {
+	return router.NewMutableRouter(b.Project, b.Key())
+}
+
+// Build returns a Router node builder. Routers are always OwnershipExternal.
+func (b *RouterBuilder) Build(f func(*compute.Router)) rnode.Builder {
+	m := b.Resource()
+	if f != nil {
+		m.Access(f)
+	}
+	r, _ := m.Freeze()
+	nb := router.NewBuilderWithResource(r)
+	nb.SetState(rnode.NodeExists)
+	return nb
+}
+
+type TargetGrpcProxyBuilder struct{ ResourceBuilder }
+
+func (b *TargetGrpcProxyBuilder) ID() *cloud.ResourceID {
+	return targetgrpcproxy.ID(b.Project, b.Key())
+}
+func (b *TargetGrpcProxyBuilder) SelfLink() string { return b.ID().SelfLink(meta.VersionGA) }
+func (b *TargetGrpcProxyBuilder) Resource() targetgrpcproxy.MutableTargetGrpcProxy {
+	return targetgrpcproxy.NewMutableTargetGrpcProxy(b.Project, b.Key())
+}
+
+func (b *TargetGrpcProxyBuilder) Build(f func(*compute.TargetGrpcProxy)) rnode.Builder {
+	m := b.Resource()
+	if f != nil {
+		m.Access(f)
+	}
+	r, _ := m.Freeze()
+	nb := targetgrpcproxy.NewBuilderWithResource(r)
+	nb.SetOwnership(rnode.OwnershipManaged)
+	nb.SetState(rnode.NodeExists)
+	return nb
+}
+
 type TargetHttpProxyBuilder struct{ ResourceBuilder }
 
 func (b *TargetHttpProxyBuilder) ID() *cloud.ResourceID {
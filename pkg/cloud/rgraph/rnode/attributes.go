@@ -61,4 +61,53 @@ type ResourceRef struct {
 	Path api.Path
 	// To is the resource that is referenced.
 	To *cloud.ResourceID
+	// Edge is the ordering imposed by the reference. The default orders
+	// both creation and deletion.
+	Edge EdgeSemantics
 }
+
+// RequiredBeforeCreate is true if To must exist before From is created or
+// updated.
+func (r ResourceRef) RequiredBeforeCreate() bool {
+	return r.Edge == EdgeDefault || r.Edge == EdgeRequiredBeforeCreate
+}
+
+// RequiredBeforeDelete is true if From must drop the reference before To
+// is deleted.
+func (r ResourceRef) RequiredBeforeDelete() bool {
+	return r.Edge == EdgeDefault || r.Edge == EdgeRequiredBeforeDelete
+}
+
+// EdgeSemantics determines which action orderings a ResourceRef imposes.
+type EdgeSemantics string
+
+const (
+	// EdgeDefault requires To to exist before From is created and the
+	// reference to be dropped before To is deleted.
+	EdgeDefault EdgeSemantics = ""
+	// EdgeRequiredBeforeCreate only orders creation: To must exist before
+	// From is created. Deletion is not ordered.
+	EdgeRequiredBeforeCreate EdgeSemantics = "RequiredBeforeCreate"
+	// EdgeRequiredBeforeDelete only orders deletion: From must drop the
+	// reference before To is deleted. From may be created before To exists,
+	// e.g. for an optional reference such as a backup target.
+	EdgeRequiredBeforeDelete EdgeSemantics = "RequiredBeforeDelete"
+)
+
+// ConflictStrategy determines what the planner does when the resource in the
+// Cloud was changed outside of the graph, i.e. fields that are set in both
+// got and want have different values.
+type ConflictStrategy string
+
+const (
+	// ConflictDefault uses the strategy configured for the plan.
+	ConflictDefault ConflictStrategy = ""
+	// ConflictOverwrite updates the resource to match want, discarding
+	// external changes. This is the default for a plan.
+	ConflictOverwrite ConflictStrategy = "Overwrite"
+	// ConflictPreserveExternal keeps the values of fields that were changed
+	// externally and only updates the rest of the resource.
+	ConflictPreserveExternal ConflictStrategy = "PreserveExternal"
+	// ConflictFail causes planning to fail.
+	ConflictFail ConflictStrategy = "Fail"
+)
@@ -0,0 +1,74 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rnode
+
+import (
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/exec"
+)
+
+func TestEdgeSemantics(t *testing.T) {
+	fnID := globalID("fn")
+	ref := func(to string, edge EdgeSemantics) ResourceRef {
+		return ResourceRef{From: fnID, To: globalID(to), Edge: edge}
+	}
+	fn := &fakeNode{}
+	fn.ownership = OwnershipManaged
+	fn.state = NodeExists
+	fn.id = fnID
+	fn.outRefs = []ResourceRef{
+		ref("a", EdgeDefault),
+		ref("b", EdgeRequiredBeforeCreate),
+		ref("c", EdgeRequiredBeforeDelete),
+	}
+	fn.inRefs = []ResourceRef{
+		{From: globalID("x"), To: fnID},
+		{From: globalID("y"), To: fnID, Edge: EdgeRequiredBeforeCreate},
+		{From: globalID("z"), To: fnID, Edge: EdgeRequiredBeforeDelete},
+	}
+
+	checkEvents := func(t *testing.T, name string, got, want exec.EventList) {
+		t.Helper()
+		if len(got) != len(want) {
+			t.Fatalf("%s = %v, want %v", name, got, want)
+		}
+		for i := range got {
+			if !got[i].Equal(want[i]) {
+				t.Errorf("%s[%d] = %v, want %v", name, i, got[i], want[i])
+			}
+		}
+	}
+
+	wantExists := newExistsEventList("a", "b")
+	got, err := CreatePreconditions(fn)
+	if err != nil {
+		t.Fatalf("CreatePreconditions() = %v, want nil", err)
+	}
+	checkEvents(t, "CreatePreconditions()", got, wantExists)
+
+	got, err = UpdatePreconditions(fn, fn)
+	if err != nil {
+		t.Fatalf("UpdatePreconditions() = %v, want nil", err)
+	}
+	checkEvents(t, "UpdatePreconditions()", got, wantExists)
+
+	checkEvents(t, "DeletePreconditions()", DeletePreconditions(fn, nil), exec.EventList{
+		exec.NewDropRefEvent(globalID("x"), fnID),
+		exec.NewDropRefEvent(globalID("z"), fnID),
+	})
+}
@@ -17,8 +17,12 @@ limitations under the License.
 package backendservice
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"net/http"
 	"testing"
 
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
@@ -31,6 +35,7 @@ import (
 	alpha "google.golang.org/api/compute/v0.alpha"
 	beta "google.golang.org/api/compute/v0.beta"
 	"google.golang.org/api/compute/v1"
+	"google.golang.org/api/googleapi"
 )
 
 const (
@@ -218,6 +223,287 @@ func TestActionUpdate(t *testing.T) {
 	}
 }
 
+func TestActionUpdateStrictFingerprint(t *testing.T) {
+	for _, tc := range []struct {
+		desc            string
+		liveFingerprint string
+		updateErr       error
+		wantConflict    bool
+		wantUpdate      bool
+	}{
+		{
+			desc:            "fingerprint matches",
+			liveFingerprint: fingerprintStr,
+			wantUpdate:      true,
+		},
+		{
+			desc:            "fingerprint changed",
+			liveFingerprint: "changed",
+			wantConflict:    true,
+		},
+		{
+			desc:            "server precondition failed",
+			liveFingerprint: fingerprintStr,
+			updateErr:       &googleapi.Error{Code: http.StatusPreconditionFailed},
+			wantConflict:    true,
+			wantUpdate:      true,
+		},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			ctx := context.Background()
+			gotNode, err := createBackendServiceNode("bs-name", func(m MutableBackendService) error {
+				return m.Access(func(x *compute.BackendService) {
+					x.LoadBalancingScheme = "INTERNAL_SELF_MANAGED"
+					x.Protocol = "TCP"
+					x.Port = 80
+					x.CompressionMode = "DISABLED"
+					x.ConnectionDraining = &compute.ConnectionDraining{}
+					x.SessionAffinity = "NONE"
+					x.TimeoutSec = 30
+				})
+			})
+			if err != nil {
+				t.Fatalf("createBackendServiceNode(bs-name, _) = %v, want nil", err)
+			}
+			actions, err := rnode.UpdateActions[compute.BackendService, alpha.BackendService, beta.BackendService](&ops{}, gotNode, gotNode, gotNode.resource, fingerprintStr)
+			if err != nil {
+				t.Fatalf("rnode.UpdateActions[]() = %v, want nil", err)
+			}
+
+			mockCloud := cloud.NewMockGCE(&cloud.SingleProjectRouter{ID: proj})
+			key := meta.GlobalKey("bs-name")
+			if err := mockCloud.BackendServices().Insert(ctx, key, &compute.BackendService{Name: "bs-name", Fingerprint: tc.liveFingerprint}); err != nil {
+				t.Fatalf("Insert() = %v, want nil", err)
+			}
+			var updated bool
+			mockCloud.MockBackendServices.UpdateHook = func(context.Context, *meta.Key, *compute.BackendService, *cloud.MockBackendServices, ...cloud.Option) error {
+				updated = true
+				return tc.updateErr
+			}
+
+			_, err = actions[0].Run(exec.WithStrictFingerprint(ctx), mockCloud)
+			var cerr *exec.ConflictError
+			if gotConflict := errors.As(err, &cerr); gotConflict != tc.wantConflict {
+				t.Errorf("Run() = %v; got conflict %t, want %t", err, gotConflict, tc.wantConflict)
+			}
+			if !tc.wantConflict && err != nil {
+				t.Errorf("Run() = %v, want nil", err)
+			}
+			if updated != tc.wantUpdate {
+				t.Errorf("updated = %t, want %t", updated, tc.wantUpdate)
+			}
+		})
+	}
+}
+
+func TestActionUpdateReadYourWrites(t *testing.T) {
+	for _, tc := range []struct {
+		desc      string
+		staleGets int
+		wantErr   bool
+		wantNGets int
+	}{
+		{desc: "visible immediately", staleGets: 0, wantNGets: 1},
+		{desc: "visible after retries", staleGets: 2, wantNGets: 3},
+		{desc: "never visible", staleGets: 10, wantErr: true, wantNGets: 3},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			ctx := context.Background()
+			gotNode, err := createBackendServiceNode("bs-name", func(m MutableBackendService) error {
+				return m.Access(func(x *compute.BackendService) {
+					x.LoadBalancingScheme = "INTERNAL_SELF_MANAGED"
+					x.Protocol = "TCP"
+					x.Port = 80
+					x.CompressionMode = "DISABLED"
+					x.ConnectionDraining = &compute.ConnectionDraining{}
+					x.SessionAffinity = "NONE"
+					x.TimeoutSec = 30
+				})
+			})
+			if err != nil {
+				t.Fatalf("createBackendServiceNode(bs-name, _) = %v, want nil", err)
+			}
+			actions, err := rnode.UpdateActions[compute.BackendService, alpha.BackendService, beta.BackendService](&ops{}, gotNode, gotNode, gotNode.resource, fingerprintStr)
+			if err != nil {
+				t.Fatalf("rnode.UpdateActions[]() = %v, want nil", err)
+			}
+
+			mockCloud := cloud.NewMockGCE(&cloud.SingleProjectRouter{ID: proj})
+			mockCloud.MockBackendServices.UpdateHook = func(context.Context, *meta.Key, *compute.BackendService, *cloud.MockBackendServices, ...cloud.Option) error {
+				return nil
+			}
+			var nGets int
+			mockCloud.MockBackendServices.GetHook = func(context.Context, *meta.Key, *cloud.MockBackendServices, ...cloud.Option) (bool, *compute.BackendService, error) {
+				nGets++
+				fp := "new"
+				if nGets <= tc.staleGets {
+					fp = fingerprintStr
+				}
+				return true, &compute.BackendService{Name: "bs-name", Fingerprint: fp}, nil
+			}
+
+			r := exec.ReadYourWrites{Attempts: 3}
+			_, err = actions[0].Run(exec.WithReadYourWrites(ctx, r), mockCloud)
+			var serr *rnode.StaleReadError
+			if gotErr := errors.As(err, &serr); gotErr != tc.wantErr {
+				t.Errorf("Run() = %v; got StaleReadError %t, want %t", err, gotErr, tc.wantErr)
+			}
+			if nGets != tc.wantNGets {
+				t.Errorf("nGets = %d, want %d", nGets, tc.wantNGets)
+			}
+		})
+	}
+}
+
+func TestActionUpdateSkipUnchanged(t *testing.T) {
+	for _, tc := range []struct {
+		desc       string
+		liveMod    func(x *compute.BackendService)
+		wantUpdate bool
+	}{
+		{
+			desc:    "live matches want",
+			liveMod: func(x *compute.BackendService) {},
+		},
+		{
+			desc:       "live differs",
+			liveMod:    func(x *compute.BackendService) { x.TimeoutSec = 60 },
+			wantUpdate: true,
+		},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			ctx := context.Background()
+			wantNode, err := createBackendServiceNode("bs-name", func(m MutableBackendService) error {
+				return m.Access(func(x *compute.BackendService) {
+					x.LoadBalancingScheme = "INTERNAL_SELF_MANAGED"
+					x.Protocol = "TCP"
+					x.Port = 80
+					x.CompressionMode = "DISABLED"
+					x.ConnectionDraining = &compute.ConnectionDraining{}
+					x.SessionAffinity = "NONE"
+					x.TimeoutSec = 30
+				})
+			})
+			if err != nil {
+				t.Fatalf("createBackendServiceNode(bs-name, _) = %v, want nil", err)
+			}
+			actions, err := rnode.UpdateActions[compute.BackendService, alpha.BackendService, beta.BackendService](&ops{}, wantNode, wantNode, wantNode.resource, fingerprintStr)
+			if err != nil {
+				t.Fatalf("rnode.UpdateActions[]() = %v, want nil", err)
+			}
+
+			mockCloud := cloud.NewMockGCE(&cloud.SingleProjectRouter{ID: proj})
+			live, err := wantNode.resource.Clone().ToGA()
+			if err != nil {
+				t.Fatalf("ToGA() = %v, want nil", err)
+			}
+			live.Fingerprint = fingerprintStr
+			tc.liveMod(live)
+			if err := mockCloud.BackendServices().Insert(ctx, meta.GlobalKey("bs-name"), live); err != nil {
+				t.Fatalf("Insert() = %v, want nil", err)
+			}
+			var updated bool
+			mockCloud.MockBackendServices.UpdateHook = func(context.Context, *meta.Key, *compute.BackendService, *cloud.MockBackendServices, ...cloud.Option) error {
+				updated = true
+				return nil
+			}
+
+			events, err := actions[0].Run(exec.WithSkipUnchanged(ctx), mockCloud)
+			if err != nil {
+				t.Fatalf("Run() = %v, want nil", err)
+			}
+			if updated != tc.wantUpdate {
+				t.Errorf("updated = %t, want %t", updated, tc.wantUpdate)
+			}
+			wantEvent := exec.NewExistsEvent(wantNode.ID())
+			var found bool
+			for _, ev := range events {
+				found = found || ev.Equal(wantEvent)
+			}
+			if !found {
+				t.Errorf("Run() events = %v, want %v", events, wantEvent)
+			}
+		})
+	}
+}
+
+func TestActionChangeLog(t *testing.T) {
+	ctx := context.Background()
+	setup := func(timeout int64) func(m MutableBackendService) error {
+		return func(m MutableBackendService) error {
+			return m.Access(func(x *compute.BackendService) {
+				x.LoadBalancingScheme = "INTERNAL_SELF_MANAGED"
+				x.Protocol = "TCP"
+				x.Port = 80
+				x.CompressionMode = "DISABLED"
+				x.ConnectionDraining = &compute.ConnectionDraining{}
+				x.SessionAffinity = "NONE"
+				x.TimeoutSec = timeout
+			})
+		}
+	}
+	gotNode, err := createBackendServiceNode("bs-name", setup(30))
+	if err != nil {
+		t.Fatalf("createBackendServiceNode(bs-name, _) = %v, want nil", err)
+	}
+	wantNode, err := createBackendServiceNode("bs-name", setup(60))
+	if err != nil {
+		t.Fatalf("createBackendServiceNode(bs-name, _) = %v, want nil", err)
+	}
+	updates, err := rnode.UpdateActions[compute.BackendService, alpha.BackendService, beta.BackendService](&ops{}, gotNode, wantNode, wantNode.resource, fingerprintStr)
+	if err != nil {
+		t.Fatalf("rnode.UpdateActions[]() = %v, want nil", err)
+	}
+	deletes, err := rnode.DeleteActions[compute.BackendService, alpha.BackendService, beta.BackendService](&ops{}, wantNode, wantNode)
+	if err != nil {
+		t.Fatalf("rnode.DeleteActions[]() = %v, want nil", err)
+	}
+
+	mockCloud := cloud.NewMockGCE(&cloud.SingleProjectRouter{ID: proj})
+	mockCloud.MockBackendServices.Objects[*gotNode.ID().Key] = mockCloud.MockBackendServices.Obj(&compute.BackendService{Name: "bs-name"})
+	mockCloud.MockBackendServices.UpdateHook = func(context.Context, *meta.Key, *compute.BackendService, *cloud.MockBackendServices, ...cloud.Option) error {
+		return nil
+	}
+
+	var buf bytes.Buffer
+	ctx = exec.WithChangeLog(ctx, exec.NewJSONChangeLog(&buf))
+	for _, a := range []exec.Action{updates[0], deletes[0]} {
+		if _, err := a.Run(ctx, mockCloud); err != nil {
+			t.Fatalf("%v.Run() = %v, want nil", a, err)
+		}
+	}
+
+	dec := json.NewDecoder(&buf)
+	var records []map[string]any
+	for dec.More() {
+		var r map[string]any
+		if err := dec.Decode(&r); err != nil {
+			t.Fatalf("Decode() = %v", err)
+		}
+		records = append(records, r)
+	}
+	if len(records) != 2 {
+		t.Fatalf("len(records) = %d, want 2", len(records))
+	}
+	timeout := func(v any) any {
+		m, _ := v.(map[string]any)
+		return m["timeoutSec"]
+	}
+	update, del := records[0], records[1]
+	if update["type"] != string(exec.ActionTypeUpdate) || timeout(update["before"]) != 30.0 || timeout(update["after"]) != 60.0 {
+		t.Errorf("update record = %v, want timeoutSec 30 -> 60", update)
+	}
+	if before, _ := update["before"].(map[string]any); before["fingerprint"] != nil {
+		t.Errorf("update record before = %v, want no OutputOnly fields", before)
+	}
+	if del["type"] != string(exec.ActionTypeDelete) || timeout(del["before"]) != 60.0 || del["after"] != nil {
+		t.Errorf("delete record = %v, want before timeoutSec 60 and no after", del)
+	}
+	if del["resource"] != gotNode.ID().String() {
+		t.Errorf("delete record resource = %v, want %v", del["resource"], gotNode.ID())
+	}
+}
+
 func TestBackendServiceDiff(t *testing.T) {
 	bsName := "bs-name"
 	for _, tc := range []struct {
@@ -1145,3 +1431,139 @@ func TestOutRefs(t *testing.T) {
 		})
 	}
 }
+
+func TestVersionFieldTraits(t *testing.T) {
+	bsID := ID(proj, meta.GlobalKey("bs-test"))
+
+	// IpAddressSelectionPolicy is required at alpha as well as beta.
+	m := NewMutableBackendService(proj, bsID.Key)
+	err := m.AccessAlpha(func(x *alpha.BackendService) {
+		x.LoadBalancingScheme = "INTERNAL_SELF_MANAGED"
+		x.Protocol = "TCP"
+		x.CompressionMode = "DISABLED"
+		x.ConnectionDraining = &alpha.ConnectionDraining{}
+		x.SessionAffinity = "NONE"
+		x.TimeoutSec = 30
+		x.ExternalManagedMigrationState = "FINALIZE"
+		x.VpcNetworkScope = "GLOBAL_VPC_NETWORK"
+	})
+	if err == nil {
+		t.Errorf("AccessAlpha() = nil, want error for IpAddressSelectionPolicy")
+	}
+
+	// UsedBy is OutputOnly and ignored in the diff.
+	a := createBackendServiceResource(t, bsID, nil).(BackendService)
+	ga, err := a.ToGA()
+	if err != nil {
+		t.Fatalf("ToGA() = %v, want nil", err)
+	}
+	gaCopy := *ga
+	gaCopy.UsedBy = []*compute.BackendServiceUsedBy{{Reference: "zzz"}}
+	mb := NewMutableBackendService(proj, bsID.Key)
+	if err := mb.Set(&gaCopy); err != nil {
+		t.Fatalf("Set() = %v, want nil", err)
+	}
+	b, err := mb.Freeze()
+	if err != nil {
+		t.Fatalf("Freeze() = %v, want nil", err)
+	}
+	r, err := a.Diff(b)
+	if err != nil {
+		t.Fatalf("Diff() = %v, want nil", err)
+	}
+	if r.HasDiff() {
+		t.Errorf("Diff() = %+v, want no diff", r)
+	}
+}
+
+func TestUpdateClearsFields(t *testing.T) {
+	gotNode, err := createBackendServiceNode("bs-name", func(m MutableBackendService) error {
+		return m.Access(func(x *compute.BackendService) {
+			x.LoadBalancingScheme = "INTERNAL_SELF_MANAGED"
+			x.Protocol = "TCP"
+			x.CompressionMode = "DISABLED"
+			x.ConnectionDraining = &compute.ConnectionDraining{DrainingTimeoutSec: 10}
+			x.Iap = &compute.BackendServiceIAP{Enabled: true, Oauth2ClientId: "id"}
+			x.SessionAffinity = "NONE"
+			x.TimeoutSec = 30
+		})
+	})
+	if err != nil {
+		t.Fatalf("createBackendServiceNode() = %v, want nil", err)
+	}
+	wantNode, err := createBackendServiceNode("bs-name", func(m MutableBackendService) error {
+		return m.Access(func(x *compute.BackendService) {
+			x.LoadBalancingScheme = "INTERNAL_SELF_MANAGED"
+			x.Protocol = "TCP"
+			x.CompressionMode = "DISABLED"
+			x.ConnectionDraining = nil
+			x.Iap = &compute.BackendServiceIAP{Oauth2ClientId: "id"}
+			x.SessionAffinity = "NONE"
+			x.TimeoutSec = 30
+		})
+	})
+	if err != nil {
+		t.Fatalf("createBackendServiceNode() = %v, want nil", err)
+	}
+	wantNode.Plan().Set(rnode.PlanDetails{Operation: rnode.OpUpdate, Why: "test"})
+	actions, err := wantNode.Actions(gotNode)
+	if err != nil || len(actions) != 1 {
+		t.Fatalf("Actions() = %v, %v; want 1 action", actions, err)
+	}
+
+	mock := cloud.NewMockGCE(&cloud.SingleProjectRouter{ID: proj})
+	var sent *compute.BackendService
+	mock.MockBackendServices.UpdateHook = func(_ context.Context, _ *meta.Key, obj *compute.BackendService, _ *cloud.MockBackendServices, _ ...cloud.Option) error {
+		sent = obj
+		return nil
+	}
+	if _, err := actions[0].Run(context.Background(), mock); err != nil {
+		t.Fatalf("Run() = %v, want nil", err)
+	}
+	if sent == nil {
+		t.Fatal("Update was not called")
+	}
+	if diff := cmp.Diff(sent.NullFields, []string{"ConnectionDraining"}); diff != "" {
+		t.Errorf("NullFields: -got,+want: %s", diff)
+	}
+	if sent.Iap == nil {
+		t.Fatal("Iap = nil, want non-nil")
+	}
+	if diff := cmp.Diff(sent.Iap.ForceSendFields, []string{"Enabled"}); diff != "" {
+		t.Errorf("Iap.ForceSendFields: -got,+want: %s", diff)
+	}
+}
+
+func TestCreateValidateOnlyUnsupported(t *testing.T) {
+	node, err := createBackendServiceNode("bs-name", func(m MutableBackendService) error {
+		return m.Access(func(x *compute.BackendService) {
+			x.LoadBalancingScheme = "INTERNAL_SELF_MANAGED"
+			x.Protocol = "TCP"
+			x.CompressionMode = "DISABLED"
+			x.SessionAffinity = "NONE"
+			x.TimeoutSec = 30
+		})
+	})
+	if err != nil {
+		t.Fatalf("createBackendServiceNode() = %v, want nil", err)
+	}
+	node.Plan().Set(rnode.PlanDetails{Operation: rnode.OpCreate, Why: "test"})
+	actions, err := node.Actions(nil)
+	if err != nil || len(actions) != 1 {
+		t.Fatalf("Actions() = %v, %v; want 1 action", actions, err)
+	}
+	if !actions[0].Metadata().ValidateOnly {
+		t.Errorf("Metadata().ValidateOnly = false, want true")
+	}
+
+	// BackendServices do not support validateOnly; the Action is dry run.
+	mockCloud := cloud.NewMockGCE(&cloud.SingleProjectRouter{ID: proj})
+	ctx := context.Background()
+	events, err := actions[0].Run(exec.WithValidateOnly(ctx), mockCloud)
+	if err != nil || len(events) != 1 {
+		t.Fatalf("Run() = %v, %v; want 1 event", events, err)
+	}
+	if _, err := mockCloud.BackendServices().Get(ctx, meta.GlobalKey("bs-name")); err == nil {
+		t.Error("Get() = nil, want NotFound")
+	}
+}
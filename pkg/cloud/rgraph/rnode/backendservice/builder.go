@@ -75,7 +75,7 @@ func (b *builder) OutRefs() ([]rnode.ResourceRef, error) {
 
 	// Backends[].Group
 	for idx, backend := range obj.Backends {
-		id, err := cloud.ParseResourceURL(backend.Group)
+		id, err := b.ParseRefURL(backend.Group)
 		if err != nil {
 			return nil, fmt.Errorf("BackendServiceNode Group: %w", err)
 		}
@@ -88,7 +88,7 @@ func (b *builder) OutRefs() ([]rnode.ResourceRef, error) {
 
 	// Healthchecks[]
 	for idx, hc := range obj.HealthChecks {
-		id, err := cloud.ParseResourceURL(hc)
+		id, err := b.ParseRefURL(hc)
 		if err != nil {
 			return nil, fmt.Errorf("BackendServiceNode HealthChecks: %w", err)
 		}
@@ -101,7 +101,7 @@ func (b *builder) OutRefs() ([]rnode.ResourceRef, error) {
 
 	// SecurityPolicy
 	if obj.SecurityPolicy != "" {
-		id, err := cloud.ParseResourceURL(obj.SecurityPolicy)
+		id, err := b.ParseRefURL(obj.SecurityPolicy)
 		if err != nil {
 			return nil, fmt.Errorf("BackendServiceNode SecurityPolicy: %w", err)
 		}
@@ -114,7 +114,7 @@ func (b *builder) OutRefs() ([]rnode.ResourceRef, error) {
 
 	// EdgeSecurityPolicy
 	if obj.EdgeSecurityPolicy != "" {
-		id, err := cloud.ParseResourceURL(obj.EdgeSecurityPolicy)
+		id, err := b.ParseRefURL(obj.EdgeSecurityPolicy)
 		if err != nil {
 			return nil, fmt.Errorf("BackendServiceNode SecurityPolicy: %w", err)
 		}
@@ -128,12 +128,19 @@ func (b *builder) OutRefs() ([]rnode.ResourceRef, error) {
 	return ret, nil
 }
 
+func (b *builder) Clone() rnode.Builder {
+	return &builder{
+		BuilderBase: b.CopyBase(),
+		resource:    rnode.CloneResource(b.resource),
+	}
+}
+
 func (b *builder) Build() (rnode.Node, error) {
 	if b.State() == rnode.NodeExists && b.resource == nil {
 		return nil, fmt.Errorf("BackendService %s resource is nil with state %s", b.ID(), b.State())
 	}
 
-	ret := &backendServiceNode{resource: b.resource}
+	ret := &backendServiceNode{resource: rnode.CloneResource(b.resource)}
 	if err := ret.InitFromBuilder(b); err != nil {
 		return nil, err
 	}
@@ -142,7 +142,12 @@ func (n *backendServiceNode) Actions(got rnode.Node) ([]exec.Action, error) {
 		if err != nil {
 			return nil, fmt.Errorf("Cannot get fingerprint from BackendService: %w", err)
 		}
-		return rnode.UpdateActions[compute.BackendService, alpha.BackendService, beta.BackendService](&ops{}, got, n, n.resource, f)
+		// Fields cleared in want must be sent explicitly.
+		res, err := api.WithClearedFields(gotNode.resource, n.resource)
+		if err != nil {
+			return nil, fmt.Errorf("BackendServiceNode: %w", err)
+		}
+		return rnode.UpdateActions[compute.BackendService, alpha.BackendService, beta.BackendService](&ops{}, got, n, res, f)
 	}
 
 	return nil, fmt.Errorf("BackendServiceNode: invalid plan op %s", op)
@@ -150,7 +155,7 @@ func (n *backendServiceNode) Actions(got rnode.Node) ([]exec.Action, error) {
 
 func (n *backendServiceNode) Builder() rnode.Builder {
 	b := &builder{}
-	b.Init(n.ID(), n.State(), n.Ownership(), n.resource)
+	b.InitFromNode(n)
 	return b
 }
 
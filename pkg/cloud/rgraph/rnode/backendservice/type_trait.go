@@ -31,6 +31,8 @@ type typeTrait struct {
 
 func (*typeTrait) FieldTraits(v meta.Version) *api.FieldTraits {
 	dt := api.NewFieldTraits()
+	dt.Description(api.Path{}.Pointer().Field("Description"))
+
 	// Built-ins
 	dt.OutputOnly(api.Path{}.Pointer().Field("Fingerprint"))
 
@@ -44,8 +46,7 @@ func (*typeTrait) FieldTraits(v meta.Version) *api.FieldTraits {
 	dt.OutputOnly(api.Path{}.Pointer().Field("SelfLink"))
 
 	dt.OutputOnly(api.Path{}.Pointer().Field("Iap").Pointer().Field("Oauth2ClientSecretSha256"))
-	dt.OutputOnly(api.Path{}.Pointer().Field("CdnPolicy").Field("SignedUrlKeyNames"))
-	dt.OutputOnly(api.Path{}.Pointer().Field("CdnPolicy").Pointer().Field("CacheKeyPolicy").Pointer().Field("SignedUrlKeyNames"))
+	dt.OutputOnly(api.Path{}.Pointer().Field("CdnPolicy").Pointer().Field("SignedUrlKeyNames"))
 
 	dt.NonZeroValue(api.Path{}.Pointer().Field("LoadBalancingScheme"))
 	dt.NonZeroValue(api.Path{}.Pointer().Field("Protocol"))
@@ -56,17 +57,20 @@ func (*typeTrait) FieldTraits(v meta.Version) *api.FieldTraits {
 	dt.NonZeroValue(api.Path{}.Pointer().Field("SessionAffinity"))
 	dt.NonZeroValue(api.Path{}.Pointer().Field("TimeoutSec"))
 
-	if v == meta.VersionBeta {
-		dt.NonZeroValue(api.Path{}.Pointer().Field("IpAddressSelectionPolicy"))
-	}
-	if v == meta.VersionAlpha {
+	dt.OutputOnly(api.Path{}.Pointer().Field("UsedBy"))
+
+	switch v {
+	case meta.VersionAlpha:
 		dt.OutputOnly(api.Path{}.Pointer().Field("SelfLinkWithId"))
 
 		// not supported
 		dt.OutputOnly(api.Path{}.Pointer().Field("HaPolicy"))
 
+		dt.NonZeroValue(api.Path{}.Pointer().Field("IpAddressSelectionPolicy"))
 		dt.NonZeroValue(api.Path{}.Pointer().Field("VpcNetworkScope"))
 		dt.NonZeroValue(api.Path{}.Pointer().Field("ExternalManagedMigrationState"))
+	case meta.VersionBeta:
+		dt.NonZeroValue(api.Path{}.Pointer().Field("IpAddressSelectionPolicy"))
 	}
 	return dt
 }
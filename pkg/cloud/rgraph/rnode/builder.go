@@ -38,6 +38,60 @@ type Builder interface {
 	// SetOwnership of this resource.
 	SetOwnership(os OwnershipStatus)
 
+	// DeletionProtected is true if the planner must not delete this
+	// resource.
+	DeletionProtected() bool
+	// SetDeletionProtected will cause the planner to return an error
+	// instead of planning a delete (or recreate) of the resource. Use
+	// this to protect shared resources from accidental teardown. The
+	// setting is stored in the resource so that it also applies when the
+	// Node is removed from the graph (see plan.DeletionProtectedKey).
+	SetDeletionProtected(bool)
+
+	// CascadeDelete is true if deleting this resource also deletes the
+	// resources that are only referenced by it.
+	CascadeDelete() bool
+	// SetCascadeDelete will cause the planner to also delete the
+	// dependents of the resource (the resources it references) when the
+	// resource is deleted, if no other resource in the graph references
+	// them. This is applied transitively. External and DeletionProtected
+	// dependents are not deleted.
+	SetCascadeDelete(bool)
+
+	// ConflictStrategy for the resource. ConflictDefault uses the
+	// strategy of the plan.
+	ConflictStrategy() ConflictStrategy
+	// SetConflictStrategy overrides the plan ConflictStrategy for this
+	// resource.
+	SetConflictStrategy(s ConflictStrategy)
+
+	// Annotations are arbitrary key/values attached by the caller (e.g.
+	// the namespace/name of the K8s object the resource was created for,
+	// see AnnotationService). Annotations are propagated to the Node and its Actions and are not
+	// sent to the Cloud.
+	Annotations() map[string]string
+	// SetAnnotations replaces the Annotations with a copy of a.
+	SetAnnotations(a map[string]string)
+
+	// Preconditions that must hold before the resource is created or
+	// updated. See Precondition.
+	Preconditions() []Precondition
+	// SetPreconditions replaces the Preconditions with a copy of p.
+	SetPreconditions(p []Precondition)
+
+	// Postconditions that are verified after the resource is created or
+	// updated. See Postcondition.
+	Postconditions() []Postcondition
+	// SetPostconditions replaces the Postconditions with a copy of p.
+	SetPostconditions(p []Postcondition)
+
+	// OutRefsMode is how OutRefs handles the references that cannot be
+	// parsed.
+	OutRefsMode() OutRefsMode
+	// SetOutRefsMode to m. Use OutRefsWarn for graphs that may contain
+	// references with URL schemes that are not known to this library.
+	SetOutRefsMode(m OutRefsMode)
+
 	// Resource (cloud type) for this Node.
 	Resource() UntypedResource
 	// SetResource to a new value.
@@ -51,12 +105,21 @@ type Builder interface {
 	OutRefs() ([]ResourceRef, error)
 	// AddInRef to this node Builder.
 	AddInRef(ref ResourceRef)
+	// ClearInRefs removes all inRefs. The inRefs are recomputed each time
+	// the graph is built.
+	ClearInRefs()
 
 	// SyncFromCloud downloads the resource from the Cloud. This
 	// may result in one or more blocking calls to the GCE APIs.
 	SyncFromCloud(ctx context.Context, cl cloud.Cloud) error
 
-	// Build the node, converting this to a Node in a Graph.
+	// Clone returns a deep copy of the Builder. Changes to the clone
+	// (including to its Resource) do not affect the original.
+	Clone() Builder
+
+	// Build the node, converting this to a Node in a Graph. The Node
+	// holds a snapshot of the Resource; later changes to the Builder are
+	// not reflected in the Node.
 	Build() (Node, error)
 
 	// inRefs that have been computed so far. This method is
@@ -73,17 +136,78 @@ type BuilderBase struct {
 	ownership OwnershipStatus
 	version   meta.Version
 
+	deletionProtected bool
+	cascadeDelete     bool
+	conflictStrategy  ConflictStrategy
+	annotations       map[string]string
+	preconditions     []Precondition
+	postconditions    []Postcondition
+	outRefsMode       OutRefsMode
+
 	curInRefs []ResourceRef
 }
 
-func (b *BuilderBase) ID() *cloud.ResourceID           { return b.id }
-func (b *BuilderBase) State() NodeState                { return b.state }
-func (b *BuilderBase) SetState(state NodeState)        { b.state = state }
-func (b *BuilderBase) Ownership() OwnershipStatus      { return b.ownership }
-func (b *BuilderBase) SetOwnership(os OwnershipStatus) { b.ownership = os }
-func (b *BuilderBase) Version() meta.Version           { return b.version }
+func (b *BuilderBase) ID() *cloud.ResourceID                  { return b.id }
+func (b *BuilderBase) State() NodeState                       { return b.state }
+func (b *BuilderBase) SetState(state NodeState)               { b.state = state }
+func (b *BuilderBase) Ownership() OwnershipStatus             { return b.ownership }
+func (b *BuilderBase) SetOwnership(os OwnershipStatus)        { b.ownership = os }
+func (b *BuilderBase) Version() meta.Version                  { return b.version }
+func (b *BuilderBase) DeletionProtected() bool                { return b.deletionProtected }
+func (b *BuilderBase) SetDeletionProtected(p bool)            { b.deletionProtected = p }
+func (b *BuilderBase) CascadeDelete() bool                    { return b.cascadeDelete }
+func (b *BuilderBase) SetCascadeDelete(c bool)                { b.cascadeDelete = c }
+func (b *BuilderBase) ConflictStrategy() ConflictStrategy     { return b.conflictStrategy }
+func (b *BuilderBase) SetConflictStrategy(s ConflictStrategy) { b.conflictStrategy = s }
+func (b *BuilderBase) OutRefsMode() OutRefsMode               { return b.outRefsMode }
+func (b *BuilderBase) SetOutRefsMode(m OutRefsMode)           { b.outRefsMode = m }
+func (b *BuilderBase) Annotations() map[string]string         { return b.annotations }
+func (b *BuilderBase) SetAnnotations(a map[string]string)     { b.annotations = copyAnnotations(a) }
+func (b *BuilderBase) Preconditions() []Precondition          { return b.preconditions }
+func (b *BuilderBase) SetPreconditions(p []Precondition) {
+	b.preconditions = append([]Precondition(nil), p...)
+}
+func (b *BuilderBase) Postconditions() []Postcondition { return b.postconditions }
+func (b *BuilderBase) SetPostconditions(p []Postcondition) {
+	b.postconditions = append([]Postcondition(nil), p...)
+}
+
+// AnnotationService is the Annotation key for the "namespace/name" of the K8s
+// Service the resource was created for. See rgraph.Graph.ByService().
+const AnnotationService = "service"
+
+func copyAnnotations(a map[string]string) map[string]string {
+	if len(a) == 0 {
+		return nil
+	}
+	ret := make(map[string]string, len(a))
+	for k, v := range a {
+		ret[k] = v
+	}
+	return ret
+}
+
+// CopyBase returns a copy of the BuilderBase that shares no mutable state
+// with b. This is used to implement Builder.Clone().
+func (b *BuilderBase) CopyBase() BuilderBase {
+	ret := *b
+	ret.annotations = copyAnnotations(b.annotations)
+	ret.preconditions = append([]Precondition(nil), b.preconditions...)
+	ret.postconditions = append([]Postcondition(nil), b.postconditions...)
+	ret.curInRefs = append([]ResourceRef(nil), b.curInRefs...)
+	return ret
+}
+
+// CloneResource returns r.Clone(), or nil if r is nil.
+func CloneResource[T interface{ Clone() T }](r T) T {
+	if any(r) == nil {
+		return r
+	}
+	return r.Clone()
+}
 
 func (b *BuilderBase) AddInRef(ref ResourceRef) { b.curInRefs = append(b.curInRefs, ref) }
+func (b *BuilderBase) ClearInRefs()             { b.curInRefs = nil }
 func (b *BuilderBase) inRefs() []ResourceRef    { return b.curInRefs }
 
 // Defaults sets the default values for a empty Builder node.
@@ -110,3 +234,17 @@ func (b *BuilderBase) Init(
 		b.version = resource.Version()
 	}
 }
+
+// InitFromNode initializes the BuilderBase from the attributes of n. The
+// Resource is not copied; the Version is set from n.Resource(). This is used
+// to implement Node.Builder().
+func (b *BuilderBase) InitFromNode(n Node) {
+	b.Init(n.ID(), n.State(), n.Ownership(), n.Resource())
+	b.SetDeletionProtected(n.DeletionProtected())
+	b.SetCascadeDelete(n.CascadeDelete())
+	b.SetConflictStrategy(n.ConflictStrategy())
+	b.SetAnnotations(n.Annotations())
+	b.SetPreconditions(n.Preconditions())
+	b.SetPostconditions(n.Postconditions())
+	b.SetOutRefsMode(n.OutRefsMode())
+}
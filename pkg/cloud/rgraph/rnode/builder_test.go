@@ -42,3 +42,60 @@ func TestBuilderBase(t *testing.T) {
 		t.Errorf("nb; -got,+want: %s", diff)
 	}
 }
+
+func TestBuilderBaseCopyBase(t *testing.T) {
+	var nb BuilderBase
+	nb.Defaults(&cloud.ResourceID{Resource: "fake", Key: meta.GlobalKey("res1")})
+	nb.SetOwnership(OwnershipManaged)
+	nb.SetAnnotations(map[string]string{"a": "1"})
+	nb.SetPostconditions([]Postcondition{FieldIsSet("Name")})
+
+	c := nb.CopyBase()
+	c.Annotations()["a"] = "changed"
+	c.postconditions[0] = nil
+	c.SetOwnership(OwnershipExternal)
+
+	if nb.Annotations()["a"] != "1" {
+		t.Errorf("nb.Annotations() = %v, want a=1", nb.Annotations())
+	}
+	if nb.Postconditions()[0] == nil {
+		t.Errorf("nb.Postconditions()[0] = nil, want unchanged")
+	}
+	if nb.Ownership() != OwnershipManaged {
+		t.Errorf("nb.Ownership() = %v, want %v", nb.Ownership(), OwnershipManaged)
+	}
+}
+
+func TestBuilderBaseInitFromNode(t *testing.T) {
+	var nb fakeBuilder
+	nb.Defaults(&cloud.ResourceID{Resource: "fake", Key: meta.GlobalKey("res1")})
+	nb.SetState(NodeExists)
+	nb.SetOwnership(OwnershipManaged)
+	nb.SetDeletionProtected(true)
+	nb.SetCascadeDelete(true)
+	nb.SetConflictStrategy(ConflictFail)
+	nb.SetAnnotations(map[string]string{"a": "1"})
+	nb.SetPreconditions([]Precondition{FieldEquals(nb.ID(), "Name", "res1")})
+	nb.SetPostconditions([]Postcondition{FieldIsSet("Name")})
+	nb.SetOutRefsMode(OutRefsWarn)
+
+	var n fakeNode
+	if err := n.InitFromBuilder(&nb); err != nil {
+		t.Fatalf("InitFromBuilder() = %v, want nil", err)
+	}
+	var b BuilderBase
+	b.InitFromNode(&n)
+
+	if !b.ID().Equal(nb.ID()) || b.State() != NodeExists || b.Ownership() != OwnershipManaged || b.Version() != meta.VersionGA {
+		t.Errorf("b = (%v, %v, %v, %v), want (%v, %v, %v, %v)", b.ID(), b.State(), b.Ownership(), b.Version(), nb.ID(), NodeExists, OwnershipManaged, meta.VersionGA)
+	}
+	if !b.DeletionProtected() || !b.CascadeDelete() || b.ConflictStrategy() != ConflictFail || b.OutRefsMode() != OutRefsWarn {
+		t.Errorf("b = (%t, %t, %v, %v), want (true, true, %v, %v)", b.DeletionProtected(), b.CascadeDelete(), b.ConflictStrategy(), b.OutRefsMode(), ConflictFail, OutRefsWarn)
+	}
+	if diff := cmp.Diff(b.Annotations(), nb.Annotations()); diff != "" {
+		t.Errorf("Annotations(): -got,+want: %s", diff)
+	}
+	if len(b.Preconditions()) != 1 || len(b.Postconditions()) != 1 {
+		t.Errorf("b = (%v, %v), want 1 precondition and 1 postcondition", b.Preconditions(), b.Postconditions())
+	}
+}
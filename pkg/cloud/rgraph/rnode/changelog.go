@@ -0,0 +1,66 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rnode
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/api"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/exec"
+)
+
+// recordChange records the change applied by the Action to the
+// exec.ChangeLog in ctx. before and after are the resources (see
+// api.FieldAccessor); nil or resources that cannot be converted are left
+// out of the record. Does nothing if there is no ChangeLog.
+func recordChange(ctx context.Context, md *exec.ActionMetadata, id *cloud.ResourceID, before, after any) error {
+	l := exec.ChangeLogFrom(ctx)
+	if l == nil {
+		return nil
+	}
+	r := &exec.ChangeRecord{
+		Time:     time.Now(),
+		Type:     md.Type,
+		Action:   md.Name,
+		ID:       id,
+		Resource: id.String(),
+		Before:   specJSON(before),
+		After:    specJSON(after),
+	}
+	if err := l.Record(ctx, r); err != nil {
+		return fmt.Errorf("%s: recording change: %w", md.Name, err)
+	}
+	return nil
+}
+
+// specJSON returns the JSON of r without the OutputOnly fields. Returns nil
+// if r is not an api.FieldAccessor.
+func specJSON(r any) json.RawMessage {
+	fa, ok := r.(api.FieldAccessor)
+	if !ok {
+		return nil
+	}
+	data, err := fa.SpecJSON()
+	if err != nil {
+		return nil
+	}
+	return data
+}
@@ -0,0 +1,66 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rnode
+
+import (
+	"context"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/api"
+	"k8s.io/klog/v2"
+)
+
+// ResourceDecodeMode controls how SyncFromCloud stores the resources fetched
+// from the Cloud in the Builder.
+type ResourceDecodeMode string
+
+const (
+	// DecodeEager stores the resources decoded. This is the default.
+	DecodeEager ResourceDecodeMode = ""
+	// DecodeLazy stores the resources in a compressed encoding (see
+	// api.Compact) that is decoded each time the resource is accessed, e.g.
+	// when diffing. This reduces the memory used by graphs with many large
+	// resources at the cost of CPU.
+	DecodeLazy ResourceDecodeMode = "Lazy"
+)
+
+var resourceDecodeContextKey = contextKey("resource decode")
+
+// WithResourceDecode returns a context that sets the ResourceDecodeMode for
+// SyncFromCloud calls made with it.
+func WithResourceDecode(ctx context.Context, mode ResourceDecodeMode) context.Context {
+	return context.WithValue(ctx, resourceDecodeContextKey, mode)
+}
+
+// ResourceDecode returns the ResourceDecodeMode set in ctx.
+func ResourceDecode(ctx context.Context) ResourceDecodeMode {
+	v, _ := ctx.Value(resourceDecodeContextKey).(ResourceDecodeMode)
+	return v
+}
+
+// storedResource returns r in the form for the ResourceDecodeMode in ctx.
+// Resources that cannot be compacted are stored decoded.
+func storedResource[GA any, Alpha any, Beta any](ctx context.Context, r api.Resource[GA, Alpha, Beta]) api.Resource[GA, Alpha, Beta] {
+	if ResourceDecode(ctx) != DecodeLazy {
+		return r
+	}
+	c, err := api.Compact(r)
+	if err != nil {
+		klog.FromContext(ctx).V(2).Info("Storing resource decoded", "id", r.ResourceID(), "err", err)
+		return r
+	}
+	return c
+}
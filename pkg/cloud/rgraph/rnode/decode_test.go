@@ -0,0 +1,54 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rnode
+
+import (
+	"context"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/api"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	alpha "google.golang.org/api/compute/v0.alpha"
+	beta "google.golang.org/api/compute/v0.beta"
+	"google.golang.org/api/compute/v1"
+)
+
+func TestStoredResource(t *testing.T) {
+	u := api.NewResource[compute.UrlMap, alpha.UrlMap, beta.UrlMap](&cloud.ResourceID{
+		ProjectID: "proj",
+		Resource:  "urlMaps",
+		Key:       meta.GlobalKey("um"),
+	}, nil)
+	u.Set(&compute.UrlMap{Name: "um", DefaultService: "bs"})
+	r, err := u.Freeze()
+	if err != nil {
+		t.Fatalf("Freeze() = %v", err)
+	}
+
+	if got := storedResource(context.Background(), r); got != r {
+		t.Errorf("storedResource(DecodeEager) = %p, want %p", got, r)
+	}
+	ctx := WithResourceDecode(context.Background(), DecodeLazy)
+	got := storedResource(ctx, r)
+	if got == r {
+		t.Fatalf("storedResource(DecodeLazy) = %p, want a compact resource", got)
+	}
+	if d, err := got.Diff(r); err != nil || d.HasDiff() {
+		t.Errorf("storedResource(DecodeLazy).Diff(r) = %+v, %v; want no diff", d, err)
+	}
+}
@@ -0,0 +1,48 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package rnode defines the nodes of the resource graph (rgraph) and the
+// generic implementations shared by the resource types.
+//
+// # Custom resource types
+//
+// A resource type can be implemented outside of this library. See the
+// examples/customnode package for a complete example. The following is the
+// minimum set of interfaces to implement; the rest of this package is subject
+// to change.
+//
+//   - api.TypeTrait for the API types of the resource. Embed
+//     api.BaseTypeTrait and implement FieldTraits() to classify the fields
+//     (OutputOnly, System etc.) so that Diff ignores the fields set by the
+//     server.
+//   - GenericOps to dispatch the Get, Insert, Update and Delete calls by
+//     version and scope. Return nil from UpdateFuncs if the resource cannot
+//     be updated in place. Set UpdateFuncsNoFingerprint if the resource does
+//     not have a Fingerprint field.
+//   - Builder. Embed BuilderBase (this is required as Builder has
+//     unexported methods), call Defaults() or Init() in the constructor and
+//     implement Resource, SetResource, SyncFromCloud (use GenericGet),
+//     OutRefs, Clone (use CopyBase and CloneResource) and Build (use
+//     NodeBase.InitFromBuilder).
+//   - Node. Embed NodeBase and implement Resource, Diff, Actions and
+//     Builder (use BuilderBase.InitFromNode). Actions can use the generic
+//     CreateActions, UpdateActions, DeleteActions and RecreateActions.
+//
+// Register the Builder constructor with all.Register so that the graph
+// algorithms can create Builders for references to the type. The type is
+// identified by its ResourceID.Resource, which must not collide with the
+// types in this library.
+package rnode
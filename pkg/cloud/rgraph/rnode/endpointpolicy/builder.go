@@ -0,0 +1,94 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package endpointpolicy
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/api"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode"
+	"google.golang.org/api/networkservices/v1"
+	beta "google.golang.org/api/networkservices/v1beta1"
+)
+
+const (
+	resourceName = "EndpointPolicy"
+)
+
+func NewBuilder(id *cloud.ResourceID) rnode.Builder {
+	b := &builder{}
+	b.Defaults(id)
+	return b
+}
+
+func NewBuilderWithResource(r EndpointPolicy) rnode.Builder {
+	b := &builder{resource: r}
+	b.Init(r.ResourceID(), rnode.NodeUnknown, rnode.OwnershipUnknown, r)
+	return b
+}
+
+type builder struct {
+	rnode.BuilderBase
+	resource EndpointPolicy
+}
+
+// builder implements node.Builder.
+var _ rnode.Builder = (*builder)(nil)
+
+func (b *builder) Resource() rnode.UntypedResource { return b.resource }
+
+func (b *builder) SetResource(u rnode.UntypedResource) error {
+	r, ok := u.(EndpointPolicy)
+	if !ok {
+		return fmt.Errorf("cannot set EndpointPolicy from untyped resource, %T", u)
+	}
+	b.resource = r
+	return nil
+}
+
+func (b *builder) SyncFromCloud(ctx context.Context, gcp cloud.Cloud) error {
+	return rnode.GenericGet[networkservices.EndpointPolicy, api.PlaceholderType, beta.EndpointPolicy](
+		ctx, gcp, resourceName, &ops{}, &typeTrait{}, b)
+}
+
+func (b *builder) OutRefs() ([]rnode.ResourceRef, error) {
+	// The authorization and TLS policies are networksecurity resources that
+	// are not in the graph.
+	return nil, nil
+}
+
+func (b *builder) Clone() rnode.Builder {
+	return &builder{
+		BuilderBase: b.CopyBase(),
+		resource:    rnode.CloneResource(b.resource),
+	}
+}
+
+func (b *builder) Build() (rnode.Node, error) {
+	if b.State() == rnode.NodeExists && b.resource == nil {
+		return nil, fmt.Errorf("EndpointPolicy %s resource is nil with state %s", b.ID(), b.State())
+	}
+
+	ret := &endpointPolicyNode{resource: rnode.CloneResource(b.resource)}
+	if err := ret.InitFromBuilder(b); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
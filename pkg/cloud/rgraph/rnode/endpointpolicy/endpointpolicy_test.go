@@ -0,0 +1,189 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package endpointpolicy
+
+import (
+	"context"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/fake"
+	"google.golang.org/api/networkservices/v1"
+)
+
+const projectID = "proj-1"
+
+func TestEndpointPolicySchema(t *testing.T) {
+	x := NewMutableEndpointPolicy(projectID, meta.GlobalKey("key-1"))
+	if err := x.CheckSchema(); err != nil {
+		t.Fatalf("CheckSchema() = %v, want nil", err)
+	}
+}
+
+func TestEndpointPolicyCreateAndUpdate(t *testing.T) {
+	ctx := context.Background()
+	mock := cloud.NewMockGCE(&cloud.SingleProjectRouter{ID: projectID})
+	id := ID(projectID, meta.GlobalKey("ep"))
+	mock.MockEndpointPolicies.PatchHook = func(_ context.Context, key *meta.Key, obj *networkservices.EndpointPolicy, m *cloud.MockEndpointPolicies, _ ...cloud.Option) error {
+		m.Objects[*key] = &cloud.MockEndpointPoliciesObj{Obj: obj}
+		return nil
+	}
+
+	run := func(want, got rnode.Node, op rnode.Operation) {
+		t.Helper()
+		want.Plan().Set(rnode.PlanDetails{Operation: op})
+		actions, err := want.Actions(got)
+		if err != nil || len(actions) == 0 {
+			t.Fatalf("Actions(%s) = %v, %v; want actions", op, actions, err)
+		}
+		for _, a := range actions {
+			if _, err := a.Run(ctx, mock); err != nil {
+				t.Fatalf("Run(%s) = %v", op, err)
+			}
+		}
+	}
+	sync := func() rnode.Node {
+		t.Helper()
+		b := NewBuilder(id)
+		if err := b.SyncFromCloud(ctx, mock); err != nil {
+			t.Fatalf("SyncFromCloud() = %v", err)
+		}
+		b.SetOwnership(rnode.OwnershipManaged)
+		n, err := b.Build()
+		if err != nil {
+			t.Fatalf("Build() = %v", err)
+		}
+		return n
+	}
+
+	if got := sync(); got.State() != rnode.NodeDoesNotExist {
+		t.Fatalf("State() = %s, want %s", got.State(), rnode.NodeDoesNotExist)
+	}
+	run(newNode(t, id, "GRPC_SERVER"), nil, rnode.OpCreate)
+
+	got := sync()
+	if got.State() != rnode.NodeExists {
+		t.Fatalf("State() = %s, want %s", got.State(), rnode.NodeExists)
+	}
+	want := newNode(t, id, "SIDECAR_PROXY")
+	p, err := want.Diff(got)
+	if err != nil || p.Operation != rnode.OpUpdate {
+		t.Fatalf("Diff() = %+v, %v; want %s, nil", p, err, rnode.OpUpdate)
+	}
+	run(want, got, rnode.OpUpdate)
+
+	obj, err := sync().Resource().(EndpointPolicy).ToGA()
+	if err != nil {
+		t.Fatalf("ToGA() = %v", err)
+	}
+	if obj.Type != "SIDECAR_PROXY" {
+		t.Errorf("Type = %q, want %q", obj.Type, "SIDECAR_PROXY")
+	}
+
+	got = sync()
+	if p, err := want.Diff(got); err != nil || p.Operation != rnode.OpNothing {
+		t.Errorf("Diff() = %+v, %v; want %s, nil", p, err, rnode.OpNothing)
+	}
+	run(want, got, rnode.OpNothing)
+
+	run(newNode(t, id, "GRPC_SERVER"), got, rnode.OpRecreate)
+	obj, err = sync().Resource().(EndpointPolicy).ToGA()
+	if err != nil {
+		t.Fatalf("ToGA() = %v", err)
+	}
+	if obj.Type != "GRPC_SERVER" {
+		t.Errorf("Type = %q, want %q", obj.Type, "GRPC_SERVER")
+	}
+
+	del := NewBuilder(id)
+	del.SetOwnership(rnode.OwnershipManaged)
+	del.SetState(rnode.NodeDoesNotExist)
+	delNode, err := del.Build()
+	if err != nil {
+		t.Fatalf("Build() = %v", err)
+	}
+	run(delNode, sync(), rnode.OpDelete)
+	if got := sync(); got.State() != rnode.NodeDoesNotExist {
+		t.Errorf("State() = %s, want %s", got.State(), rnode.NodeDoesNotExist)
+	}
+}
+
+func TestEndpointPolicyInvalidTypes(t *testing.T) {
+	id := ID(projectID, meta.GlobalKey("ep"))
+	b := NewBuilder(id)
+	if err := b.SetResource(fake.Fake(nil)); err == nil {
+		t.Error("SetResource(fake) = nil, want error")
+	}
+	b.SetState(rnode.NodeExists)
+	if _, err := b.Build(); err == nil {
+		t.Error("Build() with a nil resource = nil, want error")
+	}
+
+	n := newNode(t, id, "GRPC_SERVER")
+	nb := NewBuilderWithResource(n.Resource().(EndpointPolicy))
+	nb.SetOwnership(rnode.OwnershipManaged)
+	clone, err := nb.Clone().Build()
+	if err != nil {
+		t.Fatalf("Clone().Build() = %v", err)
+	}
+	if !clone.ID().Equal(id) || clone.Ownership() != rnode.OwnershipManaged {
+		t.Errorf("Clone().Build() = %v, %s; want %v, %s", clone.ID(), clone.Ownership(), id, rnode.OwnershipManaged)
+	}
+
+	fn, err := fake.NewBuilder(fake.ID(projectID, meta.GlobalKey("f"))).Build()
+	if err != nil {
+		t.Fatalf("fake Build() = %v", err)
+	}
+	if _, err := n.Diff(fn); err == nil {
+		t.Error("Diff(fake) = nil, want error")
+	}
+	n.Plan().Set(rnode.PlanDetails{Operation: rnode.OpUnknown})
+	if _, err := n.Actions(n); err == nil {
+		t.Errorf("Actions(%s) = nil, want error", rnode.OpUnknown)
+	}
+}
+
+func newNode(t *testing.T, id *cloud.ResourceID, typ string) rnode.Node {
+	t.Helper()
+
+	m := NewMutableEndpointPolicy(projectID, id.Key)
+	if err := m.Access(func(x *networkservices.EndpointPolicy) {
+		x.Name = id.Key.Name
+		x.Type = typ
+		x.EndpointMatcher = &networkservices.EndpointMatcher{
+			MetadataLabelMatcher: &networkservices.EndpointMatcherMetadataLabelMatcher{
+				MetadataLabelMatchCriteria: "MATCH_ANY",
+			},
+		}
+	}); err != nil {
+		t.Fatalf("Access() = %v", err)
+	}
+	r, err := m.Freeze()
+	if err != nil {
+		t.Fatalf("Freeze() = %v", err)
+	}
+	b := NewBuilderWithResource(r)
+	b.SetState(rnode.NodeExists)
+	b.SetOwnership(rnode.OwnershipManaged)
+	n, err := b.Build()
+	if err != nil {
+		t.Fatalf("Build() = %v", err)
+	}
+	return n
+}
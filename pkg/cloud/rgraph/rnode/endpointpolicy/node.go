@@ -0,0 +1,89 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package endpointpolicy
+
+import (
+	"fmt"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/api"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/exec"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode"
+	"google.golang.org/api/networkservices/v1"
+	beta "google.golang.org/api/networkservices/v1beta1"
+)
+
+type endpointPolicyNode struct {
+	rnode.NodeBase
+	resource EndpointPolicy
+}
+
+var _ rnode.Node = (*endpointPolicyNode)(nil)
+
+func (n *endpointPolicyNode) Resource() rnode.UntypedResource { return n.resource }
+
+func (n *endpointPolicyNode) Diff(gotNode rnode.Node) (*rnode.PlanDetails, error) {
+	got, ok := gotNode.(*endpointPolicyNode)
+	if !ok {
+		return nil, fmt.Errorf("EndpointPolicyNode: invalid type to Diff: %T", gotNode)
+	}
+
+	diff, err := got.resource.Diff(n.resource)
+	if err != nil {
+		return nil, fmt.Errorf("EndpointPolicyNode: Diff %w", err)
+	}
+
+	if diff.HasDiff() {
+		return &rnode.PlanDetails{
+			Operation: rnode.OpUpdate,
+			Why:       "EndpointPolicy needs to be updated",
+			Diff:      diff,
+		}, nil
+	}
+
+	return &rnode.PlanDetails{
+		Operation: rnode.OpNothing,
+		Why:       "No diff between got and want",
+	}, nil
+}
+
+func (n *endpointPolicyNode) Actions(got rnode.Node) ([]exec.Action, error) {
+	op := n.Plan().Op()
+
+	switch op {
+	case rnode.OpCreate:
+		return rnode.CreateActions[networkservices.EndpointPolicy, api.PlaceholderType, beta.EndpointPolicy](&ops{}, n, n.resource)
+
+	case rnode.OpDelete:
+		return rnode.DeleteActions[networkservices.EndpointPolicy, api.PlaceholderType, beta.EndpointPolicy](&ops{}, got, n)
+
+	case rnode.OpNothing:
+		return []exec.Action{exec.NewExistsAction(n.ID())}, nil
+
+	case rnode.OpRecreate:
+		return rnode.RecreateActions[networkservices.EndpointPolicy, api.PlaceholderType, beta.EndpointPolicy](&ops{}, got, n, n.resource)
+	case rnode.OpUpdate:
+		return rnode.UpdateActions[networkservices.EndpointPolicy, api.PlaceholderType, beta.EndpointPolicy](&ops{}, got, n, n.resource, "")
+	}
+
+	return nil, fmt.Errorf("EndpointPolicyNode: invalid plan op %s", op)
+}
+
+func (n *endpointPolicyNode) Builder() rnode.Builder {
+	b := &builder{}
+	b.InitFromNode(n)
+	return b
+}
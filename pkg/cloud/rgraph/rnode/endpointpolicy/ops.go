@@ -0,0 +1,72 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package endpointpolicy
+
+import (
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/api"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode"
+	"google.golang.org/api/networkservices/v1"
+	beta "google.golang.org/api/networkservices/v1beta1"
+)
+
+type ops struct{}
+
+func (*ops) GetFuncs(gcp cloud.Cloud) *rnode.GetFuncs[networkservices.EndpointPolicy, api.PlaceholderType, beta.EndpointPolicy] {
+	return &rnode.GetFuncs[networkservices.EndpointPolicy, api.PlaceholderType, beta.EndpointPolicy]{
+		GA: rnode.GetFuncsByScope[networkservices.EndpointPolicy]{
+			Global: gcp.EndpointPolicies().Get,
+		},
+		Beta: rnode.GetFuncsByScope[beta.EndpointPolicy]{
+			Global: gcp.BetaEndpointPolicies().Get,
+		},
+	}
+}
+
+func (*ops) CreateFuncs(gcp cloud.Cloud) *rnode.CreateFuncs[networkservices.EndpointPolicy, api.PlaceholderType, beta.EndpointPolicy] {
+	return &rnode.CreateFuncs[networkservices.EndpointPolicy, api.PlaceholderType, beta.EndpointPolicy]{
+		GA: rnode.CreateFuncsByScope[networkservices.EndpointPolicy]{
+			Global: gcp.EndpointPolicies().Insert,
+		},
+		Beta: rnode.CreateFuncsByScope[beta.EndpointPolicy]{
+			Global: gcp.BetaEndpointPolicies().Insert,
+		},
+	}
+}
+
+func (*ops) UpdateFuncs(gcp cloud.Cloud) *rnode.UpdateFuncs[networkservices.EndpointPolicy, api.PlaceholderType, beta.EndpointPolicy] {
+	return &rnode.UpdateFuncs[networkservices.EndpointPolicy, api.PlaceholderType, beta.EndpointPolicy]{
+		GA: rnode.UpdateFuncsByScope[networkservices.EndpointPolicy]{
+			Global: gcp.EndpointPolicies().Patch,
+		},
+		Beta: rnode.UpdateFuncsByScope[beta.EndpointPolicy]{
+			Global: gcp.BetaEndpointPolicies().Patch,
+		},
+		Options: rnode.UpdateFuncsNoFingerprint,
+	}
+}
+
+func (*ops) DeleteFuncs(gcp cloud.Cloud) *rnode.DeleteFuncs[networkservices.EndpointPolicy, api.PlaceholderType, beta.EndpointPolicy] {
+	return &rnode.DeleteFuncs[networkservices.EndpointPolicy, api.PlaceholderType, beta.EndpointPolicy]{
+		GA: rnode.DeleteFuncsByScope[networkservices.EndpointPolicy]{
+			Global: gcp.EndpointPolicies().Delete,
+		},
+		Beta: rnode.DeleteFuncsByScope[beta.EndpointPolicy]{
+			Global: gcp.BetaEndpointPolicies().Delete,
+		},
+	}
+}
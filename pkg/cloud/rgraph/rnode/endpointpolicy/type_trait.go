@@ -0,0 +1,45 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package endpointpolicy
+
+import (
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/api"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"google.golang.org/api/networkservices/v1"
+	beta "google.golang.org/api/networkservices/v1beta1"
+)
+
+// https://cloud.google.com/traffic-director/docs/reference/network-services/rest/v1beta1/projects.locations.endpointPolicies
+type typeTrait struct {
+	api.BaseTypeTrait[networkservices.EndpointPolicy, api.PlaceholderType, beta.EndpointPolicy]
+}
+
+func (*typeTrait) FieldTraits(meta.Version) *api.FieldTraits {
+	dt := api.NewFieldTraits()
+	dt.OutputOnly(api.Path{}.Pointer().Field("CreateTime"))
+	dt.OutputOnly(api.Path{}.Pointer().Field("UpdateTime"))
+
+	dt.AllowZeroValue(api.Path{}.Pointer().Field("AuthorizationPolicy"))
+	dt.AllowZeroValue(api.Path{}.Pointer().Field("ClientTlsPolicy"))
+	dt.AllowZeroValue(api.Path{}.Pointer().Field("ServerTlsPolicy"))
+	dt.Description(api.Path{}.Pointer().Field("Description"))
+	dt.AllowZeroValue(api.Path{}.Pointer().Field("Labels"))
+	dt.AllowZeroValue(api.Path{}.Pointer().Field("TrafficPortSelector"))
+	dt.AllowZeroValue(api.Path{}.Pointer().Field("EndpointMatcher").Pointer().Field("MetadataLabelMatcher").Pointer().Field("MetadataLabels"))
+
+	return dt
+}
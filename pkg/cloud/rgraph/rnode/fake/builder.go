@@ -71,8 +71,18 @@ func (b *Builder) OutRefs() ([]rnode.ResourceRef, error) {
 	return b.FakeOutRefs, nil
 }
 
+func (b *Builder) Clone() rnode.Builder {
+	return &Builder{
+		BuilderBase:   b.CopyBase(),
+		FakeOutRefs:   append([]rnode.ResourceRef(nil), b.FakeOutRefs...),
+		OutRefsErr:    b.OutRefsErr,
+		resource:      rnode.CloneResource(b.resource),
+		FakeSyncError: b.FakeSyncError,
+	}
+}
+
 func (b *Builder) Build() (rnode.Node, error) {
-	ret := &fakeNode{resource: b.resource}
+	ret := &fakeNode{resource: rnode.CloneResource(b.resource)}
 	if err := ret.InitFromBuilder(b); err != nil {
 		return nil, err
 	}
@@ -125,7 +135,7 @@ func (m *FakeBuilderMocks) initialize(b *Builder) {
 	m.lock.Lock()
 	defer m.lock.Unlock()
 
-	klog.Infof("FakeBuilderMocks.initialize(%s)", b.ID())
+	klog.V(4).InfoS("FakeBuilderMocks.initialize", "resourceID", b.ID())
 
 	if mock, ok := m.m[b.ID().String()]; ok {
 		b.SetState(mock.State())
@@ -77,6 +77,6 @@ func (n *fakeNode) Actions(got rnode.Node) ([]exec.Action, error) {
 
 func (n *fakeNode) Builder() rnode.Builder {
 	b := &Builder{}
-	b.Init(n.ID(), n.State(), n.Ownership(), nil)
+	b.InitFromNode(n)
 	return b
 }
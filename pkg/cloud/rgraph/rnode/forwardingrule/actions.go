@@ -29,23 +29,23 @@ import (
 func forwardingRuleSetLabels(
 	ctx context.Context,
 	cl cloud.Cloud,
-	key *meta.Key,
+	id *cloud.ResourceID,
 	labelFingerprint string,
 	labels map[string]string,
 ) error {
-	switch key.Type() {
+	switch id.Key.Type() {
 	case meta.Global:
-		return cl.GlobalForwardingRules().SetLabels(ctx, key, &compute.GlobalSetLabelsRequest{
+		return cl.GlobalForwardingRules().SetLabels(ctx, id.Key, &compute.GlobalSetLabelsRequest{
 			LabelFingerprint: labelFingerprint,
 			Labels:           labels,
-		})
+		}, cloud.ForceProjectID(id.ProjectID))
 	case meta.Regional:
-		return cl.ForwardingRules().SetLabels(ctx, key, &compute.RegionSetLabelsRequest{
+		return cl.ForwardingRules().SetLabels(ctx, id.Key, &compute.RegionSetLabelsRequest{
 			LabelFingerprint: labelFingerprint,
 			Labels:           labels,
-		})
+		}, cloud.ForceProjectID(id.ProjectID))
 	}
-	return fmt.Errorf("forwardingRuleMethodsByScope: invalid scope %v", key.Type())
+	return fmt.Errorf("forwardingRuleMethodsByScope: invalid scope %v", id.Key.Type())
 }
 
 func newForwardingRuleCreateAction(id *cloud.ResourceID, res ForwardingRule, want exec.EventList) exec.Action {
@@ -63,7 +63,6 @@ type forwardingRuleCreateAction struct {
 }
 
 func (act *forwardingRuleCreateAction) Run(ctx context.Context, cl cloud.Cloud) (exec.EventList, error) {
-	// XXX: project routing
 	ops := &ops{}
 	err := ops.CreateFuncs(cl).Do(ctx, act.id, act.res)
 	if err != nil {
@@ -79,7 +78,7 @@ func (act *forwardingRuleCreateAction) Run(ctx context.Context, cl cloud.Cloud)
 
 		}
 		ga, _ = res.ToGA()
-		if err := forwardingRuleSetLabels(ctx, cl, act.id.Key, ga.LabelFingerprint, labels); err != nil {
+		if err := forwardingRuleSetLabels(ctx, cl, act.id, ga.LabelFingerprint, labels); err != nil {
 			return nil, err
 		}
 	}
@@ -97,97 +96,95 @@ func (act *forwardingRuleCreateAction) String() string {
 
 func (act *forwardingRuleCreateAction) Metadata() *exec.ActionMetadata {
 	return &exec.ActionMetadata{
-		Name:    fmt.Sprintf("ForwardingRuleCreateAction(%s)", act.id),
-		Type:    exec.ActionTypeCreate,
-		Summary: fmt.Sprintf("Create %s", act.id),
+		Name:       fmt.Sprintf("ForwardingRuleCreateAction(%s)", act.id),
+		Type:       exec.ActionTypeCreate,
+		Summary:    fmt.Sprintf("Create %s", act.id),
+		ResourceID: act.id,
 	}
 }
 
-type forwardingRuleUpdateAction struct {
+func forwardingRuleSetTarget(
+	ctx context.Context,
+	cl cloud.Cloud,
+	id *cloud.ResourceID,
+	target *cloud.ResourceID,
+) error {
+	ref := &compute.TargetReference{Target: target.SelfLink(meta.VersionGA)}
+	switch id.Key.Type() {
+	case meta.Global:
+		return cl.GlobalForwardingRules().SetTarget(ctx, id.Key, ref, cloud.ForceProjectID(id.ProjectID))
+	case meta.Regional:
+		return cl.ForwardingRules().SetTarget(ctx, id.Key, ref, cloud.ForceProjectID(id.ProjectID))
+	}
+	return fmt.Errorf("forwardingRuleMethodsByScope: invalid scope %v", id.Key.Type())
+}
+
+// forwardingRuleSetTargetAction changes .Target using setTarget().
+type forwardingRuleSetTargetAction struct {
 	exec.ActionBase
 
-	id *cloud.ResourceID
-	// target if non-empty will call setTarget(),
+	id     *cloud.ResourceID
 	target *cloud.ResourceID
 	// oldTarget is the previous target before the update.
 	oldTarget *cloud.ResourceID
+}
 
-	// labelFingerprint for the update operation.
-	labelFingerprint string
-	// labels if non-nil will call setLabels().
-	labels map[string]string
-}
-
-func (act *forwardingRuleUpdateAction) Run(ctx context.Context, cl cloud.Cloud) (exec.EventList, error) {
-	// TODO: project routing.
-	if act.labels != nil {
-		switch act.id.Key.Type() {
-		case meta.Global:
-			err := cl.GlobalForwardingRules().SetLabels(ctx, act.id.Key, &compute.GlobalSetLabelsRequest{
-				LabelFingerprint: act.labelFingerprint,
-				Labels:           act.labels,
-			})
-			if err != nil {
-				return nil, fmt.Errorf("forwardingRuleUpdateAction Run(%s): SetLabels: %w", act.id, err)
-			}
-		case meta.Regional:
-			err := cl.ForwardingRules().SetLabels(ctx, act.id.Key, &compute.RegionSetLabelsRequest{
-				LabelFingerprint: act.labelFingerprint,
-				Labels:           act.labels,
-			})
-			if err != nil {
-				return nil, fmt.Errorf("forwardingRuleUpdateAction Run(%s): SetLabels: %w", act.id, err)
-			}
-		default:
-			return nil, fmt.Errorf("forwardingRuleUpdateAction Run(%s): invalid key type", act.id)
-		}
-	}
-
-	if act.target != nil {
-		switch act.id.Key.Type() {
-		case meta.Global:
-			err := cl.GlobalForwardingRules().SetTarget(ctx, act.id.Key, &compute.TargetReference{
-				Target: act.target.SelfLink(meta.VersionGA),
-			})
-			if err != nil {
-				return nil, fmt.Errorf("forwardingRuleUpdateAction Run(%s): SetTarget: %w", act.id, err)
-			}
-		case meta.Regional:
-			err := cl.GlobalForwardingRules().SetTarget(ctx, act.id.Key, &compute.TargetReference{
-				Target: act.target.SelfLink(meta.VersionGA),
-			})
-			if err != nil {
-				return nil, fmt.Errorf("forwardingRuleUpdateAction Run(%s): SetTarget: %w", act.id, err)
-			}
-		default:
-			return nil, fmt.Errorf("forwardingRuleUpdateAction Run(%s): invalid key type", act.id)
-		}
+func (act *forwardingRuleSetTargetAction) Run(ctx context.Context, cl cloud.Cloud) (exec.EventList, error) {
+	if err := forwardingRuleSetTarget(ctx, cl, act.id, act.target); err != nil {
+		return nil, fmt.Errorf("forwardingRuleSetTargetAction Run(%s): %w", act.id, err)
 	}
+	return act.DryRun(), nil
+}
 
+func (act *forwardingRuleSetTargetAction) DryRun() exec.EventList {
 	var events exec.EventList
 	if act.oldTarget != nil && !act.target.Equal(act.oldTarget) {
 		events = append(events, exec.NewDropRefEvent(act.id, act.oldTarget))
 	}
+	return events
+}
 
-	return events, nil
+func (act *forwardingRuleSetTargetAction) String() string {
+	return fmt.Sprintf("ForwardingRuleSetTargetAction(%s)", act.id)
 }
 
-func (act *forwardingRuleUpdateAction) DryRun() exec.EventList {
-	var events exec.EventList
-	if act.oldTarget != nil && !act.target.Equal(act.oldTarget) {
-		events = append(events, exec.NewDropRefEvent(act.id, act.oldTarget))
+func (act *forwardingRuleSetTargetAction) Metadata() *exec.ActionMetadata {
+	return &exec.ActionMetadata{
+		Name:       fmt.Sprintf("ForwardingRuleSetTargetAction(%s)", act.id),
+		Type:       exec.ActionTypeUpdate,
+		Summary:    fmt.Sprintf("Set target of %s to %s", act.id, act.target),
+		ResourceID: act.id,
 	}
-	return events
 }
 
-func (act *forwardingRuleUpdateAction) String() string {
-	return fmt.Sprintf("ForwardingRuleUpdateAction(%s)", act.id)
+// forwardingRuleSetLabelsAction changes .Labels using setLabels().
+type forwardingRuleSetLabelsAction struct {
+	exec.ActionBase
+
+	id *cloud.ResourceID
+	// labelFingerprint for the update operation.
+	labelFingerprint string
+	labels           map[string]string
+}
+
+func (act *forwardingRuleSetLabelsAction) Run(ctx context.Context, cl cloud.Cloud) (exec.EventList, error) {
+	if err := forwardingRuleSetLabels(ctx, cl, act.id, act.labelFingerprint, act.labels); err != nil {
+		return nil, fmt.Errorf("forwardingRuleSetLabelsAction Run(%s): %w", act.id, err)
+	}
+	return nil, nil
+}
+
+func (act *forwardingRuleSetLabelsAction) DryRun() exec.EventList { return nil }
+
+func (act *forwardingRuleSetLabelsAction) String() string {
+	return fmt.Sprintf("ForwardingRuleSetLabelsAction(%s)", act.id)
 }
 
-func (act *forwardingRuleUpdateAction) Metadata() *exec.ActionMetadata {
+func (act *forwardingRuleSetLabelsAction) Metadata() *exec.ActionMetadata {
 	return &exec.ActionMetadata{
-		Name:    fmt.Sprintf("ForwardingRuleUpdateAction(%s)", act.id),
-		Type:    exec.ActionTypeUpdate,
-		Summary: fmt.Sprintf("Update %s", act.id),
+		Name:       fmt.Sprintf("ForwardingRuleSetLabelsAction(%s)", act.id),
+		Type:       exec.ActionTypeUpdate,
+		Summary:    fmt.Sprintf("Set labels of %s", act.id),
+		ResourceID: act.id,
 	}
 }
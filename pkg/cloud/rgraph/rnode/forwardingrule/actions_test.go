@@ -24,55 +24,172 @@ import (
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/exec"
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/targethttpproxy"
+	"google.golang.org/api/compute/v1"
 )
 
 func TestCreateAction(t *testing.T) {
 	// TODO
 }
 
-func TestUpdateAction(t *testing.T) {
-	id := ID("proj", meta.GlobalKey("fr"))
+func TestSetTargetAction(t *testing.T) {
 	targetID := targethttpproxy.ID("proj", meta.GlobalKey("tp"))
 	oldTargetID := targethttpproxy.ID("proj", meta.GlobalKey("tp2"))
 
 	for _, tc := range []struct {
-		name       string
-		action     *forwardingRuleUpdateAction
-		wantEvents exec.EventList
+		name string
+		key  *meta.Key
 	}{
-		{
-			name: "update target",
-			action: &forwardingRuleUpdateAction{
+		{name: "global", key: meta.GlobalKey("fr")},
+		{name: "regional", key: meta.RegionalKey("fr", "us-central1")},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			id := ID("proj", tc.key)
+			action := &forwardingRuleSetTargetAction{
 				id:        id,
 				target:    targetID,
 				oldTarget: oldTargetID,
-			},
-			wantEvents: exec.EventList{
-				exec.NewDropRefEvent(id, oldTargetID),
-			},
-		},
-		{
-			name: "update label",
-			action: &forwardingRuleUpdateAction{
-				id:     id,
-				labels: map[string]string{"foo": "bar"},
-			},
-		},
-	} {
-		t.Run(tc.name, func(t *testing.T) {
+			}
+			wantEvents := exec.EventList{exec.NewDropRefEvent(id, oldTargetID)}
+
 			mock := cloud.NewMockGCE(&cloud.SingleProjectRouter{ID: "proj"})
+			var gotKey *meta.Key
+			var gotTarget string
+			mock.MockGlobalForwardingRules.SetTargetHook = func(_ context.Context, key *meta.Key, ref *compute.TargetReference, _ *cloud.MockGlobalForwardingRules, _ ...cloud.Option) error {
+				gotKey, gotTarget = key, ref.Target
+				return nil
+			}
+			mock.MockForwardingRules.SetTargetHook = func(_ context.Context, key *meta.Key, ref *compute.TargetReference, _ *cloud.MockForwardingRules, _ ...cloud.Option) error {
+				gotKey, gotTarget = key, ref.Target
+				return nil
+			}
 
-			events := tc.action.DryRun()
-			if !exec.EventList(events).Equal(tc.wantEvents) {
-				t.Errorf("DryRun() = %v, want %v", events, tc.wantEvents)
+			events := action.DryRun()
+			if !events.Equal(wantEvents) {
+				t.Errorf("DryRun() = %v, want %v", events, wantEvents)
 			}
-			events, err := tc.action.Run(context.Background(), mock)
+			events, err := action.Run(context.Background(), mock)
 			if err != nil {
 				t.Fatalf("Run() = %v, want nil", err)
 			}
-			if !exec.EventList(events).Equal(tc.wantEvents) {
-				t.Errorf("DryRun() = %v, want %v", events, tc.wantEvents)
+			if !events.Equal(wantEvents) {
+				t.Errorf("Run() = %v, want %v", events, wantEvents)
+			}
+			if gotKey == nil || *gotKey != *tc.key {
+				t.Errorf("SetTarget() key = %v, want %v", gotKey, tc.key)
+			}
+			if want := targetID.SelfLink(meta.VersionGA); gotTarget != want {
+				t.Errorf("SetTarget() target = %q, want %q", gotTarget, want)
 			}
 		})
 	}
 }
+
+func TestSetLabelsAction(t *testing.T) {
+	id := ID("proj", meta.GlobalKey("fr"))
+	action := &forwardingRuleSetLabelsAction{
+		id:               id,
+		labelFingerprint: "fp",
+		labels:           map[string]string{"foo": "bar"},
+	}
+
+	mock := cloud.NewMockGCE(&cloud.SingleProjectRouter{ID: "proj"})
+	var got *compute.GlobalSetLabelsRequest
+	mock.MockGlobalForwardingRules.SetLabelsHook = func(_ context.Context, _ *meta.Key, req *compute.GlobalSetLabelsRequest, _ *cloud.MockGlobalForwardingRules, _ ...cloud.Option) error {
+		got = req
+		return nil
+	}
+
+	if events := action.DryRun(); len(events) != 0 {
+		t.Errorf("DryRun() = %v, want none", events)
+	}
+	if _, err := action.Run(context.Background(), mock); err != nil {
+		t.Fatalf("Run() = %v, want nil", err)
+	}
+	if got == nil || got.LabelFingerprint != "fp" || got.Labels["foo"] != "bar" {
+		t.Errorf("SetLabels() request = %+v, want fingerprint \"fp\" and labels %v", got, action.labels)
+	}
+}
+
+// optionRecorder records the options passed to SetTarget() and SetLabels();
+// the generated mock does not forward them to the hooks.
+type optionRecorder struct {
+	*cloud.MockGCE
+	opts []cloud.Option
+}
+
+func (r *optionRecorder) GlobalForwardingRules() cloud.GlobalForwardingRules {
+	return &recordingGlobalForwardingRules{GlobalForwardingRules: r.MockGCE.GlobalForwardingRules(), r: r}
+}
+
+func (r *optionRecorder) ForwardingRules() cloud.ForwardingRules {
+	return &recordingForwardingRules{ForwardingRules: r.MockGCE.ForwardingRules(), r: r}
+}
+
+type recordingGlobalForwardingRules struct {
+	cloud.GlobalForwardingRules
+	r *optionRecorder
+}
+
+func (g *recordingGlobalForwardingRules) SetTarget(ctx context.Context, key *meta.Key, ref *compute.TargetReference, options ...cloud.Option) error {
+	g.r.opts = options
+	return g.GlobalForwardingRules.SetTarget(ctx, key, ref, options...)
+}
+
+func (g *recordingGlobalForwardingRules) SetLabels(ctx context.Context, key *meta.Key, req *compute.GlobalSetLabelsRequest, options ...cloud.Option) error {
+	g.r.opts = options
+	return g.GlobalForwardingRules.SetLabels(ctx, key, req, options...)
+}
+
+type recordingForwardingRules struct {
+	cloud.ForwardingRules
+	r *optionRecorder
+}
+
+func (f *recordingForwardingRules) SetTarget(ctx context.Context, key *meta.Key, ref *compute.TargetReference, options ...cloud.Option) error {
+	f.r.opts = options
+	return f.ForwardingRules.SetTarget(ctx, key, ref, options...)
+}
+
+func (f *recordingForwardingRules) SetLabels(ctx context.Context, key *meta.Key, req *compute.RegionSetLabelsRequest, options ...cloud.Option) error {
+	f.r.opts = options
+	return f.ForwardingRules.SetLabels(ctx, key, req, options...)
+}
+
+func TestActionsForceProject(t *testing.T) {
+	targetID := targethttpproxy.ID("proj", meta.GlobalKey("tp"))
+
+	for _, tc := range []struct {
+		name   string
+		action func(id *cloud.ResourceID) exec.Action
+	}{
+		{
+			name: "SetTarget",
+			action: func(id *cloud.ResourceID) exec.Action {
+				return &forwardingRuleSetTargetAction{id: id, target: targetID}
+			},
+		},
+		{
+			name: "SetLabels",
+			action: func(id *cloud.ResourceID) exec.Action {
+				return &forwardingRuleSetLabelsAction{id: id, labels: map[string]string{"foo": "bar"}}
+			},
+		},
+	} {
+		for _, key := range []*meta.Key{meta.GlobalKey("fr"), meta.RegionalKey("fr", "us-central1")} {
+			t.Run(tc.name+" "+key.String(), func(t *testing.T) {
+				// The resource is not in the default project of the router.
+				cl := &optionRecorder{MockGCE: cloud.NewMockGCE(&cloud.SingleProjectRouter{ID: "other-proj"})}
+				if _, err := tc.action(ID("proj", key)).Run(context.Background(), cl); err != nil {
+					t.Fatalf("Run() = %v, want nil", err)
+				}
+				found := false
+				for _, o := range cl.opts {
+					found = found || o == cloud.ForceProjectID("proj")
+				}
+				if !found {
+					t.Errorf("options = %v, want ForceProjectID(%q)", cl.opts, "proj")
+				}
+			})
+		}
+	}
+}
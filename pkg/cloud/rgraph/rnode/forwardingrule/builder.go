@@ -80,7 +80,7 @@ func (b *builder) OutRefs() ([]rnode.ResourceRef, error) {
 			// Numeric IP address. This is an emphemeral address that does't
 			// have a resource associated with it.
 		} else {
-			id, err := cloud.ParseResourceURL(obj.IPAddress)
+			id, err := b.ParseRefURL(obj.IPAddress)
 			if err != nil {
 				return nil, fmt.Errorf("ForwardingRuleNode IPAddress: %w", err)
 			}
@@ -103,7 +103,7 @@ func (b *builder) OutRefs() ([]rnode.ResourceRef, error) {
 		if fieldSpec.val == "" {
 			continue
 		}
-		id, err := cloud.ParseResourceURL(fieldSpec.val)
+		id, err := b.ParseRefURL(fieldSpec.val)
 		if err != nil {
 			return nil, fmt.Errorf("ForwardingRuleNode %s: %w", fieldSpec.name, err)
 		}
@@ -117,12 +117,19 @@ func (b *builder) OutRefs() ([]rnode.ResourceRef, error) {
 	return ret, nil
 }
 
+func (b *builder) Clone() rnode.Builder {
+	return &builder{
+		BuilderBase: b.CopyBase(),
+		resource:    rnode.CloneResource(b.resource),
+	}
+}
+
 func (b *builder) Build() (rnode.Node, error) {
 	if b.State() == rnode.NodeExists && b.resource == nil {
 		return nil, fmt.Errorf("ForwardingRule %s resource is nil with state %s", b.ID(), b.State())
 	}
 
-	ret := &forwardingRuleNode{resource: b.resource}
+	ret := &forwardingRuleNode{resource: rnode.CloneResource(b.resource)}
 	if err := ret.InitFromBuilder(b); err != nil {
 		return nil, err
 	}
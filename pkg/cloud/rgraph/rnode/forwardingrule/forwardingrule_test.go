@@ -20,6 +20,7 @@ import (
 	"testing"
 
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	alpha "google.golang.org/api/compute/v0.alpha"
 	"google.golang.org/api/compute/v1"
 )
 
@@ -100,3 +101,59 @@ func TestForwardingRuleFieldTraits(t *testing.T) {
 		})
 	}
 }
+
+func TestForwardingRuleAlphaFieldTraits(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		a, b     *alpha.ForwardingRule
+		wantDiff bool
+	}{
+		{
+			name: "ignored fields",
+			a: &alpha.ForwardingRule{
+				Name:           "addr-1",
+				Target:         "ZZZ",
+				SelfLinkWithId: "zzz",
+			},
+			b: &alpha.ForwardingRule{
+				Name:   "addr-1",
+				Target: "ZZZ",
+			},
+		},
+		{
+			name: "non-ignored fields",
+			a: &alpha.ForwardingRule{
+				Name:         "addr-1",
+				Target:       "ZZZ",
+				IpCollection: "aaa",
+			},
+			b: &alpha.ForwardingRule{
+				Name:   "addr-1",
+				Target: "ZZZ",
+			},
+			wantDiff: true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			a := NewMutableForwardingRule("p1", meta.GlobalKey("addr-1"))
+			if err := a.SetAlpha(tc.a); err != nil {
+				t.Fatalf("SetAlpha() = %v, want nil", err)
+			}
+			b := NewMutableForwardingRule("p1", meta.GlobalKey("addr-1"))
+			if err := b.SetAlpha(tc.b); err != nil {
+				t.Fatalf("SetAlpha() = %v, want nil", err)
+			}
+
+			fa, _ := a.Freeze()
+			fb, _ := b.Freeze()
+
+			r, err := fa.Diff(fb)
+			if err != nil {
+				t.Fatalf("Diff() = %v, want nil", err)
+			}
+			if r.HasDiff() != tc.wantDiff {
+				t.Errorf("result = %+v, HasDiff() = %t, want %t", r, r.HasDiff(), tc.wantDiff)
+			}
+		})
+	}
+}
@@ -18,6 +18,7 @@ package forwardingrule
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/api"
@@ -52,19 +53,17 @@ type changedFields struct {
 // process an item from the diff. returns true if the item can be handled
 // without recreating the resource.
 func (c *changedFields) process(item api.DiffItem) bool {
-	var messages []string
-
 	switch {
 	case api.Path{}.Pointer().Field("Target").Equal(item.Path):
-		c.messages = append(messages, fmt.Sprintf("Target (%q -> %q)", item.A, item.B))
+		c.messages = append(c.messages, fmt.Sprintf("Target (%q -> %q)", item.A, item.B))
 		c.target = true
 		return true
 	case item.Path.HasPrefix(api.Path{}.Pointer().Field("Labels")):
-		c.messages = append(messages, fmt.Sprintf("Labels (%v -> %v)", item.A, item.B))
+		c.messages = append(c.messages, fmt.Sprintf("Labels (%v -> %v)", item.A, item.B))
 		c.labels = true
 		return true
 	default:
-		c.messages = append(messages, fmt.Sprintf("%s (%v -> %v)", item.Path, item.A, item.B))
+		c.messages = append(c.messages, fmt.Sprintf("%s (%v -> %v)", item.Path, item.A, item.B))
 		c.other = true
 	}
 
@@ -91,14 +90,14 @@ func (n *forwardingRuleNode) Diff(gotNode rnode.Node) (*rnode.PlanDetails, error
 		if !changed.other {
 			return &rnode.PlanDetails{
 				Operation: rnode.OpUpdate,
-				Why:       fmt.Sprintf("update in place (changed=%+v)", changed),
+				Why:       "update in place: " + strings.Join(changed.messages, ", "),
 				Diff:      diff,
 			}, nil
 		}
 
 		return &rnode.PlanDetails{
 			Operation: rnode.OpRecreate,
-			Why:       "needs to be recreated",
+			Why:       "needs to be recreated: " + strings.Join(changed.messages, ", "),
 			Diff:      diff,
 		}, nil
 	}
@@ -133,7 +132,7 @@ func (n *forwardingRuleNode) Actions(got rnode.Node) ([]exec.Action, error) {
 
 func (n *forwardingRuleNode) Builder() rnode.Builder {
 	b := &builder{}
-	b.Init(n.ID(), n.State(), n.Ownership(), n.resource)
+	b.InitFromNode(n)
 	return b
 }
 
@@ -157,14 +156,18 @@ func (n *forwardingRuleNode) updateActions(ngot rnode.Node) ([]exec.Action, erro
 		return nil, nodeErr("updateActions: node %s has invalid type %T", n.ID(), ngot)
 	}
 
-	act := &forwardingRuleUpdateAction{id: n.ID()}
-
 	var changed changedFields
 	for _, item := range details.Diff.Items {
 		if !changed.process(item) {
 			return nil, nodeErr("updateActions %s: field %s cannot be updated in place", n.ID(), item.Path)
 		}
 	}
+
+	// Action: Signal resource exists.
+	actions := []exec.Action{exec.NewExistsAction(n.ID())}
+
+	// Only the fields that changed are updated; ForwardingRule does not
+	// support a full update.
 	if changed.target {
 		oldTarget, err := parseTarget(fmt.Sprintf("updateActions %s", n.ID()), got)
 		if err != nil {
@@ -174,24 +177,25 @@ func (n *forwardingRuleNode) updateActions(ngot rnode.Node) ([]exec.Action, erro
 		if err != nil {
 			return nil, err
 		}
-		act.Want = append(act.Want, exec.NewExistsEvent(target))
-		act.oldTarget = oldTarget
-		act.target = target
+		actions = append(actions, &forwardingRuleSetTargetAction{
+			ActionBase: exec.ActionBase{Want: exec.EventList{exec.NewExistsEvent(target)}},
+			id:         n.ID(),
+			target:     target,
+			oldTarget:  oldTarget,
+		})
 	}
 
 	if changed.labels {
 		gotRes, _ := got.resource.ToGA()
 		wantRes, _ := n.resource.ToGA()
-		act.labelFingerprint = gotRes.LabelFingerprint
-		act.labels = wantRes.Labels
+		actions = append(actions, &forwardingRuleSetLabelsAction{
+			id:               n.ID(),
+			labelFingerprint: gotRes.LabelFingerprint,
+			labels:           wantRes.Labels,
+		})
 	}
 
-	return []exec.Action{
-		// Action: Signal resource exists.
-		exec.NewExistsAction(n.ID()),
-		// Action: Do the updates.
-		act,
-	}, nil
+	return actions, nil
 }
 
 func parseTarget(errPrefix string, n *forwardingRuleNode) (*cloud.ResourceID, error) {
@@ -182,7 +182,7 @@ func TestDiffAndActions(t *testing.T) {
 			wantOp:   rnode.OpUpdate,
 			wantActions: []string{
 				"EventAction([Exists(compute/forwardingRules:proj/fr)])",
-				"ForwardingRuleUpdateAction(compute/forwardingRules:proj/fr)",
+				"ForwardingRuleSetTargetAction(compute/forwardingRules:proj/fr)",
 			},
 		},
 		{
@@ -199,7 +199,26 @@ func TestDiffAndActions(t *testing.T) {
 			wantOp:   rnode.OpUpdate,
 			wantActions: []string{
 				"EventAction([Exists(compute/forwardingRules:proj/fr)])",
-				"ForwardingRuleUpdateAction(compute/forwardingRules:proj/fr)",
+				"ForwardingRuleSetLabelsAction(compute/forwardingRules:proj/fr)",
+			},
+		},
+		{
+			name: "update .Target and .Labels",
+			frw: makeFR(func(x *compute.ForwardingRule) {
+				baseFields(x)
+				x.Labels = map[string]string{"foo": "bar"}
+			}, 0),
+			frg: makeFR(func(x *compute.ForwardingRule) {
+				baseFields(x)
+				x.Target = targetID2.SelfLink(meta.VersionGA)
+				x.Labels = map[string]string{"foo": "bar2"}
+			}, ignoreAccessErr),
+			wantDiff: true,
+			wantOp:   rnode.OpUpdate,
+			wantActions: []string{
+				"EventAction([Exists(compute/forwardingRules:proj/fr)])",
+				"ForwardingRuleSetTargetAction(compute/forwardingRules:proj/fr)",
+				"ForwardingRuleSetLabelsAction(compute/forwardingRules:proj/fr)",
 			},
 		},
 		{
@@ -0,0 +1,190 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package forwardingrule
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/cerrors"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/exec"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/address"
+	"google.golang.org/api/compute/v1"
+)
+
+// NewPromoteIPAction returns an Action that reserves the ephemeral IP of the
+// ForwardingRule fr as the static Address addr. The Action runs after fr
+// exists, so it can be appended to the Actions of a plan that creates the
+// ForwardingRule. addr must be in the same scope (global or region) as fr.
+//
+// onPromote, if non-nil, is called with the IP after the Address is
+// reserved. Use AddPromotedAddress to record the Address in the graph for
+// subsequent syncs.
+func NewPromoteIPAction(fr, addr *cloud.ResourceID, onPromote func(ip string)) exec.Action {
+	return &promoteIPAction{
+		ActionBase: exec.ActionBase{Want: exec.EventList{exec.NewExistsEvent(fr)}},
+		fr:         fr,
+		addr:       addr,
+		onPromote:  onPromote,
+	}
+}
+
+type promoteIPAction struct {
+	exec.ActionBase
+	fr        *cloud.ResourceID
+	addr      *cloud.ResourceID
+	onPromote func(ip string)
+}
+
+func (act *promoteIPAction) Run(ctx context.Context, cl cloud.Cloud) (exec.EventList, error) {
+	if act.fr.Key.Type() != act.addr.Key.Type() || act.fr.Key.Region != act.addr.Key.Region {
+		return nil, fmt.Errorf("promoteIPAction(%s): Address %s is not in the same scope", act.fr, act.addr)
+	}
+	res, err := (&ops{}).GetFuncs(cl).Do(ctx, meta.VersionGA, act.fr, &typeTrait{})
+	if err != nil {
+		return nil, fmt.Errorf("promoteIPAction(%s): %w", act.fr, err)
+	}
+	fr, _ := res.ToGA()
+	if net.ParseIP(fr.IPAddress) == nil {
+		return nil, fmt.Errorf("promoteIPAction(%s): IPAddress %q is not an ephemeral IP", act.fr, fr.IPAddress)
+	}
+
+	obj := &compute.Address{
+		Name:    act.addr.Key.Name,
+		Address: fr.IPAddress,
+	}
+	if act.addr.Key.Type() == meta.Regional {
+		if strings.HasPrefix(fr.LoadBalancingScheme, "INTERNAL") {
+			obj.AddressType = "INTERNAL"
+			obj.Subnetwork = fr.Subnetwork
+		}
+		obj.NetworkTier = fr.NetworkTier
+	}
+
+	// TODO: project routing.
+	if act.addr.Key.Type() == meta.Regional {
+		err = cl.Addresses().Insert(ctx, act.addr.Key, obj)
+	} else {
+		err = cl.GlobalAddresses().Insert(ctx, act.addr.Key, obj)
+	}
+	if cerrors.IsGoogleAPIAlreadyExists(err) {
+		// The IP may have been promoted by a previous attempt.
+		err = act.checkExisting(ctx, cl, fr.IPAddress)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("promoteIPAction(%s): %w", act.fr, err)
+	}
+
+	if act.onPromote != nil {
+		act.onPromote(fr.IPAddress)
+	}
+	return act.DryRun(), nil
+}
+
+// checkExisting returns nil if the Address exists and holds ip.
+func (act *promoteIPAction) checkExisting(ctx context.Context, cl cloud.Cloud, ip string) error {
+	var (
+		a   *compute.Address
+		err error
+	)
+	if act.addr.Key.Type() == meta.Regional {
+		a, err = cl.Addresses().Get(ctx, act.addr.Key)
+	} else {
+		a, err = cl.GlobalAddresses().Get(ctx, act.addr.Key)
+	}
+	if err != nil {
+		return err
+	}
+	if a.Address != ip {
+		return fmt.Errorf("Address %s already exists with a different IP (%q, want %q)", act.addr, a.Address, ip)
+	}
+	return nil
+}
+
+func (act *promoteIPAction) DryRun() exec.EventList {
+	return exec.EventList{exec.NewExistsEvent(act.addr)}
+}
+
+func (act *promoteIPAction) String() string {
+	return fmt.Sprintf("PromoteIPAction(%s => %s)", act.fr, act.addr)
+}
+
+func (act *promoteIPAction) Metadata() *exec.ActionMetadata {
+	return &exec.ActionMetadata{
+		Name:       fmt.Sprintf("PromoteIPAction(%s)", act.fr),
+		Type:       exec.ActionTypeCreate,
+		Summary:    fmt.Sprintf("Reserve the IP of %s as %s", act.fr, act.addr),
+		ResourceID: act.addr,
+	}
+}
+
+// AddPromotedAddress records an IP promoted by NewPromoteIPAction in the
+// graph. The Address addr is added to the Builder with the IP and, if the
+// ForwardingRule fr is in the Builder, its IPAddress is set to the IP so that
+// the ForwardingRule keeps the IP if it is recreated.
+func AddPromotedAddress(b *rgraph.Builder, fr, addr *cloud.ResourceID, ip string) error {
+	obj := &compute.Address{Name: addr.Key.Name, Address: ip}
+
+	if frb := b.Get(fr); frb != nil && frb.Resource() != nil {
+		frRes, ok := frb.Resource().(ForwardingRule)
+		if !ok {
+			return fmt.Errorf("AddPromotedAddress: invalid ForwardingRule resource %T", frb.Resource())
+		}
+		frObj, err := frRes.ToGA()
+		if err != nil {
+			return fmt.Errorf("AddPromotedAddress: %w", err)
+		}
+		if addr.Key.Type() == meta.Regional && strings.HasPrefix(frObj.LoadBalancingScheme, "INTERNAL") {
+			obj.AddressType = "INTERNAL"
+			obj.Subnetwork = frObj.Subnetwork
+		}
+
+		cp := *frObj
+		cp.IPAddress = ip
+		m := NewMutableForwardingRule(fr.ProjectID, fr.Key)
+		if err := m.Set(&cp); err != nil {
+			return fmt.Errorf("AddPromotedAddress: %w", err)
+		}
+		r, err := m.Freeze()
+		if err != nil {
+			return fmt.Errorf("AddPromotedAddress: %w", err)
+		}
+		if err := frb.SetResource(r); err != nil {
+			return fmt.Errorf("AddPromotedAddress: %w", err)
+		}
+	}
+
+	m := address.NewMutableAddress(addr.ProjectID, addr.Key)
+	if err := m.Set(obj); err != nil {
+		return fmt.Errorf("AddPromotedAddress: %w", err)
+	}
+	r, err := m.Freeze()
+	if err != nil {
+		return fmt.Errorf("AddPromotedAddress: %w", err)
+	}
+	nb := address.NewBuilderWithResource(r)
+	nb.SetState(rnode.NodeExists)
+	nb.SetOwnership(rnode.OwnershipManaged)
+	b.Add(nb)
+	return nil
+}
@@ -0,0 +1,199 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package forwardingrule
+
+import (
+	"context"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/exec"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/address"
+	"google.golang.org/api/compute/v1"
+)
+
+func TestPromoteIPAction(t *testing.T) {
+	const ip = "10.0.0.5"
+
+	for _, tc := range []struct {
+		name     string
+		frKey    *meta.Key
+		addrKey  *meta.Key
+		fr       *compute.ForwardingRule
+		existing *compute.Address
+
+		wantAddr *compute.Address
+		wantErr  bool
+	}{
+		{
+			name:     "global",
+			frKey:    meta.GlobalKey("fr"),
+			addrKey:  meta.GlobalKey("addr"),
+			fr:       &compute.ForwardingRule{IPAddress: ip, LoadBalancingScheme: "EXTERNAL_MANAGED"},
+			wantAddr: &compute.Address{Address: ip},
+		},
+		{
+			name:    "regional internal",
+			frKey:   meta.RegionalKey("fr", "us-central1"),
+			addrKey: meta.RegionalKey("addr", "us-central1"),
+			fr: &compute.ForwardingRule{
+				IPAddress:           ip,
+				LoadBalancingScheme: "INTERNAL",
+				Subnetwork:          "subnet",
+			},
+			wantAddr: &compute.Address{Address: ip, AddressType: "INTERNAL", Subnetwork: "subnet"},
+		},
+		{
+			name:     "already promoted",
+			frKey:    meta.GlobalKey("fr"),
+			addrKey:  meta.GlobalKey("addr"),
+			fr:       &compute.ForwardingRule{IPAddress: ip},
+			existing: &compute.Address{Address: ip},
+			wantAddr: &compute.Address{Address: ip},
+		},
+		{
+			name:     "address exists with different IP",
+			frKey:    meta.GlobalKey("fr"),
+			addrKey:  meta.GlobalKey("addr"),
+			fr:       &compute.ForwardingRule{IPAddress: ip},
+			existing: &compute.Address{Address: "10.0.0.6"},
+			wantErr:  true,
+		},
+		{
+			name:    "no IP",
+			frKey:   meta.GlobalKey("fr"),
+			addrKey: meta.GlobalKey("addr"),
+			fr:      &compute.ForwardingRule{},
+			wantErr: true,
+		},
+		{
+			name:    "different scope",
+			frKey:   meta.RegionalKey("fr", "us-central1"),
+			addrKey: meta.GlobalKey("addr"),
+			fr:      &compute.ForwardingRule{IPAddress: ip},
+			wantErr: true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			mock := cloud.NewMockGCE(&cloud.SingleProjectRouter{ID: "proj"})
+
+			frID := ID("proj", tc.frKey)
+			addrID := address.ID("proj", tc.addrKey)
+
+			var err error
+			if tc.frKey.Type() == meta.Regional {
+				err = mock.ForwardingRules().Insert(ctx, tc.frKey, tc.fr)
+			} else {
+				err = mock.GlobalForwardingRules().Insert(ctx, tc.frKey, tc.fr)
+			}
+			if err != nil {
+				t.Fatalf("Insert(%v) = %v", tc.frKey, err)
+			}
+			if tc.existing != nil {
+				if err := mock.GlobalAddresses().Insert(ctx, tc.addrKey, tc.existing); err != nil {
+					t.Fatalf("Insert(%v) = %v", tc.addrKey, err)
+				}
+			}
+
+			var gotIP string
+			action := NewPromoteIPAction(frID, addrID, func(ip string) { gotIP = ip })
+			wantEvents := exec.EventList{exec.NewExistsEvent(addrID)}
+
+			if events := action.DryRun(); !events.Equal(wantEvents) {
+				t.Errorf("DryRun() = %v, want %v", events, wantEvents)
+			}
+			events, err := action.Run(ctx, mock)
+			if gotErr := err != nil; gotErr != tc.wantErr {
+				t.Fatalf("Run() = %v; gotErr = %t, want %t", err, gotErr, tc.wantErr)
+			}
+			if tc.wantErr {
+				return
+			}
+			if !events.Equal(wantEvents) {
+				t.Errorf("Run() = %v, want %v", events, wantEvents)
+			}
+			if gotIP != ip {
+				t.Errorf("onPromote() ip = %q, want %q", gotIP, ip)
+			}
+
+			var got *compute.Address
+			if tc.addrKey.Type() == meta.Regional {
+				got, err = mock.Addresses().Get(ctx, tc.addrKey)
+			} else {
+				got, err = mock.GlobalAddresses().Get(ctx, tc.addrKey)
+			}
+			if err != nil {
+				t.Fatalf("Get(%v) = %v", tc.addrKey, err)
+			}
+			if got.Address != tc.wantAddr.Address || got.Subnetwork != tc.wantAddr.Subnetwork {
+				t.Errorf("Address = %+v, want %+v", got, tc.wantAddr)
+			}
+			if tc.wantAddr.AddressType != "" && got.AddressType != tc.wantAddr.AddressType {
+				t.Errorf("AddressType = %q, want %q", got.AddressType, tc.wantAddr.AddressType)
+			}
+		})
+	}
+}
+
+func TestAddPromotedAddress(t *testing.T) {
+	const ip = "10.0.0.5"
+
+	frID := ID("proj", meta.GlobalKey("fr"))
+	addrID := address.ID("proj", meta.GlobalKey("addr"))
+
+	m := NewMutableForwardingRule("proj", frID.Key)
+	m.Access(func(x *compute.ForwardingRule) {
+		x.LoadBalancingScheme = "EXTERNAL_MANAGED"
+		x.Target = "https://www.googleapis.com/compute/v1/projects/proj/global/targetHttpProxies/thp"
+	})
+	r, err := m.Freeze()
+	if err != nil {
+		t.Fatalf("Freeze() = %v", err)
+	}
+	b := rgraph.NewBuilder()
+	b.Add(NewBuilderWithResource(r))
+
+	if err := AddPromotedAddress(b, frID, addrID, ip); err != nil {
+		t.Fatalf("AddPromotedAddress() = %v", err)
+	}
+
+	frRes, _ := b.Get(frID).Resource().(ForwardingRule)
+	fr, _ := frRes.ToGA()
+	if fr.IPAddress != ip {
+		t.Errorf("ForwardingRule.IPAddress = %q, want %q", fr.IPAddress, ip)
+	}
+	if fr.LoadBalancingScheme != "EXTERNAL_MANAGED" {
+		t.Errorf("ForwardingRule.LoadBalancingScheme = %q, want EXTERNAL_MANAGED", fr.LoadBalancingScheme)
+	}
+
+	ab := b.Get(addrID)
+	if ab == nil {
+		t.Fatalf("b.Get(%v) = nil, want Address", addrID)
+	}
+	if ab.State() != rnode.NodeExists || ab.Ownership() != rnode.OwnershipManaged {
+		t.Errorf("Address state, ownership = %v, %v; want NodeExists, OwnershipManaged", ab.State(), ab.Ownership())
+	}
+	addrRes, _ := ab.Resource().(address.Address)
+	addr, _ := addrRes.ToGA()
+	if addr.Address != ip {
+		t.Errorf("Address.Address = %q, want %q", addr.Address, ip)
+	}
+}
@@ -29,8 +29,9 @@ type typeTrait struct {
 	api.BaseTypeTrait[compute.ForwardingRule, alpha.ForwardingRule, beta.ForwardingRule]
 }
 
-func (*typeTrait) FieldTraits(meta.Version) *api.FieldTraits {
+func (*typeTrait) FieldTraits(v meta.Version) *api.FieldTraits {
 	dt := api.NewFieldTraits()
+	dt.Description(api.Path{}.Pointer().Field("Description"))
 
 	dt.OutputOnly(api.Path{}.Pointer().Field("BaseForwardingRule"))
 	dt.OutputOnly(api.Path{}.Pointer().Field("CreationTimestamp"))
@@ -44,7 +45,9 @@ func (*typeTrait) FieldTraits(meta.Version) *api.FieldTraits {
 	dt.OutputOnly(api.Path{}.Pointer().Field("SelfLink"))
 	dt.OutputOnly(api.Path{}.Pointer().Field("ServiceName"))
 
-	// TODO: handle alpha/beta
+	if v == meta.VersionAlpha {
+		dt.OutputOnly(api.Path{}.Pointer().Field("SelfLinkWithId"))
+	}
 
 	return dt
 }
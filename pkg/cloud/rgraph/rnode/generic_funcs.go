@@ -23,7 +23,6 @@ import (
 
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/api"
-	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/cerrors"
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
 	"k8s.io/klog/v2"
 )
@@ -47,7 +46,7 @@ type GetFuncsByScope[T any] struct {
 
 // Do the operation.
 func (s *GetFuncsByScope[T]) Do(ctx context.Context, key *meta.Key, options ...cloud.Option) (*T, error) {
-	klog.Infof("get %s", key)
+	klog.FromContext(ctx).V(4).Info("Get", "key", key)
 	switch {
 	case key.Type() == meta.Global && s.Global != nil:
 		return s.Global(ctx, key, options...)
@@ -72,9 +71,13 @@ func (f *GetFuncs[GA, Alpha, Beta]) Do(
 	tt api.TypeTrait[GA, Alpha, Beta],
 ) (api.Resource[GA, Alpha, Beta], error) {
 	current := api.NewResource(id, tt)
+	options := []cloud.Option{cloud.ForceProjectID(id.ProjectID)}
+	if o := syncFieldsOption(ctx, ver, id, tt); o != nil {
+		options = append(options, o)
+	}
 	switch ver {
 	case meta.VersionGA:
-		raw, err := f.GA.Do(ctx, id.Key, cloud.ForceProjectID(id.ProjectID))
+		raw, err := f.GA.Do(ctx, id.Key, options...)
 		if err != nil {
 			return nil, err
 		}
@@ -82,7 +85,7 @@ func (f *GetFuncs[GA, Alpha, Beta]) Do(
 			return nil, err
 		}
 	case meta.VersionAlpha:
-		raw, err := f.Alpha.Do(ctx, id.Key, cloud.ForceProjectID(id.ProjectID))
+		raw, err := f.Alpha.Do(ctx, id.Key, options...)
 		if err != nil {
 			return nil, err
 		}
@@ -90,7 +93,7 @@ func (f *GetFuncs[GA, Alpha, Beta]) Do(
 			return nil, err
 		}
 	case meta.VersionBeta:
-		raw, err := f.Beta.Do(ctx, id.Key, cloud.ForceProjectID(id.ProjectID))
+		raw, err := f.Beta.Do(ctx, id.Key, options...)
 		if err != nil {
 			return nil, err
 		}
@@ -123,17 +126,29 @@ func (s *CreateFuncsByScope[T]) Do(ctx context.Context, key *meta.Key, x *T, opt
 	return fmt.Errorf("unsupported scope (key = %s)", key)
 }
 
+const (
+	// CreateFuncsValidateOnly is set if the Insert methods accept
+	// cloud.ValidateOnly(), i.e. the service has meta.ValidateOnly. The
+	// create Action issues a validate-only Insert in a DryRun with
+	// exec.ValidateOnlyOption.
+	CreateFuncsValidateOnly = 1 << iota
+)
+
 type CreateFuncs[GA any, Alpha any, Beta any] struct {
 	GA    CreateFuncsByScope[GA]
 	Alpha CreateFuncsByScope[Alpha]
 	Beta  CreateFuncsByScope[Beta]
+
+	Options int
 }
 
 func (f *CreateFuncs[GA, Alpha, Beta]) Do(
 	ctx context.Context,
 	id *cloud.ResourceID,
 	r api.Resource[GA, Alpha, Beta],
+	options ...cloud.Option,
 ) error {
+	options = append([]cloud.Option{cloud.ForceProjectID(id.ProjectID)}, options...)
 	// TODO: Context logging
 	// TODO: span
 	switch r.Version() {
@@ -142,7 +157,7 @@ func (f *CreateFuncs[GA, Alpha, Beta]) Do(
 		if err != nil {
 			return err
 		}
-		err = f.GA.Do(ctx, id.Key, raw, cloud.ForceProjectID(id.ProjectID))
+		err = f.GA.Do(ctx, id.Key, raw, options...)
 		if err != nil {
 			return err
 		}
@@ -152,7 +167,7 @@ func (f *CreateFuncs[GA, Alpha, Beta]) Do(
 		if err != nil {
 			return err
 		}
-		err = f.Alpha.Do(ctx, id.Key, raw, cloud.ForceProjectID(id.ProjectID))
+		err = f.Alpha.Do(ctx, id.Key, raw, options...)
 		if err != nil {
 			return err
 		}
@@ -162,7 +177,7 @@ func (f *CreateFuncs[GA, Alpha, Beta]) Do(
 		if err != nil {
 			return err
 		}
-		err = f.Beta.Do(ctx, id.Key, raw, cloud.ForceProjectID(id.ProjectID))
+		err = f.Beta.Do(ctx, id.Key, raw, options...)
 		if err != nil {
 			return err
 		}
@@ -339,7 +354,7 @@ func GenericGet[GA any, Alpha any, Beta any](
 	r, err := ops.GetFuncs(gcp).Do(ctx, b.Version(), b.ID(), typeTrait)
 
 	switch {
-	case cerrors.IsGoogleAPINotFound(err):
+	case cloud.IsNotFound(err):
 		b.SetState(NodeDoesNotExist)
 		return nil // Not found is not an error condition.
 
@@ -348,8 +363,12 @@ func GenericGet[GA any, Alpha any, Beta any](
 		return fmt.Errorf("genericGet %s: %w", resourceName, err)
 
 	default:
+		if err := checkStrictFields(ctx, typeTrait, b.ID(), r); err != nil {
+			b.SetState(NodeStateError)
+			return fmt.Errorf("genericGet %s: %w", resourceName, err)
+		}
 		b.SetState(NodeExists)
-		b.SetResource(r)
+		b.SetResource(storedResource(ctx, r))
 		return nil
 	}
 }
@@ -0,0 +1,121 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package genericnode
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode"
+	"k8s.io/klog/v2"
+)
+
+// NewBuilder returns a Builder for the resource id. The URL of the resource
+// is the GA SelfLink of id (the API URL for networkservices resources), or
+// the reference value for an rnode.UnresolvedID.
+func NewBuilder(id *cloud.ResourceID) rnode.Builder {
+	if rnode.IsUnresolved(id) {
+		return NewBuilderWithURL(id, id.Key.Name)
+	}
+	if id.APIGroup == meta.APIGroupNetworkServices {
+		return NewBuilderWithURL(id, networkServicesURL+cloud.NetworkServicesName(id))
+	}
+	return NewBuilderWithURL(id, id.SelfLink(meta.VersionGA))
+}
+
+const networkServicesURL = "https://networkservices.googleapis.com/v1/"
+
+// NewBuilderWithURL returns a Builder for the resource id at url. Use this
+// when the URL cannot be derived from id, e.g. for APIs other than compute.
+func NewBuilderWithURL(id *cloud.ResourceID, url string) rnode.Builder {
+	b := &builder{url: url}
+	b.Defaults(id)
+	b.SetOwnership(rnode.OwnershipExternal)
+	return b
+}
+
+type builder struct {
+	rnode.BuilderBase
+	url string
+}
+
+// builder implements node.Builder.
+var _ rnode.Builder = (*builder)(nil)
+
+// SetOwnership is ignored; generic nodes are always OwnershipExternal.
+func (b *builder) SetOwnership(rnode.OwnershipStatus) {
+	b.BuilderBase.SetOwnership(rnode.OwnershipExternal)
+}
+
+func (b *builder) Resource() rnode.UntypedResource { return nil }
+
+func (b *builder) SetResource(u rnode.UntypedResource) error {
+	if u != nil {
+		return fmt.Errorf("GenericNode: invalid type for SetResource: %T", u)
+	}
+	return nil
+}
+
+func (b *builder) SyncFromCloud(ctx context.Context, gcp cloud.Cloud) error {
+	if !isHTTPURL(b.url) {
+		// The existence of the resource cannot be checked, e.g. for an
+		// unresolved reference that is not a URL. Assume that it exists so
+		// that the graph can be planned.
+		klog.FromContext(ctx).Info("GenericNode: cannot check existence, assuming resource exists", "id", b.ID(), "url", b.url)
+		b.SetState(rnode.NodeExists)
+		return nil
+	}
+	err := gcp.ResourceURLs().Get(ctx, b.url)
+	switch {
+	case cloud.IsNotFound(err):
+		b.SetState(rnode.NodeDoesNotExist)
+		return nil // Not found is not an error condition.
+	case err != nil:
+		b.SetState(rnode.NodeStateError)
+		return fmt.Errorf("GenericNode %s: %w", b.url, err)
+	}
+	b.SetState(rnode.NodeExists)
+	return nil
+}
+
+func (b *builder) OutRefs() ([]rnode.ResourceRef, error) {
+	// The contents of the resource are unknown.
+	return nil, nil
+}
+
+func (b *builder) Clone() rnode.Builder {
+	return &builder{
+		BuilderBase: b.CopyBase(),
+		url:         b.url,
+	}
+}
+
+func (b *builder) Build() (rnode.Node, error) {
+	ret := &genericNode{url: b.url}
+	if err := ret.InitFromBuilder(b); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+func isHTTPURL(s string) bool {
+	u, err := url.Parse(s)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
@@ -0,0 +1,29 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package genericnode is a read-only Node for resources that do not have a
+// Node type in the rnode packages. The resource is identified by its URL; the
+// Node only checks that the resource exists with a GET on the URL
+// (cloud.ResourceURLs). This allows the graph to reference unmodeled
+// resources (e.g. a SslCertificate) and order the Actions on the references
+// without failing the plan.
+//
+// The Node is also used for the references that could not be parsed (see
+// rnode.OutRefsWarn). If the reference value is not an http(s) URL, the
+// resource is assumed to exist.
+//
+// The Node does not have a Resource and is always OwnershipExternal.
+package genericnode
@@ -0,0 +1,150 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package genericnode_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/all"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/genericnode"
+)
+
+const proj = "proj-1"
+
+func certID() *cloud.ResourceID {
+	return &cloud.ResourceID{
+		Resource:  "sslCertificates",
+		ProjectID: proj,
+		Key:       meta.GlobalKey("cert"),
+	}
+}
+
+func TestSyncFromCloud(t *testing.T) {
+	for _, tc := range []struct {
+		desc      string
+		exists    bool
+		getErr    error
+		wantState rnode.NodeState
+		wantErr   bool
+	}{
+		{desc: "exists", exists: true, wantState: rnode.NodeExists},
+		{desc: "does not exist", wantState: rnode.NodeDoesNotExist},
+		{desc: "error", getErr: errors.New("injected"), wantState: rnode.NodeStateError, wantErr: true},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			ctx := context.Background()
+			mock := cloud.NewMockGCE(&cloud.SingleProjectRouter{ID: proj})
+			id := certID()
+			url := id.SelfLink(meta.VersionGA)
+			if tc.exists {
+				mock.MockResourceURLs.Insert(url)
+			}
+			if tc.getErr != nil {
+				mock.MockResourceURLs.GetError[url] = tc.getErr
+			}
+
+			b := genericnode.NewBuilder(id)
+			err := b.SyncFromCloud(ctx, mock)
+			if gotErr := err != nil; gotErr != tc.wantErr {
+				t.Fatalf("SyncFromCloud() = %v; gotErr = %t, want %t", err, gotErr, tc.wantErr)
+			}
+			if b.State() != tc.wantState {
+				t.Errorf("State() = %v, want %v", b.State(), tc.wantState)
+			}
+		})
+	}
+}
+
+func TestNode(t *testing.T) {
+	ctx := context.Background()
+	mock := cloud.NewMockGCE(&cloud.SingleProjectRouter{ID: proj})
+	url := "https://certificatemanager.googleapis.com/v1/projects/proj-1/locations/global/certificates/cert"
+	mock.MockResourceURLs.Insert(url)
+
+	b := genericnode.NewBuilderWithURL(certID(), url)
+	b.SetOwnership(rnode.OwnershipManaged)
+	if b.Ownership() != rnode.OwnershipExternal {
+		t.Errorf("Ownership() = %v, want %v", b.Ownership(), rnode.OwnershipExternal)
+	}
+	if err := b.SyncFromCloud(ctx, mock); err != nil {
+		t.Fatalf("SyncFromCloud() = %v, want nil", err)
+	}
+	n, err := b.Build()
+	if err != nil {
+		t.Fatalf("Build() = %v, want nil", err)
+	}
+	// The Builder from the Node keeps the URL.
+	got := n.Builder()
+	if err := got.SyncFromCloud(ctx, mock); err != nil || got.State() != rnode.NodeExists {
+		t.Errorf("n.Builder().SyncFromCloud() = %v, state %v; want nil, %v", err, got.State(), rnode.NodeExists)
+	}
+	gotNode, err := got.Build()
+	if err != nil {
+		t.Fatalf("Build() = %v, want nil", err)
+	}
+
+	pd, err := n.Diff(gotNode)
+	if err != nil || pd.Operation != rnode.OpNothing {
+		t.Errorf("Diff() = %+v, %v; want %v, nil", pd, err, rnode.OpNothing)
+	}
+	n.Plan().Set(rnode.PlanDetails{Operation: rnode.OpNothing})
+	if actions, err := n.Actions(gotNode); err != nil || len(actions) != 1 {
+		t.Errorf("Actions() = %v, %v; want 1 Action", actions, err)
+	}
+	n.Plan().Set(rnode.PlanDetails{Operation: rnode.OpUpdate})
+	if _, err := n.Actions(gotNode); err == nil {
+		t.Error("Actions() = nil error for OpUpdate, want error")
+	}
+}
+
+func TestNewBuilderForRef(t *testing.T) {
+	b, err := all.NewBuilderForRef(certID())
+	if err != nil {
+		t.Fatalf("NewBuilderForRef() = %v, want nil", err)
+	}
+	if b.Ownership() != rnode.OwnershipExternal {
+		t.Errorf("Ownership() = %v, want %v", b.Ownership(), rnode.OwnershipExternal)
+	}
+}
+
+func TestUnresolved(t *testing.T) {
+	ctx := context.Background()
+	mock := cloud.NewMockGCE(&cloud.SingleProjectRouter{ID: proj})
+
+	// Values that are not URLs are assumed to exist.
+	b, err := all.NewBuilderForRef(rnode.UnresolvedID(proj, "projects/proj-1/locations/global/certificates/c"))
+	if err != nil {
+		t.Fatalf("NewBuilderForRef() = %v, want nil", err)
+	}
+	if err := b.SyncFromCloud(ctx, mock); err != nil || b.State() != rnode.NodeExists {
+		t.Errorf("SyncFromCloud() = %v, state %v; want nil, %v", err, b.State(), rnode.NodeExists)
+	}
+
+	// URLs are checked.
+	b, err = all.NewBuilderForRef(rnode.UnresolvedID(proj, "https://certificatemanager.googleapis.com/v1/projects/proj-1/locations/global/certificates/c"))
+	if err != nil {
+		t.Fatalf("NewBuilderForRef() = %v, want nil", err)
+	}
+	if err := b.SyncFromCloud(ctx, mock); err != nil || b.State() != rnode.NodeDoesNotExist {
+		t.Errorf("SyncFromCloud() = %v, state %v; want nil, %v", err, b.State(), rnode.NodeDoesNotExist)
+	}
+}
@@ -68,12 +68,19 @@ func (b *builder) OutRefs() ([]rnode.ResourceRef, error) {
 	return nil, nil
 }
 
+func (b *builder) Clone() rnode.Builder {
+	return &builder{
+		BuilderBase: b.CopyBase(),
+		resource:    rnode.CloneResource(b.resource),
+	}
+}
+
 func (b *builder) Build() (rnode.Node, error) {
 	if b.State() == rnode.NodeExists && b.resource == nil {
 		return nil, fmt.Errorf("HealthCheck %s resource is nil with state %s", b.ID(), b.State())
 	}
 
-	ret := &healthCheckNode{resource: b.resource}
+	ret := &healthCheckNode{resource: rnode.CloneResource(b.resource)}
 	if err := ret.InitFromBuilder(b); err != nil {
 		return nil, err
 	}
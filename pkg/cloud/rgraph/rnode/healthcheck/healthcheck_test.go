@@ -17,13 +17,17 @@ limitations under the License.
 package healthcheck
 
 import (
+	"context"
+	"reflect"
 	"testing"
 
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/exec"
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode"
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/fake"
 	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
 	alpha "google.golang.org/api/compute/v0.alpha"
 	beta "google.golang.org/api/compute/v0.beta"
 	"google.golang.org/api/compute/v1"
@@ -409,3 +413,118 @@ func TestAction(t *testing.T) {
 		})
 	}
 }
+
+func TestUpdatePreservesProbe(t *testing.T) {
+	ctx := context.Background()
+	id := ID(projectID, meta.GlobalKey("hc-1"))
+
+	gotHC := newDefaultHC()
+	gotHC.Type = "HTTP"
+	gotHC.HttpHealthCheck = &compute.HTTPHealthCheck{Port: 8080, RequestPath: "/healthz"}
+	gotRes := NewMutableHealthCheck(projectID, id.Key)
+	if err := gotRes.Set(&gotHC); err != nil {
+		t.Fatalf("Set() = %v, want nil", err)
+	}
+	gotFrozen, err := gotRes.Freeze()
+	if err != nil {
+		t.Fatalf("Freeze() = %v, want nil", err)
+	}
+	gb := NewBuilderWithResource(gotFrozen)
+	gb.SetState(rnode.NodeExists)
+	gotNode, err := gb.Build()
+	if err != nil {
+		t.Fatalf("Build() = %v, want nil", err)
+	}
+
+	// want only changes CheckIntervalSec and does not specify the probe.
+	wantHC := newDefaultHC()
+	wantHC.Type = "HTTP"
+	wantHC.CheckIntervalSec = 100
+	wantNode := buildHCNode(t, "hc-1", wantHC)
+
+	plan, err := wantNode.Diff(gotNode)
+	if err != nil {
+		t.Fatalf("Diff() = %v, want nil", err)
+	}
+	if plan.Operation != rnode.OpUpdate {
+		t.Fatalf("plan.Operation = %s, want %s", plan.Operation, rnode.OpUpdate)
+	}
+	wantNode.Plan().Set(*plan)
+	actions, err := wantNode.Actions(gotNode)
+	if err != nil {
+		t.Fatalf("Actions() = %v, want nil", err)
+	}
+
+	mock := cloud.NewMockGCE(&cloud.SingleProjectRouter{ID: projectID})
+	var updated *compute.HealthCheck
+	mock.MockHealthChecks.UpdateHook = func(_ context.Context, _ *meta.Key, hc *compute.HealthCheck, _ *cloud.MockHealthChecks, _ ...cloud.Option) error {
+		updated = hc
+		return nil
+	}
+	for _, a := range actions {
+		if _, err := a.Run(ctx, mock); err != nil {
+			t.Fatalf("Run() = %v, want nil", err)
+		}
+	}
+	if updated == nil {
+		t.Fatal("HealthCheck was not updated")
+	}
+	if updated.CheckIntervalSec != 100 {
+		t.Errorf("CheckIntervalSec = %d, want 100", updated.CheckIntervalSec)
+	}
+	want := &compute.HTTPHealthCheck{Port: 8080, RequestPath: "/healthz"}
+	if diff := cmp.Diff(updated.HttpHealthCheck, want, cmpopts.IgnoreFields(compute.HTTPHealthCheck{}, "ForceSendFields", "NullFields")); diff != "" {
+		t.Errorf("HttpHealthCheck: -got,+want: %s", diff)
+	}
+	// The planned want resource is not modified.
+	if w, _ := wantNode.Resource().(HealthCheck).ToGA(); w.HttpHealthCheck != nil {
+		t.Errorf("want resource was modified: HttpHealthCheck = %+v", w.HttpHealthCheck)
+	}
+}
+
+func TestMergeProbe(t *testing.T) {
+	traits := (&typeTrait{}).FieldTraits(meta.VersionGA)
+	got := func() *compute.HealthCheck {
+		return &compute.HealthCheck{
+			Type:            "HTTP",
+			HttpHealthCheck: &compute.HTTPHealthCheck{Port: 8080, RequestPath: "/healthz", ForceSendFields: []string{"Host"}},
+		}
+	}
+
+	for _, tc := range []struct {
+		name string
+		want *compute.HealthCheck
+		exp  *compute.HTTPHealthCheck
+	}{
+		{
+			name: "probe not specified",
+			want: &compute.HealthCheck{Type: "HTTP"},
+			exp:  &compute.HTTPHealthCheck{Port: 8080, RequestPath: "/healthz", ForceSendFields: []string{"Host"}},
+		},
+		{
+			name: "partial probe",
+			want: &compute.HealthCheck{Type: "HTTP", HttpHealthCheck: &compute.HTTPHealthCheck{Port: 80}},
+			exp:  &compute.HTTPHealthCheck{Port: 80, RequestPath: "/healthz", ForceSendFields: []string{"Host"}},
+		},
+		{
+			name: "explicit null field",
+			want: &compute.HealthCheck{Type: "HTTP", HttpHealthCheck: &compute.HTTPHealthCheck{Port: 80, NullFields: []string{"RequestPath"}}},
+			exp:  &compute.HTTPHealthCheck{Port: 80, NullFields: []string{"RequestPath"}, ForceSendFields: []string{"Host"}},
+		},
+		{
+			name: "probe cleared",
+			want: &compute.HealthCheck{Type: "HTTP", NullFields: []string{"HttpHealthCheck"}},
+		},
+		{
+			name: "type changed",
+			want: &compute.HealthCheck{Type: "TCP"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			mergeProbe(traits, reflect.ValueOf(got()), reflect.ValueOf(tc.want))
+			if diff := cmp.Diff(tc.want.HttpHealthCheck, tc.exp); diff != "" {
+				t.Errorf("HttpHealthCheck: -got,+want: %s", diff)
+			}
+		})
+	}
+}
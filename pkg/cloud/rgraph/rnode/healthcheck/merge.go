@@ -0,0 +1,144 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package healthcheck
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/api"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+)
+
+// probeFields maps HealthCheck.Type to the field holding the probe
+// configuration for the type.
+var probeFields = map[string]string{
+	"TCP":   "TcpHealthCheck",
+	"SSL":   "SslHealthCheck",
+	"HTTP":  "HttpHealthCheck",
+	"HTTPS": "HttpsHealthCheck",
+	"HTTP2": "Http2HealthCheck",
+	"GRPC":  "GrpcHealthCheck",
+	"UDP":   "UdpHealthCheck",
+}
+
+// mergeForUpdate returns want with the unspecified fields of the probe
+// sub-struct (e.g. .HttpHealthCheck for Type = "HTTP") filled in from got.
+//
+// HealthCheck is updated with a full Update() and the planner only compares
+// the fields that are set in want, so a want that only sets e.g.
+// CheckIntervalSec would otherwise clear the probe configuration. Fields that
+// are explicitly cleared (NullFields) are not merged. If the Type changes,
+// the old probe configuration is dropped.
+func mergeForUpdate(got, want HealthCheck) (HealthCheck, error) {
+	ver := want.Version()
+	traits := (&typeTrait{}).FieldTraits(ver)
+	id := want.ResourceID()
+	m := NewMutableHealthCheck(id.ProjectID, id.Key)
+
+	var err error
+	switch ver {
+	case meta.VersionGA:
+		g, gErr := got.ToGA()
+		w, wErr := want.ToGA()
+		if gErr != nil || wErr != nil {
+			return nil, fmt.Errorf("mergeForUpdate: ToGA: %v, %v", gErr, wErr)
+		}
+		x := *w
+		mergeProbe(traits, reflect.ValueOf(g), reflect.ValueOf(&x))
+		err = m.Set(&x)
+	case meta.VersionAlpha:
+		g, gErr := got.ToAlpha()
+		w, wErr := want.ToAlpha()
+		if gErr != nil || wErr != nil {
+			return nil, fmt.Errorf("mergeForUpdate: ToAlpha: %v, %v", gErr, wErr)
+		}
+		x := *w
+		mergeProbe(traits, reflect.ValueOf(g), reflect.ValueOf(&x))
+		err = m.SetAlpha(&x)
+	case meta.VersionBeta:
+		g, gErr := got.ToBeta()
+		w, wErr := want.ToBeta()
+		if gErr != nil || wErr != nil {
+			return nil, fmt.Errorf("mergeForUpdate: ToBeta: %v, %v", gErr, wErr)
+		}
+		x := *w
+		mergeProbe(traits, reflect.ValueOf(g), reflect.ValueOf(&x))
+		err = m.SetBeta(&x)
+	default:
+		return nil, fmt.Errorf("mergeForUpdate: unsupported version %q", ver)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("mergeForUpdate: %w", err)
+	}
+	return m.Freeze()
+}
+
+// mergeProbe fills in the probe sub-struct of want from got. got and want
+// are pointers to a HealthCheck struct of the same version. Only the top
+// level of want is modified in place.
+func mergeProbe(traits *api.FieldTraits, got, want reflect.Value) {
+	gotType := got.Elem().FieldByName("Type").String()
+	wantType := want.Elem().FieldByName("Type").String()
+	name, ok := probeFields[wantType]
+	if !ok || gotType != wantType {
+		return
+	}
+	wantNull := want.Elem().FieldByName("NullFields").Interface().([]string)
+	if slices.Contains(wantNull, name) {
+		return
+	}
+	gp := got.Elem().FieldByName(name)
+	wp := want.Elem().FieldByName(name)
+	if !gp.IsValid() || gp.IsNil() {
+		return
+	}
+	// want may share the sub-struct with a frozen resource, copy it before
+	// modifying.
+	nv := reflect.New(gp.Elem().Type())
+	if !wp.IsNil() {
+		nv.Elem().Set(wp.Elem())
+	}
+	wp.Set(nv)
+
+	gs, ws := gp.Elem(), wp.Elem()
+	wsNull := ws.FieldByName("NullFields").Interface().([]string)
+	wsForce := slices.Clone(ws.FieldByName("ForceSendFields").Interface().([]string))
+	for i := 0; i < ws.NumField(); i++ {
+		f := ws.Type().Field(i)
+		switch f.Name {
+		case "NullFields", "ForceSendFields":
+			continue
+		}
+		switch traits.FieldType(api.Path{}.Pointer().Field(name).Pointer().Field(f.Name)) {
+		case api.FieldTypeOutputOnly, api.FieldTypeSystem:
+			continue
+		}
+		if !ws.Field(i).IsZero() || slices.Contains(wsNull, f.Name) || slices.Contains(wsForce, f.Name) {
+			continue
+		}
+		ws.Field(i).Set(gs.Field(i))
+	}
+	// Zero values that were sent in got must also be sent in want.
+	for _, fs := range gs.FieldByName("ForceSendFields").Interface().([]string) {
+		if !slices.Contains(wsForce, fs) && !slices.Contains(wsNull, fs) {
+			wsForce = append(wsForce, fs)
+		}
+	}
+	ws.FieldByName("ForceSendFields").Set(reflect.ValueOf(wsForce))
+}
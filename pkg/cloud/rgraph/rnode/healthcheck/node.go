@@ -77,7 +77,16 @@ func (n *healthCheckNode) Actions(got rnode.Node) ([]exec.Action, error) {
 		return rnode.RecreateActions[compute.HealthCheck, alpha.HealthCheck, beta.HealthCheck](&healthCheckOps{}, got, n, n.resource)
 
 	case rnode.OpUpdate:
-		return rnode.UpdateActions[compute.HealthCheck, alpha.HealthCheck, beta.HealthCheck](&healthCheckOps{}, got, n, n.resource, "")
+		gotNode, ok := got.(*healthCheckNode)
+		if !ok {
+			return nil, fmt.Errorf("HealthCheckNode: invalid type for update: %T", got)
+		}
+		// Keep the probe configuration that was not specified in want.
+		res, err := mergeForUpdate(gotNode.resource, n.resource)
+		if err != nil {
+			return nil, fmt.Errorf("HealthCheckNode: %w", err)
+		}
+		return rnode.UpdateActions[compute.HealthCheck, alpha.HealthCheck, beta.HealthCheck](&healthCheckOps{}, got, n, res, "")
 	}
 
 	return nil, fmt.Errorf("HealthCheckNode: invalid plan op %s", op)
@@ -85,6 +94,6 @@ func (n *healthCheckNode) Actions(got rnode.Node) ([]exec.Action, error) {
 
 func (n *healthCheckNode) Builder() rnode.Builder {
 	b := &builder{}
-	b.Init(n.ID(), n.State(), n.Ownership(), n.resource)
+	b.InitFromNode(n)
 	return b
 }
@@ -31,6 +31,8 @@ type typeTrait struct {
 
 func (*typeTrait) FieldTraits(v meta.Version) *api.FieldTraits {
 	dt := api.NewFieldTraits()
+	dt.Description(api.Path{}.Pointer().Field("Description"))
+
 	// [Output Only]
 	dt.OutputOnly(api.Path{}.Pointer().Field("CreationTimestamp"))
 	dt.OutputOnly(api.Path{}.Pointer().Field("Id"))
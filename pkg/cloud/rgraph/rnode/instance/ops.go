@@ -0,0 +1,53 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package instance
+
+import (
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode"
+	alpha "google.golang.org/api/compute/v0.alpha"
+	beta "google.golang.org/api/compute/v0.beta"
+	"google.golang.org/api/compute/v1"
+)
+
+type ops struct{}
+
+func (*ops) GetFuncs(gcp cloud.Cloud) *rnode.GetFuncs[compute.Instance, alpha.Instance, beta.Instance] {
+	return &rnode.GetFuncs[compute.Instance, alpha.Instance, beta.Instance]{
+		GA: rnode.GetFuncsByScope[compute.Instance]{
+			Zonal: gcp.Instances().Get,
+		},
+		Alpha: rnode.GetFuncsByScope[alpha.Instance]{
+			Zonal: gcp.AlphaInstances().Get,
+		},
+		Beta: rnode.GetFuncsByScope[beta.Instance]{
+			Zonal: gcp.BetaInstances().Get,
+		},
+	}
+}
+
+func (*ops) CreateFuncs(gcp cloud.Cloud) *rnode.CreateFuncs[compute.Instance, alpha.Instance, beta.Instance] {
+	return nil // Instances are read-only.
+}
+
+func (*ops) UpdateFuncs(gcp cloud.Cloud) *rnode.UpdateFuncs[compute.Instance, alpha.Instance, beta.Instance] {
+	return nil // Instances are read-only.
+}
+
+func (*ops) DeleteFuncs(gcp cloud.Cloud) *rnode.DeleteFuncs[compute.Instance, alpha.Instance, beta.Instance] {
+	return nil // Instances are read-only.
+}
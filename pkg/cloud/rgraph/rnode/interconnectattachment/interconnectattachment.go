@@ -0,0 +1,53 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package interconnectattachment is a read-only Node for compute
+// InterconnectAttachments (VLAN attachments). Attachments are not managed by
+// the graph; they are referenced so that the graph can express that the
+// hybrid connectivity a topology depends on must exist.
+package interconnectattachment
+
+import (
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/api"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+
+	alpha "google.golang.org/api/compute/v0.alpha"
+	beta "google.golang.org/api/compute/v0.beta"
+	"google.golang.org/api/compute/v1"
+)
+
+func ID(project string, key *meta.Key) *cloud.ResourceID {
+	return &cloud.ResourceID{
+		Resource:  "interconnectAttachments",
+		APIGroup:  meta.APIGroupCompute,
+		ProjectID: project,
+		Key:       key,
+	}
+}
+
+type MutableInterconnectAttachment = api.MutableResource[compute.InterconnectAttachment, alpha.InterconnectAttachment, beta.InterconnectAttachment]
+
+func NewMutableInterconnectAttachment(project string, key *meta.Key) MutableInterconnectAttachment {
+	id := ID(project, key)
+	return api.NewResource[
+		compute.InterconnectAttachment,
+		alpha.InterconnectAttachment,
+		beta.InterconnectAttachment,
+	](id, &typeTrait{})
+}
+
+type InterconnectAttachment = api.Resource[compute.InterconnectAttachment, alpha.InterconnectAttachment, beta.InterconnectAttachment]
@@ -0,0 +1,57 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package interconnectattachment
+
+import (
+	"fmt"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/exec"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode"
+)
+
+type interconnectAttachmentNode struct {
+	rnode.NodeBase
+	resource InterconnectAttachment
+}
+
+var _ rnode.Node = (*interconnectAttachmentNode)(nil)
+
+func (n *interconnectAttachmentNode) Resource() rnode.UntypedResource { return n.resource }
+
+func (n *interconnectAttachmentNode) Diff(gotNode rnode.Node) (*rnode.PlanDetails, error) {
+	if _, ok := gotNode.(*interconnectAttachmentNode); !ok {
+		return nil, fmt.Errorf("InterconnectAttachmentNode: invalid type to Diff: %T", gotNode)
+	}
+	return &rnode.PlanDetails{
+		Operation: rnode.OpNothing,
+		Why:       "InterconnectAttachments are not managed by the graph",
+	}, nil
+}
+
+func (n *interconnectAttachmentNode) Actions(got rnode.Node) ([]exec.Action, error) {
+	op := n.Plan().Op()
+	if op == rnode.OpNothing {
+		return []exec.Action{exec.NewExistsAction(n.ID())}, nil
+	}
+	return nil, fmt.Errorf("InterconnectAttachmentNode: invalid plan op %s (InterconnectAttachments are read-only)", op)
+}
+
+func (n *interconnectAttachmentNode) Builder() rnode.Builder {
+	b := &builder{}
+	b.InitFromNode(n)
+	return b
+}
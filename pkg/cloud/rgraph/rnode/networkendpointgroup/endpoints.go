@@ -0,0 +1,203 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package networkendpointgroup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/exec"
+	"google.golang.org/api/compute/v1"
+	"k8s.io/klog/v2"
+)
+
+// MaxEndpointsPerCall is the maximum number of endpoints in a single
+// attach or detach call to the API.
+const MaxEndpointsPerCall = 500
+
+// NewReconcileEndpointsAction returns an Action that makes the endpoints of
+// the NEG id equal to want. The current endpoints are listed and the
+// difference is attached and detached in chunks of at most chunkSize
+// endpoints. chunkSize <= 0 uses MaxEndpointsPerCall.
+//
+// The Action waits for the NEG to exist and signals
+// NewEndpointsReconciledEvent(id).
+func NewReconcileEndpointsAction(id *cloud.ResourceID, want []*compute.NetworkEndpoint, chunkSize int) exec.Action {
+	if chunkSize <= 0 || chunkSize > MaxEndpointsPerCall {
+		chunkSize = MaxEndpointsPerCall
+	}
+	return &reconcileEndpointsAction{
+		ActionBase: exec.ActionBase{Want: exec.EventList{exec.NewExistsEvent(id)}},
+		id:         id,
+		want:       want,
+		chunkSize:  chunkSize,
+	}
+}
+
+// NewEndpointsReconciledEvent is signaled when the endpoints of the NEG id
+// have been reconciled.
+func NewEndpointsReconciledEvent(id *cloud.ResourceID) exec.Event {
+	return exec.StringEvent(fmt.Sprintf("EndpointsReconciled(%v)", id))
+}
+
+// ChunkOp is the operation of an EndpointChunkError.
+type ChunkOp string
+
+const (
+	ChunkAttach ChunkOp = "Attach"
+	ChunkDetach ChunkOp = "Detach"
+)
+
+// EndpointChunkError is the failure of a single attach or detach call.
+// Endpoints are the endpoints in the failed chunk.
+type EndpointChunkError struct {
+	Op        ChunkOp
+	Endpoints []*compute.NetworkEndpoint
+	Err       error
+}
+
+func (e *EndpointChunkError) Error() string {
+	return fmt.Sprintf("%s of %d endpoints: %v", e.Op, len(e.Endpoints), e.Err)
+}
+
+func (e *EndpointChunkError) Unwrap() error { return e.Err }
+
+// ReconcileEndpointsError is returned by the reconcile Action when one or
+// more chunks failed. The chunks not listed in Chunks succeeded.
+type ReconcileEndpointsError struct {
+	ID     *cloud.ResourceID
+	Chunks []*EndpointChunkError
+}
+
+func (e *ReconcileEndpointsError) Error() string {
+	return fmt.Sprintf("reconciling endpoints of %v: %d chunk(s) failed: %v", e.ID, len(e.Chunks), errors.Join(e.Unwrap()...))
+}
+
+func (e *ReconcileEndpointsError) Unwrap() []error {
+	var ret []error
+	for _, c := range e.Chunks {
+		ret = append(ret, c)
+	}
+	return ret
+}
+
+type reconcileEndpointsAction struct {
+	exec.ActionBase
+	id        *cloud.ResourceID
+	want      []*compute.NetworkEndpoint
+	chunkSize int
+}
+
+func (a *reconcileEndpointsAction) Run(ctx context.Context, c cloud.Cloud) (exec.EventList, error) {
+	got, err := c.NetworkEndpointGroups().ListNetworkEndpoints(ctx, a.id.Key, &compute.NetworkEndpointGroupsListEndpointsRequest{}, nil)
+	if err != nil {
+		return nil, fmt.Errorf("ReconcileEndpointsAction: list %v: %w", a.id, err)
+	}
+	var gotEndpoints []*compute.NetworkEndpoint
+	for _, ep := range got {
+		if ep.NetworkEndpoint != nil {
+			gotEndpoints = append(gotEndpoints, ep.NetworkEndpoint)
+		}
+	}
+	attach, detach := diffEndpoints(gotEndpoints, a.want)
+	klog.FromContext(ctx).V(4).Info("Reconcile endpoints", "id", a.id, "attach", len(attach), "detach", len(detach))
+
+	rerr := &ReconcileEndpointsError{ID: a.id}
+	for _, chunk := range chunkEndpoints(attach, a.chunkSize) {
+		req := &compute.NetworkEndpointGroupsAttachEndpointsRequest{NetworkEndpoints: chunk}
+		if err := c.NetworkEndpointGroups().AttachNetworkEndpoints(ctx, a.id.Key, req); err != nil {
+			rerr.Chunks = append(rerr.Chunks, &EndpointChunkError{Op: ChunkAttach, Endpoints: chunk, Err: err})
+		}
+	}
+	for _, chunk := range chunkEndpoints(detach, a.chunkSize) {
+		req := &compute.NetworkEndpointGroupsDetachEndpointsRequest{NetworkEndpoints: chunk}
+		if err := c.NetworkEndpointGroups().DetachNetworkEndpoints(ctx, a.id.Key, req); err != nil {
+			rerr.Chunks = append(rerr.Chunks, &EndpointChunkError{Op: ChunkDetach, Endpoints: chunk, Err: err})
+		}
+	}
+	if len(rerr.Chunks) > 0 {
+		return nil, rerr
+	}
+	return exec.EventList{NewEndpointsReconciledEvent(a.id)}, nil
+}
+
+func (a *reconcileEndpointsAction) DryRun() exec.EventList {
+	return exec.EventList{NewEndpointsReconciledEvent(a.id)}
+}
+
+func (a *reconcileEndpointsAction) String() string {
+	return fmt.Sprintf("ReconcileEndpointsAction(%v)", a.id)
+}
+
+func (a *reconcileEndpointsAction) Metadata() *exec.ActionMetadata {
+	return &exec.ActionMetadata{
+		Name:       a.String(),
+		Type:       exec.ActionTypeUpdate,
+		Summary:    fmt.Sprintf("Reconcile %d endpoints of %s", len(a.want), a.id),
+		ResourceID: a.id,
+	}
+}
+
+// endpointKey identifies a NetworkEndpoint. Annotations are not part of the
+// identity.
+func endpointKey(ep *compute.NetworkEndpoint) string {
+	return fmt.Sprintf("%s/%s/%s/%d", ep.Instance, ep.IpAddress, ep.Fqdn, ep.Port)
+}
+
+// diffEndpoints returns the endpoints to attach and detach to go from got
+// to want. The results are sorted for deterministic chunking.
+func diffEndpoints(got, want []*compute.NetworkEndpoint) (attach, detach []*compute.NetworkEndpoint) {
+	gotKeys := map[string]bool{}
+	for _, ep := range got {
+		gotKeys[endpointKey(ep)] = true
+	}
+	wantKeys := map[string]bool{}
+	for _, ep := range want {
+		k := endpointKey(ep)
+		if !gotKeys[k] && !wantKeys[k] {
+			attach = append(attach, ep)
+		}
+		wantKeys[k] = true
+	}
+	for _, ep := range got {
+		if !wantKeys[endpointKey(ep)] {
+			detach = append(detach, ep)
+		}
+	}
+	byKey := func(l []*compute.NetworkEndpoint) {
+		sort.Slice(l, func(i, j int) bool { return endpointKey(l[i]) < endpointKey(l[j]) })
+	}
+	byKey(attach)
+	byKey(detach)
+	return attach, detach
+}
+
+// chunkEndpoints splits eps into slices of at most n endpoints.
+func chunkEndpoints(eps []*compute.NetworkEndpoint, n int) [][]*compute.NetworkEndpoint {
+	var ret [][]*compute.NetworkEndpoint
+	for len(eps) > n {
+		ret = append(ret, eps[:n:n])
+		eps = eps[n:]
+	}
+	if len(eps) > 0 {
+		ret = append(ret, eps)
+	}
+	return ret
+}
@@ -17,9 +17,15 @@ limitations under the License.
 package networkendpointgroup
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"testing"
 
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/filter"
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"google.golang.org/api/compute/v1"
 )
 
 func TestNetworkEndpointGroupSchema(t *testing.T) {
@@ -30,3 +36,70 @@ func TestNetworkEndpointGroupSchema(t *testing.T) {
 		t.Fatalf("CheckSchema() = %v, want nil", err)
 	}
 }
+
+func TestReconcileEndpointsAction(t *testing.T) {
+	ctx := context.Background()
+	key := meta.ZonalKey("neg", "us-central1-b")
+	id := ID("proj", key)
+
+	endpoints := func(start, end int) []*compute.NetworkEndpoint {
+		var ret []*compute.NetworkEndpoint
+		for i := start; i < end; i++ {
+			ret = append(ret, &compute.NetworkEndpoint{IpAddress: fmt.Sprintf("10.0.%d.%d", i/256, i%256), Port: 80})
+		}
+		return ret
+	}
+
+	mockCloud := cloud.NewMockGCE(&cloud.SingleProjectRouter{ID: "proj"})
+	mockNEG := mockCloud.NetworkEndpointGroups().(*cloud.MockNetworkEndpointGroups)
+	// Endpoints [0, 100) exist; want [50, 1250).
+	mockNEG.ListNetworkEndpointsHook = func(context.Context, *meta.Key, *compute.NetworkEndpointGroupsListEndpointsRequest, *filter.F, *cloud.MockNetworkEndpointGroups, ...cloud.Option) ([]*compute.NetworkEndpointWithHealthStatus, error) {
+		var ret []*compute.NetworkEndpointWithHealthStatus
+		for _, ep := range endpoints(0, 100) {
+			ret = append(ret, &compute.NetworkEndpointWithHealthStatus{NetworkEndpoint: ep})
+		}
+		return ret, nil
+	}
+	var attachCalls []int
+	mockNEG.AttachNetworkEndpointsHook = func(_ context.Context, _ *meta.Key, req *compute.NetworkEndpointGroupsAttachEndpointsRequest, _ *cloud.MockNetworkEndpointGroups, _ ...cloud.Option) error {
+		attachCalls = append(attachCalls, len(req.NetworkEndpoints))
+		if len(attachCalls) == 2 {
+			return errors.New("injected")
+		}
+		return nil
+	}
+	var detachCalls []int
+	mockNEG.DetachNetworkEndpointsHook = func(_ context.Context, _ *meta.Key, req *compute.NetworkEndpointGroupsDetachEndpointsRequest, _ *cloud.MockNetworkEndpointGroups, _ ...cloud.Option) error {
+		detachCalls = append(detachCalls, len(req.NetworkEndpoints))
+		return nil
+	}
+
+	a := NewReconcileEndpointsAction(id, endpoints(50, 1250), 0)
+	_, err := a.Run(ctx, mockCloud)
+
+	if fmt.Sprint(attachCalls) != "[500 500 150]" {
+		t.Errorf("attach calls = %v, want [500 500 150]", attachCalls)
+	}
+	if fmt.Sprint(detachCalls) != "[50]" {
+		t.Errorf("detach calls = %v, want [50]", detachCalls)
+	}
+	var rerr *ReconcileEndpointsError
+	if !errors.As(err, &rerr) {
+		t.Fatalf("Run() = %v, want ReconcileEndpointsError", err)
+	}
+	if len(rerr.Chunks) != 1 || rerr.Chunks[0].Op != ChunkAttach || len(rerr.Chunks[0].Endpoints) != 500 {
+		t.Errorf("rerr.Chunks = %v, want 1 failed attach of 500 endpoints", rerr.Chunks)
+	}
+
+	// No changes needed.
+	mockNEG.AttachNetworkEndpointsHook = nil
+	attachCalls, detachCalls = nil, nil
+	a = NewReconcileEndpointsAction(id, endpoints(0, 100), 0)
+	events, err := a.Run(ctx, mockCloud)
+	if err != nil || len(events) != 1 || !events[0].Equal(NewEndpointsReconciledEvent(id)) {
+		t.Errorf("Run() = %v, %v; want EndpointsReconciled event", events, err)
+	}
+	if len(detachCalls) != 0 {
+		t.Errorf("detach calls = %v, want none", detachCalls)
+	}
+}
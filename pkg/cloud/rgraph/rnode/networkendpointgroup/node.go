@@ -91,6 +91,6 @@ func (n *networkEndpointGroupNode) Actions(got rnode.Node) ([]exec.Action, error
 
 func (n *networkEndpointGroupNode) Builder() rnode.Builder {
 	b := &builder{}
-	b.Init(n.ID(), n.State(), n.Ownership(), n.resource)
+	b.InitFromNode(n)
 	return b
 }
@@ -31,8 +31,8 @@ type typeTrait struct {
 
 func (*typeTrait) FieldTraits(meta.Version) *api.FieldTraits {
 	dt := api.NewFieldTraits()
-	// Built-ins
-	dt.OutputOnly(api.Path{}.Pointer().Field("Fingerprint"))
+	dt.Description(api.Path{}.Pointer().Field("Description"))
+
 	// [Output Only]
 	dt.OutputOnly(api.Path{}.Pointer().Field("CreationTimestamp"))
 	dt.OutputOnly(api.Path{}.Pointer().Field("Id"))
@@ -36,6 +36,22 @@ type Node interface {
 	State() NodeState
 	// Ownership of this resource.
 	Ownership() OwnershipStatus
+	// DeletionProtected is true if the resource must not be deleted.
+	DeletionProtected() bool
+	// CascadeDelete is true if the dependents of the resource are deleted
+	// with it. See Builder.SetCascadeDelete().
+	CascadeDelete() bool
+	// ConflictStrategy for the resource. See Builder.ConflictStrategy().
+	ConflictStrategy() ConflictStrategy
+	// Annotations attached to the Node by the Builder. See
+	// Builder.Annotations().
+	Annotations() map[string]string
+	// Preconditions of the Node. See Builder.Preconditions().
+	Preconditions() []Precondition
+	// Postconditions of the Node. See Builder.Postconditions().
+	Postconditions() []Postcondition
+	// OutRefsMode of the Node. See Builder.OutRefsMode().
+	OutRefsMode() OutRefsMode
 	// OutRefs of this resource pointing to other resources.
 	OutRefs() []ResourceRef
 	// InRefs pointing to this resource.
@@ -66,14 +82,29 @@ type NodeBase struct {
 	outRefs   []ResourceRef
 	inRefs    []ResourceRef
 	plan      Plan
+
+	deletionProtected bool
+	cascadeDelete     bool
+	conflictStrategy  ConflictStrategy
+	annotations       map[string]string
+	preconditions     []Precondition
+	postconditions    []Postcondition
+	outRefsMode       OutRefsMode
 }
 
-func (n *NodeBase) ID() *cloud.ResourceID      { return n.id }
-func (n *NodeBase) State() NodeState           { return n.state }
-func (n *NodeBase) Ownership() OwnershipStatus { return n.ownership }
-func (n *NodeBase) OutRefs() []ResourceRef     { return n.outRefs }
-func (n *NodeBase) InRefs() []ResourceRef      { return n.inRefs }
-func (n *NodeBase) Plan() *Plan                { return &n.plan }
+func (n *NodeBase) ID() *cloud.ResourceID              { return n.id }
+func (n *NodeBase) State() NodeState                   { return n.state }
+func (n *NodeBase) Ownership() OwnershipStatus         { return n.ownership }
+func (n *NodeBase) OutRefs() []ResourceRef             { return n.outRefs }
+func (n *NodeBase) InRefs() []ResourceRef              { return n.inRefs }
+func (n *NodeBase) Plan() *Plan                        { return &n.plan }
+func (n *NodeBase) DeletionProtected() bool            { return n.deletionProtected }
+func (n *NodeBase) CascadeDelete() bool                { return n.cascadeDelete }
+func (n *NodeBase) Annotations() map[string]string     { return n.annotations }
+func (n *NodeBase) ConflictStrategy() ConflictStrategy { return n.conflictStrategy }
+func (n *NodeBase) Preconditions() []Precondition      { return n.preconditions }
+func (n *NodeBase) Postconditions() []Postcondition    { return n.postconditions }
+func (n *NodeBase) OutRefsMode() OutRefsMode           { return n.outRefsMode }
 
 // InitFromBuilder is an rgraph library internal method for common
 // initialization from a Builder.
@@ -81,6 +112,13 @@ func (n *NodeBase) InitFromBuilder(b Builder) error {
 	n.id = b.ID()
 	n.state = b.State()
 	n.ownership = b.Ownership()
+	n.deletionProtected = b.DeletionProtected()
+	n.cascadeDelete = b.CascadeDelete()
+	n.conflictStrategy = b.ConflictStrategy()
+	n.annotations = copyAnnotations(b.Annotations())
+	n.preconditions = append([]Precondition(nil), b.Preconditions()...)
+	n.postconditions = append([]Postcondition(nil), b.Postconditions()...)
+	n.outRefsMode = b.OutRefsMode()
 	outRefs, err := b.OutRefs()
 	if err != nil {
 		return err
@@ -32,6 +32,7 @@ func (*fakeBuilder) Resource() UntypedResource                               { r
 func (*fakeBuilder) SetResource(UntypedResource) error                       { return nil }
 func (*fakeBuilder) OutRefs() ([]ResourceRef, error)                         { return nil, nil }
 func (*fakeBuilder) SyncFromCloud(ctx context.Context, cl cloud.Cloud) error { return nil }
+func (b *fakeBuilder) Clone() Builder                                        { return &fakeBuilder{b.CopyBase()} }
 
 func (b *fakeBuilder) Build() (Node, error) {
 	ret := &fakeNode{}
@@ -66,3 +67,18 @@ func TestNodeBase(t *testing.T) {
 
 	t.Log(n)
 }
+
+func TestNodeBaseAnnotations(t *testing.T) {
+	id := &cloud.ResourceID{Resource: "fake", Key: meta.GlobalKey("res1")}
+	a := map[string]string{"service": "ns/name"}
+	nb := fakeBuilder{}
+	nb.Defaults(id)
+	nb.SetAnnotations(a)
+	// Modifying the original map does not affect the Builder.
+	a["service"] = "changed"
+
+	n, _ := nb.Build()
+	if diff := cmp.Diff(n.Annotations(), map[string]string{"service": "ns/name"}); diff != "" {
+		t.Errorf("Annotations() -got,+want: %s", diff)
+	}
+}
@@ -0,0 +1,74 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rnode
+
+import (
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"k8s.io/klog/v2"
+)
+
+// OutRefsMode controls how OutRefs handles reference values that cannot be
+// parsed into a ResourceID, e.g. URLs from a new API scheme.
+type OutRefsMode string
+
+const (
+	// OutRefsStrict fails OutRefs (and the Build of the graph). This is the
+	// default.
+	OutRefsStrict OutRefsMode = ""
+	// OutRefsWarn logs a warning and records the reference with an
+	// UnresolvedID. The target is treated as an external resource.
+	OutRefsWarn OutRefsMode = "Warn"
+)
+
+// UnresolvedResource is the ResourceID.Resource of an UnresolvedID.
+const UnresolvedResource = "unresolved"
+
+// UnresolvedID returns the ResourceID for a reference value that could not
+// be parsed. The Key.Name is the value. project is the project of the
+// resource holding the reference.
+func UnresolvedID(project, value string) *cloud.ResourceID {
+	return &cloud.ResourceID{
+		Resource:  UnresolvedResource,
+		ProjectID: project,
+		Key:       meta.GlobalKey(value),
+	}
+}
+
+// IsUnresolved returns true if id is an UnresolvedID.
+func IsUnresolved(id *cloud.ResourceID) bool {
+	return id != nil && id.Resource == UnresolvedResource
+}
+
+// ParseRefURL parses the reference value url held by the resource of the
+// Builder. If url cannot be parsed and the OutRefsMode is OutRefsWarn, the
+// UnresolvedID for url is returned instead of an error.
+func (b *BuilderBase) ParseRefURL(url string) (*cloud.ResourceID, error) {
+	id, err := cloud.ParseResourceURL(url)
+	if err == nil {
+		return id, nil
+	}
+	if b.outRefsMode != OutRefsWarn {
+		return nil, err
+	}
+	var project string
+	if b.id != nil {
+		project = b.id.ProjectID
+	}
+	klog.Warningf("%v: unresolved reference %q: %v", b.id, url, err)
+	return UnresolvedID(project, url), nil
+}
@@ -64,6 +64,10 @@ type PlanDetails struct {
 	// Diff is an optional description of the diff between the current and
 	// wanted resources.
 	Diff *api.DiffResult
+	// Verdict of the planner that led to the Operation.
+	Verdict Verdict
+	// Inputs to the Verdict.
+	Inputs VerdictInputs
 }
 
 // Op to perform.
@@ -84,6 +88,12 @@ func (p *Plan) Details() *PlanDetails {
 	return &p.details[len(p.details)-1]
 }
 
+// History returns all of the plans that were Set(), oldest first. The last
+// element is the current plan (see Details()).
+func (p *Plan) History() []PlanDetails {
+	return append([]PlanDetails(nil), p.details...)
+}
+
 // Set the plan to the specified action.
 func (p *Plan) Set(a PlanDetails) {
 	p.details = append(p.details, a)
@@ -0,0 +1,125 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rnode
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/api"
+)
+
+// Postcondition is a check on the state of a resource after the Actions for
+// its Node have completed, e.g. that a ForwardingRule has been assigned an
+// IPAddress. Postconditions are verified by an Action that runs after all of
+// the Actions for the Node. Failures are reported as exec.VerificationErrors.
+type Postcondition interface {
+	// Check returns nil if the postcondition holds. n is the Node with the
+	// state of the resource in the Cloud after the Actions were run.
+	Check(n Node) error
+	// String describes the postcondition for error messages.
+	String() string
+}
+
+// PostconditionError is returned when a Postcondition does not hold.
+type PostconditionError struct {
+	// ID of the Node with the Postcondition.
+	ID *cloud.ResourceID
+	// Postcondition that failed.
+	Postcondition Postcondition
+	// Err is the reason the Postcondition failed.
+	Err error
+}
+
+func (e *PostconditionError) Error() string {
+	return fmt.Sprintf("postcondition %v for %v failed: %v", e.Postcondition, e.ID, e.Err)
+}
+
+func (e *PostconditionError) Unwrap() error { return e.Err }
+
+// CheckPostconditions checks the Postconditions of want against got, the
+// Node with the current state of the resource. Returns a *PostconditionError
+// for each Postcondition that does not hold.
+func CheckPostconditions(want, got Node) []error {
+	var errs []error
+	for _, p := range want.Postconditions() {
+		if err := p.Check(got); err != nil {
+			errs = append(errs, &PostconditionError{ID: want.ID(), Postcondition: p, Err: err})
+		}
+	}
+	return errs
+}
+
+// FieldIsSet is a Postcondition that the top-level field of the resource has
+// a non-zero value (e.g. the IPAddress of a ForwardingRule).
+func FieldIsSet(field string) Postcondition {
+	return &fieldIsSet{field: field}
+}
+
+type fieldIsSet struct{ field string }
+
+func (p *fieldIsSet) String() string { return fmt.Sprintf("FieldIsSet(%s)", p.field) }
+
+func (p *fieldIsSet) Check(n Node) error {
+	v, err := nodeField(n, p.field)
+	if err != nil {
+		return err
+	}
+	if v == nil || reflect.ValueOf(v).IsZero() {
+		return fmt.Errorf("%s is not set", p.field)
+	}
+	return nil
+}
+
+// FieldHasValue is a Postcondition that the top-level field of the resource
+// is equal (reflect.DeepEqual) to value.
+func FieldHasValue(field string, value any) Postcondition {
+	return &fieldHasValue{field: field, value: value}
+}
+
+type fieldHasValue struct {
+	field string
+	value any
+}
+
+func (p *fieldHasValue) String() string {
+	return fmt.Sprintf("FieldHasValue(%s, %v)", p.field, p.value)
+}
+
+func (p *fieldHasValue) Check(n Node) error {
+	v, err := nodeField(n, p.field)
+	if err != nil {
+		return err
+	}
+	if !reflect.DeepEqual(v, p.value) {
+		return fmt.Errorf("%s is %v, want %v", p.field, v, p.value)
+	}
+	return nil
+}
+
+// nodeField returns the value of the top-level field of the resource of n.
+func nodeField(n Node, field string) (any, error) {
+	if n == nil || n.State() != NodeExists || n.Resource() == nil {
+		return nil, fmt.Errorf("resource does not exist")
+	}
+	fa, ok := n.Resource().(api.FieldAccessor)
+	if !ok {
+		return nil, fmt.Errorf("cannot access fields of %T", n.Resource())
+	}
+	return fa.Field(field)
+}
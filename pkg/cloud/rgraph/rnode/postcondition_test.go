@@ -0,0 +1,84 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rnode_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/healthcheck"
+	"google.golang.org/api/compute/v1"
+)
+
+func TestPostconditions(t *testing.T) {
+	const proj = "proj"
+
+	hcID := healthcheck.ID(proj, meta.GlobalKey("hc"))
+	m := healthcheck.NewMutableHealthCheck(proj, hcID.Key)
+	m.Access(func(x *compute.HealthCheck) {
+		x.Name = "hc"
+		x.Type = "HTTP"
+	})
+	r, _ := m.Freeze()
+	hb := healthcheck.NewBuilderWithResource(r)
+	hb.SetState(rnode.NodeExists)
+	got, err := hb.Build()
+	if err != nil {
+		t.Fatalf("Build() = %v, want nil", err)
+	}
+	missing := healthcheck.NewBuilder(hcID)
+	missing.SetState(rnode.NodeDoesNotExist)
+	gotMissing, err := missing.Build()
+	if err != nil {
+		t.Fatalf("Build() = %v, want nil", err)
+	}
+
+	for _, tc := range []struct {
+		name    string
+		p       rnode.Postcondition
+		got     rnode.Node
+		wantErr bool
+	}{
+		{name: "field is set", p: rnode.FieldIsSet("Type"), got: got},
+		{name: "field is not set", p: rnode.FieldIsSet("SelfLink"), got: got, wantErr: true},
+		{name: "field has value", p: rnode.FieldHasValue("Type", "HTTP"), got: got},
+		{name: "field has other value", p: rnode.FieldHasValue("Type", "TCP"), got: got, wantErr: true},
+		{name: "invalid field", p: rnode.FieldIsSet("NoSuchField"), got: got, wantErr: true},
+		{name: "resource does not exist", p: rnode.FieldIsSet("Type"), got: gotMissing, wantErr: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			wb := healthcheck.NewBuilder(hcID)
+			wb.SetPostconditions([]rnode.Postcondition{tc.p})
+			want, err := wb.Build()
+			if err != nil {
+				t.Fatalf("Build() = %v, want nil", err)
+			}
+			errs := rnode.CheckPostconditions(want, tc.got)
+			if gotErr := len(errs) > 0; gotErr != tc.wantErr {
+				t.Fatalf("CheckPostconditions() = %v, want error = %t", errs, tc.wantErr)
+			}
+			for _, err := range errs {
+				var pErr *rnode.PostconditionError
+				if !errors.As(err, &pErr) {
+					t.Errorf("CheckPostconditions() = %v, want PostconditionError", err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,185 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rnode
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+)
+
+// GetNodeFunc returns the current state of the Node with the given ID.
+// Returns nil if the state of the Node is not known.
+type GetNodeFunc func(ctx context.Context, id *cloud.ResourceID) (Node, error)
+
+// Precondition is a declarative check on the state of the Cloud that must
+// hold before the Actions for a Node are run. Preconditions are evaluated by
+// the planner and again by a check Action that runs before any of the
+// Actions for the Node in the execution graph.
+type Precondition interface {
+	// Check returns nil if the precondition holds. get returns the
+	// current state of the Nodes the check depends on.
+	Check(ctx context.Context, cl cloud.Cloud, get GetNodeFunc) error
+	// String describes the precondition for error messages.
+	String() string
+}
+
+// PreconditionError is returned when a Precondition does not hold.
+type PreconditionError struct {
+	// ID of the Node with the Precondition.
+	ID *cloud.ResourceID
+	// Precondition that failed.
+	Precondition Precondition
+	// Err is the reason the Precondition failed.
+	Err error
+}
+
+func (e *PreconditionError) Error() string {
+	return fmt.Sprintf("precondition %v for %v failed: %v", e.Precondition, e.ID, e.Err)
+}
+
+func (e *PreconditionError) Unwrap() error { return e.Err }
+
+// CheckPreconditions checks all of the Preconditions of the Node. Returns a
+// *PreconditionError for each Precondition that does not hold.
+func CheckPreconditions(ctx context.Context, cl cloud.Cloud, n Node, get GetNodeFunc) []error {
+	var errs []error
+	for _, p := range n.Preconditions() {
+		if err := p.Check(ctx, cl, get); err != nil {
+			errs = append(errs, &PreconditionError{ID: n.ID(), Precondition: p, Err: err})
+		}
+	}
+	return errs
+}
+
+// ResourceExists is a Precondition that the resource id exists.
+func ResourceExists(id *cloud.ResourceID) Precondition {
+	return &resourceExists{id: id}
+}
+
+type resourceExists struct{ id *cloud.ResourceID }
+
+func (p *resourceExists) String() string { return fmt.Sprintf("Exists(%v)", p.id) }
+
+func (p *resourceExists) Check(ctx context.Context, _ cloud.Cloud, get GetNodeFunc) error {
+	_, err := getExisting(ctx, get, p.id)
+	return err
+}
+
+// FieldEquals is a Precondition that the resource id exists and the top-level
+// field of the resource is equal (reflect.DeepEqual) to value.
+func FieldEquals(id *cloud.ResourceID, field string, value any) Precondition {
+	return &fieldEquals{id: id, field: field, value: value}
+}
+
+type fieldEquals struct {
+	id    *cloud.ResourceID
+	field string
+	value any
+}
+
+func (p *fieldEquals) String() string {
+	return fmt.Sprintf("FieldEquals(%v, %s, %v)", p.id, p.field, p.value)
+}
+
+func (p *fieldEquals) Check(ctx context.Context, _ cloud.Cloud, get GetNodeFunc) error {
+	n, err := getExisting(ctx, get, p.id)
+	if err != nil {
+		return err
+	}
+	v, err := nodeField(n, p.field)
+	if err != nil {
+		return err
+	}
+	if !reflect.DeepEqual(v, p.value) {
+		return fmt.Errorf("%s is %v, want %v", p.field, v, p.value)
+	}
+	return nil
+}
+
+// QuotaAvailable is a Precondition that there is at least amount of the
+// quota metric (e.g. "BACKEND_SERVICES") available in the project. region
+// selects the regional quotas; use "" for the global (project) quotas.
+func QuotaAvailable(project, region, metric string, amount float64) Precondition {
+	return &quotaAvailable{project: project, region: region, metric: metric, amount: amount}
+}
+
+type quotaAvailable struct {
+	project string
+	region  string
+	metric  string
+	amount  float64
+}
+
+func (p *quotaAvailable) String() string {
+	scope := "global"
+	if p.region != "" {
+		scope = p.region
+	}
+	return fmt.Sprintf("QuotaAvailable(%s/%s, %s, %v)", p.project, scope, p.metric, p.amount)
+}
+
+func (p *quotaAvailable) Check(ctx context.Context, cl cloud.Cloud, _ GetNodeFunc) error {
+	var (
+		limit, usage float64
+		found        bool
+	)
+	if p.region == "" {
+		proj, err := cl.Projects().Get(ctx, p.project)
+		if err != nil {
+			return err
+		}
+		for _, q := range proj.Quotas {
+			if q.Metric == p.metric {
+				limit, usage, found = q.Limit, q.Usage, true
+			}
+		}
+	} else {
+		region, err := cl.Regions().Get(ctx, meta.GlobalKey(p.region), cloud.ForceProjectID(p.project))
+		if err != nil {
+			return err
+		}
+		for _, q := range region.Quotas {
+			if q.Metric == p.metric {
+				limit, usage, found = q.Limit, q.Usage, true
+			}
+		}
+	}
+	if !found {
+		return fmt.Errorf("quota metric %q not found", p.metric)
+	}
+	if avail := limit - usage; avail < p.amount {
+		return fmt.Errorf("%s has %v available (limit %v, usage %v), want %v", p.metric, avail, limit, usage, p.amount)
+	}
+	return nil
+}
+
+// getExisting returns the Node id. Returns an error if the Node does not
+// exist.
+func getExisting(ctx context.Context, get GetNodeFunc, id *cloud.ResourceID) (Node, error) {
+	n, err := get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if n == nil || n.State() != NodeExists || n.Resource() == nil {
+		return nil, fmt.Errorf("%v does not exist", id)
+	}
+	return n, nil
+}
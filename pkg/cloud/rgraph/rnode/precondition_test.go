@@ -0,0 +1,123 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rnode_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/healthcheck"
+	"google.golang.org/api/compute/v1"
+)
+
+func TestPreconditions(t *testing.T) {
+	const proj = "proj"
+	ctx := context.Background()
+
+	hcID := healthcheck.ID(proj, meta.GlobalKey("hc"))
+	missingID := healthcheck.ID(proj, meta.GlobalKey("missing"))
+
+	m := healthcheck.NewMutableHealthCheck(proj, hcID.Key)
+	m.Access(func(x *compute.HealthCheck) {
+		x.Name = "hc"
+		x.Type = "HTTP"
+	})
+	r, _ := m.Freeze()
+	hb := healthcheck.NewBuilderWithResource(r)
+	hb.SetState(rnode.NodeExists)
+	hcNode, err := hb.Build()
+	if err != nil {
+		t.Fatalf("Build() = %v, want nil", err)
+	}
+	get := func(_ context.Context, id *cloud.ResourceID) (rnode.Node, error) {
+		if id.Equal(hcID) {
+			return hcNode, nil
+		}
+		return nil, nil
+	}
+
+	mock := cloud.NewMockGCE(&cloud.SingleProjectRouter{ID: proj})
+	mock.MockRegions.Objects[*meta.GlobalKey("us-central1")] = mock.MockRegions.Obj(&compute.Region{
+		Name:   "us-central1",
+		Quotas: []*compute.Quota{{Metric: "FORWARDING_RULES", Limit: 10, Usage: 9}},
+	})
+	mock.MockProjects.Objects[*meta.GlobalKey(proj)] = mock.MockProjects.Obj(&compute.Project{
+		Name:   proj,
+		Quotas: []*compute.Quota{{Metric: "BACKEND_SERVICES", Limit: 10, Usage: 5}},
+	})
+
+	for _, tc := range []struct {
+		name    string
+		p       rnode.Precondition
+		wantErr bool
+	}{
+		{name: "exists", p: rnode.ResourceExists(hcID)},
+		{name: "does not exist", p: rnode.ResourceExists(missingID), wantErr: true},
+		{name: "field equals", p: rnode.FieldEquals(hcID, "Type", "HTTP")},
+		{name: "field not equal", p: rnode.FieldEquals(hcID, "Type", "TCP"), wantErr: true},
+		{name: "invalid field", p: rnode.FieldEquals(hcID, "NoSuchField", "x"), wantErr: true},
+		{name: "field of missing resource", p: rnode.FieldEquals(missingID, "Type", "HTTP"), wantErr: true},
+		{name: "regional quota", p: rnode.QuotaAvailable(proj, "us-central1", "FORWARDING_RULES", 1)},
+		{name: "regional quota exceeded", p: rnode.QuotaAvailable(proj, "us-central1", "FORWARDING_RULES", 2), wantErr: true},
+		{name: "global quota", p: rnode.QuotaAvailable(proj, "", "BACKEND_SERVICES", 5)},
+		{name: "global quota exceeded", p: rnode.QuotaAvailable(proj, "", "BACKEND_SERVICES", 6), wantErr: true},
+		{name: "unknown quota metric", p: rnode.QuotaAvailable(proj, "", "NO_SUCH_METRIC", 1), wantErr: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.p.Check(ctx, mock, get)
+			if gotErr := err != nil; gotErr != tc.wantErr {
+				t.Errorf("Check() = %v; gotErr = %t, want %t", err, gotErr, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestCheckPreconditions(t *testing.T) {
+	id := healthcheck.ID("proj", meta.GlobalKey("hc"))
+	missingID := healthcheck.ID("proj", meta.GlobalKey("missing"))
+
+	b := healthcheck.NewBuilder(id)
+	b.SetPreconditions([]rnode.Precondition{
+		rnode.ResourceExists(id),
+		rnode.ResourceExists(missingID),
+	})
+	b.SetState(rnode.NodeDoesNotExist)
+	n, err := b.Build()
+	if err != nil {
+		t.Fatalf("Build() = %v, want nil", err)
+	}
+	if got := len(n.Preconditions()); got != 2 {
+		t.Fatalf("len(Preconditions()) = %d, want 2", got)
+	}
+	if got := len(n.Builder().Preconditions()); got != 2 {
+		t.Errorf("len(Builder().Preconditions()) = %d, want 2", got)
+	}
+
+	get := func(context.Context, *cloud.ResourceID) (rnode.Node, error) { return nil, nil }
+	errs := rnode.CheckPreconditions(context.Background(), nil, n, get)
+	if len(errs) != 2 {
+		t.Fatalf("CheckPreconditions() = %v, want 2 errors", errs)
+	}
+	var pErr *rnode.PreconditionError
+	if !errors.As(errs[0], &pErr) || !pErr.ID.Equal(id) {
+		t.Errorf("errs[0] = %v, want *PreconditionError for %v", errs[0], id)
+	}
+}
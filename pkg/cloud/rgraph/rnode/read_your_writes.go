@@ -0,0 +1,109 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rnode
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/exec"
+)
+
+// StaleReadError is returned by WaitForWrite when the write was not
+// observed within the bounds.
+type StaleReadError struct {
+	ID *cloud.ResourceID
+	// Fingerprint is the last fingerprint observed.
+	Fingerprint string
+	Attempts    int
+}
+
+func (e *StaleReadError) Error() string {
+	return fmt.Sprintf("write to %v not observed after %d attempts (fingerprint %q)", e.ID, e.Attempts, e.Fingerprint)
+}
+
+// WaitForWrite polls the resource with Get until done returns true for the
+// fingerprint of the resource or the bounds in r are exceeded. Use this
+// after a write to get a consistent read of the post-write state, e.g.
+//
+//	err := WaitForWrite(ctx, c, ops, id, ver, r, func(fp string) bool { return fp != oldFingerprint })
+func WaitForWrite[GA any, Alpha any, Beta any](
+	ctx context.Context,
+	c cloud.Cloud,
+	ops GenericOps[GA, Alpha, Beta],
+	id *cloud.ResourceID,
+	ver meta.Version,
+	r exec.ReadYourWrites,
+	done func(fingerprint string) bool,
+) error {
+	var fp string
+	b := cloud.Backoff{Initial: r.Interval}
+	for i := 1; i <= r.Attempts; i++ {
+		if i > 1 {
+			if err := b.Wait(ctx, i-1); err != nil {
+				return err
+			}
+		}
+		var err error
+		fp, err = getFingerprint(ctx, c, ops, id, ver)
+		if err != nil {
+			return err
+		}
+		if done(fp) {
+			return nil
+		}
+	}
+	return &StaleReadError{ID: id, Fingerprint: fp, Attempts: r.Attempts}
+}
+
+// getFingerprint returns the live fingerprint of the resource.
+func getFingerprint[GA any, Alpha any, Beta any](
+	ctx context.Context,
+	c cloud.Cloud,
+	ops GenericOps[GA, Alpha, Beta],
+	id *cloud.ResourceID,
+	ver meta.Version,
+) (string, error) {
+	gf := ops.GetFuncs(c)
+	opt := cloud.ForceProjectID(id.ProjectID)
+
+	var (
+		raw any
+		err error
+	)
+	switch ver {
+	case meta.VersionGA:
+		raw, err = gf.GA.Do(ctx, id.Key, opt)
+	case meta.VersionAlpha:
+		raw, err = gf.Alpha.Do(ctx, id.Key, opt)
+	case meta.VersionBeta:
+		raw, err = gf.Beta.Do(ctx, id.Key, opt)
+	default:
+		return "", fmt.Errorf("getFingerprint: unsupported version %q", ver)
+	}
+	if err != nil {
+		return "", err
+	}
+	fv, err := fingerprintField(reflect.ValueOf(raw))
+	if err != nil {
+		return "", err
+	}
+	return fv.String(), nil
+}
@@ -0,0 +1,155 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package router
+
+import (
+	"context"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/fake"
+	"google.golang.org/api/compute/v1"
+)
+
+func TestRouterSchema(t *testing.T) {
+	const proj = "proj-1"
+	key := meta.RegionalKey("key-1", "us-central1")
+	x := NewMutableRouter(proj, key)
+	if err := x.CheckSchema(); err != nil {
+		t.Fatalf("CheckSchema() = %v, want nil", err)
+	}
+}
+
+func TestRouterBuilderAlwaysExternal(t *testing.T) {
+	id := ID("proj-1", meta.RegionalKey("router-1", "us-central1"))
+	b := NewBuilder(id)
+	if got := b.Ownership(); got != rnode.OwnershipExternal {
+		t.Errorf("Ownership() = %v, want %v", got, rnode.OwnershipExternal)
+	}
+	b.SetOwnership(rnode.OwnershipManaged)
+	if got := b.Ownership(); got != rnode.OwnershipExternal {
+		t.Errorf("after SetOwnership(Managed), Ownership() = %v, want %v", got, rnode.OwnershipExternal)
+	}
+}
+
+func TestRouterNodeIsReadOnly(t *testing.T) {
+	m := NewMutableRouter("proj-1", meta.RegionalKey("router-1", "us-central1"))
+	m.Access(func(x *compute.Router) { x.Description = "router" })
+	r, err := m.Freeze()
+	if err != nil {
+		t.Fatalf("Freeze() = %v, want nil", err)
+	}
+	b := NewBuilderWithResource(r)
+	b.SetState(rnode.NodeExists)
+	n, err := b.Build()
+	if err != nil {
+		t.Fatalf("Build() = %v, want nil", err)
+	}
+
+	plan, err := n.Diff(n)
+	if err != nil {
+		t.Fatalf("Diff() = %v, want nil", err)
+	}
+	if plan.Operation != rnode.OpNothing {
+		t.Errorf("Diff().Operation = %v, want %v", plan.Operation, rnode.OpNothing)
+	}
+
+	n.Plan().Set(rnode.PlanDetails{Operation: rnode.OpCreate})
+	if _, err := n.Actions(n); err == nil {
+		t.Errorf("Actions() with OpCreate = nil error, want error")
+	}
+}
+
+func TestRouterSyncFromCloud(t *testing.T) {
+	ctx := context.Background()
+	mock := cloud.NewMockGCE(&cloud.SingleProjectRouter{ID: "proj-1"})
+	key := meta.RegionalKey("router-1", "us-central1")
+	if err := mock.Routers().Insert(ctx, key, &compute.Router{Name: "router-1"}); err != nil {
+		t.Fatalf("Insert() = %v, want nil", err)
+	}
+
+	b := NewBuilder(ID("proj-1", key))
+	if err := b.SyncFromCloud(ctx, mock); err != nil {
+		t.Fatalf("SyncFromCloud() = %v, want nil", err)
+	}
+	if b.State() != rnode.NodeExists {
+		t.Errorf("State() = %v, want %v", b.State(), rnode.NodeExists)
+	}
+	if b.Resource() == nil {
+		t.Fatalf("Resource() = nil, want non-nil")
+	}
+
+	cb := b.Clone()
+	n, err := cb.Build()
+	if err != nil {
+		t.Fatalf("Build() = %v, want nil", err)
+	}
+	if n.Resource() == nil {
+		t.Errorf("Node.Resource() = nil, want non-nil")
+	}
+	nb := n.Builder()
+	if nb.ID() != b.ID() || nb.State() != rnode.NodeExists || nb.Ownership() != rnode.OwnershipExternal {
+		t.Errorf("Node.Builder() = %+v, want same ID, state and ownership as %+v", nb, b)
+	}
+
+	missing := NewBuilder(ID("proj-1", meta.RegionalKey("router-2", "us-central1")))
+	if err := missing.SyncFromCloud(ctx, mock); err != nil {
+		t.Fatalf("SyncFromCloud() = %v, want nil", err)
+	}
+	if missing.State() != rnode.NodeDoesNotExist {
+		t.Errorf("State() = %v, want %v", missing.State(), rnode.NodeDoesNotExist)
+	}
+}
+
+func TestRouterInvalidTypes(t *testing.T) {
+	id := ID("proj-1", meta.RegionalKey("router-1", "us-central1"))
+	b := NewBuilder(id)
+	if err := b.SetResource(fake.Fake(nil)); err == nil {
+		t.Errorf("SetResource(fake) = nil, want error")
+	}
+
+	b.SetState(rnode.NodeExists)
+	if _, err := b.Build(); err == nil {
+		t.Errorf("Build() with NodeExists and nil resource = nil, want error")
+	}
+
+	b.SetState(rnode.NodeDoesNotExist)
+	n, err := b.Build()
+	if err != nil {
+		t.Fatalf("Build() = %v, want nil", err)
+	}
+	fn, err := fake.NewBuilder(fake.ID("proj-1", meta.GlobalKey("f"))).Build()
+	if err != nil {
+		t.Fatalf("fake Build() = %v, want nil", err)
+	}
+	if _, err := n.Diff(fn); err == nil {
+		t.Errorf("Diff(fake) = nil, want error")
+	}
+}
+
+func TestRouterOps(t *testing.T) {
+	mock := cloud.NewMockGCE(&cloud.SingleProjectRouter{ID: "proj-1"})
+	o := &ops{}
+	if f := o.GetFuncs(mock); f.GA.Regional == nil || f.Alpha.Regional == nil || f.Beta.Regional == nil {
+		t.Errorf("GetFuncs() = %+v, want regional GA, Alpha and Beta", f)
+	}
+	if o.CreateFuncs(mock) != nil || o.UpdateFuncs(mock) != nil || o.DeleteFuncs(mock) != nil {
+		t.Errorf("Create/Update/DeleteFuncs() != nil, want nil (Routers are read-only)")
+	}
+}
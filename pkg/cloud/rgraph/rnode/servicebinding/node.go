@@ -0,0 +1,87 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package servicebinding
+
+import (
+	"fmt"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/api"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/exec"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode"
+	"google.golang.org/api/networkservices/v1"
+	beta "google.golang.org/api/networkservices/v1beta1"
+)
+
+type serviceBindingNode struct {
+	rnode.NodeBase
+	resource ServiceBinding
+}
+
+var _ rnode.Node = (*serviceBindingNode)(nil)
+
+func (n *serviceBindingNode) Resource() rnode.UntypedResource { return n.resource }
+
+func (n *serviceBindingNode) Diff(gotNode rnode.Node) (*rnode.PlanDetails, error) {
+	got, ok := gotNode.(*serviceBindingNode)
+	if !ok {
+		return nil, fmt.Errorf("ServiceBindingNode: invalid type to Diff: %T", gotNode)
+	}
+
+	diff, err := got.resource.Diff(n.resource)
+	if err != nil {
+		return nil, fmt.Errorf("ServiceBindingNode: Diff %w", err)
+	}
+
+	if diff.HasDiff() {
+		return &rnode.PlanDetails{
+			Operation: rnode.OpRecreate,
+			Why:       "ServiceBinding cannot be updated in place",
+			Diff:      diff,
+		}, nil
+	}
+
+	return &rnode.PlanDetails{
+		Operation: rnode.OpNothing,
+		Why:       "No diff between got and want",
+	}, nil
+}
+
+func (n *serviceBindingNode) Actions(got rnode.Node) ([]exec.Action, error) {
+	op := n.Plan().Op()
+
+	switch op {
+	case rnode.OpCreate:
+		return rnode.CreateActions[networkservices.ServiceBinding, api.PlaceholderType, beta.ServiceBinding](&ops{}, n, n.resource)
+
+	case rnode.OpDelete:
+		return rnode.DeleteActions[networkservices.ServiceBinding, api.PlaceholderType, beta.ServiceBinding](&ops{}, got, n)
+
+	case rnode.OpNothing:
+		return []exec.Action{exec.NewExistsAction(n.ID())}, nil
+
+	case rnode.OpRecreate:
+		return rnode.RecreateActions[networkservices.ServiceBinding, api.PlaceholderType, beta.ServiceBinding](&ops{}, got, n, n.resource)
+	}
+
+	return nil, fmt.Errorf("ServiceBindingNode: invalid plan op %s", op)
+}
+
+func (n *serviceBindingNode) Builder() rnode.Builder {
+	b := &builder{}
+	b.InitFromNode(n)
+	return b
+}
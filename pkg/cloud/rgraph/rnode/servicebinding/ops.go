@@ -0,0 +1,64 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package servicebinding
+
+import (
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/api"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode"
+	"google.golang.org/api/networkservices/v1"
+	beta "google.golang.org/api/networkservices/v1beta1"
+)
+
+type ops struct{}
+
+func (*ops) GetFuncs(gcp cloud.Cloud) *rnode.GetFuncs[networkservices.ServiceBinding, api.PlaceholderType, beta.ServiceBinding] {
+	return &rnode.GetFuncs[networkservices.ServiceBinding, api.PlaceholderType, beta.ServiceBinding]{
+		GA: rnode.GetFuncsByScope[networkservices.ServiceBinding]{
+			Global: gcp.ServiceBindings().Get,
+		},
+		Beta: rnode.GetFuncsByScope[beta.ServiceBinding]{
+			Global: gcp.BetaServiceBindings().Get,
+		},
+	}
+}
+
+func (*ops) CreateFuncs(gcp cloud.Cloud) *rnode.CreateFuncs[networkservices.ServiceBinding, api.PlaceholderType, beta.ServiceBinding] {
+	return &rnode.CreateFuncs[networkservices.ServiceBinding, api.PlaceholderType, beta.ServiceBinding]{
+		GA: rnode.CreateFuncsByScope[networkservices.ServiceBinding]{
+			Global: gcp.ServiceBindings().Insert,
+		},
+		Beta: rnode.CreateFuncsByScope[beta.ServiceBinding]{
+			Global: gcp.BetaServiceBindings().Insert,
+		},
+	}
+}
+
+func (*ops) UpdateFuncs(gcp cloud.Cloud) *rnode.UpdateFuncs[networkservices.ServiceBinding, api.PlaceholderType, beta.ServiceBinding] {
+	return nil // ServiceBinding does not support Patch.
+}
+
+func (*ops) DeleteFuncs(gcp cloud.Cloud) *rnode.DeleteFuncs[networkservices.ServiceBinding, api.PlaceholderType, beta.ServiceBinding] {
+	return &rnode.DeleteFuncs[networkservices.ServiceBinding, api.PlaceholderType, beta.ServiceBinding]{
+		GA: rnode.DeleteFuncsByScope[networkservices.ServiceBinding]{
+			Global: gcp.ServiceBindings().Delete,
+		},
+		Beta: rnode.DeleteFuncsByScope[beta.ServiceBinding]{
+			Global: gcp.BetaServiceBindings().Delete,
+		},
+	}
+}
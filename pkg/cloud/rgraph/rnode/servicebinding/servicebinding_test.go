@@ -0,0 +1,212 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package servicebinding
+
+import (
+	"context"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/fake"
+	"google.golang.org/api/networkservices/v1"
+)
+
+const (
+	projectID = "proj-1"
+	service1  = "projects/proj-1/locations/us-central1/namespaces/ns/services/service-1"
+	service2  = "projects/proj-1/locations/us-central1/namespaces/ns/services/service-2"
+)
+
+func TestServiceBindingSchema(t *testing.T) {
+	x := NewMutableServiceBinding(projectID, meta.GlobalKey("key-1"))
+	if err := x.CheckSchema(); err != nil {
+		t.Fatalf("CheckSchema() = %v, want nil", err)
+	}
+}
+
+func TestServiceBindingDiff(t *testing.T) {
+	id := ID(projectID, meta.GlobalKey("sb"))
+	n1 := newNode(t, id, service1)
+	n2 := newNode(t, id, service1)
+	n3 := newNode(t, id, service2)
+
+	p, err := n2.Diff(n1)
+	if err != nil || p.Operation != rnode.OpNothing {
+		t.Errorf("Diff(same) = %+v, %v; want %s, nil", p, err, rnode.OpNothing)
+	}
+	// ServiceBindings do not have a Patch method.
+	p, err = n3.Diff(n1)
+	if err != nil || p.Operation != rnode.OpRecreate {
+		t.Errorf("Diff(changed) = %+v, %v; want %s, nil", p, err, rnode.OpRecreate)
+	}
+	n3.Plan().Set(rnode.PlanDetails{Operation: rnode.OpUpdate})
+	if _, err := n3.Actions(n1); err == nil {
+		t.Errorf("Actions(%s) = nil, want error", rnode.OpUpdate)
+	}
+}
+
+func TestServiceBindingOutRefs(t *testing.T) {
+	id := ID(projectID, meta.GlobalKey("sb"))
+	b := NewBuilder(id)
+	b.SetResource(newNode(t, id, service1).Resource())
+	refs, err := b.OutRefs()
+	if err != nil {
+		t.Fatalf("OutRefs() = %v, want nil", err)
+	}
+	want := cloud.ServiceDirectoryServiceID(projectID, "us-central1", "ns", "service-1")
+	if len(refs) != 1 || !refs[0].To.Equal(want) || !refs[0].From.Equal(id) {
+		t.Errorf("OutRefs() = %v, want [%v -> %v]", refs, id, want)
+	}
+
+	m := NewMutableServiceBinding(projectID, id.Key)
+	m.Access(func(x *networkservices.ServiceBinding) { x.Service = "invalid" })
+	r, _ := m.Freeze()
+	if _, err := NewBuilderWithResource(r).OutRefs(); err == nil {
+		t.Error("OutRefs() = nil, want error for an invalid Service")
+	}
+}
+
+func TestServiceBindingCreateAndSync(t *testing.T) {
+	ctx := context.Background()
+	mock := cloud.NewMockGCE(&cloud.SingleProjectRouter{ID: projectID})
+	id := ID(projectID, meta.GlobalKey("sb"))
+
+	b := NewBuilder(id)
+	if err := b.SyncFromCloud(ctx, mock); err != nil || b.State() != rnode.NodeDoesNotExist {
+		t.Fatalf("SyncFromCloud() = %v, State() = %s; want nil, %s", err, b.State(), rnode.NodeDoesNotExist)
+	}
+
+	n := newNode(t, id, service1)
+	n.Plan().Set(rnode.PlanDetails{Operation: rnode.OpCreate})
+	actions, err := n.Actions(nil)
+	if err != nil || len(actions) != 1 {
+		t.Fatalf("Actions() = %v, %v; want 1 action", actions, err)
+	}
+	if _, err := actions[0].Run(ctx, mock); err != nil {
+		t.Fatalf("Run() = %v", err)
+	}
+
+	if err := b.SyncFromCloud(ctx, mock); err != nil || b.State() != rnode.NodeExists {
+		t.Fatalf("SyncFromCloud() = %v, State() = %s; want nil, %s", err, b.State(), rnode.NodeExists)
+	}
+	got, err := b.Resource().(ServiceBinding).ToGA()
+	if err != nil {
+		t.Fatalf("ToGA() = %v", err)
+	}
+	if got.Service != service1 {
+		t.Errorf("Service = %q, want %q", got.Service, service1)
+	}
+
+	gotNode, err := b.Clone().Build()
+	if err != nil {
+		t.Fatalf("Build() = %v", err)
+	}
+	n = newNode(t, id, service2)
+	n.Plan().Set(rnode.PlanDetails{Operation: rnode.OpRecreate})
+	actions, err = n.Actions(gotNode)
+	if err != nil {
+		t.Fatalf("Actions(%s) = %v", rnode.OpRecreate, err)
+	}
+	for _, a := range actions {
+		if _, err := a.Run(ctx, mock); err != nil {
+			t.Fatalf("Run() = %v", err)
+		}
+	}
+	if err := b.SyncFromCloud(ctx, mock); err != nil {
+		t.Fatalf("SyncFromCloud() = %v", err)
+	}
+	if got, _ := b.Resource().(ServiceBinding).ToGA(); got.Service != service2 {
+		t.Errorf("Service = %q, want %q", got.Service, service2)
+	}
+
+	del := NewBuilder(id)
+	del.SetOwnership(rnode.OwnershipManaged)
+	del.SetState(rnode.NodeDoesNotExist)
+	delNode, err := del.Build()
+	if err != nil {
+		t.Fatalf("Build() = %v", err)
+	}
+	delNode.Plan().Set(rnode.PlanDetails{Operation: rnode.OpDelete})
+	if actions, err = delNode.Actions(n); err != nil {
+		t.Fatalf("Actions(%s) = %v", rnode.OpDelete, err)
+	}
+	for _, a := range actions {
+		if _, err := a.Run(ctx, mock); err != nil {
+			t.Fatalf("Run() = %v", err)
+		}
+	}
+	if err := b.SyncFromCloud(ctx, mock); err != nil || b.State() != rnode.NodeDoesNotExist {
+		t.Errorf("SyncFromCloud() = %v, State() = %s; want nil, %s", err, b.State(), rnode.NodeDoesNotExist)
+	}
+}
+
+func TestServiceBindingInvalidTypes(t *testing.T) {
+	id := ID(projectID, meta.GlobalKey("sb"))
+	b := NewBuilder(id)
+	if err := b.SetResource(fake.Fake(nil)); err == nil {
+		t.Error("SetResource(fake) = nil, want error")
+	}
+	if refs, err := b.OutRefs(); err != nil || len(refs) != 0 {
+		t.Errorf("OutRefs() with no resource = %v, %v; want none, nil", refs, err)
+	}
+	b.SetState(rnode.NodeExists)
+	if _, err := b.Build(); err == nil {
+		t.Error("Build() with a nil resource = nil, want error")
+	}
+
+	n := newNode(t, id, service1)
+	if _, err := NewBuilderWithResource(n.Resource().(ServiceBinding)).Clone().Build(); err != nil {
+		t.Errorf("Clone().Build() = %v", err)
+	}
+	fn, err := fake.NewBuilder(fake.ID(projectID, meta.GlobalKey("f"))).Build()
+	if err != nil {
+		t.Fatalf("fake Build() = %v", err)
+	}
+	if _, err := n.Diff(fn); err == nil {
+		t.Error("Diff(fake) = nil, want error")
+	}
+	n.Plan().Set(rnode.PlanDetails{Operation: rnode.OpNothing})
+	if actions, err := n.Actions(n); err != nil || len(actions) != 1 {
+		t.Errorf("Actions(%s) = %v, %v; want 1 action", rnode.OpNothing, actions, err)
+	}
+}
+
+func newNode(t *testing.T, id *cloud.ResourceID, service string) rnode.Node {
+	t.Helper()
+
+	m := NewMutableServiceBinding(projectID, id.Key)
+	if err := m.Access(func(x *networkservices.ServiceBinding) {
+		x.Name = id.Key.Name
+		x.Service = service
+	}); err != nil {
+		t.Fatalf("Access() = %v", err)
+	}
+	r, err := m.Freeze()
+	if err != nil {
+		t.Fatalf("Freeze() = %v", err)
+	}
+	b := NewBuilderWithResource(r)
+	b.SetState(rnode.NodeExists)
+	b.SetOwnership(rnode.OwnershipManaged)
+	n, err := b.Build()
+	if err != nil {
+		t.Fatalf("Build() = %v", err)
+	}
+	return n
+}
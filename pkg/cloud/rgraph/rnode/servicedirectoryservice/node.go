@@ -0,0 +1,57 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package servicedirectoryservice
+
+import (
+	"fmt"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/exec"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode"
+)
+
+type serviceNode struct {
+	rnode.NodeBase
+	resource Service
+}
+
+var _ rnode.Node = (*serviceNode)(nil)
+
+func (n *serviceNode) Resource() rnode.UntypedResource { return n.resource }
+
+func (n *serviceNode) Diff(gotNode rnode.Node) (*rnode.PlanDetails, error) {
+	if _, ok := gotNode.(*serviceNode); !ok {
+		return nil, fmt.Errorf("ServiceDirectoryServiceNode: invalid type to Diff: %T", gotNode)
+	}
+	return &rnode.PlanDetails{
+		Operation: rnode.OpNothing,
+		Why:       "Service Directory services are not managed by the graph",
+	}, nil
+}
+
+func (n *serviceNode) Actions(got rnode.Node) ([]exec.Action, error) {
+	op := n.Plan().Op()
+	if op == rnode.OpNothing {
+		return []exec.Action{exec.NewExistsAction(n.ID())}, nil
+	}
+	return nil, fmt.Errorf("ServiceDirectoryServiceNode: invalid plan op %s (Services are read-only)", op)
+}
+
+func (n *serviceNode) Builder() rnode.Builder {
+	b := &builder{}
+	b.InitFromNode(n)
+	return b
+}
@@ -0,0 +1,46 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package servicedirectoryservice
+
+import (
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/api"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode"
+	"google.golang.org/api/servicedirectory/v1"
+)
+
+type ops struct{}
+
+func (*ops) GetFuncs(gcp cloud.Cloud) *rnode.GetFuncs[servicedirectory.Service, api.PlaceholderType, api.PlaceholderType] {
+	return &rnode.GetFuncs[servicedirectory.Service, api.PlaceholderType, api.PlaceholderType]{
+		GA: rnode.GetFuncsByScope[servicedirectory.Service]{
+			Regional: gcp.ServiceDirectoryServices().Get,
+		},
+	}
+}
+
+func (*ops) CreateFuncs(gcp cloud.Cloud) *rnode.CreateFuncs[servicedirectory.Service, api.PlaceholderType, api.PlaceholderType] {
+	return nil // Services are read-only.
+}
+
+func (*ops) UpdateFuncs(gcp cloud.Cloud) *rnode.UpdateFuncs[servicedirectory.Service, api.PlaceholderType, api.PlaceholderType] {
+	return nil // Services are read-only.
+}
+
+func (*ops) DeleteFuncs(gcp cloud.Cloud) *rnode.DeleteFuncs[servicedirectory.Service, api.PlaceholderType, api.PlaceholderType] {
+	return nil // Services are read-only.
+}
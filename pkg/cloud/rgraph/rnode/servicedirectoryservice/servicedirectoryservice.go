@@ -0,0 +1,51 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package servicedirectoryservice is a read-only Node for Service Directory
+// services. The services are not managed by the graph; they are referenced by
+// networkservices ServiceBindings so that the graph can validate that they
+// exist at plan time. See cloud.ServiceDirectoryServiceKey for the Key.
+package servicedirectoryservice
+
+import (
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/api"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+
+	"google.golang.org/api/servicedirectory/v1"
+)
+
+func ID(project string, key *meta.Key) *cloud.ResourceID {
+	return &cloud.ResourceID{
+		Resource:  "services",
+		APIGroup:  meta.APIGroupServiceDirectory,
+		ProjectID: project,
+		Key:       key,
+	}
+}
+
+type MutableService = api.MutableResource[servicedirectory.Service, api.PlaceholderType, api.PlaceholderType]
+
+func NewMutableService(project string, key *meta.Key) MutableService {
+	id := ID(project, key)
+	return api.NewResource[
+		servicedirectory.Service,
+		api.PlaceholderType,
+		api.PlaceholderType,
+	](id, &typeTrait{})
+}
+
+type Service = api.Resource[servicedirectory.Service, api.PlaceholderType, api.PlaceholderType]
@@ -0,0 +1,144 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package servicedirectoryservice
+
+import (
+	"context"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/fake"
+	"google.golang.org/api/servicedirectory/v1"
+)
+
+const projectID = "proj-1"
+
+func TestServiceSchema(t *testing.T) {
+	x := NewMutableService(projectID, cloud.ServiceDirectoryServiceKey("us-central1", "ns", "svc"))
+	if err := x.CheckSchema(); err != nil {
+		t.Fatalf("CheckSchema() = %v, want nil", err)
+	}
+}
+
+func TestServiceBuilderAlwaysExternal(t *testing.T) {
+	b := NewBuilder(ID(projectID, cloud.ServiceDirectoryServiceKey("us-central1", "ns", "svc")))
+	b.SetOwnership(rnode.OwnershipManaged)
+	if got := b.Ownership(); got != rnode.OwnershipExternal {
+		t.Errorf("after SetOwnership(Managed), Ownership() = %v, want %v", got, rnode.OwnershipExternal)
+	}
+	if refs, err := b.OutRefs(); err != nil || len(refs) != 0 {
+		t.Errorf("OutRefs() = %v, %v; want none, nil", refs, err)
+	}
+}
+
+func TestServiceSyncFromCloud(t *testing.T) {
+	ctx := context.Background()
+	mock := cloud.NewMockGCE(&cloud.SingleProjectRouter{ID: projectID})
+	key := cloud.ServiceDirectoryServiceKey("us-central1", "ns", "svc")
+	mock.MockServiceDirectoryServices.Objects[*key] = &servicedirectory.Service{
+		Name: "projects/proj-1/locations/us-central1/namespaces/ns/services/svc",
+	}
+
+	b := NewBuilder(ID(projectID, key))
+	if err := b.SyncFromCloud(ctx, mock); err != nil {
+		t.Fatalf("SyncFromCloud() = %v, want nil", err)
+	}
+	if b.State() != rnode.NodeExists {
+		t.Errorf("State() = %v, want %v", b.State(), rnode.NodeExists)
+	}
+	if b.Resource() == nil {
+		t.Fatalf("Resource() = nil, want non-nil")
+	}
+
+	n, err := b.Clone().Build()
+	if err != nil {
+		t.Fatalf("Build() = %v, want nil", err)
+	}
+	if n.Resource() == nil {
+		t.Errorf("Node.Resource() = nil, want non-nil")
+	}
+	nb := n.Builder()
+	if !nb.ID().Equal(b.ID()) || nb.State() != rnode.NodeExists || nb.Ownership() != rnode.OwnershipExternal {
+		t.Errorf("Node.Builder() = %v, %s, %s; want %v, %s, %s", nb.ID(), nb.State(), nb.Ownership(), b.ID(), rnode.NodeExists, rnode.OwnershipExternal)
+	}
+
+	plan, err := n.Diff(n)
+	if err != nil || plan.Operation != rnode.OpNothing {
+		t.Errorf("Diff() = %+v, %v; want %s, nil", plan, err, rnode.OpNothing)
+	}
+	n.Plan().Set(*plan)
+	if actions, err := n.Actions(n); err != nil || len(actions) != 1 {
+		t.Errorf("Actions(%s) = %v, %v; want 1 action", rnode.OpNothing, actions, err)
+	}
+	n.Plan().Set(rnode.PlanDetails{Operation: rnode.OpCreate})
+	if _, err := n.Actions(n); err == nil {
+		t.Errorf("Actions(%s) = nil, want error", rnode.OpCreate)
+	}
+
+	missing := NewBuilder(ID(projectID, cloud.ServiceDirectoryServiceKey("us-central1", "ns", "other")))
+	if err := missing.SyncFromCloud(ctx, mock); err != nil {
+		t.Fatalf("SyncFromCloud() = %v, want nil", err)
+	}
+	if missing.State() != rnode.NodeDoesNotExist {
+		t.Errorf("State() = %v, want %v", missing.State(), rnode.NodeDoesNotExist)
+	}
+}
+
+func TestServiceInvalidTypes(t *testing.T) {
+	id := ID(projectID, cloud.ServiceDirectoryServiceKey("us-central1", "ns", "svc"))
+	b := NewBuilder(id)
+	if err := b.SetResource(fake.Fake(nil)); err == nil {
+		t.Errorf("SetResource(fake) = nil, want error")
+	}
+
+	b.SetState(rnode.NodeExists)
+	if _, err := b.Build(); err == nil {
+		t.Errorf("Build() with NodeExists and nil resource = nil, want error")
+	}
+
+	m := NewMutableService(projectID, id.Key)
+	r, err := m.Freeze()
+	if err != nil {
+		t.Fatalf("Freeze() = %v, want nil", err)
+	}
+	b = NewBuilderWithResource(r)
+	b.SetState(rnode.NodeExists)
+	n, err := b.Build()
+	if err != nil {
+		t.Fatalf("Build() = %v, want nil", err)
+	}
+	fn, err := fake.NewBuilder(fake.ID(projectID, meta.GlobalKey("f"))).Build()
+	if err != nil {
+		t.Fatalf("fake Build() = %v, want nil", err)
+	}
+	if _, err := n.Diff(fn); err == nil {
+		t.Errorf("Diff(fake) = nil, want error")
+	}
+}
+
+func TestServiceOps(t *testing.T) {
+	mock := cloud.NewMockGCE(&cloud.SingleProjectRouter{ID: projectID})
+	o := &ops{}
+	if f := o.GetFuncs(mock); f.GA.Regional == nil {
+		t.Errorf("GetFuncs() = %+v, want regional GA", f)
+	}
+	if o.CreateFuncs(mock) != nil || o.UpdateFuncs(mock) != nil || o.DeleteFuncs(mock) != nil {
+		t.Errorf("Create/Update/DeleteFuncs() != nil, want nil (Services are read-only)")
+	}
+}
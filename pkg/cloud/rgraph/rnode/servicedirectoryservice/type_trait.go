@@ -0,0 +1,44 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package servicedirectoryservice
+
+import (
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/api"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"google.golang.org/api/servicedirectory/v1"
+)
+
+// https://cloud.google.com/service-directory/docs/reference/rest/v1/projects.locations.namespaces.services
+type typeTrait struct {
+	api.BaseTypeTrait[servicedirectory.Service, api.PlaceholderType, api.PlaceholderType]
+}
+
+func (*typeTrait) FieldTraits(meta.Version) *api.FieldTraits {
+	dt := api.NewFieldTraits()
+	// [Output Only]
+	dt.OutputOnly(api.Path{}.Pointer().Field("Uid"))
+	dt.OutputOnly(api.Path{}.Pointer().Field("Endpoints").AnySliceIndex().Pointer().Field("Uid"))
+
+	dt.AllowZeroValue(api.Path{}.Pointer().Field("Annotations"))
+	dt.AllowZeroValue(api.Path{}.Pointer().Field("Endpoints"))
+	dt.AllowZeroValue(api.Path{}.Pointer().Field("Endpoints").AnySliceIndex().Pointer().Field("Address"))
+	dt.AllowZeroValue(api.Path{}.Pointer().Field("Endpoints").AnySliceIndex().Pointer().Field("Annotations"))
+	dt.AllowZeroValue(api.Path{}.Pointer().Field("Endpoints").AnySliceIndex().Pointer().Field("Network"))
+	dt.AllowZeroValue(api.Path{}.Pointer().Field("Endpoints").AnySliceIndex().Pointer().Field("Port"))
+
+	return dt
+}
@@ -0,0 +1,116 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rnode
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/api"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"k8s.io/klog/v2"
+)
+
+// StrictFieldsMode controls how SyncFromCloud handles fields returned by the
+// API that are not classified by the FieldTraits of the resource (see
+// api.FieldTraits.Unclassified). Such fields are usually new API fields that
+// may not round-trip correctly through a diff and update.
+type StrictFieldsMode string
+
+const (
+	// StrictFieldsOff does not check the fields. This is the default.
+	StrictFieldsOff StrictFieldsMode = ""
+	// StrictFieldsWarn logs the unclassified fields.
+	StrictFieldsWarn StrictFieldsMode = "Warn"
+	// StrictFieldsError fails SyncFromCloud with an *UnclassifiedFieldsError.
+	StrictFieldsError StrictFieldsMode = "Error"
+)
+
+type contextKey string
+
+var strictFieldsContextKey = contextKey("strict fields")
+
+// WithStrictFields returns a context that sets the StrictFieldsMode for
+// SyncFromCloud calls made with it.
+func WithStrictFields(ctx context.Context, mode StrictFieldsMode) context.Context {
+	return context.WithValue(ctx, strictFieldsContextKey, mode)
+}
+
+// StrictFields returns the StrictFieldsMode set in ctx.
+func StrictFields(ctx context.Context) StrictFieldsMode {
+	v, _ := ctx.Value(strictFieldsContextKey).(StrictFieldsMode)
+	return v
+}
+
+// UnclassifiedFieldsError is returned by SyncFromCloud with StrictFieldsError
+// when the resource has fields set that are not classified by its traits.
+type UnclassifiedFieldsError struct {
+	// ID of the resource.
+	ID *cloud.ResourceID
+	// Version of the resource that was fetched.
+	Version meta.Version
+	// Paths of the unclassified fields.
+	Paths []api.Path
+}
+
+func (e *UnclassifiedFieldsError) Error() string {
+	return fmt.Sprintf("%v (%s) has fields without traits: %v", e.ID, e.Version, e.Paths)
+}
+
+// checkStrictFields checks r for unclassified fields according to the
+// StrictFieldsMode in ctx.
+func checkStrictFields[GA any, Alpha any, Beta any](
+	ctx context.Context,
+	typeTrait api.TypeTrait[GA, Alpha, Beta],
+	id *cloud.ResourceID,
+	r api.Resource[GA, Alpha, Beta],
+) error {
+	mode := StrictFields(ctx)
+	if mode == StrictFieldsOff {
+		return nil
+	}
+	var (
+		obj any
+		err error
+	)
+	switch r.Version() {
+	case meta.VersionGA:
+		obj, err = r.ToGA()
+	case meta.VersionAlpha:
+		obj, err = r.ToAlpha()
+	case meta.VersionBeta:
+		obj, err = r.ToBeta()
+	default:
+		return fmt.Errorf("checkStrictFields: invalid version %q", r.Version())
+	}
+	if err != nil {
+		return fmt.Errorf("checkStrictFields: %w", err)
+	}
+	paths := typeTrait.FieldTraits(r.Version()).UnclassifiedSet(obj)
+	if len(paths) == 0 {
+		return nil
+	}
+	switch mode {
+	case StrictFieldsWarn:
+		klog.FromContext(ctx).Info("Resource has fields without traits", "id", id, "version", r.Version(), "paths", paths)
+		return nil
+	case StrictFieldsError:
+		return &UnclassifiedFieldsError{ID: id, Version: r.Version(), Paths: paths}
+	}
+	return fmt.Errorf("checkStrictFields: invalid StrictFieldsMode %q", mode)
+}
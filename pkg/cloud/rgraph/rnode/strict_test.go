@@ -0,0 +1,76 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rnode_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/healthcheck"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/targethttpproxy"
+	"google.golang.org/api/compute/v1"
+)
+
+func TestStrictFields(t *testing.T) {
+	ctx := context.Background()
+	mock := cloud.NewMockGCE(&cloud.SingleProjectRouter{ID: "proj"})
+	key := meta.GlobalKey("x")
+	// HealthCheck does not classify Name.
+	mock.HealthChecks().Insert(ctx, key, &compute.HealthCheck{Description: "d"})
+	// All TargetHttpProxy fields are classified.
+	mock.TargetHttpProxies().Insert(ctx, key, &compute.TargetHttpProxy{UrlMap: "um", Description: "d"})
+
+	for _, tc := range []struct {
+		name    string
+		b       rnode.Builder
+		mode    rnode.StrictFieldsMode
+		wantErr bool
+	}{
+		{name: "off", b: healthcheck.NewBuilder(healthcheck.ID("proj", key))},
+		{name: "warn", b: healthcheck.NewBuilder(healthcheck.ID("proj", key)), mode: rnode.StrictFieldsWarn},
+		{name: "error", b: healthcheck.NewBuilder(healthcheck.ID("proj", key)), mode: rnode.StrictFieldsError, wantErr: true},
+		{name: "error classified", b: targethttpproxy.NewBuilder(targethttpproxy.ID("proj", key)), mode: rnode.StrictFieldsError},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.b.SyncFromCloud(rnode.WithStrictFields(ctx, tc.mode), mock)
+			var ufErr *rnode.UnclassifiedFieldsError
+			if gotErr := errors.As(err, &ufErr); gotErr != tc.wantErr {
+				t.Fatalf("SyncFromCloud() = %v; errors.As(UnclassifiedFieldsError) = %t, want %t", err, gotErr, tc.wantErr)
+			}
+			if tc.wantErr {
+				if tc.b.State() != rnode.NodeStateError {
+					t.Errorf("State() = %s, want %s", tc.b.State(), rnode.NodeStateError)
+				}
+				found := false
+				for _, p := range ufErr.Paths {
+					found = found || p.String() == "*.Name"
+				}
+				if !found {
+					t.Errorf("Paths = %v, want *.Name", ufErr.Paths)
+				}
+				return
+			}
+			if tc.b.State() != rnode.NodeExists {
+				t.Errorf("State() = %s, want %s", tc.b.State(), rnode.NodeExists)
+			}
+		})
+	}
+}
@@ -0,0 +1,90 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rnode
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/api"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+)
+
+// SyncFieldsMap are the fields fetched by SyncFromCloud for each type of
+// resource, keyed by the ResourceID.Resource (e.g. "urlMaps"). The fields
+// are the JSON names of the fields (see cloud.Fields). Types that are not in
+// the map are fetched in full.
+//
+// The fields classified by the traits of the resource (e.g. Fingerprint) are
+// always fetched. Fields that are not fetched are zero in the resource from
+// the Cloud, so the map must list all of the fields set by the graph for the
+// type; otherwise the diff will report these fields as changed.
+type SyncFieldsMap map[string][]string
+
+var syncFieldsContextKey = contextKey("sync fields")
+
+// WithSyncFields returns a context that limits the fields fetched by
+// SyncFromCloud calls made with it to m.
+//
+//	ctx = WithSyncFields(ctx, SyncFieldsMap{"urlMaps": {"defaultService", "hostRules", "pathMatchers"}})
+func WithSyncFields(ctx context.Context, m SyncFieldsMap) context.Context {
+	return context.WithValue(ctx, syncFieldsContextKey, m)
+}
+
+// SyncFields returns the SyncFieldsMap set in ctx.
+func SyncFields(ctx context.Context) SyncFieldsMap {
+	v, _ := ctx.Value(syncFieldsContextKey).(SyncFieldsMap)
+	return v
+}
+
+// syncFieldsOption returns the cloud.Fields option for fetching the resource
+// id with the SyncFieldsMap in ctx, or nil to fetch the complete resource.
+func syncFieldsOption[GA any, Alpha any, Beta any](
+	ctx context.Context,
+	ver meta.Version,
+	id *cloud.ResourceID,
+	tt api.TypeTrait[GA, Alpha, Beta],
+) cloud.Option {
+	fields := SyncFields(ctx)[id.Resource]
+	if len(fields) == 0 {
+		return nil
+	}
+	if tt != nil {
+		var t reflect.Type
+		switch ver {
+		case meta.VersionGA:
+			t = reflect.TypeOf((*GA)(nil))
+		case meta.VersionAlpha:
+			t = reflect.TypeOf((*Alpha)(nil))
+		case meta.VersionBeta:
+			t = reflect.TypeOf((*Beta)(nil))
+		}
+		if t != nil {
+			seen := map[string]bool{}
+			for _, f := range fields {
+				seen[f] = true
+			}
+			for _, f := range tt.FieldTraits(ver).ClassifiedFields(t) {
+				if !seen[f] {
+					fields = append(fields, f)
+				}
+			}
+		}
+	}
+	return cloud.Fields(fields...)
+}
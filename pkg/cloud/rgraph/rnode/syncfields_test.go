@@ -0,0 +1,92 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rnode
+
+import (
+	"context"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/api"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+)
+
+type syncFieldsRes struct {
+	Name            string `json:"name,omitempty"`
+	Fingerprint     string `json:"fingerprint,omitempty"`
+	HostRules       []int  `json:"hostRules,omitempty"`
+	NullFields      []string
+	ForceSendFields []string
+}
+
+func TestSyncFields(t *testing.T) {
+	id := &cloud.ResourceID{Resource: "urlMaps", ProjectID: "proj", Key: meta.GlobalKey("um")}
+	tt := &api.TypeTraitFuncs[syncFieldsRes, syncFieldsRes, syncFieldsRes]{
+		FieldTraitsF: func(meta.Version) *api.FieldTraits {
+			dt := &api.FieldTraits{}
+			dt.System(api.Path{}.Pointer().Field("Fingerprint"))
+			return dt
+		},
+	}
+	var gotOptions []cloud.Option
+	getFuncs := &GetFuncs[syncFieldsRes, syncFieldsRes, syncFieldsRes]{}
+	getFuncs.GA.Global = func(_ context.Context, _ *meta.Key, options ...cloud.Option) (*syncFieldsRes, error) {
+		gotOptions = options
+		return &syncFieldsRes{Name: "um"}, nil
+	}
+
+	for _, tc := range []struct {
+		name string
+		m    SyncFieldsMap
+		want cloud.Option
+	}{
+		{name: "no map"},
+		{name: "other resource", m: SyncFieldsMap{"backendServices": {"name"}}},
+		{
+			name: "classified fields are added",
+			m:    SyncFieldsMap{"urlMaps": {"name", "hostRules"}},
+			want: cloud.Fields("name", "hostRules", "fingerprint"),
+		},
+		{
+			name: "no duplicates",
+			m:    SyncFieldsMap{"urlMaps": {"fingerprint", "name"}},
+			want: cloud.Fields("fingerprint", "name"),
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tc.m != nil {
+				ctx = WithSyncFields(ctx, tc.m)
+			}
+			if _, err := getFuncs.Do(ctx, meta.VersionGA, id, tt); err != nil {
+				t.Fatalf("Do() = %v, want nil", err)
+			}
+			want := []cloud.Option{cloud.ForceProjectID("proj")}
+			if tc.want != nil {
+				want = append(want, tc.want)
+			}
+			if len(gotOptions) != len(want) {
+				t.Fatalf("options = %v, want %v", gotOptions, want)
+			}
+			for i := range want {
+				if gotOptions[i] != want[i] {
+					t.Errorf("options[%d] = %v, want %v", i, gotOptions[i], want[i])
+				}
+			}
+		})
+	}
+}
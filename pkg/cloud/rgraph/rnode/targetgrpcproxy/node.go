@@ -0,0 +1,149 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package targetgrpcproxy
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/api"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/exec"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode"
+	alpha "google.golang.org/api/compute/v0.alpha"
+	beta "google.golang.org/api/compute/v0.beta"
+	"google.golang.org/api/compute/v1"
+)
+
+type targetGrpcProxyNode struct {
+	rnode.NodeBase
+	resource TargetGrpcProxy
+}
+
+var _ rnode.Node = (*targetGrpcProxyNode)(nil)
+
+func (n *targetGrpcProxyNode) Resource() rnode.UntypedResource { return n.resource }
+
+func (n *targetGrpcProxyNode) Diff(gotNode rnode.Node) (*rnode.PlanDetails, error) {
+	got, ok := gotNode.(*targetGrpcProxyNode)
+	if !ok {
+		return nil, fmt.Errorf("TargetGrpcProxyNode: invalid type to Diff: %T", gotNode)
+	}
+
+	diff, err := got.resource.Diff(n.resource)
+	if err != nil {
+		return nil, fmt.Errorf("TargetGrpcProxyNode: Diff %w", err)
+	}
+
+	if !diff.HasDiff() {
+		return &rnode.PlanDetails{
+			Operation: rnode.OpNothing,
+			Why:       "No diff between got and want",
+		}, nil
+	}
+
+	var (
+		needsRecreate bool
+		details       []string
+	)
+
+	for _, delta := range diff.Items {
+		switch {
+		// ValidateForProxyless cannot be changed by Patch.
+		case delta.Path.Equal(api.Path{}.Pointer().Field("ValidateForProxyless")):
+			details = append(details, fmt.Sprintf("ValidateForProxyless change: '%v' -> '%v'", delta.A, delta.B))
+			needsRecreate = true
+		default:
+			details = append(details, fmt.Sprintf("%s change: '%v' -> '%v'", delta.Path, delta.A, delta.B))
+		}
+	}
+
+	if needsRecreate {
+		return &rnode.PlanDetails{
+			Operation: rnode.OpRecreate,
+			Why:       "TargetGrpcProxy needs to be recreated: " + strings.Join(details, ", "),
+			Diff:      diff,
+		}, nil
+	}
+	return &rnode.PlanDetails{
+		Operation: rnode.OpUpdate,
+		Why:       "TargetGrpcProxy needs to be updated: " + strings.Join(details, ", "),
+		Diff:      diff,
+	}, nil
+}
+
+func (n *targetGrpcProxyNode) Actions(got rnode.Node) ([]exec.Action, error) {
+	op := n.Plan().Op()
+
+	switch op {
+	case rnode.OpCreate:
+		return rnode.CreateActions[compute.TargetGrpcProxy, alpha.TargetGrpcProxy, beta.TargetGrpcProxy](&ops{}, n, n.resource)
+
+	case rnode.OpDelete:
+		return rnode.DeleteActions[compute.TargetGrpcProxy, alpha.TargetGrpcProxy, beta.TargetGrpcProxy](&ops{}, got, n)
+
+	case rnode.OpNothing:
+		return []exec.Action{exec.NewExistsAction(n.ID())}, nil
+
+	case rnode.OpRecreate:
+		return rnode.RecreateActions[compute.TargetGrpcProxy, alpha.TargetGrpcProxy, beta.TargetGrpcProxy](&ops{}, got, n, n.resource)
+
+	case rnode.OpUpdate:
+		gotNode, ok := got.(*targetGrpcProxyNode)
+		if !ok {
+			return nil, fmt.Errorf("TargetGrpcProxyNode: invalid type for got: %T", got)
+		}
+		f, err := fingerprint(gotNode)
+		if err != nil {
+			return nil, fmt.Errorf("Cannot get fingerprint from TargetGrpcProxy: %w", err)
+		}
+		return rnode.UpdateActions[compute.TargetGrpcProxy, alpha.TargetGrpcProxy, beta.TargetGrpcProxy](&ops{}, got, n, n.resource, f)
+	}
+
+	return nil, fmt.Errorf("TargetGrpcProxyNode: invalid plan op %s", op)
+}
+
+func fingerprint(gotNode *targetGrpcProxyNode) (string, error) {
+	gotRes := gotNode.resource
+	switch gotRes.Version() {
+	case meta.VersionGA:
+		obj, err := gotRes.ToGA()
+		if err != nil {
+			return "", err
+		}
+		return obj.Fingerprint, nil
+	case meta.VersionAlpha:
+		obj, err := gotRes.ToAlpha()
+		if err != nil {
+			return "", err
+		}
+		return obj.Fingerprint, nil
+	case meta.VersionBeta:
+		obj, err := gotRes.ToBeta()
+		if err != nil {
+			return "", err
+		}
+		return obj.Fingerprint, nil
+	}
+	return "", fmt.Errorf("Unsupported target gRPC proxy resource version %v", gotRes.Version())
+}
+
+func (n *targetGrpcProxyNode) Builder() rnode.Builder {
+	b := &builder{}
+	b.InitFromNode(n)
+	return b
+}
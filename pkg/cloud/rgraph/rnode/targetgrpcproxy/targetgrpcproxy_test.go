@@ -0,0 +1,249 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package targetgrpcproxy
+
+import (
+	"context"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/fake"
+	"google.golang.org/api/compute/v1"
+)
+
+func TestTargetGrpcProxySchema(t *testing.T) {
+	const proj = "proj-1"
+	key := meta.GlobalKey("key-1")
+	x := NewMutableTargetGrpcProxy(proj, key)
+	if err := x.CheckSchema(); err != nil {
+		t.Fatalf("CheckSchema() = %v, want nil", err)
+	}
+}
+
+func TestTargetGrpcProxyOutRefs(t *testing.T) {
+	const umURL = "https://www.googleapis.com/compute/v1/projects/proj-1/global/urlMaps/um"
+
+	m := NewMutableTargetGrpcProxy("proj-1", meta.GlobalKey("tgp"))
+	m.Access(func(x *compute.TargetGrpcProxy) { x.UrlMap = umURL })
+	r, _ := m.Freeze()
+
+	refs, err := NewBuilderWithResource(r).OutRefs()
+	if err != nil {
+		t.Fatalf("OutRefs() = %v, want nil", err)
+	}
+	if len(refs) != 1 || refs[0].To.Resource != "urlMaps" || refs[0].To.Key.Name != "um" {
+		t.Errorf("OutRefs() = %v, want one ref to urlMaps/um", refs)
+	}
+}
+
+func TestTargetGrpcProxyDiff(t *testing.T) {
+	newNode := func(f func(*compute.TargetGrpcProxy)) rnode.Node {
+		t.Helper()
+		m := NewMutableTargetGrpcProxy("proj-1", meta.GlobalKey("tgp"))
+		m.Access(func(x *compute.TargetGrpcProxy) {
+			x.Name = "tgp"
+			x.UrlMap = "https://www.googleapis.com/compute/v1/projects/proj-1/global/urlMaps/um"
+		})
+		m.Access(f)
+		r, err := m.Freeze()
+		if err != nil {
+			t.Fatalf("Freeze() = %v, want nil", err)
+		}
+		b := NewBuilderWithResource(r)
+		b.SetState(rnode.NodeExists)
+		n, err := b.Build()
+		if err != nil {
+			t.Fatalf("Build() = %v, want nil", err)
+		}
+		return n
+	}
+
+	for _, tc := range []struct {
+		name   string
+		f      func(*compute.TargetGrpcProxy)
+		wantOp rnode.Operation
+	}{
+		{
+			name:   "no diff",
+			f:      func(*compute.TargetGrpcProxy) {},
+			wantOp: rnode.OpNothing,
+		},
+		{
+			name: "UrlMap change is an update",
+			f: func(x *compute.TargetGrpcProxy) {
+				x.UrlMap = "https://www.googleapis.com/compute/v1/projects/proj-1/global/urlMaps/um2"
+			},
+			wantOp: rnode.OpUpdate,
+		},
+		{
+			name:   "ValidateForProxyless change is a recreate",
+			f:      func(x *compute.TargetGrpcProxy) { x.ValidateForProxyless = true },
+			wantOp: rnode.OpRecreate,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got := newNode(func(*compute.TargetGrpcProxy) {})
+			want := newNode(tc.f)
+			plan, err := want.Diff(got)
+			if err != nil {
+				t.Fatalf("Diff() = %v, want nil", err)
+			}
+			if plan.Operation != tc.wantOp {
+				t.Errorf("Diff().Operation = %v, want %v (why: %s)", plan.Operation, tc.wantOp, plan.Why)
+			}
+		})
+	}
+}
+
+func TestTargetGrpcProxyActions(t *testing.T) {
+	ctx := context.Background()
+	mock := cloud.NewMockGCE(&cloud.SingleProjectRouter{ID: "proj-1"})
+	id := ID("proj-1", meta.GlobalKey("tgp"))
+
+	// node builds a Node with the given state; f == nil leaves the resource
+	// unset.
+	node := func(state rnode.NodeState, f func(*compute.TargetGrpcProxy)) rnode.Node {
+		t.Helper()
+		b := NewBuilder(id)
+		if f != nil {
+			m := NewMutableTargetGrpcProxy("proj-1", id.Key)
+			m.Access(func(x *compute.TargetGrpcProxy) {
+				x.UrlMap = "https://www.googleapis.com/compute/v1/projects/proj-1/global/urlMaps/um"
+			})
+			m.Access(f)
+			r, err := m.Freeze()
+			if err != nil {
+				t.Fatalf("Freeze() = %v, want nil", err)
+			}
+			if err := b.SetResource(r); err != nil {
+				t.Fatalf("SetResource() = %v, want nil", err)
+			}
+		}
+		b.SetState(state)
+		n, err := b.Build()
+		if err != nil {
+			t.Fatalf("Build() = %v, want nil", err)
+		}
+		return n
+	}
+	// synced returns the Node for the current state in the mock.
+	synced := func() rnode.Node {
+		t.Helper()
+		b := NewBuilder(id)
+		if err := b.SyncFromCloud(ctx, mock); err != nil {
+			t.Fatalf("SyncFromCloud() = %v, want nil", err)
+		}
+		n, err := b.Clone().Build()
+		if err != nil {
+			t.Fatalf("Build() = %v, want nil", err)
+		}
+		return n
+	}
+	// run plans want against got and runs the resulting Actions.
+	run := func(got, want rnode.Node, op rnode.Operation) {
+		t.Helper()
+		want.Plan().Set(rnode.PlanDetails{Operation: op})
+		actions, err := want.Actions(got)
+		if err != nil {
+			t.Fatalf("Actions(%v) = %v, want nil", op, err)
+		}
+		for _, a := range actions {
+			if _, err := a.Run(ctx, mock); err != nil {
+				t.Fatalf("%s Run() = %v, want nil", a, err)
+			}
+		}
+	}
+
+	run(synced(), node(rnode.NodeExists, func(*compute.TargetGrpcProxy) {}), rnode.OpCreate)
+	got := synced()
+	if got.State() != rnode.NodeExists {
+		t.Fatalf("after create, State() = %v, want %v", got.State(), rnode.NodeExists)
+	}
+
+	run(got, node(rnode.NodeExists, func(*compute.TargetGrpcProxy) {}), rnode.OpNothing)
+
+	var patched *compute.TargetGrpcProxy
+	mock.MockTargetGrpcProxies.PatchHook = func(_ context.Context, _ *meta.Key, obj *compute.TargetGrpcProxy, _ *cloud.MockTargetGrpcProxies, _ ...cloud.Option) error {
+		patched = obj
+		return nil
+	}
+	run(got, node(rnode.NodeExists, func(x *compute.TargetGrpcProxy) { x.Description = "updated" }), rnode.OpUpdate)
+	if patched == nil || patched.Description != "updated" {
+		t.Errorf("after update, patched = %+v, want Description %q", patched, "updated")
+	}
+
+	run(synced(), node(rnode.NodeExists, func(x *compute.TargetGrpcProxy) { x.ValidateForProxyless = true }), rnode.OpRecreate)
+	obj, err := mock.TargetGrpcProxies().Get(ctx, id.Key)
+	if err != nil {
+		t.Fatalf("Get() = %v, want nil", err)
+	}
+	if !obj.ValidateForProxyless {
+		t.Errorf("after recreate, ValidateForProxyless = false, want true")
+	}
+
+	run(synced(), node(rnode.NodeDoesNotExist, nil), rnode.OpDelete)
+	if got := synced(); got.State() != rnode.NodeDoesNotExist {
+		t.Errorf("after delete, State() = %v, want %v", got.State(), rnode.NodeDoesNotExist)
+	}
+
+	want := node(rnode.NodeExists, func(*compute.TargetGrpcProxy) {})
+	want.Plan().Set(rnode.PlanDetails{Operation: rnode.OpUnknown})
+	if _, err := want.Actions(got); err == nil {
+		t.Errorf("Actions(OpUnknown) = nil, want error")
+	}
+}
+
+func TestTargetGrpcProxyInvalidTypes(t *testing.T) {
+	id := ID("proj-1", meta.GlobalKey("tgp"))
+	b := NewBuilder(id)
+	if err := b.SetResource(fake.Fake(nil)); err == nil {
+		t.Errorf("SetResource(fake) = nil, want error")
+	}
+	b.SetState(rnode.NodeExists)
+	if _, err := b.Build(); err == nil {
+		t.Errorf("Build() with NodeExists and nil resource = nil, want error")
+	}
+
+	m := NewMutableTargetGrpcProxy("proj-1", id.Key)
+	m.Access(func(x *compute.TargetGrpcProxy) { x.UrlMap = "garbage" })
+	r, _ := m.Freeze()
+	if _, err := NewBuilderWithResource(r).OutRefs(); err == nil {
+		t.Errorf("OutRefs() with invalid UrlMap = nil, want error")
+	}
+
+	m = NewMutableTargetGrpcProxy("proj-1", id.Key)
+	r, _ = m.Freeze()
+	b = NewBuilderWithResource(r)
+	b.SetState(rnode.NodeExists)
+	n, err := b.Build()
+	if err != nil {
+		t.Fatalf("Build() = %v, want nil", err)
+	}
+	fn, err := fake.NewBuilder(fake.ID("proj-1", meta.GlobalKey("f"))).Build()
+	if err != nil {
+		t.Fatalf("fake Build() = %v, want nil", err)
+	}
+	if _, err := n.Diff(fn); err == nil {
+		t.Errorf("Diff(fake) = nil, want error")
+	}
+	n.Plan().Set(rnode.PlanDetails{Operation: rnode.OpUpdate})
+	if _, err := n.Actions(fn); err == nil {
+		t.Errorf("Actions(fake) with OpUpdate = nil, want error")
+	}
+}
@@ -73,7 +73,7 @@ func (b *builder) OutRefs() ([]rnode.ResourceRef, error) {
 	obj, _ := b.resource.ToGA()
 
 	if obj.UrlMap != "" {
-		id, err := cloud.ParseResourceURL(obj.UrlMap)
+		id, err := b.ParseRefURL(obj.UrlMap)
 		if err != nil {
 			return nil, fmt.Errorf("targetHttpProxyNode: %w", err)
 		}
@@ -87,12 +87,19 @@ func (b *builder) OutRefs() ([]rnode.ResourceRef, error) {
 	return ret, nil
 }
 
+func (b *builder) Clone() rnode.Builder {
+	return &builder{
+		BuilderBase: b.CopyBase(),
+		resource:    rnode.CloneResource(b.resource),
+	}
+}
+
 func (b *builder) Build() (rnode.Node, error) {
 	if b.State() == rnode.NodeExists && b.resource == nil {
 		return nil, fmt.Errorf("TargetHttpProxy %s resource is nil with state %s", b.ID(), b.State())
 	}
 
-	ret := &targetHttpProxyNode{resource: b.resource}
+	ret := &targetHttpProxyNode{resource: rnode.CloneResource(b.resource)}
 	if err := ret.InitFromBuilder(b); err != nil {
 		return nil, err
 	}
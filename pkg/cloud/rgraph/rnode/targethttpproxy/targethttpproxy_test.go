@@ -20,6 +20,11 @@ import (
 	"testing"
 
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/testing/traitcheck"
+	alpha "google.golang.org/api/compute/v0.alpha"
+	beta "google.golang.org/api/compute/v0.beta"
+	"google.golang.org/api/compute/v1"
 )
 
 func TestTargetHttpProxySchema(t *testing.T) {
@@ -30,3 +35,39 @@ func TestTargetHttpProxySchema(t *testing.T) {
 		t.Fatalf("CheckSchema() = %v, want nil", err)
 	}
 }
+
+func TestTargetHttpProxyTraitCoverage(t *testing.T) {
+	traitcheck.Coverage[compute.TargetHttpProxy, alpha.TargetHttpProxy, beta.TargetHttpProxy](t, &targetHttpProxyTypeTrait{})
+}
+
+func TestOutRefsUnresolved(t *testing.T) {
+	const proj = "proj-1"
+	const urlMap = "https://compute.googleapis.com/compute/v1/projects/proj-1/locations/global/urlMaps/um"
+	x := NewMutableTargetHttpProxy(proj, meta.GlobalKey("tp"))
+	x.Access(func(x *compute.TargetHttpProxy) { x.UrlMap = urlMap })
+	r, err := x.Freeze()
+	if err != nil {
+		t.Fatalf("Freeze() = %v, want nil", err)
+	}
+
+	b := NewBuilderWithResource(r)
+	if _, err := b.OutRefs(); err == nil {
+		t.Fatalf("OutRefs() = nil, want error with %v", rnode.OutRefsStrict)
+	}
+	b.SetOutRefsMode(rnode.OutRefsWarn)
+	refs, err := b.OutRefs()
+	if err != nil {
+		t.Fatalf("OutRefs() = %v, want nil with %v", err, rnode.OutRefsWarn)
+	}
+	if len(refs) != 1 || !rnode.IsUnresolved(refs[0].To) || refs[0].To.Key.Name != urlMap {
+		t.Errorf("OutRefs() = %+v, want unresolved reference to %q", refs, urlMap)
+	}
+	b.SetState(rnode.NodeExists)
+	n, err := b.Build()
+	if err != nil {
+		t.Fatalf("Build() = %v, want nil", err)
+	}
+	if got := n.Builder().OutRefsMode(); got != rnode.OutRefsWarn {
+		t.Errorf("n.Builder().OutRefsMode() = %q, want %q", got, rnode.OutRefsWarn)
+	}
+}
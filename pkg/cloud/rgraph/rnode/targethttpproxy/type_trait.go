@@ -29,7 +29,7 @@ type targetHttpProxyTypeTrait struct {
 	api.BaseTypeTrait[compute.TargetHttpProxy, alpha.TargetHttpProxy, beta.TargetHttpProxy]
 }
 
-func (*targetHttpProxyTypeTrait) FieldTraits(meta.Version) *api.FieldTraits {
+func (*targetHttpProxyTypeTrait) FieldTraits(v meta.Version) *api.FieldTraits {
 	dt := api.NewFieldTraits()
 	// Built-ins
 	dt.OutputOnly(api.Path{}.Pointer().Field("Fingerprint"))
@@ -37,8 +37,21 @@ func (*targetHttpProxyTypeTrait) FieldTraits(meta.Version) *api.FieldTraits {
 	dt.OutputOnly(api.Path{}.Pointer().Field("CreationTimestamp"))
 	dt.OutputOnly(api.Path{}.Pointer().Field("Id"))
 	dt.OutputOnly(api.Path{}.Pointer().Field("Kind"))
+	dt.OutputOnly(api.Path{}.Pointer().Field("Region"))
 	dt.OutputOnly(api.Path{}.Pointer().Field("SelfLink"))
-	// TODO: finish me
-	// TODO: handle alpha/beta
+
+	dt.Description(api.Path{}.Pointer().Field("Description"))
+	dt.Ordinary(api.Path{}.Pointer().Field("HttpKeepAliveTimeoutSec"))
+	dt.Ordinary(api.Path{}.Pointer().Field("Name"))
+	dt.Ordinary(api.Path{}.Pointer().Field("ProxyBind"))
+	dt.Ordinary(api.Path{}.Pointer().Field("UrlMap"))
+
+	switch v {
+	case meta.VersionAlpha:
+		dt.OutputOnly(api.Path{}.Pointer().Field("SelfLinkWithId"))
+		dt.Ordinary(api.Path{}.Pointer().Field("HttpFilters"))
+	case meta.VersionBeta:
+		dt.Ordinary(api.Path{}.Pointer().Field("HttpFilters"))
+	}
 	return dt
 }
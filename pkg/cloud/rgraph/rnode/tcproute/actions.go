@@ -0,0 +1,135 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tcproute
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/api"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/exec"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode"
+	"google.golang.org/api/networkservices/v1"
+	beta "google.golang.org/api/networkservices/v1beta1"
+	"k8s.io/klog/v2"
+)
+
+func newTcpRouteUpdateAction(got, want *tcpRouteNode) (*tcpRouteUpdateAction, error) {
+	preEvents, err := rnode.UpdatePreconditions(got, want)
+	if err != nil {
+		return nil, err
+	}
+	return &tcpRouteUpdateAction{
+		ActionBase:      exec.ActionBase{Want: preEvents},
+		id:              want.ID(),
+		want:            want.resource,
+		weightTolerance: want.weightTolerance,
+		postEvents:      rnode.PostUpdateActionEvents(got, want),
+	}, nil
+}
+
+// tcpRouteUpdateAction updates the TcpRoute using Patch. TcpRoute does not
+// have a fingerprint, so the Action fetches the live object and patches only
+// the fields that differ from want (see api.UpdateMask). Concurrent changes to
+// other fields are preserved. Changes to the destination weights that are
+// within the weight tolerance are ignored, as in the plan.
+type tcpRouteUpdateAction struct {
+	exec.ActionBase
+
+	id              *cloud.ResourceID
+	want            TcpRoute
+	weightTolerance int64
+	postEvents      exec.EventList
+}
+
+func (a *tcpRouteUpdateAction) Run(ctx context.Context, cl cloud.Cloud) (exec.EventList, error) {
+	ops := &tcpRouteOps{}
+	live, err := ops.GetFuncs(cl).Do(ctx, a.want.Version(), a.id, &tcpRouteTypeTrait{})
+	if err != nil {
+		return nil, fmt.Errorf("tcpRouteUpdateAction Run(%s): Get: %w", a.id, err)
+	}
+	diff, err := live.Diff(a.want)
+	if err != nil {
+		return nil, fmt.Errorf("tcpRouteUpdateAction Run(%s): Diff: %w", a.id, err)
+	}
+	diff = ignoreWeightsWithinTolerance(diff, a.weightTolerance)
+	if !diff.HasDiff() {
+		klog.FromContext(ctx).V(2).Info("TcpRoute already up to date", "resourceID", a.id)
+		return a.postEvents, nil
+	}
+
+	traits := (&tcpRouteTypeTrait{}).FieldTraits(a.want.Version())
+	switch a.want.Version() {
+	case meta.VersionGA:
+		raw, err := a.want.ToGA()
+		if err != nil {
+			return nil, err
+		}
+		forceSendWeights(raw)
+		mask, err := api.UpdateMask[networkservices.TcpRoute](diff, traits)
+		if err != nil {
+			return nil, fmt.Errorf("tcpRouteUpdateAction Run(%s): %w", a.id, err)
+		}
+		if mask == "" {
+			break
+		}
+		err = cl.TcpRoutes().Patch(ctx, a.id.Key, raw, cloud.ForceProjectID(a.id.ProjectID), cloud.UpdateMask(mask))
+		if err != nil {
+			return nil, fmt.Errorf("tcpRouteUpdateAction Run(%s): Patch: %w", a.id, err)
+		}
+	case meta.VersionBeta:
+		raw, err := a.want.ToBeta()
+		if err != nil {
+			return nil, err
+		}
+		forceSendWeights(raw)
+		mask, err := api.UpdateMask[beta.TcpRoute](diff, traits)
+		if err != nil {
+			return nil, fmt.Errorf("tcpRouteUpdateAction Run(%s): %w", a.id, err)
+		}
+		if mask == "" {
+			break
+		}
+		err = cl.BetaTcpRoutes().Patch(ctx, a.id.Key, raw, cloud.ForceProjectID(a.id.ProjectID), cloud.UpdateMask(mask))
+		if err != nil {
+			return nil, fmt.Errorf("tcpRouteUpdateAction Run(%s): Patch: %w", a.id, err)
+		}
+	default:
+		return nil, fmt.Errorf("tcpRouteUpdateAction Run(%s): unsupported version %q", a.id, a.want.Version())
+	}
+
+	return a.postEvents, nil
+}
+
+func (a *tcpRouteUpdateAction) DryRun() exec.EventList {
+	return a.postEvents
+}
+
+func (a *tcpRouteUpdateAction) String() string {
+	return fmt.Sprintf("TcpRouteUpdateAction(%s)", a.id)
+}
+
+func (a *tcpRouteUpdateAction) Metadata() *exec.ActionMetadata {
+	return &exec.ActionMetadata{
+		Name:       fmt.Sprintf("TcpRouteUpdateAction(%s)", a.id),
+		Type:       exec.ActionTypeUpdate,
+		Summary:    fmt.Sprintf("Patch %s", a.id),
+		ResourceID: a.id,
+	}
+}
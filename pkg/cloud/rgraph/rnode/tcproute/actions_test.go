@@ -0,0 +1,325 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tcproute
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode"
+	"google.golang.org/api/networkservices/v1"
+)
+
+func TestUpdateAction(t *testing.T) {
+	ctx := context.Background()
+	id := ID(projectID, meta.GlobalKey("tcproute-1"))
+
+	gotNode := createTcpNode(t, id, rnode.NodeExists)
+	mutRes := defaultTCPRouteResource(t, id)
+	err := mutRes.Access(func(x *networkservices.TcpRoute) {
+		x.Rules[0].Action.Destinations[0].Weight = 50
+	})
+	if err != nil {
+		t.Fatalf("Access(_) = %v, want nil", err)
+	}
+	wantRes, err := mutRes.Freeze()
+	if err != nil {
+		t.Fatalf("Freeze() = %v, want nil", err)
+	}
+	b := gotNode.Builder()
+	b.SetResource(wantRes)
+	wantNode, err := b.Build()
+	if err != nil {
+		t.Fatalf("Build() = %v, want nil", err)
+	}
+
+	plan, err := wantNode.Diff(gotNode)
+	if err != nil {
+		t.Fatalf("Diff() = %v, want nil", err)
+	}
+	wantNode.Plan().Set(*plan)
+	actions, err := wantNode.Actions(gotNode)
+	if err != nil {
+		t.Fatalf("Actions() = %v, want nil", err)
+	}
+	if len(actions) != 1 {
+		t.Fatalf("len(Actions()) = %d, want 1", len(actions))
+	}
+	if got, want := actions[0].Metadata().Name, "TcpRouteUpdateAction(networkservices/tcpRoutes:proj-1/tcproute-1)"; got != want {
+		t.Errorf("Metadata().Name = %q, want %q", got, want)
+	}
+
+	for _, tc := range []struct {
+		name      string
+		live      TcpRoute
+		wantPatch bool
+	}{
+		{name: "live differs", live: gotNode.Resource().(TcpRoute), wantPatch: true},
+		{name: "live already up to date", live: wantRes},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			mock := cloud.NewMockGCE(&cloud.SingleProjectRouter{ID: projectID})
+			liveGA, _ := tc.live.ToGA()
+			if err := mock.TcpRoutes().Insert(ctx, id.Key, liveGA); err != nil {
+				t.Fatalf("Insert() = %v, want nil", err)
+			}
+			var patched *networkservices.TcpRoute
+			mock.MockTcpRoutes.PatchHook = func(_ context.Context, _ *meta.Key, obj *networkservices.TcpRoute, _ *cloud.MockTcpRoutes, _ ...cloud.Option) error {
+				patched = obj
+				return nil
+			}
+
+			if _, err := actions[0].Run(ctx, mock); err != nil {
+				t.Fatalf("Run() = %v, want nil", err)
+			}
+			if (patched != nil) != tc.wantPatch {
+				t.Fatalf("patched = %v, want %t", patched, tc.wantPatch)
+			}
+			if tc.wantPatch && patched.Rules[0].Action.Destinations[0].Weight != 50 {
+				t.Errorf("patched Weight = %d, want 50", patched.Rules[0].Action.Destinations[0].Weight)
+			}
+		})
+	}
+}
+
+// updateAction returns the update Action for the change made by f to the
+// default TcpRoute.
+func updateAction(t *testing.T, id *cloud.ResourceID, f func(x *networkservices.TcpRoute)) *tcpRouteUpdateAction {
+	t.Helper()
+
+	gotNode := createTcpNode(t, id, rnode.NodeExists)
+	mutRes := defaultTCPRouteResource(t, id)
+	if err := mutRes.Access(f); err != nil {
+		t.Fatalf("Access(_) = %v, want nil", err)
+	}
+	wantRes, err := mutRes.Freeze()
+	if err != nil {
+		t.Fatalf("Freeze() = %v, want nil", err)
+	}
+	b := gotNode.Builder()
+	b.SetResource(wantRes)
+	wantNode, err := b.Build()
+	if err != nil {
+		t.Fatalf("Build() = %v, want nil", err)
+	}
+	a, err := newTcpRouteUpdateAction(gotNode.(*tcpRouteNode), wantNode.(*tcpRouteNode))
+	if err != nil {
+		t.Fatalf("newTcpRouteUpdateAction() = %v, want nil", err)
+	}
+	return a
+}
+
+// patchRecorder records the options passed to TcpRoutes().Patch(); the
+// generated mock does not forward them to the PatchHook.
+type patchRecorder struct {
+	*cloud.MockGCE
+	routes recordingTcpRoutes
+}
+
+func (r *patchRecorder) TcpRoutes() cloud.TcpRoutes {
+	r.routes.TcpRoutes = r.MockGCE.TcpRoutes()
+	return &r.routes
+}
+
+type recordingTcpRoutes struct {
+	cloud.TcpRoutes
+	opts []cloud.Option
+}
+
+func (r *recordingTcpRoutes) Patch(ctx context.Context, key *meta.Key, obj *networkservices.TcpRoute, options ...cloud.Option) error {
+	r.opts = options
+	return r.TcpRoutes.Patch(ctx, key, obj, options...)
+}
+
+func TestUpdateActionMask(t *testing.T) {
+	ctx := context.Background()
+	id := ID(projectID, meta.GlobalKey("tcproute-1"))
+
+	for _, tc := range []struct {
+		name     string
+		f        func(x *networkservices.TcpRoute)
+		wantMask string
+	}{
+		{
+			name:     "rules",
+			f:        func(x *networkservices.TcpRoute) { x.Rules[0].Action.Destinations[0].Weight = 50 },
+			wantMask: "rules",
+		},
+		{
+			name:     "description",
+			f:        func(x *networkservices.TcpRoute) { x.Description = "new" },
+			wantMask: "description",
+		},
+		{
+			name: "description and meshes",
+			f: func(x *networkservices.TcpRoute) {
+				x.Description = "new"
+				x.Meshes = []string{"projects/proj-1/locations/global/meshes/mesh-2"}
+			},
+			wantMask: "description,meshes",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			a := updateAction(t, id, tc.f)
+
+			mock := cloud.NewMockGCE(&cloud.SingleProjectRouter{ID: projectID})
+			live, _ := createTcpNode(t, id, rnode.NodeExists).Resource().(TcpRoute).ToGA()
+			if err := mock.TcpRoutes().Insert(ctx, id.Key, live); err != nil {
+				t.Fatalf("Insert() = %v, want nil", err)
+			}
+			cl := &patchRecorder{MockGCE: mock}
+			if _, err := a.Run(ctx, cl); err != nil {
+				t.Fatalf("Run() = %v, want nil", err)
+			}
+			opts := cl.routes.opts
+			found := false
+			for _, o := range opts {
+				found = found || o == cloud.UpdateMask(tc.wantMask)
+			}
+			if !found {
+				t.Errorf("Patch options = %v, want UpdateMask(%q)", opts, tc.wantMask)
+			}
+		})
+	}
+}
+
+func TestUpdateActionWeightTolerance(t *testing.T) {
+	ctx := context.Background()
+	id := ID(projectID, meta.GlobalKey("tcproute-1"))
+
+	gotNode := createTcpNode(t, id, rnode.NodeExists)
+	mutRes := defaultTCPRouteResource(t, id)
+	err := mutRes.Access(func(x *networkservices.TcpRoute) {
+		x.Description = "new"
+		x.Rules[0].Action.Destinations[0].Weight = 11
+	})
+	if err != nil {
+		t.Fatalf("Access(_) = %v, want nil", err)
+	}
+	wantRes, err := mutRes.Freeze()
+	if err != nil {
+		t.Fatalf("Freeze() = %v, want nil", err)
+	}
+	b := gotNode.Builder()
+	b.SetResource(wantRes)
+	if err := SetWeightTolerance(b, 1); err != nil {
+		t.Fatalf("SetWeightTolerance() = %v, want nil", err)
+	}
+	wantNode, err := b.Build()
+	if err != nil {
+		t.Fatalf("Build() = %v, want nil", err)
+	}
+	a, err := newTcpRouteUpdateAction(gotNode.(*tcpRouteNode), wantNode.(*tcpRouteNode))
+	if err != nil {
+		t.Fatalf("newTcpRouteUpdateAction() = %v, want nil", err)
+	}
+
+	for _, tc := range []struct {
+		name     string
+		desc     string
+		wantMask string
+	}{
+		// Only the Description is patched; the weight is within tolerance.
+		{name: "description differs", wantMask: "description"},
+		// The weight within tolerance is the only difference.
+		{name: "only weight differs", desc: "new"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			mock := cloud.NewMockGCE(&cloud.SingleProjectRouter{ID: projectID})
+			live, _ := gotNode.Resource().(TcpRoute).ToGA()
+			if tc.desc != "" {
+				live.Description = tc.desc
+			}
+			if err := mock.TcpRoutes().Insert(ctx, id.Key, live); err != nil {
+				t.Fatalf("Insert() = %v, want nil", err)
+			}
+			cl := &patchRecorder{MockGCE: mock}
+			patched := false
+			mock.MockTcpRoutes.PatchHook = func(context.Context, *meta.Key, *networkservices.TcpRoute, *cloud.MockTcpRoutes, ...cloud.Option) error {
+				patched = true
+				return nil
+			}
+			if _, err := a.Run(ctx, cl); err != nil {
+				t.Fatalf("Run() = %v, want nil", err)
+			}
+			if patched != (tc.wantMask != "") {
+				t.Fatalf("patched = %t, want %t", patched, tc.wantMask != "")
+			}
+			if tc.wantMask == "" {
+				return
+			}
+			found := false
+			for _, o := range cl.routes.opts {
+				found = found || o == cloud.UpdateMask(tc.wantMask)
+			}
+			if !found {
+				t.Errorf("Patch options = %v, want UpdateMask(%q)", cl.routes.opts, tc.wantMask)
+			}
+		})
+	}
+}
+
+func TestUpdateActionErrors(t *testing.T) {
+	ctx := context.Background()
+	id := ID(projectID, meta.GlobalKey("tcproute-1"))
+	a := updateAction(t, id, func(x *networkservices.TcpRoute) { x.Description = "new" })
+
+	if got, want := a.String(), "TcpRouteUpdateAction(networkservices/tcpRoutes:proj-1/tcproute-1)"; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+	if got := a.DryRun(); !reflect.DeepEqual(got, a.postEvents) {
+		t.Errorf("DryRun() = %v, want %v", got, a.postEvents)
+	}
+
+	t.Run("not found", func(t *testing.T) {
+		mock := cloud.NewMockGCE(&cloud.SingleProjectRouter{ID: projectID})
+		if _, err := a.Run(ctx, mock); err == nil {
+			t.Errorf("Run() = nil, want error")
+		}
+	})
+	t.Run("patch error", func(t *testing.T) {
+		mock := cloud.NewMockGCE(&cloud.SingleProjectRouter{ID: projectID})
+		live, _ := createTcpNode(t, id, rnode.NodeExists).Resource().(TcpRoute).ToGA()
+		if err := mock.TcpRoutes().Insert(ctx, id.Key, live); err != nil {
+			t.Fatalf("Insert() = %v, want nil", err)
+		}
+		mock.MockTcpRoutes.PatchHook = func(context.Context, *meta.Key, *networkservices.TcpRoute, *cloud.MockTcpRoutes, ...cloud.Option) error {
+			return errors.New("injected")
+		}
+		if _, err := a.Run(ctx, mock); err == nil {
+			t.Errorf("Run() = nil, want error")
+		}
+	})
+}
+
+func TestOps(t *testing.T) {
+	mock := cloud.NewMockGCE(&cloud.SingleProjectRouter{ID: projectID})
+	ops := &tcpRouteOps{}
+	if f := ops.CreateFuncs(mock); f.GA.Global == nil || f.Beta.Global == nil {
+		t.Errorf("CreateFuncs() = %+v, want GA and Beta", f)
+	}
+	if f := ops.UpdateFuncs(mock); f.GA.Global == nil || f.Beta.Global == nil || f.Options != rnode.UpdateFuncsNoFingerprint {
+		t.Errorf("UpdateFuncs() = %+v, want GA and Beta with UpdateFuncsNoFingerprint", f)
+	}
+	if f := ops.DeleteFuncs(mock); f.GA.Global == nil || f.Beta.Global == nil {
+		t.Errorf("DeleteFuncs() = %+v, want GA and Beta", f)
+	}
+}
@@ -48,7 +48,24 @@ func NewBuilderWithResource(r TcpRoute) rnode.Builder {
 
 type builder struct {
 	rnode.BuilderBase
-	resource TcpRoute
+	resource        TcpRoute
+	weightTolerance int64
+}
+
+// SetWeightTolerance sets the difference in destination weights that is
+// ignored when diffing the TcpRoute, e.g. 1 to ignore rounding when weights
+// are computed from percentages by a controller. b must be a TcpRoute
+// Builder.
+func SetWeightTolerance(b rnode.Builder, tolerance int64) error {
+	tb, ok := b.(*builder)
+	if !ok {
+		return fmt.Errorf("SetWeightTolerance: %T is not a TcpRoute builder", b)
+	}
+	if tolerance < 0 {
+		return fmt.Errorf("SetWeightTolerance: invalid tolerance %d", tolerance)
+	}
+	tb.weightTolerance = tolerance
+	return nil
 }
 
 // builder implements node.Builder.
@@ -74,10 +91,31 @@ func (b *builder) OutRefs() ([]rnode.ResourceRef, error) {
 	if b.resource == nil {
 		return nil, nil
 	}
-	// TODO(kl52752) Add mesh dependency
 
 	var ret []rnode.ResourceRef
 	obj, _ := b.resource.ToGA()
+	for idx, mesh := range obj.Meshes {
+		id, err := b.parseNetworkServicesRef(mesh)
+		if err != nil {
+			return nil, fmt.Errorf("tcpRouteNode Meshes: %w", err)
+		}
+		ret = append(ret, rnode.ResourceRef{
+			From: b.resource.ResourceID(),
+			Path: api.Path{}.Field("Meshes").Index(idx),
+			To:   id,
+		})
+	}
+	for idx, gateway := range obj.Gateways {
+		id, err := b.parseNetworkServicesRef(gateway)
+		if err != nil {
+			return nil, fmt.Errorf("tcpRouteNode Gateways: %w", err)
+		}
+		ret = append(ret, rnode.ResourceRef{
+			From: b.resource.ResourceID(),
+			Path: api.Path{}.Field("Gateways").Index(idx),
+			To:   id,
+		})
+	}
 	for ruleIdx, rule := range obj.Rules {
 		if rule == nil || rule.Action == nil {
 			continue
@@ -86,7 +124,7 @@ func (b *builder) OutRefs() ([]rnode.ResourceRef, error) {
 			if dest == nil {
 				continue
 			}
-			id, err := cloud.ParseResourceURL(dest.ServiceName)
+			id, err := b.ParseRefURL(dest.ServiceName)
 			if err != nil {
 				return nil, fmt.Errorf("tcpRouteNode: %w", err)
 			}
@@ -100,12 +138,30 @@ func (b *builder) OutRefs() ([]rnode.ResourceRef, error) {
 	return ret, nil
 }
 
+// parseNetworkServicesRef parses a reference to a networkservices resource
+// (e.g. a Mesh). The API returns these as resource names
+// ("projects/<proj>/locations/global/meshes/<name>").
+func (b *builder) parseNetworkServicesRef(name string) (*cloud.ResourceID, error) {
+	if id, err := cloud.ParseNetworkServicesName(name); err == nil {
+		return id, nil
+	}
+	return b.ParseRefURL(name)
+}
+
+func (b *builder) Clone() rnode.Builder {
+	return &builder{
+		BuilderBase:     b.CopyBase(),
+		resource:        rnode.CloneResource(b.resource),
+		weightTolerance: b.weightTolerance,
+	}
+}
+
 func (b *builder) Build() (rnode.Node, error) {
 	if b.State() == rnode.NodeExists && b.resource == nil {
 		return nil, fmt.Errorf("TcpRoute %s resource is nil with state %s", b.ID(), b.State())
 	}
 
-	ret := &tcpRouteNode{resource: b.resource}
+	ret := &tcpRouteNode{resource: rnode.CloneResource(b.resource), weightTolerance: b.weightTolerance}
 	if err := ret.InitFromBuilder(b); err != nil {
 		return nil, err
 	}
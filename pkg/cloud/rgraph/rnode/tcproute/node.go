@@ -28,7 +28,8 @@ import (
 
 type tcpRouteNode struct {
 	rnode.NodeBase
-	resource TcpRoute
+	resource        TcpRoute
+	weightTolerance int64
 }
 
 var _ rnode.Node = (*tcpRouteNode)(nil)
@@ -45,11 +46,16 @@ func (n *tcpRouteNode) Diff(gotNode rnode.Node) (*rnode.PlanDetails, error) {
 	if err != nil {
 		return nil, fmt.Errorf("TcpRouteNode: Diff %w", err)
 	}
+	diff = ignoreWeightsWithinTolerance(diff, n.weightTolerance)
 
 	if diff.HasDiff() {
+		why := "TcpRoute needs to be updated"
+		if onlyWeights(diff) {
+			why = "TcpRoute destination weights need to be updated"
+		}
 		return &rnode.PlanDetails{
 			Operation: rnode.OpUpdate,
-			Why:       "TcpRoute needs to be recreated",
+			Why:       why,
 			Diff:      diff,
 		}, nil
 	}
@@ -75,8 +81,15 @@ func (n *tcpRouteNode) runOp(got rnode.Node, op rnode.Operation) ([]exec.Action,
 		return rnode.RecreateActions[networkservices.TcpRoute, api.PlaceholderType, beta.TcpRoute](&tcpRouteOps{}, got, n, n.resource)
 
 	case rnode.OpUpdate:
-		// TCP route does not support fingerprint
-		return rnode.UpdateActions[networkservices.TcpRoute, api.PlaceholderType, beta.TcpRoute](&tcpRouteOps{}, got, n, n.resource, "")
+		gotNode, ok := got.(*tcpRouteNode)
+		if !ok {
+			return nil, fmt.Errorf("TcpRouteNode: invalid type for update: %T", got)
+		}
+		act, err := newTcpRouteUpdateAction(gotNode, n)
+		if err != nil {
+			return nil, err
+		}
+		return []exec.Action{act}, nil
 	}
 
 	return nil, fmt.Errorf("TcpRouteNode: invalid plan op %s", op)
@@ -93,6 +106,7 @@ func (n *tcpRouteNode) Actions(got rnode.Node) ([]exec.Action, error) {
 
 func (n *tcpRouteNode) Builder() rnode.Builder {
 	b := &builder{}
-	b.Init(n.ID(), n.State(), n.Ownership(), n.resource)
+	b.InitFromNode(n)
+	b.weightTolerance = n.weightTolerance
 	return b
 }
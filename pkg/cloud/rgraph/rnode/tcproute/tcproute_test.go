@@ -167,7 +167,7 @@ func TestAction(t *testing.T) {
 			want: 1,
 		},
 		{
-			desc:    "update action - not implemented",
+			desc:    "update action - got does not exist",
 			op:      rnode.OpUpdate,
 			wantErr: true,
 		},
@@ -249,9 +249,10 @@ func validateOutRefs(t *testing.T, b rnode.Builder) {
 	if err != nil {
 		t.Fatalf("b.OutRefs() = %v, want nil", err)
 	}
-	if len(outRefs) != 2 {
-		t.Errorf("Expected 2 out refs")
+	if len(outRefs) != 3 {
+		t.Errorf("Expected 3 out refs")
 	}
+	gotResources := map[string]int{}
 	for _, o := range outRefs {
 		if o.From == nil {
 			t.Errorf("OutRefReference From is nil")
@@ -265,9 +266,10 @@ func validateOutRefs(t *testing.T, b rnode.Builder) {
 			t.Errorf("OutRefReference To is nil")
 			continue
 		}
-		if o.To.Resource != "backendServices" {
-			t.Errorf("o.To.Resource != BackendService: got: %v", o.To.Resource)
-		}
+		gotResources[o.To.Resource]++
+	}
+	if want := map[string]int{"meshes": 1, "backendServices": 2}; !reflect.DeepEqual(gotResources, want) {
+		t.Errorf("OutRefs resources = %v, want %v", gotResources, want)
 	}
 }
 
@@ -287,7 +289,7 @@ func defaultTCPRouteResource(t *testing.T, id *cloud.ResourceID) MutableTcpRoute
 	err := tcpMutResource.Access(func(x *networkservices.TcpRoute) {
 		x.Description = "desc"
 		x.Name = id.Key.Name
-		x.Meshes = []string{"mesh-1"}
+		x.Meshes = []string{"projects/proj-1/locations/global/meshes/mesh-1"}
 		x.Rules = []*networkservices.TcpRouteRouteRule{trrr, trrr}
 	})
 	if err != nil {
@@ -309,7 +311,7 @@ func defaultTCPRoute() *networkservices.TcpRoute {
 	}
 	return &networkservices.TcpRoute{
 		Name:   "tcproute-2",
-		Meshes: []string{"mesh-2"},
+		Meshes: []string{"projects/proj-1/locations/global/meshes/mesh-2"},
 		Rules:  []*networkservices.TcpRouteRouteRule{trrr},
 	}
 }
@@ -30,6 +30,8 @@ type tcpRouteTypeTrait struct {
 
 func (*tcpRouteTypeTrait) FieldTraits(meta.Version) *api.FieldTraits {
 	dt := api.NewFieldTraits()
+	dt.Description(api.Path{}.Pointer().Field("Description"))
+
 	dt.OutputOnly(api.Path{}.Pointer().Field("SelfLink"))
 	dt.OutputOnly(api.Path{}.Pointer().Field("CreateTime"))
 	dt.OutputOnly(api.Path{}.Pointer().Field("UpdateTime"))
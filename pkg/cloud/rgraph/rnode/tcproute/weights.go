@@ -0,0 +1,107 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tcproute
+
+import (
+	"reflect"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/api"
+	"google.golang.org/api/networkservices/v1"
+	beta "google.golang.org/api/networkservices/v1beta1"
+)
+
+// weightPath is the path to the weight of a destination in a TcpRoute.
+var weightPath = api.Path{}.Pointer().Field("Rules").AnySliceIndex().Pointer().Field("Action").Pointer().Field("Destinations").AnySliceIndex().Pointer().Field("Weight")
+
+// isWeight is true if the DiffItem is a change to a destination weight.
+func isWeight(item api.DiffItem) bool {
+	return item.State == api.DiffItemDifferent && weightPath.Match(item.Path)
+}
+
+// ignoreWeightsWithinTolerance returns diff without the changes to
+// destination weights that are within tolerance.
+func ignoreWeightsWithinTolerance(diff *api.DiffResult, tolerance int64) *api.DiffResult {
+	if tolerance == 0 {
+		return diff
+	}
+	ret := &api.DiffResult{}
+	for _, item := range diff.Items {
+		if isWeight(item) {
+			a, aOK := item.A.(int64)
+			b, bOK := item.B.(int64)
+			if aOK && bOK && a-b <= tolerance && b-a <= tolerance {
+				continue
+			}
+		}
+		ret.Items = append(ret.Items, item)
+	}
+	return ret
+}
+
+// onlyWeights is true if all of the changes in diff are to destination
+// weights.
+func onlyWeights(diff *api.DiffResult) bool {
+	for _, item := range diff.Items {
+		if !isWeight(item) {
+			return false
+		}
+	}
+	return true
+}
+
+// forceSendWeights sets the weights of the destinations in the rules of r to
+// be sent even if they are 0. The API treats a missing weight as unset (an
+// even split) so a weight of 0 must be sent explicitly if any of the other
+// destinations in the rule has a weight. The GA and beta TcpRoutes have the
+// same fields, so the rules are walked by field name.
+func forceSendWeights[T networkservices.TcpRoute | beta.TcpRoute](r *T) {
+	rules := reflect.ValueOf(r).Elem().FieldByName("Rules")
+	for i := 0; i < rules.Len(); i++ {
+		dests := fieldByNames(rules.Index(i), "Action", "Destinations")
+		if !dests.IsValid() {
+			continue
+		}
+		weighted := false
+		for j := 0; j < dests.Len(); j++ {
+			if w := fieldByNames(dests.Index(j), "Weight"); w.IsValid() && w.Int() != 0 {
+				weighted = true
+			}
+		}
+		if !weighted {
+			continue
+		}
+		for j := 0; j < dests.Len(); j++ {
+			d := dests.Index(j)
+			if w := fieldByNames(d, "Weight"); w.IsValid() && w.Int() == 0 {
+				fsf := d.Elem().FieldByName("ForceSendFields")
+				fsf.Set(reflect.Append(fsf, reflect.ValueOf("Weight")))
+			}
+		}
+	}
+}
+
+// fieldByNames follows the field names from the pointer to struct v. The
+// returned Value is invalid if any of the pointers is nil.
+func fieldByNames(v reflect.Value, names ...string) reflect.Value {
+	for _, name := range names {
+		if v.IsNil() {
+			return reflect.Value{}
+		}
+		v = v.Elem().FieldByName(name)
+	}
+	return v
+}
@@ -0,0 +1,134 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tcproute
+
+import (
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/backendservice"
+	"google.golang.org/api/networkservices/v1"
+	beta "google.golang.org/api/networkservices/v1beta1"
+)
+
+func TestWeightTolerance(t *testing.T) {
+	id := ID(projectID, meta.GlobalKey("tcproute-1"))
+	gotNode := createTcpNode(t, id, rnode.NodeExists)
+
+	for _, tc := range []struct {
+		name      string
+		tolerance int64
+		weight    int64
+		desc      string
+		wantOp    rnode.Operation
+		wantWhy   string
+	}{
+		{name: "same", tolerance: 1, weight: 10, wantOp: rnode.OpNothing},
+		{name: "within tolerance", tolerance: 1, weight: 11, wantOp: rnode.OpNothing},
+		{name: "within tolerance, lower", tolerance: 1, weight: 9, wantOp: rnode.OpNothing},
+		{name: "no tolerance", weight: 11, wantOp: rnode.OpUpdate, wantWhy: "TcpRoute destination weights need to be updated"},
+		{name: "outside tolerance", tolerance: 1, weight: 12, wantOp: rnode.OpUpdate, wantWhy: "TcpRoute destination weights need to be updated"},
+		{name: "other field", tolerance: 1, weight: 11, desc: "changed", wantOp: rnode.OpUpdate, wantWhy: "TcpRoute needs to be updated"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			mr := defaultTCPRouteResource(t, id)
+			mr.Access(func(x *networkservices.TcpRoute) {
+				x.Rules[0].Action.Destinations[0].Weight = tc.weight
+				if tc.desc != "" {
+					x.Description = tc.desc
+				}
+			})
+			r, err := mr.Freeze()
+			if err != nil {
+				t.Fatalf("Freeze() = %v, want nil", err)
+			}
+			b := gotNode.Builder()
+			b.SetResource(r)
+			if err := SetWeightTolerance(b, tc.tolerance); err != nil {
+				t.Fatalf("SetWeightTolerance() = %v, want nil", err)
+			}
+			wantNode, err := b.Build()
+			if err != nil {
+				t.Fatalf("Build() = %v, want nil", err)
+			}
+			plan, err := wantNode.Diff(gotNode)
+			if err != nil {
+				t.Fatalf("Diff() = %v, want nil", err)
+			}
+			if plan.Operation != tc.wantOp {
+				t.Errorf("Diff() Operation = %v, want %v (diff: %+v)", plan.Operation, tc.wantOp, plan.Diff)
+			}
+			if tc.wantWhy != "" && plan.Why != tc.wantWhy {
+				t.Errorf("Diff() Why = %q, want %q", plan.Why, tc.wantWhy)
+			}
+			// The tolerance is preserved by Builder().
+			if got := wantNode.Builder().(*builder).weightTolerance; got != tc.tolerance {
+				t.Errorf("Builder() weightTolerance = %d, want %d", got, tc.tolerance)
+			}
+		})
+	}
+
+	bsBuilder := backendservice.NewBuilder(backendservice.ID(projectID, meta.GlobalKey("bs")))
+	if err := SetWeightTolerance(bsBuilder, 1); err == nil {
+		t.Error("SetWeightTolerance(non-TcpRoute builder) = nil, want error")
+	}
+}
+
+func TestForceSendWeights(t *testing.T) {
+	dest := func(w int64) *networkservices.TcpRouteRouteDestination {
+		return &networkservices.TcpRouteRouteDestination{ServiceName: "bs", Weight: w}
+	}
+	r := &networkservices.TcpRoute{
+		Rules: []*networkservices.TcpRouteRouteRule{
+			{Action: &networkservices.TcpRouteRouteAction{Destinations: []*networkservices.TcpRouteRouteDestination{dest(100), dest(0)}}},
+			{Action: &networkservices.TcpRouteRouteAction{Destinations: []*networkservices.TcpRouteRouteDestination{dest(0), dest(0)}}},
+		},
+	}
+	forceSendWeights(r)
+
+	for i, want := range [][]bool{{false, true}, {false, false}} {
+		for j, d := range r.Rules[i].Action.Destinations {
+			if got := len(d.ForceSendFields) > 0; got != want[j] {
+				t.Errorf("Rules[%d].Destinations[%d].ForceSendFields = %v, want Weight sent = %t", i, j, d.ForceSendFields, want[j])
+			}
+		}
+	}
+}
+
+func TestForceSendBetaWeights(t *testing.T) {
+	r := &beta.TcpRoute{
+		Rules: []*beta.TcpRouteRouteRule{
+			nil,
+			{},
+			{Action: &beta.TcpRouteRouteAction{Destinations: []*beta.TcpRouteRouteDestination{
+				{ServiceName: "bs1", Weight: 100},
+				nil,
+				{ServiceName: "bs2"},
+			}}},
+		},
+	}
+	forceSendWeights(r)
+
+	dests := r.Rules[2].Action.Destinations
+	if len(dests[0].ForceSendFields) != 0 {
+		t.Errorf("Destinations[0].ForceSendFields = %v, want []", dests[0].ForceSendFields)
+	}
+	if got := dests[2].ForceSendFields; len(got) != 1 || got[0] != "Weight" {
+		t.Errorf("Destinations[2].ForceSendFields = %v, want [Weight]", got)
+	}
+}
@@ -73,7 +73,7 @@ func (b *builder) OutRefs() ([]rnode.ResourceRef, error) {
 	obj, _ := b.resource.ToGA()
 	// DefaultService
 	if obj.DefaultService != "" {
-		id, err := cloud.ParseResourceURL(obj.DefaultService)
+		id, err := b.ParseRefURL(obj.DefaultService)
 		if err != nil {
 			return nil, fmt.Errorf("UrlMapNode DefaultService: %w", err)
 		}
@@ -89,12 +89,19 @@ func (b *builder) OutRefs() ([]rnode.ResourceRef, error) {
 	return ret, nil
 }
 
+func (b *builder) Clone() rnode.Builder {
+	return &builder{
+		BuilderBase: b.CopyBase(),
+		resource:    rnode.CloneResource(b.resource),
+	}
+}
+
 func (b *builder) Build() (rnode.Node, error) {
 	if b.State() == rnode.NodeExists && b.resource == nil {
 		return nil, fmt.Errorf("UrlMap %s resource is nil with state %s", b.ID(), b.State())
 	}
 
-	ret := &urlMapNode{resource: b.resource}
+	ret := &urlMapNode{resource: rnode.CloneResource(b.resource)}
 	if err := ret.InitFromBuilder(b); err != nil {
 		return nil, err
 	}
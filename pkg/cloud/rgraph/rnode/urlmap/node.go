@@ -86,6 +86,6 @@ func (n *urlMapNode) Actions(got rnode.Node) ([]exec.Action, error) {
 
 func (n *urlMapNode) Builder() rnode.Builder {
 	b := &builder{}
-	b.Init(n.ID(), n.State(), n.Ownership(), n.resource)
+	b.InitFromNode(n)
 	return b
 }
@@ -31,6 +31,7 @@ type urlMapTypeTrait struct {
 
 func (*urlMapTypeTrait) FieldTraits(meta.Version) *api.FieldTraits {
 	dt := api.NewFieldTraits()
+	dt.Description(api.Path{}.Pointer().Field("Description"))
 
 	dt.OutputOnly(api.Path{}.Pointer().Field("CreationTimestamp"))
 	dt.OutputOnly(api.Path{}.Pointer().Field("Id"))
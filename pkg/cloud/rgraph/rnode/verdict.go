@@ -0,0 +1,122 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rnode
+
+// Verdict is the decision of the planner for a Node. Each Verdict maps to a
+// single Operation; the Verdict distinguishes between the reasons for the
+// same Operation (e.g. VerdictInSync and VerdictUnmanaged are both
+// OpNothing).
+type Verdict string
+
+const (
+	// VerdictUnknown means no planning has been done.
+	VerdictUnknown Verdict = ""
+	// VerdictUnmanaged nodes are not changed by the planner.
+	VerdictUnmanaged Verdict = "Unmanaged"
+	// VerdictAbsent nodes do not exist in either got or want.
+	VerdictAbsent Verdict = "Absent"
+	// VerdictCreate nodes exist in want but not in got.
+	VerdictCreate Verdict = "Create"
+	// VerdictDelete nodes exist in got but not in want.
+	VerdictDelete Verdict = "Delete"
+	// VerdictInSync nodes exist in got and want without a diff.
+	VerdictInSync Verdict = "InSync"
+	// VerdictUpdate nodes have a diff that can be updated in place.
+	VerdictUpdate Verdict = "Update"
+	// VerdictRecreate nodes have a diff that requires the resource to be
+	// recreated.
+	VerdictRecreate Verdict = "Recreate"
+	// VerdictDependencyRecreated nodes are recreated because a resource
+	// they reference is recreated.
+	VerdictDependencyRecreated Verdict = "DependencyRecreated"
+	// VerdictBlocked nodes could not be synced from the Cloud, or depend on
+	// a node that could not be synced. Nothing is done for them.
+	VerdictBlocked Verdict = "Blocked"
+)
+
+// VerdictInputs are the inputs to the planner decision for a Node.
+type VerdictInputs struct {
+	// Ownership of the Node.
+	Ownership OwnershipStatus
+	// GotState is the current state of the resource.
+	GotState NodeState
+	// WantState is the wanted state of the resource.
+	WantState NodeState
+	// HasDiff is true if got and want both exist and differ.
+	HasDiff bool
+}
+
+// Transition is an entry in Transitions.
+type Transition struct {
+	// Managed is true if the entry applies to OwnershipManaged Nodes.
+	Managed bool
+	// GotState and WantState of the Node. Empty matches any state.
+	GotState, WantState NodeState
+	// DiffOp is the Operation returned by Node.Diff(). This is only set for
+	// Nodes that exist in both got and want.
+	DiffOp Operation
+	// Verdict for the Node.
+	Verdict Verdict
+	// Operation planned for the Node.
+	Operation Operation
+	// Why is the explanation used in the PlanDetails.
+	Why string
+}
+
+// Transitions is the table used by the local planner to decide the Verdict
+// for each Node. Nodes that are not in the table cannot be planned. Later
+// phases of the planner may change the Verdict (e.g.
+// VerdictDependencyRecreated).
+var Transitions = []Transition{
+	{Managed: false, Verdict: VerdictUnmanaged, Operation: OpNothing, Why: "Node is not managed"},
+	{Managed: true, GotState: NodeStateError, Verdict: VerdictBlocked, Operation: OpNothing, Why: "Node could not be synced from the Cloud"},
+	{Managed: true, GotState: NodeExists, WantState: NodeExists, DiffOp: OpNothing, Verdict: VerdictInSync, Operation: OpNothing},
+	{Managed: true, GotState: NodeExists, WantState: NodeExists, DiffOp: OpUpdate, Verdict: VerdictUpdate, Operation: OpUpdate},
+	{Managed: true, GotState: NodeExists, WantState: NodeExists, DiffOp: OpRecreate, Verdict: VerdictRecreate, Operation: OpRecreate},
+	{Managed: true, GotState: NodeExists, WantState: NodeDoesNotExist, Verdict: VerdictDelete, Operation: OpDelete, Why: "Node doesn't exist in want, but exists in got"},
+	{Managed: true, GotState: NodeDoesNotExist, WantState: NodeExists, Verdict: VerdictCreate, Operation: OpCreate, Why: "Node doesn't exist in got, but exists in want"},
+	{Managed: true, GotState: NodeDoesNotExist, WantState: NodeDoesNotExist, Verdict: VerdictAbsent, Operation: OpNothing, Why: "Node does not exist"},
+}
+
+// LookupTransition returns the entry in Transitions for the inputs. diffOp
+// is the Operation from Node.Diff() for Nodes that exist in both got and
+// want, and is ignored otherwise. Returns false if there is no entry.
+func LookupTransition(in VerdictInputs, diffOp Operation) (Transition, bool) {
+	managed := in.Ownership == OwnershipManaged
+	for _, t := range Transitions {
+		if t.Managed != managed {
+			continue
+		}
+		if t.GotState != "" && t.GotState != in.GotState {
+			continue
+		}
+		if t.WantState != "" && t.WantState != in.WantState {
+			continue
+		}
+		if t.DiffOp != "" && t.DiffOp != diffOp {
+			continue
+		}
+		return t, true
+	}
+	return Transition{}, false
+}
+
+// NeedsDiff returns true if the Transition for the inputs depends on the
+// result of Node.Diff().
+func NeedsDiff(in VerdictInputs) bool {
+	return in.Ownership == OwnershipManaged && in.GotState == NodeExists && in.WantState == NodeExists
+}
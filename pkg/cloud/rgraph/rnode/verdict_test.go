@@ -0,0 +1,109 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rnode
+
+import "testing"
+
+func TestLookupTransition(t *testing.T) {
+	for _, tc := range []struct {
+		name        string
+		in          VerdictInputs
+		diffOp      Operation
+		wantVerdict Verdict
+		wantOp      Operation
+		wantOK      bool
+	}{
+		{
+			name:        "unmanaged",
+			in:          VerdictInputs{Ownership: OwnershipExternal, GotState: NodeDoesNotExist, WantState: NodeExists},
+			wantVerdict: VerdictUnmanaged,
+			wantOp:      OpNothing,
+			wantOK:      true,
+		},
+		{
+			name:        "create",
+			in:          VerdictInputs{Ownership: OwnershipManaged, GotState: NodeDoesNotExist, WantState: NodeExists},
+			wantVerdict: VerdictCreate,
+			wantOp:      OpCreate,
+			wantOK:      true,
+		},
+		{
+			name:        "delete",
+			in:          VerdictInputs{Ownership: OwnershipManaged, GotState: NodeExists, WantState: NodeDoesNotExist},
+			wantVerdict: VerdictDelete,
+			wantOp:      OpDelete,
+			wantOK:      true,
+		},
+		{
+			name:        "absent",
+			in:          VerdictInputs{Ownership: OwnershipManaged, GotState: NodeDoesNotExist, WantState: NodeDoesNotExist},
+			wantVerdict: VerdictAbsent,
+			wantOp:      OpNothing,
+			wantOK:      true,
+		},
+		{
+			name:        "in sync",
+			in:          VerdictInputs{Ownership: OwnershipManaged, GotState: NodeExists, WantState: NodeExists},
+			diffOp:      OpNothing,
+			wantVerdict: VerdictInSync,
+			wantOp:      OpNothing,
+			wantOK:      true,
+		},
+		{
+			name:        "update",
+			in:          VerdictInputs{Ownership: OwnershipManaged, GotState: NodeExists, WantState: NodeExists, HasDiff: true},
+			diffOp:      OpUpdate,
+			wantVerdict: VerdictUpdate,
+			wantOp:      OpUpdate,
+			wantOK:      true,
+		},
+		{
+			name:        "recreate",
+			in:          VerdictInputs{Ownership: OwnershipManaged, GotState: NodeExists, WantState: NodeExists, HasDiff: true},
+			diffOp:      OpRecreate,
+			wantVerdict: VerdictRecreate,
+			wantOp:      OpRecreate,
+			wantOK:      true,
+		},
+		{
+			name:        "sync error",
+			in:          VerdictInputs{Ownership: OwnershipManaged, GotState: NodeStateError, WantState: NodeExists},
+			wantVerdict: VerdictBlocked,
+			wantOp:      OpNothing,
+			wantOK:      true,
+		},
+		{
+			name: "invalid state",
+			in:   VerdictInputs{Ownership: OwnershipManaged, GotState: NodeUnknown, WantState: NodeExists},
+		},
+		{
+			name:   "invalid diff op",
+			in:     VerdictInputs{Ownership: OwnershipManaged, GotState: NodeExists, WantState: NodeExists},
+			diffOp: OpDelete,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tr, ok := LookupTransition(tc.in, tc.diffOp)
+			if ok != tc.wantOK {
+				t.Fatalf("LookupTransition(%+v, %q) = _, %t, want %t", tc.in, tc.diffOp, ok, tc.wantOK)
+			}
+			if tr.Verdict != tc.wantVerdict || tr.Operation != tc.wantOp {
+				t.Errorf("LookupTransition(%+v, %q) = %s/%s, want %s/%s", tc.in, tc.diffOp, tr.Verdict, tr.Operation, tc.wantVerdict, tc.wantOp)
+			}
+		})
+	}
+}
@@ -0,0 +1,70 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rgraph
+
+import (
+	"sync"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode"
+)
+
+// NewSyncBuilder returns a new SyncBuilder.
+func NewSyncBuilder() *SyncBuilder {
+	return &SyncBuilder{b: NewBuilder()}
+}
+
+// SyncBuilder is a Builder that is safe for concurrent use. This allows
+// multiple callers (e.g. informer event handlers) to update the wanted graph
+// while a snapshot Graph is built for reconciliation.
+//
+// Node Builders that are added must not be modified by the caller afterwards;
+// use Update() to modify nodes in place.
+type SyncBuilder struct {
+	lock sync.Mutex
+	b    *Builder
+}
+
+// Add a node to the graph, replacing any existing node with the same ID.
+func (g *SyncBuilder) Add(node rnode.Builder) {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+	g.b.Add(node)
+}
+
+// Remove the node named by id from the graph.
+func (g *SyncBuilder) Remove(id *cloud.ResourceID) {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+	g.b.Remove(id)
+}
+
+// Update calls f with exclusive access to the underlying Builder. f must not
+// retain the Builder or its nodes after returning.
+func (g *SyncBuilder) Update(f func(b *Builder) error) error {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+	return f(g.b)
+}
+
+// Build a snapshot Graph from the current nodes. The Graph is not affected by
+// subsequent changes to the SyncBuilder.
+func (g *SyncBuilder) Build() (*Graph, error) {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+	return g.b.Build()
+}
@@ -0,0 +1,114 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rgraph
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/fake"
+)
+
+func fakeID(i int) *cloud.ResourceID {
+	return &cloud.ResourceID{Resource: "fake", Key: meta.GlobalKey(fmt.Sprintf("r%d", i))}
+}
+
+func managedFake(i int) *fake.Builder {
+	b := fake.NewBuilder(fakeID(i))
+	b.SetOwnership(rnode.OwnershipManaged)
+	return b
+}
+
+func TestSyncBuilder(t *testing.T) {
+	const n = 50
+
+	sb := NewSyncBuilder()
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			sb.Add(managedFake(i))
+			// Snapshots can be taken while the graph is being updated.
+			if _, err := sb.Build(); err != nil {
+				t.Errorf("Build() = %v, want nil", err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	g, err := sb.Build()
+	if err != nil {
+		t.Fatalf("Build() = %v, want nil", err)
+	}
+	if len(g.All()) != n {
+		t.Errorf("len(g.All()) = %d, want %d", len(g.All()), n)
+	}
+
+	sb.Remove(fakeID(0))
+	err = sb.Update(func(b *Builder) error {
+		if b.Get(fakeID(0)) != nil {
+			return fmt.Errorf("r0 was not removed")
+		}
+		b.Get(fakeID(1)).SetOwnership(rnode.OwnershipExternal)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Update() = %v, want nil", err)
+	}
+
+	g2, err := sb.Build()
+	if err != nil {
+		t.Fatalf("Build() = %v, want nil", err)
+	}
+	if len(g2.All()) != n-1 {
+		t.Errorf("len(g2.All()) = %d, want %d", len(g2.All()), n-1)
+	}
+	if got := g2.Get(fakeID(1)).Ownership(); got != rnode.OwnershipExternal {
+		t.Errorf("r1 Ownership() = %v, want %v", got, rnode.OwnershipExternal)
+	}
+	// The earlier snapshot is not affected.
+	if len(g.All()) != n || g.Get(fakeID(1)).Ownership() != rnode.OwnershipManaged {
+		t.Errorf("snapshot g was modified")
+	}
+}
+
+func TestBuilderRebuildInRefs(t *testing.T) {
+	b := NewBuilder()
+	b0 := managedFake(0)
+	b0.FakeOutRefs = []rnode.ResourceRef{{From: fakeID(0), To: fakeID(1)}}
+	b.Add(b0)
+	b.Add(managedFake(1))
+
+	for i := 0; i < 2; i++ {
+		g := b.MustBuild()
+		if got := len(g.Get(fakeID(1)).InRefs()); got != 1 {
+			t.Errorf("Build %d: len(InRefs()) = %d, want 1", i, got)
+		}
+	}
+
+	// Removing the referencing node removes the InRef.
+	b.Remove(fakeID(0))
+	g := b.MustBuild()
+	if got := len(g.Get(fakeID(1)).InRefs()); got != 0 {
+		t.Errorf("after Remove: len(InRefs()) = %d, want 0", got)
+	}
+}
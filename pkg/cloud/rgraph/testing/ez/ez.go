@@ -155,6 +155,10 @@ const (
 	Exists
 	// DoesNotExist state.
 	DoesNotExist
+	// DeletionProtected node.
+	DeletionProtected
+	// CascadeDelete node.
+	CascadeDelete
 )
 
 func (g *Graph) Builder() *rgraph.Builder {
@@ -90,6 +90,9 @@ func setCommonOptions(n *Node, b rnode.Builder) {
 	case n.Options&DoesNotExist != 0:
 		b.SetState(rnode.NodeDoesNotExist)
 	}
+
+	b.SetDeletionProtected(n.Options&DeletionProtected != 0)
+	b.SetCascadeDelete(n.Options&CascadeDelete != 0)
 }
 
 type addressFactory struct{}
@@ -0,0 +1,150 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package golden is a test harness that compares the Actions generated by the
+// planner against a golden file.
+//
+// The "got" and "want" graphs are given as loader documents (see package
+// loader). The "got" graph is first applied to a MockGCE and then the "want"
+// graph is planned against the mock. The resulting set of Actions is compared
+// to the golden file.
+//
+// Golden files can be regenerated by running the test with -golden.update:
+//
+//	$ go test ./my/package -golden.update
+package golden
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/exec"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/loader"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/workflow/plan"
+	"github.com/google/go-cmp/cmp"
+)
+
+var update = flag.Bool("golden.update", false, "Update the golden files instead of comparing against them")
+
+// Case is a golden test case.
+type Case struct {
+	// Got is the path to the document describing the existing resources.
+	// This may be empty, in which case the cloud starts out empty.
+	Got string
+	// Want is the path to the document describing the wanted resources.
+	Want string
+	// Golden is the path to the golden file with the expected Actions.
+	Golden string
+}
+
+// Run the test case. The test fails if the planned Actions differ from the
+// golden file.
+func Run(t *testing.T, c Case) {
+	t.Helper()
+
+	out, err := Actions(c.Got, c.Want)
+	if err != nil {
+		t.Fatalf("golden.Actions(%q, %q) = %v", c.Got, c.Want, err)
+	}
+
+	if *update {
+		if err := os.MkdirAll(filepath.Dir(c.Golden), 0755); err != nil {
+			t.Fatalf("MkdirAll(%q) = %v", filepath.Dir(c.Golden), err)
+		}
+		if err := os.WriteFile(c.Golden, []byte(out), 0644); err != nil {
+			t.Fatalf("WriteFile(%q) = %v", c.Golden, err)
+		}
+		t.Logf("Updated golden file %q", c.Golden)
+		return
+	}
+
+	golden, err := os.ReadFile(c.Golden)
+	if err != nil {
+		t.Fatalf("ReadFile(%q) = %v (run with -golden.update to create it)", c.Golden, err)
+	}
+	if diff := cmp.Diff(string(golden), out); diff != "" {
+		t.Errorf("Actions differ from golden file %q (run with -golden.update to regenerate); diff -golden,+got:\n%s", c.Golden, diff)
+	}
+}
+
+// Actions returns the planned Actions for the transition from the got to
+// the want document as a string, one Action per line in sorted order.
+func Actions(gotFile, wantFile string) (string, error) {
+	ctx := context.Background()
+	mock := cloud.NewMockGCE(&cloud.SingleProjectRouter{ID: "golden-project"})
+
+	if gotFile != "" {
+		if err := apply(ctx, mock, gotFile); err != nil {
+			return "", fmt.Errorf("got: %w", err)
+		}
+	}
+
+	wantBuilder, err := loader.LoadFile(wantFile)
+	if err != nil {
+		return "", err
+	}
+	want, err := wantBuilder.Build()
+	if err != nil {
+		return "", err
+	}
+	result, err := plan.Do(ctx, mock, want)
+	if err != nil {
+		return "", err
+	}
+
+	var lines []string
+	for _, a := range result.Actions {
+		lines = append(lines, a.Metadata().Name)
+	}
+	sort.Strings(lines)
+
+	return strings.Join(lines, "\n") + "\n", nil
+}
+
+// apply the graph in the file to the mock.
+func apply(ctx context.Context, mock cloud.Cloud, file string) error {
+	b, err := loader.LoadFile(file)
+	if err != nil {
+		return err
+	}
+	g, err := b.Build()
+	if err != nil {
+		return err
+	}
+	result, err := plan.Do(ctx, mock, g)
+	if err != nil {
+		return err
+	}
+	ex, err := exec.NewSerialExecutor(mock, result.Actions)
+	if err != nil {
+		return err
+	}
+	exResult, err := ex.Run(ctx)
+	if err != nil {
+		return err
+	}
+	if len(exResult.Pending) > 0 {
+		return fmt.Errorf("%d pending actions after apply", len(exResult.Pending))
+	}
+	return nil
+}
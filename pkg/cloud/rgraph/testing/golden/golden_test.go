@@ -0,0 +1,80 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package golden
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestGolden(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		c    Case
+	}{
+		{
+			name: "create",
+			c:    Case{Want: "testdata/want.json", Golden: "testdata/create.golden"},
+		},
+		{
+			name: "update",
+			c:    Case{Got: "testdata/got.json", Want: "testdata/want.json", Golden: "testdata/update.golden"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) { Run(t, tc.c) })
+	}
+}
+
+func TestGoldenUpdate(t *testing.T) {
+	c := Case{Want: "testdata/want.json", Golden: filepath.Join(t.TempDir(), "sub", "create.golden")}
+
+	*update = true
+	Run(t, c)
+	*update = false
+
+	got, err := os.ReadFile(c.Golden)
+	if err != nil {
+		t.Fatalf("ReadFile(%q) = %v", c.Golden, err)
+	}
+	want, err := os.ReadFile("testdata/create.golden")
+	if err != nil {
+		t.Fatalf("ReadFile() = %v", err)
+	}
+	if string(got) != string(want) {
+		t.Errorf("updated golden file = %q, want %q", got, want)
+	}
+	// The updated file must compare equal.
+	Run(t, c)
+}
+
+func TestActionsErrors(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		got  string
+		want string
+	}{
+		{name: "missing want", want: "testdata/does-not-exist.json"},
+		{name: "missing got", got: "testdata/does-not-exist.json", want: "testdata/want.json"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := Actions(tc.got, tc.want); err == nil {
+				t.Errorf("Actions(%q, %q) = nil, want error", tc.got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,145 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package traitcheck is a test helper that checks that the FieldTraits of a
+// resource classify every field of the GA, alpha and beta API types. New
+// fields added to the API will fail the check until they are given a trait.
+//
+// Example usage in an rnode package:
+//
+//	func TestTypeTraitCoverage(t *testing.T) {
+//		traitcheck.Coverage[compute.HealthCheck, alpha.HealthCheck, beta.HealthCheck](t, &typeTrait{})
+//	}
+//
+// Resources that do not classify every field use Drift instead, which
+// compares the fields without a trait against a baseline file. The baselines
+// can be regenerated by running the tests with -traitcheck.update (only
+// packages that import traitcheck define the flag):
+//
+//	$ make traits
+package traitcheck
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/api"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"github.com/google/go-cmp/cmp"
+)
+
+var update = flag.Bool("traitcheck.update", false, "Update the Drift baseline files instead of comparing against them")
+
+// Coverage fails the test for each field of the resource that does not have
+// a trait (see api.FieldTraits.Unclassified) in the FieldTraits for the
+// corresponding version. Fields that are under one of the ignore Paths are
+// not reported.
+func Coverage[GA any, Alpha any, Beta any](t *testing.T, tt api.TypeTrait[GA, Alpha, Beta], ignore ...api.Path) {
+	t.Helper()
+	for _, tc := range []struct {
+		ver meta.Version
+		typ reflect.Type
+	}{
+		{meta.VersionGA, reflect.TypeOf((*GA)(nil))},
+		{meta.VersionAlpha, reflect.TypeOf((*Alpha)(nil))},
+		{meta.VersionBeta, reflect.TypeOf((*Beta)(nil))},
+	} {
+		traits := tt.FieldTraits(tc.ver)
+		if err := traits.CheckSchema(tc.typ); err != nil {
+			t.Errorf("%s: %v", tc.ver, err)
+		}
+	paths:
+		for _, p := range traits.Unclassified(tc.typ) {
+			for _, ip := range ignore {
+				if p.HasPrefix(ip) {
+					continue paths
+				}
+			}
+			t.Errorf("%s: %s field %s has no trait", tc.ver, tc.typ.Elem().Name(), p)
+		}
+	}
+}
+
+// Drift fails the test if the fields of the resource without a trait (see
+// api.FieldTraits.Unclassified) differ from the baseline file or if a trait
+// refers to a field that is not in the API type. This detects the fields
+// added to (or removed from) the API types by an update of the
+// google.golang.org/api module: a new field that is not classified may be
+// diffed incorrectly (e.g. an OutputOnly field causing an update on each
+// sync).
+//
+// The baseline has one "<version> <path>" line for each field without a
+// trait. Classify new fields in the FieldTraits of the resource before
+// regenerating the baseline with -traitcheck.update.
+func Drift[GA any, Alpha any, Beta any](t *testing.T, tt api.TypeTrait[GA, Alpha, Beta], baseline string) {
+	t.Helper()
+
+	var lines []string
+	for _, tc := range []struct {
+		ver meta.Version
+		typ reflect.Type
+	}{
+		{meta.VersionGA, reflect.TypeOf((*GA)(nil))},
+		{meta.VersionAlpha, reflect.TypeOf((*Alpha)(nil))},
+		{meta.VersionBeta, reflect.TypeOf((*Beta)(nil))},
+	} {
+		if tc.typ.Elem() == reflect.TypeOf(api.PlaceholderType{}) {
+			continue
+		}
+		traits := tt.FieldTraits(tc.ver)
+		if err := traits.CheckSchema(tc.typ); err != nil {
+			t.Errorf("%s: %v", tc.ver, err)
+		}
+		for _, p := range traits.Unclassified(tc.typ) {
+			lines = append(lines, fmt.Sprintf("%s %s", tc.ver, p))
+		}
+	}
+	sort.Strings(lines)
+	out := fmt.Sprintf("# Fields of %s without a trait. Generated by traitcheck.Drift.\n", typeName[GA]())
+	if len(lines) > 0 {
+		out += strings.Join(lines, "\n") + "\n"
+	}
+
+	if *update {
+		if err := os.MkdirAll(filepath.Dir(baseline), 0755); err != nil {
+			t.Fatalf("MkdirAll(%q) = %v", filepath.Dir(baseline), err)
+		}
+		if err := os.WriteFile(baseline, []byte(out), 0644); err != nil {
+			t.Fatalf("WriteFile(%q) = %v", baseline, err)
+		}
+		t.Logf("Updated baseline %q", baseline)
+		return
+	}
+
+	want, err := os.ReadFile(baseline)
+	if err != nil {
+		t.Fatalf("ReadFile(%q) = %v (run with -traitcheck.update to create it)", baseline, err)
+	}
+	if diff := cmp.Diff(string(want), out); diff != "" {
+		t.Errorf("Fields without a trait differ from baseline %q; classify the new fields and run with -traitcheck.update; diff -baseline,+got:\n%s", baseline, diff)
+	}
+}
+
+func typeName[T any]() string {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	return t.PkgPath() + "." + t.Name()
+}
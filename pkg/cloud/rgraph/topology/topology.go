@@ -0,0 +1,150 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package topology enumerates the zones available in a region. This is used
+// to fan out zonal resources (e.g. one NEG per zone, see zonalneg) and for
+// regional planning.
+//
+//	p := topology.NewCloud(gce)
+//	zones, err := p.Zones(ctx, "us-central1")
+package topology
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/filter"
+)
+
+const errPrefix = "Topology"
+
+// DefaultTTL is how long the zones are cached if TTLOption is not given.
+const DefaultTTL = 10 * time.Minute
+
+// Provider enumerates the zones in a region.
+type Provider interface {
+	// Zones returns the sorted names of the available zones in the region.
+	// Returns an error if the region has no zones.
+	Zones(ctx context.Context, region string) ([]string, error)
+}
+
+// Static is a Provider with a fixed map of region to zones. This is used
+// for testing.
+type Static map[string][]string
+
+var _ Provider = Static(nil)
+
+// Zones implements Provider.
+func (s Static) Zones(_ context.Context, region string) ([]string, error) {
+	zones, ok := s[region]
+	if !ok || len(zones) == 0 {
+		return nil, fmt.Errorf("%s: no zones for region %q", errPrefix, region)
+	}
+	ret := append([]string{}, zones...)
+	sort.Strings(ret)
+	return ret, nil
+}
+
+// Option for NewCloud().
+type Option func(c *Config)
+
+// TTLOption sets how long the zones listed from the Cloud are cached. A TTL
+// of 0 disables caching.
+func TTLOption(ttl time.Duration) Option {
+	return func(c *Config) { c.TTL = ttl }
+}
+
+// NowOption overrides the clock. This is used for testing.
+func NowOption(now func() time.Time) Option {
+	return func(c *Config) { c.Now = now }
+}
+
+// Config for the Cloud Provider.
+type Config struct {
+	// TTL of the cached zones.
+	TTL time.Duration
+	// Now returns the current time.
+	Now func() time.Time
+}
+
+// NewCloud returns a Provider that lists the zones using the compute
+// zones.list API. Only zones with status "UP" are returned. The list of
+// zones is shared by all regions and cached for the TTL.
+func NewCloud(c cloud.Cloud, opts ...Option) Provider {
+	config := Config{
+		TTL: DefaultTTL,
+		Now: time.Now,
+	}
+	for _, o := range opts {
+		o(&config)
+	}
+	return &cloudProvider{cloud: c, config: config}
+}
+
+type cloudProvider struct {
+	cloud  cloud.Cloud
+	config Config
+
+	lock sync.Mutex
+	// byRegion is the cached zones, valid until expiry.
+	byRegion map[string][]string
+	expiry   time.Time
+}
+
+// Zones implements Provider.
+func (p *cloudProvider) Zones(ctx context.Context, region string) ([]string, error) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	if p.byRegion == nil || !p.config.Now().Before(p.expiry) {
+		if err := p.refresh(ctx); err != nil {
+			return nil, err
+		}
+	}
+	zones := p.byRegion[region]
+	if len(zones) == 0 {
+		return nil, fmt.Errorf("%s: no zones for region %q", errPrefix, region)
+	}
+	return append([]string{}, zones...), nil
+}
+
+// refresh the cached zones. p.lock must be held.
+func (p *cloudProvider) refresh(ctx context.Context) error {
+	zones, err := p.cloud.Zones().List(ctx, filter.None)
+	if err != nil {
+		return fmt.Errorf("%s: list zones: %w", errPrefix, err)
+	}
+	byRegion := map[string][]string{}
+	for _, z := range zones {
+		if z.Status != "UP" {
+			continue
+		}
+		// Region is a URL to the region resource.
+		region := path.Base(z.Region)
+		byRegion[region] = append(byRegion[region], z.Name)
+	}
+	for _, l := range byRegion {
+		sort.Strings(l)
+	}
+	p.byRegion = byRegion
+	p.expiry = p.config.Now().Add(p.config.TTL)
+	return nil
+}
@@ -0,0 +1,101 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package topology
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/filter"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/api/compute/v1"
+)
+
+func TestStatic(t *testing.T) {
+	p := Static{"us-central1": {"us-central1-c", "us-central1-a"}}
+	zones, err := p.Zones(context.Background(), "us-central1")
+	if err != nil {
+		t.Fatalf("Zones() = %v", err)
+	}
+	if diff := cmp.Diff(zones, []string{"us-central1-a", "us-central1-c"}); diff != "" {
+		t.Errorf("Zones(): diff -got,+want: %s", diff)
+	}
+	if _, err := p.Zones(context.Background(), "europe-west1"); err == nil {
+		t.Error("Zones(europe-west1) = nil, want error")
+	}
+}
+
+func TestCloud(t *testing.T) {
+	ctx := context.Background()
+	mockCloud := cloud.NewMockGCE(&cloud.SingleProjectRouter{ID: "proj"})
+	mockZones := mockCloud.Zones().(*cloud.MockZones)
+	addZone := func(name, region, status string) {
+		mockZones.Objects[*meta.GlobalKey(name)] = mockZones.Obj(&compute.Zone{
+			Name:   name,
+			Region: "https://www.googleapis.com/compute/v1/projects/proj/regions/" + region,
+			Status: status,
+		})
+	}
+	addZone("us-central1-b", "us-central1", "UP")
+	addZone("us-central1-a", "us-central1", "UP")
+	addZone("us-central1-f", "us-central1", "DOWN")
+	addZone("europe-west1-b", "europe-west1", "UP")
+
+	listCalls := 0
+	mockZones.ListHook = func(context.Context, *filter.F, *cloud.MockZones, ...cloud.Option) (bool, []*compute.Zone, error) {
+		listCalls++
+		return false, nil, nil
+	}
+	now := time.Unix(1000, 0)
+	p := NewCloud(mockCloud, TTLOption(time.Minute), NowOption(func() time.Time { return now }))
+
+	zones, err := p.Zones(ctx, "us-central1")
+	if err != nil {
+		t.Fatalf("Zones() = %v", err)
+	}
+	if diff := cmp.Diff(zones, []string{"us-central1-a", "us-central1-b"}); diff != "" {
+		t.Errorf("Zones(): diff -got,+want: %s", diff)
+	}
+	if _, err := p.Zones(ctx, "europe-west1"); err != nil {
+		t.Errorf("Zones(europe-west1) = %v, want nil", err)
+	}
+	if _, err := p.Zones(ctx, "asia-east1"); err == nil {
+		t.Error("Zones(asia-east1) = nil, want error")
+	}
+	if listCalls != 1 {
+		t.Errorf("listCalls = %d, want 1 (cached)", listCalls)
+	}
+
+	now = now.Add(2 * time.Minute)
+	if _, err := p.Zones(ctx, "us-central1"); err != nil {
+		t.Errorf("Zones() = %v", err)
+	}
+	if listCalls != 2 {
+		t.Errorf("listCalls = %d, want 2 (expired)", listCalls)
+	}
+
+	errList := fmt.Errorf("injected")
+	mockZones.ListError = &errList
+	now = now.Add(2 * time.Minute)
+	if _, err := p.Zones(ctx, "us-central1"); err == nil {
+		t.Error("Zones() = nil, want error")
+	}
+}
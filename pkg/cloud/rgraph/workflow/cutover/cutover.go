@@ -0,0 +1,253 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package cutover implements a blue/green cutover of traffic between two
+// stacks of resources (e.g. BackendServices and NEGs) using route weights:
+//
+//  1. Create the new (green) stack alongside the old (blue) stack with all
+//     traffic going to blue.
+//  2. Shift the traffic to green in steps, waiting and verifying the health
+//     of the stacks between each step.
+//  3. Delete the blue stack.
+//
+// Each step is synced to the Cloud with package ensure.
+package cutover
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/all"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/workflow/ensure"
+	"google.golang.org/api/networkservices/v1"
+	"k8s.io/klog/v2"
+)
+
+const errPrefix = "Cutover"
+
+// Spec describes the cutover.
+type Spec struct {
+	// Graph returns the wanted graph with greenPercent of the traffic sent
+	// to the green stack and the rest to the blue stack. The graph must
+	// contain the resources of both stacks. See TcpRouteWeights for
+	// setting the weights of a TcpRoute.
+	Graph func(greenPercent int) (*rgraph.Builder, error)
+	// Blue are the resources of the blue stack. These are deleted after
+	// all of the traffic has been shifted to green.
+	Blue []*cloud.ResourceID
+	// Steps are the percentages of traffic sent to green, in increasing
+	// order. The last step must be 100, e.g. [10, 50, 100].
+	Steps []int
+	// Interval to wait after each step before calling Verify.
+	Interval time.Duration
+	// Verify is called after each step. Returning an error stops the
+	// cutover. nil skips verification.
+	Verify func(ctx context.Context, greenPercent int) error
+}
+
+func (s *Spec) validate() error {
+	if s.Graph == nil {
+		return fmt.Errorf("%s: Graph must be set", errPrefix)
+	}
+	if len(s.Steps) == 0 {
+		return fmt.Errorf("%s: Steps must not be empty", errPrefix)
+	}
+	prev := 0
+	for _, p := range s.Steps {
+		if p <= prev || p > 100 {
+			return fmt.Errorf("%s: invalid Steps %v (must be increasing and in (0, 100])", errPrefix, s.Steps)
+		}
+		prev = p
+	}
+	if prev != 100 {
+		return fmt.Errorf("%s: invalid Steps %v (last step must be 100)", errPrefix, s.Steps)
+	}
+	if s.Interval < 0 {
+		return fmt.Errorf("%s: invalid Interval %v", errPrefix, s.Interval)
+	}
+	return nil
+}
+
+// Option for Do().
+type Option func(c *Config)
+
+// EnsureOptions are passed to ensure.Do() for each step.
+func EnsureOptions(opts ...ensure.Option) Option {
+	return func(c *Config) { c.EnsureOptions = append(c.EnsureOptions, opts...) }
+}
+
+// RollbackOption sends all of the traffic back to the blue stack if a step
+// fails. The green stack is not deleted.
+func RollbackOption() Option {
+	return func(c *Config) { c.Rollback = true }
+}
+
+// Config for Do().
+type Config struct {
+	// EnsureOptions for each step.
+	EnsureOptions []ensure.Option
+	// Rollback to blue on failure.
+	Rollback bool
+}
+
+// StepResult is the result of syncing one step of the cutover.
+type StepResult struct {
+	// GreenPercent of the traffic for the step.
+	GreenPercent int
+	// Ensure is the result of syncing the step.
+	Ensure *ensure.Result
+}
+
+// Result of Do().
+type Result struct {
+	// Steps that were synced, in order. The first step creates the green
+	// stack (GreenPercent = 0).
+	Steps []StepResult
+	// Teardown is the result of deleting the blue stack. This is nil if
+	// the cutover did not complete.
+	Teardown *ensure.Result
+	// Rollback is the result of the rollback to blue. This is nil if there
+	// was no rollback.
+	Rollback *ensure.Result
+}
+
+// Do runs the cutover in spec. The Result is returned along with any error
+// to allow the caller to inspect partial progress.
+func Do(ctx context.Context, cl cloud.Cloud, spec *Spec, opts ...Option) (*Result, error) {
+	if err := spec.validate(); err != nil {
+		return nil, err
+	}
+	c := &Config{}
+	for _, o := range opts {
+		o(c)
+	}
+	logger := klog.FromContext(ctx).WithName("Cutover")
+	result := &Result{}
+
+	// Create the green stack with all traffic going to blue.
+	if err := c.step(ctx, cl, spec, result, 0); err != nil {
+		return result, err
+	}
+	for _, p := range spec.Steps {
+		logger.V(2).Info("Shift traffic", "greenPercent", p)
+		err := c.step(ctx, cl, spec, result, p)
+		if err == nil {
+			err = cloud.SleepWithContext(ctx, spec.Interval)
+		}
+		if err == nil && spec.Verify != nil {
+			if vErr := spec.Verify(ctx, p); vErr != nil {
+				err = fmt.Errorf("%s: Verify(%d%%): %w", errPrefix, p, vErr)
+			}
+		}
+		if err != nil {
+			return result, c.rollback(ctx, cl, spec, result, err)
+		}
+	}
+
+	logger.V(2).Info("Delete blue stack", "resources", spec.Blue)
+	b, err := spec.Graph(100)
+	if err != nil {
+		return result, fmt.Errorf("%s: Graph(100): %w", errPrefix, err)
+	}
+	if err := deleteAll(b, spec.Blue); err != nil {
+		return result, err
+	}
+	result.Teardown, err = ensure.Do(ctx, cl, b, c.EnsureOptions...)
+	if err != nil {
+		return result, fmt.Errorf("%s: teardown: %w", errPrefix, err)
+	}
+	return result, nil
+}
+
+// step syncs the graph for greenPercent.
+func (c *Config) step(ctx context.Context, cl cloud.Cloud, spec *Spec, result *Result, greenPercent int) error {
+	b, err := spec.Graph(greenPercent)
+	if err != nil {
+		return fmt.Errorf("%s: Graph(%d): %w", errPrefix, greenPercent, err)
+	}
+	sr := StepResult{GreenPercent: greenPercent}
+	sr.Ensure, err = ensure.Do(ctx, cl, b, c.EnsureOptions...)
+	result.Steps = append(result.Steps, sr)
+	if err != nil {
+		return fmt.Errorf("%s: step %d%%: %w", errPrefix, greenPercent, err)
+	}
+	return nil
+}
+
+// rollback sends all of the traffic back to blue if configured. Returns err
+// annotated with the result of the rollback.
+func (c *Config) rollback(ctx context.Context, cl cloud.Cloud, spec *Spec, result *Result, err error) error {
+	if !c.Rollback {
+		return err
+	}
+	b, gErr := spec.Graph(0)
+	if gErr != nil {
+		return fmt.Errorf("%w (rollback: Graph(0): %v)", err, gErr)
+	}
+	var rErr error
+	result.Rollback, rErr = ensure.Do(ctx, cl, b, c.EnsureOptions...)
+	if rErr != nil {
+		return fmt.Errorf("%w (rollback: %v)", err, rErr)
+	}
+	return err
+}
+
+// deleteAll sets the Nodes for ids in b to be deleted.
+func deleteAll(b *rgraph.Builder, ids []*cloud.ResourceID) error {
+	for _, id := range ids {
+		nb, err := all.NewBuilderByID(id)
+		if err != nil {
+			return fmt.Errorf("%s: %w", errPrefix, err)
+		}
+		nb.SetOwnership(rnode.OwnershipManaged)
+		nb.SetState(rnode.NodeDoesNotExist)
+		b.Add(nb)
+	}
+	return nil
+}
+
+// TcpRouteWeights sets the weights of the destinations in route for a
+// cutover from the blue to the green service: greenPercent for green and
+// 100-greenPercent for blue. blue and green are the ServiceNames of the
+// destinations. Destinations with a weight of 0 are removed from the rule as
+// the API treats an unset weight as an even split.
+func TcpRouteWeights(route *networkservices.TcpRoute, blue, green string, greenPercent int) {
+	for _, rule := range route.Rules {
+		if rule == nil || rule.Action == nil {
+			continue
+		}
+		var dests []*networkservices.TcpRouteRouteDestination
+		for _, d := range rule.Action.Destinations {
+			switch d.ServiceName {
+			case blue:
+				d.Weight = int64(100 - greenPercent)
+			case green:
+				d.Weight = int64(greenPercent)
+			default:
+				dests = append(dests, d)
+				continue
+			}
+			if d.Weight > 0 {
+				dests = append(dests, d)
+			}
+		}
+		rule.Action.Destinations = dests
+	}
+}
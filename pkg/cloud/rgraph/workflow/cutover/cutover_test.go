@@ -0,0 +1,303 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cutover
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/backendservice"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/testing/ez"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/workflow/ensure"
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/api/networkservices/v1"
+)
+
+func newMock() *cloud.MockGCE {
+	m := cloud.NewMockGCE(&cloud.SingleProjectRouter{ID: "proj"})
+	m.MockTcpRoutes.PatchHook = func(_ context.Context, key *meta.Key, obj *networkservices.TcpRoute, m *cloud.MockTcpRoutes, _ ...cloud.Option) error {
+		m.Lock.Lock()
+		defer m.Lock.Unlock()
+		m.Objects[*key] = m.Obj(obj)
+		return nil
+	}
+	return m
+}
+
+func spec(verify func(context.Context, int) error) *Spec {
+	return &Spec{
+		Graph: func(greenPercent int) (*rgraph.Builder, error) {
+			g := &ez.Graph{
+				Project: "proj",
+				Nodes: []ez.Node{
+					{Name: "hc"},
+					{Name: "bs-blue", Refs: []ez.Ref{{Field: "Healthchecks", To: "hc"}}},
+					{Name: "bs-green", Refs: []ez.Ref{{Field: "Healthchecks", To: "hc"}}},
+					{
+						Name: "tcp-route",
+						Refs: []ez.Ref{
+							{Field: "Rules.Action.Destinations.ServiceName", To: "bs-blue"},
+							{Field: "Rules.Action.Destinations.ServiceName", To: "bs-green"},
+						},
+						SetupFunc: func(x *networkservices.TcpRoute) {
+							dests := x.Rules[0].Action.Destinations
+							TcpRouteWeights(x, dests[0].ServiceName, dests[1].ServiceName, greenPercent)
+						},
+					},
+				},
+			}
+			return g.Builder(), nil
+		},
+		Blue:   []*cloud.ResourceID{backendservice.ID("proj", meta.GlobalKey("bs-blue"))},
+		Steps:  []int{50, 100},
+		Verify: verify,
+	}
+}
+
+// weights returns the weights of the TcpRoute destinations by service name.
+func weights(t *testing.T, m *cloud.MockGCE) map[string]int64 {
+	t.Helper()
+	route, err := m.TcpRoutes().Get(context.Background(), meta.GlobalKey("tcp-route"))
+	if err != nil {
+		t.Fatalf("TcpRoutes().Get() = %v, want nil", err)
+	}
+	ret := map[string]int64{}
+	for _, d := range route.Rules[0].Action.Destinations {
+		id, err := cloud.ParseResourceURL(d.ServiceName)
+		if err != nil {
+			t.Fatalf("ParseResourceURL(%q) = %v", d.ServiceName, err)
+		}
+		ret[id.Key.Name] = d.Weight
+	}
+	return ret
+}
+
+func exists(m *cloud.MockGCE, name string) bool {
+	_, err := m.BackendServices().Get(context.Background(), meta.GlobalKey(name))
+	return err == nil
+}
+
+func TestCutover(t *testing.T) {
+	ctx := context.Background()
+	m := newMock()
+
+	var verified []int
+	var seen []map[string]int64
+	res, err := Do(ctx, m, spec(func(_ context.Context, p int) error {
+		verified = append(verified, p)
+		seen = append(seen, weights(t, m))
+		return nil
+	}), EnsureOptions(ensure.SerialOption()))
+	if err != nil {
+		t.Fatalf("Do() = %v, want nil", err)
+	}
+
+	if diff := cmp.Diff(verified, []int{50, 100}); diff != "" {
+		t.Errorf("verified: diff -got,+want: %s", diff)
+	}
+	wantSeen := []map[string]int64{
+		{"bs-blue": 50, "bs-green": 50},
+		{"bs-green": 100},
+	}
+	if diff := cmp.Diff(seen, wantSeen); diff != "" {
+		t.Errorf("weights: diff -got,+want: %s", diff)
+	}
+	var steps []int
+	for _, s := range res.Steps {
+		steps = append(steps, s.GreenPercent)
+	}
+	if diff := cmp.Diff(steps, []int{0, 50, 100}); diff != "" {
+		t.Errorf("Steps: diff -got,+want: %s", diff)
+	}
+	if res.Teardown == nil {
+		t.Error("Teardown = nil, want non-nil")
+	}
+	if exists(m, "bs-blue") {
+		t.Error("bs-blue exists, want deleted")
+	}
+	if !exists(m, "bs-green") {
+		t.Error("bs-green does not exist, want exists")
+	}
+}
+
+func TestCutoverRollback(t *testing.T) {
+	ctx := context.Background()
+	m := newMock()
+
+	verifyErr := errors.New("unhealthy")
+	res, err := Do(ctx, m, spec(func(context.Context, int) error { return verifyErr }),
+		EnsureOptions(ensure.SerialOption()), RollbackOption())
+	if !errors.Is(err, verifyErr) {
+		t.Fatalf("Do() = %v, want %v", err, verifyErr)
+	}
+	if res.Rollback == nil {
+		t.Error("Rollback = nil, want non-nil")
+	}
+	if res.Teardown != nil {
+		t.Errorf("Teardown = %v, want nil", res.Teardown)
+	}
+	if diff := cmp.Diff(weights(t, m), map[string]int64{"bs-blue": 100}); diff != "" {
+		t.Errorf("weights: diff -got,+want: %s", diff)
+	}
+	if !exists(m, "bs-blue") || !exists(m, "bs-green") {
+		t.Error("stacks were deleted, want both to exist")
+	}
+}
+
+func TestSpecValidate(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		steps    []int
+		interval time.Duration
+		noGraph  bool
+		wantErr  bool
+	}{
+		{name: "ok", steps: []int{10, 50, 100}},
+		{name: "single step", steps: []int{100}},
+		{name: "empty", wantErr: true},
+		{name: "not increasing", steps: []int{50, 10, 100}, wantErr: true},
+		{name: "last is not 100", steps: []int{10, 50}, wantErr: true},
+		{name: "over 100", steps: []int{50, 150}, wantErr: true},
+		{name: "negative interval", steps: []int{100}, interval: -time.Second, wantErr: true},
+		{name: "no graph", steps: []int{100}, noGraph: true, wantErr: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			s := spec(nil)
+			s.Steps = tc.steps
+			s.Interval = tc.interval
+			if tc.noGraph {
+				s.Graph = nil
+			}
+			if err := s.validate(); (err != nil) != tc.wantErr {
+				t.Errorf("validate() = %v, want error = %t", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestCutoverGraphError(t *testing.T) {
+	graphErr := errors.New("graph")
+
+	for _, tc := range []struct {
+		name string
+		// failAt is the greenPercent for which Graph() returns an error.
+		failAt       map[int]bool
+		opts         []Option
+		wantSteps    int
+		wantRollback bool
+	}{
+		{name: "create green", failAt: map[int]bool{0: true}},
+		{name: "shift", failAt: map[int]bool{50: true}, wantSteps: 1},
+		{
+			name:         "shift with rollback",
+			failAt:       map[int]bool{50: true},
+			opts:         []Option{RollbackOption()},
+			wantSteps:    1,
+			wantRollback: true,
+		},
+		{
+			name:      "shift with failed rollback",
+			failAt:    map[int]bool{50: true, 0: false},
+			opts:      []Option{RollbackOption()},
+			wantSteps: 1,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			m := newMock()
+			s := spec(nil)
+			graph := s.Graph
+			calls := map[int]int{}
+			s.Graph = func(p int) (*rgraph.Builder, error) {
+				calls[p]++
+				fail, ok := tc.failAt[p]
+				// A false entry fails the second call, i.e. the rollback.
+				if fail || (ok && calls[p] > 1) {
+					return nil, fmt.Errorf("%d: %w", p, graphErr)
+				}
+				return graph(p)
+			}
+			opts := append([]Option{EnsureOptions(ensure.SerialOption())}, tc.opts...)
+			res, err := Do(context.Background(), m, s, opts...)
+			if !errors.Is(err, graphErr) {
+				t.Fatalf("Do() = %v, want %v", err, graphErr)
+			}
+			if res == nil {
+				t.Fatal("Do() = nil Result, want non-nil")
+			}
+			if len(res.Steps) != tc.wantSteps {
+				t.Errorf("len(Steps) = %d, want %d", len(res.Steps), tc.wantSteps)
+			}
+			if gotRollback := res.Rollback != nil; gotRollback != tc.wantRollback {
+				t.Errorf("Rollback = %v, want non-nil = %t", res.Rollback, tc.wantRollback)
+			}
+		})
+	}
+}
+
+func TestCutoverTeardownGraphError(t *testing.T) {
+	graphErr := errors.New("graph")
+	s := spec(nil)
+	graph := s.Graph
+	shifted := false
+	s.Graph = func(p int) (*rgraph.Builder, error) {
+		if p == 100 && shifted {
+			return nil, graphErr
+		}
+		shifted = shifted || p == 100
+		return graph(p)
+	}
+	res, err := Do(context.Background(), newMock(), s, EnsureOptions(ensure.SerialOption()))
+	if !errors.Is(err, graphErr) {
+		t.Fatalf("Do() = %v, want %v", err, graphErr)
+	}
+	if res.Teardown != nil {
+		t.Errorf("Teardown = %v, want nil", res.Teardown)
+	}
+}
+
+func TestTcpRouteWeights(t *testing.T) {
+	route := &networkservices.TcpRoute{
+		Rules: []*networkservices.TcpRouteRouteRule{
+			nil,
+			{},
+			{
+				Action: &networkservices.TcpRouteRouteAction{
+					Destinations: []*networkservices.TcpRouteRouteDestination{
+						{ServiceName: "blue"},
+						{ServiceName: "green"},
+						{ServiceName: "other", Weight: 7},
+					},
+				},
+			},
+		},
+	}
+	TcpRouteWeights(route, "blue", "green", 0)
+
+	got := map[string]int64{}
+	for _, d := range route.Rules[2].Action.Destinations {
+		got[d.ServiceName] = d.Weight
+	}
+	if diff := cmp.Diff(got, map[string]int64{"blue": 100, "other": 7}); diff != "" {
+		t.Errorf("weights: diff -got,+want: %s", diff)
+	}
+}
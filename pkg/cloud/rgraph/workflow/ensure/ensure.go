@@ -0,0 +1,335 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package ensure syncs the Cloud to a wanted graph in a single call: build
+// the graph, fetch the current state from the Cloud, plan and execute the
+// Actions. This is the common path for controllers; use package plan and
+// package exec directly for more control.
+//
+// Ensure is idempotent: calling it again with the same graph after a
+// successful run will not change any resources.
+//
+// This is not in package rgraph as package plan depends on rgraph.
+package ensure
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/exec"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/workflow/lease"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/workflow/plan"
+	"google.golang.org/api/googleapi"
+	"k8s.io/klog/v2"
+)
+
+const errPrefix = "Ensure"
+
+// Option for Do().
+type Option func(c *Config)
+
+// SerialOption uses the serial executor instead of the default parallel
+// executor.
+func SerialOption() Option {
+	return func(c *Config) { c.Serial = true }
+}
+
+// RetryOption sets the maximum number of attempts for each Action and the
+// initial backoff between attempts. The backoff doubles after each attempt.
+// maxAttempts = 1 disables retries.
+func RetryOption(maxAttempts int, backoff time.Duration) Option {
+	return func(c *Config) {
+		c.MaxAttempts = maxAttempts
+		c.Backoff = backoff
+	}
+}
+
+// IsRetriableOption overrides the default check for retriable errors.
+func IsRetriableOption(f func(error) bool) Option {
+	return func(c *Config) { c.IsRetriable = f }
+}
+
+// ExecutorOptions are passed to the executor.
+func ExecutorOptions(opts ...exec.Option) Option {
+	return func(c *Config) { c.ExecutorOptions = append(c.ExecutorOptions, opts...) }
+}
+
+// PlanOptions are passed to the planner.
+func PlanOptions(opts ...plan.Option) Option {
+	return func(c *Config) { c.PlanOptions = append(c.PlanOptions, opts...) }
+}
+
+// CoordinatorOption serializes Actions on the same resource with other
+// callers using the same Coordinator. Use this when multiple reconcile loops in
+// the same process may touch shared resources.
+func CoordinatorOption(co *exec.Coordinator) Option {
+	return func(c *Config) { c.Coordinator = co }
+}
+
+// LeaseOption acquires the lease before planning and releases it when Do()
+// returns. Do() fails with a lease.HeldError if another holder has the
+// lease. The lease labels are added to the root resource in the Builder so
+// the plan preserves them. Use this when multiple replicas of a controller
+// may sync the same graph.
+//
+// The lease is not renewed while the Actions run. Each Action fails with a
+// lease.ExpiredError instead of running once the lease has expired, so use a
+// lease duration that is longer than the expected time to execute the plan.
+func LeaseOption(l *lease.Lease) Option {
+	return func(c *Config) { c.Lease = l }
+}
+
+// StreamOption starts executing the Actions for each Node as soon as the
+// Node and its dependencies are planned, while the rest of the graph is
+// planned (see plan.Stream). This reduces the latency for large graphs.
+// SerialOption is ignored.
+func StreamOption() Option {
+	return func(c *Config) { c.Stream = true }
+}
+
+// Config for Do().
+type Config struct {
+	// Serial uses the serial executor.
+	Serial bool
+	// MaxAttempts for each Action.
+	MaxAttempts int
+	// Backoff before the first retry.
+	Backoff time.Duration
+	// IsRetriable returns true if the Action error can be retried.
+	IsRetriable func(error) bool
+	// ExecutorOptions are passed to the executor.
+	ExecutorOptions []exec.Option
+	// PlanOptions are passed to the planner.
+	PlanOptions []plan.Option
+	// Coordinator, if set, wraps the Actions.
+	Coordinator *exec.Coordinator
+	// Lease, if set, is held while syncing.
+	Lease *lease.Lease
+	// Stream plans and executes the graph in a pipeline.
+	Stream bool
+}
+
+func makeConfig(opts ...Option) (*Config, error) {
+	c := &Config{
+		MaxAttempts: 3,
+		Backoff:     time.Second,
+		IsRetriable: IsRetriable,
+	}
+	for _, o := range opts {
+		o(c)
+	}
+	if c.MaxAttempts < 1 {
+		return nil, fmt.Errorf("%s: invalid MaxAttempts %d", errPrefix, c.MaxAttempts)
+	}
+	if c.Backoff < 0 {
+		return nil, fmt.Errorf("%s: invalid Backoff %v", errPrefix, c.Backoff)
+	}
+	return c, nil
+}
+
+// IsRetriable is the default check for retriable errors: rate limiting
+// (429), server errors (500, 502, 503, 504) and calls rejected by
+// cloud.DeadlineRateLimiter. The latter are not retried by Do() as the
+// deadline of the context only gets shorter; the caller should call Do()
+// again with a new context, e.g. by requeueing the request.
+func IsRetriable(err error) bool {
+	if cloud.IsDeadlineTooShort(err) {
+		return true
+	}
+	var gerr *googleapi.Error
+	if !errors.As(err, &gerr) {
+		return false
+	}
+	switch gerr.Code {
+	case http.StatusTooManyRequests,
+		http.StatusInternalServerError,
+		http.StatusBadGateway,
+		http.StatusServiceUnavailable,
+		http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// Result of Do().
+type Result struct {
+	// Plan is the result of planning. This is nil if planning failed.
+	Plan *plan.Result
+	// Exec is the result of executing the Actions. This is nil if the
+	// Actions were not executed.
+	Exec *exec.Result
+}
+
+// Do syncs the Cloud to the graph in b. The Result is returned along with
+// any error to allow the caller to inspect partial progress.
+func Do(ctx context.Context, cl cloud.Cloud, b *rgraph.Builder, opts ...Option) (*Result, error) {
+	c, err := makeConfig(opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	logger := klog.FromContext(ctx).WithName("Ensure")
+
+	if c.Lease != nil {
+		if err := c.Lease.Acquire(ctx, cl); err != nil {
+			return nil, fmt.Errorf("%s: %w", errPrefix, err)
+		}
+		defer func() {
+			if err := c.Lease.Release(ctx, cl); err != nil {
+				logger.Error(err, "Release lease", "id", c.Lease.ID)
+			}
+		}()
+		// Apply the lease to a copy so the lease labels do not leak into
+		// the caller's Builder.
+		b = b.Clone()
+		if err := c.Lease.Apply(b); err != nil {
+			return nil, fmt.Errorf("%s: %w", errPrefix, err)
+		}
+	}
+
+	want, err := b.Build()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", errPrefix, err)
+	}
+
+	if c.Stream {
+		return c.doStream(ctx, cl, want, logger)
+	}
+
+	result := &Result{}
+	result.Plan, err = plan.Do(ctx, cl, want, c.PlanOptions...)
+	if err != nil {
+		return result, fmt.Errorf("%s: %w", errPrefix, err)
+	}
+
+	acts := c.wrap(logger, result.Plan.Actions)
+
+	var ex exec.Executor
+	if c.Serial {
+		ex, err = exec.NewSerialExecutor(cl, acts, c.ExecutorOptions...)
+	} else {
+		ex, err = exec.NewParallelExecutor(cl, acts, c.ExecutorOptions...)
+	}
+	if err != nil {
+		return result, fmt.Errorf("%s: %w", errPrefix, err)
+	}
+
+	result.Exec, err = ex.Run(ctx)
+	if err != nil {
+		return result, fmt.Errorf("%s: %w", errPrefix, err)
+	}
+	return result, nil
+}
+
+// doStream plans and executes the graph with plan.Stream().
+func (c *Config) doStream(ctx context.Context, cl cloud.Cloud, want *rgraph.Graph, logger klog.Logger) (*Result, error) {
+	ex, err := exec.NewStreamingExecutor(cl, c.ExecutorOptions...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", errPrefix, err)
+	}
+
+	result := &Result{}
+	var (
+		planErr error
+		wg      sync.WaitGroup
+	)
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		sex := &wrappedStreamingExecutor{StreamingExecutor: ex, wrap: func(acts []exec.Action) []exec.Action {
+			return c.wrap(logger, acts)
+		}}
+		result.Plan, planErr = plan.Stream(ctx, cl, want, sex, c.PlanOptions...)
+	}()
+	result.Exec, err = ex.Run(ctx)
+	wg.Wait()
+
+	if planErr != nil {
+		return result, fmt.Errorf("%s: %w", errPrefix, planErr)
+	}
+	if err != nil {
+		return result, fmt.Errorf("%s: %w", errPrefix, err)
+	}
+	return result, nil
+}
+
+// wrappedStreamingExecutor applies wrap to the Actions before adding them
+// to the executor.
+type wrappedStreamingExecutor struct {
+	exec.StreamingExecutor
+	wrap func([]exec.Action) []exec.Action
+}
+
+func (ex *wrappedStreamingExecutor) Add(actions ...exec.Action) error {
+	return ex.StreamingExecutor.Add(ex.wrap(actions)...)
+}
+
+// wrap the planned Actions with the lease check, retries and the
+// Coordinator.
+func (c *Config) wrap(logger klog.Logger, actions []exec.Action) []exec.Action {
+	var acts []exec.Action
+	for _, a := range actions {
+		if c.Lease != nil {
+			a = &leaseAction{Action: a, lease: c.Lease}
+		}
+		acts = append(acts, c.withRetry(logger, a))
+	}
+	if c.Coordinator != nil {
+		acts = c.Coordinator.Wrap(acts)
+	}
+	return acts
+}
+
+// leaseAction fails instead of running the Action if the lease has expired.
+type leaseAction struct {
+	exec.Action
+	lease *lease.Lease
+}
+
+func (a *leaseAction) Run(ctx context.Context, cl cloud.Cloud) (exec.EventList, error) {
+	if err := a.lease.Check(); err != nil {
+		return nil, err
+	}
+	return a.Action.Run(ctx, cl)
+}
+
+func (c *Config) withRetry(logger klog.Logger, a exec.Action) exec.Action {
+	if c.MaxAttempts <= 1 {
+		return a
+	}
+	attempts := 0
+	b := cloud.Backoff{Initial: c.Backoff, Multiplier: 2}
+	return exec.NewRetriableAction(a, func(err error) (bool, time.Duration) {
+		attempts++
+		if attempts >= c.MaxAttempts || !c.IsRetriable(err) {
+			return false, 0
+		}
+		// Retrying with the same context will fail the deadline check
+		// again.
+		if cloud.IsDeadlineTooShort(err) {
+			return false, 0
+		}
+		backoff := b.Duration(attempts)
+		logger.V(2).Info("Retry action", "action", a.Metadata().Name, "backoff", backoff, "attempt", attempts, "err", err)
+		return true, backoff
+	})
+}
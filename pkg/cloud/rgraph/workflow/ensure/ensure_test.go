@@ -0,0 +1,321 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ensure
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/mock"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/exec"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/forwardingrule"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/testing/ez"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/workflow/lease"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/workflow/plan"
+	"google.golang.org/api/compute/v1"
+	"google.golang.org/api/googleapi"
+)
+
+func newMock() *cloud.MockGCE {
+	m := cloud.NewMockGCE(&cloud.SingleProjectRouter{ID: "proj"})
+	m.MockHealthChecks.UpdateHook = mock.UpdateHealthCheckHook
+	m.MockBackendServices.UpdateHook = mock.UpdateBackendServiceHook
+	return m
+}
+
+func graph() *ez.Graph {
+	return &ez.Graph{
+		Project: "proj",
+		Nodes: []ez.Node{
+			{Name: "hc"},
+			{Name: "bs", Refs: []ez.Ref{{Field: "Healthchecks", To: "hc"}}},
+		},
+	}
+}
+
+// changes returns the Actions that are not Exists events.
+func changes(r *exec.Result) []string {
+	var ret []string
+	for _, a := range r.Completed {
+		if name := a.Metadata().Name; !strings.HasPrefix(name, "EventAction") {
+			ret = append(ret, name)
+		}
+	}
+	return ret
+}
+
+func TestDo(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		opts []Option
+	}{
+		{name: "parallel"},
+		{name: "serial", opts: []Option{SerialOption()}},
+		{name: "coordinator", opts: []Option{CoordinatorOption(exec.NewCoordinator())}},
+		{name: "stream", opts: []Option{StreamOption()}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			m := newMock()
+
+			r, err := Do(ctx, m, graph().Builder(), tc.opts...)
+			if err != nil {
+				t.Fatalf("Do() = %v, want nil", err)
+			}
+			if got := changes(r.Exec); len(got) != 2 {
+				t.Errorf("changes = %v, want 2 create Actions", got)
+			}
+			if _, err := m.BackendServices().Get(ctx, meta.GlobalKey("bs")); err != nil {
+				t.Errorf("BackendServices().Get(bs) = %v, want nil", err)
+			}
+
+			// Ensure is idempotent.
+			r, err = Do(ctx, m, graph().Builder(), tc.opts...)
+			if err != nil {
+				t.Fatalf("Do() = %v, want nil", err)
+			}
+			if got := changes(r.Exec); len(got) != 0 {
+				t.Errorf("changes = %v, want none", got)
+			}
+		})
+	}
+}
+
+func TestDoRetry(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		failures int
+		err      error
+		opts     []Option
+		wantErr  bool
+	}{
+		{
+			name:     "retriable",
+			failures: 2,
+			err:      &googleapi.Error{Code: http.StatusServiceUnavailable},
+			opts:     []Option{RetryOption(3, 0)},
+		},
+		{
+			name:     "too many failures",
+			failures: 3,
+			err:      &googleapi.Error{Code: http.StatusServiceUnavailable},
+			opts:     []Option{RetryOption(3, 0)},
+			wantErr:  true,
+		},
+		{
+			name:     "not retriable",
+			failures: 1,
+			err:      &googleapi.Error{Code: http.StatusBadRequest},
+			opts:     []Option{RetryOption(3, 0)},
+			wantErr:  true,
+		},
+		{
+			name:     "custom IsRetriable",
+			failures: 1,
+			err:      errors.New("injected"),
+			opts:     []Option{RetryOption(3, 0), IsRetriableOption(func(error) bool { return true })},
+		},
+		{
+			name:     "deadline too short",
+			failures: 1,
+			err:      &cloud.DeadlineTooShortError{Key: &cloud.RateLimitKey{Operation: "Insert"}, Minimum: time.Minute},
+			opts:     []Option{RetryOption(3, 0)},
+			wantErr:  true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			m := newMock()
+			calls := 0
+			m.MockHealthChecks.InsertHook = func(context.Context, *meta.Key, *compute.HealthCheck, *cloud.MockHealthChecks, ...cloud.Option) (bool, error) {
+				calls++
+				if calls <= tc.failures {
+					return true, tc.err
+				}
+				return false, nil
+			}
+			_, err := Do(context.Background(), m, graph().Builder(), tc.opts...)
+			if gotErr := err != nil; gotErr != tc.wantErr {
+				t.Errorf("Do() = %v, want err=%t", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestDoInvalid(t *testing.T) {
+	for _, opts := range [][]Option{
+		{RetryOption(0, 0)},
+		{RetryOption(1, -1)},
+	} {
+		if _, err := Do(context.Background(), newMock(), graph().Builder(), opts...); err == nil {
+			t.Errorf("Do(%v) = nil, want error", opts)
+		}
+	}
+}
+
+func TestIsRetriable(t *testing.T) {
+	for _, tc := range []struct {
+		err  error
+		want bool
+	}{
+		{err: errors.New("x")},
+		{err: &googleapi.Error{Code: http.StatusNotFound}},
+		{err: &googleapi.Error{Code: http.StatusTooManyRequests}, want: true},
+		{err: fmt.Errorf("wrapped: %w", &googleapi.Error{Code: http.StatusInternalServerError}), want: true},
+		{err: fmt.Errorf("wrapped: %w", &cloud.DeadlineTooShortError{Key: &cloud.RateLimitKey{Operation: "Insert"}}), want: true},
+	} {
+		if got := IsRetriable(tc.err); got != tc.want {
+			t.Errorf("IsRetriable(%v) = %t, want %t", tc.err, got, tc.want)
+		}
+	}
+}
+
+func TestDoPlanOptions(t *testing.T) {
+	ctx := context.Background()
+	m := newMock()
+	m.HealthChecks().Insert(ctx, meta.GlobalKey("hc"), &compute.HealthCheck{Description: "external"})
+
+	g := &ez.Graph{
+		Project: "proj",
+		Nodes: []ez.Node{{Name: "hc", SetupFunc: func(x *compute.HealthCheck) {
+			x.Description = "want"
+		}}},
+	}
+	_, err := Do(ctx, m, g.Builder(), PlanOptions(plan.ConflictStrategyOption(rnode.ConflictFail)))
+	var conflictErr *plan.ConflictError
+	if !errors.As(err, &conflictErr) {
+		t.Errorf("Do() = %v, want plan.ConflictError", err)
+	}
+}
+
+func TestDoLease(t *testing.T) {
+	ctx := context.Background()
+	m := newMock()
+	m.MockGlobalForwardingRules.SetLabelsHook = mock.SetLabelsGlobalForwardingRuleHook
+
+	g := &ez.Graph{
+		Project: "proj",
+		Nodes: []ez.Node{{Name: "fr", SetupFunc: func(x *compute.ForwardingRule) {
+			x.Labels = map[string]string{"app": "x"}
+		}}},
+	}
+	frID := forwardingrule.ID("proj", meta.GlobalKey("fr"))
+	newLease := func(holder string) *lease.Lease {
+		l, err := lease.New(frID, holder)
+		if err != nil {
+			t.Fatalf("lease.New() = %v", err)
+		}
+		return l
+	}
+
+	// The ForwardingRule does not exist yet, so the lease is not held.
+	if _, err := Do(ctx, m, g.Builder(), LeaseOption(newLease("a"))); err != nil {
+		t.Fatalf("Do() = %v", err)
+	}
+
+	// The lease labels do not appear as a diff in the plan.
+	b := g.Builder()
+	r, err := Do(ctx, m, b, LeaseOption(newLease("a")))
+	if err != nil {
+		t.Fatalf("Do() = %v", err)
+	}
+	if got := changes(r.Exec); len(got) != 0 {
+		t.Errorf("changes = %v, want none", got)
+	}
+	// The caller's Builder is not modified.
+	if bfr, _ := b.Get(frID).Resource().(forwardingrule.ForwardingRule).ToGA(); len(bfr.Labels) != 1 {
+		t.Errorf("Builder Labels = %v, want no lease labels", bfr.Labels)
+	}
+	fr, err := m.GlobalForwardingRules().Get(ctx, frID.Key)
+	if err != nil {
+		t.Fatalf("Get() = %v", err)
+	}
+	if _, ok := fr.Labels[lease.HolderKey]; ok {
+		t.Errorf("Labels = %v, want lease released", fr.Labels)
+	}
+
+	// Held by another replica.
+	other := newLease("b")
+	if err := other.Acquire(ctx, m); err != nil {
+		t.Fatalf("Acquire() = %v", err)
+	}
+	var heldErr *lease.HeldError
+	if _, err := Do(ctx, m, g.Builder(), LeaseOption(newLease("a"))); !errors.As(err, &heldErr) {
+		t.Errorf("Do() = %v, want lease.HeldError", err)
+	}
+}
+
+func TestDoLeaseExpired(t *testing.T) {
+	ctx := context.Background()
+	m := newMock()
+	m.MockGlobalForwardingRules.SetLabelsHook = mock.SetLabelsGlobalForwardingRuleHook
+	frKey := meta.GlobalKey("fr")
+	if err := m.GlobalForwardingRules().Insert(ctx, frKey, &compute.ForwardingRule{Labels: map[string]string{"app": "x"}}); err != nil {
+		t.Fatalf("Insert() = %v", err)
+	}
+
+	// The lease expires after Acquire(), before the Actions run.
+	start := time.Unix(1000, 0)
+	var calls atomic.Int32
+	clock := func() time.Time {
+		if calls.Add(1) == 1 {
+			return start
+		}
+		return start.Add(2 * time.Minute)
+	}
+	l, err := lease.New(forwardingrule.ID("proj", frKey), "a", lease.NowOption(clock), lease.DurationOption(time.Minute))
+	if err != nil {
+		t.Fatalf("lease.New() = %v", err)
+	}
+
+	g := &ez.Graph{
+		Project: "proj",
+		Nodes: []ez.Node{
+			{Name: "fr", SetupFunc: func(x *compute.ForwardingRule) {
+				x.Labels = map[string]string{"app": "x"}
+			}},
+			{Name: "hc"},
+		},
+	}
+	r, err := Do(ctx, m, g.Builder(), LeaseOption(l))
+	if err == nil {
+		t.Fatalf("Do() = nil, want error")
+	}
+	if r == nil || r.Exec == nil {
+		t.Fatalf("Do() = %+v, want the exec Result", r)
+	}
+	var expiredErr *lease.ExpiredError
+	for _, ae := range r.Exec.Errors {
+		if errors.As(ae.Err, &expiredErr) {
+			break
+		}
+	}
+	if expiredErr == nil {
+		t.Errorf("Exec.Errors = %v, want lease.ExpiredError", r.Exec.Errors)
+	}
+	if _, err := m.HealthChecks().Get(ctx, meta.GlobalKey("hc")); err == nil {
+		t.Errorf("HealthCheck was created after the lease expired")
+	}
+}
@@ -0,0 +1,188 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package gc garbage collects orphaned resources (see package sweep) in two
+// phases. The first run that detects an orphan marks it with the current
+// time. A later run deletes the orphan only if the mark is older than the
+// grace period. This tolerates eventual consistency between the caller's view
+// of the graph (e.g. from informers) and the Cloud: a resource that is
+// briefly missing from the graph will not be deleted.
+//
+// Do removes the mark from a marked resource that has returned to the graph,
+// so the grace period starts again if the resource is later orphaned. The
+// mark is not removed by planning the graph as the Description metadata is
+// ignored in a diff.
+package gc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/exec"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/workflow/sweep"
+	"k8s.io/klog/v2"
+)
+
+const errPrefix = "GC"
+
+// DefaultGracePeriod is the grace period used if GracePeriodOption is not
+// given.
+const DefaultGracePeriod = time.Hour
+
+// Option for Do().
+type Option func(c *Config)
+
+// GracePeriodOption sets the minimum time between marking an orphan and
+// deleting it.
+func GracePeriodOption(d time.Duration) Option {
+	return func(c *Config) { c.GracePeriod = d }
+}
+
+// MarkerOption overrides the default CloudMarker.
+func MarkerOption(m Marker) Option {
+	return func(c *Config) { c.Marker = m }
+}
+
+// SweepOptions are passed to sweep.Do() to select the resources.
+// sweep.DeleteOption is ignored.
+func SweepOptions(opts ...sweep.Option) Option {
+	return func(c *Config) { c.SweepOptions = append(c.SweepOptions, opts...) }
+}
+
+// NowOption sets the clock (used for testing).
+func NowOption(now func() time.Time) Option {
+	return func(c *Config) { c.Now = now }
+}
+
+// Config for the garbage collection.
+type Config struct {
+	// GracePeriod between marking and deleting an orphan.
+	GracePeriod time.Duration
+	// Marker records the time an orphan was detected.
+	Marker Marker
+	// SweepOptions to select the resources.
+	SweepOptions []sweep.Option
+	// Now returns the current time.
+	Now func() time.Time
+}
+
+func makeConfig(opts ...Option) (*Config, error) {
+	c := &Config{
+		GracePeriod: DefaultGracePeriod,
+		Marker:      CloudMarker{},
+		Now:         time.Now,
+	}
+	for _, o := range opts {
+		o(c)
+	}
+	if c.GracePeriod < 0 {
+		return nil, fmt.Errorf("%s: invalid GracePeriod %v", errPrefix, c.GracePeriod)
+	}
+	return c, nil
+}
+
+// Result of the garbage collection. Each orphan is in exactly one of Marked,
+// Pending, Expired or Unmarkable.
+type Result struct {
+	// Marked are the orphans that were marked in this run.
+	Marked []sweep.Live
+	// Pending are the orphans that were marked in a previous run but are
+	// still within the grace period.
+	Pending []sweep.Live
+	// Expired are the orphans that were marked more than the grace period
+	// ago. Actions will delete these resources, except the Protected ones.
+	Expired []sweep.Live
+	// Protected are the Expired orphans that are deletion protected (see
+	// plan.DeletionProtectedKey). These will not be deleted.
+	Protected []sweep.Live
+	// Unmarkable are the orphans that cannot be marked. These will never be
+	// deleted by garbage collection.
+	Unmarkable []sweep.Live
+	// Unmarked are the resources in the graph that were marked by a
+	// previous run and had the mark removed in this run.
+	Unmarked []sweep.Live
+	// Actions to delete the Expired orphans.
+	Actions []exec.Action
+}
+
+// Do finds the orphans for the graph g, marks the orphans that were not
+// previously marked, unmarks the resources that are in g and returns the
+// Actions to delete the orphans whose grace period has expired. The caller
+// is responsible for executing the Actions.
+func Do(ctx context.Context, cl cloud.Cloud, project string, g *rgraph.Graph, opts ...Option) (*Result, error) {
+	c, err := makeConfig(opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	sweepOpts := append(append([]sweep.Option{}, c.SweepOptions...), sweep.DeleteOption(false))
+	sr, err := sweep.Do(ctx, cl, project, g, sweepOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", errPrefix, err)
+	}
+
+	logger := klog.FromContext(ctx).WithName("GC")
+	now := c.Now()
+	result := &Result{}
+	for i := range sr.Orphans {
+		l := &sr.Orphans[i]
+		markedAt, ok := c.Marker.MarkedAt(l)
+		switch {
+		case !ok:
+			err := c.Marker.Mark(ctx, cl, l, now)
+			switch {
+			case errors.Is(err, ErrMarkNotSupported):
+				logger.V(2).Info("Orphan cannot be marked", "resourceID", l.ID)
+				result.Unmarkable = append(result.Unmarkable, *l)
+			case err != nil:
+				return nil, fmt.Errorf("%s: mark %v: %w", errPrefix, l.ID, err)
+			default:
+				logger.V(2).Info("Marked orphan", "resourceID", l.ID)
+				result.Marked = append(result.Marked, *l)
+			}
+		case now.Sub(markedAt) >= c.GracePeriod:
+			logger.V(2).Info("Orphan expired", "resourceID", l.ID, "markedAt", markedAt)
+			result.Expired = append(result.Expired, *l)
+		default:
+			result.Pending = append(result.Pending, *l)
+		}
+	}
+
+	for i := range sr.InGraph {
+		l := &sr.InGraph[i]
+		if _, ok := c.Marker.MarkedAt(l); !ok {
+			continue
+		}
+		if err := c.Marker.Unmark(ctx, cl, l); err != nil {
+			return nil, fmt.Errorf("%s: unmark %v: %w", errPrefix, l.ID, err)
+		}
+		logger.V(2).Info("Unmarked resource in graph", "resourceID", l.ID)
+		result.Unmarked = append(result.Unmarked, *l)
+	}
+
+	if len(result.Expired) > 0 {
+		result.Actions, result.Protected, err = sweep.DeleteActions(ctx, cl, result.Expired)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", errPrefix, err)
+		}
+	}
+
+	return result, nil
+}
@@ -0,0 +1,583 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gc
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/mock"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/exec"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/healthcheck"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/workflow/plan"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/workflow/sweep"
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/api/compute/v1"
+	"google.golang.org/api/networkservices/v1"
+)
+
+const project = "proj"
+
+func setupMock(t *testing.T) *cloud.MockGCE {
+	t.Helper()
+
+	ctx := context.Background()
+	m := cloud.NewMockGCE(&cloud.SingleProjectRouter{ID: project})
+	m.MockHealthChecks.UpdateHook = mock.UpdateHealthCheckHook
+	m.MockBackendServices.UpdateHook = mock.UpdateBackendServiceHook
+
+	for _, name := range []string{"k8s-keep", "k8s-hc"} {
+		if err := m.HealthChecks().Insert(ctx, meta.GlobalKey(name), &compute.HealthCheck{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	hcLink := cloud.SelfLink(meta.VersionGA, project, "healthChecks", meta.GlobalKey("k8s-hc"))
+	bs := &compute.BackendService{Description: "created by k8s", HealthChecks: []string{hcLink}}
+	if err := m.BackendServices().Insert(ctx, meta.GlobalKey("k8s-bs"), bs); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.GlobalAddresses().Insert(ctx, meta.GlobalKey("k8s-addr"), &compute.Address{}); err != nil {
+		t.Fatal(err)
+	}
+	return m
+}
+
+// graph returns a graph with the "k8s-keep" HealthCheck.
+func graph() *rgraph.Graph {
+	return healthCheckGraph("k8s-keep")
+}
+
+// healthCheckGraph returns a graph with the named HealthChecks.
+func healthCheckGraph(hcNames ...string) *rgraph.Graph {
+	b := rgraph.NewBuilder()
+	for _, name := range hcNames {
+		r, _ := healthcheck.NewMutableHealthCheck(project, meta.GlobalKey(name)).Freeze()
+		nb := healthcheck.NewBuilderWithResource(r)
+		nb.SetOwnership(rnode.OwnershipManaged)
+		nb.SetState(rnode.NodeExists)
+		b.Add(nb)
+	}
+	return b.MustBuild()
+}
+
+func names(l []sweep.Live) []string {
+	var ret []string
+	for _, x := range l {
+		ret = append(ret, x.ID.String())
+	}
+	return ret
+}
+
+func TestDo(t *testing.T) {
+	ctx := context.Background()
+	m := setupMock(t)
+	start := time.Unix(1700000000, 0)
+
+	run := func(now time.Time) *Result {
+		t.Helper()
+		r, err := Do(ctx, m, project, graph(),
+			SweepOptions(sweep.PrefixOption("k8s-")),
+			GracePeriodOption(time.Hour),
+			NowOption(func() time.Time { return now }))
+		if err != nil {
+			t.Fatalf("Do() = %v, want nil", err)
+		}
+		return r
+	}
+
+	bsID := "compute/backendServices:proj/k8s-bs"
+	hcID := "compute/healthChecks:proj/k8s-hc"
+	addrID := "compute/addresses:proj/k8s-addr"
+
+	// Run 1: orphans are marked.
+	r := run(start)
+	if diff := cmp.Diff(names(r.Marked), []string{bsID, hcID}); diff != "" {
+		t.Errorf("run 1: Marked: diff -got,+want: %s", diff)
+	}
+	if diff := cmp.Diff(names(r.Unmarkable), []string{addrID}); diff != "" {
+		t.Errorf("run 1: Unmarkable: diff -got,+want: %s", diff)
+	}
+	if len(r.Actions) != 0 {
+		t.Errorf("run 1: len(Actions) = %d, want 0", len(r.Actions))
+	}
+	bs, err := m.BackendServices().Get(ctx, meta.GlobalKey("k8s-bs"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := markedDescription("created by k8s", "1700000000"); bs.Description != want {
+		t.Errorf("bs.Description = %q, want %q", bs.Description, want)
+	}
+
+	// Run 2: within the grace period.
+	r = run(start.Add(30 * time.Minute))
+	if diff := cmp.Diff(names(r.Pending), []string{bsID, hcID}); diff != "" {
+		t.Errorf("run 2: Pending: diff -got,+want: %s", diff)
+	}
+	if len(r.Marked)+len(r.Expired)+len(r.Actions) != 0 {
+		t.Errorf("run 2: got Marked=%v, Expired=%v, Actions=%v; want none", r.Marked, r.Expired, r.Actions)
+	}
+
+	// Run 3: grace period expired.
+	r = run(start.Add(2 * time.Hour))
+	if diff := cmp.Diff(names(r.Expired), []string{bsID, hcID}); diff != "" {
+		t.Errorf("run 3: Expired: diff -got,+want: %s", diff)
+	}
+	var actNames []string
+	for _, a := range r.Actions {
+		actNames = append(actNames, a.Metadata().Name)
+	}
+	sort.Strings(actNames)
+	wantActs := []string{
+		"GenericDeleteAction(" + bsID + ")",
+		"GenericDeleteAction(" + hcID + ")",
+	}
+	if diff := cmp.Diff(actNames, wantActs); diff != "" {
+		t.Errorf("run 3: Actions: diff -got,+want: %s", diff)
+	}
+}
+
+func TestDoProtected(t *testing.T) {
+	ctx := context.Background()
+	m := setupMock(t)
+	start := time.Unix(1700000000, 0)
+
+	// "k8s-hc" was DeletionProtected in "want" when it was last applied.
+	d := &cloud.Description{}
+	d.SetValue(plan.DeletionProtectedKey, "true")
+	hc, err := m.HealthChecks().Get(ctx, meta.GlobalKey("k8s-hc"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	hc.Description = d.String()
+
+	run := func(now time.Time) *Result {
+		t.Helper()
+		r, err := Do(ctx, m, project, graph(),
+			SweepOptions(sweep.PrefixOption("k8s-")),
+			GracePeriodOption(time.Hour),
+			NowOption(func() time.Time { return now }))
+		if err != nil {
+			t.Fatalf("Do() = %v, want nil", err)
+		}
+		return r
+	}
+	run(start)
+	r := run(start.Add(2 * time.Hour))
+
+	bsID := "compute/backendServices:proj/k8s-bs"
+	hcID := "compute/healthChecks:proj/k8s-hc"
+	if diff := cmp.Diff(names(r.Expired), []string{bsID, hcID}); diff != "" {
+		t.Errorf("Expired: diff -got,+want: %s", diff)
+	}
+	if diff := cmp.Diff(names(r.Protected), []string{hcID}); diff != "" {
+		t.Errorf("Protected: diff -got,+want: %s", diff)
+	}
+	var actNames []string
+	for _, a := range r.Actions {
+		actNames = append(actNames, a.Metadata().Name)
+	}
+	if diff := cmp.Diff(actNames, []string{"GenericDeleteAction(" + bsID + ")"}); diff != "" {
+		t.Fatalf("Actions: diff -got,+want: %s", diff)
+	}
+
+	ex, err := exec.NewSerialExecutor(m, r.Actions)
+	if err != nil {
+		t.Fatalf("NewSerialExecutor() = %v", err)
+	}
+	if _, err := ex.Run(ctx); err != nil {
+		t.Fatalf("Run() = %v, want nil", err)
+	}
+	if _, err := m.HealthChecks().Get(ctx, meta.GlobalKey("k8s-hc")); err != nil {
+		t.Errorf("Get(k8s-hc) = %v, want the protected orphan to survive GC", err)
+	}
+}
+
+func TestDoUnmark(t *testing.T) {
+	ctx := context.Background()
+	m := setupMock(t)
+	start := time.Unix(1700000000, 0)
+	hcID := "compute/healthChecks:proj/k8s-hc"
+
+	run := func(g *rgraph.Graph, now time.Time) *Result {
+		t.Helper()
+		r, err := Do(ctx, m, project, g,
+			SweepOptions(sweep.PrefixOption("k8s-"), sweep.ResourcesOption("healthChecks")),
+			GracePeriodOption(time.Hour),
+			NowOption(func() time.Time { return now }))
+		if err != nil {
+			t.Fatalf("Do() = %v, want nil", err)
+		}
+		return r
+	}
+
+	// Run 1: "k8s-hc" is briefly missing from the graph and is marked.
+	r := run(graph(), start)
+	if diff := cmp.Diff(names(r.Marked), []string{hcID}); diff != "" {
+		t.Errorf("run 1: Marked: diff -got,+want: %s", diff)
+	}
+
+	// Run 2: "k8s-hc" is back in the graph; the mark is removed.
+	r = run(healthCheckGraph("k8s-keep", "k8s-hc"), start.Add(30*time.Minute))
+	if diff := cmp.Diff(names(r.Unmarked), []string{hcID}); diff != "" {
+		t.Errorf("run 2: Unmarked: diff -got,+want: %s", diff)
+	}
+	hc, err := m.HealthChecks().Get(ctx, meta.GlobalKey("k8s-hc"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hc.Description != "" {
+		t.Errorf("run 2: hc.Description = %q, want \"\"", hc.Description)
+	}
+
+	// Run 3: "k8s-hc" is missing again after the original grace period.
+	// It is marked again instead of deleted.
+	r = run(graph(), start.Add(2*time.Hour))
+	if diff := cmp.Diff(names(r.Marked), []string{hcID}); diff != "" {
+		t.Errorf("run 3: Marked: diff -got,+want: %s", diff)
+	}
+	if len(r.Expired)+len(r.Actions)+len(r.Unmarked) != 0 {
+		t.Errorf("run 3: got Expired=%v, Actions=%v, Unmarked=%v; want none", r.Expired, r.Actions, r.Unmarked)
+	}
+
+	// Run 4: "k8s-hc" is back in the graph; the new mark is removed.
+	r = run(healthCheckGraph("k8s-keep", "k8s-hc"), start.Add(3*time.Hour))
+	if diff := cmp.Diff(names(r.Unmarked), []string{hcID}); diff != "" {
+		t.Errorf("run 4: Unmarked: diff -got,+want: %s", diff)
+	}
+
+	// Run 5: resources in the graph without a mark are left untouched.
+	r = run(healthCheckGraph("k8s-keep", "k8s-hc"), start.Add(4*time.Hour))
+	if len(r.Unmarked) != 0 {
+		t.Errorf("run 5: Unmarked = %v, want none", names(r.Unmarked))
+	}
+}
+
+func TestDoInvalidConfig(t *testing.T) {
+	m := setupMock(t)
+	if _, err := Do(context.Background(), m, project, graph(), GracePeriodOption(-time.Second), SweepOptions(sweep.PrefixOption("k8s-"))); err == nil {
+		t.Errorf("Do(negative grace period) = nil, want error")
+	}
+	if _, err := Do(context.Background(), m, project, graph()); err == nil {
+		t.Errorf("Do(no selector) = nil, want error")
+	}
+}
+
+func TestCloudMarkerMarkedAt(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		live   sweep.Live
+		want   time.Time
+		wantOK bool
+	}{
+		{name: "unmarked", live: sweep.Live{Description: "foo"}},
+		{
+			name:   "description",
+			live:   sweep.Live{Description: markedDescription("foo", "100")},
+			want:   time.Unix(100, 0),
+			wantOK: true,
+		},
+		{name: "invalid description metadata", live: sweep.Live{Description: "foo\n" + cloud.DescriptionMetadataKey + "={"}},
+		{
+			name:   "label",
+			live:   sweep.Live{Labels: map[string]string{MarkKey: "200"}},
+			want:   time.Unix(200, 0),
+			wantOK: true,
+		},
+		{name: "invalid label", live: sweep.Live{Labels: map[string]string{MarkKey: "x"}}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got, ok := CloudMarker{}.MarkedAt(&tc.live)
+			if ok != tc.wantOK || !got.Equal(tc.want) {
+				t.Errorf("MarkedAt() = %v, %t; want %v, %t", got, ok, tc.want, tc.wantOK)
+			}
+		})
+	}
+}
+
+// markedDescription returns the Description with text and the mark value.
+func markedDescription(text, value string) string {
+	d := &cloud.Description{Text: text}
+	d.SetValue(MarkKey, value)
+	return d.String()
+}
+
+func TestMarkDescription(t *testing.T) {
+	owned := &cloud.Description{Text: "foo", Owner: "ingress"}
+	ownedMarked := &cloud.Description{Text: "foo", Owner: "ingress", Values: map[string]string{MarkKey: "5"}}
+
+	for _, tc := range []struct {
+		desc    string
+		value   string
+		want    string
+		wantErr bool
+	}{
+		{desc: "", value: "5", want: markedDescription("", "5")},
+		{desc: "foo", value: "5", want: markedDescription("foo", "5")},
+		{desc: markedDescription("foo", "1"), value: "5", want: markedDescription("foo", "5")},
+		{desc: owned.String(), value: "5", want: ownedMarked.String()},
+		{desc: "foo\n" + cloud.DescriptionMetadataKey + "={", value: "5", wantErr: true},
+		// An empty value removes the mark.
+		{desc: markedDescription("foo", "1"), want: "foo"},
+		{desc: ownedMarked.String(), want: owned.String()},
+		{desc: "foo", want: "foo"},
+	} {
+		got, err := markDescription(tc.desc, tc.value)
+		if gotErr := err != nil; gotErr != tc.wantErr {
+			t.Errorf("markDescription(%q, %q) = %v; gotErr = %t, want %t", tc.desc, tc.value, err, gotErr, tc.wantErr)
+			continue
+		}
+		if got != tc.want {
+			t.Errorf("markDescription(%q, %q) = %q, want %q", tc.desc, tc.value, got, tc.want)
+		}
+	}
+}
+
+func TestCloudMarkerMark(t *testing.T) {
+	ctx := context.Background()
+	m := cloud.NewMockGCE(&cloud.SingleProjectRouter{ID: project})
+	m.MockBackendServices.UpdateHook = mock.UpdateBackendServiceHook
+	m.MockRegionBackendServices.UpdateHook = mock.UpdateRegionBackendServiceHook
+	m.MockHealthChecks.UpdateHook = mock.UpdateHealthCheckHook
+	m.MockRegionHealthChecks.UpdateHook = mock.UpdateRegionHealthCheckHook
+	m.MockUrlMaps.UpdateHook = mock.UpdateURLMapHook
+	m.MockRegionUrlMaps.UpdateHook = mock.UpdateRegionURLMapHook
+	m.MockGlobalForwardingRules.SetLabelsHook = mock.SetLabelsGlobalForwardingRuleHook
+	m.MockForwardingRules.SetLabelsHook = mock.SetLabelsForwardingRuleHook
+	m.MockTcpRoutes.PatchHook = func(_ context.Context, key *meta.Key, obj *networkservices.TcpRoute, m *cloud.MockTcpRoutes, _ ...cloud.Option) error {
+		m.Lock.Lock()
+		defer m.Lock.Unlock()
+		m.Objects[*key] = &cloud.MockTcpRoutesObj{Obj: obj}
+		return nil
+	}
+
+	global := meta.GlobalKey("x")
+	regional := meta.RegionalKey("x", "us-central1")
+	desc := func(d string, err error) (*sweep.Live, error) { return &sweep.Live{Description: d}, err }
+	labels := func(l map[string]string, err error) (*sweep.Live, error) { return &sweep.Live{Labels: l}, err }
+
+	for _, tc := range []struct {
+		name   string
+		id     *cloud.ResourceID
+		insert func() error
+		get    func() (*sweep.Live, error)
+	}{
+		{
+			name:   "global BackendService",
+			id:     &cloud.ResourceID{ProjectID: project, Resource: "backendServices", Key: global},
+			insert: func() error { return m.BackendServices().Insert(ctx, global, &compute.BackendService{}) },
+			get: func() (*sweep.Live, error) {
+				o, err := m.BackendServices().Get(ctx, global)
+				return desc(o.Description, err)
+			},
+		},
+		{
+			name:   "regional BackendService",
+			id:     &cloud.ResourceID{ProjectID: project, Resource: "backendServices", Key: regional},
+			insert: func() error { return m.RegionBackendServices().Insert(ctx, regional, &compute.BackendService{}) },
+			get: func() (*sweep.Live, error) {
+				o, err := m.RegionBackendServices().Get(ctx, regional)
+				return desc(o.Description, err)
+			},
+		},
+		{
+			name:   "global HealthCheck",
+			id:     &cloud.ResourceID{ProjectID: project, Resource: "healthChecks", Key: global},
+			insert: func() error { return m.HealthChecks().Insert(ctx, global, &compute.HealthCheck{}) },
+			get: func() (*sweep.Live, error) {
+				o, err := m.HealthChecks().Get(ctx, global)
+				return desc(o.Description, err)
+			},
+		},
+		{
+			name:   "regional HealthCheck",
+			id:     &cloud.ResourceID{ProjectID: project, Resource: "healthChecks", Key: regional},
+			insert: func() error { return m.RegionHealthChecks().Insert(ctx, regional, &compute.HealthCheck{}) },
+			get: func() (*sweep.Live, error) {
+				o, err := m.RegionHealthChecks().Get(ctx, regional)
+				return desc(o.Description, err)
+			},
+		},
+		{
+			name:   "global UrlMap",
+			id:     &cloud.ResourceID{ProjectID: project, Resource: "urlMaps", Key: global},
+			insert: func() error { return m.UrlMaps().Insert(ctx, global, &compute.UrlMap{}) },
+			get: func() (*sweep.Live, error) {
+				o, err := m.UrlMaps().Get(ctx, global)
+				return desc(o.Description, err)
+			},
+		},
+		{
+			name:   "regional UrlMap",
+			id:     &cloud.ResourceID{ProjectID: project, Resource: "urlMaps", Key: regional},
+			insert: func() error { return m.RegionUrlMaps().Insert(ctx, regional, &compute.UrlMap{}) },
+			get: func() (*sweep.Live, error) {
+				o, err := m.RegionUrlMaps().Get(ctx, regional)
+				return desc(o.Description, err)
+			},
+		},
+		{
+			name:   "TcpRoute",
+			id:     &cloud.ResourceID{ProjectID: project, Resource: "tcpRoutes", Key: global},
+			insert: func() error { return m.TcpRoutes().Insert(ctx, global, &networkservices.TcpRoute{}) },
+			get: func() (*sweep.Live, error) {
+				o, err := m.TcpRoutes().Get(ctx, global)
+				return desc(o.Description, err)
+			},
+		},
+		{
+			name: "global ForwardingRule",
+			id:   &cloud.ResourceID{ProjectID: project, Resource: "forwardingRules", Key: global},
+			insert: func() error {
+				return m.GlobalForwardingRules().Insert(ctx, global, &compute.ForwardingRule{Labels: map[string]string{"a": "b"}})
+			},
+			get: func() (*sweep.Live, error) {
+				o, err := m.GlobalForwardingRules().Get(ctx, global)
+				return labels(o.Labels, err)
+			},
+		},
+		{
+			name:   "regional ForwardingRule",
+			id:     &cloud.ResourceID{ProjectID: project, Resource: "forwardingRules", Key: regional},
+			insert: func() error { return m.ForwardingRules().Insert(ctx, regional, &compute.ForwardingRule{}) },
+			get: func() (*sweep.Live, error) {
+				o, err := m.ForwardingRules().Get(ctx, regional)
+				return labels(o.Labels, err)
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := tc.insert(); err != nil {
+				t.Fatalf("Insert() = %v", err)
+			}
+			markTime := time.Unix(1700000000, 0)
+			if err := (CloudMarker{}).Mark(ctx, m, &sweep.Live{ID: tc.id}, markTime); err != nil {
+				t.Fatalf("Mark() = %v, want nil", err)
+			}
+			l, err := tc.get()
+			if err != nil {
+				t.Fatalf("Get() = %v", err)
+			}
+			if got, ok := (CloudMarker{}).MarkedAt(l); !ok || !got.Equal(markTime) {
+				t.Errorf("MarkedAt() = %v, %t; want %v, true", got, ok, markTime)
+			}
+
+			if err := (CloudMarker{}).Unmark(ctx, m, &sweep.Live{ID: tc.id}); err != nil {
+				t.Fatalf("Unmark() = %v, want nil", err)
+			}
+			l, err = tc.get()
+			if err != nil {
+				t.Fatalf("Get() = %v", err)
+			}
+			if got, ok := (CloudMarker{}).MarkedAt(l); ok {
+				t.Errorf("MarkedAt() after Unmark() = %v, true; want false", got)
+			}
+		})
+	}
+
+	t.Run("not found", func(t *testing.T) {
+		id := &cloud.ResourceID{ProjectID: project, Resource: "healthChecks", Key: meta.GlobalKey("missing")}
+		if err := (CloudMarker{}).Mark(ctx, m, &sweep.Live{ID: id}, time.Now()); err == nil {
+			t.Errorf("Mark() = nil, want error")
+		}
+	})
+	t.Run("forces project", func(t *testing.T) {
+		m := cloud.NewMockGCE(&cloud.SingleProjectRouter{ID: "default-proj"})
+		if err := m.HealthChecks().Insert(ctx, global, &compute.HealthCheck{}); err != nil {
+			t.Fatal(err)
+		}
+		var calls int
+		checkProject := func(name string, options []cloud.Option) {
+			calls++
+			for _, o := range options {
+				if o == cloud.ForceProjectID(project) {
+					return
+				}
+			}
+			t.Errorf("%s(options = %v), want ForceProjectID(%q)", name, options, project)
+		}
+		m.MockHealthChecks.GetHook = func(_ context.Context, _ *meta.Key, _ *cloud.MockHealthChecks, options ...cloud.Option) (bool, *compute.HealthCheck, error) {
+			checkProject("Get", options)
+			return false, nil, nil
+		}
+		// The generated mock does not pass the options of Update to
+		// the hook so only Get is checked.
+		m.MockHealthChecks.UpdateHook = func(_ context.Context, key *meta.Key, obj *compute.HealthCheck, m *cloud.MockHealthChecks, _ ...cloud.Option) error {
+			m.Lock.Lock()
+			defer m.Lock.Unlock()
+			m.Objects[*key] = &cloud.MockHealthChecksObj{Obj: obj}
+			return nil
+		}
+		id := &cloud.ResourceID{ProjectID: project, Resource: "healthChecks", Key: global}
+		if err := (CloudMarker{}).Mark(ctx, m, &sweep.Live{ID: id}, time.Now()); err != nil {
+			t.Fatalf("Mark() = %v, want nil", err)
+		}
+		if err := (CloudMarker{}).Unmark(ctx, m, &sweep.Live{ID: id}); err != nil {
+			t.Fatalf("Unmark() = %v, want nil", err)
+		}
+		if calls != 2 {
+			t.Errorf("calls = %d, want 2 (Get for Mark and Unmark)", calls)
+		}
+	})
+	t.Run("unsupported", func(t *testing.T) {
+		id := &cloud.ResourceID{ProjectID: project, Resource: "addresses", Key: global}
+		if err := (CloudMarker{}).Mark(ctx, m, &sweep.Live{ID: id}, time.Now()); !errors.Is(err, ErrMarkNotSupported) {
+			t.Errorf("Mark() = %v, want ErrMarkNotSupported", err)
+		}
+	})
+}
+
+// fakeMarker marks resources in memory.
+type fakeMarker struct {
+	marks map[string]time.Time
+}
+
+func (f *fakeMarker) MarkedAt(l *sweep.Live) (time.Time, bool) {
+	t, ok := f.marks[l.ID.String()]
+	return t, ok
+}
+
+func (f *fakeMarker) Mark(_ context.Context, _ cloud.Cloud, l *sweep.Live, t time.Time) error {
+	f.marks[l.ID.String()] = t
+	return nil
+}
+
+func (f *fakeMarker) Unmark(_ context.Context, _ cloud.Cloud, l *sweep.Live) error {
+	delete(f.marks, l.ID.String())
+	return nil
+}
+
+func TestDoMarkerOption(t *testing.T) {
+	ctx := context.Background()
+	m := setupMock(t)
+	fm := &fakeMarker{marks: map[string]time.Time{}}
+
+	r, err := Do(ctx, m, project, graph(), SweepOptions(sweep.PrefixOption("k8s-")), MarkerOption(fm))
+	if err != nil {
+		t.Fatalf("Do() = %v, want nil", err)
+	}
+	// The fakeMarker supports all resource types.
+	if len(r.Unmarkable) != 0 || len(r.Marked) != 3 || len(fm.marks) != 3 {
+		t.Errorf("Do() = Marked %v, Unmarkable %v, marks %v; want 3 marked", names(r.Marked), names(r.Unmarkable), fm.marks)
+	}
+}
@@ -0,0 +1,220 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/workflow/sweep"
+	"google.golang.org/api/compute/v1"
+)
+
+// MarkKey is the label key (for resources with labels) and the
+// cloud.Description value name (for resources without labels) used to record
+// when a resource was first seen as an orphan.
+const MarkKey = "rgraph-orphaned-at"
+
+// ErrMarkNotSupported is returned by Marker.Mark() for resource types that
+// cannot be marked.
+var ErrMarkNotSupported = errors.New("mark not supported")
+
+// Marker records the time a resource was first detected as an orphan.
+type Marker interface {
+	// MarkedAt returns the time the resource was marked. Returns false if
+	// the resource is not marked.
+	MarkedAt(l *sweep.Live) (time.Time, bool)
+	// Mark the resource in the Cloud with time t. Returns
+	// ErrMarkNotSupported if the resource type cannot be marked.
+	Mark(ctx context.Context, cl cloud.Cloud, l *sweep.Live, t time.Time) error
+	// Unmark removes the mark from the resource in the Cloud. Returns
+	// ErrMarkNotSupported if the resource type cannot be marked.
+	Unmark(ctx context.Context, cl cloud.Cloud, l *sweep.Live) error
+}
+
+// CloudMarker is the default Marker. ForwardingRules are marked with a label;
+// BackendServices, HealthChecks, UrlMaps and TcpRoutes are marked with a
+// value in the cloud.Description metadata. Other resource types are not
+// supported.
+type CloudMarker struct{}
+
+// MarkedAt implements Marker.
+func (CloudMarker) MarkedAt(l *sweep.Live) (time.Time, bool) {
+	if v, ok := l.Labels[MarkKey]; ok {
+		return parseUnix(v)
+	}
+	d, err := cloud.ParseDescription(l.Description)
+	if err != nil {
+		return time.Time{}, false
+	}
+	v, ok := d.Values[MarkKey]
+	if !ok {
+		return time.Time{}, false
+	}
+	return parseUnix(v)
+}
+
+func parseUnix(s string) (time.Time, bool) {
+	sec, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return time.Unix(sec, 0), true
+}
+
+// markDescription sets the mark in the description, replacing any existing
+// mark. An empty value removes the mark.
+func markDescription(desc string, value string) (string, error) {
+	d, err := cloud.ParseDescription(desc)
+	if err != nil {
+		return "", err
+	}
+	if value == "" {
+		d.DeleteValue(MarkKey)
+	} else {
+		d.SetValue(MarkKey, value)
+	}
+	return d.String(), nil
+}
+
+// Mark implements Marker.
+func (CloudMarker) Mark(ctx context.Context, cl cloud.Cloud, l *sweep.Live, t time.Time) error {
+	return setMark(ctx, cl, l, strconv.FormatInt(t.Unix(), 10))
+}
+
+// Unmark implements Marker.
+func (CloudMarker) Unmark(ctx context.Context, cl cloud.Cloud, l *sweep.Live) error {
+	return setMark(ctx, cl, l, "")
+}
+
+// setMark sets the mark on the resource in the Cloud. An empty value removes
+// the mark.
+func setMark(ctx context.Context, cl cloud.Cloud, l *sweep.Live, value string) error {
+	key := l.ID.Key
+	opt := cloud.ForceProjectID(l.ID.ProjectID)
+	switch l.ID.Resource {
+	case "backendServices":
+		if key.Region != "" {
+			obj, err := cl.RegionBackendServices().Get(ctx, key, opt)
+			if err != nil {
+				return err
+			}
+			if obj.Description, err = markDescription(obj.Description, value); err != nil {
+				return err
+			}
+			return cl.RegionBackendServices().Update(ctx, key, obj, opt)
+		}
+		obj, err := cl.BackendServices().Get(ctx, key, opt)
+		if err != nil {
+			return err
+		}
+		if obj.Description, err = markDescription(obj.Description, value); err != nil {
+			return err
+		}
+		return cl.BackendServices().Update(ctx, key, obj, opt)
+
+	case "healthChecks":
+		if key.Region != "" {
+			obj, err := cl.RegionHealthChecks().Get(ctx, key, opt)
+			if err != nil {
+				return err
+			}
+			if obj.Description, err = markDescription(obj.Description, value); err != nil {
+				return err
+			}
+			return cl.RegionHealthChecks().Update(ctx, key, obj, opt)
+		}
+		obj, err := cl.HealthChecks().Get(ctx, key, opt)
+		if err != nil {
+			return err
+		}
+		if obj.Description, err = markDescription(obj.Description, value); err != nil {
+			return err
+		}
+		return cl.HealthChecks().Update(ctx, key, obj, opt)
+
+	case "urlMaps":
+		if key.Region != "" {
+			obj, err := cl.RegionUrlMaps().Get(ctx, key, opt)
+			if err != nil {
+				return err
+			}
+			if obj.Description, err = markDescription(obj.Description, value); err != nil {
+				return err
+			}
+			return cl.RegionUrlMaps().Update(ctx, key, obj, opt)
+		}
+		obj, err := cl.UrlMaps().Get(ctx, key, opt)
+		if err != nil {
+			return err
+		}
+		if obj.Description, err = markDescription(obj.Description, value); err != nil {
+			return err
+		}
+		return cl.UrlMaps().Update(ctx, key, obj, opt)
+
+	case "tcpRoutes":
+		obj, err := cl.TcpRoutes().Get(ctx, key, opt)
+		if err != nil {
+			return err
+		}
+		if obj.Description, err = markDescription(obj.Description, value); err != nil {
+			return err
+		}
+		return cl.TcpRoutes().Patch(ctx, key, obj, opt)
+
+	case "forwardingRules":
+		if key.Region != "" {
+			obj, err := cl.ForwardingRules().Get(ctx, key, opt)
+			if err != nil {
+				return err
+			}
+			return cl.ForwardingRules().SetLabels(ctx, key, &compute.RegionSetLabelsRequest{
+				Labels:           withLabel(obj.Labels, value),
+				LabelFingerprint: obj.LabelFingerprint,
+			}, opt)
+		}
+		obj, err := cl.GlobalForwardingRules().Get(ctx, key, opt)
+		if err != nil {
+			return err
+		}
+		return cl.GlobalForwardingRules().SetLabels(ctx, key, &compute.GlobalSetLabelsRequest{
+			Labels:           withLabel(obj.Labels, value),
+			LabelFingerprint: obj.LabelFingerprint,
+		}, opt)
+	}
+	return fmt.Errorf("%w: %s", ErrMarkNotSupported, l.ID.Resource)
+}
+
+// withLabel returns a copy of labels with the mark set to value. An empty
+// value removes the mark.
+func withLabel(labels map[string]string, value string) map[string]string {
+	ret := map[string]string{}
+	for k, v := range labels {
+		ret[k] = v
+	}
+	if value == "" {
+		delete(ret, MarkKey)
+	} else {
+		ret[MarkKey] = value
+	}
+	return ret
+}
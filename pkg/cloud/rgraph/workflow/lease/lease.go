@@ -0,0 +1,298 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package lease implements a lease held by a controller replica on a root
+// resource of a graph. The lease is stored as labels on the resource with
+// the holder and the expiry time. Use the lease to prevent two replicas of
+// a controller from applying conflicting plans to the same graph (see
+// ensure.LeaseOption).
+//
+// Updates to the labels use the label fingerprint of the resource, so two
+// replicas racing for the lease will not both acquire it. Only
+// ForwardingRules are supported as the root resource.
+package lease
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/cerrors"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/forwardingrule"
+	"google.golang.org/api/compute/v1"
+	"k8s.io/klog/v2"
+)
+
+const errPrefix = "Lease"
+
+const (
+	// HolderKey is the label key for the holder of the lease.
+	HolderKey = "rgraph-lease-holder"
+	// ExpiryKey is the label key for the expiry of the lease in Unix
+	// seconds.
+	ExpiryKey = "rgraph-lease-expiry"
+
+	// DefaultDuration of the lease if DurationOption is not given.
+	DefaultDuration = 5 * time.Minute
+)
+
+// Option for New().
+type Option func(c *Config)
+
+// DurationOption sets the duration of the lease. The lease must be
+// acquired again before it expires; see Check().
+func DurationOption(d time.Duration) Option {
+	return func(c *Config) { c.Duration = d }
+}
+
+// NowOption overrides the clock. This is used for testing.
+func NowOption(now func() time.Time) Option {
+	return func(c *Config) { c.Now = now }
+}
+
+// Config for the Lease.
+type Config struct {
+	// Duration of the lease.
+	Duration time.Duration
+	// Now returns the current time.
+	Now func() time.Time
+}
+
+// HeldError is returned by Acquire() when the lease is held by another
+// holder.
+type HeldError struct {
+	ID     *cloud.ResourceID
+	Holder string
+	Expiry time.Time
+}
+
+func (e *HeldError) Error() string {
+	return fmt.Sprintf("%s: %v is held by %q until %v", errPrefix, e.ID, e.Holder, e.Expiry)
+}
+
+// ExpiredError is returned by Check() when the lease held by Holder has
+// expired.
+type ExpiredError struct {
+	ID     *cloud.ResourceID
+	Holder string
+	Expiry time.Time
+}
+
+func (e *ExpiredError) Error() string {
+	return fmt.Sprintf("%s: %v held by %q expired at %v", errPrefix, e.ID, e.Holder, e.Expiry)
+}
+
+// Lease on the resource ID for Holder.
+type Lease struct {
+	ID     *cloud.ResourceID
+	Holder string
+
+	config Config
+	// held is true if the last Acquire() set the labels.
+	held   bool
+	expiry time.Time
+}
+
+// labelValueRegexp matches valid GCE label values.
+var labelValueRegexp = regexp.MustCompile(`^[a-z0-9_-]{1,63}$`)
+
+// New returns a Lease on id. holder identifies the replica (e.g. the Pod
+// name) and must be a valid label value.
+func New(id *cloud.ResourceID, holder string, opts ...Option) (*Lease, error) {
+	c := Config{
+		Duration: DefaultDuration,
+		Now:      time.Now,
+	}
+	for _, o := range opts {
+		o(&c)
+	}
+	if id.Resource != "forwardingRules" {
+		return nil, fmt.Errorf("%s: unsupported resource %v (only forwardingRules are supported)", errPrefix, id)
+	}
+	if !labelValueRegexp.MatchString(holder) {
+		return nil, fmt.Errorf("%s: invalid holder %q (must be a valid label value)", errPrefix, holder)
+	}
+	if c.Duration <= 0 {
+		return nil, fmt.Errorf("%s: invalid Duration %v", errPrefix, c.Duration)
+	}
+	return &Lease{ID: id, Holder: holder, config: c}, nil
+}
+
+// Held is true if the lease was set on the resource by the last call to
+// Acquire().
+func (l *Lease) Held() bool { return l.held }
+
+// Check returns an ExpiredError if the lease is held but has expired, i.e.
+// another holder may have acquired it. Call Check() before each mutation
+// that relies on the lease. Returns nil if the lease is not held.
+func (l *Lease) Check() error {
+	if l.held && !l.config.Now().Before(l.expiry) {
+		return &ExpiredError{ID: l.ID, Holder: l.Holder, Expiry: l.expiry}
+	}
+	return nil
+}
+
+// Acquire the lease. This returns a HeldError if another holder has an
+// unexpired lease. Acquire() also renews a lease that is already held by
+// l.Holder.
+//
+// If the resource does not exist, Acquire() returns nil and the lease is
+// not held (Held() == false). Concurrent creation of the same resource is
+// already rejected by the API.
+func (l *Lease) Acquire(ctx context.Context, cl cloud.Cloud) error {
+	logger := klog.FromContext(ctx)
+	l.held = false
+
+	fr, err := l.get(ctx, cl)
+	if cerrors.IsGoogleAPINotFound(err) {
+		logger.V(2).Info("Lease resource does not exist, lease not held", "id", l.ID)
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("%s: %w", errPrefix, err)
+	}
+
+	now := l.config.Now()
+	if holder, expiry, ok := parse(fr.Labels); ok && holder != l.Holder && now.Before(expiry) {
+		return &HeldError{ID: l.ID, Holder: holder, Expiry: expiry}
+	}
+
+	expiry := now.Add(l.config.Duration)
+	labels := copyLabels(fr.Labels)
+	labels[HolderKey] = l.Holder
+	labels[ExpiryKey] = strconv.FormatInt(expiry.Unix(), 10)
+	if err := l.setLabels(ctx, cl, labels, fr.LabelFingerprint); err != nil {
+		return fmt.Errorf("%s: %w", errPrefix, err)
+	}
+	l.held = true
+	l.expiry = time.Unix(expiry.Unix(), 0)
+	logger.V(2).Info("Lease acquired", "id", l.ID, "holder", l.Holder, "expiry", l.expiry)
+
+	return nil
+}
+
+// Release the lease if it is held by l.Holder.
+func (l *Lease) Release(ctx context.Context, cl cloud.Cloud) error {
+	if !l.held {
+		return nil
+	}
+	l.held = false
+
+	fr, err := l.get(ctx, cl)
+	if cerrors.IsGoogleAPINotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("%s: %w", errPrefix, err)
+	}
+	if holder, _, ok := parse(fr.Labels); !ok || holder != l.Holder {
+		// The lease expired and was taken by another holder.
+		return nil
+	}
+	labels := copyLabels(fr.Labels)
+	delete(labels, HolderKey)
+	delete(labels, ExpiryKey)
+	if err := l.setLabels(ctx, cl, labels, fr.LabelFingerprint); err != nil {
+		return fmt.Errorf("%s: %w", errPrefix, err)
+	}
+	klog.FromContext(ctx).V(2).Info("Lease released", "id", l.ID, "holder", l.Holder)
+
+	return nil
+}
+
+// Apply the lease labels to the resource in the Builder. This must be
+// called after Acquire() so that the plan for the graph does not remove the
+// lease. This is a no-op if the lease is not held or the resource is not in
+// the Builder.
+func (l *Lease) Apply(b *rgraph.Builder) error {
+	if !l.held {
+		return nil
+	}
+	nb := b.Get(l.ID)
+	if nb == nil || nb.Resource() == nil {
+		return nil
+	}
+	res, ok := nb.Resource().(forwardingrule.ForwardingRule)
+	if !ok {
+		return fmt.Errorf("%s: invalid resource type %T", errPrefix, nb.Resource())
+	}
+	obj, err := res.ToGA()
+	if err != nil {
+		return fmt.Errorf("%s: %w", errPrefix, err)
+	}
+	cp := *obj
+	cp.Labels = copyLabels(obj.Labels)
+	cp.Labels[HolderKey] = l.Holder
+	cp.Labels[ExpiryKey] = strconv.FormatInt(l.expiry.Unix(), 10)
+
+	m := forwardingrule.NewMutableForwardingRule(l.ID.ProjectID, l.ID.Key)
+	if err := m.Set(&cp); err != nil {
+		return fmt.Errorf("%s: %w", errPrefix, err)
+	}
+	r, err := m.Freeze()
+	if err != nil {
+		return fmt.Errorf("%s: %w", errPrefix, err)
+	}
+	if err := nb.SetResource(r); err != nil {
+		return fmt.Errorf("%s: %w", errPrefix, err)
+	}
+	return nil
+}
+
+func (l *Lease) get(ctx context.Context, cl cloud.Cloud) (*compute.ForwardingRule, error) {
+	if l.ID.Key.Region != "" {
+		return cl.ForwardingRules().Get(ctx, l.ID.Key, cloud.ForceProjectID(l.ID.ProjectID))
+	}
+	return cl.GlobalForwardingRules().Get(ctx, l.ID.Key, cloud.ForceProjectID(l.ID.ProjectID))
+}
+
+func (l *Lease) setLabels(ctx context.Context, cl cloud.Cloud, labels map[string]string, fingerprint string) error {
+	if l.ID.Key.Region != "" {
+		return cl.ForwardingRules().SetLabels(ctx, l.ID.Key, &compute.RegionSetLabelsRequest{
+			Labels:           labels,
+			LabelFingerprint: fingerprint,
+		}, cloud.ForceProjectID(l.ID.ProjectID))
+	}
+	return cl.GlobalForwardingRules().SetLabels(ctx, l.ID.Key, &compute.GlobalSetLabelsRequest{
+		Labels:           labels,
+		LabelFingerprint: fingerprint,
+	}, cloud.ForceProjectID(l.ID.ProjectID))
+}
+
+// parse the lease from labels. Returns false if there is no valid lease.
+func parse(labels map[string]string) (string, time.Time, bool) {
+	holder, ok := labels[HolderKey]
+	if !ok {
+		return "", time.Time{}, false
+	}
+	sec, err := strconv.ParseInt(labels[ExpiryKey], 10, 64)
+	if err != nil {
+		return "", time.Time{}, false
+	}
+	return holder, time.Unix(sec, 0), true
+}
+
+func copyLabels(labels map[string]string) map[string]string {
+	ret := map[string]string{}
+	for k, v := range labels {
+		ret[k] = v
+	}
+	return ret
+}
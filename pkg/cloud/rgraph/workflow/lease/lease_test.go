@@ -0,0 +1,291 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lease
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/mock"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/forwardingrule"
+	"google.golang.org/api/compute/v1"
+)
+
+func newMock(t *testing.T, key *meta.Key) *cloud.MockGCE {
+	t.Helper()
+	m := cloud.NewMockGCE(&cloud.SingleProjectRouter{ID: "proj"})
+	m.MockGlobalForwardingRules.SetLabelsHook = mock.SetLabelsGlobalForwardingRuleHook
+	m.MockForwardingRules.SetLabelsHook = mock.SetLabelsForwardingRuleHook
+	if key != nil {
+		fr := &compute.ForwardingRule{Labels: map[string]string{"app": "x"}}
+		if err := m.GlobalForwardingRules().Insert(context.Background(), key, fr); err != nil {
+			t.Fatalf("Insert() = %v", err)
+		}
+	}
+	return m
+}
+
+func labels(t *testing.T, cl cloud.Cloud, key *meta.Key) map[string]string {
+	t.Helper()
+	fr, err := cl.GlobalForwardingRules().Get(context.Background(), key)
+	if err != nil {
+		t.Fatalf("Get() = %v", err)
+	}
+	return fr.Labels
+}
+
+func TestLease(t *testing.T) {
+	ctx := context.Background()
+	key := meta.GlobalKey("fr")
+	id := forwardingrule.ID("proj", key)
+	now := time.Unix(1000, 0)
+	clock := func() time.Time { return now }
+
+	m := newMock(t, key)
+	a, err := New(id, "replica-a", NowOption(clock), DurationOption(time.Minute))
+	if err != nil {
+		t.Fatalf("New() = %v", err)
+	}
+	b, err := New(id, "replica-b", NowOption(clock), DurationOption(time.Minute))
+	if err != nil {
+		t.Fatalf("New() = %v", err)
+	}
+
+	if err := a.Acquire(ctx, m); err != nil || !a.Held() {
+		t.Fatalf("a.Acquire() = %v, Held() = %t; want nil, true", err, a.Held())
+	}
+	l := labels(t, m, key)
+	if l[HolderKey] != "replica-a" || l[ExpiryKey] != "1060" || l["app"] != "x" {
+		t.Errorf("labels = %v, want holder replica-a, expiry 1060 and existing labels", l)
+	}
+
+	// Held by a.
+	var heldErr *HeldError
+	if err := b.Acquire(ctx, m); !errors.As(err, &heldErr) || b.Held() {
+		t.Fatalf("b.Acquire() = %v, Held() = %t; want HeldError, false", err, b.Held())
+	}
+	if heldErr.Holder != "replica-a" {
+		t.Errorf("HeldError.Holder = %q, want replica-a", heldErr.Holder)
+	}
+
+	// Renew.
+	now = now.Add(30 * time.Second)
+	if err := a.Acquire(ctx, m); err != nil {
+		t.Fatalf("a.Acquire() = %v", err)
+	}
+	if got := labels(t, m, key)[ExpiryKey]; got != "1090" {
+		t.Errorf("expiry = %q, want 1090", got)
+	}
+
+	// Expired.
+	now = now.Add(2 * time.Minute)
+	if err := b.Acquire(ctx, m); err != nil || !b.Held() {
+		t.Fatalf("b.Acquire() = %v, Held() = %t; want nil, true", err, b.Held())
+	}
+
+	// a does not release b's lease.
+	a.held = true
+	if err := a.Release(ctx, m); err != nil {
+		t.Fatalf("a.Release() = %v", err)
+	}
+	if got := labels(t, m, key)[HolderKey]; got != "replica-b" {
+		t.Errorf("holder = %q, want replica-b", got)
+	}
+
+	if err := b.Release(ctx, m); err != nil {
+		t.Fatalf("b.Release() = %v", err)
+	}
+	l = labels(t, m, key)
+	if _, ok := l[HolderKey]; ok || l["app"] != "x" {
+		t.Errorf("labels = %v, want lease removed", l)
+	}
+	if err := a.Acquire(ctx, m); err != nil {
+		t.Fatalf("a.Acquire() = %v", err)
+	}
+}
+
+func TestLeaseNotFound(t *testing.T) {
+	ctx := context.Background()
+	id := forwardingrule.ID("proj", meta.GlobalKey("fr"))
+	m := newMock(t, nil)
+
+	l, err := New(id, "replica-a")
+	if err != nil {
+		t.Fatalf("New() = %v", err)
+	}
+	if err := l.Acquire(ctx, m); err != nil || l.Held() {
+		t.Fatalf("Acquire() = %v, Held() = %t; want nil, false", err, l.Held())
+	}
+	if err := l.Release(ctx, m); err != nil {
+		t.Fatalf("Release() = %v", err)
+	}
+}
+
+func TestLeaseFingerprintConflict(t *testing.T) {
+	ctx := context.Background()
+	key := meta.GlobalKey("fr")
+	id := forwardingrule.ID("proj", key)
+	m := newMock(t, key)
+
+	// Another replica changes the labels between the Get and SetLabels.
+	m.MockGlobalForwardingRules.SetLabelsHook = func(ctx context.Context, key *meta.Key, req *compute.GlobalSetLabelsRequest, gm *cloud.MockGlobalForwardingRules, opts ...cloud.Option) error {
+		fr, _ := gm.Get(ctx, key)
+		fr.LabelFingerprint = "changed"
+		return mock.SetLabelsGlobalForwardingRuleHook(ctx, key, req, gm, opts...)
+	}
+
+	l, err := New(id, "replica-a")
+	if err != nil {
+		t.Fatalf("New() = %v", err)
+	}
+	if err := l.Acquire(ctx, m); err == nil || l.Held() {
+		t.Fatalf("Acquire() = %v, Held() = %t; want error, false", err, l.Held())
+	}
+}
+
+func TestLeaseApply(t *testing.T) {
+	ctx := context.Background()
+	key := meta.GlobalKey("fr")
+	id := forwardingrule.ID("proj", key)
+	m := newMock(t, key)
+
+	mr := forwardingrule.NewMutableForwardingRule("proj", key)
+	mr.Access(func(x *compute.ForwardingRule) { x.Labels = map[string]string{"app": "x"} })
+	r, err := mr.Freeze()
+	if err != nil {
+		t.Fatalf("Freeze() = %v", err)
+	}
+	b := rgraph.NewBuilder()
+	b.Add(forwardingrule.NewBuilderWithResource(r))
+
+	l, err := New(id, "replica-a", NowOption(func() time.Time { return time.Unix(1000, 0) }))
+	if err != nil {
+		t.Fatalf("New() = %v", err)
+	}
+	// Not held: no-op.
+	if err := l.Apply(b); err != nil {
+		t.Fatalf("Apply() = %v", err)
+	}
+	if err := l.Acquire(ctx, m); err != nil {
+		t.Fatalf("Acquire() = %v", err)
+	}
+	if err := l.Apply(b); err != nil {
+		t.Fatalf("Apply() = %v", err)
+	}
+	res, _ := b.Get(id).Resource().(forwardingrule.ForwardingRule)
+	fr, _ := res.ToGA()
+	want := map[string]string{"app": "x", HolderKey: "replica-a", ExpiryKey: "1300"}
+	for k, v := range want {
+		if fr.Labels[k] != v {
+			t.Errorf("Labels[%q] = %q, want %q", k, fr.Labels[k], v)
+		}
+	}
+}
+
+func TestLeaseCheck(t *testing.T) {
+	ctx := context.Background()
+	key := meta.GlobalKey("fr")
+	id := forwardingrule.ID("proj", key)
+	m := newMock(t, key)
+	now := time.Unix(1000, 0)
+
+	l, err := New(id, "replica-a", NowOption(func() time.Time { return now }), DurationOption(time.Minute))
+	if err != nil {
+		t.Fatalf("New() = %v", err)
+	}
+	// Not held.
+	if err := l.Check(); err != nil {
+		t.Errorf("Check() = %v, want nil", err)
+	}
+	if err := l.Acquire(ctx, m); err != nil {
+		t.Fatalf("Acquire() = %v", err)
+	}
+	now = now.Add(59 * time.Second)
+	if err := l.Check(); err != nil {
+		t.Errorf("Check() = %v, want nil", err)
+	}
+	now = now.Add(time.Second)
+	var expiredErr *ExpiredError
+	if err := l.Check(); !errors.As(err, &expiredErr) {
+		t.Errorf("Check() = %v, want ExpiredError", err)
+	}
+}
+
+func TestLeaseForcesProject(t *testing.T) {
+	ctx := context.Background()
+	key := meta.GlobalKey("fr")
+	id := forwardingrule.ID("proj", key)
+	// The mock routes to a default project that differs from the project of
+	// the lease resource.
+	m := cloud.NewMockGCE(&cloud.SingleProjectRouter{ID: "default-proj"})
+	if err := m.GlobalForwardingRules().Insert(ctx, key, &compute.ForwardingRule{}); err != nil {
+		t.Fatalf("Insert() = %v", err)
+	}
+	var calls int
+	m.MockGlobalForwardingRules.GetHook = func(_ context.Context, _ *meta.Key, _ *cloud.MockGlobalForwardingRules, options ...cloud.Option) (bool, *compute.ForwardingRule, error) {
+		calls++
+		for _, o := range options {
+			if o == cloud.ForceProjectID("proj") {
+				return false, nil, nil
+			}
+		}
+		t.Errorf("Get(options = %v), want ForceProjectID(\"proj\")", options)
+		return false, nil, nil
+	}
+	// The generated mock does not pass the options of SetLabels to the
+	// hook so only Get is checked.
+
+	l, err := New(id, "replica-a")
+	if err != nil {
+		t.Fatalf("New() = %v", err)
+	}
+	if err := l.Acquire(ctx, m); err != nil {
+		t.Fatalf("Acquire() = %v", err)
+	}
+	if err := l.Release(ctx, m); err != nil {
+		t.Fatalf("Release() = %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("calls = %d, want 2 (Get for Acquire and Release)", calls)
+	}
+}
+
+func TestNewInvalid(t *testing.T) {
+	frID := forwardingrule.ID("proj", meta.GlobalKey("fr"))
+	for _, tc := range []struct {
+		name   string
+		id     *cloud.ResourceID
+		holder string
+		opts   []Option
+	}{
+		{name: "unsupported resource", id: &cloud.ResourceID{Resource: "backendServices", Key: meta.GlobalKey("bs")}, holder: "a"},
+		{name: "invalid holder", id: frID, holder: "Replica/A"},
+		{name: "empty holder", id: frID},
+		{name: "invalid duration", id: frID, holder: "a", opts: []Option{DurationOption(0)}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := New(tc.id, tc.holder, tc.opts...); err == nil {
+				t.Errorf("New() = nil, want error")
+			}
+		})
+	}
+}
@@ -0,0 +1,122 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plan
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode"
+	"k8s.io/klog/v2"
+)
+
+// BlastRadius limits the number of managed resources that a plan may delete
+// or recreate. This protects against a truncated "want" graph (e.g. from a
+// bug in the caller) deleting all of the resources. A limit of 0 is not
+// checked.
+type BlastRadius struct {
+	// MaxNodes is the maximum number of managed Nodes that may be deleted or
+	// recreated.
+	MaxNodes int
+	// MaxPercent is the maximum percentage (0-100) of the managed Nodes in
+	// the graph that may be deleted or recreated.
+	MaxPercent float64
+	// Force allows the plan to exceed the limits. The violation is logged
+	// instead.
+	Force bool
+}
+
+// BlastRadiusOption fails the plan with a *BlastRadiusError if it would
+// delete or recreate more managed Nodes than allowed by b.
+func BlastRadiusOption(b BlastRadius) Option {
+	return func(c *Config) { c.BlastRadius = &b }
+}
+
+// BlastRadiusError is returned when the plan exceeds the BlastRadius.
+type BlastRadiusError struct {
+	// IDs of the Nodes that would be deleted or recreated, sorted.
+	IDs []*cloud.ResourceID
+	// Managed is the number of managed Nodes in the graph.
+	Managed int
+	// Limit that was exceeded.
+	Limit BlastRadius
+}
+
+func (e *BlastRadiusError) Error() string {
+	var limits []string
+	if e.Limit.MaxNodes > 0 {
+		limits = append(limits, fmt.Sprintf("max %d nodes", e.Limit.MaxNodes))
+	}
+	if e.Limit.MaxPercent > 0 {
+		limits = append(limits, fmt.Sprintf("max %v%%", e.Limit.MaxPercent))
+	}
+	return fmt.Sprintf("%s: plan deletes or recreates %d of %d managed nodes, exceeding the blast radius (%s); use Force to override",
+		errPrefix, len(e.IDs), e.Managed, strings.Join(limits, ", "))
+}
+
+// validate the BlastRadius.
+func (b *BlastRadius) validate() error {
+	if b.MaxNodes < 0 || b.MaxPercent < 0 || b.MaxPercent > 100 {
+		return fmt.Errorf("%s: invalid BlastRadius %+v", errPrefix, *b)
+	}
+	return nil
+}
+
+// exceeded returns true if destroying n of the managed Nodes exceeds b.
+func (b *BlastRadius) exceeded(n, managed int) bool {
+	if b.MaxNodes > 0 && n > b.MaxNodes {
+		return true
+	}
+	return b.MaxPercent > 0 && managed > 0 && float64(n)*100 > b.MaxPercent*float64(managed)
+}
+
+// checkBlastRadius returns a *BlastRadiusError if the plan deletes or
+// recreates more of the managed Nodes than allowed by the config.
+func (pl *planner) checkBlastRadius(ctx context.Context) error {
+	b := pl.config.BlastRadius
+	// Stream() checks the blast radius when all of the Nodes are planned.
+	if b == nil || len(pl.pending) > 0 {
+		return nil
+	}
+	var (
+		ids     []*cloud.ResourceID
+		managed int
+	)
+	for _, n := range pl.want.All() {
+		if n.Ownership() != rnode.OwnershipManaged {
+			continue
+		}
+		managed++
+		switch n.Plan().Op() {
+		case rnode.OpDelete, rnode.OpRecreate:
+			ids = append(ids, n.ID())
+		}
+	}
+	if !b.exceeded(len(ids), managed) {
+		return nil
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i].String() < ids[j].String() })
+	err := &BlastRadiusError{IDs: ids, Managed: managed, Limit: *b}
+	if b.Force {
+		klog.FromContext(ctx).Info("Blast radius exceeded, continuing with Force", "err", err)
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,83 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plan
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/testing/ez"
+	"google.golang.org/api/compute/v1"
+)
+
+func TestBlastRadius(t *testing.T) {
+	ctx := context.Background()
+	mock := cloud.NewMockGCE(&cloud.SingleProjectRouter{ID: "proj"})
+	for _, name := range []string{"hc1", "hc2", "hc3", "hc4"} {
+		mock.HealthChecks().Insert(ctx, meta.GlobalKey(name), &compute.HealthCheck{})
+	}
+	// Deletes 3 of the 4 managed nodes.
+	g := ez.Graph{
+		Project: "proj",
+		Nodes: []ez.Node{
+			{Name: "hc1"},
+			{Name: "hc2", Options: ez.DoesNotExist},
+			{Name: "hc3", Options: ez.DoesNotExist},
+			{Name: "hc4", Options: ez.DoesNotExist},
+		},
+	}
+
+	for _, tc := range []struct {
+		name    string
+		b       BlastRadius
+		wantErr bool
+	}{
+		{name: "no limit"},
+		{name: "under MaxNodes", b: BlastRadius{MaxNodes: 3}},
+		{name: "over MaxNodes", b: BlastRadius{MaxNodes: 2}, wantErr: true},
+		{name: "under MaxPercent", b: BlastRadius{MaxPercent: 75}},
+		{name: "over MaxPercent", b: BlastRadius{MaxPercent: 50}, wantErr: true},
+		{name: "Force", b: BlastRadius{MaxNodes: 1, Force: true}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := Do(ctx, mock, g.Builder().MustBuild(), BlastRadiusOption(tc.b))
+			var brErr *BlastRadiusError
+			if gotErr := errors.As(err, &brErr); gotErr != tc.wantErr {
+				t.Fatalf("Do() = %v; errors.As(BlastRadiusError) = %t, want %t", err, gotErr, tc.wantErr)
+			}
+			if !tc.wantErr {
+				if err != nil {
+					t.Fatalf("Do() = %v, want nil", err)
+				}
+				return
+			}
+			if len(brErr.IDs) != 3 || brErr.Managed != 4 {
+				t.Errorf("brErr = %+v, want 3 IDs of 4 managed nodes", brErr)
+			}
+			if brErr.IDs[0].Key.Name != "hc2" {
+				t.Errorf("brErr.IDs[0] = %v, want hc2", brErr.IDs[0])
+			}
+		})
+	}
+
+	if _, err := Do(ctx, mock, g.Builder().MustBuild(), BlastRadiusOption(BlastRadius{MaxPercent: 101})); err == nil {
+		t.Error("Do(MaxPercent: 101) = nil, want error")
+	}
+}
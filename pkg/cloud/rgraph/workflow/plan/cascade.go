@@ -0,0 +1,92 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plan
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode"
+	"k8s.io/klog/v2"
+)
+
+// cascadeDeletes changes the dependents of deleted Nodes with
+// CascadeDelete() to be deleted as well. A dependent is deleted if all of the
+// references to it (in both "got" and "want") are from deleted Nodes. The
+// cascade is transitive.
+//
+// Only references within the graph are considered; use
+// exec.DeleteRefCheckOption to guard against references from resources
+// outside of the graph.
+func (pl *planner) cascadeDeletes(ctx context.Context) error {
+	deleted := map[cloud.ResourceMapKey]bool{}
+	var queue []*cloud.ResourceID
+	for _, n := range pl.want.All() {
+		if n.State() != rnode.NodeDoesNotExist {
+			continue
+		}
+		deleted[n.ID().MapKey()] = true
+		if n.CascadeDelete() {
+			queue = append(queue, n.ID())
+		}
+	}
+
+	exclusive := func(id *cloud.ResourceID) bool {
+		for _, ref := range append(pl.want.InRefs(id), pl.got.InRefs(id)...) {
+			if !deleted[ref.From.MapKey()] {
+				return false
+			}
+		}
+		return true
+	}
+
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+
+		gotNode := pl.got.Get(id)
+		if gotNode == nil || gotNode.State() != rnode.NodeExists {
+			continue
+		}
+		for _, ref := range gotNode.OutRefs() {
+			dep := pl.want.Get(ref.To)
+			switch {
+			case dep == nil || deleted[ref.To.MapKey()]:
+				continue
+			case dep.Ownership() != rnode.OwnershipManaged || dep.DeletionProtected():
+				continue
+			case !exclusive(ref.To):
+				continue
+			}
+			b := dep.Builder()
+			b.SetState(rnode.NodeDoesNotExist)
+			newNode, err := b.Build()
+			if err != nil {
+				return fmt.Errorf("%s: cascade delete %v: %w", errPrefix, ref.To, err)
+			}
+			if err := pl.want.Replace(newNode); err != nil {
+				return fmt.Errorf("%s: cascade delete %v: %w", errPrefix, ref.To, err)
+			}
+			klog.FromContext(ctx).V(2).Info("Cascade delete", "from", id, "to", ref.To)
+			pl.setDeleteCause(ref.To, fmt.Sprintf("node is cascade deleted with %v", id))
+			deleted[ref.To.MapKey()] = true
+			queue = append(queue, ref.To)
+		}
+	}
+	return nil
+}
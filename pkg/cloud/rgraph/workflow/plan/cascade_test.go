@@ -0,0 +1,109 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plan
+
+import (
+	"context"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/exec"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/testing/ez"
+)
+
+func TestCascadeDelete(t *testing.T) {
+	ctx := context.Background()
+
+	for _, tc := range []struct {
+		name    string
+		thpOpts ez.NodeOption
+		hcOpts  ez.NodeOption
+		withUM2 bool
+		wantOps map[string]rnode.Operation
+	}{
+		{
+			name:    "no cascade",
+			thpOpts: ez.DoesNotExist,
+			wantOps: map[string]rnode.Operation{"thp": rnode.OpDelete, "um": rnode.OpNothing, "bs": rnode.OpNothing, "hc": rnode.OpNothing},
+		},
+		{
+			name:    "cascade",
+			thpOpts: ez.DoesNotExist | ez.CascadeDelete,
+			wantOps: map[string]rnode.Operation{"thp": rnode.OpDelete, "um": rnode.OpDelete, "bs": rnode.OpDelete, "hc": rnode.OpDelete},
+		},
+		{
+			name:    "shared dependent",
+			thpOpts: ez.DoesNotExist | ez.CascadeDelete,
+			withUM2: true,
+			wantOps: map[string]rnode.Operation{"thp": rnode.OpDelete, "um": rnode.OpDelete, "bs": rnode.OpNothing, "hc": rnode.OpNothing},
+		},
+		{
+			name:    "protected dependent",
+			thpOpts: ez.DoesNotExist | ez.CascadeDelete,
+			hcOpts:  ez.DeletionProtected,
+			wantOps: map[string]rnode.Operation{"thp": rnode.OpDelete, "um": rnode.OpDelete, "bs": rnode.OpDelete, "hc": rnode.OpNothing},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			mock := cloud.NewMockGCE(&cloud.SingleProjectRouter{ID: "proj"})
+
+			graph := func(thpOpts, hcOpts ez.NodeOption) *ez.Graph {
+				g := &ez.Graph{
+					Project: "proj",
+					Nodes: []ez.Node{
+						{Name: "thp", Options: thpOpts, Refs: []ez.Ref{{Field: "UrlMap", To: "um"}}},
+						{Name: "um", Refs: []ez.Ref{{Field: "DefaultService", To: "bs"}}},
+						{Name: "bs", Refs: []ez.Ref{{Field: "Healthchecks", To: "hc"}}},
+						{Name: "hc", Options: hcOpts},
+					},
+				}
+				if tc.withUM2 {
+					g.Nodes = append(g.Nodes, ez.Node{Name: "um2", Refs: []ez.Ref{{Field: "DefaultService", To: "bs"}}})
+				}
+				return g
+			}
+
+			// Create the resources.
+			result, err := Do(ctx, mock, graph(0, 0).Builder().MustBuild())
+			if err != nil {
+				t.Fatalf("Do() = %v", err)
+			}
+			ex, err := exec.NewSerialExecutor(mock, result.Actions)
+			if err != nil {
+				t.Fatalf("NewSerialExecutor() = %v", err)
+			}
+			if _, err := ex.Run(ctx); err != nil {
+				t.Fatalf("Run() = %v", err)
+			}
+
+			result, err = Do(ctx, mock, graph(tc.thpOpts, tc.hcOpts).Builder().MustBuild())
+			if err != nil {
+				t.Fatalf("Do() = %v", err)
+			}
+			for _, n := range result.Want.All() {
+				want, ok := tc.wantOps[n.ID().Key.Name]
+				if !ok {
+					continue
+				}
+				if got := n.Plan().Op(); got != want {
+					t.Errorf("%s: Op() = %s, want %s", n.ID().Key.Name, got, want)
+				}
+			}
+		})
+	}
+}
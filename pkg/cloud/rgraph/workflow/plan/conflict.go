@@ -0,0 +1,204 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plan
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/api"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode"
+)
+
+// ConflictError is returned when a resource was changed outside of the graph
+// and the ConflictStrategy is ConflictFail.
+type ConflictError struct {
+	// ID of the resource.
+	ID *cloud.ResourceID
+	// Items that were changed externally.
+	Items []api.DiffItem
+}
+
+func (e *ConflictError) Error() string {
+	var paths []string
+	for _, item := range e.Items {
+		paths = append(paths, item.Path.String())
+	}
+	return fmt.Sprintf("%s: %v was changed outside of the graph (%s)", errPrefix, e.ID, strings.Join(paths, ", "))
+}
+
+// resolveConflicts applies the ConflictStrategy to the nodes that will be
+// updated.
+//
+// Without a record of the values that were last applied (see
+// LastAppliedOption), a field that has a non-zero value in got that differs
+// from want is considered to be an external change. Fields that are unset in
+// got are not conflicts.
+func (pl *planner) resolveConflicts() error {
+	var errs []error
+	for _, wantNode := range pl.want.All() {
+		switch wantNode.Plan().Op() {
+		case rnode.OpUpdate, rnode.OpRecreate:
+		default:
+			continue
+		}
+		details := wantNode.Plan().Details()
+		if details == nil || details.Diff == nil {
+			continue
+		}
+		conflicts, ok := pl.lastAppliedConflicts(wantNode, details.Diff.Items)
+		if !ok {
+			for _, item := range details.Diff.Items {
+				if isConflict(item) && item.Path.TopLevelField() != string(pl.config.LastApplied) {
+					conflicts = append(conflicts, item)
+				}
+			}
+		}
+		if len(conflicts) == 0 {
+			continue
+		}
+
+		strategy := wantNode.ConflictStrategy()
+		if strategy == rnode.ConflictDefault {
+			strategy = pl.config.ConflictStrategy
+		}
+		switch strategy {
+		case rnode.ConflictOverwrite:
+		case rnode.ConflictFail:
+			errs = append(errs, &ConflictError{ID: wantNode.ID(), Items: conflicts})
+		case rnode.ConflictPreserveExternal:
+			if err := pl.preserveExternal(wantNode, conflicts); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("%s: node %v has invalid ConflictStrategy %q", errPrefix, wantNode.ID(), strategy)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// isConflict returns true if the item changes a value that is set in got.
+func isConflict(item api.DiffItem) bool {
+	if item.State == api.DiffItemOnlyInB || item.A == nil {
+		return false
+	}
+	return !reflect.ValueOf(item.A).IsZero()
+}
+
+// preserveExternal replaces wantNode with a node that has the top-level
+// fields of the conflicts copied from the got resource and replans it.
+func (pl *planner) preserveExternal(wantNode rnode.Node, conflicts []api.DiffItem) error {
+	gotNode := pl.got.Get(wantNode.ID())
+
+	fieldSet := map[string]bool{}
+	for _, item := range conflicts {
+		if f := item.Path.TopLevelField(); f != "" {
+			fieldSet[f] = true
+		}
+	}
+	var fields []string
+	for f := range fieldSet {
+		fields = append(fields, f)
+	}
+	sort.Strings(fields)
+
+	copier, ok := wantNode.Resource().(api.FieldCopier)
+	if !ok {
+		return fmt.Errorf("%s: %v: PreserveExternal is not supported for %T", errPrefix, wantNode.ID(), wantNode.Resource())
+	}
+	merged, err := copier.WithFieldsFrom(gotNode.Resource(), fields)
+	if err != nil {
+		return fmt.Errorf("%s: %v: %w", errPrefix, wantNode.ID(), err)
+	}
+
+	in := wantNode.Plan().Details().Inputs
+	newNode, err := pl.replaceResource(wantNode, merged)
+	if err != nil {
+		return fmt.Errorf("cannot preserve external changes to %v: %w", fields, err)
+	}
+
+	details, err := newNode.Diff(gotNode)
+	if err != nil {
+		return fmt.Errorf("%s: %w", errPrefix, err)
+	}
+	details.Why = fmt.Sprintf("Preserving external changes to %v; %s", fields, details.Why)
+	in.HasDiff = details.Diff != nil && details.Diff.HasDiff()
+	if t, ok := rnode.LookupTransition(in, details.Operation); ok {
+		details.Verdict = t.Verdict
+	}
+	details.Inputs = in
+	newNode.Plan().Set(*details)
+	return nil
+}
+
+// replaceResource replaces wantNode in the "want" graph with a Node that has
+// the resource r. The new Node is returned without a plan.
+func (pl *planner) replaceResource(wantNode rnode.Node, r any) (rnode.Node, error) {
+	return replaceNodeResource(pl.want, wantNode, r)
+}
+
+// replaceNodeResource replaces wantNode in g with a Node that has the
+// resource r.
+func replaceNodeResource(g *rgraph.Graph, wantNode rnode.Node, r any) (rnode.Node, error) {
+	res, ok := r.(rnode.UntypedResource)
+	if !ok {
+		return nil, fmt.Errorf("%s: %v: invalid resource %T", errPrefix, wantNode.ID(), r)
+	}
+	b := wantNode.Builder()
+	if err := b.SetResource(res); err != nil {
+		return nil, fmt.Errorf("%s: %v: %w", errPrefix, wantNode.ID(), err)
+	}
+	for _, ref := range wantNode.InRefs() {
+		b.AddInRef(ref)
+	}
+	newNode, err := b.Build()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v: %w", errPrefix, wantNode.ID(), err)
+	}
+	// The graph structure is fixed at this point, so changing a reference is
+	// not possible.
+	if !sameRefs(wantNode.OutRefs(), newNode.OutRefs()) {
+		return nil, fmt.Errorf("%s: %v: references cannot be changed", errPrefix, wantNode.ID())
+	}
+	if err := g.Replace(newNode); err != nil {
+		return nil, err
+	}
+	return newNode, nil
+}
+
+// sameRefs returns true if a and b have the same set of references.
+func sameRefs(a, b []rnode.ResourceRef) bool {
+	key := func(r rnode.ResourceRef) string { return r.Path.String() + "=" + r.To.String() }
+	set := map[string]int{}
+	for _, r := range a {
+		set[key(r)]++
+	}
+	for _, r := range b {
+		set[key(r)]--
+	}
+	for _, v := range set {
+		if v != 0 {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,106 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plan
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/exec"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode"
+)
+
+// CostAnnotationKey is the Action annotation with the estimated monthly cost
+// delta of the Action. The value is formatted with two decimal places, e.g.
+// "18.25" for a create and "-18.25" for a delete.
+const CostAnnotationKey = "rgraph-estimated-monthly-cost-delta"
+
+// PricingProvider estimates the monthly cost of resources. The currency is
+// up to the implementation.
+type PricingProvider interface {
+	// MonthlyCost of the resource in Node n. ok is false if the cost of the
+	// resource is not known.
+	MonthlyCost(ctx context.Context, n rnode.Node) (cost float64, ok bool, err error)
+}
+
+// StaticPricing is a PricingProvider with a fixed monthly cost for each type
+// of resource, keyed by the ResourceID.Resource (e.g. "forwardingRules",
+// "targetHttpProxies").
+type StaticPricing map[string]float64
+
+// MonthlyCost implements PricingProvider.
+func (p StaticPricing) MonthlyCost(_ context.Context, n rnode.Node) (float64, bool, error) {
+	cost, ok := p[n.ID().Resource]
+	return cost, ok, nil
+}
+
+// CostOption annotates the planned create and delete Actions with the
+// estimated monthly cost delta from p (see CostAnnotationKey). The total is
+// returned in Result.EstimatedMonthlyCostDelta. Updates are assumed not to
+// change the cost.
+func CostOption(p PricingProvider) Option {
+	return func(c *Config) { c.Pricing = p }
+}
+
+// addCostEstimates annotates the Actions with the cost delta from the
+// configured PricingProvider. Returns the annotated Actions and the total
+// delta.
+func (pl *planner) addCostEstimates(ctx context.Context, acts []exec.Action) ([]exec.Action, float64, error) {
+	if pl.config.Pricing == nil {
+		return acts, 0, nil
+	}
+	var (
+		ret   []exec.Action
+		total float64
+	)
+	for _, a := range acts {
+		md := a.Metadata()
+		if md.ResourceID == nil {
+			ret = append(ret, a)
+			continue
+		}
+		var (
+			n    rnode.Node
+			sign float64
+		)
+		switch md.Type {
+		case exec.ActionTypeCreate:
+			n, sign = pl.want.Get(md.ResourceID), 1
+		case exec.ActionTypeDelete:
+			n, sign = pl.got.Get(md.ResourceID), -1
+		}
+		if n == nil {
+			ret = append(ret, a)
+			continue
+		}
+		cost, ok, err := pl.config.Pricing.MonthlyCost(ctx, n)
+		if err != nil {
+			return nil, 0, fmt.Errorf("%s: cost of %v: %w", errPrefix, md.ResourceID, err)
+		}
+		if !ok {
+			ret = append(ret, a)
+			continue
+		}
+		delta := sign * cost
+		total += delta
+		ret = append(ret, exec.WithAnnotations(a, map[string]string{
+			CostAnnotationKey: strconv.FormatFloat(delta, 'f', 2, 64),
+		}))
+	}
+	return ret, total, nil
+}
@@ -0,0 +1,75 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plan
+
+import (
+	"context"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/testing/ez"
+	"google.golang.org/api/compute/v1"
+)
+
+func TestCostOption(t *testing.T) {
+	ctx := context.Background()
+	mock := cloud.NewMockGCE(&cloud.SingleProjectRouter{ID: "proj"})
+	mock.HealthChecks().Insert(ctx, meta.GlobalKey("hc-old"), &compute.HealthCheck{})
+
+	g := ez.Graph{
+		Project: "proj",
+		Nodes: []ez.Node{
+			{Name: "fr"},
+			{Name: "hc-old", Options: ez.DoesNotExist},
+		},
+	}
+	pricing := StaticPricing{"forwardingRules": 18.25, "healthChecks": 1}
+	result, err := Do(ctx, mock, g.Builder().MustBuild(), CostOption(pricing))
+	if err != nil {
+		t.Fatalf("Do() = %v", err)
+	}
+	if got, want := result.EstimatedMonthlyCostDelta, 17.25; got != want {
+		t.Errorf("EstimatedMonthlyCostDelta = %v, want %v", got, want)
+	}
+
+	got := map[string]string{}
+	for _, a := range result.Actions {
+		md := a.Metadata()
+		if v, ok := md.Annotations[CostAnnotationKey]; ok {
+			got[md.ResourceID.Key.Name] = v
+		}
+	}
+	want := map[string]string{"fr": "18.25", "hc-old": "-1.00"}
+	if len(got) != len(want) {
+		t.Fatalf("annotations = %v, want %v", got, want)
+	}
+	for k, v := range want {
+		if got[k] != v {
+			t.Errorf("annotations[%s] = %q, want %q", k, got[k], v)
+		}
+	}
+
+	// No pricing, no estimate.
+	result, err = Do(ctx, mock, g.Builder().MustBuild())
+	if err != nil {
+		t.Fatalf("Do() = %v", err)
+	}
+	if result.EstimatedMonthlyCostDelta != 0 {
+		t.Errorf("EstimatedMonthlyCostDelta = %v, want 0", result.EstimatedMonthlyCostDelta)
+	}
+}
@@ -0,0 +1,98 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plan
+
+import (
+	"fmt"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/api"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode"
+)
+
+// DeletionProtectedKey is the cloud.Description value name that records that
+// the resource is deletion protected.
+//
+// The value is stored in the Description of the resources that are
+// DeletionProtected in "want" and read back into the "got" graph. This
+// protects a resource that is dropped from "want" (e.g. by mistake), as the
+// Node that deletes it is built from "got". A Node in "want" overrides the
+// stored value: to delete a protected resource, add it to "want" with
+// SetDeletionProtected(false) and NodeDoesNotExist.
+//
+// The Description metadata is ignored in a diff (api.FieldTypeDescription),
+// so the value is stored with the next create or update of the resource.
+const DeletionProtectedKey = "rgraph-deletion-protected"
+
+// StoredDeletionProtected returns true if the resource of nb has the stored
+// DeletionProtectedKey, i.e. the resource was DeletionProtected in "want"
+// when it was last created or updated. Workflows that delete resources that
+// are not in "want" (e.g. package sweep) must not delete these resources.
+func StoredDeletionProtected(nb rnode.Builder) bool {
+	if nb.State() != rnode.NodeExists || nb.Resource() == nil {
+		return false
+	}
+	fa, ok := nb.Resource().(api.FieldAccessor)
+	if !ok {
+		return false
+	}
+	v, err := fa.Field(string(LastAppliedDescription))
+	if err != nil {
+		// The resource does not support the field.
+		return false
+	}
+	_, stored := stripStored(LastAppliedDescription, DeletionProtectedKey, v)
+	return stored != ""
+}
+
+// syncDeletionProtection sets DeletionProtected on the Builders whose
+// resource in the Cloud has the stored DeletionProtectedKey.
+func syncDeletionProtection(gotBuilder *rgraph.Builder) {
+	for _, nb := range gotBuilder.All() {
+		if StoredDeletionProtected(nb) {
+			nb.SetDeletionProtected(true)
+		}
+	}
+}
+
+// stampDeletionProtection adds the DeletionProtectedKey to the Description
+// of the protected resources in "want".
+func (pl *planner) stampDeletionProtection() error {
+	for _, wantNode := range pl.want.All() {
+		if !wantNode.DeletionProtected() || wantNode.Ownership() != rnode.OwnershipManaged ||
+			wantNode.State() != rnode.NodeExists || wantNode.Resource() == nil {
+			continue
+		}
+		fa, ok := wantNode.Resource().(api.FieldAccessor)
+		if !ok {
+			continue
+		}
+		v, err := fa.Field(string(LastAppliedDescription))
+		if err != nil {
+			// The resource does not support the field.
+			continue
+		}
+		r, err := fa.WithField(string(LastAppliedDescription), withStored(LastAppliedDescription, DeletionProtectedKey, v, "true"))
+		if err != nil {
+			return fmt.Errorf("%s: %v: %w", errPrefix, wantNode.ID(), err)
+		}
+		if _, err := pl.replaceResource(wantNode, r); err != nil {
+			return err
+		}
+	}
+	return nil
+}
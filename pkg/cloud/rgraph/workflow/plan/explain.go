@@ -0,0 +1,135 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plan
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/api"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/exec"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode"
+)
+
+// ExplainAnnotationKey is the Action annotation with the reasons the Action
+// was planned, separated by "; ". See ExplainOption.
+const ExplainAnnotationKey = "rgraph-explain"
+
+// ExplainOption records human-readable reasons for the plan of each Node,
+// e.g. "field Backends[0].MaxConnections changed 10→20" or "node marked
+// NodeDoesNotExist by caller". The reasons are attached to the Actions of the
+// Node (see ExplainAnnotationKey) and returned by Result.Explain().
+func ExplainOption() Option {
+	return func(c *Config) { c.Explain = true }
+}
+
+// Explain returns the reasons for the plan of the resource named by id.
+// Returns nil if the plan was not done with ExplainOption or the resource is
+// not in the plan.
+func (r *Result) Explain(id *cloud.ResourceID) []string {
+	return r.explanations[id.MapKey()]
+}
+
+// addExplanations annotates the Actions with the reasons for the plan of
+// their Node. Returns the annotated Actions and the reasons for all of the
+// Nodes.
+func (pl *planner) addExplanations(acts []exec.Action) ([]exec.Action, map[cloud.ResourceMapKey][]string) {
+	if !pl.config.Explain {
+		return acts, nil
+	}
+	explanations := map[cloud.ResourceMapKey][]string{}
+	for _, n := range pl.want.All() {
+		explanations[n.ID().MapKey()] = pl.explain(n)
+	}
+	var ret []exec.Action
+	for _, a := range acts {
+		if id := a.Metadata().ResourceID; id != nil {
+			if reasons := explanations[id.MapKey()]; len(reasons) > 0 {
+				a = exec.WithAnnotations(a, map[string]string{
+					ExplainAnnotationKey: strings.Join(reasons, "; "),
+				})
+			}
+		}
+		ret = append(ret, a)
+	}
+	return ret, explanations
+}
+
+// explain returns the reasons for the plan of Node n in "want".
+func (pl *planner) explain(n rnode.Node) []string {
+	var reasons []string
+	if n.State() == rnode.NodeDoesNotExist {
+		if why, ok := pl.deleteCauses[n.ID().MapKey()]; ok {
+			reasons = append(reasons, why)
+		} else {
+			reasons = append(reasons, "node marked NodeDoesNotExist by caller")
+		}
+	}
+	var diff *api.DiffResult
+	for _, details := range n.Plan().History() {
+		r := fmt.Sprintf("%s: %s", details.Operation, details.Why)
+		if len(reasons) == 0 || reasons[len(reasons)-1] != r {
+			reasons = append(reasons, r)
+		}
+		if details.Diff != nil {
+			diff = details.Diff
+		}
+	}
+	if diff != nil {
+		for _, item := range diff.Items {
+			reasons = append(reasons, explainDiffItem(item))
+		}
+	}
+	return reasons
+}
+
+// explainDiffItem returns a description of the change to the field in item.
+// A is the value in the Cloud, B is the wanted value.
+func explainDiffItem(item api.DiffItem) string {
+	field := explainPath(item.Path)
+	switch item.State {
+	case api.DiffItemOnlyInA:
+		return fmt.Sprintf("field %s removed (was %v)", field, item.A)
+	case api.DiffItemOnlyInB:
+		return fmt.Sprintf("field %s added (%v)", field, item.B)
+	default:
+		return fmt.Sprintf("field %s changed %v→%v", field, item.A, item.B)
+	}
+}
+
+// explainPath formats p in Go syntax without the pointer dereferences, e.g.
+// "Backends[0].MaxConnections".
+func explainPath(p api.Path) string {
+	var b strings.Builder
+	for _, elem := range p {
+		if elem == "" {
+			continue
+		}
+		switch elem[0] {
+		case '.':
+			if b.Len() > 0 {
+				b.WriteString(elem)
+			} else {
+				b.WriteString(elem[1:])
+			}
+		case '!', ':':
+			fmt.Fprintf(&b, "[%s]", elem[1:])
+		}
+	}
+	return b.String()
+}
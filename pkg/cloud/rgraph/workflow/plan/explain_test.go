@@ -0,0 +1,130 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plan
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/api"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/mock"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/exec"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/healthcheck"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/testing/ez"
+	"google.golang.org/api/compute/v1"
+)
+
+func TestExplainOption(t *testing.T) {
+	ctx := context.Background()
+	mockCloud := cloud.NewMockGCE(&cloud.SingleProjectRouter{ID: "proj"})
+	mockCloud.MockHealthChecks.UpdateHook = mock.UpdateHealthCheckHook
+	mockCloud.HealthChecks().Insert(ctx, meta.GlobalKey("hc-old"), &compute.HealthCheck{})
+
+	hcNode := func(interval int64) ez.Node {
+		return ez.Node{
+			Name:      "hc",
+			SetupFunc: func(x *compute.HealthCheck) { x.CheckIntervalSec = interval },
+		}
+	}
+	g := ez.Graph{Project: "proj", Nodes: []ez.Node{hcNode(5)}}
+	result, err := Do(ctx, mockCloud, g.Builder().MustBuild())
+	if err != nil {
+		t.Fatalf("Do() = %v", err)
+	}
+	ex, err := exec.NewSerialExecutor(mockCloud, result.Actions)
+	if err != nil {
+		t.Fatalf("NewSerialExecutor() = %v", err)
+	}
+	if _, err := ex.Run(ctx); err != nil {
+		t.Fatalf("Run() = %v", err)
+	}
+
+	g = ez.Graph{
+		Project: "proj",
+		Nodes: []ez.Node{
+			hcNode(10),
+			{Name: "hc-old", Options: ez.DoesNotExist},
+		},
+	}
+	result, err = Do(ctx, mockCloud, g.Builder().MustBuild(), ExplainOption())
+	if err != nil {
+		t.Fatalf("Do() = %v", err)
+	}
+
+	hcID := healthcheck.ID("proj", meta.GlobalKey("hc"))
+	oldID := healthcheck.ID("proj", meta.GlobalKey("hc-old"))
+	for _, tc := range []struct {
+		id   *cloud.ResourceID
+		want []string
+	}{
+		{id: hcID, want: []string{"Update: ", "field CheckIntervalSec changed 5→10"}},
+		{id: oldID, want: []string{"node marked NodeDoesNotExist by caller", "Delete: "}},
+	} {
+		got := strings.Join(result.Explain(tc.id), "; ")
+		for _, w := range tc.want {
+			if !strings.Contains(got, w) {
+				t.Errorf("Explain(%v) = %q, want substring %q", tc.id, got, w)
+			}
+		}
+		var annotated bool
+		for _, a := range result.Actions {
+			md := a.Metadata()
+			if md.ResourceID == nil || md.ResourceID.MapKey() != tc.id.MapKey() {
+				continue
+			}
+			if v := md.Annotations[ExplainAnnotationKey]; v != got {
+				t.Errorf("Annotations[%s] for %s = %q, want %q", ExplainAnnotationKey, md.Name, v, got)
+			}
+			annotated = true
+		}
+		if !annotated {
+			t.Errorf("no Actions for %v", tc.id)
+		}
+	}
+
+	// Without the option, nothing is explained.
+	result, err = Do(ctx, mockCloud, g.Builder().MustBuild())
+	if err != nil {
+		t.Fatalf("Do() = %v", err)
+	}
+	if got := result.Explain(hcID); got != nil {
+		t.Errorf("Explain(%v) = %v, want nil", hcID, got)
+	}
+	for _, a := range result.Actions {
+		if v, ok := a.Metadata().Annotations[ExplainAnnotationKey]; ok {
+			t.Errorf("Annotations[%s] for %s = %q, want none", ExplainAnnotationKey, a.Metadata().Name, v)
+		}
+	}
+}
+
+func TestExplainPath(t *testing.T) {
+	for _, tc := range []struct {
+		p    api.Path
+		want string
+	}{
+		{p: api.Path{}.Pointer().Field("Name"), want: "Name"},
+		{p: api.Path{}.Pointer().Field("Backends").Index(0).Pointer().Field("MaxConnections"), want: "Backends[0].MaxConnections"},
+		{p: api.Path{}.Pointer().Field("Labels").MapIndex("k"), want: "Labels[k]"},
+	} {
+		if got := explainPath(tc.p); got != tc.want {
+			t.Errorf("explainPath(%v) = %q, want %q", tc.p, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,209 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plan
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/api"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/exec"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode"
+	"k8s.io/klog/v2"
+)
+
+// IdempotencyKeyName is the label key (or cloud.Description value name) for
+// the idempotency key.
+const IdempotencyKeyName = "rgraph-idempotency-key"
+
+// IdempotencyOption stamps an idempotency key in field f of each resource
+// that is created, updated or recreated by the plan. The key is derived from
+// planID, the resource ID and the diff. Before running a mutation, the
+// Action reads the key from the resource in the Cloud and skips the
+// mutation if the key matches, so running the Actions of the same plan
+// again (e.g. after a crash) does not repeat the mutations that were already
+// applied. planID must be unique for each plan that is executed.
+//
+// The stored key is ignored when diffing, so it does not cause updates by
+// itself.
+func IdempotencyOption(planID string, f LastAppliedField) Option {
+	return func(c *Config) {
+		c.IdempotencyPlanID = planID
+		c.IdempotencyField = f
+	}
+}
+
+// idempotencyKey for the mutation of the resource id with the given diff.
+// The key is a valid label value.
+func idempotencyKey(planID string, id *cloud.ResourceID, diff *api.DiffResult) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\n%s\n", planID, id)
+	if diff != nil {
+		for _, item := range diff.Items {
+			fmt.Fprintf(h, "%s %v %v\n", item.Path, item.State, item.B)
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil))[:32]
+}
+
+// stripIdempotencyKeys removes the stored idempotency keys from the
+// resources in the "got" graph so that they do not show up in the diff.
+func (pl *planner) stripIdempotencyKeys() error {
+	f := pl.config.IdempotencyField
+	if pl.config.IdempotencyPlanID == "" {
+		return nil
+	}
+	for _, gotNode := range pl.got.All() {
+		if gotNode.State() != rnode.NodeExists || gotNode.Resource() == nil {
+			continue
+		}
+		fa, ok := gotNode.Resource().(api.FieldAccessor)
+		if !ok {
+			continue
+		}
+		v, err := fa.Field(string(f))
+		if err != nil {
+			// The resource does not support the field.
+			continue
+		}
+		stripped, stored := stripStored(f, IdempotencyKeyName, v)
+		if stored == "" {
+			continue
+		}
+		r, err := fa.WithField(string(f), stripped)
+		if err != nil {
+			return fmt.Errorf("%s: %v: %w", errPrefix, gotNode.ID(), err)
+		}
+		if _, err := replaceNodeResource(pl.got, gotNode, r); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// stampIdempotencyKeys adds the idempotency key to the resources that will be
+// created, updated or recreated. This must be called after the plan for the
+// Nodes is final.
+func (pl *planner) stampIdempotencyKeys() error {
+	f := pl.config.IdempotencyField
+	if pl.config.IdempotencyPlanID == "" {
+		return nil
+	}
+	pl.idempotencyKeys = map[cloud.ResourceMapKey]string{}
+
+	for _, wantNode := range pl.want.All() {
+		switch wantNode.Plan().Op() {
+		case rnode.OpCreate, rnode.OpUpdate, rnode.OpRecreate:
+		default:
+			continue
+		}
+		fa, ok := wantNode.Resource().(api.FieldAccessor)
+		if !ok {
+			continue
+		}
+		v, err := fa.Field(string(f))
+		if err != nil {
+			// The resource does not support the field.
+			continue
+		}
+		details := wantNode.Plan().Details()
+		key := idempotencyKey(pl.config.IdempotencyPlanID, wantNode.ID(), details.Diff)
+		r, err := fa.WithField(string(f), withStored(f, IdempotencyKeyName, v, key))
+		if err != nil {
+			return fmt.Errorf("%s: %v: %w", errPrefix, wantNode.ID(), err)
+		}
+		newNode, err := pl.replaceResource(wantNode, r)
+		if err != nil {
+			return err
+		}
+		newNode.Plan().Set(*details)
+		pl.idempotencyKeys[wantNode.ID().MapKey()] = key
+	}
+	return nil
+}
+
+// addIdempotencyChecks wraps the mutation Actions for the Nodes with an
+// idempotency key. The delete Action of a recreate is also wrapped as the
+// resource must not be deleted again once it has been recreated.
+func (pl *planner) addIdempotencyChecks(acts []exec.Action) []exec.Action {
+	if len(pl.idempotencyKeys) == 0 {
+		return acts
+	}
+	var ret []exec.Action
+	for _, a := range acts {
+		m := a.Metadata()
+		if m.ResourceID == nil {
+			ret = append(ret, a)
+			continue
+		}
+		key, ok := pl.idempotencyKeys[m.ResourceID.MapKey()]
+		n := pl.want.Get(m.ResourceID)
+		switch {
+		case !ok || n == nil:
+		case m.Type == exec.ActionTypeCreate, m.Type == exec.ActionTypeUpdate,
+			m.Type == exec.ActionTypeDelete && n.Plan().Op() == rnode.OpRecreate:
+			a = &idempotentAction{Action: a, node: n, field: pl.config.IdempotencyField, key: key}
+		}
+		ret = append(ret, a)
+	}
+	return ret
+}
+
+// idempotentAction skips the wrapped Action if the resource in the Cloud
+// already has the idempotency key.
+type idempotentAction struct {
+	exec.Action
+	node  rnode.Node
+	field LastAppliedField
+	key   string
+}
+
+func (a *idempotentAction) Run(ctx context.Context, c cloud.Cloud) (exec.EventList, error) {
+	stored, err := a.storedKey(ctx, c)
+	if err != nil {
+		return nil, err
+	}
+	if stored == a.key {
+		klog.FromContext(ctx).V(2).Info("Skipping action, already applied", "action", a.Metadata().Name, "key", a.key)
+		return a.Action.DryRun(), nil
+	}
+	return a.Action.Run(ctx, c)
+}
+
+// storedKey returns the idempotency key of the resource in the Cloud.
+func (a *idempotentAction) storedKey(ctx context.Context, c cloud.Cloud) (string, error) {
+	b := a.node.Builder()
+	if err := b.SyncFromCloud(ctx, c); err != nil {
+		return "", fmt.Errorf("idempotency check for %v: %w", a.node.ID(), err)
+	}
+	if b.State() != rnode.NodeExists {
+		return "", nil
+	}
+	fa, ok := b.Resource().(api.FieldAccessor)
+	if !ok {
+		return "", nil
+	}
+	v, err := fa.Field(string(a.field))
+	if err != nil {
+		return "", nil
+	}
+	_, stored := stripStored(a.field, IdempotencyKeyName, v)
+	return stored, nil
+}
@@ -0,0 +1,182 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plan
+
+import (
+	"context"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/mock"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/exec"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/healthcheck"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/testing/ez"
+	"google.golang.org/api/compute/v1"
+)
+
+func TestIdempotency(t *testing.T) {
+	ctx := context.Background()
+	mockCloud := cloud.NewMockGCE(&cloud.SingleProjectRouter{ID: "proj"})
+	mockCloud.MockHealthChecks.UpdateHook = mock.UpdateHealthCheckHook
+	key := meta.GlobalKey("hc")
+	hcID := healthcheck.ID("proj", key)
+
+	graph := func(interval int64) *ez.Graph {
+		return &ez.Graph{
+			Project: "proj",
+			Nodes: []ez.Node{{
+				Name: "hc",
+				SetupFunc: func(x *compute.HealthCheck) {
+					x.Description = "user description"
+					x.CheckIntervalSec = interval
+				},
+			}},
+		}
+	}
+	run := func(acts []exec.Action) {
+		t.Helper()
+		ex, err := exec.NewSerialExecutor(mockCloud, acts)
+		if err != nil {
+			t.Fatalf("NewSerialExecutor() = %v", err)
+		}
+		if _, err := ex.Run(ctx); err != nil {
+			t.Fatalf("Run() = %v", err)
+		}
+	}
+	storedKey := func() string {
+		t.Helper()
+		hc, err := mockCloud.HealthChecks().Get(ctx, key)
+		if err != nil {
+			t.Fatalf("Get() = %v", err)
+		}
+		_, stored := stripStored(LastAppliedDescription, IdempotencyKeyName, hc.Description)
+		return stored
+	}
+
+	for i, tc := range []struct {
+		interval int64
+		wantOp   rnode.Operation
+	}{
+		{interval: 5, wantOp: rnode.OpCreate},
+		{interval: 10, wantOp: rnode.OpUpdate},
+	} {
+		planID := []string{"plan-1", "plan-2"}[i]
+		result, err := Do(ctx, mockCloud, graph(tc.interval).Builder().MustBuild(), IdempotencyOption(planID, LastAppliedDescription))
+		if err != nil {
+			t.Fatalf("Do() = %v", err)
+		}
+		if op := result.Want.Get(hcID).Plan().Op(); op != tc.wantOp {
+			t.Fatalf("Op() = %s, want %s", op, tc.wantOp)
+		}
+		run(result.Actions)
+		first := storedKey()
+		if first == "" {
+			t.Fatalf("no idempotency key stored after %s", tc.wantOp)
+		}
+		// Running the same Actions again is a no-op. Without the key, the
+		// create would fail as the resource exists.
+		mutated := false
+		mockCloud.MockHealthChecks.InsertHook = func(context.Context, *meta.Key, *compute.HealthCheck, *cloud.MockHealthChecks, ...cloud.Option) (bool, error) {
+			mutated = true
+			return false, nil
+		}
+		mockCloud.MockHealthChecks.UpdateHook = func(context.Context, *meta.Key, *compute.HealthCheck, *cloud.MockHealthChecks, ...cloud.Option) error {
+			mutated = true
+			return nil
+		}
+		run(result.Actions)
+		if mutated {
+			t.Errorf("%s was repeated", tc.wantOp)
+		}
+		mockCloud.MockHealthChecks.InsertHook = nil
+		mockCloud.MockHealthChecks.UpdateHook = mock.UpdateHealthCheckHook
+	}
+
+	// The stored key does not cause a diff.
+	result, err := Do(ctx, mockCloud, graph(10).Builder().MustBuild(), IdempotencyOption("plan-3", LastAppliedDescription))
+	if err != nil {
+		t.Fatalf("Do() = %v", err)
+	}
+	if op := result.Want.Get(hcID).Plan().Op(); op != rnode.OpNothing {
+		t.Errorf("Op() = %s, want %s", op, rnode.OpNothing)
+	}
+	hc, _ := mockCloud.HealthChecks().Get(ctx, key)
+	if d, err := cloud.ParseDescription(hc.Description); err != nil || d.Text != "user description" || d.Values[IdempotencyKeyName] == "" {
+		t.Errorf("Description = %q, want user description with the key", hc.Description)
+	}
+}
+
+func TestIdempotencyWithLastApplied(t *testing.T) {
+	ctx := context.Background()
+	mockCloud := cloud.NewMockGCE(&cloud.SingleProjectRouter{ID: "proj"})
+	mockCloud.MockHealthChecks.UpdateHook = mock.UpdateHealthCheckHook
+	key := meta.GlobalKey("hc")
+	hcID := healthcheck.ID("proj", key)
+
+	graph := func(interval int64) *ez.Graph {
+		return &ez.Graph{
+			Project: "proj",
+			Nodes: []ez.Node{{
+				Name: "hc",
+				SetupFunc: func(x *compute.HealthCheck) {
+					x.Description = "user description"
+					x.CheckIntervalSec = interval
+				},
+			}},
+		}
+	}
+	fail := ConflictStrategyOption(rnode.ConflictFail)
+	lastApplied := LastAppliedOption(LastAppliedDescription)
+
+	// Both are stored in the Description metadata.
+	for i, tc := range []struct {
+		interval int64
+		wantOp   rnode.Operation
+	}{
+		{interval: 5, wantOp: rnode.OpCreate},
+		{interval: 5, wantOp: rnode.OpNothing},
+		// Not a conflict as the last applied hash matches.
+		{interval: 10, wantOp: rnode.OpUpdate},
+	} {
+		planID := []string{"plan-1", "plan-2", "plan-3"}[i]
+		result, err := Do(ctx, mockCloud, graph(tc.interval).Builder().MustBuild(), fail, lastApplied, IdempotencyOption(planID, LastAppliedDescription))
+		if err != nil {
+			t.Fatalf("Do(%s) = %v", planID, err)
+		}
+		if op := result.Want.Get(hcID).Plan().Op(); op != tc.wantOp {
+			t.Fatalf("Do(%s): Op() = %s, want %s", planID, op, tc.wantOp)
+		}
+		ex, err := exec.NewSerialExecutor(mockCloud, result.Actions)
+		if err != nil {
+			t.Fatalf("NewSerialExecutor() = %v", err)
+		}
+		if _, err := ex.Run(ctx); err != nil {
+			t.Fatalf("Run() = %v", err)
+		}
+	}
+
+	hc, err := mockCloud.HealthChecks().Get(ctx, key)
+	if err != nil {
+		t.Fatalf("Get() = %v", err)
+	}
+	d, err := cloud.ParseDescription(hc.Description)
+	if err != nil || d.Text != "user description" || d.Values[LastAppliedKey] == "" || d.Values[IdempotencyKeyName] == "" {
+		t.Errorf("Description = %q, want user description with the hash and the key", hc.Description)
+	}
+}
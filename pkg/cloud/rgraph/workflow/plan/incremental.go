@@ -0,0 +1,139 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plan
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/algo/traversal"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/algo/trclosure"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode"
+	"k8s.io/klog/v2"
+)
+
+// DoIncremental plans want, reusing the Result of a previous plan for the
+// Nodes that have not changed. This is for controllers that reconcile on
+// fine-grained events (e.g. from an informer) where only a few resources in
+// a large graph change.
+//
+// changed are the IDs of the resources that changed since prev, either in
+// want or in the Cloud. The changed resources and the resources that
+// reference them (transitively) are fetched again from the Cloud and diffed.
+// The other Nodes reuse the state from prev.Got and the local plan from
+// prev. Nodes in want that are not in prev are always planned.
+//
+// The caller must ensure that the Nodes not in changed are the same in want
+// as they were when prev was planned. If prev is nil, this is equivalent to
+// Do().
+func DoIncremental(ctx context.Context, c cloud.Cloud, want *rgraph.Graph, prev *Result, changed []*cloud.ResourceID, opts ...Option) (*Result, error) {
+	if prev == nil || prev.local == nil {
+		return Do(ctx, c, want, opts...)
+	}
+	config, err := makeConfig(opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	dirty, err := dirtyNodes(want, prev, changed)
+	if err != nil {
+		return nil, err
+	}
+
+	// The "got" graph starts with the state of the Nodes in prev. Nodes that
+	// are not dirty are not fetched again.
+	gotBuilder := rgraph.NewBuilder()
+	for _, n := range prev.Got.All() {
+		nb := n.Builder()
+		if r := n.Resource(); r != nil && !dirty[n.ID().MapKey()] {
+			// Node.Builder() does not copy the resource.
+			if err := nb.SetResource(r); err != nil {
+				return nil, fmt.Errorf("%s: %w", errPrefix, err)
+			}
+		}
+		gotBuilder.Add(nb)
+	}
+	for _, n := range want.All() {
+		if gotBuilder.Get(n.ID()) == nil {
+			gotBuilder.Add(n.Builder())
+		}
+	}
+	synced := trclosure.SyncedFunc(func(n rnode.Builder) bool {
+		return !dirty[n.ID().MapKey()]
+	})
+
+	reuse := map[cloud.ResourceMapKey]bool{}
+	for _, n := range prev.Got.All() {
+		if !dirty[n.ID().MapKey()] {
+			reuse[n.ID().MapKey()] = true
+		}
+	}
+
+	klog.FromContext(ctx).V(2).Info("Incremental plan", "changed", len(changed), "dirty", len(dirty), "reused", len(reuse))
+
+	pl := planner{
+		cloud:  c,
+		want:   want,
+		config: config,
+		prev:   prev,
+		reuse:  reuse,
+	}
+	if err := pl.sync(ctx, gotBuilder, synced); err != nil {
+		return nil, err
+	}
+	return pl.planGot(ctx)
+}
+
+// dirtyNodes returns the set of Nodes that need to be planned again: the
+// changed Nodes, the Nodes that are not in prev and all of the Nodes that
+// reference them in either want or prev.Want.
+func dirtyNodes(want *rgraph.Graph, prev *Result, changed []*cloud.ResourceID) (map[cloud.ResourceMapKey]bool, error) {
+	dirty := map[cloud.ResourceMapKey]bool{}
+	mark := func(id *cloud.ResourceID) error {
+		dirty[id.MapKey()] = true
+		for _, g := range []*rgraph.Graph{want, prev.Want} {
+			n := g.Get(id)
+			if n == nil {
+				continue
+			}
+			inRefs, err := traversal.TransitiveInRefs(g, n)
+			if err != nil {
+				return err
+			}
+			for _, inRef := range inRefs {
+				dirty[inRef.ID().MapKey()] = true
+			}
+		}
+		return nil
+	}
+
+	for _, id := range changed {
+		if err := mark(id); err != nil {
+			return nil, err
+		}
+	}
+	for _, n := range want.All() {
+		if prev.Got.Get(n.ID()) == nil {
+			if err := mark(n.ID()); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return dirty, nil
+}
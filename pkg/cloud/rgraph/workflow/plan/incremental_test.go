@@ -0,0 +1,134 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plan
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/exec"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/backendservice"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/healthcheck"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/testing/ez"
+	"google.golang.org/api/compute/v1"
+)
+
+func TestDoIncremental(t *testing.T) {
+	ctx := context.Background()
+	mock := cloud.NewMockGCE(&cloud.SingleProjectRouter{ID: "proj"})
+
+	graph := func(hc1Description string) *ez.Graph {
+		return &ez.Graph{
+			Project: "proj",
+			Nodes: []ez.Node{
+				{Name: "hc1", SetupFunc: func(x *compute.HealthCheck) { x.Description = hc1Description }},
+				{Name: "bs1", Refs: []ez.Ref{{Field: "Healthchecks", To: "hc1"}}},
+				{Name: "hc2"},
+				{Name: "bs2", Refs: []ez.Ref{{Field: "Healthchecks", To: "hc2"}}},
+			},
+		}
+	}
+
+	// Create the resources.
+	result, err := Do(ctx, mock, graph("").Builder().MustBuild())
+	if err != nil {
+		t.Fatalf("Do() = %v", err)
+	}
+	ex, err := exec.NewSerialExecutor(mock, result.Actions)
+	if err != nil {
+		t.Fatalf("NewSerialExecutor() = %v", err)
+	}
+	if _, err := ex.Run(ctx); err != nil {
+		t.Fatalf("Run() = %v", err)
+	}
+
+	prev, err := Do(ctx, mock, graph("").Builder().MustBuild())
+	if err != nil {
+		t.Fatalf("Do() = %v", err)
+	}
+
+	// The resources are fetched in parallel.
+	var (
+		fetchedLock sync.Mutex
+		fetched     = map[string]bool{}
+	)
+	mock.MockHealthChecks.GetHook = func(_ context.Context, key *meta.Key, _ *cloud.MockHealthChecks, _ ...cloud.Option) (bool, *compute.HealthCheck, error) {
+		fetchedLock.Lock()
+		defer fetchedLock.Unlock()
+		fetched[key.Name] = true
+		return false, nil, nil
+	}
+	mock.MockBackendServices.GetHook = func(_ context.Context, key *meta.Key, _ *cloud.MockBackendServices, _ ...cloud.Option) (bool, *compute.BackendService, error) {
+		fetchedLock.Lock()
+		defer fetchedLock.Unlock()
+		fetched[key.Name] = true
+		return false, nil, nil
+	}
+
+	hc1 := healthcheck.ID("proj", meta.GlobalKey("hc1"))
+	got, err := DoIncremental(ctx, mock, graph("changed").Builder().MustBuild(), prev, []*cloud.ResourceID{hc1})
+	if err != nil {
+		t.Fatalf("DoIncremental() = %v", err)
+	}
+
+	// Only hc1 and bs1 (which references hc1) are fetched again.
+	wantFetched := map[string]bool{"hc1": true, "bs1": true}
+	if len(fetched) != len(wantFetched) || !fetched["hc1"] || !fetched["bs1"] {
+		t.Errorf("fetched = %v, want %v", fetched, wantFetched)
+	}
+
+	full, err := Do(ctx, mock, graph("changed").Builder().MustBuild())
+	if err != nil {
+		t.Fatalf("Do() = %v", err)
+	}
+	if len(got.Actions) != len(full.Actions) {
+		t.Errorf("len(Actions) = %d, want %d", len(got.Actions), len(full.Actions))
+	}
+	for _, n := range full.Want.All() {
+		gotNode := got.Want.Get(n.ID())
+		if gotNode == nil {
+			t.Errorf("DoIncremental() missing node %v", n.ID())
+			continue
+		}
+		if gotNode.Plan().Op() != n.Plan().Op() {
+			t.Errorf("%v: Op() = %s, want %s", n.ID(), gotNode.Plan().Op(), n.Plan().Op())
+		}
+	}
+	if op := got.Want.Get(hc1).Plan().Op(); op != rnode.OpUpdate {
+		t.Errorf("hc1 Op() = %s, want %s", op, rnode.OpUpdate)
+	}
+	if op := got.Want.Get(backendservice.ID("proj", meta.GlobalKey("bs2"))).Plan().Op(); op != rnode.OpNothing {
+		t.Errorf("bs2 Op() = %s, want %s", op, rnode.OpNothing)
+	}
+}
+
+func TestDoIncrementalNoPrev(t *testing.T) {
+	mock := cloud.NewMockGCE(&cloud.SingleProjectRouter{ID: "proj"})
+	g := &ez.Graph{Project: "proj", Nodes: []ez.Node{{Name: "hc"}}}
+
+	result, err := DoIncremental(context.Background(), mock, g.Builder().MustBuild(), nil, nil)
+	if err != nil {
+		t.Fatalf("DoIncremental() = %v", err)
+	}
+	if op := result.Want.Get(healthcheck.ID("proj", meta.GlobalKey("hc"))).Plan().Op(); op != rnode.OpCreate {
+		t.Errorf("Op() = %s, want %s", op, rnode.OpCreate)
+	}
+}
@@ -0,0 +1,224 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plan
+
+import (
+	"fmt"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/api"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode"
+)
+
+// LastAppliedField is the field of the resource that stores the hash of the
+// last applied spec. The hash allows a three-way diff without storing the
+// last applied graph outside of the Cloud:
+//
+//   - If the hash of got matches the stored hash, the resource has not been
+//     changed outside of the graph and there are no conflicts.
+//   - If the hash of want matches the stored hash, want has not changed since
+//     it was applied and all of the diffs are external changes.
+//
+// Otherwise, the default conflict detection is used (see resolveConflicts).
+//
+// Adding the hash to existing resources updates all of them on the first
+// plan, except for the resources that ignore the Description metadata in a
+// diff (api.FieldTypeDescription); these store the hash with the next
+// update. Note that the Description of some resources (e.g. ForwardingRules)
+// cannot be updated without recreating the resource; use LastAppliedLabels
+// for these.
+type LastAppliedField string
+
+const (
+	// LastAppliedDescription stores the hash as the LastAppliedKey value in
+	// the cloud.Description metadata.
+	LastAppliedDescription LastAppliedField = "Description"
+	// LastAppliedLabels stores the hash in the label LastAppliedKey.
+	LastAppliedLabels LastAppliedField = "Labels"
+)
+
+// LastAppliedKey is the label key (or cloud.Description value name) for the
+// hash.
+const LastAppliedKey = "rgraph-last-applied"
+
+// lastAppliedHashes for a Node. The hashes do not include the hash stored in
+// the resource.
+type lastAppliedHashes struct {
+	want   string
+	got    string
+	stored string
+}
+
+// stampLastApplied stores the hash of each want resource in the resource and
+// records the hashes for resolveConflicts().
+func (pl *planner) stampLastApplied() error {
+	f := pl.config.LastApplied
+	if f == "" {
+		return nil
+	}
+	pl.lastApplied = map[cloud.ResourceMapKey]lastAppliedHashes{}
+
+	for _, wantNode := range pl.want.All() {
+		if wantNode.Ownership() != rnode.OwnershipManaged || wantNode.State() != rnode.NodeExists || wantNode.Resource() == nil {
+			continue
+		}
+		stripped, wantHash, _, err := lastAppliedHash(wantNode.Resource(), f)
+		if err != nil {
+			// The resource does not support the field.
+			continue
+		}
+		var hashes lastAppliedHashes
+		hashes.want = wantHash
+
+		if gotNode := pl.got.Get(wantNode.ID()); gotNode != nil && gotNode.State() == rnode.NodeExists && gotNode.Resource() != nil {
+			_, hashes.got, hashes.stored, err = lastAppliedHash(gotNode.Resource(), f)
+			if err != nil {
+				return fmt.Errorf("%s: %v: %w", errPrefix, wantNode.ID(), err)
+			}
+		}
+		pl.lastApplied[wantNode.ID().MapKey()] = hashes
+
+		stamped, err := stripped.(api.FieldAccessor).WithField(string(f), withLastApplied(f, stripped, wantHash))
+		if err != nil {
+			return fmt.Errorf("%s: %v: %w", errPrefix, wantNode.ID(), err)
+		}
+		if _, err := pl.replaceResource(wantNode, stamped); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// lastAppliedHash returns the resource without the stored hash, the hash of
+// the resource without the stored hash and the stored hash.
+func lastAppliedHash(r rnode.UntypedResource, f LastAppliedField) (any, string, string, error) {
+	fa, ok := r.(api.FieldAccessor)
+	if !ok {
+		return nil, "", "", fmt.Errorf("LastApplied is not supported for %T", r)
+	}
+	v, err := fa.Field(string(f))
+	if err != nil {
+		return nil, "", "", err
+	}
+	strippedValue, stored := stripLastApplied(f, v)
+	stripped, err := fa.WithField(string(f), strippedValue)
+	if err != nil {
+		return nil, "", "", err
+	}
+	hash, err := stripped.(api.FieldAccessor).SpecHash()
+	if err != nil {
+		return nil, "", "", err
+	}
+	return stripped, hash, stored, nil
+}
+
+// stripLastApplied returns the field value v without the hash and the hash.
+func stripLastApplied(f LastAppliedField, v any) (any, string) {
+	return stripStored(f, LastAppliedKey, v)
+}
+
+// stripStored returns the field value v without the value stored under name
+// and the stored value.
+func stripStored(f LastAppliedField, name string, v any) (any, string) {
+	switch f {
+	case LastAppliedDescription:
+		s, _ := v.(string)
+		d, err := cloud.ParseDescription(s)
+		if err != nil {
+			return s, ""
+		}
+		stored, ok := d.Values[name]
+		if !ok {
+			return s, ""
+		}
+		d.DeleteValue(name)
+		return d.String(), stored
+	case LastAppliedLabels:
+		labels, _ := v.(map[string]string)
+		stored, ok := labels[name]
+		if !ok {
+			return labels, ""
+		}
+		var ret map[string]string
+		for k, val := range labels {
+			if k == name {
+				continue
+			}
+			if ret == nil {
+				ret = map[string]string{}
+			}
+			ret[k] = val
+		}
+		return ret, stored
+	}
+	return v, ""
+}
+
+// withLastApplied returns the value for field f of the stripped resource
+// with the hash.
+func withLastApplied(f LastAppliedField, stripped any, hash string) any {
+	v, _ := stripped.(api.FieldAccessor).Field(string(f))
+	return withStored(f, LastAppliedKey, v, hash)
+}
+
+// withStored returns the field value v with value stored under name.
+func withStored(f LastAppliedField, name string, v any, value string) any {
+	switch f {
+	case LastAppliedDescription:
+		s, _ := v.(string)
+		d, err := cloud.ParseDescription(s)
+		if err != nil {
+			// Replace the invalid metadata.
+			d = &cloud.Description{Text: cloud.DescriptionText(s)}
+		}
+		d.SetValue(name, value)
+		return d.String()
+	case LastAppliedLabels:
+		labels, _ := v.(map[string]string)
+		ret := map[string]string{name: value}
+		for k, val := range labels {
+			ret[k] = val
+		}
+		return ret
+	}
+	return v
+}
+
+// lastAppliedConflicts returns the conflicts for the diff items of wantNode
+// using the last applied hash. ok is false if the hash cannot decide and the
+// default conflict detection should be used.
+func (pl *planner) lastAppliedConflicts(wantNode rnode.Node, items []api.DiffItem) ([]api.DiffItem, bool) {
+	hashes, ok := pl.lastApplied[wantNode.ID().MapKey()]
+	if !ok || hashes.stored == "" {
+		return nil, false
+	}
+	switch {
+	case hashes.got == hashes.stored:
+		// No changes since the last apply.
+		return nil, true
+	case hashes.want == hashes.stored:
+		// Only external changes.
+		var conflicts []api.DiffItem
+		for _, item := range items {
+			if item.Path.TopLevelField() != string(pl.config.LastApplied) {
+				conflicts = append(conflicts, item)
+			}
+		}
+		return conflicts, true
+	}
+	return nil, false
+}
@@ -0,0 +1,159 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plan
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/mock"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/exec"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/healthcheck"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/testing/ez"
+	"google.golang.org/api/compute/v1"
+)
+
+func TestLastApplied(t *testing.T) {
+	ctx := context.Background()
+	mockCloud := cloud.NewMockGCE(&cloud.SingleProjectRouter{ID: "proj"})
+	mockCloud.MockHealthChecks.UpdateHook = mock.UpdateHealthCheckHook
+	key := meta.GlobalKey("hc")
+	hcID := healthcheck.ID("proj", key)
+
+	graph := func(interval int64) *ez.Graph {
+		return &ez.Graph{
+			Project: "proj",
+			Nodes: []ez.Node{{
+				Name: "hc",
+				SetupFunc: func(x *compute.HealthCheck) {
+					x.Description = "user description"
+					x.CheckIntervalSec = interval
+				},
+			}},
+		}
+	}
+	apply := func(g *ez.Graph, opts ...Option) (*Result, error) {
+		t.Helper()
+		result, err := Do(ctx, mockCloud, g.Builder().MustBuild(), opts...)
+		if err != nil {
+			return nil, err
+		}
+		ex, err := exec.NewSerialExecutor(mockCloud, result.Actions)
+		if err != nil {
+			t.Fatalf("NewSerialExecutor() = %v", err)
+		}
+		if _, err := ex.Run(ctx); err != nil {
+			t.Fatalf("Run() = %v", err)
+		}
+		return result, nil
+	}
+	fail := ConflictStrategyOption(rnode.ConflictFail)
+	lastApplied := LastAppliedOption(LastAppliedDescription)
+
+	if _, err := apply(graph(5), fail, lastApplied); err != nil {
+		t.Fatalf("Do() = %v", err)
+	}
+	hc, err := mockCloud.HealthChecks().Get(ctx, key)
+	if err != nil {
+		t.Fatalf("Get() = %v", err)
+	}
+	if d, err := cloud.ParseDescription(hc.Description); err != nil || d.Text != "user description" || d.Values[LastAppliedKey] == "" {
+		t.Errorf("Description = %q, want user description with the hash", hc.Description)
+	}
+
+	// No changes.
+	result, err := apply(graph(5), fail, lastApplied)
+	if err != nil {
+		t.Fatalf("Do() = %v", err)
+	}
+	if op := result.Want.Get(hcID).Plan().Op(); op != rnode.OpNothing {
+		t.Errorf("Op() = %s, want %s", op, rnode.OpNothing)
+	}
+
+	// Changing a field that was set by the last apply is not a conflict.
+	// Without the hash this is indistinguishable from an external change.
+	var conflictErr *ConflictError
+	if _, err := Do(ctx, mockCloud, graph(10).Builder().MustBuild(), fail); !errors.As(err, &conflictErr) || !hasField(conflictErr, "CheckIntervalSec") {
+		t.Errorf("Do() = %v, want ConflictError for CheckIntervalSec", err)
+	}
+	if _, err := apply(graph(10), fail, lastApplied); err != nil {
+		t.Fatalf("Do() = %v", err)
+	}
+
+	// An external change that clears a field is detected.
+	hc, _ = mockCloud.HealthChecks().Get(ctx, key)
+	hc.CheckIntervalSec = 0
+	if err := mockCloud.HealthChecks().Update(ctx, key, hc); err != nil {
+		t.Fatalf("Update() = %v", err)
+	}
+	if _, err := Do(ctx, mockCloud, graph(10).Builder().MustBuild(), fail, lastApplied); !errors.As(err, &conflictErr) || !hasField(conflictErr, "CheckIntervalSec") {
+		t.Errorf("Do() = %v, want ConflictError for CheckIntervalSec", err)
+	}
+}
+
+func hasField(e *ConflictError, field string) bool {
+	for _, item := range e.Items {
+		if item.Path.TopLevelField() == field {
+			return true
+		}
+	}
+	return false
+}
+
+func TestStripLastApplied(t *testing.T) {
+	for _, tc := range []struct {
+		f        LastAppliedField
+		v        any
+		want     any
+		wantHash string
+	}{
+		{f: LastAppliedDescription, v: "", want: ""},
+		{f: LastAppliedDescription, v: "d", want: "d"},
+		{f: LastAppliedDescription, v: withStored(LastAppliedDescription, LastAppliedKey, "d", "x"), want: "d", wantHash: "x"},
+		{f: LastAppliedDescription, v: withStored(LastAppliedDescription, LastAppliedKey, "", "x"), want: "", wantHash: "x"},
+		{
+			f:        LastAppliedDescription,
+			v:        withStored(LastAppliedDescription, LastAppliedKey, withStored(LastAppliedDescription, IdempotencyKeyName, "d", "k"), "x"),
+			want:     withStored(LastAppliedDescription, IdempotencyKeyName, "d", "k"),
+			wantHash: "x",
+		},
+		{f: LastAppliedDescription, v: "d\n" + cloud.DescriptionMetadataKey + "={", want: "d\n" + cloud.DescriptionMetadataKey + "={"},
+	} {
+		got, hash := stripLastApplied(tc.f, tc.v)
+		if got != tc.want || hash != tc.wantHash {
+			t.Errorf("stripLastApplied(%q, %q) = %q, %q; want %q, %q", tc.f, tc.v, got, hash, tc.want, tc.wantHash)
+		}
+	}
+
+	labels, hash := stripLastApplied(LastAppliedLabels, map[string]string{"a": "b", LastAppliedKey: "x"})
+	if l := labels.(map[string]string); len(l) != 1 || l["a"] != "b" || hash != "x" {
+		t.Errorf("stripLastApplied(Labels) = %v, %q; want map[a:b], x", labels, hash)
+	}
+	if labels, hash := stripLastApplied(LastAppliedLabels, map[string]string{LastAppliedKey: "x"}); labels.(map[string]string) != nil || hash != "x" {
+		t.Errorf("stripLastApplied(Labels) = %v, %q; want nil, x", labels, hash)
+	}
+
+	// Invalid metadata is replaced.
+	invalid := "d\n" + cloud.DescriptionMetadataKey + "={"
+	if got, want := withStored(LastAppliedDescription, LastAppliedKey, invalid, "x"), withStored(LastAppliedDescription, LastAppliedKey, "d", "x"); got != want {
+		t.Errorf("withStored(%q) = %q, want %q", invalid, got, want)
+	}
+}
@@ -0,0 +1,76 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plan
+
+import (
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/exec"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode"
+)
+
+// PhaseFunc returns the exec.Phase for an Action planned for Node n. See
+// PhaseOption.
+type PhaseFunc func(n rnode.Node, a exec.Action) exec.Phase
+
+// PhaseOption tags each of the planned Actions with the Phase returned by f.
+// The Executor will complete all Actions in a Phase before starting the
+// next. See exec.Phase.
+func PhaseOption(f PhaseFunc) Option {
+	return func(c *Config) { c.Phase = f }
+}
+
+// routingResources are the resources that are sequenced in
+// exec.PhaseRouting by DefaultPhases.
+var routingResources = map[string]bool{
+	"forwardingRules":   true,
+	"targetGrpcProxies": true,
+	"targetHttpProxies": true,
+	"tcpRoutes":         true,
+	"urlMaps":           true,
+}
+
+// DefaultPhases is a PhaseFunc that creates and updates the backing
+// infrastructure first, then changes the routing resources (e.g.
+// ForwardingRules, UrlMaps), then deletes resources that are no longer used.
+// The delete Actions of a Node being recreated are in the same Phase as the
+// create Actions.
+func DefaultPhases(n rnode.Node, a exec.Action) exec.Phase {
+	if n.Plan().Op() == rnode.OpDelete {
+		return exec.PhaseCleanup
+	}
+	if routingResources[n.ID().Resource] {
+		return exec.PhaseRouting
+	}
+	return exec.PhaseInfra
+}
+
+// addPhases tags the Actions for the Nodes in the plan with the Phase from
+// the config. Actions that are not for a Node are not tagged.
+func (pl *planner) addPhases(acts []exec.Action) []exec.Action {
+	if pl.config.Phase == nil {
+		return acts
+	}
+	var ret []exec.Action
+	for _, a := range acts {
+		if id := a.Metadata().ResourceID; id != nil {
+			if n := pl.want.Get(id); n != nil {
+				a = exec.WithPhase(a, pl.config.Phase(n, a))
+			}
+		}
+		ret = append(ret, a)
+	}
+	return ret
+}
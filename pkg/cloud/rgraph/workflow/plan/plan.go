@@ -18,7 +18,9 @@ package plan
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sync"
 
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph"
@@ -28,20 +30,181 @@ import (
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/algo/trclosure"
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/exec"
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode"
+	"k8s.io/klog/v2"
 )
 
 type Result struct {
 	Got     *rgraph.Graph
 	Want    *rgraph.Graph
 	Actions []exec.Action
+	// SyncErrors are the resources that could not be fetched from the
+	// Cloud. This is only set with SyncErrorBlock.
+	SyncErrors []*SyncError
+	// EstimatedMonthlyCostDelta of the Actions. This is only set with
+	// CostOption.
+	EstimatedMonthlyCostDelta float64
+
+	// explanations for each Node, if ExplainOption is set.
+	explanations map[cloud.ResourceMapKey][]string
+
+	// local is the plan computed for each Node by the local planner, before
+	// conflicts and recreates are resolved. This is reused by
+	// DoIncremental().
+	local map[cloud.ResourceMapKey]rnode.PlanDetails
+}
+
+// Verdict returns the Verdict of the planner for the resource named by id
+// and the inputs that led to it. Returns false if the resource is not in the
+// plan.
+func (r *Result) Verdict(id *cloud.ResourceID) (rnode.Verdict, rnode.VerdictInputs, bool) {
+	n := r.Want.Get(id)
+	if n == nil || n.Plan().Details() == nil {
+		return rnode.VerdictUnknown, rnode.VerdictInputs{}, false
+	}
+	details := n.Plan().Details()
+	return details.Verdict, details.Inputs, true
+}
+
+// Option for the planner.
+type Option func(c *Config)
+
+// ConflictStrategyOption sets the default ConflictStrategy for nodes that do
+// not set one (see rnode.Builder.SetConflictStrategy).
+func ConflictStrategyOption(s rnode.ConflictStrategy) Option {
+	return func(c *Config) { c.ConflictStrategy = s }
+}
+
+// SyncOptions are passed to trclosure.Do() when fetching the current state of
+// the resources from the Cloud, e.g. to set the parallelism and rate limits.
+func SyncOptions(opts ...trclosure.Option) Option {
+	return func(c *Config) { c.SyncOptions = append(c.SyncOptions, opts...) }
+}
+
+// LastAppliedOption stores a hash of the last applied spec in field f of
+// each managed resource and uses it to detect external changes when
+// resolving conflicts (see LastAppliedField). Resources that do not have the
+// field are planned as usual.
+func LastAppliedOption(f LastAppliedField) Option {
+	return func(c *Config) { c.LastApplied = f }
+}
+
+// StrictFieldsOption sets the rnode.StrictFieldsMode used when fetching the
+// resources from the Cloud.
+func StrictFieldsOption(m rnode.StrictFieldsMode) Option {
+	return func(c *Config) { c.StrictFields = m }
+}
+
+// SyncFieldsOption limits the fields fetched from the Cloud for the types
+// of resources in m to reduce the size of the responses for large resources
+// (see rnode.SyncFieldsMap).
+func SyncFieldsOption(m rnode.SyncFieldsMap) Option {
+	return func(c *Config) { c.SyncFields = m }
+}
+
+// ResourceDecodeOption sets the rnode.ResourceDecodeMode for the resources
+// fetched from the Cloud. Use rnode.DecodeLazy to reduce the memory used by
+// graphs with many large resources.
+func ResourceDecodeOption(m rnode.ResourceDecodeMode) Option {
+	return func(c *Config) { c.ResourceDecode = m }
+}
+
+// Config for the planner.
+type Config struct {
+	// ConflictStrategy to use for nodes with ConflictDefault.
+	ConflictStrategy rnode.ConflictStrategy
+	// SyncOptions for trclosure.Do().
+	SyncOptions []trclosure.Option
+	// LastApplied is the field storing the hash of the last applied spec.
+	// Empty disables the hash.
+	LastApplied LastAppliedField
+	// Phase to tag the Actions with. nil does not tag the Actions.
+	Phase PhaseFunc
+	// WhatIf overrides the state of the resources fetched from the Cloud.
+	// See WhatIfOption.
+	WhatIf map[cloud.ResourceMapKey]rnode.Builder
+	// StrictFields mode for SyncFromCloud.
+	StrictFields rnode.StrictFieldsMode
+	// SyncFields limits the fields fetched from the Cloud. nil fetches
+	// the complete resources.
+	SyncFields rnode.SyncFieldsMap
+	// ResourceDecode is how the resources fetched from the Cloud are
+	// stored.
+	ResourceDecode rnode.ResourceDecodeMode
+	// IdempotencyPlanID is the ID of the plan for the idempotency keys.
+	// Empty disables the keys. See IdempotencyOption.
+	IdempotencyPlanID string
+	// IdempotencyField is the field storing the idempotency key.
+	IdempotencyField LastAppliedField
+	// Policies consulted for each planned Action. See PolicyOption.
+	Policies []PolicyEvaluator
+	// SyncErrors is how errors fetching resources are handled.
+	SyncErrors SyncErrorMode
+	// Pricing for the cost estimates. nil disables the estimates. See
+	// CostOption.
+	Pricing PricingProvider
+	// Explain the planned Actions. See ExplainOption.
+	Explain bool
+	// BlastRadius limits the Nodes that may be deleted or recreated. nil
+	// is unlimited.
+	BlastRadius *BlastRadius
+}
+
+func makeConfig(opts ...Option) (*Config, error) {
+	c := &Config{ConflictStrategy: rnode.ConflictOverwrite, SyncErrors: SyncErrorFail}
+	for _, o := range opts {
+		o(c)
+	}
+	switch c.ConflictStrategy {
+	case rnode.ConflictOverwrite, rnode.ConflictPreserveExternal, rnode.ConflictFail:
+	default:
+		return nil, fmt.Errorf("%s: invalid ConflictStrategy %q", errPrefix, c.ConflictStrategy)
+	}
+	switch c.StrictFields {
+	case rnode.StrictFieldsOff, rnode.StrictFieldsWarn, rnode.StrictFieldsError:
+	default:
+		return nil, fmt.Errorf("%s: invalid StrictFieldsMode %q", errPrefix, c.StrictFields)
+	}
+	switch c.ResourceDecode {
+	case rnode.DecodeEager, rnode.DecodeLazy:
+	default:
+		return nil, fmt.Errorf("%s: invalid ResourceDecodeMode %q", errPrefix, c.ResourceDecode)
+	}
+	switch c.SyncErrors {
+	case SyncErrorFail, SyncErrorBlock:
+	default:
+		return nil, fmt.Errorf("%s: invalid SyncErrorMode %q", errPrefix, c.SyncErrors)
+	}
+	switch c.LastApplied {
+	case "", LastAppliedDescription, LastAppliedLabels:
+	default:
+		return nil, fmt.Errorf("%s: invalid LastAppliedField %q", errPrefix, c.LastApplied)
+	}
+	if c.BlastRadius != nil {
+		if err := c.BlastRadius.validate(); err != nil {
+			return nil, err
+		}
+	}
+	if c.IdempotencyPlanID != "" {
+		switch c.IdempotencyField {
+		case LastAppliedDescription, LastAppliedLabels:
+		default:
+			return nil, fmt.Errorf("%s: invalid IdempotencyField %q", errPrefix, c.IdempotencyField)
+		}
+	}
+	return c, nil
 }
 
 // Do will plan updates to cloud resources wanted in graph. Returns the set of
 // Actions needed to sync to "want".
-func Do(ctx context.Context, c cloud.Cloud, want *rgraph.Graph) (*Result, error) {
+func Do(ctx context.Context, c cloud.Cloud, want *rgraph.Graph, opts ...Option) (*Result, error) {
+	config, err := makeConfig(opts...)
+	if err != nil {
+		return nil, err
+	}
 	w := planner{
-		cloud: c,
-		want:  want,
+		cloud:  c,
+		want:   want,
+		config: config,
 	}
 	return w.plan(ctx)
 }
@@ -49,9 +212,60 @@ func Do(ctx context.Context, c cloud.Cloud, want *rgraph.Graph) (*Result, error)
 const errPrefix = "Plan"
 
 type planner struct {
-	cloud cloud.Cloud
-	got   *rgraph.Graph
-	want  *rgraph.Graph
+	cloud  cloud.Cloud
+	got    *rgraph.Graph
+	want   *rgraph.Graph
+	config *Config
+
+	// prev is the Result whose local plans are reused for the Nodes in
+	// reuse. This is only set for DoIncremental().
+	prev  *Result
+	reuse map[cloud.ResourceMapKey]bool
+
+	// lastApplied hashes for the Nodes, if Config.LastApplied is set.
+	lastApplied map[cloud.ResourceMapKey]lastAppliedHashes
+	// idempotencyKeys for the Nodes, if Config.IdempotencyPlanID is set.
+	idempotencyKeys map[cloud.ResourceMapKey]string
+
+	// syncErrors recorded with SyncErrorBlock. syncErrorsLock guards
+	// syncErrors during the sync.
+	syncErrorsLock sync.Mutex
+	syncErrors     []*SyncError
+
+	// deleteCauses records why the planner changed a Node in "want" to
+	// NodeDoesNotExist. Used by ExplainOption.
+	deleteCauses map[cloud.ResourceMapKey]string
+
+	// pending are the Nodes that are planned later by Stream(). These are
+	// not in "want" yet, so they must not be deleted when they are in
+	// "got".
+	pending map[cloud.ResourceMapKey]bool
+	// only limits the Actions to the Nodes for which it returns true. nil
+	// returns the Actions for all of the Nodes. This is set by Stream().
+	only func(n rnode.Node) bool
+}
+
+// actionNodes returns the Nodes in "want" to return the Actions for.
+func (pl *planner) actionNodes() []rnode.Node {
+	if pl.only == nil {
+		return pl.want.All()
+	}
+	var ret []rnode.Node
+	for _, n := range pl.want.All() {
+		if pl.only(n) {
+			ret = append(ret, n)
+		}
+	}
+	return ret
+}
+
+// setDeleteCause records why the Node id was changed to NodeDoesNotExist by
+// the planner.
+func (pl *planner) setDeleteCause(id *cloud.ResourceID, why string) {
+	if pl.deleteCauses == nil {
+		pl.deleteCauses = map[cloud.ResourceMapKey]string{}
+	}
+	pl.deleteCauses[id.MapKey()] = why
 }
 
 func (pl *planner) plan(ctx context.Context) (*Result, error) {
@@ -59,23 +273,54 @@ func (pl *planner) plan(ctx context.Context) (*Result, error) {
 	// resources and also enumerate any resouces that are currently linked that
 	// are not in the "want" graph.
 	gotBuilder := pl.want.NewBuilderWithEmptyNodes()
+	if err := pl.sync(ctx, gotBuilder); err != nil {
+		return nil, err
+	}
+	return pl.planGot(ctx)
+}
 
+// sync fetches the current state of the resources in gotBuilder from the
+// Cloud and builds the "got" graph.
+func (pl *planner) sync(ctx context.Context, gotBuilder *rgraph.Builder, opts ...trclosure.Option) error {
 	// Fetch the current resource graph from Cloud.
 	// TODO: resource_prefix, ownership due to prefix etc.
-	err := trclosure.Do(ctx, pl.cloud, gotBuilder,
+	syncOpts := append([]trclosure.Option{
 		trclosure.OnGetFunc(func(n rnode.Builder) error {
 			n.SetOwnership(rnode.OwnershipManaged)
 			return nil
 		}),
-	)
+	}, opts...)
+	syncOpts = append(syncOpts, pl.config.SyncOptions...)
+	if o := pl.whatIfSyncOption(); o != nil {
+		syncOpts = append(syncOpts, o)
+	}
+	if o := pl.syncErrorOption(); o != nil {
+		syncOpts = append(syncOpts, o)
+	}
+	if pl.config.StrictFields != rnode.StrictFieldsOff {
+		ctx = rnode.WithStrictFields(ctx, pl.config.StrictFields)
+	}
+	if pl.config.SyncFields != nil {
+		ctx = rnode.WithSyncFields(ctx, pl.config.SyncFields)
+	}
+	if pl.config.ResourceDecode != rnode.DecodeEager {
+		ctx = rnode.WithResourceDecode(ctx, pl.config.ResourceDecode)
+	}
+	err := trclosure.Do(ctx, pl.cloud, gotBuilder, syncOpts...)
 	if err != nil {
-		return nil, err
+		return err
 	}
+	syncDeletionProtection(gotBuilder)
 
 	pl.got, err = gotBuilder.Build()
 	if err != nil {
-		return nil, fmt.Errorf("%s: %w", errPrefix, err)
+		return fmt.Errorf("%s: %w", errPrefix, err)
 	}
+	return nil
+}
+
+// planGot plans the "want" graph against the "got" graph.
+func (pl *planner) planGot(ctx context.Context) (*Result, error) {
 
 	// Figure out what to do with Nodes in "got" that aren't in "want". These
 	// are resources that will no longer by referenced in the updated graph.
@@ -83,6 +328,8 @@ func (pl *planner) plan(ctx context.Context) (*Result, error) {
 		switch {
 		case pl.want.Get(gotNode.ID()) != nil:
 			// Node exists in "want", don't need to do anything.
+		case pl.pending[gotNode.ID().MapKey()]:
+			// Node is in "want" for a later step of Stream().
 		case gotNode.Ownership() == rnode.OwnershipExternal:
 			// TODO: clone the node from the "got" graph for "want" unchanged.
 		case gotNode.Ownership() == rnode.OwnershipManaged:
@@ -97,13 +344,39 @@ func (pl *planner) plan(ctx context.Context) (*Result, error) {
 			if err != nil {
 				return nil, err
 			}
+			pl.setDeleteCause(gotNode.ID(), "node is no longer referenced by the wanted graph")
 		default:
 			return nil, fmt.Errorf("%s: node %s has invalid ownership %s", errPrefix, gotNode.ID(), gotNode.Ownership())
 		}
 	}
 
+	if err := pl.cascadeDeletes(ctx); err != nil {
+		return nil, err
+	}
+
+	if err := pl.stripIdempotencyKeys(); err != nil {
+		return nil, err
+	}
+
+	if err := pl.stampDeletionProtection(); err != nil {
+		return nil, err
+	}
+
+	if err := pl.stampLastApplied(); err != nil {
+		return nil, err
+	}
+
 	// Compute the local plan for each resource.
-	if err := localplan.PlanWantGraph(pl.got, pl.want); err != nil {
+	local, err := pl.localPlan()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := pl.blockDependents(ctx); err != nil {
+		return nil, err
+	}
+
+	if err := pl.resolveConflicts(); err != nil {
 		return nil, err
 	}
 
@@ -115,17 +388,88 @@ func (pl *planner) plan(ctx context.Context) (*Result, error) {
 		return nil, err
 	}
 
-	acts, err := actions.Do(pl.got, pl.want)
+	if err := pl.checkDeletionProtection(); err != nil {
+		return nil, err
+	}
+
+	if err := pl.checkBlastRadius(ctx); err != nil {
+		return nil, err
+	}
+
+	if err := pl.checkExternalDependencies(); err != nil {
+		return nil, err
+	}
+
+	if err := pl.checkPreconditions(ctx); err != nil {
+		return nil, err
+	}
+
+	if err := pl.stampIdempotencyKeys(); err != nil {
+		return nil, err
+	}
+
+	acts, err := actions.DoNodes(pl.got, pl.actionNodes())
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w", errPrefix, err)
 	}
+	acts, err = pl.applyPolicies(ctx, acts)
+	if err != nil {
+		return nil, err
+	}
+	acts, cost, err := pl.addCostEstimates(ctx, acts)
+	if err != nil {
+		return nil, err
+	}
+	acts = pl.addIdempotencyChecks(acts)
+	acts = pl.addPreconditionActions(acts)
+	acts = pl.addPostconditionActions(acts)
+	acts = pl.addPhases(acts)
+	acts, explanations := pl.addExplanations(acts)
+	klog.FromContext(ctx).V(2).Info("Plan done", "nodes", len(pl.want.All()), "actions", len(acts))
 	return &Result{
 		Got:     pl.got,
 		Want:    pl.want,
 		Actions: acts,
+		local:   local,
+
+		explanations: explanations,
+
+		SyncErrors:                pl.syncErrors,
+		EstimatedMonthlyCostDelta: cost,
 	}, nil
 }
 
+// localPlan computes the local plan for each Node in "want", reusing the
+// plans from pl.prev for the Nodes in pl.reuse. Returns the local plans.
+func (pl *planner) localPlan() (map[cloud.ResourceMapKey]rnode.PlanDetails, error) {
+	if pl.prev == nil {
+		if err := localplan.PlanWantGraph(pl.got, pl.want); err != nil {
+			return nil, err
+		}
+	} else {
+		var nodes []rnode.Node
+		for _, n := range pl.want.All() {
+			details, ok := pl.prev.local[n.ID().MapKey()]
+			if ok && pl.reuse[n.ID().MapKey()] {
+				n.Plan().Set(details)
+				continue
+			}
+			nodes = append(nodes, n)
+		}
+		if err := localplan.PlanWantNodes(pl.got, pl.want, nodes); err != nil {
+			return nil, err
+		}
+	}
+
+	local := map[cloud.ResourceMapKey]rnode.PlanDetails{}
+	for _, n := range pl.want.All() {
+		if details := n.Plan().Details(); details != nil {
+			local[n.ID().MapKey()] = *details
+		}
+	}
+	return local, nil
+}
+
 // propagateRecreates through inbound references. If a resource needs to be
 // recreated, this means any references will also be affected transitively.
 func (pl *planner) propagateRecreates() error {
@@ -162,6 +506,8 @@ func (pl *planner) propagateRecreates() error {
 				inRefNode.Plan().Set(rnode.PlanDetails{
 					Operation: rnode.OpRecreate,
 					Why:       fmt.Sprintf("Dependency %v is being recreated", n.ID()),
+					Verdict:   rnode.VerdictDependencyRecreated,
+					Inputs:    inRefNode.Plan().Details().Inputs,
 				})
 			default:
 				return fmt.Errorf("%s: inRef %s has invalid op %s, can't propagate recreate", errPrefix, inRefNode.ID(), inRefNode.Plan().Op())
@@ -178,9 +524,12 @@ func (pl *planner) sanityCheck() error {
 			return fmt.Errorf("%s: node %v has invalid op %s", errPrefix, n.ID(), n.Plan().Op())
 		case rnode.OpDelete:
 			// If A => B; if B is to be deleted, then A must be deleted.
-			for _, refs := range n.InRefs() {
-				if inNode := pl.want.Get(refs.To); inNode == nil {
-					return fmt.Errorf("%s: inRef from node %v that doesn't exist", errPrefix, refs.From)
+			for _, ref := range pl.want.InRefs(n.ID()) {
+				if !ref.RequiredBeforeDelete() {
+					continue
+				}
+				if inNode := pl.want.Get(ref.From); inNode == nil {
+					return fmt.Errorf("%s: inRef from node %v that doesn't exist", errPrefix, ref.From)
 				} else if inNode.Plan().Op() != rnode.OpDelete {
 					return fmt.Errorf("%s: %v to be deleted, but inRef %v is not", errPrefix, n.ID(), inNode.ID())
 				}
@@ -190,3 +539,81 @@ func (pl *planner) sanityCheck() error {
 
 	return nil
 }
+
+// DeletionProtectedError is returned when the plan would delete a node that
+// has been marked with SetDeletionProtected().
+type DeletionProtectedError struct {
+	// ID of the protected node.
+	ID *cloud.ResourceID
+	// Operation that was planned for the node.
+	Operation rnode.Operation
+	// Why the Operation was planned.
+	Why string
+}
+
+func (e *DeletionProtectedError) Error() string {
+	return fmt.Sprintf("%s: node %v is deletion protected but was planned for %s (%s)", errPrefix, e.ID, e.Operation, e.Why)
+}
+
+// checkDeletionProtection returns an error if any of the protected nodes are
+// going to be deleted. All of the violations are returned as a joined list of
+// *DeletionProtectedError.
+//
+// A Node that is not in "want" is deleted with the DeletionProtected setting
+// stored in the resource (see DeletionProtectedKey).
+func (pl *planner) checkDeletionProtection() error {
+	var errs []error
+	for _, n := range pl.want.All() {
+		switch n.Plan().Op() {
+		case rnode.OpDelete, rnode.OpRecreate:
+		default:
+			continue
+		}
+		if n.DeletionProtected() {
+			errs = append(errs, &DeletionProtectedError{
+				ID:        n.ID(),
+				Operation: n.Plan().Op(),
+				Why:       n.Plan().Details().Why,
+			})
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// MissingExternalError is returned when a node that is not managed by the
+// graph (e.g. a Router or InterconnectAttachment) does not exist but is
+// referenced by a node that will exist after the plan is applied.
+type MissingExternalError struct {
+	// ID of the missing external node.
+	ID *cloud.ResourceID
+	// From is the node that references the missing node.
+	From *cloud.ResourceID
+}
+
+func (e *MissingExternalError) Error() string {
+	return fmt.Sprintf("%s: external node %v referenced by %v does not exist", errPrefix, e.ID, e.From)
+}
+
+// checkExternalDependencies returns an error if any of the external nodes do
+// not exist in the cloud but are referenced by nodes that are not going to be
+// deleted. All of the violations are returned as a joined list of
+// *MissingExternalError.
+func (pl *planner) checkExternalDependencies() error {
+	var errs []error
+	for _, n := range pl.want.All() {
+		if n.Ownership() != rnode.OwnershipExternal {
+			continue
+		}
+		if gotNode := pl.got.Get(n.ID()); gotNode == nil || gotNode.State() != rnode.NodeDoesNotExist {
+			continue
+		}
+		for _, ref := range pl.want.InRefs(n.ID()) {
+			inNode := pl.want.Get(ref.From)
+			if inNode == nil || inNode.State() == rnode.NodeDoesNotExist {
+				continue
+			}
+			errs = append(errs, &MissingExternalError{ID: n.ID(), From: ref.From})
+		}
+	}
+	return errors.Join(errs...)
+}
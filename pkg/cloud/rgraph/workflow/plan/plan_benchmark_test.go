@@ -0,0 +1,78 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plan
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/exec"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/testing/ez"
+)
+
+// benchGraph returns a graph of n load balancer chains
+// (fr -> thp -> um -> bs -> hc), i.e. 5*n nodes.
+func benchGraph(n int) *ez.Graph {
+	g := &ez.Graph{Project: "proj"}
+	for i := 0; i < n; i++ {
+		name := func(p string) string { return fmt.Sprintf("%s-%d", p, i) }
+		g.Nodes = append(g.Nodes,
+			ez.Node{Name: name("fr"), Refs: []ez.Ref{{Field: "Target", To: name("thp")}}},
+			ez.Node{Name: name("thp"), Refs: []ez.Ref{{Field: "UrlMap", To: name("um")}}},
+			ez.Node{Name: name("um"), Refs: []ez.Ref{{Field: "DefaultService", To: name("bs")}}},
+			ez.Node{Name: name("bs"), Refs: []ez.Ref{{Field: "Healthchecks", To: name("hc")}}},
+			ez.Node{Name: name("hc")},
+		)
+	}
+	return g
+}
+
+func benchmarkPlan(b *testing.B, n int, exists bool) {
+	ctx := context.Background()
+	mock := cloud.NewMockGCE(&cloud.SingleProjectRouter{ID: "proj"})
+	g := benchGraph(n)
+
+	if exists {
+		result, err := Do(ctx, mock, g.Builder().MustBuild())
+		if err != nil {
+			b.Fatalf("Do() = %v", err)
+		}
+		ex, err := exec.NewParallelExecutor(mock, result.Actions)
+		if err != nil {
+			b.Fatalf("NewParallelExecutor() = %v", err)
+		}
+		if _, err := ex.Run(ctx); err != nil {
+			b.Fatalf("Run() = %v", err)
+		}
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		want := g.Builder().MustBuild()
+		b.StartTimer()
+		if _, err := Do(ctx, mock, want); err != nil {
+			b.Fatalf("Do() = %v", err)
+		}
+	}
+}
+
+func BenchmarkPlanCreate1k(b *testing.B)   { benchmarkPlan(b, 200, false) }
+func BenchmarkPlanNoChange1k(b *testing.B) { benchmarkPlan(b, 200, true) }
+func BenchmarkPlanNoChange5k(b *testing.B) { benchmarkPlan(b, 1000, true) }
@@ -18,10 +18,12 @@ package plan
 
 import (
 	"context"
+	"errors"
 	"testing"
 
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/mock"
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph"
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/algo/graphviz"
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/exec"
@@ -32,9 +34,14 @@ import (
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/forwardingrule"
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/healthcheck"
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/networkendpointgroup"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/servicebinding"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/servicedirectoryservice"
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/targethttpproxy"
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/urlmap"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/testing/ez"
 	"google.golang.org/api/compute/v1"
+	"google.golang.org/api/networkservices/v1"
+	"google.golang.org/api/servicedirectory/v1"
 )
 
 func TestLB(t *testing.T) {
@@ -150,3 +157,548 @@ func TestLB(t *testing.T) {
 	t.Logf("got: %s", graphviz.Do(res.Got))
 	t.Logf("want: %s", graphviz.Do(res.Want))
 }
+
+func TestDeletionProtection(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		opts    ez.NodeOption
+		wantErr bool
+	}{
+		{name: "unprotected", opts: ez.DoesNotExist},
+		{name: "protected", opts: ez.DoesNotExist | ez.DeletionProtected, wantErr: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			mock := cloud.NewMockGCE(&cloud.SingleProjectRouter{ID: "proj"})
+			mock.HealthChecks().Insert(context.Background(), meta.GlobalKey("hc"), &compute.HealthCheck{})
+
+			g := ez.Graph{Project: "proj", Nodes: []ez.Node{{Name: "hc", Options: tc.opts}}}
+			_, err := Do(context.Background(), mock, g.Builder().MustBuild())
+
+			var dpErr *DeletionProtectedError
+			if gotErr := errors.As(err, &dpErr); gotErr != tc.wantErr {
+				t.Fatalf("Do() = %v; errors.As(DeletionProtectedError) = %t, want %t", err, gotErr, tc.wantErr)
+			}
+			if tc.wantErr && dpErr.Operation != rnode.OpDelete {
+				t.Errorf("dpErr.Operation = %s, want %s", dpErr.Operation, rnode.OpDelete)
+			}
+		})
+	}
+}
+
+func TestDeletionProtectionRemovedFromWant(t *testing.T) {
+	ctx := context.Background()
+	mockCloud := cloud.NewMockGCE(&cloud.SingleProjectRouter{ID: "proj"})
+	mockCloud.MockBackendServices.UpdateHook = mock.UpdateBackendServiceHook
+
+	apply := func(g *ez.Graph) error {
+		t.Helper()
+		result, err := Do(ctx, mockCloud, g.Builder().MustBuild())
+		if err != nil {
+			return err
+		}
+		ex, err := exec.NewSerialExecutor(mockCloud, result.Actions)
+		if err != nil {
+			t.Fatalf("NewSerialExecutor() = %v", err)
+		}
+		if _, err := ex.Run(ctx); err != nil {
+			t.Fatalf("Run() = %v", err)
+		}
+		return nil
+	}
+
+	// Create the protected HealthCheck referenced by the BackendService.
+	if err := apply(&ez.Graph{Project: "proj", Nodes: []ez.Node{
+		{Name: "bs", Refs: []ez.Ref{{Field: "Healthchecks", To: "hc"}}},
+		{Name: "hc", Options: ez.DeletionProtected},
+	}}); err != nil {
+		t.Fatalf("apply() = %v", err)
+	}
+	hc, err := mockCloud.HealthChecks().Get(ctx, meta.GlobalKey("hc"))
+	if err != nil {
+		t.Fatalf("Get() = %v", err)
+	}
+	if d, err := cloud.ParseDescription(hc.Description); err != nil || d.Values[DeletionProtectedKey] == "" {
+		t.Fatalf("Description = %q, want %s", hc.Description, DeletionProtectedKey)
+	}
+
+	// Dropping the HealthCheck from "want" must not delete it.
+	dropped := &ez.Graph{Project: "proj", Nodes: []ez.Node{{Name: "bs"}}}
+	_, err = Do(ctx, mockCloud, dropped.Builder().MustBuild())
+	var dpErr *DeletionProtectedError
+	if !errors.As(err, &dpErr) {
+		t.Fatalf("Do() = %v, want DeletionProtectedError", err)
+	}
+	if dpErr.ID.Key.Name != "hc" || dpErr.Operation != rnode.OpDelete {
+		t.Errorf("dpErr = %v, want OpDelete of hc", dpErr)
+	}
+
+	// The protection is removed explicitly by adding the Node to "want".
+	unprotected := &ez.Graph{Project: "proj", Nodes: []ez.Node{
+		{Name: "bs"},
+		{Name: "hc", Options: ez.DoesNotExist},
+	}}
+	if err := apply(unprotected); err != nil {
+		t.Fatalf("apply() = %v, want nil", err)
+	}
+	if _, err := mockCloud.HealthChecks().Get(ctx, meta.GlobalKey("hc")); err == nil {
+		t.Errorf("HealthCheck exists after delete, want not found")
+	}
+}
+
+func TestMissingExternal(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		exists  bool
+		wantErr bool
+	}{
+		{name: "external exists", exists: true},
+		{name: "external missing", wantErr: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			mock := cloud.NewMockGCE(&cloud.SingleProjectRouter{ID: "proj"})
+			if tc.exists {
+				mock.HealthChecks().Insert(context.Background(), meta.GlobalKey("hc"), &compute.HealthCheck{})
+			}
+
+			g := ez.Graph{
+				Project: "proj",
+				Nodes: []ez.Node{
+					{Name: "hc", Options: ez.External},
+					{Name: "bs", Refs: []ez.Ref{{Field: "Healthchecks", To: "hc"}}},
+				},
+			}
+			_, err := Do(context.Background(), mock, g.Builder().MustBuild())
+
+			var meErr *MissingExternalError
+			if gotErr := errors.As(err, &meErr); gotErr != tc.wantErr {
+				t.Fatalf("Do() = %v; errors.As(MissingExternalError) = %t, want %t", err, gotErr, tc.wantErr)
+			}
+			if tc.wantErr && meErr.From.Key.Name != "bs" {
+				t.Errorf("meErr.From = %v, want bs", meErr.From)
+			}
+		})
+	}
+}
+
+func TestMissingServiceDirectoryService(t *testing.T) {
+	const sdName = "projects/proj/locations/us-central1/namespaces/ns/services/svc"
+	sdID, err := cloud.ParseServiceDirectoryServiceName(sdName)
+	if err != nil {
+		t.Fatalf("ParseServiceDirectoryServiceName(%q) = %v", sdName, err)
+	}
+
+	for _, tc := range []struct {
+		name    string
+		exists  bool
+		wantErr bool
+	}{
+		{name: "service exists", exists: true},
+		{name: "service missing", wantErr: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			mock := cloud.NewMockGCE(&cloud.SingleProjectRouter{ID: "proj"})
+			if tc.exists {
+				mock.MockServiceDirectoryServices.Insert(sdID.Key, &servicedirectory.Service{})
+			}
+
+			sb := servicebinding.NewMutableServiceBinding("proj", meta.GlobalKey("sb"))
+			sb.Access(func(x *networkservices.ServiceBinding) { x.Service = sdName })
+			sbr, err := sb.Freeze()
+			if err != nil {
+				t.Fatalf("Freeze() = %v", err)
+			}
+			gb := rgraph.NewBuilder()
+			sbb := servicebinding.NewBuilderWithResource(sbr)
+			sbb.SetOwnership(rnode.OwnershipManaged)
+			sbb.SetState(rnode.NodeExists)
+			gb.Add(sbb)
+			gb.Add(servicedirectoryservice.NewBuilder(sdID))
+
+			_, err = Do(context.Background(), mock, gb.MustBuild())
+
+			var meErr *MissingExternalError
+			if gotErr := errors.As(err, &meErr); gotErr != tc.wantErr {
+				t.Fatalf("Do() = %v; errors.As(MissingExternalError) = %t, want %t", err, gotErr, tc.wantErr)
+			}
+			if tc.wantErr && !meErr.ID.Equal(sdID) {
+				t.Errorf("meErr.ID = %v, want %v", meErr.ID, sdID)
+			}
+		})
+	}
+}
+
+func TestPreconditions(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		exists  bool
+		wantErr bool
+	}{
+		{name: "precondition holds", exists: true},
+		{name: "precondition fails", wantErr: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			mock := cloud.NewMockGCE(&cloud.SingleProjectRouter{ID: "proj"})
+			if tc.exists {
+				mock.HealthChecks().Insert(ctx, meta.GlobalKey("other-hc"), &compute.HealthCheck{})
+			}
+
+			g := ez.Graph{Project: "proj", Nodes: []ez.Node{{Name: "hc"}}}
+			b := g.Builder()
+			hcID := healthcheck.ID("proj", meta.GlobalKey("hc"))
+			otherID := healthcheck.ID("proj", meta.GlobalKey("other-hc"))
+			b.Get(hcID).SetPreconditions([]rnode.Precondition{rnode.ResourceExists(otherID)})
+
+			res, err := Do(ctx, mock, b.MustBuild())
+
+			var pErr *rnode.PreconditionError
+			if gotErr := errors.As(err, &pErr); gotErr != tc.wantErr {
+				t.Fatalf("Do() = %v; errors.As(PreconditionError) = %t, want %t", err, gotErr, tc.wantErr)
+			}
+			if tc.wantErr {
+				return
+			}
+
+			// The create must wait for the check Action.
+			var sawCheck bool
+			for _, a := range res.Actions {
+				if _, ok := a.(*preconditionAction); ok {
+					sawCheck = true
+					continue
+				}
+				if id := a.Metadata().ResourceID; id != nil && id.Equal(hcID) && a.CanRun() {
+					t.Errorf("Action %v can run before the preconditions are checked", a)
+				}
+			}
+			if !sawCheck {
+				t.Fatalf("Actions = %v, want a preconditionAction", res.Actions)
+			}
+
+			ex, err := exec.NewSerialExecutor(mock, res.Actions)
+			if err != nil {
+				t.Fatalf("NewSerialExecutor() = %v, want nil", err)
+			}
+			if _, err := ex.Run(ctx); err != nil {
+				t.Fatalf("Run() = %v, want nil", err)
+			}
+		})
+	}
+}
+
+func TestPostconditions(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		p       rnode.Postcondition
+		wantErr bool
+	}{
+		{name: "postcondition holds", p: rnode.FieldIsSet("SelfLink")},
+		{name: "postcondition fails", p: rnode.FieldHasValue("Description", "not-set"), wantErr: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			mock := cloud.NewMockGCE(&cloud.SingleProjectRouter{ID: "proj"})
+
+			g := ez.Graph{Project: "proj", Nodes: []ez.Node{{Name: "hc"}}}
+			b := g.Builder()
+			hcID := healthcheck.ID("proj", meta.GlobalKey("hc"))
+			b.Get(hcID).SetPostconditions([]rnode.Postcondition{tc.p})
+
+			res, err := Do(ctx, mock, b.MustBuild())
+			if err != nil {
+				t.Fatalf("Do() = %v, want nil", err)
+			}
+			var check exec.Action
+			for _, a := range res.Actions {
+				if _, ok := a.(*postconditionAction); ok {
+					check = a
+				}
+			}
+			if check == nil {
+				t.Fatalf("Actions = %v, want a postconditionAction", res.Actions)
+			}
+			if check.CanRun() {
+				t.Errorf("%v can run before the Actions for the Node", check)
+			}
+
+			ex, err := exec.NewSerialExecutor(mock, res.Actions)
+			if err != nil {
+				t.Fatalf("NewSerialExecutor() = %v, want nil", err)
+			}
+			result, err := ex.Run(ctx)
+			if gotErr := errors.Is(err, exec.ErrVerificationFailed); gotErr != tc.wantErr {
+				t.Fatalf("Run() = %v; errors.Is(ErrVerificationFailed) = %t, want %t", err, gotErr, tc.wantErr)
+			}
+			if len(result.Errors) != 0 {
+				t.Errorf("result.Errors = %v, want none", result.Errors)
+			}
+			wantVerification := 0
+			if tc.wantErr {
+				wantVerification = 1
+			}
+			if len(result.VerificationErrors) != wantVerification {
+				t.Errorf("len(result.VerificationErrors) = %d, want %d", len(result.VerificationErrors), wantVerification)
+			}
+		})
+	}
+}
+
+func TestDefaultPhases(t *testing.T) {
+	ctx := context.Background()
+	mock := cloud.NewMockGCE(&cloud.SingleProjectRouter{ID: "proj"})
+	mock.HealthChecks().Insert(ctx, meta.GlobalKey("hc-old"), &compute.HealthCheck{})
+
+	g := ez.Graph{
+		Project: "proj",
+		Nodes: []ez.Node{
+			{Name: "hc"},
+			{Name: "hc-old", Options: ez.DoesNotExist},
+			{Name: "bs", Refs: []ez.Ref{{Field: "Healthchecks", To: "hc"}}},
+			{Name: "um", Refs: []ez.Ref{{Field: "DefaultService", To: "bs"}}},
+		},
+	}
+	res, err := Do(ctx, mock, g.Builder().MustBuild(), PhaseOption(DefaultPhases))
+	if err != nil {
+		t.Fatalf("Do() = %v, want nil", err)
+	}
+
+	wantPhases := map[string]exec.Phase{
+		"hc":     exec.PhaseInfra,
+		"bs":     exec.PhaseInfra,
+		"um":     exec.PhaseRouting,
+		"hc-old": exec.PhaseCleanup,
+	}
+	seen := map[string]bool{}
+	for _, a := range res.Actions {
+		m := a.Metadata()
+		if m.ResourceID == nil || m.Type == exec.ActionTypeMeta {
+			continue
+		}
+		seen[m.ResourceID.Key.Name] = true
+		if want := wantPhases[m.ResourceID.Key.Name]; m.Phase != want {
+			t.Errorf("Action %v has Phase %v, want %v", a, m.Phase, want)
+		}
+	}
+	if len(seen) != len(wantPhases) {
+		t.Errorf("Actions for %v, want Actions for %v", seen, wantPhases)
+	}
+
+	ex, err := exec.NewParallelExecutor(mock, res.Actions)
+	if err != nil {
+		t.Fatalf("NewParallelExecutor() = %v, want nil", err)
+	}
+	if _, err := ex.Run(ctx); err != nil {
+		t.Fatalf("Run() = %v, want nil", err)
+	}
+}
+
+func TestDeleteWithInRef(t *testing.T) {
+	mock := cloud.NewMockGCE(&cloud.SingleProjectRouter{ID: "proj"})
+	mock.HealthChecks().Insert(context.Background(), meta.GlobalKey("hc"), &compute.HealthCheck{})
+
+	// hc is to be deleted but bs still references it.
+	g := ez.Graph{
+		Project: "proj",
+		Nodes: []ez.Node{
+			{Name: "hc", Options: ez.DoesNotExist},
+			{Name: "bs", Refs: []ez.Ref{{Field: "Healthchecks", To: "hc"}}},
+		},
+	}
+	if _, err := Do(context.Background(), mock, g.Builder().MustBuild()); err == nil {
+		t.Fatal("Do() = nil, want error")
+	}
+}
+
+func TestConflictStrategy(t *testing.T) {
+	hcID := healthcheck.ID("proj", meta.GlobalKey("hc"))
+
+	for _, tc := range []struct {
+		name         string
+		opts         []Option
+		nodeStrategy rnode.ConflictStrategy
+		setup        func(x *compute.HealthCheck)
+		wantOp       rnode.Operation
+		wantInterval int64
+		wantErr      bool
+	}{
+		{
+			name:         "default overwrites",
+			wantOp:       rnode.OpUpdate,
+			wantInterval: 5,
+		},
+		{
+			name:    "fail",
+			opts:    []Option{ConflictStrategyOption(rnode.ConflictFail)},
+			wantErr: true,
+		},
+		{
+			name:         "fail overridden by node",
+			opts:         []Option{ConflictStrategyOption(rnode.ConflictFail)},
+			nodeStrategy: rnode.ConflictOverwrite,
+			wantOp:       rnode.OpUpdate,
+			wantInterval: 5,
+		},
+		{
+			name:         "preserve external",
+			opts:         []Option{ConflictStrategyOption(rnode.ConflictPreserveExternal)},
+			wantOp:       rnode.OpUpdate,
+			wantInterval: 10,
+		},
+		{
+			name:         "preserve external, node strategy",
+			nodeStrategy: rnode.ConflictPreserveExternal,
+			wantOp:       rnode.OpUpdate,
+			wantInterval: 10,
+		},
+		{
+			name:         "preserve external, nothing else to update",
+			opts:         []Option{ConflictStrategyOption(rnode.ConflictPreserveExternal)},
+			setup:        func(x *compute.HealthCheck) { x.CheckIntervalSec = 5 },
+			wantOp:       rnode.OpNothing,
+			wantInterval: 10,
+		},
+		{
+			name: "no conflict",
+			opts: []Option{ConflictStrategyOption(rnode.ConflictFail)},
+			setup: func(x *compute.HealthCheck) {
+				x.CheckIntervalSec = 10
+				x.Description = "desc"
+			},
+			wantOp:       rnode.OpUpdate,
+			wantInterval: 10,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			mock := cloud.NewMockGCE(&cloud.SingleProjectRouter{ID: "proj"})
+			// CheckIntervalSec was changed outside of the graph.
+			mock.HealthChecks().Insert(context.Background(), hcID.Key, &compute.HealthCheck{CheckIntervalSec: 10})
+
+			setup := tc.setup
+			if setup == nil {
+				setup = func(x *compute.HealthCheck) {
+					x.CheckIntervalSec = 5
+					x.Description = "desc"
+				}
+			}
+			g := ez.Graph{Project: "proj", Nodes: []ez.Node{{Name: "hc", SetupFunc: setup}}}
+			b := g.Builder()
+			b.Get(hcID).SetConflictStrategy(tc.nodeStrategy)
+
+			res, err := Do(context.Background(), mock, b.MustBuild(), tc.opts...)
+			var conflictErr *ConflictError
+			if tc.wantErr {
+				if !errors.As(err, &conflictErr) {
+					t.Fatalf("Do() = %v, want ConflictError", err)
+				}
+				if !conflictErr.ID.Equal(hcID) {
+					t.Errorf("conflictErr.ID = %v, want %v", conflictErr.ID, hcID)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Do() = %v, want nil", err)
+			}
+			n := res.Want.Get(hcID)
+			if op := n.Plan().Op(); op != tc.wantOp {
+				t.Errorf("Op() = %s, want %s (%s)", op, tc.wantOp, n.Plan().Details().Why)
+			}
+			hc, _ := n.Resource().(healthcheck.HealthCheck).ToGA()
+			if hc.CheckIntervalSec != tc.wantInterval {
+				t.Errorf("CheckIntervalSec = %d, want %d", hc.CheckIntervalSec, tc.wantInterval)
+			}
+		})
+	}
+}
+
+func TestDoInvalidOption(t *testing.T) {
+	mock := cloud.NewMockGCE(&cloud.SingleProjectRouter{ID: "proj"})
+	g := ez.Graph{Project: "proj", Nodes: []ez.Node{{Name: "hc"}}}
+	if _, err := Do(context.Background(), mock, g.Builder().MustBuild(), ConflictStrategyOption("invalid")); err == nil {
+		t.Error("Do() = nil, want error")
+	}
+	if _, err := Do(context.Background(), mock, g.Builder().MustBuild(), StrictFieldsOption("invalid")); err == nil {
+		t.Error("Do() = nil, want error")
+	}
+	if _, err := Do(context.Background(), mock, g.Builder().MustBuild(), IdempotencyOption("plan", "invalid")); err == nil {
+		t.Error("Do() = nil, want error")
+	}
+}
+
+func TestStrictFields(t *testing.T) {
+	mock := cloud.NewMockGCE(&cloud.SingleProjectRouter{ID: "proj"})
+	mock.HealthChecks().Insert(context.Background(), meta.GlobalKey("hc"), &compute.HealthCheck{Description: "unclassified"})
+	g := ez.Graph{Project: "proj", Nodes: []ez.Node{{Name: "hc"}}}
+
+	if _, err := Do(context.Background(), mock, g.Builder().MustBuild(), StrictFieldsOption(rnode.StrictFieldsWarn)); err != nil {
+		t.Errorf("Do(StrictFieldsWarn) = %v, want nil", err)
+	}
+	_, err := Do(context.Background(), mock, g.Builder().MustBuild(), StrictFieldsOption(rnode.StrictFieldsError))
+	var ufErr *rnode.UnclassifiedFieldsError
+	if !errors.As(err, &ufErr) {
+		t.Errorf("Do(StrictFieldsError) = %v, want UnclassifiedFieldsError", err)
+	}
+}
+
+func TestResourceDecodeLazy(t *testing.T) {
+	hcID := healthcheck.ID("proj", meta.GlobalKey("hc"))
+	mock := cloud.NewMockGCE(&cloud.SingleProjectRouter{ID: "proj"})
+	mock.HealthChecks().Insert(context.Background(), hcID.Key, &compute.HealthCheck{CheckIntervalSec: 10})
+	g := ez.Graph{Project: "proj", Nodes: []ez.Node{{Name: "hc", SetupFunc: func(x *compute.HealthCheck) { x.CheckIntervalSec = 5 }}}}
+
+	res, err := Do(context.Background(), mock, g.Builder().MustBuild(), ResourceDecodeOption(rnode.DecodeLazy))
+	if err != nil {
+		t.Fatalf("Do() = %v, want nil", err)
+	}
+	if op := res.Want.Get(hcID).Plan().Op(); op != rnode.OpUpdate {
+		t.Errorf("Op() = %s, want %s", op, rnode.OpUpdate)
+	}
+	hc, err := res.Got.Get(hcID).Resource().(healthcheck.HealthCheck).ToGA()
+	if err != nil {
+		t.Fatalf("ToGA() = %v, want nil", err)
+	}
+	if hc.CheckIntervalSec != 10 {
+		t.Errorf("CheckIntervalSec = %d, want 10", hc.CheckIntervalSec)
+	}
+
+	if _, err := Do(context.Background(), mock, g.Builder().MustBuild(), ResourceDecodeOption("invalid")); err == nil {
+		t.Error("Do(invalid ResourceDecodeMode) = nil, want error")
+	}
+}
+
+func TestVerdict(t *testing.T) {
+	ctx := context.Background()
+	mock := cloud.NewMockGCE(&cloud.SingleProjectRouter{ID: "proj"})
+	mock.HealthChecks().Insert(ctx, meta.GlobalKey("hc-old"), &compute.HealthCheck{})
+	mock.HealthChecks().Insert(ctx, meta.GlobalKey("hc-ext"), &compute.HealthCheck{})
+
+	g := ez.Graph{
+		Project: "proj",
+		Nodes: []ez.Node{
+			{Name: "hc"},
+			{Name: "hc-old", Options: ez.DoesNotExist},
+			{Name: "hc-absent", Options: ez.DoesNotExist},
+			{Name: "hc-ext", Options: ez.External},
+		},
+	}
+	result, err := Do(ctx, mock, g.Builder().MustBuild())
+	if err != nil {
+		t.Fatalf("Do() = %v", err)
+	}
+	for name, want := range map[string]rnode.Verdict{
+		"hc":        rnode.VerdictCreate,
+		"hc-old":    rnode.VerdictDelete,
+		"hc-absent": rnode.VerdictAbsent,
+		"hc-ext":    rnode.VerdictUnmanaged,
+	} {
+		id := healthcheck.ID("proj", meta.GlobalKey(name))
+		got, in, ok := result.Verdict(id)
+		if !ok || got != want {
+			t.Errorf("Verdict(%s) = %s, %t; want %s, true", name, got, ok, want)
+		}
+		if in.Ownership == rnode.OwnershipUnknown || in.GotState == "" || in.WantState == "" {
+			t.Errorf("Verdict(%s) inputs = %+v, want all set", name, in)
+		}
+	}
+	if _, _, ok := result.Verdict(healthcheck.ID("proj", meta.GlobalKey("missing"))); ok {
+		t.Error("Verdict(missing) = _, _, true, want false")
+	}
+}
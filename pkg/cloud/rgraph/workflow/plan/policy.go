@@ -0,0 +1,189 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plan
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/api"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/exec"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode"
+)
+
+// PolicyInput describes a planned Action for a PolicyEvaluator.
+type PolicyInput struct {
+	// ActionType of the planned Action.
+	ActionType exec.ActionType
+	// ID of the resource modified by the Action.
+	ID *cloud.ResourceID
+	// Operation planned for the resource.
+	Operation rnode.Operation
+	// Diff between the current and wanted resource. This is nil if there
+	// is no diff (e.g. for creates and deletes).
+	Diff *api.DiffResult
+	// Want is the Node from the want graph.
+	Want rnode.Node
+}
+
+// PolicyDecision is the result of evaluating a PolicyInput.
+type PolicyDecision struct {
+	// Deny the Action. The plan will fail with a *PolicyDeniedError.
+	Deny bool
+	// Reason is a human readable explanation for the decision.
+	Reason string
+	// Annotations to add to the Action. See exec.WithAnnotations().
+	Annotations map[string]string
+}
+
+// PolicyEvaluator is consulted for each of the planned Actions that modify a
+// resource. An implementation may deny or annotate the Action. Errors
+// returned by Evaluate() fail the plan.
+//
+// PolicyInput is a plain description of the change so that an implementation
+// can forward it to an external policy engine (e.g. OPA).
+type PolicyEvaluator interface {
+	Evaluate(ctx context.Context, in *PolicyInput) (PolicyDecision, error)
+}
+
+// PolicyFunc adapts a function to a PolicyEvaluator.
+type PolicyFunc func(ctx context.Context, in *PolicyInput) (PolicyDecision, error)
+
+// Evaluate implements PolicyEvaluator.
+func (f PolicyFunc) Evaluate(ctx context.Context, in *PolicyInput) (PolicyDecision, error) {
+	return f(ctx, in)
+}
+
+// PolicyOption adds the PolicyEvaluators to the plan. The evaluators are
+// consulted in order; an Action is denied if any of them denies it and the
+// annotations from all of them are added to the Action.
+func PolicyOption(p ...PolicyEvaluator) Option {
+	return func(c *Config) { c.Policies = append(c.Policies, p...) }
+}
+
+// PolicyDeniedError is returned when a PolicyEvaluator denies a planned
+// Action.
+type PolicyDeniedError struct {
+	// ID of the resource.
+	ID *cloud.ResourceID
+	// Action that was denied.
+	Action string
+	// Reason given by the PolicyEvaluator.
+	Reason string
+}
+
+func (e *PolicyDeniedError) Error() string {
+	return fmt.Sprintf("%s: action %q for %v denied by policy: %s", errPrefix, e.Action, e.ID, e.Reason)
+}
+
+// applyPolicies evaluates the Actions against the configured policies. All
+// of the denials are returned as a joined list of *PolicyDeniedError.
+func (pl *planner) applyPolicies(ctx context.Context, acts []exec.Action) ([]exec.Action, error) {
+	if len(pl.config.Policies) == 0 {
+		return acts, nil
+	}
+	var (
+		ret  []exec.Action
+		errs []error
+	)
+	for _, a := range acts {
+		md := a.Metadata()
+		if md.ResourceID == nil || pl.want.Get(md.ResourceID) == nil {
+			ret = append(ret, a)
+			continue
+		}
+		n := pl.want.Get(md.ResourceID)
+		in := &PolicyInput{
+			ActionType: md.Type,
+			ID:         md.ResourceID,
+			Operation:  n.Plan().Op(),
+			Want:       n,
+		}
+		if details := n.Plan().Details(); details != nil {
+			in.Diff = details.Diff
+		}
+		annotations := map[string]string{}
+		for _, p := range pl.config.Policies {
+			d, err := p.Evaluate(ctx, in)
+			if err != nil {
+				return nil, fmt.Errorf("%s: policy for %v: %w", errPrefix, md.ResourceID, err)
+			}
+			if d.Deny {
+				errs = append(errs, &PolicyDeniedError{ID: md.ResourceID, Action: md.Name, Reason: d.Reason})
+			}
+			for k, v := range d.Annotations {
+				annotations[k] = v
+			}
+		}
+		ret = append(ret, exec.WithAnnotations(a, annotations))
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	return ret, nil
+}
+
+// Rules is a simple PolicyEvaluator.
+type Rules struct {
+	// DenyDeleteProjects denies deleting resources in these projects. This
+	// includes the delete of a resource that is being recreated.
+	DenyDeleteProjects []string
+	// RequireLabels denies creating or updating a resource without these
+	// label keys. Resources that do not have Labels are not checked.
+	RequireLabels []string
+}
+
+// Evaluate implements PolicyEvaluator.
+func (r *Rules) Evaluate(_ context.Context, in *PolicyInput) (PolicyDecision, error) {
+	switch in.ActionType {
+	case exec.ActionTypeDelete:
+		for _, p := range r.DenyDeleteProjects {
+			if in.ID.ProjectID == p {
+				return PolicyDecision{Deny: true, Reason: fmt.Sprintf("deletes are not allowed in project %q", p)}, nil
+			}
+		}
+	case exec.ActionTypeCreate, exec.ActionTypeUpdate:
+		if len(r.RequireLabels) == 0 || in.Want == nil || in.Want.Resource() == nil {
+			break
+		}
+		fa, ok := in.Want.Resource().(api.FieldAccessor)
+		if !ok {
+			break
+		}
+		v, err := fa.Field("Labels")
+		if err != nil {
+			// The resource does not have Labels.
+			break
+		}
+		labels, _ := v.(map[string]string)
+		var missing []string
+		for _, k := range r.RequireLabels {
+			if _, ok := labels[k]; !ok {
+				missing = append(missing, k)
+			}
+		}
+		if len(missing) > 0 {
+			sort.Strings(missing)
+			return PolicyDecision{Deny: true, Reason: fmt.Sprintf("missing required labels [%s]", strings.Join(missing, ", "))}, nil
+		}
+	}
+	return PolicyDecision{}, nil
+}
@@ -0,0 +1,137 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plan
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/exec"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/testing/ez"
+	"google.golang.org/api/compute/v1"
+)
+
+func TestPolicy(t *testing.T) {
+	ctx := context.Background()
+
+	for _, tc := range []struct {
+		name       string
+		nodes      []ez.Node
+		policy     PolicyEvaluator
+		wantDenied []string
+	}{
+		{
+			name:   "allow",
+			nodes:  []ez.Node{{Name: "hc", Options: ez.DoesNotExist}},
+			policy: &Rules{DenyDeleteProjects: []string{"other"}},
+		},
+		{
+			name:       "deny delete",
+			nodes:      []ez.Node{{Name: "hc", Options: ez.DoesNotExist}},
+			policy:     &Rules{DenyDeleteProjects: []string{"proj"}},
+			wantDenied: []string{"hc"},
+		},
+		{
+			name: "required labels present",
+			nodes: []ez.Node{{Name: "fr", SetupFunc: func(x *compute.ForwardingRule) {
+				x.Labels = map[string]string{"team": "a"}
+			}}},
+			policy: &Rules{RequireLabels: []string{"team"}},
+		},
+		{
+			name:       "required labels missing",
+			nodes:      []ez.Node{{Name: "fr"}},
+			policy:     &Rules{RequireLabels: []string{"team"}},
+			wantDenied: []string{"fr"},
+		},
+		{
+			name:   "resource without labels",
+			nodes:  []ez.Node{{Name: "hc2"}},
+			policy: &Rules{RequireLabels: []string{"team"}},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			mock := cloud.NewMockGCE(&cloud.SingleProjectRouter{ID: "proj"})
+			mock.HealthChecks().Insert(ctx, meta.GlobalKey("hc"), &compute.HealthCheck{})
+
+			g := ez.Graph{Project: "proj", Nodes: tc.nodes}
+			_, err := Do(ctx, mock, g.Builder().MustBuild(), PolicyOption(tc.policy))
+
+			var denied []string
+			if err != nil {
+				var joined interface{ Unwrap() []error }
+				if !errors.As(err, &joined) {
+					t.Fatalf("Do() = %v, want joined errors", err)
+				}
+				for _, e := range joined.Unwrap() {
+					var pdErr *PolicyDeniedError
+					if !errors.As(e, &pdErr) {
+						t.Fatalf("Do() = %v, want *PolicyDeniedError", e)
+					}
+					denied = append(denied, pdErr.ID.Key.Name)
+				}
+			}
+			if len(denied) != len(tc.wantDenied) {
+				t.Fatalf("denied = %v, want %v", denied, tc.wantDenied)
+			}
+			for i := range denied {
+				if denied[i] != tc.wantDenied[i] {
+					t.Errorf("denied = %v, want %v", denied, tc.wantDenied)
+				}
+			}
+		})
+	}
+}
+
+func TestPolicyAnnotations(t *testing.T) {
+	ctx := context.Background()
+	mock := cloud.NewMockGCE(&cloud.SingleProjectRouter{ID: "proj"})
+
+	var inputs []*PolicyInput
+	p := PolicyFunc(func(_ context.Context, in *PolicyInput) (PolicyDecision, error) {
+		inputs = append(inputs, in)
+		return PolicyDecision{Annotations: map[string]string{"reviewed": string(in.ActionType)}}, nil
+	})
+	g := ez.Graph{Project: "proj", Nodes: []ez.Node{{Name: "hc"}}}
+	result, err := Do(ctx, mock, g.Builder().MustBuild(), PolicyOption(p))
+	if err != nil {
+		t.Fatalf("Do() = %v", err)
+	}
+	if len(inputs) != 1 || inputs[0].ID.Key.Name != "hc" {
+		t.Fatalf("inputs = %v, want [hc]", inputs)
+	}
+	for _, a := range result.Actions {
+		md := a.Metadata()
+		if md.Type != exec.ActionTypeCreate {
+			continue
+		}
+		if got := md.Annotations["reviewed"]; got != string(exec.ActionTypeCreate) {
+			t.Errorf("Annotations[reviewed] = %q, want %q", got, exec.ActionTypeCreate)
+		}
+	}
+
+	wantErr := errors.New("injected")
+	_, err = Do(ctx, mock, g.Builder().MustBuild(), PolicyOption(PolicyFunc(func(context.Context, *PolicyInput) (PolicyDecision, error) {
+		return PolicyDecision{}, wantErr
+	})))
+	if !errors.Is(err, wantErr) {
+		t.Errorf("Do() = %v, want %v", err, wantErr)
+	}
+}
@@ -0,0 +1,112 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plan
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/exec"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode"
+)
+
+// needsPostconditions is true if the Postconditions of the Node must be
+// verified after the plan is applied.
+func needsPostconditions(n rnode.Node) bool {
+	if len(n.Postconditions()) == 0 {
+		return false
+	}
+	switch n.Plan().Op() {
+	case rnode.OpCreate, rnode.OpUpdate, rnode.OpRecreate:
+		return true
+	}
+	return false
+}
+
+// addPostconditionActions adds a verification Action for each Node with
+// Postconditions. The verification Action waits for all of the Actions for
+// the Node to complete.
+func (pl *planner) addPostconditionActions(acts []exec.Action) []exec.Action {
+	checks := map[cloud.ResourceMapKey]*postconditionAction{}
+	for _, n := range pl.actionNodes() {
+		if needsPostconditions(n) {
+			checks[n.ID().MapKey()] = &postconditionAction{node: n}
+		}
+	}
+	if len(checks) == 0 {
+		return acts
+	}
+
+	var ret []exec.Action
+	for _, a := range acts {
+		if id := a.Metadata().ResourceID; id != nil {
+			if check, ok := checks[id.MapKey()]; ok {
+				ev := exec.StringEvent(fmt.Sprintf("Applied(%s)", a.Metadata().Name))
+				check.Want = append(check.Want, ev)
+				a = exec.WithSignal(a, ev)
+			}
+		}
+		ret = append(ret, a)
+	}
+	for _, n := range pl.actionNodes() {
+		if check, ok := checks[n.ID().MapKey()]; ok {
+			ret = append(ret, exec.WithAnnotations(check, n.Annotations()))
+		}
+	}
+	return ret
+}
+
+// postconditionAction verifies the Postconditions of a Node against the
+// Cloud after all of the Actions for the Node have completed.
+type postconditionAction struct {
+	exec.ActionBase
+	node rnode.Node
+}
+
+var _ exec.Action = (*postconditionAction)(nil)
+
+func (a *postconditionAction) Run(ctx context.Context, cl cloud.Cloud) (exec.EventList, error) {
+	got, err := syncNode(ctx, cl, a.node.ID())
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v: %w", errPrefix, a.node.ID(), err)
+	}
+	if errs := rnode.CheckPostconditions(a.node, got); len(errs) > 0 {
+		return nil, &exec.VerificationError{Err: errors.Join(errs...)}
+	}
+	return nil, nil
+}
+
+func (a *postconditionAction) DryRun() exec.EventList { return nil }
+
+func (a *postconditionAction) String() string {
+	return fmt.Sprintf("PostconditionAction(%v)", a.node.ID())
+}
+
+func (a *postconditionAction) Metadata() *exec.ActionMetadata {
+	var checks []string
+	for _, p := range a.node.Postconditions() {
+		checks = append(checks, p.String())
+	}
+	return &exec.ActionMetadata{
+		Name:    a.String(),
+		Type:    exec.ActionTypeMeta,
+		Summary: fmt.Sprintf("Verify postconditions for %v: %s", a.node.ID(), strings.Join(checks, ", ")),
+	}
+}
@@ -0,0 +1,143 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plan
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/exec"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/all"
+)
+
+// needsPreconditions is true if the Preconditions of the Node must be checked
+// for the plan. Preconditions only apply to Nodes that will be mutated into
+// the state in want.
+func needsPreconditions(n rnode.Node) bool {
+	if len(n.Preconditions()) == 0 {
+		return false
+	}
+	switch n.Plan().Op() {
+	case rnode.OpCreate, rnode.OpUpdate, rnode.OpRecreate:
+		return true
+	}
+	return false
+}
+
+// checkPreconditions evaluates the Preconditions of the Nodes against the
+// "got" graph. Resources that are not in the "got" graph are fetched from the
+// Cloud. All of the violations are returned as a joined list of
+// *rnode.PreconditionError.
+func (pl *planner) checkPreconditions(ctx context.Context) error {
+	get := func(ctx context.Context, id *cloud.ResourceID) (rnode.Node, error) {
+		if n := pl.got.Get(id); n != nil {
+			return n, nil
+		}
+		return syncNode(ctx, pl.cloud, id)
+	}
+	var errs []error
+	for _, n := range pl.want.All() {
+		if needsPreconditions(n) {
+			errs = append(errs, rnode.CheckPreconditions(ctx, pl.cloud, n, get)...)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// addPreconditionActions adds a check Action for each Node with
+// Preconditions. The Actions for the Node wait for the check to succeed.
+func (pl *planner) addPreconditionActions(acts []exec.Action) []exec.Action {
+	var ret []exec.Action
+	wants := map[cloud.ResourceMapKey]exec.Event{}
+	for _, n := range pl.actionNodes() {
+		if !needsPreconditions(n) {
+			continue
+		}
+		check := &preconditionAction{node: n}
+		wants[n.ID().MapKey()] = check.event()
+		ret = append(ret, exec.WithAnnotations(check, n.Annotations()))
+	}
+	if len(wants) == 0 {
+		return acts
+	}
+	for _, a := range acts {
+		if id := a.Metadata().ResourceID; id != nil {
+			if ev, ok := wants[id.MapKey()]; ok {
+				a = exec.WithWant(a, ev)
+			}
+		}
+		ret = append(ret, a)
+	}
+	return ret
+}
+
+// syncNode returns the Node id with the current state from the Cloud.
+func syncNode(ctx context.Context, cl cloud.Cloud, id *cloud.ResourceID) (rnode.Node, error) {
+	b, err := all.NewBuilderByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := b.SyncFromCloud(ctx, cl); err != nil {
+		return nil, err
+	}
+	return b.Build()
+}
+
+// preconditionAction re-checks the Preconditions of a Node against the Cloud
+// before any of the Actions for the Node are run.
+type preconditionAction struct {
+	exec.ActionBase
+	node rnode.Node
+}
+
+var _ exec.Action = (*preconditionAction)(nil)
+
+func (a *preconditionAction) event() exec.Event {
+	return exec.StringEvent(fmt.Sprintf("Preconditions(%v)", a.node.ID()))
+}
+
+func (a *preconditionAction) Run(ctx context.Context, cl cloud.Cloud) (exec.EventList, error) {
+	errs := rnode.CheckPreconditions(ctx, cl, a.node, func(ctx context.Context, id *cloud.ResourceID) (rnode.Node, error) {
+		return syncNode(ctx, cl, id)
+	})
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	return exec.EventList{a.event()}, nil
+}
+
+func (a *preconditionAction) DryRun() exec.EventList { return exec.EventList{a.event()} }
+
+func (a *preconditionAction) String() string {
+	return fmt.Sprintf("PreconditionAction(%v)", a.node.ID())
+}
+
+func (a *preconditionAction) Metadata() *exec.ActionMetadata {
+	var checks []string
+	for _, p := range a.node.Preconditions() {
+		checks = append(checks, p.String())
+	}
+	return &exec.ActionMetadata{
+		Name:    a.String(),
+		Type:    exec.ActionTypeMeta,
+		Summary: fmt.Sprintf("Check preconditions for %v: %s", a.node.ID(), strings.Join(checks, ", ")),
+	}
+}
@@ -0,0 +1,184 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plan
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/algo/traversal"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/algo/trclosure"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/exec"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode"
+	"k8s.io/klog/v2"
+)
+
+// Stream plans the graph want and adds the Actions for each Node to ex as
+// soon as the Node and its dependencies (the Nodes it references,
+// transitively) are planned. ex.Run() should be called concurrently so that
+// the Actions for the first Nodes execute while the rest of the graph is
+// planned. Stream() calls ex.Close() when it returns.
+//
+// The Nodes are planned one dependency level at a time (see
+// traversal.DependencyLevels); the current state of the resources is fetched
+// from the Cloud once and reused for the next levels. Deleting a resource
+// depends on the resources that reference it, so the Actions for the Nodes
+// that are deleted or recreated are added once the complete graph is
+// planned. If want deletes a Node with CascadeDelete(), the cascade can
+// change the plan of any Node and the complete graph is planned before any
+// Actions are added.
+//
+// The Result is the plan for the complete graph; Result.Actions are all of
+// the Actions added to ex. Errors in planning are returned after the Actions
+// for the Nodes planned before have been added; these Actions may have
+// already executed.
+func Stream(ctx context.Context, c cloud.Cloud, want *rgraph.Graph, ex exec.StreamingExecutor, opts ...Option) (*Result, error) {
+	defer ex.Close()
+
+	config, err := makeConfig(opts...)
+	if err != nil {
+		return nil, err
+	}
+	levels, err := streamLevels(want)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", errPrefix, err)
+	}
+
+	logger := klog.FromContext(ctx)
+	var (
+		// got is the current state fetched for the previous levels.
+		got     *rgraph.Graph
+		planned = map[cloud.ResourceMapKey]bool{}
+		added   = map[cloud.ResourceMapKey]bool{}
+		acts    []exec.Action
+		cost    float64
+	)
+	for i, level := range levels {
+		for _, n := range level {
+			planned[n.ID().MapKey()] = true
+		}
+		pl := planner{
+			cloud:  c,
+			want:   want,
+			config: config,
+			only:   func(n rnode.Node) bool { return !added[n.ID().MapKey()] },
+		}
+		last := i == len(levels)-1
+		if !last {
+			pl.want = want.Subgraph(func(n rnode.Node) bool { return planned[n.ID().MapKey()] })
+			pl.pending = map[cloud.ResourceMapKey]bool{}
+			for _, n := range want.All() {
+				if !planned[n.ID().MapKey()] {
+					pl.pending[n.ID().MapKey()] = true
+				}
+			}
+			pl.only = func(n rnode.Node) bool {
+				switch n.Plan().Op() {
+				case rnode.OpDelete, rnode.OpRecreate:
+					return false
+				}
+				return !added[n.ID().MapKey()]
+			}
+		}
+
+		gotBuilder, synced, err := streamGotBuilder(pl.want, got)
+		if err != nil {
+			return nil, err
+		}
+		if err := pl.sync(ctx, gotBuilder, synced); err != nil {
+			return nil, err
+		}
+		result, err := pl.planGot(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, n := range pl.actionNodes() {
+			added[n.ID().MapKey()] = true
+		}
+		logger.V(2).Info("Stream level planned", "level", i, "of", len(levels), "nodes", len(level), "actions", len(result.Actions))
+		if err := ex.Add(result.Actions...); err != nil {
+			return nil, fmt.Errorf("%s: %w", errPrefix, err)
+		}
+		acts = append(acts, result.Actions...)
+		cost += result.EstimatedMonthlyCostDelta
+		got = result.Got
+
+		if last {
+			result.Actions = acts
+			result.EstimatedMonthlyCostDelta = cost
+			return result, nil
+		}
+	}
+	// streamLevels always returns at least one level.
+	return nil, fmt.Errorf("%s: no levels to plan", errPrefix)
+}
+
+// streamLevels returns the Nodes of want grouped in the order they are
+// planned by Stream(). There is always at least one level.
+func streamLevels(want *rgraph.Graph) ([][]rnode.Node, error) {
+	for _, n := range want.All() {
+		if n.State() == rnode.NodeDoesNotExist && n.CascadeDelete() {
+			return [][]rnode.Node{want.All()}, nil
+		}
+	}
+	levels, err := traversal.DependencyLevels(want)
+	if err != nil {
+		return nil, err
+	}
+	if len(levels) == 0 {
+		return [][]rnode.Node{nil}, nil
+	}
+	return levels, nil
+}
+
+// streamGotBuilder returns the Builder to fetch the current state of want.
+// The Nodes in got were fetched for the previous levels and are not fetched
+// again, except for the Nodes that could not be synced.
+func streamGotBuilder(want, got *rgraph.Graph) (*rgraph.Builder, trclosure.Option, error) {
+	gotBuilder := rgraph.NewBuilder()
+	synced := map[cloud.ResourceMapKey]bool{}
+	if got != nil {
+		for _, n := range got.All() {
+			nb := n.Builder()
+			switch n.State() {
+			case rnode.NodeExists:
+				r := n.Resource()
+				if r == nil {
+					break
+				}
+				// Node.Builder() does not copy the resource.
+				if err := nb.SetResource(r); err != nil {
+					return nil, nil, fmt.Errorf("%s: %w", errPrefix, err)
+				}
+				synced[n.ID().MapKey()] = true
+			case rnode.NodeDoesNotExist:
+				synced[n.ID().MapKey()] = true
+			}
+			gotBuilder.Add(nb)
+		}
+	}
+	for _, n := range want.All() {
+		if gotBuilder.Get(n.ID()) == nil {
+			gotBuilder.Add(n.Builder())
+		}
+	}
+	return gotBuilder, trclosure.SyncedFunc(func(n rnode.Builder) bool {
+		return synced[n.ID().MapKey()]
+	}), nil
+}
@@ -0,0 +1,268 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plan
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/mock"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/exec"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/testing/ez"
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/api/compute/v1"
+)
+
+// recordingExecutor records the Actions of each Add().
+type recordingExecutor struct {
+	exec.StreamingExecutor
+	adds [][]exec.Action
+}
+
+func (ex *recordingExecutor) Add(actions ...exec.Action) error {
+	ex.adds = append(ex.adds, actions)
+	return ex.StreamingExecutor.Add(actions...)
+}
+
+// addIndex returns the index of the Add() with the Action named name.
+func (ex *recordingExecutor) addIndex(t *testing.T, name string) int {
+	t.Helper()
+	for i, acts := range ex.adds {
+		for _, a := range acts {
+			if a.Metadata().Name == name {
+				return i
+			}
+		}
+	}
+	t.Fatalf("Action %q was not added", name)
+	return -1
+}
+
+// runStream runs Stream() with a StreamingExecutor.
+func runStream(t *testing.T, mock cloud.Cloud, g *ez.Graph, opts ...exec.Option) (*Result, error, *recordingExecutor, *exec.Result, error) {
+	t.Helper()
+	sex, err := exec.NewStreamingExecutor(mock, opts...)
+	if err != nil {
+		t.Fatalf("NewStreamingExecutor() = %v", err)
+	}
+	ex := &recordingExecutor{StreamingExecutor: sex}
+	type runResult struct {
+		result *exec.Result
+		err    error
+	}
+	done := make(chan runResult)
+	go func() {
+		r, err := ex.Run(context.Background())
+		done <- runResult{r, err}
+	}()
+	result, err := Stream(context.Background(), mock, g.Builder().MustBuild(), ex)
+	r := <-done
+	return result, err, ex, r.result, r.err
+}
+
+func actionNames(acts []exec.Action) []string {
+	var ret []string
+	for _, a := range acts {
+		ret = append(ret, a.Metadata().Name)
+	}
+	sort.Strings(ret)
+	return ret
+}
+
+func TestStream(t *testing.T) {
+	mock := cloud.NewMockGCE(&cloud.SingleProjectRouter{ID: "proj"})
+	g := &ez.Graph{
+		Project: "proj",
+		Nodes: []ez.Node{
+			{Name: "hc1"},
+			{Name: "bs1", Refs: []ez.Ref{{Field: "Healthchecks", To: "hc1"}}},
+			{Name: "hc2"},
+			{Name: "bs2", Refs: []ez.Ref{{Field: "Healthchecks", To: "hc2"}}},
+			{Name: "hc3"},
+		},
+	}
+
+	result, err, ex, execResult, execErr := runStream(t, mock, g)
+	if err != nil || execErr != nil {
+		t.Fatalf("Stream() = %v, Run() = %v; want nil, nil", err, execErr)
+	}
+	if got := len(result.Want.All()); got != 5 {
+		t.Errorf("len(result.Want.All()) = %d, want 5", got)
+	}
+	if len(execResult.Completed) != len(result.Actions) {
+		t.Errorf("len(Completed) = %d, want %d", len(execResult.Completed), len(result.Actions))
+	}
+	for _, name := range []string{"bs1", "bs2"} {
+		if _, err := mock.BackendServices().Get(context.Background(), meta.GlobalKey(name)); err != nil {
+			t.Errorf("BackendServices().Get(%s) = %v", name, err)
+		}
+	}
+	// The HealthChecks are added before the BackendServices that
+	// reference them are planned.
+	hc := ex.addIndex(t, "GenericCreateAction(compute/healthChecks:proj/hc1)")
+	bs := ex.addIndex(t, "GenericCreateAction(compute/backendServices:proj/bs1)")
+	if hc >= bs {
+		t.Errorf("HealthCheck added in Add() #%d, BackendService in #%d; want HealthCheck first", hc, bs)
+	}
+}
+
+func TestStreamSharedDependency(t *testing.T) {
+	ctx := context.Background()
+	m := cloud.NewMockGCE(&cloud.SingleProjectRouter{ID: "proj"})
+	m.MockBackendServices.UpdateHook = mock.UpdateBackendServiceHook
+	m.HealthChecks().Insert(ctx, meta.GlobalKey("hc-old"), &compute.HealthCheck{})
+	hcOld := cloud.SelfLink(meta.VersionGA, "proj", "healthChecks", meta.GlobalKey("hc-old"))
+	for _, name := range []string{"bs1", "bs2"} {
+		m.BackendServices().Insert(ctx, meta.GlobalKey(name), &compute.BackendService{HealthChecks: []string{hcOld}})
+	}
+
+	// hc-old is in the current state of both BackendServices and is
+	// deleted once neither references it.
+	g := &ez.Graph{
+		Project: "proj",
+		Nodes: []ez.Node{
+			{Name: "hc1"},
+			{Name: "bs1", Refs: []ez.Ref{{Field: "Healthchecks", To: "hc1"}}},
+			{Name: "hc2"},
+			{Name: "bs2", Refs: []ez.Ref{{Field: "Healthchecks", To: "hc2"}}},
+			{Name: "um", Refs: []ez.Ref{{Field: "DefaultService", To: "bs1"}}},
+		},
+	}
+	_, err, ex, _, execErr := runStream(t, m, g)
+	if err != nil || execErr != nil {
+		t.Fatalf("Stream() = %v, Run() = %v; want nil, nil", err, execErr)
+	}
+	if _, err := m.HealthChecks().Get(ctx, meta.GlobalKey("hc-old")); err == nil {
+		t.Errorf("HealthChecks().Get(hc-old) = nil, want NotFound")
+	}
+	// Deletes are added when the complete graph is planned.
+	del := ex.addIndex(t, "GenericDeleteAction(compute/healthChecks:proj/hc-old)")
+	if del != len(ex.adds)-1 {
+		t.Errorf("delete added in Add() #%d, want last (#%d)", del, len(ex.adds)-1)
+	}
+	for _, name := range []string{"bs1", "bs2"} {
+		if i := ex.addIndex(t, "GenericUpdateAction(compute/backendServices:proj/"+name+")"); i >= del {
+			t.Errorf("update %s added in Add() #%d, want before the delete (#%d)", name, i, del)
+		}
+	}
+}
+
+func TestStreamSameAsDo(t *testing.T) {
+	ctx := context.Background()
+	setup := func() *cloud.MockGCE {
+		m := cloud.NewMockGCE(&cloud.SingleProjectRouter{ID: "proj"})
+		m.HealthChecks().Insert(ctx, meta.GlobalKey("hc-old"), &compute.HealthCheck{})
+		m.HealthChecks().Insert(ctx, meta.GlobalKey("hc-gone"), &compute.HealthCheck{})
+		hcOld := cloud.SelfLink(meta.VersionGA, "proj", "healthChecks", meta.GlobalKey("hc-old"))
+		m.BackendServices().Insert(ctx, meta.GlobalKey("bs1"), &compute.BackendService{HealthChecks: []string{hcOld}})
+		return m
+	}
+	g := &ez.Graph{
+		Project: "proj",
+		Nodes: []ez.Node{
+			{Name: "hc1"},
+			{Name: "bs1", Refs: []ez.Ref{{Field: "Healthchecks", To: "hc1"}}},
+			{Name: "bs2", Refs: []ez.Ref{{Field: "Healthchecks", To: "hc1"}}},
+			{Name: "hc-gone", Options: ez.DoesNotExist},
+		},
+	}
+
+	want, err := Do(ctx, setup(), g.Builder().MustBuild())
+	if err != nil {
+		t.Fatalf("Do() = %v", err)
+	}
+	got, err, _, _, execErr := runStream(t, setup(), g, exec.DryRunOption(true))
+	if err != nil || execErr != nil {
+		t.Fatalf("Stream() = %v, Run() = %v; want nil, nil", err, execErr)
+	}
+	if diff := cmp.Diff(actionNames(got.Actions), actionNames(want.Actions)); diff != "" {
+		t.Errorf("Stream() Actions: -got,+want: %s", diff)
+	}
+}
+
+func TestStreamCascadeDelete(t *testing.T) {
+	ctx := context.Background()
+	m := cloud.NewMockGCE(&cloud.SingleProjectRouter{ID: "proj"})
+	m.HealthChecks().Insert(ctx, meta.GlobalKey("hc"), &compute.HealthCheck{})
+	hc := cloud.SelfLink(meta.VersionGA, "proj", "healthChecks", meta.GlobalKey("hc"))
+	m.BackendServices().Insert(ctx, meta.GlobalKey("bs"), &compute.BackendService{HealthChecks: []string{hc}})
+
+	g := &ez.Graph{
+		Project: "proj",
+		Nodes: []ez.Node{
+			{Name: "hc"},
+			{Name: "bs", Options: ez.DoesNotExist | ez.CascadeDelete},
+		},
+	}
+	_, err, ex, _, execErr := runStream(t, m, g)
+	if err != nil || execErr != nil {
+		t.Fatalf("Stream() = %v, Run() = %v; want nil, nil", err, execErr)
+	}
+	// The complete graph is planned at once.
+	if len(ex.adds) != 1 {
+		t.Errorf("len(adds) = %d, want 1", len(ex.adds))
+	}
+	ex.addIndex(t, "GenericDeleteAction(compute/healthChecks:proj/hc)")
+	if _, err := m.HealthChecks().Get(ctx, meta.GlobalKey("hc")); err == nil {
+		t.Errorf("HealthChecks().Get(hc) = nil, want NotFound")
+	}
+}
+
+func TestStreamBlastRadius(t *testing.T) {
+	ctx := context.Background()
+	m := cloud.NewMockGCE(&cloud.SingleProjectRouter{ID: "proj"})
+	for _, name := range []string{"hc-old1", "hc-old2"} {
+		m.HealthChecks().Insert(ctx, meta.GlobalKey(name), &compute.HealthCheck{})
+	}
+	g := &ez.Graph{
+		Project: "proj",
+		Nodes: []ez.Node{
+			{Name: "hc1"},
+			{Name: "bs1", Refs: []ez.Ref{{Field: "Healthchecks", To: "hc1"}}},
+			{Name: "hc-old1", Options: ez.DoesNotExist},
+			{Name: "hc-old2", Options: ez.DoesNotExist},
+		},
+	}
+	sex, err := exec.NewStreamingExecutor(m)
+	if err != nil {
+		t.Fatalf("NewStreamingExecutor() = %v", err)
+	}
+	ex := &recordingExecutor{StreamingExecutor: sex}
+
+	_, err = Stream(ctx, m, g.Builder().MustBuild(), ex, BlastRadiusOption(BlastRadius{MaxNodes: 1}))
+	var brErr *BlastRadiusError
+	if !errors.As(err, &brErr) {
+		t.Fatalf("Stream() = %v, want BlastRadiusError", err)
+	}
+	if len(brErr.IDs) != 2 {
+		t.Errorf("BlastRadiusError.IDs = %v, want 2 IDs", brErr.IDs)
+	}
+	// The HealthCheck is added before the complete graph is planned; the
+	// deletes are not added.
+	ex.addIndex(t, "GenericCreateAction(compute/healthChecks:proj/hc1)")
+	for _, acts := range ex.adds {
+		for _, a := range acts {
+			if a.Metadata().Type == exec.ActionTypeDelete {
+				t.Errorf("Action %v was added, want no deletes", a)
+			}
+		}
+	}
+}
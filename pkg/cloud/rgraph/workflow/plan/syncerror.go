@@ -0,0 +1,120 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plan
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/algo/traversal"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/algo/trclosure"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode"
+	"k8s.io/klog/v2"
+)
+
+// SyncErrorMode is how the planner handles errors fetching a resource from
+// the Cloud.
+type SyncErrorMode string
+
+const (
+	// SyncErrorFail fails the plan on the first error (default).
+	SyncErrorFail SyncErrorMode = "Fail"
+	// SyncErrorBlock puts the resources that could not be fetched in
+	// rnode.NodeStateError and continues. Nothing is planned for these
+	// resources and for the resources that reference them, directly or
+	// indirectly (rnode.VerdictBlocked). The errors are returned in
+	// Result.SyncErrors.
+	SyncErrorBlock SyncErrorMode = "Block"
+)
+
+// SyncErrorOption sets the SyncErrorMode for the plan.
+func SyncErrorOption(m SyncErrorMode) Option {
+	return func(c *Config) { c.SyncErrors = m }
+}
+
+// SyncError is an error fetching a resource from the Cloud.
+type SyncError struct {
+	// ID of the resource.
+	ID *cloud.ResourceID
+	// Err from the Cloud.
+	Err error
+}
+
+func (e *SyncError) Error() string {
+	return fmt.Sprintf("%s: sync %v: %v", errPrefix, e.ID, e.Err)
+}
+
+func (e *SyncError) Unwrap() error { return e.Err }
+
+// syncErrorOption returns the trclosure.Option that records the sync errors
+// for SyncErrorBlock. Returns nil for SyncErrorFail.
+func (pl *planner) syncErrorOption() trclosure.Option {
+	if pl.config.SyncErrors != SyncErrorBlock {
+		return nil
+	}
+	return trclosure.SyncErrorFunc(func(n rnode.Builder, err error) error {
+		pl.syncErrorsLock.Lock()
+		defer pl.syncErrorsLock.Unlock()
+
+		n.SetState(rnode.NodeStateError)
+		// The Node was not fetched, so the OnGetFunc that sets the
+		// Ownership was not called.
+		n.SetOwnership(rnode.OwnershipManaged)
+		pl.syncErrors = append(pl.syncErrors, &SyncError{ID: n.ID(), Err: err})
+		return nil
+	})
+}
+
+// blockDependents sets the plan of the Nodes that reference a Node that
+// could not be synced to rnode.VerdictBlocked.
+func (pl *planner) blockDependents(ctx context.Context) error {
+	if len(pl.syncErrors) == 0 {
+		return nil
+	}
+	sort.Slice(pl.syncErrors, func(i, j int) bool {
+		return pl.syncErrors[i].ID.String() < pl.syncErrors[j].ID.String()
+	})
+	for _, se := range pl.syncErrors {
+		n := pl.want.Get(se.ID)
+		if n == nil {
+			continue
+		}
+		inRefNodes, err := traversal.TransitiveInRefs(pl.want, n)
+		if err != nil {
+			return err
+		}
+		for _, inRefNode := range inRefNodes {
+			var in rnode.VerdictInputs
+			if d := inRefNode.Plan().Details(); d != nil {
+				if d.Verdict == rnode.VerdictBlocked {
+					continue
+				}
+				in = d.Inputs
+			}
+			klog.FromContext(ctx).V(2).Info("Blocked by sync error", "id", inRefNode.ID(), "dependency", se.ID)
+			inRefNode.Plan().Set(rnode.PlanDetails{
+				Operation: rnode.OpNothing,
+				Why:       fmt.Sprintf("Dependency %v could not be synced: %v", se.ID, se.Err),
+				Verdict:   rnode.VerdictBlocked,
+				Inputs:    in,
+			})
+		}
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plan
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/testing/ez"
+	"google.golang.org/api/compute/v1"
+	"google.golang.org/api/googleapi"
+)
+
+func TestSyncErrors(t *testing.T) {
+	ctx := context.Background()
+	injected := &googleapi.Error{Code: http.StatusInternalServerError, Message: "injected"}
+
+	newMock := func() *cloud.MockGCE {
+		mock := cloud.NewMockGCE(&cloud.SingleProjectRouter{ID: "proj"})
+		mock.MockHealthChecks.GetHook = func(_ context.Context, key *meta.Key, _ *cloud.MockHealthChecks, _ ...cloud.Option) (bool, *compute.HealthCheck, error) {
+			if key.Name == "hc" {
+				return true, nil, injected
+			}
+			return false, nil, nil
+		}
+		return mock
+	}
+	g := ez.Graph{
+		Project: "proj",
+		Nodes: []ez.Node{
+			{Name: "um", Refs: []ez.Ref{{Field: "DefaultService", To: "bs"}}},
+			{Name: "bs", Refs: []ez.Ref{{Field: "Healthchecks", To: "hc"}}},
+			{Name: "hc"},
+			{Name: "hc2"},
+		},
+	}
+
+	t.Run("fail", func(t *testing.T) {
+		for _, opts := range [][]Option{nil, {SyncErrorOption(SyncErrorFail)}} {
+			if _, err := Do(ctx, newMock(), g.Builder().MustBuild(), opts...); !errors.Is(err, injected) {
+				t.Errorf("Do() = %v, want %v", err, injected)
+			}
+		}
+	})
+
+	t.Run("block", func(t *testing.T) {
+		result, err := Do(ctx, newMock(), g.Builder().MustBuild(), SyncErrorOption(SyncErrorBlock))
+		if err != nil {
+			t.Fatalf("Do() = %v", err)
+		}
+		if len(result.SyncErrors) != 1 || result.SyncErrors[0].ID.Key.Name != "hc" || !errors.Is(result.SyncErrors[0], injected) {
+			t.Errorf("SyncErrors = %v, want [hc: injected]", result.SyncErrors)
+		}
+		for name, want := range map[string]rnode.Verdict{
+			"hc":  rnode.VerdictBlocked,
+			"bs":  rnode.VerdictBlocked,
+			"um":  rnode.VerdictBlocked,
+			"hc2": rnode.VerdictCreate,
+		} {
+			for _, n := range result.Want.ByName(name) {
+				got, _, _ := result.Verdict(n.ID())
+				if got != want {
+					t.Errorf("Verdict(%s) = %s, want %s", name, got, want)
+				}
+				if want == rnode.VerdictBlocked && n.Plan().Op() != rnode.OpNothing {
+					t.Errorf("%s Op() = %s, want %s", name, n.Plan().Op(), rnode.OpNothing)
+				}
+			}
+		}
+		for _, a := range result.Actions {
+			if id := a.Metadata().ResourceID; id != nil && id.Key.Name != "hc2" {
+				t.Errorf("Action %s planned for blocked resource", a.Metadata().Name)
+			}
+		}
+	})
+
+	if _, err := Do(ctx, newMock(), g.Builder().MustBuild(), SyncErrorOption("invalid")); err == nil {
+		t.Error("Do(invalid SyncErrorMode) = nil, want error")
+	}
+}
@@ -0,0 +1,72 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plan
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/algo/trclosure"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode"
+)
+
+// WhatIfOption plans against a hypothetical state of the Cloud. For each
+// Node in the "got" graph with the same ID as one of the overrides, the State
+// and Resource of the override are used instead of fetching the resource from
+// the Cloud. Set the override State to rnode.NodeDoesNotExist to plan as if
+// the resource were missing. Overrides for resources that are not reached by
+// the plan are ignored.
+//
+// The remaining resources are still fetched from the Cloud; use a mock Cloud
+// to plan without touching the Cloud at all.
+func WhatIfOption(overrides ...rnode.Builder) Option {
+	return func(c *Config) {
+		if c.WhatIf == nil {
+			c.WhatIf = map[cloud.ResourceMapKey]rnode.Builder{}
+		}
+		for _, o := range overrides {
+			c.WhatIf[o.ID().MapKey()] = o.Clone()
+		}
+	}
+}
+
+// whatIfSyncOption returns the trclosure.Option that applies the WhatIf
+// overrides. Returns nil if there are no overrides.
+func (pl *planner) whatIfSyncOption() trclosure.Option {
+	if len(pl.config.WhatIf) == 0 {
+		return nil
+	}
+	return trclosure.SyncFunc(func(ctx context.Context, cl cloud.Cloud, n rnode.Builder) error {
+		o, ok := pl.config.WhatIf[n.ID().MapKey()]
+		if !ok {
+			return n.SyncFromCloud(ctx, cl)
+		}
+		switch o.State() {
+		case rnode.NodeExists:
+			if err := n.SetResource(o.Clone().Resource()); err != nil {
+				return fmt.Errorf("%s: what-if %v: %w", errPrefix, n.ID(), err)
+			}
+			n.SetState(rnode.NodeExists)
+		case rnode.NodeDoesNotExist:
+			n.SetState(rnode.NodeDoesNotExist)
+		default:
+			return fmt.Errorf("%s: what-if %v has invalid state %s", errPrefix, n.ID(), o.State())
+		}
+		return nil
+	})
+}
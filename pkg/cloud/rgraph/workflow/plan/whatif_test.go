@@ -0,0 +1,106 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plan
+
+import (
+	"context"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/exec"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/backendservice"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/healthcheck"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/testing/ez"
+)
+
+func TestWhatIf(t *testing.T) {
+	ctx := context.Background()
+	mockCloud := cloud.NewMockGCE(&cloud.SingleProjectRouter{ID: "proj"})
+	g := &ez.Graph{
+		Project: "proj",
+		Nodes: []ez.Node{
+			{Name: "hc"},
+			{Name: "bs", Refs: []ez.Ref{{Field: "Healthchecks", To: "hc"}}},
+		},
+	}
+	result, err := Do(ctx, mockCloud, g.Builder().MustBuild())
+	if err != nil {
+		t.Fatalf("Do() = %v", err)
+	}
+	ex, err := exec.NewSerialExecutor(mockCloud, result.Actions)
+	if err != nil {
+		t.Fatalf("NewSerialExecutor() = %v", err)
+	}
+	if _, err := ex.Run(ctx); err != nil {
+		t.Fatalf("Run() = %v", err)
+	}
+
+	hcKey := meta.GlobalKey("hc")
+	hcID := healthcheck.ID("proj", hcKey)
+	bsID := backendservice.ID("proj", meta.GlobalKey("bs"))
+
+	missing := healthcheck.NewBuilder(hcID)
+	missing.SetState(rnode.NodeDoesNotExist)
+
+	hc, err := mockCloud.HealthChecks().Get(ctx, hcKey)
+	if err != nil {
+		t.Fatalf("Get() = %v", err)
+	}
+	// The mock returns the stored object; modify a copy.
+	hcCopy := *hc
+	hcCopy.CheckIntervalSec = 99
+	mutable := healthcheck.NewMutableHealthCheck("proj", hcKey)
+	if err := mutable.Set(&hcCopy); err != nil {
+		t.Fatalf("Set() = %v", err)
+	}
+	r, err := mutable.Freeze()
+	if err != nil {
+		t.Fatalf("Freeze() = %v", err)
+	}
+	misconfigured := healthcheck.NewBuilderWithResource(r)
+	misconfigured.SetState(rnode.NodeExists)
+
+	for _, tc := range []struct {
+		name   string
+		opts   []Option
+		wantHC rnode.Operation
+	}{
+		{name: "no overrides", wantHC: rnode.OpNothing},
+		{name: "missing", opts: []Option{WhatIfOption(missing)}, wantHC: rnode.OpCreate},
+		{name: "misconfigured", opts: []Option{WhatIfOption(misconfigured)}, wantHC: rnode.OpUpdate},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := Do(ctx, mockCloud, g.Builder().MustBuild(), tc.opts...)
+			if err != nil {
+				t.Fatalf("Do() = %v", err)
+			}
+			if op := result.Want.Get(hcID).Plan().Op(); op != tc.wantHC {
+				t.Errorf("hc Op() = %s, want %s", op, tc.wantHC)
+			}
+			if op := result.Want.Get(bsID).Plan().Op(); op != rnode.OpNothing {
+				t.Errorf("bs Op() = %s, want %s", op, rnode.OpNothing)
+			}
+		})
+	}
+
+	// The Cloud is not changed by the overrides.
+	if got, err := mockCloud.HealthChecks().Get(ctx, hcKey); err != nil || got.CheckIntervalSec == 99 {
+		t.Errorf("Get() = %+v, %v; want unchanged", got, err)
+	}
+}
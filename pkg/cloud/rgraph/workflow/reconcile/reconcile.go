@@ -0,0 +1,188 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package reconcile runs ensure.Do() and then verifies that the Cloud matches
+// the graph by planning again. If there are remaining changes (e.g. another
+// actor modified a resource between plan and execute), Ensure is retried with
+// backoff up to a maximum number of attempts.
+package reconcile
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/exec"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/workflow/ensure"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/workflow/plan"
+	"k8s.io/klog/v2"
+)
+
+const errPrefix = "Reconcile"
+
+// Option for Do().
+type Option func(c *Config)
+
+// MaxAttemptsOption sets the maximum number of times Ensure is run.
+func MaxAttemptsOption(n int) Option {
+	return func(c *Config) { c.MaxAttempts = n }
+}
+
+// BackoffOption sets the wait before the second attempt. The backoff doubles
+// after each attempt.
+func BackoffOption(d time.Duration) Option {
+	return func(c *Config) { c.Backoff = d }
+}
+
+// EnsureOptions are passed to ensure.Do().
+func EnsureOptions(opts ...ensure.Option) Option {
+	return func(c *Config) { c.EnsureOptions = append(c.EnsureOptions, opts...) }
+}
+
+// Config for Do().
+type Config struct {
+	// MaxAttempts to run Ensure.
+	MaxAttempts int
+	// Backoff before the second attempt.
+	Backoff time.Duration
+	// EnsureOptions are passed to ensure.Do().
+	EnsureOptions []ensure.Option
+}
+
+func makeConfig(opts ...Option) (*Config, error) {
+	c := &Config{
+		MaxAttempts: 3,
+		Backoff:     5 * time.Second,
+	}
+	for _, o := range opts {
+		o(c)
+	}
+	if c.MaxAttempts < 1 {
+		return nil, fmt.Errorf("%s: invalid MaxAttempts %d", errPrefix, c.MaxAttempts)
+	}
+	if c.Backoff < 0 {
+		return nil, fmt.Errorf("%s: invalid Backoff %v", errPrefix, c.Backoff)
+	}
+	return c, nil
+}
+
+// DriftError is returned when the Cloud does not match the graph after all
+// attempts.
+type DriftError struct {
+	// Attempts that were made.
+	Attempts int
+	// Actions that remain to be done.
+	Actions []exec.Action
+}
+
+func (e *DriftError) Error() string {
+	var names []string
+	for _, a := range e.Actions {
+		names = append(names, a.Metadata().Name)
+	}
+	return fmt.Sprintf("%s: resources still differ after %d attempts: %v", errPrefix, e.Attempts, names)
+}
+
+// Result of Do().
+type Result struct {
+	// Attempts is the number of times Ensure was run.
+	Attempts int
+	// Ensure is the result of each attempt.
+	Ensure []*ensure.Result
+	// Drift are the changes that remained after the last attempt. This is
+	// empty if the reconcile was successful.
+	Drift []exec.Action
+}
+
+// Do reconciles the Cloud to the graph in b. The Builder is built for each
+// attempt, so it must not be modified concurrently.
+func Do(ctx context.Context, cl cloud.Cloud, b *rgraph.Builder, opts ...Option) (*Result, error) {
+	c, err := makeConfig(opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	logger := klog.FromContext(ctx).WithName("Reconcile")
+	result := &Result{}
+	backoff := cloud.Backoff{Initial: c.Backoff, Multiplier: 2}
+	for {
+		result.Attempts++
+
+		er, ensureErr := ensure.Do(ctx, cl, b, c.EnsureOptions...)
+		result.Ensure = append(result.Ensure, er)
+		if er == nil || er.Plan == nil {
+			// Configuration and planning errors will not be fixed by
+			// retrying.
+			return result, fmt.Errorf("%s: %w", errPrefix, ensureErr)
+		}
+
+		if ensureErr == nil {
+			result.Drift, err = drift(ctx, cl, b, c.planOptions())
+			if err != nil {
+				return result, err
+			}
+			if len(result.Drift) == 0 {
+				return result, nil
+			}
+			logger.V(2).Info("Changes remain", "attempt", result.Attempts, "changes", len(result.Drift))
+		} else {
+			logger.V(2).Info("Ensure failed", "attempt", result.Attempts, "err", ensureErr)
+		}
+
+		if result.Attempts >= c.MaxAttempts {
+			if ensureErr != nil {
+				return result, fmt.Errorf("%s: %w", errPrefix, ensureErr)
+			}
+			return result, &DriftError{Attempts: result.Attempts, Actions: result.Drift}
+		}
+
+		if err := backoff.Wait(ctx, result.Attempts); err != nil {
+			return result, fmt.Errorf("%s: %w", errPrefix, err)
+		}
+	}
+}
+
+// planOptions returns the plan.Options from the EnsureOptions so the drift
+// check plans the same way as ensure.Do().
+func (c *Config) planOptions() []plan.Option {
+	var ec ensure.Config
+	for _, o := range c.EnsureOptions {
+		o(&ec)
+	}
+	return ec.PlanOptions
+}
+
+// drift plans the graph against the current state of the Cloud and returns
+// the Actions that would change resources.
+func drift(ctx context.Context, cl cloud.Cloud, b *rgraph.Builder, opts []plan.Option) ([]exec.Action, error) {
+	want, err := b.Build()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", errPrefix, err)
+	}
+	pr, err := plan.Do(ctx, cl, want, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", errPrefix, err)
+	}
+	var ret []exec.Action
+	for _, a := range pr.Actions {
+		if a.Metadata().Type != exec.ActionTypeMeta {
+			ret = append(ret, a)
+		}
+	}
+	return ret, nil
+}
@@ -0,0 +1,182 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reconcile
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/mock"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/forwardingrule"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/testing/ez"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/workflow/ensure"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/workflow/lease"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/workflow/plan"
+	"google.golang.org/api/compute/v1"
+)
+
+func graph() *ez.Graph {
+	return &ez.Graph{
+		Project: "proj",
+		Nodes: []ez.Node{{
+			Name:      "hc",
+			SetupFunc: func(x *compute.HealthCheck) { x.Description = "wanted" },
+		}},
+	}
+}
+
+// tamperOnInsert simulates another actor modifying the HealthCheck after it
+// was created.
+func tamperOnInsert(m *cloud.MockGCE) {
+	m.MockHealthChecks.InsertHook = func(_ context.Context, key *meta.Key, obj *compute.HealthCheck, m *cloud.MockHealthChecks, _ ...cloud.Option) (bool, error) {
+		// obj must not be modified as it is shared with the graph.
+		tampered := *obj
+		tampered.Name = key.Name
+		tampered.Description = "tampered"
+
+		m.Lock.Lock()
+		defer m.Lock.Unlock()
+		m.Objects[*key] = &cloud.MockHealthChecksObj{Obj: &tampered}
+		return true, nil
+	}
+}
+
+func TestDo(t *testing.T) {
+	for _, tc := range []struct {
+		name         string
+		setup        func(m *cloud.MockGCE)
+		wantAttempts int
+		wantDrift    bool
+	}{
+		{
+			name:         "no drift",
+			setup:        func(m *cloud.MockGCE) { m.MockHealthChecks.UpdateHook = mock.UpdateHealthCheckHook },
+			wantAttempts: 1,
+		},
+		{
+			name: "drift fixed by retry",
+			setup: func(m *cloud.MockGCE) {
+				tamperOnInsert(m)
+				m.MockHealthChecks.UpdateHook = mock.UpdateHealthCheckHook
+			},
+			wantAttempts: 2,
+		},
+		{
+			// Without an UpdateHook, the mock ignores updates so the drift
+			// is never fixed.
+			name:         "drift remains",
+			setup:        tamperOnInsert,
+			wantAttempts: 3,
+			wantDrift:    true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			m := cloud.NewMockGCE(&cloud.SingleProjectRouter{ID: "proj"})
+			tc.setup(m)
+
+			r, err := Do(context.Background(), m, graph().Builder(), MaxAttemptsOption(3), BackoffOption(0))
+
+			var driftErr *DriftError
+			if gotDrift := errors.As(err, &driftErr); gotDrift != tc.wantDrift {
+				t.Fatalf("Do() = %v; errors.As(DriftError) = %t, want %t", err, gotDrift, tc.wantDrift)
+			}
+			if !tc.wantDrift && err != nil {
+				t.Fatalf("Do() = %v, want nil", err)
+			}
+			if r.Attempts != tc.wantAttempts {
+				t.Errorf("r.Attempts = %d, want %d", r.Attempts, tc.wantAttempts)
+			}
+			if gotDrift := len(r.Drift) > 0; gotDrift != tc.wantDrift {
+				t.Errorf("r.Drift = %v, want drift=%t", r.Drift, tc.wantDrift)
+			}
+		})
+	}
+}
+
+func TestDoPlanOptions(t *testing.T) {
+	m := cloud.NewMockGCE(&cloud.SingleProjectRouter{ID: "proj"})
+	tamperOnInsert(m)
+	m.MockHealthChecks.UpdateHook = mock.UpdateHealthCheckHook
+
+	// The drift check must plan with the same options as ensure.Do(), so
+	// the update planned to fix the drift is denied in the first attempt.
+	denyUpdates := plan.PolicyFunc(func(_ context.Context, in *plan.PolicyInput) (plan.PolicyDecision, error) {
+		return plan.PolicyDecision{Deny: in.Operation == rnode.OpUpdate, Reason: "no updates"}, nil
+	})
+	r, err := Do(context.Background(), m, graph().Builder(), MaxAttemptsOption(3), BackoffOption(0),
+		EnsureOptions(ensure.PlanOptions(plan.PolicyOption(denyUpdates))))
+
+	var deniedErr *plan.PolicyDeniedError
+	if !errors.As(err, &deniedErr) {
+		t.Fatalf("Do() = %v, want PolicyDeniedError", err)
+	}
+	if r.Attempts != 1 {
+		t.Errorf("r.Attempts = %d, want 1", r.Attempts)
+	}
+}
+
+func TestDoLease(t *testing.T) {
+	ctx := context.Background()
+	m := cloud.NewMockGCE(&cloud.SingleProjectRouter{ID: "proj"})
+	m.MockGlobalForwardingRules.SetLabelsHook = mock.SetLabelsGlobalForwardingRuleHook
+
+	g := &ez.Graph{
+		Project: "proj",
+		Nodes: []ez.Node{{Name: "fr", SetupFunc: func(x *compute.ForwardingRule) {
+			x.Labels = map[string]string{"app": "x"}
+		}}},
+	}
+	frID := forwardingrule.ID("proj", meta.GlobalKey("fr"))
+
+	// The first Do() creates the ForwardingRule; the lease is held from
+	// the second Do() on. The lease labels are removed on release so they
+	// must not show up as drift.
+	for i := 0; i < 2; i++ {
+		l, err := lease.New(frID, "a")
+		if err != nil {
+			t.Fatalf("lease.New() = %v", err)
+		}
+		b := g.Builder()
+		r, err := Do(ctx, m, b, MaxAttemptsOption(2), BackoffOption(0), EnsureOptions(ensure.LeaseOption(l)))
+		if err != nil {
+			t.Fatalf("Do() #%d = %v, want nil", i, err)
+		}
+		if r.Attempts != 1 {
+			t.Errorf("Do() #%d: r.Attempts = %d, want 1", i, r.Attempts)
+		}
+		fr, _ := b.Get(frID).Resource().(forwardingrule.ForwardingRule).ToGA()
+		if _, ok := fr.Labels[lease.HolderKey]; ok {
+			t.Errorf("Do() #%d: Builder Labels = %v, want no lease labels", i, fr.Labels)
+		}
+	}
+}
+
+func TestDoInvalid(t *testing.T) {
+	m := cloud.NewMockGCE(&cloud.SingleProjectRouter{ID: "proj"})
+	for _, opts := range [][]Option{
+		{MaxAttemptsOption(0)},
+		{BackoffOption(-1)},
+	} {
+		if _, err := Do(context.Background(), m, graph().Builder(), opts...); err == nil {
+			t.Errorf("Do() = nil, want error")
+		}
+	}
+}
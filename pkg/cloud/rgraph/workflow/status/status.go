@@ -0,0 +1,261 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package status converts the result of planning and executing a graph into
+// Kubernetes-style Events and conditions, so controllers embedding rgraph
+// report a consistent user-facing status.
+//
+// The types mirror the fields of the Kubernetes core/v1 Event and
+// meta/v1 Condition without depending on the Kubernetes API packages:
+//
+//	for _, ev := range status.Events(planResult, execResult, err) {
+//		recorder.Event(obj, ev.Type, ev.Reason, ev.Message)
+//	}
+//	for _, c := range status.Conditions(planResult, execResult, err) {
+//		meta.SetStatusCondition(&obj.Status.Conditions, metav1.Condition{
+//			Type: c.Type, Status: metav1.ConditionStatus(c.Status), Reason: c.Reason, Message: c.Message,
+//		})
+//	}
+package status
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/exec"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/workflow/plan"
+)
+
+// Event types (core/v1 EventTypeNormal, EventTypeWarning).
+const (
+	EventNormal  = "Normal"
+	EventWarning = "Warning"
+)
+
+// Condition types.
+const (
+	// ConditionReady is True if the Cloud matches the graph.
+	ConditionReady = "Ready"
+	// ConditionReconciled is True if all of the planned Actions were run.
+	ConditionReconciled = "Reconciled"
+	// ConditionError is True if planning or any of the Actions failed.
+	ConditionError = "Error"
+)
+
+// Reasons for the Events and conditions.
+const (
+	ReasonPlanned            = "Planned"
+	ReasonPlanFailed         = "PlanFailed"
+	ReasonSynced             = "Synced"
+	ReasonActionFailed       = "ActionFailed"
+	ReasonActionsPending     = "ActionsPending"
+	ReasonVerificationFailed = "VerificationFailed"
+	ReasonOperationWarning   = "OperationWarning"
+	ReasonNoErrors           = "NoErrors"
+)
+
+// ConditionStatus is the status of a Condition (meta/v1 ConditionStatus).
+type ConditionStatus string
+
+const (
+	ConditionTrue    ConditionStatus = "True"
+	ConditionFalse   ConditionStatus = "False"
+	ConditionUnknown ConditionStatus = "Unknown"
+)
+
+// Event to record for the object being reconciled.
+type Event struct {
+	Type    string
+	Reason  string
+	Message string
+}
+
+// Condition of the object being reconciled.
+type Condition struct {
+	Type               string
+	Status             ConditionStatus
+	Reason             string
+	Message            string
+	LastTransitionTime time.Time
+}
+
+// Recorder records Events. This is satisfied by an adapter around the
+// client-go EventRecorder bound to the object.
+type Recorder interface {
+	Event(eventType, reason, message string)
+}
+
+// Record all of the events with rec.
+func Record(rec Recorder, events []Event) {
+	for _, ev := range events {
+		rec.Event(ev.Type, ev.Reason, ev.Message)
+	}
+}
+
+// PlanSummary returns a message with the number of Nodes for each
+// operation, e.g. "1 create, 2 update". Returns "" if there are no changes.
+func PlanSummary(p *plan.Result) string {
+	if p == nil || p.Want == nil {
+		return ""
+	}
+	counts := map[rnode.Operation]int{}
+	for _, n := range p.Want.All() {
+		counts[n.Plan().Op()]++
+	}
+	var parts []string
+	for _, op := range []rnode.Operation{rnode.OpCreate, rnode.OpUpdate, rnode.OpRecreate, rnode.OpDelete} {
+		if counts[op] > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", counts[op], strings.ToLower(string(op))))
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Events for the result of planning (p) and executing (r) a graph. err is
+// the error returned by the workflow (e.g. ensure.Do()). p and r may be nil
+// if the workflow did not get that far.
+func Events(p *plan.Result, r *exec.Result, err error) []Event {
+	var ret []Event
+	if p == nil {
+		if err != nil {
+			ret = append(ret, Event{Type: EventWarning, Reason: ReasonPlanFailed, Message: err.Error()})
+		}
+		return ret
+	}
+	if s := PlanSummary(p); s != "" {
+		ret = append(ret, Event{Type: EventNormal, Reason: ReasonPlanned, Message: s})
+	}
+	if r == nil {
+		if err != nil {
+			ret = append(ret, Event{Type: EventWarning, Reason: ReasonActionFailed, Message: err.Error()})
+		}
+		return ret
+	}
+	for _, w := range r.Warnings {
+		for _, ow := range w.Warnings {
+			ret = append(ret, Event{
+				Type:    EventWarning,
+				Reason:  ReasonOperationWarning,
+				Message: fmt.Sprintf("%s: %s: %s", w.Action.Metadata().Summary, ow.Code, ow.Message),
+			})
+		}
+	}
+	for _, e := range r.Errors {
+		ret = append(ret, Event{
+			Type:    EventWarning,
+			Reason:  ReasonActionFailed,
+			Message: fmt.Sprintf("%s: %v", e.Action.Metadata().Summary, e.Err),
+		})
+	}
+	for _, e := range r.VerificationErrors {
+		ret = append(ret, Event{
+			Type:    EventWarning,
+			Reason:  ReasonVerificationFailed,
+			Message: fmt.Sprintf("%s: %v", e.Action.Metadata().Summary, e.Err),
+		})
+	}
+	if len(r.Pending) > 0 {
+		ret = append(ret, Event{
+			Type:    EventWarning,
+			Reason:  ReasonActionsPending,
+			Message: fmt.Sprintf("%d actions were not run", len(r.Pending)),
+		})
+	}
+	if err == nil && len(r.Errors) == 0 && len(r.VerificationErrors) == 0 && len(r.Pending) == 0 {
+		ret = append(ret, Event{
+			Type:    EventNormal,
+			Reason:  ReasonSynced,
+			Message: fmt.Sprintf("%d actions completed", len(r.Completed)),
+		})
+	}
+	return ret
+}
+
+// Conditions for the result of planning (p) and executing (r) a graph. See
+// Events() for the parameters. LastTransitionTime is not set; use
+// SetCondition() to merge the conditions into the existing ones.
+func Conditions(p *plan.Result, r *exec.Result, err error) []Condition {
+	reconciled := Condition{Type: ConditionReconciled, Status: ConditionTrue, Reason: ReasonSynced}
+	errCond := Condition{Type: ConditionError, Status: ConditionFalse, Reason: ReasonNoErrors}
+
+	switch {
+	case p == nil:
+		reconciled = Condition{Type: ConditionReconciled, Status: ConditionFalse, Reason: ReasonPlanFailed}
+		errCond = Condition{Type: ConditionError, Status: ConditionTrue, Reason: ReasonPlanFailed}
+	case r == nil && err != nil:
+		reconciled = Condition{Type: ConditionReconciled, Status: ConditionFalse, Reason: ReasonActionFailed}
+		errCond = Condition{Type: ConditionError, Status: ConditionTrue, Reason: ReasonActionFailed}
+	case r == nil:
+		reconciled = Condition{Type: ConditionReconciled, Status: ConditionUnknown, Reason: ReasonPlanned, Message: PlanSummary(p)}
+	case len(r.Errors) > 0:
+		msg := fmt.Sprintf("%s: %v", r.Errors[0].Action.Metadata().Summary, r.Errors[0].Err)
+		reconciled = Condition{Type: ConditionReconciled, Status: ConditionFalse, Reason: ReasonActionFailed, Message: msg}
+		errCond = Condition{Type: ConditionError, Status: ConditionTrue, Reason: ReasonActionFailed, Message: msg}
+	case len(r.Pending) > 0:
+		msg := fmt.Sprintf("%d actions were not run", len(r.Pending))
+		reconciled = Condition{Type: ConditionReconciled, Status: ConditionFalse, Reason: ReasonActionsPending, Message: msg}
+	}
+	if err != nil && errCond.Status != ConditionTrue {
+		reason := ReasonActionFailed
+		switch {
+		case reconciled.Status == ConditionFalse:
+			reason = reconciled.Reason
+		case r != nil && len(r.VerificationErrors) > 0:
+			reason = ReasonVerificationFailed
+		}
+		errCond = Condition{Type: ConditionError, Status: ConditionTrue, Reason: reason}
+	}
+	if err != nil && errCond.Message == "" {
+		errCond.Message = err.Error()
+	}
+	if err != nil && reconciled.Status == ConditionFalse && reconciled.Message == "" {
+		reconciled.Message = err.Error()
+	}
+
+	ready := Condition{Type: ConditionReady, Status: reconciled.Status, Reason: reconciled.Reason, Message: reconciled.Message}
+	if reconciled.Status == ConditionTrue && r != nil && len(r.VerificationErrors) > 0 {
+		e := r.VerificationErrors[0]
+		ready = Condition{
+			Type:    ConditionReady,
+			Status:  ConditionFalse,
+			Reason:  ReasonVerificationFailed,
+			Message: fmt.Sprintf("%s: %v", e.Action.Metadata().Summary, e.Err),
+		}
+	}
+	return []Condition{ready, reconciled, errCond}
+}
+
+// SetCondition sets c in conds, replacing the condition with the same Type.
+// LastTransitionTime is set to now if the Status changed, otherwise it is
+// kept from the existing condition.
+func SetCondition(conds []Condition, c Condition, now time.Time) []Condition {
+	for i := range conds {
+		if conds[i].Type != c.Type {
+			continue
+		}
+		if conds[i].Status == c.Status {
+			c.LastTransitionTime = conds[i].LastTransitionTime
+		} else {
+			c.LastTransitionTime = now
+		}
+		conds[i] = c
+		return conds
+	}
+	c.LastTransitionTime = now
+	return append(conds, c)
+}
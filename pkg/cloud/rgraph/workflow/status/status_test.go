@@ -0,0 +1,170 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package status
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/exec"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/testing/ez"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/workflow/plan"
+	"github.com/google/go-cmp/cmp"
+)
+
+type testAction struct {
+	exec.ActionBase
+	name string
+}
+
+func (a *testAction) Run(context.Context, cloud.Cloud) (exec.EventList, error) { return nil, nil }
+func (a *testAction) DryRun() exec.EventList                                   { return nil }
+func (a *testAction) String() string                                           { return a.name }
+func (a *testAction) Metadata() *exec.ActionMetadata {
+	return &exec.ActionMetadata{Name: a.name, Type: exec.ActionTypeCustom, Summary: a.name}
+}
+
+type recorder struct{ events []Event }
+
+func (r *recorder) Event(eventType, reason, message string) {
+	r.events = append(r.events, Event{Type: eventType, Reason: reason, Message: message})
+}
+
+func TestEventsAndConditions(t *testing.T) {
+	mockCloud := cloud.NewMockGCE(&cloud.SingleProjectRouter{ID: "proj"})
+	g := &ez.Graph{
+		Project: "proj",
+		Nodes: []ez.Node{
+			{Name: "hc"},
+			{Name: "bs", Refs: []ez.Ref{{Field: "Healthchecks", To: "hc"}}},
+		},
+	}
+	p, err := plan.Do(context.Background(), mockCloud, g.Builder().MustBuild())
+	if err != nil {
+		t.Fatalf("plan.Do() = %v", err)
+	}
+	a := &testAction{name: "A"}
+	injected := errors.New("injected")
+
+	type conds struct{ ready, reconciled, err ConditionStatus }
+
+	for _, tc := range []struct {
+		name       string
+		p          *plan.Result
+		r          *exec.Result
+		err        error
+		wantEvents []string
+		wantConds  conds
+	}{
+		{
+			name:       "plan failed",
+			err:        injected,
+			wantEvents: []string{ReasonPlanFailed},
+			wantConds:  conds{ConditionFalse, ConditionFalse, ConditionTrue},
+		},
+		{
+			name:       "planned only",
+			p:          p,
+			wantEvents: []string{ReasonPlanned},
+			wantConds:  conds{ConditionUnknown, ConditionUnknown, ConditionFalse},
+		},
+		{
+			name:       "synced",
+			p:          p,
+			r:          &exec.Result{Completed: []exec.Action{a}},
+			wantEvents: []string{ReasonPlanned, ReasonSynced},
+			wantConds:  conds{ConditionTrue, ConditionTrue, ConditionFalse},
+		},
+		{
+			name: "action failed",
+			p:    p,
+			r: &exec.Result{
+				Errors:  []exec.ActionWithErr{{Action: a, Err: injected}},
+				Pending: []exec.Action{a},
+			},
+			err:        injected,
+			wantEvents: []string{ReasonPlanned, ReasonActionFailed, ReasonActionsPending},
+			wantConds:  conds{ConditionFalse, ConditionFalse, ConditionTrue},
+		},
+		{
+			name: "verification failed",
+			p:    p,
+			r: &exec.Result{
+				Completed:          []exec.Action{a},
+				VerificationErrors: []exec.ActionWithErr{{Action: a, Err: injected}},
+				Warnings:           []exec.ActionWithWarnings{{Action: a, Warnings: []cloud.OperationWarning{{Code: "W", Message: "m"}}}},
+			},
+			err:        exec.ErrVerificationFailed,
+			wantEvents: []string{ReasonPlanned, ReasonOperationWarning, ReasonVerificationFailed},
+			wantConds:  conds{ConditionFalse, ConditionTrue, ConditionTrue},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			rec := &recorder{}
+			Record(rec, Events(tc.p, tc.r, tc.err))
+			var reasons []string
+			for _, ev := range rec.events {
+				reasons = append(reasons, ev.Reason)
+			}
+			if diff := cmp.Diff(reasons, tc.wantEvents); diff != "" {
+				t.Errorf("Events(): diff -got,+want: %s", diff)
+			}
+
+			got := map[string]ConditionStatus{}
+			for _, c := range Conditions(tc.p, tc.r, tc.err) {
+				got[c.Type] = c.Status
+			}
+			want := map[string]ConditionStatus{
+				ConditionReady:      tc.wantConds.ready,
+				ConditionReconciled: tc.wantConds.reconciled,
+				ConditionError:      tc.wantConds.err,
+			}
+			if diff := cmp.Diff(got, want); diff != "" {
+				t.Errorf("Conditions(): diff -got,+want: %s", diff)
+			}
+		})
+	}
+}
+
+func TestPlanSummary(t *testing.T) {
+	mockCloud := cloud.NewMockGCE(&cloud.SingleProjectRouter{ID: "proj"})
+	g := &ez.Graph{Project: "proj", Nodes: []ez.Node{{Name: "hc"}}}
+	p, err := plan.Do(context.Background(), mockCloud, g.Builder().MustBuild())
+	if err != nil {
+		t.Fatalf("plan.Do() = %v", err)
+	}
+	if got := PlanSummary(p); got != "1 create" {
+		t.Errorf("PlanSummary() = %q, want %q", got, "1 create")
+	}
+}
+
+func TestSetCondition(t *testing.T) {
+	t1 := time.Unix(100, 0)
+	t2 := time.Unix(200, 0)
+	conds := SetCondition(nil, Condition{Type: ConditionReady, Status: ConditionFalse}, t1)
+	conds = SetCondition(conds, Condition{Type: ConditionReady, Status: ConditionFalse, Message: "still"}, t2)
+	if len(conds) != 1 || !conds[0].LastTransitionTime.Equal(t1) || conds[0].Message != "still" {
+		t.Errorf("conds = %+v, want transition time unchanged", conds)
+	}
+	conds = SetCondition(conds, Condition{Type: ConditionReady, Status: ConditionTrue}, t2)
+	if len(conds) != 1 || !conds[0].LastTransitionTime.Equal(t2) {
+		t.Errorf("conds = %+v, want transition time %v", conds, t2)
+	}
+}
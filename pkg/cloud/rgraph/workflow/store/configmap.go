@@ -0,0 +1,105 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ConfigMapClient is the subset of the Kubernetes ConfigMap API used by
+// ConfigMapStore. This is implemented by the caller with client-go, so this
+// package does not depend on the Kubernetes client libraries.
+type ConfigMapClient interface {
+	// Get the data and resourceVersion of the ConfigMap. Returns an error
+	// wrapping ErrNotFound if the ConfigMap does not exist.
+	Get(ctx context.Context, namespace, name string) (map[string]string, string, error)
+	// Create the ConfigMap. Returns the resourceVersion or an error wrapping
+	// ErrAlreadyExists.
+	Create(ctx context.Context, namespace, name string, data map[string]string) (string, error)
+	// Update the ConfigMap if its resourceVersion matches. Returns the new
+	// resourceVersion or an error wrapping ErrVersionMismatch (or
+	// ErrNotFound if the ConfigMap does not exist).
+	Update(ctx context.Context, namespace, name string, data map[string]string, resourceVersion string) (string, error)
+}
+
+var (
+	// ErrAlreadyExists is returned by ConfigMapClient.Create() when the
+	// ConfigMap exists.
+	ErrAlreadyExists = errors.New("already exists")
+	// ErrVersionMismatch is returned by ConfigMapClient.Update() when the
+	// resourceVersion is not current.
+	ErrVersionMismatch = errors.New("resourceVersion mismatch")
+)
+
+// ConfigMapDataKey is the key in the ConfigMap data for the snapshot.
+const ConfigMapDataKey = "graph.yaml"
+
+// ConfigMapStore stores each snapshot in a ConfigMap. The version is the
+// ConfigMap resourceVersion. ConfigMaps are limited to 1 MiB, so this is only
+// suitable for small graphs.
+type ConfigMapStore struct {
+	client    ConfigMapClient
+	namespace string
+	prefix    string
+}
+
+var _ Store = (*ConfigMapStore)(nil)
+
+// NewConfigMapStore returns a Store for ConfigMaps in namespace. The
+// ConfigMap name for a key is prefix + key.
+func NewConfigMapStore(client ConfigMapClient, namespace, prefix string) *ConfigMapStore {
+	return &ConfigMapStore{client: client, namespace: namespace, prefix: prefix}
+}
+
+// Get implements Store.
+func (s *ConfigMapStore) Get(ctx context.Context, key string) (*Snapshot, error) {
+	name := s.prefix + key
+	data, rv, err := s.client.Get(ctx, s.namespace, name)
+	if err != nil {
+		return nil, fmt.Errorf("%s: get ConfigMap %s/%s: %w", errPrefix, s.namespace, name, err)
+	}
+	d, ok := data[ConfigMapDataKey]
+	if !ok {
+		return nil, fmt.Errorf("%s: ConfigMap %s/%s has no key %q: %w", errPrefix, s.namespace, name, ConfigMapDataKey, ErrNotFound)
+	}
+	return &Snapshot{Data: []byte(d), Version: rv}, nil
+}
+
+// Put implements Store.
+func (s *ConfigMapStore) Put(ctx context.Context, key string, data []byte, version string) (string, error) {
+	name := s.prefix + key
+	cmData := map[string]string{ConfigMapDataKey: string(data)}
+
+	var (
+		rv  string
+		err error
+	)
+	if version == "" {
+		rv, err = s.client.Create(ctx, s.namespace, name, cmData)
+	} else {
+		rv, err = s.client.Update(ctx, s.namespace, name, cmData, version)
+	}
+	switch {
+	case errors.Is(err, ErrAlreadyExists), errors.Is(err, ErrVersionMismatch), errors.Is(err, ErrNotFound):
+		return "", &ConflictError{Key: key, Version: version}
+	case err != nil:
+		return "", fmt.Errorf("%s: put ConfigMap %s/%s: %w", errPrefix, s.namespace, name, err)
+	}
+	return rv, nil
+}
@@ -0,0 +1,101 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package store
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/cerrors"
+	"google.golang.org/api/storage/v1"
+)
+
+// GCSStore stores each snapshot as an object in a GCS bucket. The version
+// is the object generation.
+type GCSStore struct {
+	svc    *storage.Service
+	bucket string
+	prefix string
+}
+
+var _ Store = (*GCSStore)(nil)
+
+// NewGCSStore returns a Store for the bucket. The object name for a key is
+// prefix + key.
+func NewGCSStore(svc *storage.Service, bucket, prefix string) *GCSStore {
+	return &GCSStore{svc: svc, bucket: bucket, prefix: prefix}
+}
+
+// Get implements Store.
+func (s *GCSStore) Get(ctx context.Context, key string) (*Snapshot, error) {
+	name := s.prefix + key
+	obj, err := s.svc.Objects.Get(s.bucket, name).Context(ctx).Do()
+	if err != nil {
+		if cerrors.IsGoogleAPINotFound(err) {
+			return nil, fmt.Errorf("%s: %q: %w", errPrefix, key, ErrNotFound)
+		}
+		return nil, fmt.Errorf("%s: get gs://%s/%s: %w", errPrefix, s.bucket, name, err)
+	}
+	// Download the generation from the metadata so that the data and the
+	// version match.
+	resp, err := s.svc.Objects.Get(s.bucket, name).Generation(obj.Generation).Context(ctx).Download()
+	if err != nil {
+		if cerrors.IsGoogleAPINotFound(err) {
+			return nil, fmt.Errorf("%s: %q: %w", errPrefix, key, ErrNotFound)
+		}
+		return nil, fmt.Errorf("%s: download gs://%s/%s: %w", errPrefix, s.bucket, name, err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("%s: download gs://%s/%s: %w", errPrefix, s.bucket, name, err)
+	}
+	return &Snapshot{
+		Data:    data,
+		Version: strconv.FormatInt(obj.Generation, 10),
+	}, nil
+}
+
+// Put implements Store.
+func (s *GCSStore) Put(ctx context.Context, key string, data []byte, version string) (string, error) {
+	name := s.prefix + key
+	// Generation 0 is a precondition that the object does not exist.
+	var gen int64
+	if version != "" {
+		var err error
+		gen, err = strconv.ParseInt(version, 10, 64)
+		if err != nil {
+			return "", fmt.Errorf("%s: invalid version %q: %w", errPrefix, version, err)
+		}
+	}
+	obj, err := s.svc.Objects.Insert(s.bucket, &storage.Object{Name: name, ContentType: "application/yaml"}).
+		IfGenerationMatch(gen).
+		Media(bytes.NewReader(data)).
+		Context(ctx).
+		Do()
+	if err != nil {
+		if cerrors.IsGoogleAPIPreconditionFailed(err) {
+			return "", &ConflictError{Key: key, Version: version}
+		}
+		return "", fmt.Errorf("%s: put gs://%s/%s: %w", errPrefix, s.bucket, name, err)
+	}
+	return strconv.FormatInt(obj.Generation, 10), nil
+}
@@ -0,0 +1,159 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package store persists snapshots of graphs, e.g. the last applied graph
+// that is needed for a three-way merge. Snapshots are versioned: Put() only
+// succeeds if the caller has the current version of the snapshot, so two
+// replicas of a controller cannot overwrite each other's updates.
+//
+// Implementations are provided for GCS objects (GCSStore), Kubernetes
+// ConfigMaps (ConfigMapStore) and memory (MemoryStore, for testing).
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/loader"
+)
+
+const errPrefix = "Store"
+
+// ErrNotFound is returned by Get() when there is no snapshot for the key.
+var ErrNotFound = errors.New("snapshot not found")
+
+// ConflictError is returned by Put() when the version given does not match
+// the current version of the snapshot. The caller should Get() the snapshot
+// again and retry.
+type ConflictError struct {
+	Key     string
+	Version string
+}
+
+func (e *ConflictError) Error() string {
+	return fmt.Sprintf("%s: snapshot %q was changed (version %q is not current)", errPrefix, e.Key, e.Version)
+}
+
+// Snapshot of a graph.
+type Snapshot struct {
+	// Data is the encoded graph (see Encode()).
+	Data []byte
+	// Version of the snapshot. This is opaque to the caller and is passed
+	// to Put() to update the snapshot.
+	Version string
+}
+
+// Store for graph snapshots.
+type Store interface {
+	// Get the snapshot for key. Returns an error wrapping ErrNotFound if
+	// there is no snapshot.
+	Get(ctx context.Context, key string) (*Snapshot, error)
+	// Put data as the snapshot for key if the current version is version.
+	// version = "" creates the snapshot; it must not exist. Returns the new
+	// version or *ConflictError if version is not current.
+	Put(ctx context.Context, key string, data []byte, version string) (string, error)
+}
+
+// Encode the graph for storage. The format is the loader YAML document.
+func Encode(g *rgraph.Graph) ([]byte, error) {
+	doc, err := loader.FromGraph(g)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", errPrefix, err)
+	}
+	data, err := doc.Marshal()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", errPrefix, err)
+	}
+	return data, nil
+}
+
+// Decode data returned by Encode().
+func Decode(data []byte) (*rgraph.Builder, error) {
+	b, err := loader.Load(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", errPrefix, err)
+	}
+	return b, nil
+}
+
+// PutGraph encodes g and puts it in s. See Store.Put().
+func PutGraph(ctx context.Context, s Store, key string, g *rgraph.Graph, version string) (string, error) {
+	data, err := Encode(g)
+	if err != nil {
+		return "", err
+	}
+	return s.Put(ctx, key, data, version)
+}
+
+// GetGraph gets the snapshot for key from s and decodes it. Returns the
+// Builder for the graph and the version of the snapshot.
+func GetGraph(ctx context.Context, s Store, key string) (*rgraph.Builder, string, error) {
+	snap, err := s.Get(ctx, key)
+	if err != nil {
+		return nil, "", err
+	}
+	b, err := Decode(snap.Data)
+	if err != nil {
+		return nil, "", err
+	}
+	return b, snap.Version, nil
+}
+
+// MemoryStore keeps the snapshots in memory. This is used for testing.
+type MemoryStore struct {
+	lock      sync.Mutex
+	snapshots map[string]Snapshot
+	next      int
+}
+
+// NewMemoryStore returns an empty MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{snapshots: map[string]Snapshot{}}
+}
+
+// Get implements Store.
+func (s *MemoryStore) Get(_ context.Context, key string) (*Snapshot, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	snap, ok := s.snapshots[key]
+	if !ok {
+		return nil, fmt.Errorf("%s: %q: %w", errPrefix, key, ErrNotFound)
+	}
+	snap.Data = append([]byte{}, snap.Data...)
+	return &snap, nil
+}
+
+// Put implements Store.
+func (s *MemoryStore) Put(_ context.Context, key string, data []byte, version string) (string, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.snapshots[key].Version != version {
+		return "", &ConflictError{Key: key, Version: version}
+	}
+	s.next++
+	snap := Snapshot{
+		Data:    append([]byte{}, data...),
+		Version: strconv.Itoa(s.next),
+	}
+	s.snapshots[key] = snap
+	return snap.Version, nil
+}
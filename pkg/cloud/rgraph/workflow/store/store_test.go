@@ -0,0 +1,240 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/healthcheck"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/testing/ez"
+	"google.golang.org/api/compute/v1"
+	"google.golang.org/api/option"
+	"google.golang.org/api/storage/v1"
+)
+
+func TestStore(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		newStore func(t *testing.T) Store
+	}{
+		{name: "memory", newStore: func(*testing.T) Store { return NewMemoryStore() }},
+		{name: "configmap", newStore: func(*testing.T) Store {
+			return NewConfigMapStore(&fakeConfigMaps{objs: map[string]fakeConfigMap{}}, "ns", "graph-")
+		}},
+		{name: "gcs", newStore: newFakeGCSStore},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			s := tc.newStore(t)
+
+			if _, err := s.Get(ctx, "k"); !errors.Is(err, ErrNotFound) {
+				t.Fatalf("Get() = %v, want ErrNotFound", err)
+			}
+			v1, err := s.Put(ctx, "k", []byte("a"), "")
+			if err != nil {
+				t.Fatalf("Put() = %v", err)
+			}
+			var conflictErr *ConflictError
+			if _, err := s.Put(ctx, "k", []byte("b"), ""); !errors.As(err, &conflictErr) {
+				t.Errorf("Put(create existing) = %v, want ConflictError", err)
+			}
+			snap, err := s.Get(ctx, "k")
+			if err != nil || string(snap.Data) != "a" || snap.Version != v1 {
+				t.Errorf("Get() = %+v, %v; want {a %s}, nil", snap, err, v1)
+			}
+			v2, err := s.Put(ctx, "k", []byte("b"), v1)
+			if err != nil {
+				t.Fatalf("Put() = %v", err)
+			}
+			if v2 == v1 {
+				t.Errorf("Put() = %q, want new version", v2)
+			}
+			if _, err := s.Put(ctx, "k", []byte("c"), v1); !errors.As(err, &conflictErr) {
+				t.Errorf("Put(stale) = %v, want ConflictError", err)
+			}
+
+			// Graph round trip.
+			g := &ez.Graph{
+				Project: "proj",
+				Nodes: []ez.Node{{Name: "hc", SetupFunc: func(x *compute.HealthCheck) {
+					x.Description = "saved"
+					x.Type = "TCP"
+					x.CheckIntervalSec = 5
+					x.TimeoutSec = 5
+					x.HealthyThreshold = 2
+					x.UnhealthyThreshold = 2
+				}}},
+			}
+			if _, err := PutGraph(ctx, s, "g", g.Builder().MustBuild(), ""); err != nil {
+				t.Fatalf("PutGraph() = %v", err)
+			}
+			b, _, err := GetGraph(ctx, s, "g")
+			if err != nil {
+				t.Fatalf("GetGraph() = %v", err)
+			}
+			n := b.MustBuild().Get(healthcheck.ID("proj", meta.GlobalKey("hc")))
+			if n == nil {
+				t.Fatalf("GetGraph() missing hc")
+			}
+			hc, _ := n.Resource().(healthcheck.HealthCheck).ToGA()
+			if hc.Description != "saved" {
+				t.Errorf("hc.Description = %q, want saved", hc.Description)
+			}
+		})
+	}
+}
+
+type fakeConfigMap struct {
+	data map[string]string
+	rv   int
+}
+
+// fakeConfigMaps implements ConfigMapClient.
+type fakeConfigMaps struct {
+	lock sync.Mutex
+	objs map[string]fakeConfigMap
+	rv   int
+}
+
+func (f *fakeConfigMaps) Get(_ context.Context, namespace, name string) (map[string]string, string, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	cm, ok := f.objs[namespace+"/"+name]
+	if !ok {
+		return nil, "", ErrNotFound
+	}
+	return cm.data, strconv.Itoa(cm.rv), nil
+}
+
+func (f *fakeConfigMaps) Create(_ context.Context, namespace, name string, data map[string]string) (string, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	if _, ok := f.objs[namespace+"/"+name]; ok {
+		return "", ErrAlreadyExists
+	}
+	f.rv++
+	f.objs[namespace+"/"+name] = fakeConfigMap{data: data, rv: f.rv}
+	return strconv.Itoa(f.rv), nil
+}
+
+func (f *fakeConfigMaps) Update(_ context.Context, namespace, name string, data map[string]string, rv string) (string, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	cm, ok := f.objs[namespace+"/"+name]
+	if !ok {
+		return "", ErrNotFound
+	}
+	if strconv.Itoa(cm.rv) != rv {
+		return "", ErrVersionMismatch
+	}
+	f.rv++
+	f.objs[namespace+"/"+name] = fakeConfigMap{data: data, rv: f.rv}
+	return strconv.Itoa(f.rv), nil
+}
+
+type fakeObject struct {
+	data       []byte
+	generation int64
+}
+
+// newFakeGCSStore returns a GCSStore for a fake GCS server that supports
+// the calls made by GCSStore.
+func newFakeGCSStore(t *testing.T) Store {
+	var (
+		lock    sync.Mutex
+		objects = map[string]fakeObject{}
+		gen     int64
+	)
+	writeErr := func(w http.ResponseWriter, code int) {
+		w.WriteHeader(code)
+		fmt.Fprintf(w, `{"error": {"code": %d, "message": "fake"}}`, code)
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lock.Lock()
+		defer lock.Unlock()
+
+		switch {
+		case r.Method == http.MethodGet && strings.HasPrefix(r.URL.Path, "/storage/v1/b/bucket/o/"):
+			obj, ok := objects[strings.TrimPrefix(r.URL.Path, "/storage/v1/b/bucket/o/")]
+			if !ok {
+				writeErr(w, http.StatusNotFound)
+				return
+			}
+			if r.URL.Query().Get("alt") == "media" {
+				w.Write(obj.data)
+				return
+			}
+			json.NewEncoder(w).Encode(&storage.Object{Generation: obj.generation})
+		case r.Method == http.MethodPost && r.URL.Path == "/upload/storage/v1/b/bucket/o":
+			_, params, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+			if err != nil {
+				writeErr(w, http.StatusBadRequest)
+				return
+			}
+			mr := multipart.NewReader(r.Body, params["boundary"])
+			var meta storage.Object
+			part, err := mr.NextPart()
+			if err == nil {
+				err = json.NewDecoder(part).Decode(&meta)
+			}
+			var data []byte
+			if err == nil {
+				part, err = mr.NextPart()
+			}
+			if err == nil {
+				data, err = io.ReadAll(part)
+			}
+			if err != nil {
+				writeErr(w, http.StatusBadRequest)
+				return
+			}
+			match, _ := strconv.ParseInt(r.URL.Query().Get("ifGenerationMatch"), 10, 64)
+			if objects[meta.Name].generation != match {
+				writeErr(w, http.StatusPreconditionFailed)
+				return
+			}
+			gen++
+			objects[meta.Name] = fakeObject{data: data, generation: gen}
+			json.NewEncoder(w).Encode(&storage.Object{Name: meta.Name, Generation: gen})
+		default:
+			writeErr(w, http.StatusBadRequest)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	svc, err := storage.NewService(context.Background(),
+		option.WithEndpoint(srv.URL+"/storage/v1/"),
+		option.WithoutAuthentication(),
+	)
+	if err != nil {
+		t.Fatalf("storage.NewService() = %v", err)
+	}
+	return NewGCSStore(svc, "bucket", "graph-")
+}
@@ -0,0 +1,182 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sweep
+
+import (
+	"context"
+	"path"
+	"reflect"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/filter"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/address"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/backendservice"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/endpointpolicy"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/forwardingrule"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/healthcheck"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/networkendpointgroup"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/servicebinding"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/targetgrpcproxy"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/targethttpproxy"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/tcproute"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/urlmap"
+)
+
+// Live is a resource that was listed from the Cloud.
+type Live struct {
+	ID          *cloud.ResourceID
+	Labels      map[string]string
+	Description string
+}
+
+// lister lists all resources of a type in Config.Project. The calls must
+// force the project as the Cloud may be routed to a different default project.
+type lister func(ctx context.Context, cl cloud.Cloud, c *Config) ([]Live, error)
+
+// listers by Resource. Regional and zonal resources are only listed in
+// Config.Regions and Config.Zones.
+var listers = map[string]lister{
+	"addresses": func(ctx context.Context, cl cloud.Cloud, c *Config) ([]Live, error) {
+		ret, err := listGlobal(ctx, c, cl.GlobalAddresses().List, address.ID)
+		if err != nil {
+			return nil, err
+		}
+		return appendRegional(ctx, c, ret, cl.Addresses().List, address.ID)
+	},
+	"backendServices": func(ctx context.Context, cl cloud.Cloud, c *Config) ([]Live, error) {
+		ret, err := listGlobal(ctx, c, cl.BackendServices().List, backendservice.ID)
+		if err != nil {
+			return nil, err
+		}
+		return appendRegional(ctx, c, ret, cl.RegionBackendServices().List, backendservice.ID)
+	},
+	"forwardingRules": func(ctx context.Context, cl cloud.Cloud, c *Config) ([]Live, error) {
+		ret, err := listGlobal(ctx, c, cl.GlobalForwardingRules().List, forwardingrule.ID)
+		if err != nil {
+			return nil, err
+		}
+		return appendRegional(ctx, c, ret, cl.ForwardingRules().List, forwardingrule.ID)
+	},
+	"healthChecks": func(ctx context.Context, cl cloud.Cloud, c *Config) ([]Live, error) {
+		ret, err := listGlobal(ctx, c, cl.HealthChecks().List, healthcheck.ID)
+		if err != nil {
+			return nil, err
+		}
+		return appendRegional(ctx, c, ret, cl.RegionHealthChecks().List, healthcheck.ID)
+	},
+	"networkEndpointGroups": func(ctx context.Context, cl cloud.Cloud, c *Config) ([]Live, error) {
+		ret, err := listGlobal(ctx, c, cl.GlobalNetworkEndpointGroups().List, networkendpointgroup.ID)
+		if err != nil {
+			return nil, err
+		}
+		ret, err = appendRegional(ctx, c, ret, cl.RegionNetworkEndpointGroups().List, networkendpointgroup.ID)
+		if err != nil {
+			return nil, err
+		}
+		for _, zone := range c.Zones {
+			objs, err := cl.NetworkEndpointGroups().List(ctx, zone, filter.None, cloud.ForceProjectID(c.Project))
+			if err != nil {
+				return nil, err
+			}
+			ret = append(ret, toLive(objs, func(name string) *cloud.ResourceID {
+				return networkendpointgroup.ID(c.Project, meta.ZonalKey(name, zone))
+			})...)
+		}
+		return ret, nil
+	},
+	"targetGrpcProxies": func(ctx context.Context, cl cloud.Cloud, c *Config) ([]Live, error) {
+		return listGlobal(ctx, c, cl.TargetGrpcProxies().List, targetgrpcproxy.ID)
+	},
+	"targetHttpProxies": func(ctx context.Context, cl cloud.Cloud, c *Config) ([]Live, error) {
+		ret, err := listGlobal(ctx, c, cl.TargetHttpProxies().List, targethttpproxy.ID)
+		if err != nil {
+			return nil, err
+		}
+		return appendRegional(ctx, c, ret, cl.RegionTargetHttpProxies().List, targethttpproxy.ID)
+	},
+	"urlMaps": func(ctx context.Context, cl cloud.Cloud, c *Config) ([]Live, error) {
+		ret, err := listGlobal(ctx, c, cl.UrlMaps().List, urlmap.ID)
+		if err != nil {
+			return nil, err
+		}
+		return appendRegional(ctx, c, ret, cl.RegionUrlMaps().List, urlmap.ID)
+	},
+	"tcpRoutes": func(ctx context.Context, cl cloud.Cloud, c *Config) ([]Live, error) {
+		return listGlobal(ctx, c, cl.TcpRoutes().List, tcproute.ID)
+	},
+	"serviceBindings": func(ctx context.Context, cl cloud.Cloud, c *Config) ([]Live, error) {
+		return listGlobal(ctx, c, cl.ServiceBindings().List, servicebinding.ID)
+	},
+	"endpointPolicies": func(ctx context.Context, cl cloud.Cloud, c *Config) ([]Live, error) {
+		return listGlobal(ctx, c, cl.EndpointPolicies().List, endpointpolicy.ID)
+	},
+}
+
+func listGlobal[T any](
+	ctx context.Context,
+	c *Config,
+	list func(context.Context, *filter.F, ...cloud.Option) ([]*T, error),
+	id func(string, *meta.Key) *cloud.ResourceID,
+) ([]Live, error) {
+	objs, err := list(ctx, filter.None, cloud.ForceProjectID(c.Project))
+	if err != nil {
+		return nil, err
+	}
+	return toLive(objs, func(name string) *cloud.ResourceID {
+		return id(c.Project, meta.GlobalKey(name))
+	}), nil
+}
+
+func appendRegional[T any](
+	ctx context.Context,
+	c *Config,
+	ret []Live,
+	list func(context.Context, string, *filter.F, ...cloud.Option) ([]*T, error),
+	id func(string, *meta.Key) *cloud.ResourceID,
+) ([]Live, error) {
+	for _, region := range c.Regions {
+		objs, err := list(ctx, region, filter.None, cloud.ForceProjectID(c.Project))
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, toLive(objs, func(name string) *cloud.ResourceID {
+			return id(c.Project, meta.RegionalKey(name, region))
+		})...)
+	}
+	return ret, nil
+}
+
+// toLive extracts the common fields from the API objects. All of the types
+// have .Name and .Description; .Labels is optional.
+func toLive[T any](objs []*T, id func(name string) *cloud.ResourceID) []Live {
+	var ret []Live
+	for _, obj := range objs {
+		v := reflect.ValueOf(obj).Elem()
+		// Network services resources use the full resource path as the
+		// name.
+		l := Live{
+			ID:          id(path.Base(v.FieldByName("Name").String())),
+			Description: v.FieldByName("Description").String(),
+		}
+		if f := v.FieldByName("Labels"); f.IsValid() && f.Kind() == reflect.Map {
+			l.Labels, _ = f.Interface().(map[string]string)
+		}
+		ret = append(ret, l)
+	}
+	return ret
+}
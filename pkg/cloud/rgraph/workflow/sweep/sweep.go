@@ -0,0 +1,286 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package sweep finds orphaned resources: resources that were created by a
+// controller (identified by a name prefix or labels) but are no longer in the
+// graph of resources the controller manages. This can happen when a
+// controller crashes between creating resources and recording them.
+package sweep
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/algo/actions"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/algo/localplan"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/exec"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/all"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/workflow/plan"
+	"k8s.io/klog/v2"
+)
+
+const errPrefix = "Sweep"
+
+// Option for Do().
+type Option func(c *Config)
+
+// PrefixOption selects resources with names starting with prefix.
+func PrefixOption(prefix string) Option {
+	return func(c *Config) { c.Prefix = prefix }
+}
+
+// LabelsOption selects resources that have all of the given labels. Note:
+// resources types without labels will never match.
+func LabelsOption(labels map[string]string) Option {
+	return func(c *Config) { c.Labels = labels }
+}
+
+// RegionsOption lists regional resources in the given regions.
+func RegionsOption(regions ...string) Option {
+	return func(c *Config) { c.Regions = regions }
+}
+
+// ZonesOption lists zonal resources in the given zones.
+func ZonesOption(zones ...string) Option {
+	return func(c *Config) { c.Zones = zones }
+}
+
+// ResourcesOption limits the sweep to the given resource types (e.g.
+// "backendServices").
+func ResourcesOption(resources ...string) Option {
+	return func(c *Config) { c.Resources = resources }
+}
+
+// DeleteOption will generate Delete Actions for the orphans if true. This is
+// false by default; only the list of orphans is returned.
+func DeleteOption(del bool) Option {
+	return func(c *Config) { c.Delete = del }
+}
+
+// Config for the sweep.
+type Config struct {
+	// Project to list resources in.
+	Project string
+	// Prefix of the resource names to select.
+	Prefix string
+	// Labels that must be present to select the resource.
+	Labels map[string]string
+	// Regions to list regional resources.
+	Regions []string
+	// Zones to list zonal resources.
+	Zones []string
+	// Resources to list. Defaults to all supported resources.
+	Resources []string
+	// Delete will generate Delete Actions for the orphans.
+	Delete bool
+}
+
+func makeConfig(project string, opts ...Option) (*Config, error) {
+	c := &Config{Project: project}
+	for r := range listers {
+		c.Resources = append(c.Resources, r)
+	}
+	sort.Strings(c.Resources)
+
+	for _, o := range opts {
+		o(c)
+	}
+
+	if c.Project == "" {
+		return nil, fmt.Errorf("%s: project must be set", errPrefix)
+	}
+	// Sweeping without a selector would select everything in the project.
+	if c.Prefix == "" && len(c.Labels) == 0 {
+		return nil, fmt.Errorf("%s: one of PrefixOption or LabelsOption must be set", errPrefix)
+	}
+	for _, r := range c.Resources {
+		if _, ok := listers[r]; !ok {
+			return nil, fmt.Errorf("%s: unsupported resource %q", errPrefix, r)
+		}
+	}
+	return c, nil
+}
+
+// Matches returns true if the resource is selected by the Config.
+func (c *Config) Matches(l *Live) bool {
+	if c.Prefix != "" && !strings.HasPrefix(l.ID.Key.Name, c.Prefix) {
+		return false
+	}
+	for k, v := range c.Labels {
+		if lv, ok := l.Labels[k]; !ok || lv != v {
+			return false
+		}
+	}
+	return true
+}
+
+// Result of the sweep.
+type Result struct {
+	// Orphans are the selected resources that are not in the graph, sorted
+	// by ID.
+	Orphans []Live
+	// InGraph are the selected resources that are present in the graph,
+	// sorted by ID.
+	InGraph []Live
+	// Protected are the orphans that are deletion protected (see
+	// plan.DeletionProtectedKey). Actions does not delete these resources.
+	// This is only set if DeleteOption(true) was given.
+	Protected []Live
+	// Actions to delete the orphans. This is only set if DeleteOption(true)
+	// was given.
+	Actions []exec.Action
+}
+
+// Do lists the resources in the project and returns the resources that are
+// selected by the options but not present in the graph g.
+func Do(ctx context.Context, cl cloud.Cloud, project string, g *rgraph.Graph, opts ...Option) (*Result, error) {
+	c, err := makeConfig(project, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Result{}
+	for _, r := range c.Resources {
+		live, err := listers[r](ctx, cl, c)
+		if err != nil {
+			return nil, fmt.Errorf("%s: list %s: %w", errPrefix, r, err)
+		}
+		for i := range live {
+			l := &live[i]
+			if !c.Matches(l) {
+				continue
+			}
+			if g.Get(l.ID) != nil {
+				result.InGraph = append(result.InGraph, *l)
+				continue
+			}
+			klog.FromContext(ctx).V(2).Info("Sweep: orphan", "resourceID", l.ID)
+			result.Orphans = append(result.Orphans, *l)
+		}
+	}
+	sort.Slice(result.Orphans, func(i, j int) bool {
+		return result.Orphans[i].ID.String() < result.Orphans[j].ID.String()
+	})
+	sort.Slice(result.InGraph, func(i, j int) bool {
+		return result.InGraph[i].ID.String() < result.InGraph[j].ID.String()
+	})
+
+	if c.Delete && len(result.Orphans) > 0 {
+		result.Actions, result.Protected, err = DeleteActions(ctx, cl, result.Orphans)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
+
+// DeleteActions plans the deletion of the orphans. Resources referenced by
+// the orphans that are not orphans themselves are added to the graph as
+// external so they are left untouched.
+//
+// Orphans that are deletion protected (see plan.DeletionProtectedKey) are
+// not deleted: the orphan may have been dropped from "want" by mistake. These
+// are returned as protected and are left untouched like the external
+// resources.
+//
+// TODO: external resources are fetched only to satisfy the graph
+// invariants; they could be represented without the resource.
+func DeleteActions(ctx context.Context, cl cloud.Cloud, orphans []Live) ([]exec.Action, []Live, error) {
+	var protected []Live
+	gotBuilder := rgraph.NewBuilder()
+	for _, o := range orphans {
+		nb, err := all.NewBuilderByID(o.ID)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%s: %w", errPrefix, err)
+		}
+		if err := nb.SyncFromCloud(ctx, cl); err != nil {
+			return nil, nil, fmt.Errorf("%s: %w", errPrefix, err)
+		}
+		if nb.DeletionProtected() || plan.StoredDeletionProtected(nb) {
+			klog.FromContext(ctx).V(2).Info("Sweep: orphan is deletion protected", "resourceID", o.ID)
+			protected = append(protected, o)
+			nb.SetOwnership(rnode.OwnershipExternal)
+		} else {
+			nb.SetOwnership(rnode.OwnershipManaged)
+		}
+		gotBuilder.Add(nb)
+	}
+	for _, nb := range gotBuilder.All() {
+		outRefs, err := nb.OutRefs()
+		if err != nil {
+			return nil, nil, fmt.Errorf("%s: %w", errPrefix, err)
+		}
+		for _, ref := range outRefs {
+			if gotBuilder.Get(ref.To) != nil {
+				continue
+			}
+			ext, err := all.NewBuilderForRef(ref.To)
+			if err != nil {
+				return nil, nil, fmt.Errorf("%s: %w", errPrefix, err)
+			}
+			if err := ext.SyncFromCloud(ctx, cl); err != nil {
+				return nil, nil, fmt.Errorf("%s: %w", errPrefix, err)
+			}
+			ext.SetOwnership(rnode.OwnershipExternal)
+			gotBuilder.Add(ext)
+		}
+	}
+	got, err := gotBuilder.Build()
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s: %w", errPrefix, err)
+	}
+
+	wantBuilder := rgraph.NewBuilder()
+	for _, n := range got.All() {
+		nb := n.Builder()
+		// Builder() does not copy the resource; the nodes that are left
+		// untouched keep it.
+		if n.Ownership() == rnode.OwnershipManaged {
+			nb.SetState(rnode.NodeDoesNotExist)
+		} else if err := nb.SetResource(n.Resource()); err != nil {
+			return nil, nil, fmt.Errorf("%s: %w", errPrefix, err)
+		}
+		wantBuilder.Add(nb)
+	}
+	want, err := wantBuilder.Build()
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s: %w", errPrefix, err)
+	}
+
+	if err := localplan.PlanWantGraph(got, want); err != nil {
+		return nil, nil, fmt.Errorf("%s: %w", errPrefix, err)
+	}
+	acts, err := actions.Do(got, want)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s: %w", errPrefix, err)
+	}
+
+	// Only return the Delete Actions; the other Actions are no-ops for the
+	// external resources.
+	var ret []exec.Action
+	for _, a := range acts {
+		if a.Metadata().Type == exec.ActionTypeDelete {
+			ret = append(ret, a)
+		}
+	}
+	return ret, protected, nil
+}
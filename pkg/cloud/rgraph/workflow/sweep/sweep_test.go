@@ -0,0 +1,284 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sweep
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/filter"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/exec"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/healthcheck"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/workflow/plan"
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/api/compute/v1"
+	"google.golang.org/api/googleapi"
+)
+
+const project = "proj"
+
+func setupMock(t *testing.T) *cloud.MockGCE {
+	t.Helper()
+
+	ctx := context.Background()
+	mock := cloud.NewMockGCE(&cloud.SingleProjectRouter{ID: project})
+	for _, name := range []string{"k8s-keep", "k8s-hc", "other-hc"} {
+		if err := mock.HealthChecks().Insert(ctx, meta.GlobalKey(name), &compute.HealthCheck{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	hcLink := cloud.SelfLink(meta.VersionGA, project, "healthChecks", meta.GlobalKey("k8s-hc"))
+	if err := mock.BackendServices().Insert(ctx, meta.GlobalKey("k8s-bs"), &compute.BackendService{HealthChecks: []string{hcLink}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := mock.Addresses().Insert(ctx, meta.RegionalKey("addr", "us-central1"), &compute.Address{Labels: map[string]string{"owner": "k8s"}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := mock.Addresses().Insert(ctx, meta.RegionalKey("addr2", "us-central1"), &compute.Address{Labels: map[string]string{"owner": "other"}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := mock.NetworkEndpointGroups().Insert(ctx, meta.ZonalKey("k8s-neg", "us-central1-b"), &compute.NetworkEndpointGroup{}); err != nil {
+		t.Fatal(err)
+	}
+	return mock
+}
+
+// graph returns a graph with the "k8s-keep" HealthCheck.
+func graph() *rgraph.Graph {
+	b := rgraph.NewBuilder()
+	r, _ := healthcheck.NewMutableHealthCheck(project, meta.GlobalKey("k8s-keep")).Freeze()
+	nb := healthcheck.NewBuilderWithResource(r)
+	nb.SetOwnership(rnode.OwnershipManaged)
+	nb.SetState(rnode.NodeExists)
+	b.Add(nb)
+	return b.MustBuild()
+}
+
+func orphanNames(r *Result) []string {
+	var ret []string
+	for _, o := range r.Orphans {
+		ret = append(ret, o.ID.String())
+	}
+	return ret
+}
+
+func TestDo(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		opts    []Option
+		want    []string
+		wantErr bool
+	}{
+		{
+			name: "prefix",
+			opts: []Option{PrefixOption("k8s-")},
+			want: []string{
+				"compute/backendServices:proj/k8s-bs",
+				"compute/healthChecks:proj/k8s-hc",
+			},
+		},
+		{
+			name: "labels",
+			opts: []Option{LabelsOption(map[string]string{"owner": "k8s"}), RegionsOption("us-central1")},
+			want: []string{"compute/addresses:proj/us-central1/addr"},
+		},
+		{
+			name: "resources",
+			opts: []Option{PrefixOption("k8s-"), ResourcesOption("backendServices")},
+			want: []string{"compute/backendServices:proj/k8s-bs"},
+		},
+		{
+			name: "zones",
+			opts: []Option{PrefixOption("k8s-"), ResourcesOption("networkEndpointGroups"), ZonesOption("us-central1-b")},
+			want: []string{"compute/networkEndpointGroups:proj/us-central1-b/k8s-neg"},
+		},
+		{
+			name:    "no selector",
+			wantErr: true,
+		},
+		{
+			name:    "invalid resource",
+			opts:    []Option{PrefixOption("k8s-"), ResourcesOption("foos")},
+			wantErr: true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			mock := setupMock(t)
+			r, err := Do(context.Background(), mock, project, graph(), tc.opts...)
+			if gotErr := err != nil; gotErr != tc.wantErr {
+				t.Fatalf("Do() = %v, want err=%t", err, tc.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if diff := cmp.Diff(orphanNames(r), tc.want); diff != "" {
+				t.Errorf("Orphans: diff -got,+want: %s", diff)
+			}
+			if len(r.Actions) != 0 {
+				t.Errorf("len(Actions) = %d, want 0 (DeleteOption not set)", len(r.Actions))
+			}
+		})
+	}
+}
+
+func TestDoForcesProject(t *testing.T) {
+	// The mock routes to a default project that differs from the project
+	// being swept; every List must force the project.
+	mock := cloud.NewMockGCE(&cloud.SingleProjectRouter{ID: "default-proj"})
+	ctx := context.Background()
+	for _, name := range []string{"k8s-keep", "k8s-hc"} {
+		if err := mock.HealthChecks().Insert(ctx, meta.GlobalKey(name), &compute.HealthCheck{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	checkProject := func(name string, options []cloud.Option) {
+		for _, o := range options {
+			if o == cloud.ForceProjectID(project) {
+				return
+			}
+		}
+		t.Errorf("%s.List(options = %v), want ForceProjectID(%q)", name, options, project)
+	}
+	mock.MockHealthChecks.ListHook = func(_ context.Context, _ *filter.F, _ *cloud.MockHealthChecks, options ...cloud.Option) (bool, []*compute.HealthCheck, error) {
+		checkProject("HealthChecks", options)
+		return false, nil, nil
+	}
+	mock.MockRegionHealthChecks.ListHook = func(_ context.Context, _ string, _ *filter.F, _ *cloud.MockRegionHealthChecks, options ...cloud.Option) (bool, []*compute.HealthCheck, error) {
+		checkProject("RegionHealthChecks", options)
+		return false, nil, nil
+	}
+	mock.MockNetworkEndpointGroups.ListHook = func(_ context.Context, _ string, _ *filter.F, _ *cloud.MockNetworkEndpointGroups, options ...cloud.Option) (bool, []*compute.NetworkEndpointGroup, error) {
+		checkProject("NetworkEndpointGroups", options)
+		return false, nil, nil
+	}
+
+	r, err := Do(ctx, mock, project, graph(),
+		PrefixOption("k8s-"),
+		ResourcesOption("healthChecks", "networkEndpointGroups"),
+		RegionsOption("us-central1"),
+		ZonesOption("us-central1-b"))
+	if err != nil {
+		t.Fatalf("Do() = %v, want nil", err)
+	}
+	if diff := cmp.Diff(orphanNames(r), []string{"compute/healthChecks:proj/k8s-hc"}); diff != "" {
+		t.Errorf("Orphans: diff -got,+want: %s", diff)
+	}
+}
+
+func TestDoListError(t *testing.T) {
+	mock := setupMock(t)
+	var listErr error = &googleapi.Error{Code: http.StatusInternalServerError}
+	mock.MockHealthChecks.ListError = &listErr
+
+	if _, err := Do(context.Background(), mock, project, graph(), PrefixOption("k8s-")); err == nil {
+		t.Errorf("Do() = nil, want error")
+	}
+}
+
+func TestDoDelete(t *testing.T) {
+	ctx := context.Background()
+	mock := setupMock(t)
+
+	r, err := Do(ctx, mock, project, graph(), PrefixOption("k8s-"), DeleteOption(true))
+	if err != nil {
+		t.Fatalf("Do() = %v, want nil", err)
+	}
+
+	var names []string
+	for _, a := range r.Actions {
+		names = append(names, a.Metadata().Name)
+	}
+	sort.Strings(names)
+	wantNames := []string{
+		"GenericDeleteAction(compute/backendServices:proj/k8s-bs)",
+		"GenericDeleteAction(compute/healthChecks:proj/k8s-hc)",
+	}
+	if diff := cmp.Diff(names, wantNames); diff != "" {
+		t.Fatalf("Actions: diff -got,+want: %s", diff)
+	}
+
+	ex, err := exec.NewSerialExecutor(mock, r.Actions)
+	if err != nil {
+		t.Fatalf("NewSerialExecutor() = %v", err)
+	}
+	if _, err := ex.Run(ctx); err != nil {
+		t.Fatalf("Run() = %v, want nil", err)
+	}
+
+	var hcNames []string
+	hcs, _ := mock.HealthChecks().List(ctx, filter.None)
+	for _, hc := range hcs {
+		hcNames = append(hcNames, hc.Name)
+	}
+	sort.Strings(hcNames)
+	if diff := cmp.Diff(hcNames, []string{"k8s-keep", "other-hc"}); diff != "" {
+		t.Errorf("HealthChecks after sweep: diff -got,+want: %s", diff)
+	}
+	bss, _ := mock.BackendServices().List(ctx, filter.None)
+	if len(bss) != 0 {
+		t.Errorf("len(BackendServices) after sweep = %d, want 0", len(bss))
+	}
+}
+
+func TestDoDeleteProtected(t *testing.T) {
+	ctx := context.Background()
+	mock := setupMock(t)
+	// "k8s-hc" was DeletionProtected in "want" when it was last applied.
+	d := &cloud.Description{}
+	d.SetValue(plan.DeletionProtectedKey, "true")
+	hc, err := mock.HealthChecks().Get(ctx, meta.GlobalKey("k8s-hc"))
+	if err != nil {
+		t.Fatalf("Get() = %v", err)
+	}
+	hc.Description = d.String()
+
+	r, err := Do(ctx, mock, project, graph(), PrefixOption("k8s-"), DeleteOption(true))
+	if err != nil {
+		t.Fatalf("Do() = %v, want nil", err)
+	}
+	var protected []string
+	for _, l := range r.Protected {
+		protected = append(protected, l.ID.String())
+	}
+	if diff := cmp.Diff(protected, []string{"compute/healthChecks:proj/k8s-hc"}); diff != "" {
+		t.Errorf("Protected: diff -got,+want: %s", diff)
+	}
+	var names []string
+	for _, a := range r.Actions {
+		names = append(names, a.Metadata().Name)
+	}
+	if diff := cmp.Diff(names, []string{"GenericDeleteAction(compute/backendServices:proj/k8s-bs)"}); diff != "" {
+		t.Fatalf("Actions: diff -got,+want: %s", diff)
+	}
+
+	ex, err := exec.NewSerialExecutor(mock, r.Actions)
+	if err != nil {
+		t.Fatalf("NewSerialExecutor() = %v", err)
+	}
+	if _, err := ex.Run(ctx); err != nil {
+		t.Fatalf("Run() = %v, want nil", err)
+	}
+	if _, err := mock.HealthChecks().Get(ctx, meta.GlobalKey("k8s-hc")); err != nil {
+		t.Errorf("Get(k8s-hc) = %v, want the protected orphan to survive the sweep", err)
+	}
+}
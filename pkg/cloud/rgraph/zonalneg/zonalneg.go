@@ -0,0 +1,258 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package zonalneg expands a logical NetworkEndpointGroup into one zonal NEG
+// per zone in the graph. This is the common pattern for a K8s Service backed
+// by a cluster that spans multiple zones.
+//
+//	neg := &zonalneg.NEG{
+//		Project:        "proj",
+//		Name:           "k8s-neg",
+//		Zones:          []string{"us-central1-a", "us-central1-b"},
+//		BackendService: backendservice.ID("proj", meta.GlobalKey("bs")),
+//	}
+//	err := neg.Expand(builder)
+//
+// When the set of zones changes, Expand() marks the NEGs in the removed zones
+// for deletion and removes them from the BackendService backends.
+package zonalneg
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/filter"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/backendservice"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/networkendpointgroup"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/topology"
+	"google.golang.org/api/compute/v1"
+)
+
+const errPrefix = "ZonalNEG"
+
+// NEG is a logical NetworkEndpointGroup that has a zonal NEG with the same
+// name in each of the Zones.
+type NEG struct {
+	// Project of the NEGs.
+	Project string
+	// Name of the NEG in each zone.
+	Name string
+	// Zones to create the NEG in.
+	Zones []string
+	// Setup is called to fill in the NEG for the zone (e.g. Network,
+	// NetworkEndpointType). Name is set automatically. Optional.
+	Setup func(zone string, x *compute.NetworkEndpointGroup)
+	// BackendService, if set, has its Backends kept in sync with the zonal
+	// NEGs. The BackendService must already be in the Builder and must be
+	// the GA version.
+	BackendService *cloud.ResourceID
+	// Backend is called to fill in a new Backend for the zone (e.g.
+	// BalancingMode). Group is set automatically. Optional.
+	Backend func(zone string, x *compute.Backend)
+}
+
+// ID of the NEG in the zone.
+func (n *NEG) ID(zone string) *cloud.ResourceID {
+	return networkendpointgroup.ID(n.Project, meta.ZonalKey(n.Name, zone))
+}
+
+// IDs of the NEGs for all of the Zones.
+func (n *NEG) IDs() []*cloud.ResourceID {
+	var ret []*cloud.ResourceID
+	for _, z := range n.sortedZones() {
+		ret = append(ret, n.ID(z))
+	}
+	return ret
+}
+
+func (n *NEG) sortedZones() []string {
+	zones := append([]string{}, n.Zones...)
+	sort.Strings(zones)
+	return zones
+}
+
+// SetZonesFrom sets Zones to all of the zones in the region as returned by
+// the topology Provider.
+func (n *NEG) SetZonesFrom(ctx context.Context, p topology.Provider, region string) error {
+	zones, err := p.Zones(ctx, region)
+	if err != nil {
+		return fmt.Errorf("%s: %w", errPrefix, err)
+	}
+	n.Zones = zones
+	return nil
+}
+
+// isZonalNEG returns true if id is a NEG for this logical NEG (in any
+// zone).
+func (n *NEG) isZonalNEG(id *cloud.ResourceID) bool {
+	return id.Resource == "networkEndpointGroups" &&
+		id.ProjectID == n.Project &&
+		id.Key.Type() == meta.Zonal &&
+		id.Key.Name == n.Name
+}
+
+// Expand adds a NEG for each zone to the Builder. NEGs in the Builder for
+// zones that are no longer in Zones are marked as NodeDoesNotExist so they
+// will be deleted. If BackendService is set, its Backends are updated to
+// reference exactly the NEGs in Zones; Backends for other groups are not
+// changed.
+func (n *NEG) Expand(b *rgraph.Builder) error {
+	if n.Name == "" || len(n.Zones) == 0 {
+		return fmt.Errorf("%s: Name and Zones must be set", errPrefix)
+	}
+	zones := map[string]bool{}
+	for _, z := range n.Zones {
+		zones[z] = true
+	}
+
+	for _, z := range n.sortedZones() {
+		nb, err := n.builder(z)
+		if err != nil {
+			return err
+		}
+		b.Add(nb)
+	}
+
+	for _, nb := range b.All() {
+		id := nb.ID()
+		if n.isZonalNEG(id) && !zones[id.Key.Zone] {
+			nb.SetState(rnode.NodeDoesNotExist)
+			nb.SetOwnership(rnode.OwnershipManaged)
+		}
+	}
+
+	if n.BackendService != nil {
+		return n.syncBackends(b, zones)
+	}
+	return nil
+}
+
+// AddStale lists the NEGs named Name in the Cloud and adds the ones in zones
+// not in Zones to the Builder to be deleted. Use this when the Builder is
+// created from scratch each time and does not have the NEGs from the
+// previous expansion.
+func (n *NEG) AddStale(ctx context.Context, cl cloud.Cloud, b *rgraph.Builder) error {
+	zones := map[string]bool{}
+	for _, z := range n.Zones {
+		zones[z] = true
+	}
+	all, err := cl.NetworkEndpointGroups().AggregatedList(ctx, filter.Regexp("name", n.Name), cloud.ForceProjectID(n.Project))
+	if err != nil {
+		return fmt.Errorf("%s: AggregatedList: %w", errPrefix, err)
+	}
+	for _, negs := range all {
+		for _, neg := range negs {
+			id, err := cloud.ParseResourceURL(neg.SelfLink)
+			if err != nil {
+				return fmt.Errorf("%s: %w", errPrefix, err)
+			}
+			if !n.isZonalNEG(id) || zones[id.Key.Zone] || b.Get(id) != nil {
+				continue
+			}
+			nb := networkendpointgroup.NewBuilder(id)
+			nb.SetState(rnode.NodeDoesNotExist)
+			nb.SetOwnership(rnode.OwnershipManaged)
+			b.Add(nb)
+		}
+	}
+	return nil
+}
+
+func (n *NEG) builder(zone string) (rnode.Builder, error) {
+	id := n.ID(zone)
+	neg := &compute.NetworkEndpointGroup{}
+	if n.Setup != nil {
+		n.Setup(zone, neg)
+	}
+	neg.Name = n.Name
+
+	m := networkendpointgroup.NewMutableNetworkEndpointGroup(id.ProjectID, id.Key)
+	if err := m.Set(neg); err != nil {
+		return nil, fmt.Errorf("%s: %s: %w", errPrefix, id, err)
+	}
+	r, err := m.Freeze()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %s: %w", errPrefix, id, err)
+	}
+	nb := networkendpointgroup.NewBuilder(id)
+	if err := nb.SetResource(r); err != nil {
+		return nil, fmt.Errorf("%s: %s: %w", errPrefix, id, err)
+	}
+	nb.SetState(rnode.NodeExists)
+	nb.SetOwnership(rnode.OwnershipManaged)
+	return nb, nil
+}
+
+// syncBackends updates the Backends of the BackendService to reference the
+// NEGs in zones.
+func (n *NEG) syncBackends(b *rgraph.Builder, zones map[string]bool) error {
+	bsb := b.Get(n.BackendService)
+	if bsb == nil {
+		return fmt.Errorf("%s: BackendService %s is not in the graph", errPrefix, n.BackendService)
+	}
+	res, ok := bsb.Resource().(backendservice.BackendService)
+	if !ok || res == nil {
+		return fmt.Errorf("%s: BackendService %s has invalid resource %T", errPrefix, n.BackendService, bsb.Resource())
+	}
+	if res.Version() != meta.VersionGA {
+		return fmt.Errorf("%s: BackendService %s has unsupported version %s", errPrefix, n.BackendService, res.Version())
+	}
+	obj, err := res.ToGA()
+	if err != nil {
+		return fmt.Errorf("%s: %w", errPrefix, err)
+	}
+
+	// Copy obj as it is shared with the frozen resource.
+	newObj := *obj
+	newObj.Backends = nil
+	present := map[string]bool{}
+	for _, be := range obj.Backends {
+		id, err := cloud.ParseResourceURL(be.Group)
+		if err == nil && n.isZonalNEG(id) {
+			if !zones[id.Key.Zone] || present[id.Key.Zone] {
+				continue
+			}
+			present[id.Key.Zone] = true
+		}
+		newObj.Backends = append(newObj.Backends, be)
+	}
+	for _, z := range n.sortedZones() {
+		if present[z] {
+			continue
+		}
+		be := &compute.Backend{}
+		if n.Backend != nil {
+			n.Backend(z, be)
+		}
+		be.Group = n.ID(z).SelfLink(meta.VersionGA)
+		newObj.Backends = append(newObj.Backends, be)
+	}
+
+	m := backendservice.NewMutableBackendService(n.BackendService.ProjectID, n.BackendService.Key)
+	if err := m.Set(&newObj); err != nil {
+		return fmt.Errorf("%s: %w", errPrefix, err)
+	}
+	newRes, err := m.Freeze()
+	if err != nil {
+		return fmt.Errorf("%s: %w", errPrefix, err)
+	}
+	return bsb.SetResource(newRes)
+}
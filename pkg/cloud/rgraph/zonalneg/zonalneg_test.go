@@ -0,0 +1,177 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package zonalneg
+
+import (
+	"context"
+	"sort"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/backendservice"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/testing/ez"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/topology"
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/api/compute/v1"
+)
+
+const proj = "proj"
+
+func builder() *rgraph.Builder {
+	g := &ez.Graph{
+		Project: proj,
+		Nodes: []ez.Node{
+			{Name: "bs", Refs: []ez.Ref{{Field: "Backends.Group", To: "us-central1-b/neg-other"}, {Field: "Healthchecks", To: "hc"}}},
+			{Name: "hc"},
+			{Name: "neg-other", Zone: "us-central1-b"},
+		},
+	}
+	return g.Builder()
+}
+
+// backends returns the zone/name of the Backends of the BackendService.
+func backends(t *testing.T, b *rgraph.Builder) []string {
+	t.Helper()
+	bs := b.Get(backendservice.ID(proj, meta.GlobalKey("bs")))
+	obj, err := bs.Resource().(backendservice.BackendService).ToGA()
+	if err != nil {
+		t.Fatalf("ToGA() = %v, want nil", err)
+	}
+	var ret []string
+	for _, be := range obj.Backends {
+		id, err := cloud.ParseResourceURL(be.Group)
+		if err != nil {
+			t.Fatalf("ParseResourceURL(%q) = %v, want nil", be.Group, err)
+		}
+		ret = append(ret, id.Key.Zone+"/"+id.Key.Name)
+	}
+	sort.Strings(ret)
+	return ret
+}
+
+func TestExpand(t *testing.T) {
+	b := builder()
+	neg := &NEG{
+		Project:        proj,
+		Name:           "neg",
+		Zones:          []string{"us-central1-b", "us-central1-a"},
+		BackendService: backendservice.ID(proj, meta.GlobalKey("bs")),
+		Setup: func(_ string, x *compute.NetworkEndpointGroup) {
+			x.NetworkEndpointType = "GCE_VM_IP_PORT"
+		},
+		Backend: func(_ string, x *compute.Backend) {
+			x.BalancingMode = "RATE"
+		},
+	}
+	if err := neg.Expand(b); err != nil {
+		t.Fatalf("Expand() = %v, want nil", err)
+	}
+	for _, id := range neg.IDs() {
+		nb := b.Get(id)
+		if nb == nil || nb.State() != rnode.NodeExists {
+			t.Errorf("NEG %s not in graph", id)
+		}
+	}
+	want := []string{"us-central1-a/neg", "us-central1-b/neg", "us-central1-b/neg-other"}
+	if diff := cmp.Diff(backends(t, b), want); diff != "" {
+		t.Errorf("backends: -got,+want: %s", diff)
+	}
+	if _, err := b.Build(); err != nil {
+		t.Fatalf("Build() = %v, want nil", err)
+	}
+
+	// Zone a is removed, zone c is added.
+	neg.Zones = []string{"us-central1-b", "us-central1-c"}
+	if err := neg.Expand(b); err != nil {
+		t.Fatalf("Expand() = %v, want nil", err)
+	}
+	if got := b.Get(neg.ID("us-central1-a")).State(); got != rnode.NodeDoesNotExist {
+		t.Errorf("NEG in us-central1-a State() = %v, want %v", got, rnode.NodeDoesNotExist)
+	}
+	want = []string{"us-central1-b/neg", "us-central1-b/neg-other", "us-central1-c/neg"}
+	if diff := cmp.Diff(backends(t, b), want); diff != "" {
+		t.Errorf("backends: -got,+want: %s", diff)
+	}
+	g, err := b.Build()
+	if err != nil {
+		t.Fatalf("Build() = %v, want nil", err)
+	}
+	if n := g.Get(neg.ID("us-central1-a")); len(n.InRefs()) != 0 {
+		t.Errorf("NEG in us-central1-a InRefs() = %v, want none", n.InRefs())
+	}
+}
+
+func TestExpandInvalid(t *testing.T) {
+	for _, neg := range []*NEG{
+		{Project: proj, Name: "neg"},
+		{Project: proj, Zones: []string{"us-central1-a"}},
+		{Project: proj, Name: "neg", Zones: []string{"us-central1-a"}, BackendService: backendservice.ID(proj, meta.GlobalKey("missing"))},
+	} {
+		if err := neg.Expand(builder()); err == nil {
+			t.Errorf("Expand(%+v) = nil, want error", neg)
+		}
+	}
+}
+
+func TestSetZonesFrom(t *testing.T) {
+	ctx := context.Background()
+	p := topology.Static{"us-central1": {"us-central1-b", "us-central1-a"}}
+	neg := &NEG{Project: proj, Name: "neg"}
+	if err := neg.SetZonesFrom(ctx, p, "us-central1"); err != nil {
+		t.Fatalf("SetZonesFrom() = %v", err)
+	}
+	if diff := cmp.Diff(neg.Zones, []string{"us-central1-a", "us-central1-b"}); diff != "" {
+		t.Errorf("Zones: diff -got,+want: %s", diff)
+	}
+	if err := neg.SetZonesFrom(ctx, p, "europe-west1"); err == nil {
+		t.Error("SetZonesFrom(europe-west1) = nil, want error")
+	}
+}
+
+func TestAddStale(t *testing.T) {
+	ctx := context.Background()
+	mock := cloud.NewMockGCE(&cloud.SingleProjectRouter{ID: proj})
+	for _, z := range []string{"us-central1-a", "us-central1-d"} {
+		if err := mock.NetworkEndpointGroups().Insert(ctx, meta.ZonalKey("neg", z), &compute.NetworkEndpointGroup{Name: "neg"}); err != nil {
+			t.Fatalf("Insert() = %v, want nil", err)
+		}
+	}
+	if err := mock.NetworkEndpointGroups().Insert(ctx, meta.ZonalKey("neg-2", "us-central1-d"), &compute.NetworkEndpointGroup{Name: "neg-2"}); err != nil {
+		t.Fatalf("Insert() = %v, want nil", err)
+	}
+
+	b := builder()
+	neg := &NEG{Project: proj, Name: "neg", Zones: []string{"us-central1-a"}}
+	if err := neg.Expand(b); err != nil {
+		t.Fatalf("Expand() = %v, want nil", err)
+	}
+	if err := neg.AddStale(ctx, mock, b); err != nil {
+		t.Fatalf("AddStale() = %v, want nil", err)
+	}
+	if nb := b.Get(neg.ID("us-central1-d")); nb == nil || nb.State() != rnode.NodeDoesNotExist {
+		t.Errorf("stale NEG in us-central1-d = %v, want NodeDoesNotExist", nb)
+	}
+	if nb := b.Get(neg.ID("us-central1-a")); nb == nil || nb.State() != rnode.NodeExists {
+		t.Errorf("NEG in us-central1-a = %v, want NodeExists", nb)
+	}
+	if nb := b.Get(&cloud.ResourceID{Resource: "networkEndpointGroups", ProjectID: proj, Key: meta.ZonalKey("neg-2", "us-central1-d")}); nb != nil {
+		t.Errorf("neg-2 was added to the graph")
+	}
+}
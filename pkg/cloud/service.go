@@ -28,6 +28,7 @@ import (
 	networkservicesga "google.golang.org/api/networkservices/v1"
 	networkservicesbeta "google.golang.org/api/networkservices/v1beta1"
 	"google.golang.org/api/option"
+	servicedirectoryga "google.golang.org/api/servicedirectory/v1"
 	"k8s.io/klog/v2"
 )
 
@@ -39,8 +40,19 @@ type Service struct {
 	Beta                *beta.Service
 	NetworkServicesGA   *networkservicesga.ProjectsLocationsService
 	NetworkServicesBeta *networkservicesbeta.ProjectsLocationsService
+	ServiceDirectoryGA  *servicedirectoryga.ProjectsLocationsService
 	ProjectRouter       ProjectRouter
 	RateLimiter         RateLimiter
+	// Metrics receives the latency and result of the calls. This can be
+	// nil.
+	Metrics CallMetrics
+	// PollBackoff is the wait between polls of a long running operation.
+	// The zero value polls again right away (subject to the RateLimiter).
+	PollBackoff Backoff
+
+	// client is used for the calls that are not made through the API
+	// clients, e.g. ResourceURLs.
+	client *http.Client
 }
 
 // NewService returns a new Service instance initialized with from an HTTP
@@ -68,14 +80,21 @@ func NewService(ctx context.Context, client *http.Client, pr ProjectRouter, rl R
 		return nil, err
 	}
 
+	sdGA, err := servicedirectoryga.NewService(ctx, option.WithHTTPClient(client))
+	if err != nil {
+		return nil, err
+	}
+
 	svc := &Service{
 		GA:                  ga,
 		Alpha:               alpha,
 		Beta:                beta,
 		NetworkServicesGA:   nsGA.Projects.Locations,
 		NetworkServicesBeta: nsBeta.Projects.Locations,
+		ServiceDirectoryGA:  sdGA.Projects.Locations,
 		ProjectRouter:       pr,
 		RateLimiter:         rl,
+		client:              client,
 	}
 
 	return svc, nil
@@ -157,30 +176,32 @@ func (s *Service) WaitForCompletion(ctx context.Context, genericOp interface{})
 // This is to prevent a transient error from bubbling up to controller-level logic.
 func (s *Service) pollOperation(ctx context.Context, op operation) error {
 	start := time.Now()
-	var pollCount int
-	for {
-		// Check if context has been cancelled. Note that ctx.Done() must be checked before
-		// returning ctx.Err().
-		select {
-		case <-ctx.Done():
-			klog.V(5).Infof("op.pollOperation(%v, %v) not completed, poll count = %d, ctx.Err = %v (%v elapsed)", ctx, op, pollCount, ctx.Err(), time.Since(start))
-			return ctx.Err()
-		default:
-			// ctx is not canceled, continue immediately
-		}
-
-		pollCount++
+	err := s.PollBackoff.Retry(ctx, func(ctx context.Context, pollCount int) (bool, error) {
 		klog.V(5).Infof("op.isDone(%v) waiting; op = %v, poll count = %d (%v elapsed)", ctx, op, pollCount, time.Since(start))
 		s.RateLimiter.Accept(ctx, op.rateLimitKey())
-		switch done, err := op.isDone(ctx); {
+		pollStart := time.Now()
+		done, err := op.isDone(ctx)
+		if s.Metrics != nil {
+			s.Metrics.ObserveCall(op.rateLimitKey(), CallStats{Latency: time.Since(pollStart)}, err)
+		}
+		switch {
 		case err != nil:
 			klog.V(5).Infof("op.isDone(%v) error; op = %v, poll count = %d, err = %v, retrying (%v elapsed)", ctx, op, pollCount, err, time.Since(start))
 			s.RateLimiter.Observe(ctx, err, op.rateLimitKey())
-			return err
+			return false, err
 		case done:
 			klog.V(5).Infof("op.isDone(%v) complete; op = %v, poll count = %d, op.err = %v (%v elapsed)", ctx, op, pollCount, op.error(), time.Since(start))
 			s.RateLimiter.Observe(ctx, op.error(), op.rateLimitKey())
-			return op.error()
+			if w := op.warnings(); len(w) > 0 {
+				klog.V(2).Infof("op.isDone(%v) completed with warnings; op = %v, warnings = %v", ctx, op, w)
+				ReportOperationWarnings(ctx, w)
+			}
+			return true, op.error()
 		}
+		return false, nil
+	})
+	if ctx.Err() != nil && err == ctx.Err() {
+		klog.V(5).Infof("op.pollOperation(%v, %v) not completed, ctx.Err = %v (%v elapsed)", ctx, op, ctx.Err(), time.Since(start))
 	}
+	return err
 }
@@ -22,6 +22,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/google/go-cmp/cmp"
 	alpha "google.golang.org/api/compute/v0.alpha"
 	beta "google.golang.org/api/compute/v0.beta"
 	ga "google.golang.org/api/compute/v1"
@@ -84,10 +85,54 @@ func TestPollOperation(t *testing.T) {
 	}
 }
 
+func TestPollOperationWarnings(t *testing.T) {
+	warns := []OperationWarning{{Code: "DEPRECATED_RESOURCE_USED", Message: "deprecated"}}
+	for _, tc := range []struct {
+		name    string
+		op      *fakeOperation
+		handler bool
+		want    []OperationWarning
+	}{
+		{
+			name:    "warnings",
+			op:      &fakeOperation{attemptsRemaining: 1, warns: warns},
+			handler: true,
+			want:    warns,
+		},
+		{
+			name:    "no warnings",
+			op:      &fakeOperation{attemptsRemaining: 1},
+			handler: true,
+		},
+		{
+			name: "no handler",
+			op:   &fakeOperation{attemptsRemaining: 1, warns: warns},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			s := Service{RateLimiter: &NopRateLimiter{}}
+			ctx := context.Background()
+			var got []OperationWarning
+			if tc.handler {
+				ctx = WithOperationWarningHandler(ctx, func(_ context.Context, w []OperationWarning) {
+					got = append(got, w...)
+				})
+			}
+			if err := s.pollOperation(ctx, tc.op); err != nil {
+				t.Fatalf("pollOperation() = %v, want nil", err)
+			}
+			if diff := cmp.Diff(got, tc.want); diff != "" {
+				t.Errorf("warnings: diff -got,+want: %s", diff)
+			}
+		})
+	}
+}
+
 type fakeOperation struct {
 	attemptsRemaining int
 	doneErr           error
 	err               error
+	warns             []OperationWarning
 }
 
 func (f *fakeOperation) isDone(ctx context.Context) (bool, error) {
@@ -102,6 +147,10 @@ func (f *fakeOperation) error() error {
 	return f.err
 }
 
+func (f *fakeOperation) warnings() []OperationWarning {
+	return f.warns
+}
+
 func (f *fakeOperation) rateLimitKey() *RateLimitKey {
 	return nil
 }
@@ -198,3 +247,15 @@ func TestWrapOperation(t *testing.T) {
 		})
 	}
 }
+
+func TestOperationWarningHandlerChain(t *testing.T) {
+	warns := []OperationWarning{{Code: "c", Message: "m"}}
+	var calls []string
+	ctx := WithOperationWarningHandler(context.Background(), func(context.Context, []OperationWarning) { calls = append(calls, "outer") })
+	ctx = WithOperationWarningHandler(ctx, func(context.Context, []OperationWarning) { calls = append(calls, "inner") })
+
+	ReportOperationWarnings(ctx, warns)
+	if diff := cmp.Diff(calls, []string{"inner", "outer"}); diff != "" {
+		t.Errorf("calls: diff -got,+want: %s", diff)
+	}
+}
@@ -0,0 +1,226 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"google.golang.org/api/googleapi"
+	servicedirectoryga "google.golang.org/api/servicedirectory/v1"
+	"k8s.io/klog/v2"
+)
+
+// Service Directory services are nested in a namespace
+// (projects/<project>/locations/<location>/namespaces/<ns>/services/<svc>),
+// which cannot be expressed by a meta.Key. The services are addressed by a
+// regional key where the region is the location and the name is
+// "<ns>/<svc>". Use ServiceDirectoryServiceID and
+// ParseServiceDirectoryServiceName to convert between the forms.
+//
+// The Service Directory services are read-only; they are referenced by
+// networkservices ServiceBindings.
+
+const serviceDirectoryServicesResource = "services"
+
+// ServiceDirectoryServiceKey returns the key for the service in the
+// namespace and location.
+func ServiceDirectoryServiceKey(location, namespace, service string) *meta.Key {
+	return meta.RegionalKey(namespace+"/"+service, location)
+}
+
+// ServiceDirectoryServiceID returns the ResourceID for the Service Directory
+// service.
+func ServiceDirectoryServiceID(project, location, namespace, service string) *ResourceID {
+	return &ResourceID{
+		ProjectID: project,
+		APIGroup:  meta.APIGroupServiceDirectory,
+		Resource:  serviceDirectoryServicesResource,
+		Key:       ServiceDirectoryServiceKey(location, namespace, service),
+	}
+}
+
+// ParseServiceDirectoryServiceName parses the resource name of a Service
+// Directory service (e.g. ServiceBinding.Service). The name may have a
+// "https://servicedirectory.googleapis.com/v1/" prefix.
+func ParseServiceDirectoryServiceName(name string) (*ResourceID, error) {
+	s := name
+	if i := strings.Index(s, "projects/"); i > 0 {
+		s = s[i:]
+	}
+	parts := strings.Split(s, "/")
+	if len(parts) != 8 || parts[0] != "projects" || parts[2] != "locations" || parts[4] != "namespaces" || parts[6] != "services" {
+		return nil, fmt.Errorf("invalid Service Directory service name %q", name)
+	}
+	for _, p := range parts {
+		if p == "" {
+			return nil, fmt.Errorf("invalid Service Directory service name %q", name)
+		}
+	}
+	return ServiceDirectoryServiceID(parts[1], parts[3], parts[5], parts[7]), nil
+}
+
+// ServiceDirectoryServiceName returns the resource name of the Service
+// Directory service for the key.
+func ServiceDirectoryServiceName(project string, key *meta.Key) (string, error) {
+	ns, svc, ok := strings.Cut(key.Name, "/")
+	if key.Type() != meta.Regional || !ok || ns == "" || svc == "" || strings.Contains(svc, "/") {
+		return "", fmt.Errorf("invalid Service Directory service key %v", key)
+	}
+	return fmt.Sprintf("projects/%s/locations/%s/namespaces/%s/services/%s", project, key.Region, ns, svc), nil
+}
+
+// ServiceDirectoryServices is an interface for the Service Directory
+// services. See ServiceDirectoryServiceKey for the keys.
+type ServiceDirectoryServices interface {
+	Get(ctx context.Context, key *meta.Key, options ...Option) (*servicedirectoryga.Service, error)
+}
+
+// ServiceDirectoryServices returns the interface for the Service Directory
+// services.
+func (gce *GCE) ServiceDirectoryServices() ServiceDirectoryServices {
+	return gce.gceServiceDirectoryServices
+}
+
+// ServiceDirectoryServices returns the interface for the Service Directory
+// services.
+func (mock *MockGCE) ServiceDirectoryServices() ServiceDirectoryServices {
+	return mock.MockServiceDirectoryServices
+}
+
+// GCEServiceDirectoryServices is a simplifying adapter for the Service
+// Directory services.
+type GCEServiceDirectoryServices struct {
+	s *Service
+}
+
+// Get the Service Directory service named by key.
+func (g *GCEServiceDirectoryServices) Get(ctx context.Context, key *meta.Key, options ...Option) (*servicedirectoryga.Service, error) {
+	opts := mergeOptions(options)
+	klog.V(5).Infof("GCEServiceDirectoryServices.Get(%v, %v, %v): called", ctx, key, opts)
+
+	projectID := getProjectID(ctx, g.s.ProjectRouter, opts, "ga", "ServiceDirectoryServices")
+	name, err := ServiceDirectoryServiceName(projectID, key)
+	if err != nil {
+		return nil, err
+	}
+
+	ck := &CallContextKey{
+		ProjectID: projectID,
+		Operation: "Get",
+		Version:   meta.Version("ga"),
+		Service:   "ServiceDirectoryServices",
+	}
+	callObserverStart(ctx, ck)
+	if err := g.s.RateLimiter.Accept(ctx, ck); err != nil {
+		klog.V(4).Infof("GCEServiceDirectoryServices.Get(%v, %v): RateLimiter error: %v", ctx, key, err)
+		return nil, err
+	}
+	start := time.Now()
+	call := g.s.ServiceDirectoryGA.Namespaces.Services.Get(name)
+	setCallHeaders(ctx, call.Header(), opts)
+	call.Context(ctx)
+	v, err := call.Do()
+	klog.V(4).Infof("GCEServiceDirectoryServices.Get(%v, %v) = %+v, %v", ctx, key, v, err)
+
+	g.s.endCall(ctx, ck, g.s.callStats(start, nil, v), err)
+	g.s.RateLimiter.Observe(ctx, err, ck)
+
+	return v, err
+}
+
+// NewMockServiceDirectoryServices returns a new mock for the Service
+// Directory services.
+func NewMockServiceDirectoryServices(pr ProjectRouter) *MockServiceDirectoryServices {
+	return &MockServiceDirectoryServices{
+		ProjectRouter: pr,
+		Objects:       map[meta.Key]*servicedirectoryga.Service{},
+		GetError:      map[meta.Key]error{},
+	}
+}
+
+// MockServiceDirectoryServices is the mock for the Service Directory
+// services.
+type MockServiceDirectoryServices struct {
+	Lock sync.Mutex
+
+	ProjectRouter ProjectRouter
+
+	// Objects maintained by the mock.
+	Objects map[meta.Key]*servicedirectoryga.Service
+
+	// If an entry exists for the given key and operation, then the error
+	// will be returned instead of the operation.
+	GetError map[meta.Key]error
+
+	// GetHook intercepts the Get call. If GetHook returns true, the mock
+	// returns the values from the hook.
+	GetHook func(ctx context.Context, key *meta.Key, m *MockServiceDirectoryServices, options ...Option) (bool, *servicedirectoryga.Service, error)
+}
+
+// Get is a mock for Get.
+func (m *MockServiceDirectoryServices) Get(ctx context.Context, key *meta.Key, options ...Option) (*servicedirectoryga.Service, error) {
+	if m.GetHook != nil {
+		if intercept, obj, err := m.GetHook(ctx, key, m, options...); intercept {
+			klog.V(5).Infof("MockServiceDirectoryServices.Get(%v, %s) = %+v, %v", ctx, key, obj, err)
+			return obj, err
+		}
+	}
+	projectID := getProjectID(ctx, m.ProjectRouter, mergeOptions(options), "ga", "ServiceDirectoryServices")
+	name, err := ServiceDirectoryServiceName(projectID, key)
+	if err != nil {
+		return nil, err
+	}
+
+	m.Lock.Lock()
+	defer m.Lock.Unlock()
+
+	if err, ok := m.GetError[*key]; ok {
+		klog.V(5).Infof("MockServiceDirectoryServices.Get(%v, %s) = nil, %v", ctx, key, err)
+		return nil, err
+	}
+	if obj, ok := m.Objects[*key]; ok {
+		ret := &servicedirectoryga.Service{}
+		if err := copyViaJSON(ret, obj); err != nil {
+			return nil, err
+		}
+		ret.Name = name
+		klog.V(5).Infof("MockServiceDirectoryServices.Get(%v, %s) = %+v, nil", ctx, key, ret)
+		return ret, nil
+	}
+
+	err = &googleapi.Error{
+		Code:    http.StatusNotFound,
+		Message: fmt.Sprintf("MockServiceDirectoryServices %v not found", key),
+	}
+	klog.V(5).Infof("MockServiceDirectoryServices.Get(%v, %s) = nil, %v", ctx, key, err)
+	return nil, err
+}
+
+// Insert adds the service to the mock. This is a convenience for tests;
+// Service Directory services are not created through this interface.
+func (m *MockServiceDirectoryServices) Insert(key *meta.Key, obj *servicedirectoryga.Service) {
+	m.Lock.Lock()
+	defer m.Lock.Unlock()
+
+	m.Objects[*key] = obj
+}
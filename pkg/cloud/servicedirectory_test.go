@@ -0,0 +1,82 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"context"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	servicedirectoryga "google.golang.org/api/servicedirectory/v1"
+)
+
+func TestParseServiceDirectoryServiceName(t *testing.T) {
+	t.Parallel()
+
+	want := ServiceDirectoryServiceID("proj", "us-central1", "ns", "svc")
+	for _, tc := range []struct {
+		name    string
+		wantErr bool
+	}{
+		{name: "projects/proj/locations/us-central1/namespaces/ns/services/svc"},
+		{name: "https://servicedirectory.googleapis.com/v1/projects/proj/locations/us-central1/namespaces/ns/services/svc"},
+		{name: "projects/proj/locations/us-central1/namespaces/ns", wantErr: true},
+		{name: "projects/proj/locations/us-central1/namespaces/ns/services/", wantErr: true},
+		{name: "projects/proj/locations/us-central1/namespaces/ns/endpoints/svc", wantErr: true},
+		{name: "", wantErr: true},
+	} {
+		id, err := ParseServiceDirectoryServiceName(tc.name)
+		if gotErr := err != nil; gotErr != tc.wantErr {
+			t.Errorf("ParseServiceDirectoryServiceName(%q) = %v, %v; gotErr = %t, want %t", tc.name, id, err, gotErr, tc.wantErr)
+			continue
+		}
+		if tc.wantErr {
+			continue
+		}
+		if !id.Equal(want) {
+			t.Errorf("ParseServiceDirectoryServiceName(%q) = %v, want %v", tc.name, id, want)
+		}
+		name, err := ServiceDirectoryServiceName(id.ProjectID, id.Key)
+		if err != nil || name != "projects/proj/locations/us-central1/namespaces/ns/services/svc" {
+			t.Errorf("ServiceDirectoryServiceName(%v) = %q, %v", id, name, err)
+		}
+	}
+
+	if _, err := ServiceDirectoryServiceName("proj", meta.GlobalKey("ns/svc")); err == nil {
+		t.Error("ServiceDirectoryServiceName(global key) = nil, want error")
+	}
+}
+
+func TestMockServiceDirectoryServices(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	mock := NewMockGCE(&SingleProjectRouter{ID: "proj"})
+	key := ServiceDirectoryServiceKey("us-central1", "ns", "svc")
+
+	if _, err := mock.ServiceDirectoryServices().Get(ctx, key); !IsNotFound(err) {
+		t.Fatalf("Get(%v) = %v, want NotFound", key, err)
+	}
+	mock.MockServiceDirectoryServices.Insert(key, &servicedirectoryga.Service{})
+	svc, err := mock.ServiceDirectoryServices().Get(ctx, key)
+	if err != nil {
+		t.Fatalf("Get(%v) = %v, want nil", key, err)
+	}
+	if want := "projects/proj/locations/us-central1/namespaces/ns/services/svc"; svc.Name != want {
+		t.Errorf("Name = %q, want %q", svc.Name, want)
+	}
+}
@@ -19,10 +19,12 @@ package cloud
 import (
 	"encoding/json"
 	"fmt"
+	"reflect"
 	"regexp"
 	"strings"
 
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"k8s.io/klog/v2"
 )
 
 var (
@@ -245,6 +247,42 @@ func parseURL(url string, apiGroup meta.APIGroup) (*ResourceID, error) {
 	return nil, errNotValid
 }
 
+// ParseNetworkServicesName parses the resource name of a global
+// networkservices resource as returned by the API (e.g. TcpRoute.Meshes):
+//
+//	projects/<proj>/locations/global/<res>/<name>
+//	[https://networkservices.googleapis.com/<ver>/]projects/<proj>/locations/global/<res>/<name>
+func ParseNetworkServicesName(name string) (*ResourceID, error) {
+	s := name
+	if i := strings.Index(s, "projects/"); i > 0 {
+		if !strings.Contains(s[:i], "networkservices.googleapis.com/") {
+			return nil, fmt.Errorf("%q is not a valid networkservices resource name", name)
+		}
+		s = s[i:]
+	}
+	parts := strings.Split(s, "/")
+	if len(parts) != 6 || parts[0] != "projects" || parts[2] != "locations" || parts[3] != "global" {
+		return nil, fmt.Errorf("%q is not a valid networkservices resource name", name)
+	}
+	for _, p := range parts {
+		if p == "" {
+			return nil, fmt.Errorf("%q is not a valid networkservices resource name", name)
+		}
+	}
+	return &ResourceID{
+		ProjectID: parts[1],
+		APIGroup:  meta.APIGroupNetworkServices,
+		Resource:  parts[4],
+		Key:       meta.GlobalKey(parts[5]),
+	}, nil
+}
+
+// NetworkServicesName returns the resource name of the global networkservices
+// resource id, e.g. "projects/<proj>/locations/global/meshes/<name>".
+func NetworkServicesName(id *ResourceID) string {
+	return fmt.Sprintf("projects/%s/locations/global/%s/%s", id.ProjectID, id.Resource, id.Key.Name)
+}
+
 func copyViaJSON(dest, src interface{}) error {
 	bytes, err := json.Marshal(src)
 	if err != nil {
@@ -253,6 +291,88 @@ func copyViaJSON(dest, src interface{}) error {
 	return json.Unmarshal(bytes, dest)
 }
 
+// mockSelfLink returns selfLink rewritten for the API version ver. This is
+// used by the mocks to project an object stored with one version into
+// another. selfLink is returned unchanged if it cannot be parsed.
+func mockSelfLink(selfLink string, ver meta.Version) string {
+	if selfLink == "" {
+		return ""
+	}
+	id, err := ParseResourceURL(selfLink)
+	if err != nil {
+		return selfLink
+	}
+	return id.SelfLink(ver)
+}
+
+// mockMergeVersions returns the object to store in the mock when stored is
+// replaced by obj. If obj is a different API version than stored, the top
+// level fields of stored that do not exist in the version of obj are kept,
+// mirroring the API which does not clear fields that are not visible in the
+// version used for the update.
+func mockMergeVersions(stored, obj interface{}) interface{} {
+	if stored == nil || reflect.TypeOf(stored) == reflect.TypeOf(obj) {
+		return obj
+	}
+	st, ot := reflect.TypeOf(stored), reflect.TypeOf(obj)
+	if st.Kind() != reflect.Ptr || ot.Kind() != reflect.Ptr || st.Elem().Kind() != reflect.Struct || ot.Elem().Kind() != reflect.Struct {
+		return obj
+	}
+	objFields := jsonFieldNames(ot.Elem())
+	var extra []string
+	for name := range jsonFieldNames(st.Elem()) {
+		if !objFields[name] {
+			extra = append(extra, name)
+		}
+	}
+	if len(extra) == 0 {
+		return obj
+	}
+
+	var storedMap, objMap map[string]interface{}
+	if err := copyViaJSON(&storedMap, stored); err != nil {
+		klog.Errorf("Could not convert %T via JSON: %v", stored, err)
+		return obj
+	}
+	if err := copyViaJSON(&objMap, obj); err != nil {
+		klog.Errorf("Could not convert %T via JSON: %v", obj, err)
+		return obj
+	}
+	// The object is stored as the version of stored, so the SelfLink must be
+	// for that version.
+	for _, name := range append(extra, "selfLink") {
+		if v, ok := storedMap[name]; ok {
+			objMap[name] = v
+		}
+	}
+	ret := reflect.New(st.Elem()).Interface()
+	if err := copyViaJSON(ret, objMap); err != nil {
+		klog.Errorf("Could not convert %T to %T via JSON: %v", obj, ret, err)
+		return obj
+	}
+	return ret
+}
+
+// jsonFieldNames returns the JSON names of the fields of the struct type t.
+func jsonFieldNames(t reflect.Type) map[string]bool {
+	ret := map[string]bool{}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		switch name {
+		case "-":
+			continue
+		case "":
+			name = f.Name
+		}
+		ret[name] = true
+	}
+	return ret
+}
+
 // ResourcePath returns the path starting from the location.
 // Example: regions/us-central1/subnetworks/my-subnet
 // Deprecated: Use SelfLinkWithGroup instead
@@ -339,6 +339,35 @@ func TestResourceIdSelfLink(t *testing.T) {
 	}
 }
 
+func TestParseNetworkServicesName(t *testing.T) {
+	want := &ResourceID{"proj1", meta.APIGroupNetworkServices, "meshes", meta.GlobalKey("m")}
+	for _, name := range []string{
+		"projects/proj1/locations/global/meshes/m",
+		"https://networkservices.googleapis.com/v1/projects/proj1/locations/global/meshes/m",
+		"//networkservices.googleapis.com/projects/proj1/locations/global/meshes/m",
+	} {
+		got, err := ParseNetworkServicesName(name)
+		if err != nil || !got.Equal(want) {
+			t.Errorf("ParseNetworkServicesName(%q) = %v, %v; want %v, nil", name, got, err, want)
+			continue
+		}
+		if n := NetworkServicesName(got); n != "projects/proj1/locations/global/meshes/m" {
+			t.Errorf("NetworkServicesName(%v) = %q", got, n)
+		}
+	}
+	for _, name := range []string{
+		"mesh-1",
+		"projects/proj1/locations/us-central1/meshes/m",
+		"projects/proj1/global/meshes/m",
+		"https://compute.googleapis.com/compute/v1/projects/proj1/locations/global/meshes/m",
+		"projects/proj1/locations/global/meshes/",
+	} {
+		if got, err := ParseNetworkServicesName(name); err == nil {
+			t.Errorf("ParseNetworkServicesName(%q) = %v, nil; want error", name, got)
+		}
+	}
+}
+
 func TestSelfLink(t *testing.T) {
 	t.Parallel()
 